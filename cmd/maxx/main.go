@@ -15,12 +15,25 @@ import (
 	"time"
 
 	"github.com/awsl-project/maxx/internal/adapter/client"
-	_ "github.com/awsl-project/maxx/internal/adapter/provider/custom" // Register custom adapter
-	_ "github.com/awsl-project/maxx/internal/adapter/provider/kiro"   // Register kiro adapter
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/azure_openai" // Register azure openai adapter
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/bedrock"      // Register bedrock adapter
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/copilot"      // Register copilot adapter
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/custom"       // Register custom adapter
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/kiro"         // Register kiro adapter
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/local"        // Register local adapter
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/vertex"       // Register vertex adapter
+	"github.com/awsl-project/maxx/internal/admission"
+	"github.com/awsl-project/maxx/internal/anomaly"
+	"github.com/awsl-project/maxx/internal/billing"
 	"github.com/awsl-project/maxx/internal/cooldown"
 	"github.com/awsl-project/maxx/internal/core"
+	"github.com/awsl-project/maxx/internal/detailstore"
+	"github.com/awsl-project/maxx/internal/domain"
 	"github.com/awsl-project/maxx/internal/executor"
 	"github.com/awsl-project/maxx/internal/handler"
+	"github.com/awsl-project/maxx/internal/health"
+	"github.com/awsl-project/maxx/internal/pricing"
+	"github.com/awsl-project/maxx/internal/report"
 	"github.com/awsl-project/maxx/internal/repository/cached"
 	"github.com/awsl-project/maxx/internal/repository/sqlite"
 	"github.com/awsl-project/maxx/internal/router"
@@ -90,6 +103,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
+	go db.BackfillFullTextIndex()
 
 	// Create repositories
 	providerRepo := sqlite.NewProviderRepository(db)
@@ -103,6 +117,7 @@ func main() {
 	settingRepo := sqlite.NewSystemSettingRepository(db)
 	antigravityQuotaRepo := sqlite.NewAntigravityQuotaRepository(db)
 	codexQuotaRepo := sqlite.NewCodexQuotaRepository(db)
+	copilotQuotaRepo := sqlite.NewCopilotQuotaRepository(db)
 	cooldownRepo := sqlite.NewCooldownRepository(db)
 	failureCountRepo := sqlite.NewFailureCountRepository(db)
 	apiTokenRepo := sqlite.NewAPITokenRepository(db)
@@ -110,6 +125,17 @@ func main() {
 	usageStatsRepo := sqlite.NewUsageStatsRepository(db)
 	responseModelRepo := sqlite.NewResponseModelRepository(db)
 	modelPriceRepo := sqlite.NewModelPriceRepository(db)
+	auditLogRepo := sqlite.NewAuditLogRepository(db)
+	billingRuleRepo := sqlite.NewBillingRuleRepository(db)
+	healthCheckRepo := sqlite.NewProviderHealthCheckRepository(db)
+	cooldownPolicyRepo := sqlite.NewCooldownPolicyRepository(db)
+	modelFallbackRepo := sqlite.NewModelFallbackRepository(db)
+
+	// Optional external storage for request/response bodies
+	detailStore, err := detailstore.NewFromEnv(dataDirPath)
+	if err != nil {
+		log.Fatalf("Failed to initialize detail store: %v", err)
+	}
 
 	// Initialize cooldown manager with database persistence
 	cooldown.Default().SetRepository(cooldownRepo)
@@ -117,6 +143,30 @@ func main() {
 	if err := cooldown.Default().LoadFromDatabase(); err != nil {
 		log.Printf("Warning: Failed to load cooldowns from database: %v", err)
 	}
+	cooldown.Default().SetPolicyRepository(cooldownPolicyRepo)
+	if err := cooldown.Default().ReloadPolicies(); err != nil {
+		log.Printf("Warning: Failed to load cooldown policies: %v", err)
+	}
+
+	// Initialize billing engine (margin rules per project / API token)
+	billing.Default().SetRepositories(billingRuleRepo, usageStatsRepo, settingRepo)
+	if err := billing.Default().Reload(); err != nil {
+		log.Printf("Warning: Failed to load billing rules: %v", err)
+	}
+
+	// Load model prices (including provider overrides) and exchange rates into the calculator
+	if prices, err := modelPriceRepo.ListCurrentPrices(); err != nil {
+		log.Printf("Warning: Failed to load model prices: %v", err)
+	} else if len(prices) > 0 {
+		pricing.GlobalCalculator().LoadFromDatabase(prices)
+	}
+	if value, err := settingRepo.Get(domain.SettingKeyExchangeRates); err == nil && value != "" {
+		if rates, err := pricing.ParseExchangeRates(value); err != nil {
+			log.Printf("Warning: Failed to parse exchange rates: %v", err)
+		} else {
+			pricing.GlobalCalculator().SetExchangeRates(rates)
+		}
+	}
 
 	// Generate instance ID and mark stale requests as failed
 	instanceID := generateInstanceID()
@@ -160,6 +210,7 @@ func main() {
 	cachedProjectRepo := cached.NewProjectRepository(projectRepo)
 	cachedAPITokenRepo := cached.NewAPITokenRepository(apiTokenRepo)
 	cachedModelMappingRepo := cached.NewModelMappingRepository(modelMappingRepo)
+	cachedModelFallbackRepo := cached.NewModelFallbackRepository(modelFallbackRepo)
 
 	// Load cached data
 	startupStep = time.Now()
@@ -185,8 +236,14 @@ func main() {
 	if err := cachedModelMappingRepo.Load(); err != nil {
 		log.Printf("Warning: Failed to load model mappings cache: %v", err)
 	}
+	if err := cachedModelFallbackRepo.Load(); err != nil {
+		log.Printf("Warning: Failed to load model fallbacks cache: %v", err)
+	}
 	log.Printf("[Startup] Caches loaded (%v)", time.Since(startupStep))
 
+	// Per-provider circuit breaker settings are read from the provider cache
+	cooldown.Default().SetProviderRepository(cachedProviderRepo)
+
 	// Create router
 	r := router.NewRouter(cachedRouteRepo, cachedProviderRepo, cachedRoutingStrategyRepo, cachedRetryConfigRepo, cachedProjectRepo)
 
@@ -245,6 +302,15 @@ func main() {
 		wsHub,
 	)
 
+	// Billing reports are written to <data dir>/reports
+	reportGenerator := report.NewGenerator(usageStatsRepo, cachedProviderRepo, cachedProjectRepo, cachedAPITokenRepo, settingRepo, dataDirPath)
+
+	// Anomaly detection compares recent usage of each token / project with its baseline
+	anomalyDetector := anomaly.NewDetector(usageStatsRepo, cachedAPITokenRepo, cachedProjectRepo, settingRepo, wsHub)
+
+	// Health probes send tiny requests through the cached adapters and apply / clear cooldowns
+	healthChecker := health.NewChecker(cachedProviderRepo, cachedRouteRepo, healthCheckRepo, r, wsHub)
+
 	// Start background tasks
 	core.StartBackgroundTasks(core.BackgroundTaskDeps{
 		DB:                 db,
@@ -252,6 +318,11 @@ func main() {
 		ProxyRequest:       proxyRequestRepo,
 		AttemptRepo:        attemptRepo,
 		Settings:           settingRepo,
+		AuditLog:           auditLogRepo,
+		DetailStore:        detailStore,
+		ReportGenerator:    reportGenerator,
+		AnomalyDetector:    anomalyDetector,
+		HealthChecker:      healthChecker,
 		AntigravityTaskSvc: antigravityTaskSvc,
 		CodexTaskSvc:       codexTaskSvc,
 	})
@@ -262,12 +333,14 @@ func main() {
 
 	// Create project waiter for force project binding
 	projectWaiter := waiter.NewProjectWaiter(cachedSessionRepo, settingRepo, wsHub)
+	loopGuard := waiter.NewLoopGuard(settingRepo)
+	admissionQueue := admission.NewQueue(settingRepo, cachedProjectRepo)
 
 	// Create stats aggregator
 	statsAggregator := stats.NewStatsAggregator(usageStatsRepo)
 
 	// Create executor
-	requestExecutor := executor.NewExecutor(r, proxyRequestRepo, attemptRepo, cachedRetryConfigRepo, cachedSessionRepo, cachedModelMappingRepo, settingRepo, wsHub, projectWaiter, instanceID, statsAggregator)
+	requestExecutor := executor.NewExecutor(r, detailstore.WrapProxyRequestRepository(proxyRequestRepo, detailStore), detailstore.WrapProxyUpstreamAttemptRepository(attemptRepo, detailStore), cachedRetryConfigRepo, cachedSessionRepo, cachedModelMappingRepo, cachedModelFallbackRepo, settingRepo, wsHub, projectWaiter, loopGuard, admissionQueue, instanceID, statsAggregator)
 
 	// Create client adapter
 	clientAdapter := client.NewAdapter()
@@ -289,11 +362,20 @@ func main() {
 		usageStatsRepo,
 		responseModelRepo,
 		modelPriceRepo,
+		auditLogRepo,
 		*addr,
 		r, // Router implements ProviderAdapterRefresher interface
 		wsHub,
 		pprofMgr, // Pprof reloader
 	)
+	adminService.SetDetailStore(detailStore)
+	adminService.SetReportGenerator(reportGenerator)
+	adminService.SetBillingRuleRepository(billingRuleRepo)
+	adminService.SetCooldownPolicyRepository(cooldownPolicyRepo)
+	adminService.SetModelFallbackRepository(cachedModelFallbackRepo)
+	adminService.SetAnomalyDetector(anomalyDetector)
+	adminService.SetHealthChecker(healthChecker)
+	adminService.SetAdmissionQueue(admissionQueue)
 
 	// Start pprof manager (will check system settings)
 	if err := pprofMgr.Start(context.Background()); err != nil {
@@ -311,15 +393,21 @@ func main() {
 		cachedAPITokenRepo,
 		cachedModelMappingRepo,
 		modelPriceRepo,
+		cooldownPolicyRepo,
+		cachedModelFallbackRepo,
 		r, // Router implements ProviderAdapterRefresher interface
+		auditLogRepo,
 	)
 
 	// Create auth middleware
 	authMiddleware := handler.NewAuthMiddleware()
 	if authMiddleware.IsEnabled() {
 		log.Println("Admin API authentication is enabled")
+		if oidc := authMiddleware.OIDC(); oidc != nil {
+			log.Printf("Admin OIDC login is enabled (issuer: %s)", oidc.Config().Issuer)
+		}
 	} else {
-		log.Println("Admin API authentication is disabled (set MAXX_ADMIN_PASSWORD to enable)")
+		log.Println("Admin API authentication is disabled (set MAXX_ADMIN_PASSWORD or MAXX_OIDC_ISSUER to enable)")
 	}
 
 	// Create token auth middleware
@@ -335,12 +423,14 @@ func main() {
 	proxyHandler := handler.NewProxyHandler(clientAdapter, requestExecutor, cachedSessionRepo, tokenAuthMiddleware)
 	proxyHandler.SetRequestTracker(requestTracker)
 	adminHandler := handler.NewAdminHandler(adminService, backupService, logPath)
+	projectTokenHandler := handler.NewProjectTokenHandler(adminService, tokenAuthMiddleware)
 	authHandler := handler.NewAuthHandler(authMiddleware)
 	antigravityHandler := handler.NewAntigravityHandler(adminService, antigravityQuotaRepo, wsHub)
 	antigravityHandler.SetTaskService(antigravityTaskSvc)
 	kiroHandler := handler.NewKiroHandler(adminService)
 	codexHandler := handler.NewCodexHandler(adminService, codexQuotaRepo, wsHub)
 	codexHandler.SetTaskService(codexTaskSvc)
+	copilotHandler := handler.NewCopilotHandler(adminService, copilotQuotaRepo, wsHub)
 
 	// Use already-created cached project repository for project proxy handler
 	modelsHandler := handler.NewModelsHandler(responseModelRepo, cachedProviderRepo, cachedModelMappingRepo)
@@ -355,10 +445,16 @@ func main() {
 	// Admin API routes with authentication middleware
 	mux.Handle("/api/admin/", http.StripPrefix("/api", authMiddleware.Wrap(adminHandler)))
 
+	// Project self-service token API (authenticated with a project admin API token)
+	mux.Handle("/api/project/tokens", http.StripPrefix("/api", projectTokenHandler))
+	mux.Handle("/api/project/tokens/", http.StripPrefix("/api", projectTokenHandler))
+
 	// Other API routes (no authentication required)
 	mux.Handle("/api/antigravity/", http.StripPrefix("/api", antigravityHandler))
 	mux.Handle("/api/kiro/", http.StripPrefix("/api", kiroHandler))
 	mux.Handle("/api/codex/", http.StripPrefix("/api", codexHandler))
+	// Copilot token validation, device flow and provider refresh require admin authentication
+	mux.Handle("/api/copilot/", http.StripPrefix("/api", authMiddleware.WrapMutating(copilotHandler)))
 
 	// Proxy routes - catch all AI API endpoints
 	// Claude API
@@ -447,6 +543,9 @@ func main() {
 				log.Printf("Force close error: %v", closeErr)
 			}
 		}
+
+		// Step 4: Finish queued request detail uploads
+		_ = detailstore.Flush(shutdownCtx, detailStore)
 	}
 
 	restartServer := func() error {
@@ -482,6 +581,11 @@ func main() {
 	log.Printf("Data directory: %s", dataDirPath)
 	log.Printf("  Database: %s", dbPath)
 	log.Printf("  Log file: %s", logPath)
+	if fs, ok := detailstore.Backend(detailStore).(*detailstore.FileStore); ok {
+		log.Printf("  Request details: %s", fs.Dir())
+	} else if detailStore != nil {
+		log.Printf("  Request details: S3 bucket")
+	}
 	log.Printf("Admin API: http://localhost%s/api/admin/", *addr)
 	log.Printf("WebSocket: ws://localhost%s/ws", *addr)
 	log.Printf("Proxy endpoints:")
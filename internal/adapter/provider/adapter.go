@@ -17,6 +17,13 @@ type ProviderAdapter interface {
 	Execute(c *flow.Ctx, provider *domain.Provider) error
 }
 
+// ModelAwareAdapter is implemented by adapters whose native client types depend on the target model,
+// e.g. Vertex AI serves Claude models via the Anthropic API and Gemini models via generateContent.
+// The executor uses it instead of SupportedClientTypes to pick the conversion target.
+type ModelAwareAdapter interface {
+	SupportedClientTypesForModel(model string) []domain.ClientType
+}
+
 // AdapterFactory creates ProviderAdapter instances
 type AdapterFactory func(provider *domain.Provider) (ProviderAdapter, error)
 
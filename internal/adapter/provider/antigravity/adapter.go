@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -38,6 +37,7 @@ type AntigravityAdapter struct {
 	tokenMu       sync.RWMutex
 	projectIDOnce sync.Once
 	httpClient    *http.Client
+	oauthClient   *http.Client // token refresh and quota queries
 }
 
 func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
@@ -65,10 +65,20 @@ func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
 		return cliproxyapi.NewAdapter(cliproxyapiProvider)
 	}
 
+	httpClient, err := newUpstreamHTTPClient(p)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
+	oauthClient, err := provider.NewOAuthHTTPClient(p.Config.Network)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
+
 	return &AntigravityAdapter{
-		provider:   p,
-		tokenCache: &TokenCache{},
-		httpClient: newUpstreamHTTPClient(),
+		provider:    p,
+		tokenCache:  &TokenCache{},
+		httpClient:  httpClient,
+		oauthClient: oauthClient,
 	}, nil
 }
 
@@ -170,7 +180,7 @@ func (a *AntigravityAdapter) Execute(c *flow.Ctx, provider *domain.Provider) err
 			if strings.TrimSpace(config.ProjectID) != "" {
 				return
 			}
-			if pid, _, err := FetchProjectInfo(ctx, accessToken, config.Email); err == nil {
+			if pid, _, err := FetchProjectInfo(ctx, a.oauthClient, accessToken, config.Email); err == nil {
 				pid = strings.TrimSpace(pid)
 				if pid != "" {
 					config.ProjectID = pid
@@ -292,7 +302,7 @@ func (a *AntigravityAdapter) Execute(c *flow.Ctx, provider *domain.Provider) err
 					var rateLimitInfo *domain.RateLimitInfo
 					var cooldownUpdateChan chan time.Time
 					if resp.StatusCode == http.StatusTooManyRequests {
-						rateLimitInfo, cooldownUpdateChan = a.parseRateLimitInfo(ctx, body, provider)
+						rateLimitInfo, cooldownUpdateChan = a.parseRateLimitInfo(ctx, body, provider, mappedModel)
 					}
 
 					// Parse retry info for 429/5xx responses (like Antigravity-Manager)
@@ -425,7 +435,7 @@ func (a *AntigravityAdapter) getAccessToken(ctx context.Context) (string, error)
 
 	// Refresh token
 	config := a.provider.Config.Antigravity
-	accessToken, expiresIn, err := refreshGoogleToken(ctx, config.RefreshToken)
+	accessToken, expiresIn, err := refreshGoogleToken(ctx, a.oauthClient, config.RefreshToken)
 	if err != nil {
 		return "", err
 	}
@@ -441,7 +451,7 @@ func (a *AntigravityAdapter) getAccessToken(ctx context.Context) (string, error)
 	return accessToken, nil
 }
 
-func refreshGoogleToken(ctx context.Context, refreshToken string) (string, int, error) {
+func refreshGoogleToken(ctx context.Context, client *http.Client, refreshToken string) (string, int, error) {
 	data := url.Values{}
 	data.Set("grant_type", "refresh_token")
 	data.Set("refresh_token", refreshToken)
@@ -454,7 +464,6 @@ func refreshGoogleToken(ctx context.Context, refreshToken string) (string, int,
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", 0, err
@@ -477,28 +486,8 @@ func refreshGoogleToken(ctx context.Context, refreshToken string) (string, int,
 	return result.AccessToken, result.ExpiresIn, nil
 }
 
-func newUpstreamHTTPClient() *http.Client {
-	// Mirrors Antigravity-Manager's reqwest client settings:
-	// connect_timeout=20s, pool_max_idle_per_host=16, pool_idle_timeout=90s, tcp_keepalive=60s, timeout=600s.
-	dialer := &net.Dialer{
-		Timeout:   20 * time.Second,
-		KeepAlive: 60 * time.Second,
-	}
-
-	transport := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConnsPerHost:   16,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   20 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-	}
-
-	return &http.Client{
-		Transport: transport,
-		Timeout:   600 * time.Second,
-	}
+func newUpstreamHTTPClient(p *domain.Provider) (*http.Client, error) {
+	return provider.NewHTTPClient(p.Config.Network, provider.DefaultTransportOptions())
 }
 
 // applyClaudePostProcess applies minimal post-processing for advanced features
@@ -1070,9 +1059,21 @@ func (a *AntigravityAdapter) handleCollectedStreamResponse(c *flow.Ctx, resp *ht
 	return nil
 }
 
+// rateLimitScope returns the model family sharing the quota of model
+// Claude models share one quota pool on Antigravity, other models are limited individually
+func rateLimitScope(model string) string {
+	if strings.HasPrefix(model, "claude-") {
+		return "claude-*"
+	}
+	return model
+}
+
 // parseRateLimitInfo parses 429 RESOURCE_EXHAUSTED errors and extracts cooldown information
+// Antigravity quota is per model, so the cooldown only covers the quota pool of model
 // Returns RateLimitInfo and optional channel for async cooldown updates
-func (a *AntigravityAdapter) parseRateLimitInfo(ctx context.Context, body []byte, provider *domain.Provider) (*domain.RateLimitInfo, chan time.Time) {
+func (a *AntigravityAdapter) parseRateLimitInfo(ctx context.Context, body []byte, provider *domain.Provider, model string) (*domain.RateLimitInfo, chan time.Time) {
+	scope := rateLimitScope(model)
+
 	// Parse error response to check if it's QUOTA_EXHAUSTED with reset timestamp
 	var errResp struct {
 		Error struct {
@@ -1119,7 +1120,8 @@ func (a *AntigravityAdapter) parseRateLimitInfo(ctx context.Context, body []byte
 			Type:             "quota_exhausted",
 			QuotaResetTime:   resetTime,
 			RetryHintMessage: errResp.Error.Message,
-			ClientType:       "", // Antigravity quota is global, affects all client types
+			ClientType:       "", // Antigravity quota is shared by all client types
+			Model:            scope,
 		}, nil
 	}
 
@@ -1133,6 +1135,7 @@ func (a *AntigravityAdapter) parseRateLimitInfo(ctx context.Context, body []byte
 			QuotaResetTime:   oneMinuteFromNow,
 			RetryHintMessage: errResp.Error.Message,
 			ClientType:       "",
+			Model:            scope,
 		}, nil
 	}
 
@@ -1146,18 +1149,21 @@ func (a *AntigravityAdapter) parseRateLimitInfo(ctx context.Context, body []byte
 		quotaCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		quota, err := FetchQuotaForProvider(quotaCtx, config.RefreshToken, config.ProjectID)
+		quota, err := FetchQuotaForProvider(quotaCtx, a.oauthClient, config.RefreshToken, config.ProjectID)
 		if err != nil {
 			// Failed to fetch quota, send 1-minute cooldown
 			updateChan <- time.Now().Add(time.Minute)
 			return
 		}
 
-		// Check if any model has 0% quota
+		// Check if any model of the rate limited quota pool has 0% quota
 		var earliestReset time.Time
 		hasZeroQuota := false
 
 		for _, model := range quota.Models {
+			if scope != "" && !domain.MatchWildcard(scope, model.Name) {
+				continue
+			}
 			if model.Percentage == 0 && model.ResetTime != "" {
 				hasZeroQuota = true
 				rt, err := time.Parse(time.RFC3339, model.ResetTime)
@@ -1185,7 +1191,8 @@ func (a *AntigravityAdapter) parseRateLimitInfo(ctx context.Context, body []byte
 		Type:             "quota_exhausted",
 		QuotaResetTime:   oneMinuteFromNow,
 		RetryHintMessage: errResp.Error.Message,
-		ClientType:       "", // Global cooldown
+		ClientType:       "", // All client types
+		Model:            scope,
 	}, updateChan
 }
 
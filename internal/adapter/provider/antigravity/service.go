@@ -66,11 +66,11 @@ type TokenValidationResult struct {
 }
 
 // ValidateRefreshToken 验证 refresh token 并获取用户信息和配额
-func ValidateRefreshToken(ctx context.Context, refreshToken string) (*TokenValidationResult, error) {
+func ValidateRefreshToken(ctx context.Context, client *http.Client, refreshToken string) (*TokenValidationResult, error) {
 	result := &TokenValidationResult{}
 
 	// 1. 使用 refresh token 获取 access token
-	accessToken, _, err := refreshGoogleToken(ctx, refreshToken)
+	accessToken, _, err := refreshGoogleToken(ctx, client, refreshToken)
 	if err != nil {
 		result.Valid = false
 		result.Error = fmt.Sprintf("Token refresh failed: %v", err)
@@ -78,7 +78,7 @@ func ValidateRefreshToken(ctx context.Context, refreshToken string) (*TokenValid
 	}
 
 	// 2. 获取用户信息
-	userInfo, err := fetchUserInfo(ctx, accessToken)
+	userInfo, err := fetchUserInfo(ctx, client, accessToken)
 	if err != nil {
 		result.Valid = false
 		result.Error = fmt.Sprintf("Failed to fetch user info: %v", err)
@@ -87,7 +87,7 @@ func ValidateRefreshToken(ctx context.Context, refreshToken string) (*TokenValid
 	result.UserInfo = userInfo
 
 	// 3. 获取 Project ID 和订阅信息
-	projectID, tier, err := fetchProjectInfo(ctx, accessToken, userInfo.Email)
+	projectID, tier, err := fetchProjectInfo(ctx, client, accessToken, userInfo.Email)
 	if err != nil {
 		// Project info 获取失败不算致命错误，继续
 		result.Valid = true
@@ -97,7 +97,7 @@ func ValidateRefreshToken(ctx context.Context, refreshToken string) (*TokenValid
 	result.ProjectID = projectID
 
 	// 4. 获取配额信息
-	quota, err := fetchQuota(ctx, accessToken, projectID)
+	quota, err := fetchQuota(ctx, client, accessToken, projectID)
 	if err != nil {
 		result.Valid = true
 		result.Quota = &QuotaData{
@@ -115,18 +115,18 @@ func ValidateRefreshToken(ctx context.Context, refreshToken string) (*TokenValid
 }
 
 // FetchQuotaForProvider 为现有 provider 获取配额信息
-func FetchQuotaForProvider(ctx context.Context, refreshToken, projectID string) (*QuotaData, error) {
+func FetchQuotaForProvider(ctx context.Context, client *http.Client, refreshToken, projectID string) (*QuotaData, error) {
 	// 获取 access token
-	accessToken, _, err := refreshGoogleToken(ctx, refreshToken)
+	accessToken, _, err := refreshGoogleToken(ctx, client, refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("token refresh failed: %w", err)
 	}
 
 	// 获取订阅信息
-	_, tier, _ := fetchProjectInfoByID(ctx, accessToken, projectID)
+	_, tier, _ := fetchProjectInfoByID(ctx, client, accessToken, projectID)
 
 	// 获取配额
-	quota, err := fetchQuota(ctx, accessToken, projectID)
+	quota, err := fetchQuota(ctx, client, accessToken, projectID)
 	if err != nil {
 		if strings.Contains(err.Error(), "403") {
 			return &QuotaData{
@@ -142,19 +142,18 @@ func FetchQuotaForProvider(ctx context.Context, refreshToken, projectID string)
 }
 
 // FetchUserInfo 获取用户信息 (导出版本供 handler 使用)
-func FetchUserInfo(ctx context.Context, accessToken string) (*UserInfo, error) {
-	return fetchUserInfo(ctx, accessToken)
+func FetchUserInfo(ctx context.Context, client *http.Client, accessToken string) (*UserInfo, error) {
+	return fetchUserInfo(ctx, client, accessToken)
 }
 
 // fetchUserInfo 获取用户信息
-func fetchUserInfo(ctx context.Context, accessToken string) (*UserInfo, error) {
+func fetchUserInfo(ctx context.Context, client *http.Client, accessToken string) (*UserInfo, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", GoogleUserInfoURL, nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -174,12 +173,12 @@ func fetchUserInfo(ctx context.Context, accessToken string) (*UserInfo, error) {
 }
 
 // FetchProjectInfo 获取 Project ID 和订阅信息 (导出版本供 handler 使用)
-func FetchProjectInfo(ctx context.Context, accessToken, email string) (projectID, tier string, err error) {
-	return fetchProjectInfo(ctx, accessToken, email)
+func FetchProjectInfo(ctx context.Context, client *http.Client, accessToken, email string) (projectID, tier string, err error) {
+	return fetchProjectInfo(ctx, client, accessToken, email)
 }
 
 // fetchProjectInfo 获取 Project ID 和订阅信息
-func fetchProjectInfo(ctx context.Context, accessToken, email string) (projectID, tier string, err error) {
+func fetchProjectInfo(ctx context.Context, client *http.Client, accessToken, email string) (projectID, tier string, err error) {
 	payload := map[string]interface{}{
 		"metadata": map[string]string{
 			"ideType": "ANTIGRAVITY",
@@ -195,7 +194,6 @@ func fetchProjectInfo(ctx context.Context, accessToken, email string) (projectID
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", UserAgentLoadCodeAssist)
 
-	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", "", err
@@ -238,7 +236,7 @@ func fetchProjectInfo(ctx context.Context, accessToken, email string) (projectID
 }
 
 // fetchProjectInfoByID 通过已知 projectID 获取订阅信息
-func fetchProjectInfoByID(ctx context.Context, accessToken, projectID string) (string, string, error) {
+func fetchProjectInfoByID(ctx context.Context, client *http.Client, accessToken, projectID string) (string, string, error) {
 	payload := map[string]interface{}{
 		"metadata": map[string]string{
 			"ideType": "ANTIGRAVITY",
@@ -255,7 +253,6 @@ func fetchProjectInfoByID(ctx context.Context, accessToken, projectID string) (s
 	// loadCodeAssist 使用不带版本号的 User-Agent
 	req.Header.Set("User-Agent", UserAgentLoadCodeAssist)
 
-	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return projectID, "FREE", err
@@ -290,12 +287,12 @@ func fetchProjectInfoByID(ctx context.Context, accessToken, projectID string) (s
 }
 
 // FetchQuota 获取配额信息 (导出版本供 handler 使用)
-func FetchQuota(ctx context.Context, accessToken, projectID string) (*QuotaData, error) {
-	return fetchQuota(ctx, accessToken, projectID)
+func FetchQuota(ctx context.Context, client *http.Client, accessToken, projectID string) (*QuotaData, error) {
+	return fetchQuota(ctx, client, accessToken, projectID)
 }
 
 // fetchQuota 获取配额信息
-func fetchQuota(ctx context.Context, accessToken, projectID string) (*QuotaData, error) {
+func fetchQuota(ctx context.Context, client *http.Client, accessToken, projectID string) (*QuotaData, error) {
 	payload := map[string]interface{}{
 		"project": projectID,
 	}
@@ -310,7 +307,6 @@ func fetchQuota(ctx context.Context, accessToken, projectID string) (*QuotaData,
 	// fetchAvailableModels 使用带版本号的 User-Agent
 	req.Header.Set("User-Agent", UserAgentFetchModels)
 
-	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -368,7 +364,7 @@ func fetchQuota(ctx context.Context, accessToken, projectID string) (*QuotaData,
 }
 
 // BatchValidateRefreshTokens 批量验证 refresh tokens
-func BatchValidateRefreshTokens(ctx context.Context, tokens []string) []*TokenValidationResult {
+func BatchValidateRefreshTokens(ctx context.Context, client *http.Client, tokens []string) []*TokenValidationResult {
 	results := make([]*TokenValidationResult, len(tokens))
 
 	for i, token := range tokens {
@@ -381,7 +377,7 @@ func BatchValidateRefreshTokens(ctx context.Context, tokens []string) []*TokenVa
 			continue
 		}
 
-		result, err := ValidateRefreshToken(ctx, token)
+		result, err := ValidateRefreshToken(ctx, client, token)
 		if err != nil {
 			results[i] = &TokenValidationResult{
 				Valid: false,
@@ -461,7 +457,7 @@ func GetAuthURL(redirectURI, state string) string {
 
 // ExchangeCodeForTokens 使用 authorization code 交换 access_token 和 refresh_token
 // 参考: oauth.rs line 75-121
-func ExchangeCodeForTokens(ctx context.Context, code, redirectURI string) (string, string, int, error) {
+func ExchangeCodeForTokens(ctx context.Context, client *http.Client, code, redirectURI string) (string, string, int, error) {
 	data := url.Values{}
 	data.Set("client_id", OAuthClientID)
 	data.Set("client_secret", OAuthClientSecret)
@@ -475,7 +471,6 @@ func ExchangeCodeForTokens(ctx context.Context, code, redirectURI string) (strin
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", "", 0, fmt.Errorf("token exchange request failed: %w", err)
@@ -0,0 +1,323 @@
+package azure_openai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/flow"
+	"github.com/awsl-project/maxx/internal/usage"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+func init() {
+	provider.RegisterAdapterFactory("azure_openai", NewAdapter)
+}
+
+// AzureOpenAIAdapter calls Azure OpenAI deployments with Chat Completions (OpenAI clients)
+// or the Responses API (Codex clients); other client formats are converted by the executor.
+type AzureOpenAIAdapter struct {
+	provider   *domain.Provider
+	entra      *entraTokenSource // nil when using API key auth
+	httpClient *http.Client
+}
+
+// NewAdapter creates a new Azure OpenAI adapter
+func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
+	if p.Config == nil || p.Config.AzureOpenAI == nil {
+		return nil, fmt.Errorf("provider %s missing azure openai config", p.Name)
+	}
+	config := p.Config.AzureOpenAI
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("provider %s missing azure endpoint", p.Name)
+	}
+	useEntra := config.APIKey == ""
+	if useEntra && (config.TenantID == "" || config.ClientID == "" || config.ClientSecret == "") {
+		return nil, fmt.Errorf("provider %s requires an API key or Entra ID client credentials", p.Name)
+	}
+
+	opts := provider.DefaultTransportOptions()
+	opts.Timeout = 10 * time.Minute
+	httpClient, err := provider.NewHTTPClient(p.Config.Network, opts)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
+	adapter := &AzureOpenAIAdapter{provider: p, httpClient: httpClient}
+	if useEntra {
+		adapter.entra = newEntraTokenSource(config, httpClient)
+	}
+	return adapter, nil
+}
+
+// SupportedClientTypes returns the list of client types this adapter natively supports
+func (a *AzureOpenAIAdapter) SupportedClientTypes() []domain.ClientType {
+	return []domain.ClientType{domain.ClientTypeOpenAI, domain.ClientTypeCodex}
+}
+
+// Execute performs the proxy request to Azure OpenAI
+func (a *AzureOpenAIAdapter) Execute(c *flow.Ctx, p *domain.Provider) error {
+	config := a.provider.Config.AzureOpenAI
+	clientType := flow.GetClientType(c)
+	stream := flow.GetIsStream(c)
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+	if clientType != domain.ClientTypeOpenAI && clientType != domain.ClientTypeCodex {
+		return domain.NewProxyErrorWithMessage(domain.ErrFormatConversion, false,
+			fmt.Sprintf("azure openai does not support client type %s", clientType))
+	}
+
+	model := flow.GetMappedModel(c)
+	if model == "" {
+		model = flow.GetRequestModel(c)
+	}
+	deployment := resolveDeployment(model, config)
+	if attempt := flow.GetUpstreamAttempt(c); attempt != nil {
+		attempt.MappedModel = deployment
+	}
+
+	body := flow.GetRequestBody(c)
+	var err error
+	if usesModelInBody(config, clientType) {
+		if body, err = sjson.SetBytes(body, "model", deployment); err != nil {
+			return domain.NewProxyErrorWithMessage(err, false, "failed to update model in body")
+		}
+	}
+	// 流式 Chat Completions 默认不返回 usage，需显式开启以便计费
+	if stream && clientType == domain.ClientTypeOpenAI && !gjson.GetBytes(body, "stream_options.include_usage").Exists() {
+		if body, err = sjson.SetBytes(body, "stream_options.include_usage", true); err != nil {
+			return domain.NewProxyErrorWithMessage(err, false, "failed to set stream options")
+		}
+	}
+
+	upstreamURL := buildURL(config, clientType, deployment)
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(err, true, "failed to create upstream request")
+	}
+	upstreamReq.Header.Set("Content-Type", "application/json")
+	if stream {
+		upstreamReq.Header.Set("Accept", "text/event-stream")
+	} else {
+		upstreamReq.Header.Set("Accept", "application/json")
+	}
+	if a.entra != nil {
+		token, tokenErr := a.entra.Token(ctx)
+		if tokenErr != nil {
+			return domain.NewProxyErrorWithMessage(tokenErr, true, fmt.Sprintf("failed to get Entra ID token: %v", tokenErr))
+		}
+		upstreamReq.Header.Set("Authorization", "Bearer "+token)
+	} else {
+		upstreamReq.Header.Set("api-key", config.APIKey)
+	}
+
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendRequestInfo(&domain.RequestInfo{
+		Method:  upstreamReq.Method,
+		URL:     upstreamURL,
+		Headers: flattenHeaders(upstreamReq.Header),
+		Body:    string(body),
+	})
+
+	resp, err := a.httpClient.Do(upstreamReq)
+	if err != nil {
+		proxyErr := domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to connect to upstream")
+		proxyErr.IsNetworkError = true
+		return proxyErr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    string(respBody),
+		})
+		if resp.StatusCode == http.StatusUnauthorized && a.entra != nil {
+			a.entra.Invalidate()
+		}
+		return newUpstreamError(resp, respBody)
+	}
+
+	if stream {
+		return a.handleStreamResponse(c, resp, clientType)
+	}
+	return a.handleNonStreamResponse(c, resp, clientType)
+}
+
+func (a *AzureOpenAIAdapter) handleNonStreamResponse(c *flow.Ctx, resp *http.Response, clientType domain.ClientType) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to read upstream response")
+	}
+
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    string(body),
+	})
+	if metrics := usage.ExtractFromResponse(string(body)); metrics != nil {
+		eventChan.SendMetrics(toAdapterMetrics(metrics))
+	}
+	eventChan.SendResponseModel(gjson.GetBytes(body, "model").String())
+
+	c.Writer.Header().Set("Content-Type", "application/json")
+	c.Writer.WriteHeader(http.StatusOK)
+	_, _ = c.Writer.Write(body)
+	return nil
+}
+
+// handleStreamResponse passes the SSE stream through unchanged
+func (a *AzureOpenAIAdapter) handleStreamResponse(c *flow.Ctx, resp *http.Response, clientType domain.ClientType) error {
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    "[streaming]",
+	})
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, false, "streaming not supported")
+	}
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	var sseBuffer strings.Builder
+	sendFinalEvents := func() {
+		if sseBuffer.Len() == 0 {
+			return
+		}
+		content := sseBuffer.String()
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    content,
+		})
+		if metrics := usage.ExtractFromStreamContent(content); metrics != nil {
+			eventChan.SendMetrics(toAdapterMetrics(metrics))
+		}
+		eventChan.SendResponseModel(extractStreamModel(content))
+	}
+
+	headerWritten := false
+	buf := make([]byte, 32*1024)
+	for {
+		select {
+		case <-ctx.Done():
+			sendFinalEvents()
+			return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+		default:
+		}
+
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if !headerWritten {
+				c.Writer.WriteHeader(http.StatusOK)
+				headerWritten = true
+				eventChan.SendFirstToken(time.Now().UnixMilli())
+			}
+			sseBuffer.Write(buf[:n])
+			if _, writeErr := c.Writer.Write(buf[:n]); writeErr != nil {
+				sendFinalEvents()
+				return domain.NewProxyErrorWithMessage(writeErr, false, "client disconnected")
+			}
+			flusher.Flush()
+		}
+
+		if readErr != nil {
+			sendFinalEvents()
+			if readErr == io.EOF {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+			}
+			return domain.NewProxyErrorWithMessage(readErr, false, "failed to read upstream stream")
+		}
+	}
+}
+
+// extractStreamModel returns the model of the last chunk (chat) or response event (responses API)
+func extractStreamModel(content string) string {
+	var model string
+	for _, line := range strings.Split(content, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		if m := gjson.Get(data, "model").String(); m != "" {
+			model = m
+		} else if m := gjson.Get(data, "response.model").String(); m != "" {
+			model = m
+		}
+	}
+	return model
+}
+
+// newUpstreamError builds the ProxyError for an Azure error response
+func newUpstreamError(resp *http.Response, body []byte) *domain.ProxyError {
+	message := gjson.GetBytes(body, "error.message").String()
+	if message == "" {
+		message = string(body)
+	}
+	proxyErr := domain.NewProxyErrorWithMessage(
+		fmt.Errorf("upstream error: %s", message),
+		isRetryableStatusCode(resp.StatusCode),
+		fmt.Sprintf("upstream returned status %d", resp.StatusCode),
+	)
+	proxyErr.HTTPStatusCode = resp.StatusCode
+	proxyErr.IsServerError = resp.StatusCode >= 500 && resp.StatusCode < 600
+	// Azure 限流时通过 retry-after 告知冷却时间
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			proxyErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	return proxyErr
+}
+
+func isRetryableStatusCode(status int) bool {
+	return status == http.StatusTooManyRequests ||
+		status == http.StatusRequestTimeout ||
+		status >= 500
+}
+
+func toAdapterMetrics(m *usage.Metrics) *domain.AdapterMetrics {
+	return &domain.AdapterMetrics{
+		InputTokens:          m.InputTokens,
+		OutputTokens:         m.OutputTokens,
+		CacheReadCount:       m.CacheReadCount,
+		CacheCreationCount:   m.CacheCreationCount,
+		Cache5mCreationCount: m.Cache5mCreationCount,
+		Cache1hCreationCount: m.Cache1hCreationCount,
+	}
+}
+
+func flattenHeaders(h http.Header) map[string]string {
+	result := make(map[string]string)
+	for k, v := range h {
+		if len(v) > 0 {
+			result[k] = v[0]
+		}
+	}
+	return result
+}
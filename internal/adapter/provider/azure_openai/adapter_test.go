@@ -0,0 +1,122 @@
+package azure_openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+func TestResolveDeployment(t *testing.T) {
+	config := &domain.ProviderConfigAzureOpenAI{
+		Deployments: map[string]string{"gpt-4o": "prod-gpt4o"},
+	}
+	if got := resolveDeployment("gpt-4o", config); got != "prod-gpt4o" {
+		t.Errorf("mapped deployment = %q", got)
+	}
+	if got := resolveDeployment("gpt-5", config); got != "gpt-5" {
+		t.Errorf("unmapped model should pass through, got %q", got)
+	}
+
+	config.Deployments["*"] = "default-deployment"
+	if got := resolveDeployment("gpt-5", config); got != "default-deployment" {
+		t.Errorf("wildcard deployment = %q", got)
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	config := &domain.ProviderConfigAzureOpenAI{Endpoint: "https://res.openai.azure.com/"}
+	cases := []struct {
+		apiVersion string
+		clientType domain.ClientType
+		want       string
+	}{
+		{"", domain.ClientTypeOpenAI, "https://res.openai.azure.com/openai/deployments/my-dep/chat/completions?api-version=" + DefaultAPIVersion},
+		{"", domain.ClientTypeCodex, "https://res.openai.azure.com/openai/responses?api-version=" + DefaultAPIVersion},
+		{"2024-10-21", domain.ClientTypeOpenAI, "https://res.openai.azure.com/openai/deployments/my-dep/chat/completions?api-version=2024-10-21"},
+		{"v1", domain.ClientTypeOpenAI, "https://res.openai.azure.com/openai/v1/chat/completions"},
+		{"v1", domain.ClientTypeCodex, "https://res.openai.azure.com/openai/v1/responses"},
+	}
+	for _, tc := range cases {
+		config.APIVersion = tc.apiVersion
+		if got := buildURL(config, tc.clientType, "my-dep"); got != tc.want {
+			t.Errorf("buildURL(%q, %s) = %q, want %q", tc.apiVersion, tc.clientType, got, tc.want)
+		}
+	}
+}
+
+func TestUsesModelInBody(t *testing.T) {
+	config := &domain.ProviderConfigAzureOpenAI{}
+	if usesModelInBody(config, domain.ClientTypeOpenAI) {
+		t.Error("chat completions uses the deployment path")
+	}
+	if !usesModelInBody(config, domain.ClientTypeCodex) {
+		t.Error("responses API takes the deployment as model")
+	}
+	config.APIVersion = "v1"
+	if !usesModelInBody(config, domain.ClientTypeOpenAI) {
+		t.Error("v1 API takes the deployment as model")
+	}
+}
+
+func TestEntraTokenSource(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("scope") != cognitiveScope {
+			t.Errorf("unexpected token request %v", r.Form)
+		}
+		if r.Form.Get("client_id") != "client" || r.Form.Get("client_secret") != "secret" {
+			t.Errorf("unexpected client credentials %v", r.Form)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "entra-token", "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	source := newEntraTokenSource(&domain.ProviderConfigAzureOpenAI{
+		TenantID:     "tenant",
+		ClientID:     "client",
+		ClientSecret: "secret",
+	}, server.Client())
+	if source.tokenURL != entraAuthorityHost+"/tenant/oauth2/v2.0/token" {
+		t.Errorf("unexpected token URL %q", source.tokenURL)
+	}
+	source.tokenURL = server.URL
+
+	for i := 0; i < 2; i++ {
+		token, err := source.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "entra-token" {
+			t.Errorf("unexpected token %q", token)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("token should be cached, got %d requests", calls)
+	}
+	source.Invalidate()
+	if _, err := source.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("invalidate should force a new request, got %d requests", calls)
+	}
+}
+
+func TestNewAdapterValidation(t *testing.T) {
+	p := &domain.Provider{Name: "azure", Config: &domain.ProviderConfig{
+		AzureOpenAI: &domain.ProviderConfigAzureOpenAI{Endpoint: "https://res.openai.azure.com"},
+	}}
+	if _, err := NewAdapter(p); err == nil {
+		t.Error("expected error without API key or Entra credentials")
+	}
+	p.Config.AzureOpenAI.APIKey = "key"
+	if _, err := NewAdapter(p); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
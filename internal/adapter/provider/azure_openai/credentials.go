@@ -0,0 +1,118 @@
+package azure_openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+const (
+	entraAuthorityHost  = "https://login.microsoftonline.com"
+	cognitiveScope      = "https://cognitiveservices.azure.com/.default"
+	tokenRefreshBuffer  = 5 * time.Minute
+	defaultTokenExpires = time.Hour
+)
+
+// entraTokenSource obtains Entra ID access tokens with the OAuth client-credential grant
+// and caches them until shortly before expiry.
+type entraTokenSource struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func newEntraTokenSource(config *domain.ProviderConfigAzureOpenAI, httpClient *http.Client) *entraTokenSource {
+	return &entraTokenSource{
+		tokenURL:     fmt.Sprintf("%s/%s/oauth2/v2.0/token", entraAuthorityHost, url.PathEscape(config.TenantID)),
+		clientID:     config.ClientID,
+		clientSecret: config.ClientSecret,
+		httpClient:   httpClient,
+	}
+}
+
+// Token returns a valid access token, requesting a new one when the cached token is about to expire
+func (s *entraTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Add(tokenRefreshBuffer).Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	token, expiresAt, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	s.expiresAt = expiresAt
+	return token, nil
+}
+
+// Invalidate drops the cached token so the next Token call requests a new one
+func (s *entraTokenSource) Invalidate() {
+	s.mu.Lock()
+	s.token = ""
+	s.expiresAt = time.Time{}
+	s.mu.Unlock()
+}
+
+type entraTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (s *entraTokenSource) fetch(ctx context.Context) (string, time.Time, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", s.clientID)
+	form.Set("client_secret", s.clientSecret)
+	form.Set("scope", cognitiveScope)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	now := time.Now()
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read token response: %w", err)
+	}
+	var result entraTokenResponse
+	_ = json.Unmarshal(body, &result)
+	if resp.StatusCode != http.StatusOK {
+		if result.Error != "" {
+			return "", time.Time{}, fmt.Errorf("entra token request failed: %s: %s", result.Error, result.ErrorDescription)
+		}
+		return "", time.Time{}, fmt.Errorf("entra token request failed: status %d", resp.StatusCode)
+	}
+	if result.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("entra token response missing access_token")
+	}
+	expiresIn := time.Duration(result.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = defaultTokenExpires
+	}
+	return result.AccessToken, now.Add(expiresIn), nil
+}
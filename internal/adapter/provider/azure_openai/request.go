@@ -0,0 +1,64 @@
+package azure_openai
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+const (
+	// DefaultAPIVersion 同时支持 Chat Completions 与 Responses API 的版本
+	DefaultAPIVersion = "2025-04-01-preview"
+
+	// apiVersionV1 使用无需 api-version 的 /openai/v1/ 路径
+	apiVersionV1 = "v1"
+)
+
+// resolveDeployment maps a request model to an Azure deployment name
+func resolveDeployment(model string, config *domain.ProviderConfigAzureOpenAI) string {
+	if deployment, ok := config.Deployments[model]; ok && deployment != "" {
+		return deployment
+	}
+	if deployment, ok := config.Deployments["*"]; ok && deployment != "" {
+		return deployment
+	}
+	return model
+}
+
+// apiVersion returns the configured API version or the default
+func apiVersion(config *domain.ProviderConfigAzureOpenAI) string {
+	if config.APIVersion == "" {
+		return DefaultAPIVersion
+	}
+	return config.APIVersion
+}
+
+// usesModelInBody reports whether the deployment is sent as the body "model"
+// rather than in the URL path (Responses API and the v1 API)
+func usesModelInBody(config *domain.ProviderConfigAzureOpenAI, clientType domain.ClientType) bool {
+	return clientType == domain.ClientTypeCodex || apiVersion(config) == apiVersionV1
+}
+
+// buildURL returns the Azure URL for a client type:
+//   - OpenAI chat: {endpoint}/openai/deployments/{deployment}/chat/completions?api-version=...
+//   - Codex responses: {endpoint}/openai/responses?api-version=...
+//   - v1 API: {endpoint}/openai/v1/chat/completions or {endpoint}/openai/v1/responses
+func buildURL(config *domain.ProviderConfigAzureOpenAI, clientType domain.ClientType, deployment string) string {
+	endpoint := strings.TrimSuffix(config.Endpoint, "/")
+	version := apiVersion(config)
+
+	operation := "chat/completions"
+	if clientType == domain.ClientTypeCodex {
+		operation = "responses"
+	}
+	if version == apiVersionV1 {
+		return endpoint + "/openai/v1/" + operation
+	}
+
+	query := "?api-version=" + url.QueryEscape(version)
+	if clientType == domain.ClientTypeCodex {
+		return endpoint + "/openai/responses" + query
+	}
+	return endpoint + "/openai/deployments/" + url.PathEscape(deployment) + "/" + operation + query
+}
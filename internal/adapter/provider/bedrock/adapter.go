@@ -0,0 +1,298 @@
+package bedrock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/flow"
+	"github.com/awsl-project/maxx/internal/sigv4"
+	"github.com/awsl-project/maxx/internal/usage"
+	"github.com/tidwall/gjson"
+)
+
+func init() {
+	provider.RegisterAdapterFactory("bedrock", NewAdapter)
+}
+
+// BedrockAdapter calls Claude models through AWS Bedrock InvokeModel with SigV4 signing
+type BedrockAdapter struct {
+	provider    *domain.Provider
+	region      string
+	credentials *credentialProvider
+	httpClient  *http.Client
+}
+
+// NewAdapter creates a new Bedrock adapter
+func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
+	if p.Config == nil || p.Config.Bedrock == nil {
+		return nil, fmt.Errorf("provider %s missing bedrock config", p.Name)
+	}
+	config := p.Config.Bedrock
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("provider %s missing AWS access key", p.Name)
+	}
+	opts := provider.DefaultTransportOptions()
+	opts.Timeout = 10 * time.Minute
+	httpClient, err := provider.NewHTTPClient(p.Config.Network, opts)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
+	region := config.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+	return &BedrockAdapter{
+		provider:    p,
+		region:      region,
+		credentials: newCredentialProvider(config, region, httpClient),
+		httpClient:  httpClient,
+	}, nil
+}
+
+// SupportedClientTypes returns the list of client types this adapter natively supports
+func (a *BedrockAdapter) SupportedClientTypes() []domain.ClientType {
+	return []domain.ClientType{domain.ClientTypeClaude}
+}
+
+// Execute performs the proxy request to Bedrock
+func (a *BedrockAdapter) Execute(c *flow.Ctx, p *domain.Provider) error {
+	config := a.provider.Config.Bedrock
+	requestBody := flow.GetRequestBody(c)
+	stream := flow.GetIsStream(c)
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	model := flow.GetMappedModel(c)
+	if model == "" {
+		model = flow.GetRequestModel(c)
+	}
+	modelID := resolveModelID(model, config)
+	if attempt := flow.GetUpstreamAttempt(c); attempt != nil {
+		attempt.MappedModel = modelID
+	}
+
+	body, err := buildRequestBody(requestBody, flow.GetRequestHeaders(c))
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(err, false, fmt.Sprintf("failed to convert request: %v", err))
+	}
+
+	creds, err := a.credentials.Get(ctx)
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(err, true, "failed to get AWS credentials")
+	}
+
+	upstreamURL := buildURL(config, a.region, modelID, stream)
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(err, true, "failed to create upstream request")
+	}
+	upstreamReq.Header.Set("Content-Type", "application/json")
+	if stream {
+		upstreamReq.Header.Set("Accept", "application/vnd.amazon.eventstream")
+	} else {
+		upstreamReq.Header.Set("Accept", "application/json")
+	}
+	sigv4.Signer{Credentials: creds, Region: a.region, Service: "bedrock", EscapePathTwice: true}.Sign(upstreamReq, body, time.Now())
+
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendRequestInfo(&domain.RequestInfo{
+		Method:  upstreamReq.Method,
+		URL:     upstreamURL,
+		Headers: flattenHeaders(upstreamReq.Header),
+		Body:    string(body),
+	})
+
+	resp, err := a.httpClient.Do(upstreamReq)
+	if err != nil {
+		proxyErr := domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to connect to upstream")
+		proxyErr.IsNetworkError = true
+		return proxyErr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    string(respBody),
+		})
+		// 角色临时凭证失效时下次重新 AssumeRole
+		if resp.StatusCode == http.StatusForbidden && config.RoleARN != "" {
+			a.credentials.Invalidate()
+		}
+		message := gjson.GetBytes(respBody, "message").String()
+		if message == "" {
+			message = string(respBody)
+		}
+		return newUpstreamError(resp.StatusCode, message)
+	}
+
+	if stream {
+		return a.handleStreamResponse(c, resp)
+	}
+	return a.handleNonStreamResponse(c, resp)
+}
+
+func (a *BedrockAdapter) handleNonStreamResponse(c *flow.Ctx, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to read upstream response")
+	}
+
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    string(body),
+	})
+	if metrics := usage.ExtractFromResponse(string(body)); metrics != nil {
+		eventChan.SendMetrics(toAdapterMetrics(metrics))
+	}
+	eventChan.SendResponseModel(gjson.GetBytes(body, "model").String())
+
+	c.Writer.Header().Set("Content-Type", "application/json")
+	c.Writer.WriteHeader(http.StatusOK)
+	_, _ = c.Writer.Write(body)
+	return nil
+}
+
+func (a *BedrockAdapter) handleStreamResponse(c *flow.Ctx, resp *http.Response) error {
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    "[streaming]",
+	})
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, false, "streaming not supported")
+	}
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	var sseBuffer strings.Builder
+	sendFinalEvents := func() {
+		if sseBuffer.Len() == 0 {
+			return
+		}
+		content := sseBuffer.String()
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    content,
+		})
+		if metrics := usage.ExtractFromStreamContent(content); metrics != nil {
+			eventChan.SendMetrics(toAdapterMetrics(metrics))
+		}
+		eventChan.SendResponseModel(extractStreamModel(content))
+	}
+
+	decoder := newStreamDecoder()
+	headerWritten := false
+	buf := make([]byte, 32*1024)
+	for {
+		select {
+		case <-ctx.Done():
+			sendFinalEvents()
+			return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+		default:
+		}
+
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			events, exc := decoder.DecodeOrFail(buf[:n])
+			if exc != nil {
+				status := exceptionStatus(exc.Type)
+				// 还未向客户端写出任何内容时返回错误，交给重试逻辑切换路由
+				if !headerWritten {
+					sendFinalEvents()
+					return newUpstreamError(status, exc.Type+": "+exc.Message)
+				}
+				events = append(events, errorSSE(status, exc.Message))
+			}
+			for _, event := range events {
+				if !headerWritten {
+					c.Writer.WriteHeader(http.StatusOK)
+					headerWritten = true
+					eventChan.SendFirstToken(time.Now().UnixMilli())
+				}
+				sseBuffer.WriteString(event)
+				if _, writeErr := c.Writer.Write([]byte(event)); writeErr != nil {
+					sendFinalEvents()
+					return domain.NewProxyErrorWithMessage(writeErr, false, "client disconnected")
+				}
+			}
+			if len(events) > 0 {
+				flusher.Flush()
+			}
+			if exc != nil {
+				sendFinalEvents()
+				return newUpstreamError(exceptionStatus(exc.Type), exc.Type+": "+exc.Message)
+			}
+		}
+
+		if readErr != nil {
+			sendFinalEvents()
+			if readErr == io.EOF {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+			}
+			return domain.NewProxyErrorWithMessage(readErr, false, "failed to read upstream stream")
+		}
+	}
+}
+
+// extractStreamModel returns the model from the message_start event
+func extractStreamModel(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		if model := gjson.Get(data, "message.model").String(); model != "" {
+			return model
+		}
+	}
+	return ""
+}
+
+func toAdapterMetrics(m *usage.Metrics) *domain.AdapterMetrics {
+	return &domain.AdapterMetrics{
+		InputTokens:          m.InputTokens,
+		OutputTokens:         m.OutputTokens,
+		CacheReadCount:       m.CacheReadCount,
+		CacheCreationCount:   m.CacheCreationCount,
+		Cache5mCreationCount: m.Cache5mCreationCount,
+		Cache1hCreationCount: m.Cache1hCreationCount,
+	}
+}
+
+func flattenHeaders(h http.Header) map[string]string {
+	result := make(map[string]string)
+	for k, v := range h {
+		if len(v) > 0 {
+			result[k] = v[0]
+		}
+	}
+	return result
+}
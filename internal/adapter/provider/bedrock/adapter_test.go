@@ -0,0 +1,150 @@
+package bedrock
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/tidwall/gjson"
+)
+
+func TestResolveModelID(t *testing.T) {
+	config := &domain.ProviderConfigBedrock{
+		ModelMapping: map[string]string{"claude-sonnet-4-5": "us.anthropic.claude-sonnet-4-5-20250929-v1:0"},
+	}
+	cases := map[string]string{
+		"claude-sonnet-4-5":                                 "us.anthropic.claude-sonnet-4-5-20250929-v1:0",
+		"claude-sonnet-4-20250514":                          "anthropic.claude-sonnet-4-20250514-v1:0",
+		"anthropic.claude-3-5-sonnet-20241022-v2:0":         "anthropic.claude-3-5-sonnet-20241022-v2:0",
+		"arn:aws:bedrock:us-east-1:123:inference-profile/x": "arn:aws:bedrock:us-east-1:123:inference-profile/x",
+	}
+	for model, want := range cases {
+		if got := resolveModelID(model, config); got != want {
+			t.Errorf("resolveModelID(%q) = %q, want %q", model, got, want)
+		}
+	}
+
+	config.InferenceProfilePrefix = "eu"
+	if got := resolveModelID("claude-opus-4-1-20250805", config); got != "eu.anthropic.claude-opus-4-1-20250805-v1:0" {
+		t.Errorf("unexpected profile model ID %q", got)
+	}
+}
+
+func TestBuildRequestBody(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("anthropic-beta", "claude-code-20250219, interleaved-thinking-2025-05-14")
+	body, err := buildRequestBody([]byte(`{"model":"claude","stream":true,"metadata":{"user_id":"u"},"max_tokens":10,"messages":[]}`), headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"model", "stream", "metadata"} {
+		if gjson.GetBytes(body, field).Exists() {
+			t.Errorf("field %s should be removed", field)
+		}
+	}
+	if v := gjson.GetBytes(body, "anthropic_version").String(); v != bedrockAnthropicVersion {
+		t.Errorf("anthropic_version = %q", v)
+	}
+	betas := gjson.GetBytes(body, "anthropic_beta").Array()
+	if len(betas) != 1 || betas[0].String() != "interleaved-thinking-2025-05-14" {
+		t.Errorf("unexpected anthropic_beta %s", gjson.GetBytes(body, "anthropic_beta").Raw)
+	}
+	if gjson.GetBytes(body, "max_tokens").Int() != 10 {
+		t.Error("max_tokens should be preserved")
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	config := &domain.ProviderConfigBedrock{}
+	got := buildURL(config, "us-west-2", "anthropic.claude-sonnet-4-20250514-v1:0", true)
+	want := "https://bedrock-runtime.us-west-2.amazonaws.com/model/anthropic.claude-sonnet-4-20250514-v1%3A0/invoke-with-response-stream"
+	if got != want {
+		t.Errorf("buildURL = %q, want %q", got, want)
+	}
+}
+
+func TestStreamDecoder(t *testing.T) {
+	start := `{"type":"message_start","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":5}}}`
+	stop := `{"type":"message_stop","amazon-bedrock-invocationMetrics":{"inputTokenCount":5}}`
+	data := append(chunkFrame(start), chunkFrame(stop)...)
+
+	d := newStreamDecoder()
+	// 拆成两段输入，验证跨读取边界的帧能被正确拼接
+	events1, exc, _ := d.Decode(data[:10])
+	events2, exc2, _ := d.Decode(data[10:])
+	if exc != nil || exc2 != nil {
+		t.Fatal("unexpected exception")
+	}
+	events := append(events1, events2...)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if !strings.HasPrefix(events[0], "event: message_start\ndata: ") {
+		t.Errorf("unexpected first event %q", events[0])
+	}
+	if strings.Contains(events[1], "invocationMetrics") {
+		t.Errorf("invocation metrics should be stripped: %q", events[1])
+	}
+	if model := extractStreamModel(strings.Join(events, "")); model != "claude-sonnet-4-20250514" {
+		t.Errorf("unexpected model %q", model)
+	}
+
+	exceptionData := frame(map[string]string{
+		":message-type":   "exception",
+		":exception-type": "throttlingException",
+	}, []byte(`{"message":"Too many requests"}`))
+	_, exc, _ = newStreamDecoder().Decode(exceptionData)
+	if exc == nil || exc.Type != "throttlingException" || exc.Message != "Too many requests" {
+		t.Fatalf("unexpected exception %+v", exc)
+	}
+	if exceptionStatus(exc.Type) != http.StatusTooManyRequests {
+		t.Error("throttling should map to 429")
+	}
+}
+
+func chunkFrame(event string) []byte {
+	payload := `{"bytes":"` + base64.StdEncoding.EncodeToString([]byte(event)) + `"}`
+	return frame(map[string]string{
+		":message-type": "event",
+		":event-type":   "chunk",
+		":content-type": "application/json",
+	}, []byte(payload))
+}
+
+// frame encodes an AWS EventStream message with string headers
+func frame(headers map[string]string, payload []byte) []byte {
+	var h []byte
+	for name, value := range headers {
+		h = append(h, byte(len(name)))
+		h = append(h, name...)
+		h = append(h, 7) // string
+		h = binary.BigEndian.AppendUint16(h, uint16(len(value)))
+		h = append(h, value...)
+	}
+	total := 12 + len(h) + len(payload) + 4
+	msg := binary.BigEndian.AppendUint32(nil, uint32(total))
+	msg = binary.BigEndian.AppendUint32(msg, uint32(len(h)))
+	msg = binary.BigEndian.AppendUint32(msg, crc32.ChecksumIEEE(msg))
+	msg = append(msg, h...)
+	msg = append(msg, payload...)
+	return binary.BigEndian.AppendUint32(msg, crc32.ChecksumIEEE(msg))
+}
+
+func TestStreamDecoder_FailsOnCorruptStream(t *testing.T) {
+	d := newStreamDecoder()
+	garbage := make([]byte, 64)
+	for i := range garbage {
+		garbage[i] = 0xff
+	}
+	var exc *streamException
+	for i := 0; i < 5 && exc == nil; i++ {
+		_, exc = d.DecodeOrFail(garbage)
+	}
+	if exc == nil || !strings.Contains(exc.Message, "failed to decode event stream") {
+		t.Fatalf("expected a decode exception, got %+v", exc)
+	}
+}
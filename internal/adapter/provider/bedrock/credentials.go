@@ -0,0 +1,151 @@
+package bedrock
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/sigv4"
+)
+
+const (
+	stsAPIVersion = "2011-06-15"
+
+	// AssumeRole 临时凭证有效期
+	assumeRoleDuration = time.Hour
+
+	// 临时凭证提前刷新的时间
+	credentialRefreshBuffer = 5 * time.Minute
+)
+
+// credentialProvider returns the credentials used to sign Bedrock requests.
+// Without a role ARN the static credentials are used as-is, otherwise they are
+// exchanged for temporary role credentials via STS AssumeRole and cached until shortly before expiry.
+type credentialProvider struct {
+	config     *domain.ProviderConfigBedrock
+	region     string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	cached    sigv4.Credentials
+	expiresAt time.Time
+}
+
+func newCredentialProvider(config *domain.ProviderConfigBedrock, region string, httpClient *http.Client) *credentialProvider {
+	return &credentialProvider{config: config, region: region, httpClient: httpClient}
+}
+
+func (p *credentialProvider) static() sigv4.Credentials {
+	return sigv4.Credentials{
+		AccessKeyID:     p.config.AccessKeyID,
+		SecretAccessKey: p.config.SecretAccessKey,
+		SessionToken:    p.config.SessionToken,
+	}
+}
+
+// Get returns valid credentials, assuming the configured role when necessary
+func (p *credentialProvider) Get(ctx context.Context) (sigv4.Credentials, error) {
+	if p.config.RoleARN == "" {
+		return p.static(), nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cached.AccessKeyID != "" && time.Now().Add(credentialRefreshBuffer).Before(p.expiresAt) {
+		return p.cached, nil
+	}
+
+	creds, expiresAt, err := p.assumeRole(ctx)
+	if err != nil {
+		return sigv4.Credentials{}, err
+	}
+	p.cached = creds
+	p.expiresAt = expiresAt
+	return creds, nil
+}
+
+// Invalidate drops cached role credentials so the next Get assumes the role again
+func (p *credentialProvider) Invalidate() {
+	p.mu.Lock()
+	p.cached = sigv4.Credentials{}
+	p.expiresAt = time.Time{}
+	p.mu.Unlock()
+}
+
+type assumeRoleResponse struct {
+	Result struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"Credentials"`
+	} `xml:"AssumeRoleResult"`
+}
+
+type stsErrorResponse struct {
+	Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+func (p *credentialProvider) assumeRole(ctx context.Context) (sigv4.Credentials, time.Time, error) {
+	form := url.Values{}
+	form.Set("Action", "AssumeRole")
+	form.Set("Version", stsAPIVersion)
+	form.Set("RoleArn", p.config.RoleARN)
+	form.Set("RoleSessionName", fmt.Sprintf("maxx-%d", time.Now().Unix()))
+	form.Set("DurationSeconds", fmt.Sprintf("%d", int(assumeRoleDuration.Seconds())))
+	if p.config.ExternalID != "" {
+		form.Set("ExternalId", p.config.ExternalID)
+	}
+	body := []byte(form.Encode())
+
+	endpoint := fmt.Sprintf("https://sts.%s.amazonaws.com/", p.region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return sigv4.Credentials{}, time.Time{}, fmt.Errorf("failed to create AssumeRole request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	sigv4.Signer{Credentials: p.static(), Region: p.region, Service: "sts", EscapePathTwice: true}.Sign(req, body, time.Now())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return sigv4.Credentials{}, time.Time{}, fmt.Errorf("AssumeRole request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return sigv4.Credentials{}, time.Time{}, fmt.Errorf("failed to read AssumeRole response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var stsErr stsErrorResponse
+		if xml.Unmarshal(respBody, &stsErr) == nil && stsErr.Error.Code != "" {
+			return sigv4.Credentials{}, time.Time{}, fmt.Errorf("AssumeRole failed: %s: %s", stsErr.Error.Code, stsErr.Error.Message)
+		}
+		return sigv4.Credentials{}, time.Time{}, fmt.Errorf("AssumeRole failed: status %d", resp.StatusCode)
+	}
+
+	var result assumeRoleResponse
+	if err := xml.Unmarshal(respBody, &result); err != nil {
+		return sigv4.Credentials{}, time.Time{}, fmt.Errorf("failed to decode AssumeRole response: %w", err)
+	}
+	c := result.Result.Credentials
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return sigv4.Credentials{}, time.Time{}, fmt.Errorf("AssumeRole returned empty credentials")
+	}
+	return sigv4.Credentials{
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+	}, c.Expiration, nil
+}
@@ -0,0 +1,110 @@
+package bedrock
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	// DefaultRegion 默认 AWS 区域
+	DefaultRegion = "us-east-1"
+
+	// bedrockAnthropicVersion Bedrock Claude Messages API 固定的 anthropic_version
+	bedrockAnthropicVersion = "bedrock-2023-05-31"
+)
+
+// supportedBetas Bedrock 接受的 anthropic-beta 列表，其余 beta（如 claude-code、oauth）会导致 400
+var supportedBetas = map[string]bool{
+	"computer-use-2024-10-22":          true,
+	"computer-use-2025-01-24":          true,
+	"token-efficient-tools-2025-02-19": true,
+	"interleaved-thinking-2025-05-14":  true,
+	"output-128k-2025-02-19":           true,
+	"dev-full-thinking-2025-05-14":     true,
+	"context-1m-2025-08-07":            true,
+	"context-management-2025-06-27":    true,
+	"effort-2025-11-24":                true,
+	"tool-search-tool-2025-10-19":      true,
+	"tool-examples-2025-10-29":         true,
+}
+
+// unsupportedFields Claude API 请求中 Bedrock 不接受的顶层字段
+var unsupportedFields = []string{"model", "stream", "metadata"}
+
+// resolveModelID maps a Claude model name to a Bedrock model ID.
+// Provider-level mapping wins; Bedrock IDs and ARNs pass through unchanged;
+// otherwise "claude-sonnet-4-20250514" becomes "anthropic.claude-sonnet-4-20250514-v1:0",
+// prefixed with the cross-region inference profile when configured.
+// Undated aliases (e.g. "claude-sonnet-4-5") have no Bedrock equivalent and need an explicit mapping.
+func resolveModelID(model string, config *domain.ProviderConfigBedrock) string {
+	if mapped, ok := config.ModelMapping[model]; ok && mapped != "" {
+		return mapped
+	}
+	if strings.HasPrefix(model, "arn:") || strings.Contains(model, "anthropic.") {
+		return model
+	}
+
+	id := "anthropic." + model
+	if !strings.Contains(model, ":") {
+		id += "-v1:0"
+	}
+	if config.InferenceProfilePrefix != "" {
+		id = config.InferenceProfilePrefix + "." + id
+	}
+	return id
+}
+
+// buildRequestBody converts a Claude Messages request into a Bedrock InvokeModel body
+func buildRequestBody(body []byte, headers http.Header) ([]byte, error) {
+	if !gjson.ValidBytes(body) {
+		return nil, fmt.Errorf("invalid JSON request body")
+	}
+
+	var err error
+	for _, field := range unsupportedFields {
+		if body, err = sjson.DeleteBytes(body, field); err != nil {
+			return nil, err
+		}
+	}
+	if body, err = sjson.SetBytes(body, "anthropic_version", bedrockAnthropicVersion); err != nil {
+		return nil, err
+	}
+
+	// anthropic-beta 请求头在 Bedrock 中通过 body 的 anthropic_beta 传递
+	var betas []string
+	for _, value := range headers.Values("anthropic-beta") {
+		for _, beta := range strings.Split(value, ",") {
+			beta = strings.TrimSpace(beta)
+			if supportedBetas[beta] {
+				betas = append(betas, beta)
+			}
+		}
+	}
+	if len(betas) > 0 {
+		if body, err = sjson.SetBytes(body, "anthropic_beta", betas); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// buildURL returns the InvokeModel or InvokeModelWithResponseStream URL for a model
+func buildURL(config *domain.ProviderConfigBedrock, region, modelID string, stream bool) string {
+	endpoint := strings.TrimSuffix(config.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)
+	}
+	action := "invoke"
+	if stream {
+		action = "invoke-with-response-stream"
+	}
+	// Model ID 含 ":"，ARN 还含 "/"，与 AWS SDK 一致整体转义为一个路径段
+	escaped := strings.ReplaceAll(url.PathEscape(modelID), ":", "%3A")
+	return fmt.Sprintf("%s/model/%s/%s", endpoint, escaped, action)
+}
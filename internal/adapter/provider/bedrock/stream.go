@@ -0,0 +1,165 @@
+package bedrock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider/kiro"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// streamDecoder converts InvokeModelWithResponseStream EventStream frames back into Claude SSE.
+// Frame decoding reuses the Kiro EventStream parser; every "chunk" event carries one
+// base64-encoded Claude streaming event in its "bytes" field.
+type streamDecoder struct {
+	parser *kiro.RobustEventStreamParser
+}
+
+func newStreamDecoder() *streamDecoder {
+	return &streamDecoder{parser: kiro.NewRobustEventStreamParser()}
+}
+
+type chunkPayload struct {
+	Bytes []byte `json:"bytes"` // encoding/json 自动做 base64 解码
+}
+
+// streamException is an exception frame sent by Bedrock in the middle of a stream
+type streamException struct {
+	Type    string
+	Message string
+}
+
+// Decode feeds raw response bytes and returns the complete SSE events decoded so far.
+// An exception frame stops decoding and is returned separately.
+func (d *streamDecoder) Decode(data []byte) ([]string, *streamException, error) {
+	messages, err := d.parser.ParseStream(data)
+	var events []string
+	for _, msg := range messages {
+		switch msg.MessageType {
+		case "exception", "error":
+			exc := &streamException{Type: headerString(msg, ":exception-type")}
+			if exc.Type == "" {
+				exc.Type = headerString(msg, ":error-code")
+			}
+			exc.Message = gjson.GetBytes(msg.Payload, "message").String()
+			if exc.Message == "" {
+				exc.Message = headerString(msg, ":error-message")
+			}
+			return events, exc, nil
+		}
+		if msg.EventType != "chunk" {
+			continue
+		}
+		var chunk chunkPayload
+		if jsonErr := json.Unmarshal(msg.Payload, &chunk); jsonErr != nil || len(chunk.Bytes) == 0 {
+			continue
+		}
+		events = append(events, toSSE(chunk.Bytes))
+	}
+	return events, nil, err
+}
+
+// DecodeOrFail is Decode with unparseable frames reported as an exception,
+// so the stream ends with an error instead of silently dropping events
+func (d *streamDecoder) DecodeOrFail(data []byte) ([]string, *streamException) {
+	events, exc, err := d.Decode(data)
+	if exc == nil && err != nil {
+		exc = &streamException{Type: "invalidEventStreamException", Message: "failed to decode event stream: " + err.Error()}
+	}
+	return events, exc
+}
+
+// toSSE formats one Claude streaming event, dropping the Bedrock-only invocation metrics
+func toSSE(event []byte) string {
+	if gjson.GetBytes(event, "amazon-bedrock-invocationMetrics").Exists() {
+		if cleaned, err := sjson.DeleteBytes(event, "amazon-bedrock-invocationMetrics"); err == nil {
+			event = cleaned
+		}
+	}
+	eventType := gjson.GetBytes(event, "type").String()
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", eventType, event)
+}
+
+func headerString(msg *kiro.EventStreamMessage, name string) string {
+	if h, ok := msg.Headers[name]; ok {
+		if v, ok := h.Value.(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// exceptionStatus maps Bedrock exception types to the HTTP status of the equivalent non-streaming error
+func exceptionStatus(exceptionType string) int {
+	switch strings.ToLower(strings.TrimSuffix(exceptionType, "Exception")) {
+	case "throttling":
+		return http.StatusTooManyRequests
+	case "validation":
+		return http.StatusBadRequest
+	case "accessdenied":
+		return http.StatusForbidden
+	case "resourcenotfound":
+		return http.StatusNotFound
+	case "modeltimeout":
+		return http.StatusRequestTimeout
+	case "servicequotaexceeded":
+		return http.StatusTooManyRequests
+	case "modelnotready", "serviceunavailable":
+		return http.StatusServiceUnavailable
+	default:
+		// internalServerException, modelStreamErrorException 等
+		return http.StatusInternalServerError
+	}
+}
+
+// claudeErrorType maps an HTTP status to the Claude API error type
+func claudeErrorType(status int) string {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return "rate_limit_error"
+	case status == http.StatusBadRequest:
+		return "invalid_request_error"
+	case status == http.StatusForbidden:
+		return "permission_error"
+	case status == http.StatusNotFound:
+		return "not_found_error"
+	case status == http.StatusServiceUnavailable:
+		return "overloaded_error"
+	default:
+		return "api_error"
+	}
+}
+
+// errorSSE builds a Claude SSE error event for exceptions after the stream has started
+func errorSSE(status int, message string) string {
+	data, _ := json.Marshal(map[string]any{
+		"type": "error",
+		"error": map[string]string{
+			"type":    claudeErrorType(status),
+			"message": message,
+		},
+	})
+	return fmt.Sprintf("event: error\ndata: %s\n\n", data)
+}
+
+// newUpstreamError builds the ProxyError for a Bedrock error status
+func newUpstreamError(status int, message string) *domain.ProxyError {
+	proxyErr := domain.NewProxyErrorWithMessage(
+		fmt.Errorf("upstream error: %s", message),
+		isRetryableStatusCode(status),
+		fmt.Sprintf("upstream returned status %d", status),
+	)
+	proxyErr.HTTPStatusCode = status
+	proxyErr.IsServerError = status >= 500 && status < 600
+	return proxyErr
+}
+
+func isRetryableStatusCode(status int) bool {
+	return status == http.StatusTooManyRequests ||
+		status == http.StatusRequestTimeout ||
+		status >= 500
+}
@@ -35,6 +35,7 @@ type CLIProxyAPICodexAdapter struct {
 	executor       *exec.CodexExecutor
 	tokenCache     *TokenCache
 	tokenMu        sync.RWMutex
+	oauthClient    *http.Client // token refresh
 	providerUpdate func(*domain.Provider) error
 }
 
@@ -56,6 +57,11 @@ func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
 
 	cfg := p.Config.Codex
 
+	oauthClient, err := provider.NewOAuthHTTPClient(p.Config.Network)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
+
 	// 创建 Auth 对象
 	metadata := map[string]any{
 		"type":          "codex",
@@ -71,10 +77,11 @@ func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
 	}
 
 	adapter := &CLIProxyAPICodexAdapter{
-		provider:   p,
-		authObj:    authObj,
-		executor:   exec.NewCodexExecutor(),
-		tokenCache: &TokenCache{},
+		provider:    p,
+		authObj:     authObj,
+		executor:    exec.NewCodexExecutor(),
+		tokenCache:  &TokenCache{},
+		oauthClient: oauthClient,
 	}
 
 	// 从配置初始化 token 缓存
@@ -139,7 +146,7 @@ func (a *CLIProxyAPICodexAdapter) getAccessToken(ctx context.Context) (string, e
 	}
 
 	// 刷新 token
-	tokenResp, err := refreshAccessToken(ctx, cfgRefreshToken)
+	tokenResp, err := refreshAccessToken(ctx, a.oauthClient, cfgRefreshToken)
 	if err != nil {
 		// 刷新失败时，如果有旧 token 就兜底使用
 		if cfgAccessToken != "" {
@@ -266,8 +273,6 @@ func (a *CLIProxyAPICodexAdapter) executeNonStream(c *flow.Ctx, w http.ResponseW
 
 		// Extract and send token usage metrics
 		if metrics := usage.ExtractFromResponse(string(resp.Payload)); metrics != nil {
-			// Adjust for Codex: input_tokens includes cached_tokens
-			metrics = usage.AdjustForClientType(metrics, domain.ClientTypeCodex)
 			eventChan.SendMetrics(&domain.AdapterMetrics{
 				InputTokens:  metrics.InputTokens,
 				OutputTokens: metrics.OutputTokens,
@@ -346,8 +351,6 @@ func (a *CLIProxyAPICodexAdapter) executeStream(c *flow.Ctx, w http.ResponseWrit
 
 		// Extract and send token usage metrics
 		if metrics := usage.ExtractFromStreamContent(sseBuffer.String()); metrics != nil {
-			// Adjust for Codex: input_tokens includes cached_tokens
-			metrics = usage.AdjustForClientType(metrics, domain.ClientTypeCodex)
 			eventChan.SendMetrics(&domain.AdapterMetrics{
 				InputTokens:  metrics.InputTokens,
 				OutputTokens: metrics.OutputTokens,
@@ -416,7 +419,7 @@ const (
 )
 
 // refreshAccessToken refreshes the access token using a refresh token
-func refreshAccessToken(ctx context.Context, refreshToken string) (*tokenResponse, error) {
+func refreshAccessToken(ctx context.Context, client *http.Client, refreshToken string) (*tokenResponse, error) {
 	data := url.Values{}
 	data.Set("grant_type", "refresh_token")
 	data.Set("client_id", oauthClientID)
@@ -430,7 +433,6 @@ func refreshAccessToken(ctx context.Context, refreshToken string) (*tokenRespons
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("token refresh request failed: %w", err)
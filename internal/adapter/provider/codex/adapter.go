@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net"
 	"net/http"
 	"strings"
 	"sync"
@@ -58,6 +57,7 @@ type CodexAdapter struct {
 	tokenCache     *TokenCache
 	tokenMu        sync.RWMutex
 	httpClient     *http.Client
+	oauthClient    *http.Client // token refresh
 	providerUpdate ProviderUpdateFunc
 }
 
@@ -78,10 +78,20 @@ func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
 		return cliproxyapi.NewAdapter(p)
 	}
 
+	httpClient, err := newUpstreamHTTPClient(p)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
+	oauthClient, err := provider.NewOAuthHTTPClient(p.Config.Network)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
+
 	adapter := &CodexAdapter{
-		provider:   p,
-		tokenCache: &TokenCache{},
-		httpClient: newUpstreamHTTPClient(),
+		provider:    p,
+		tokenCache:  &TokenCache{},
+		httpClient:  httpClient,
+		oauthClient: oauthClient,
 	}
 
 	// Initialize token cache from persisted config if available
@@ -269,7 +279,7 @@ func (a *CodexAdapter) getAccessToken(ctx context.Context) (string, error) {
 	}
 
 	// Refresh token
-	tokenResp, err := RefreshAccessToken(ctx, config.RefreshToken)
+	tokenResp, err := RefreshAccessToken(ctx, a.oauthClient, config.RefreshToken)
 	if err != nil {
 		if strings.TrimSpace(config.AccessToken) != "" {
 			return config.AccessToken, nil
@@ -578,26 +588,8 @@ func applyCodexRequestTuning(c *flow.Ctx, body []byte) (string, []byte) {
 	return cacheID, body
 }
 
-func newUpstreamHTTPClient() *http.Client {
-	dialer := &net.Dialer{
-		Timeout:   20 * time.Second,
-		KeepAlive: 60 * time.Second,
-	}
-
-	transport := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConnsPerHost:   16,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   20 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-	}
-
-	return &http.Client{
-		Transport: transport,
-		Timeout:   600 * time.Second,
-	}
+func newUpstreamHTTPClient(p *domain.Provider) (*http.Client, error) {
+	return provider.NewHTTPClient(p.Config.Network, provider.DefaultTransportOptions())
 }
 
 func flattenHeaders(h http.Header) map[string]string {
@@ -144,7 +144,7 @@ func GetAuthURL(state string, pkce *PKCEChallenge) string {
 }
 
 // ExchangeCodeForTokens exchanges the authorization code for tokens
-func ExchangeCodeForTokens(ctx context.Context, code, redirectURI, codeVerifier string) (*TokenResponse, error) {
+func ExchangeCodeForTokens(ctx context.Context, client *http.Client, code, redirectURI, codeVerifier string) (*TokenResponse, error) {
 	data := url.Values{}
 	data.Set("grant_type", "authorization_code")
 	data.Set("client_id", OAuthClientID)
@@ -158,7 +158,6 @@ func ExchangeCodeForTokens(ctx context.Context, code, redirectURI, codeVerifier
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("token exchange request failed: %w", err)
@@ -183,7 +182,7 @@ func ExchangeCodeForTokens(ctx context.Context, code, redirectURI, codeVerifier
 }
 
 // RefreshAccessToken refreshes the access token using a refresh token
-func RefreshAccessToken(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+func RefreshAccessToken(ctx context.Context, client *http.Client, refreshToken string) (*TokenResponse, error) {
 	data := url.Values{}
 	data.Set("grant_type", "refresh_token")
 	data.Set("client_id", OAuthClientID)
@@ -197,7 +196,6 @@ func RefreshAccessToken(ctx context.Context, refreshToken string) (*TokenRespons
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("token refresh request failed: %w", err)
@@ -528,7 +526,7 @@ func normalizeUsageResponse(raw *codexUsageAPIResponse) *CodexUsageResponse {
 }
 
 // FetchUsage fetches usage/quota information from Codex API
-func FetchUsage(ctx context.Context, accessToken, accountID string) (*CodexUsageResponse, error) {
+func FetchUsage(ctx context.Context, client *http.Client, accessToken, accountID string) (*CodexUsageResponse, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", CodexUsageURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -541,7 +539,6 @@ func FetchUsage(ctx context.Context, accessToken, accountID string) (*CodexUsage
 		req.Header.Set("Chatgpt-Account-Id", accountID)
 	}
 
-	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("usage request failed: %w", err)
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"net/http"
 	"sync"
 	"time"
 
@@ -42,14 +43,14 @@ type CodexQuotaResponse struct {
 }
 
 // ValidateRefreshToken validates a refresh token and retrieves user info
-func ValidateRefreshToken(ctx context.Context, refreshToken string) (*CodexTokenValidationResult, error) {
+func ValidateRefreshToken(ctx context.Context, client *http.Client, refreshToken string) (*CodexTokenValidationResult, error) {
 	result := &CodexTokenValidationResult{
 		Valid:        false,
 		RefreshToken: refreshToken,
 	}
 
 	// 1. Refresh the token to get access token and ID token
-	tokenResp, err := RefreshAccessToken(ctx, refreshToken)
+	tokenResp, err := RefreshAccessToken(ctx, client, refreshToken)
 	if err != nil {
 		result.Error = fmt.Sprintf("Token refresh failed: %v", err)
 		return result, nil
@@ -0,0 +1,434 @@
+package copilot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/flow"
+	"github.com/awsl-project/maxx/internal/usage"
+	"github.com/google/uuid"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+func init() {
+	provider.RegisterAdapterFactory("copilot", NewAdapter)
+}
+
+// CopilotAdapter calls the GitHub Copilot Chat Completions API. Copilot serves OpenAI,
+// Claude and Gemini models through the same endpoint, so only OpenAI clients are
+// native; Claude/Gemini clients are converted by the executor.
+type CopilotAdapter struct {
+	provider       *domain.Provider
+	httpClient     *http.Client
+	tokenMu        sync.Mutex
+	token          *CopilotToken // nil until exchanged
+	providerUpdate func(*domain.Provider) error
+}
+
+// NewAdapter creates a new Copilot adapter
+func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
+	if p.Config == nil || p.Config.Copilot == nil {
+		return nil, fmt.Errorf("provider %s missing copilot config", p.Name)
+	}
+	config := p.Config.Copilot
+	if config.GitHubToken == "" {
+		return nil, fmt.Errorf("provider %s missing github token", p.Name)
+	}
+
+	opts := provider.DefaultTransportOptions()
+	opts.Timeout = 10 * time.Minute
+	httpClient, err := provider.NewHTTPClient(p.Config.Network, opts)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
+
+	adapter := &CopilotAdapter{provider: p, httpClient: httpClient}
+
+	// Initialize token cache from persisted config if available
+	if config.CopilotToken != "" && config.ExpiresAt != "" {
+		if expiresAt, err := time.Parse(time.RFC3339, config.ExpiresAt); err == nil && time.Now().Before(expiresAt) {
+			adapter.token = &CopilotToken{
+				Token:       config.CopilotToken,
+				ExpiresAt:   expiresAt,
+				APIEndpoint: config.APIEndpoint,
+			}
+		}
+	}
+	return adapter, nil
+}
+
+// SetProviderUpdateFunc sets the callback for persisting provider updates
+func (a *CopilotAdapter) SetProviderUpdateFunc(fn func(*domain.Provider) error) {
+	a.providerUpdate = fn
+}
+
+// SupportedClientTypes returns the list of client types this adapter natively supports
+func (a *CopilotAdapter) SupportedClientTypes() []domain.ClientType {
+	return []domain.ClientType{domain.ClientTypeOpenAI}
+}
+
+// Execute performs the proxy request to the Copilot API
+func (a *CopilotAdapter) Execute(c *flow.Ctx, p *domain.Provider) error {
+	config := a.provider.Config.Copilot
+	clientType := flow.GetClientType(c)
+	stream := flow.GetIsStream(c)
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+	if clientType != domain.ClientTypeOpenAI {
+		return domain.NewProxyErrorWithMessage(domain.ErrFormatConversion, false,
+			fmt.Sprintf("copilot does not support client type %s", clientType))
+	}
+
+	model := flow.GetMappedModel(c)
+	if model == "" {
+		model = flow.GetRequestModel(c)
+	}
+	if mapped, ok := config.ModelMapping[model]; ok && mapped != "" {
+		model = mapped
+	}
+	model = normalizeModel(model)
+	if attempt := flow.GetUpstreamAttempt(c); attempt != nil {
+		attempt.MappedModel = model
+	}
+
+	body, err := sjson.SetBytes(flow.GetRequestBody(c), "model", model)
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(err, false, "failed to update model in body")
+	}
+	// 流式请求需显式开启 usage 以便计费
+	if stream && !gjson.GetBytes(body, "stream_options.include_usage").Exists() {
+		if body, err = sjson.SetBytes(body, "stream_options.include_usage", true); err != nil {
+			return domain.NewProxyErrorWithMessage(err, false, "failed to set stream options")
+		}
+	}
+
+	token, err := a.getToken(ctx, false)
+	if err != nil {
+		return newTokenError(err)
+	}
+
+	eventChan := flow.GetEventChan(c)
+	resp, err := a.doRequest(ctx, eventChan, token, body, stream)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Handle 401 (token expired) - exchange a new token and retry once
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		if token, err = a.getToken(ctx, true); err != nil {
+			return newTokenError(err)
+		}
+		if resp, err = a.doRequest(ctx, eventChan, token, body, stream); err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+	}
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    string(respBody),
+		})
+		return newUpstreamError(resp, respBody)
+	}
+
+	if stream {
+		return a.handleStreamResponse(c, resp)
+	}
+	return a.handleNonStreamResponse(c, resp)
+}
+
+// doRequest sends the chat completion request with Copilot headers
+func (a *CopilotAdapter) doRequest(ctx context.Context, eventChan domain.AdapterEventChan, token *CopilotToken, body []byte, stream bool) (*http.Response, error) {
+	upstreamURL := token.APIEndpoint + "/chat/completions"
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, domain.NewProxyErrorWithMessage(err, true, "failed to create upstream request")
+	}
+	applyCopilotHeaders(upstreamReq, token.Token, body, stream)
+
+	eventChan.SendRequestInfo(&domain.RequestInfo{
+		Method:  upstreamReq.Method,
+		URL:     upstreamURL,
+		Headers: flattenHeaders(upstreamReq.Header),
+		Body:    string(body),
+	})
+
+	resp, err := a.httpClient.Do(upstreamReq)
+	if err != nil {
+		proxyErr := domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to connect to upstream")
+		proxyErr.IsNetworkError = true
+		return nil, proxyErr
+	}
+	return resp, nil
+}
+
+// getToken returns a cached Copilot token, exchanging a new one when expired or forced
+func (a *CopilotAdapter) getToken(ctx context.Context, force bool) (*CopilotToken, error) {
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
+
+	if !force && a.token != nil && time.Now().Add(60*time.Second).Before(a.token.ExpiresAt) {
+		return a.token, nil
+	}
+
+	config := a.provider.Config.Copilot
+	token, err := ExchangeCopilotToken(ctx, a.httpClient, config.GitHubToken)
+	if err != nil {
+		return nil, err
+	}
+	a.token = token
+
+	// Persist token to database if update function is set
+	if a.providerUpdate != nil {
+		config.CopilotToken = token.Token
+		config.ExpiresAt = token.ExpiresAt.Format(time.RFC3339)
+		config.APIEndpoint = token.APIEndpoint
+		// Best-effort: token already works in memory, log if DB update fails
+		if err := a.providerUpdate(a.provider); err != nil {
+			log.Printf("[Copilot] failed to persist refreshed token: %v", err)
+		}
+	}
+	return token, nil
+}
+
+func (a *CopilotAdapter) handleNonStreamResponse(c *flow.Ctx, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to read upstream response")
+	}
+
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    string(body),
+	})
+	if metrics := usage.ExtractFromResponse(string(body)); metrics != nil {
+		eventChan.SendMetrics(toAdapterMetrics(metrics))
+	}
+	eventChan.SendResponseModel(gjson.GetBytes(body, "model").String())
+
+	c.Writer.Header().Set("Content-Type", "application/json")
+	c.Writer.WriteHeader(http.StatusOK)
+	_, _ = c.Writer.Write(body)
+	return nil
+}
+
+// handleStreamResponse passes the SSE stream through unchanged
+func (a *CopilotAdapter) handleStreamResponse(c *flow.Ctx, resp *http.Response) error {
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    "[streaming]",
+	})
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, false, "streaming not supported")
+	}
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	var sseBuffer strings.Builder
+	sendFinalEvents := func() {
+		if sseBuffer.Len() == 0 {
+			return
+		}
+		content := sseBuffer.String()
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    content,
+		})
+		if metrics := usage.ExtractFromStreamContent(content); metrics != nil {
+			eventChan.SendMetrics(toAdapterMetrics(metrics))
+		}
+		eventChan.SendResponseModel(extractStreamModel(content))
+	}
+
+	headerWritten := false
+	buf := make([]byte, 32*1024)
+	for {
+		select {
+		case <-ctx.Done():
+			sendFinalEvents()
+			return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+		default:
+		}
+
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if !headerWritten {
+				c.Writer.WriteHeader(http.StatusOK)
+				headerWritten = true
+				eventChan.SendFirstToken(time.Now().UnixMilli())
+			}
+			sseBuffer.Write(buf[:n])
+			if _, writeErr := c.Writer.Write(buf[:n]); writeErr != nil {
+				sendFinalEvents()
+				return domain.NewProxyErrorWithMessage(writeErr, false, "client disconnected")
+			}
+			flusher.Flush()
+		}
+
+		if readErr != nil {
+			sendFinalEvents()
+			if readErr == io.EOF {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+			}
+			return domain.NewProxyErrorWithMessage(readErr, false, "failed to read upstream stream")
+		}
+	}
+}
+
+// applyCopilotHeaders sets the editor identification headers required by the Copilot API
+func applyCopilotHeaders(req *http.Request, token string, body []byte, stream bool) {
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	if stream {
+		req.Header.Set("Accept", "text/event-stream")
+	} else {
+		req.Header.Set("Accept", "application/json")
+	}
+	req.Header.Set("Copilot-Integration-Id", CopilotIntegrationID)
+	req.Header.Set("Editor-Version", CopilotEditorVersion)
+	req.Header.Set("Editor-Plugin-Version", CopilotEditorPluginVersion)
+	req.Header.Set("User-Agent", CopilotUserAgent)
+	req.Header.Set("Openai-Intent", "conversation-panel")
+	req.Header.Set("X-Github-Api-Version", CopilotAPIVersion)
+	req.Header.Set("X-Request-Id", uuid.NewString())
+
+	// 含 assistant/tool 消息的请求视为 agent 续写，不计入高级请求额度
+	initiator := "user"
+	hasImage := false
+	for _, msg := range gjson.GetBytes(body, "messages").Array() {
+		switch msg.Get("role").String() {
+		case "assistant", "tool":
+			initiator = "agent"
+		}
+		for _, part := range msg.Get("content").Array() {
+			if part.Get("type").String() == "image_url" {
+				hasImage = true
+			}
+		}
+	}
+	req.Header.Set("X-Initiator", initiator)
+	if hasImage {
+		req.Header.Set("Copilot-Vision-Request", "true")
+	}
+}
+
+var (
+	claudeModelSuffix  = regexp.MustCompile(`-(\d{8}|latest)$`)
+	claudeVersionDigit = regexp.MustCompile(`(\d)-(\d)\b`)
+)
+
+// normalizeModel converts Anthropic model IDs to the names Copilot exposes,
+// e.g. claude-sonnet-4-5-20250929 → claude-sonnet-4.5, claude-3-7-sonnet-latest → claude-3.7-sonnet
+func normalizeModel(model string) string {
+	if !strings.HasPrefix(model, "claude-") {
+		return model
+	}
+	model = claudeModelSuffix.ReplaceAllString(model, "")
+	return claudeVersionDigit.ReplaceAllString(model, "$1.$2")
+}
+
+// extractStreamModel returns the model of the last chunk that carries one
+func extractStreamModel(content string) string {
+	var model string
+	for _, line := range strings.Split(content, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		if m := gjson.Get(data, "model").String(); m != "" {
+			model = m
+		}
+	}
+	return model
+}
+
+// newTokenError builds the ProxyError for a failed Copilot token exchange
+func newTokenError(err error) *domain.ProxyError {
+	// 无 Copilot 订阅不可重试
+	return domain.NewProxyErrorWithMessage(err, !errors.Is(err, ErrCopilotForbidden), "failed to get copilot token")
+}
+
+// newUpstreamError builds the ProxyError for a Copilot error response
+func newUpstreamError(resp *http.Response, body []byte) *domain.ProxyError {
+	message := gjson.GetBytes(body, "error.message").String()
+	if message == "" {
+		message = string(body)
+	}
+	proxyErr := domain.NewProxyErrorWithMessage(
+		fmt.Errorf("upstream error: %s", message),
+		isRetryableStatusCode(resp.StatusCode),
+		fmt.Sprintf("upstream returned status %d", resp.StatusCode),
+	)
+	proxyErr.HTTPStatusCode = resp.StatusCode
+	proxyErr.IsServerError = resp.StatusCode >= 500 && resp.StatusCode < 600
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			proxyErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	return proxyErr
+}
+
+func isRetryableStatusCode(status int) bool {
+	return status == http.StatusTooManyRequests ||
+		status == http.StatusRequestTimeout ||
+		status >= 500
+}
+
+func toAdapterMetrics(m *usage.Metrics) *domain.AdapterMetrics {
+	return &domain.AdapterMetrics{
+		InputTokens:          m.InputTokens,
+		OutputTokens:         m.OutputTokens,
+		CacheReadCount:       m.CacheReadCount,
+		CacheCreationCount:   m.CacheCreationCount,
+		Cache5mCreationCount: m.Cache5mCreationCount,
+		Cache1hCreationCount: m.Cache1hCreationCount,
+	}
+}
+
+func flattenHeaders(h http.Header) map[string]string {
+	result := make(map[string]string)
+	for k, v := range h {
+		if len(v) > 0 {
+			result[k] = v[0]
+		}
+	}
+	return result
+}
@@ -0,0 +1,243 @@
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/event"
+)
+
+// rewriteTransport sends every request to the test server, keeping the original path
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newGitHubServer(t *testing.T) (*httptest.Server, *http.Client) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login/device/code":
+			_ = r.ParseForm()
+			if r.Form.Get("client_id") != GitHubClientID {
+				t.Errorf("unexpected client id %q", r.Form.Get("client_id"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"device_code": "dev-code", "user_code": "ABCD-1234",
+				"verification_uri": "https://github.com/login/device", "expires_in": 60, "interval": 1,
+			})
+		case "/login/oauth/access_token":
+			_ = r.ParseForm()
+			if r.Form.Get("grant_type") != deviceGrantType || r.Form.Get("device_code") != "dev-code" {
+				t.Errorf("unexpected token request %v", r.Form)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "ghu_token", "token_type": "bearer"})
+		case "/user":
+			_ = json.NewEncoder(w).Encode(map[string]any{"login": "octocat", "name": "The Octocat"})
+		case "/copilot_internal/v2/token":
+			if r.Header.Get("Authorization") != "token ghu_token" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"token": "tid=copilot", "expires_at": time.Now().Add(30 * time.Minute).Unix(), "refresh_in": 1500,
+				"endpoints": map[string]string{"api": "https://api.individual.githubcopilot.com/"},
+			})
+		case "/copilot_internal/user":
+			_, _ = w.Write([]byte(`{
+				"login": "octocat", "copilot_plan": "individual", "quota_reset_date": "2026-11-01",
+				"quota_snapshots": {
+					"chat": {"entitlement": 0, "remaining": 0, "percent_remaining": 100, "unlimited": true},
+					"premium_interactions": {"entitlement": 300, "remaining": 120.5, "percent_remaining": 40.2, "overage_permitted": false}
+				}
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	target, _ := url.Parse(server.URL)
+	return server, &http.Client{Transport: &rewriteTransport{target: target}}
+}
+
+func TestNormalizeModel(t *testing.T) {
+	cases := map[string]string{
+		"claude-sonnet-4-5-20250929": "claude-sonnet-4.5",
+		"claude-3-7-sonnet-latest":   "claude-3.7-sonnet",
+		"claude-sonnet-4-20250514":   "claude-sonnet-4",
+		"claude-opus-4.1":            "claude-opus-4.1",
+		"gemini-2.5-pro":             "gemini-2.5-pro",
+		"gpt-4.1":                    "gpt-4.1",
+	}
+	for in, want := range cases {
+		if got := normalizeModel(in); got != want {
+			t.Errorf("normalizeModel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestApplyCopilotHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/chat/completions", nil)
+	applyCopilotHeaders(req, "tid=copilot", []byte(`{"messages":[{"role":"user","content":"hi"}]}`), true)
+	if req.Header.Get("Authorization") != "Bearer tid=copilot" || req.Header.Get("Accept") != "text/event-stream" {
+		t.Errorf("unexpected headers %v", req.Header)
+	}
+	if req.Header.Get("X-Initiator") != "user" || req.Header.Get("Copilot-Vision-Request") != "" {
+		t.Errorf("plain user request: %v", req.Header)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/chat/completions", nil)
+	applyCopilotHeaders(req, "tid=copilot", []byte(`{"messages":[
+		{"role":"user","content":[{"type":"image_url","image_url":{"url":"data:image/png;base64,AA=="}}]},
+		{"role":"assistant","content":"ok"}
+	]}`), false)
+	if req.Header.Get("X-Initiator") != "agent" || req.Header.Get("Copilot-Vision-Request") != "true" {
+		t.Errorf("agent/vision request: %v", req.Header)
+	}
+}
+
+func TestValidateGitHubToken(t *testing.T) {
+	server, client := newGitHubServer(t)
+	defer server.Close()
+
+	result, err := ValidateGitHubToken(context.Background(), client, "ghu_token")
+	if err != nil || !result.Valid {
+		t.Fatalf("validate = %+v, %v", result, err)
+	}
+	if result.Username != "octocat" || result.Plan != "individual" || result.CopilotToken != "tid=copilot" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.APIEndpoint != "https://api.individual.githubcopilot.com" {
+		t.Errorf("endpoint should drop the trailing slash: %q", result.APIEndpoint)
+	}
+
+	result, _ = ValidateGitHubToken(context.Background(), client, "ghu_other")
+	if result.Valid {
+		t.Error("account without copilot should be invalid")
+	}
+	if _, err := ExchangeCopilotToken(context.Background(), client, "ghu_other"); !errors.Is(err, ErrCopilotForbidden) {
+		t.Errorf("expected ErrCopilotForbidden, got %v", err)
+	}
+}
+
+func TestFetchUsage(t *testing.T) {
+	server, client := newGitHubServer(t)
+	defer server.Close()
+
+	usage, err := FetchUsage(context.Background(), client, "ghu_token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Plan != "individual" || usage.ResetDate != "2026-11-01" {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	if usage.Chat == nil || !usage.Chat.Unlimited || usage.Completions != nil {
+		t.Errorf("unexpected chat/completions %+v %+v", usage.Chat, usage.Completions)
+	}
+	if p := usage.PremiumInteractions; p == nil || p.Entitlement != 300 || p.Remaining != 120 || p.PercentRemaining != 40.2 {
+		t.Errorf("unexpected premium interactions %+v", p)
+	}
+}
+
+func TestGetTokenCachesAndPersists(t *testing.T) {
+	server, client := newGitHubServer(t)
+	defer server.Close()
+
+	p := &domain.Provider{Name: "copilot", Config: &domain.ProviderConfig{
+		Copilot: &domain.ProviderConfigCopilot{GitHubToken: "ghu_token"},
+	}}
+	a, err := NewAdapter(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	adapter := a.(*CopilotAdapter)
+	adapter.httpClient = client
+	updates := 0
+	adapter.SetProviderUpdateFunc(func(*domain.Provider) error {
+		updates++
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := adapter.getToken(context.Background(), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if updates != 1 {
+		t.Errorf("token should be cached, got %d updates", updates)
+	}
+	if p.Config.Copilot.CopilotToken != "tid=copilot" || p.Config.Copilot.ExpiresAt == "" {
+		t.Errorf("token should be persisted: %+v", p.Config.Copilot)
+	}
+
+	// 重建 adapter 时复用持久化的 token
+	rebuilt, _ := NewAdapter(p)
+	if token := rebuilt.(*CopilotAdapter).token; token == nil || token.APIEndpoint != "https://api.individual.githubcopilot.com" {
+		t.Errorf("persisted token should be restored: %+v", token)
+	}
+}
+
+type recordingBroadcaster struct {
+	event.NopBroadcaster
+	mu       sync.Mutex
+	messages []any
+}
+
+func (b *recordingBroadcaster) BroadcastMessage(messageType string, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if messageType == "copilot_device_result" {
+		b.messages = append(b.messages, data)
+	}
+}
+
+func (b *recordingBroadcaster) results() []any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]any(nil), b.messages...)
+}
+
+func TestDeviceFlowManager(t *testing.T) {
+	server, client := newGitHubServer(t)
+	defer server.Close()
+
+	broadcaster := &recordingBroadcaster{}
+	manager := NewDeviceFlowManager(broadcaster)
+	manager.httpClient = client
+
+	start, err := manager.Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start.UserCode != "ABCD-1234" || start.VerificationURI != "https://github.com/login/device" {
+		t.Errorf("unexpected start result %+v", start)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(broadcaster.results()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	results := broadcaster.results()
+	if len(results) != 1 {
+		t.Fatalf("expected one result, got %d", len(results))
+	}
+	result := results[0].(*DeviceFlowResult)
+	if !result.Success || result.SessionID != start.SessionID || result.GitHubToken != "ghu_token" || result.Username != "octocat" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if manager.Cancel(start.SessionID) {
+		t.Error("completed session should be removed")
+	}
+}
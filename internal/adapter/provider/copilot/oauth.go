@@ -0,0 +1,336 @@
+package copilot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitHub device flow and Copilot API constants
+const (
+	// GitHub OAuth App Client ID used by the official Copilot editor plugins
+	GitHubClientID = "Iv1.b507a08c87ecfe98"
+	GitHubScope    = "read:user"
+
+	GitHubDeviceCodeURL  = "https://github.com/login/device/code"
+	GitHubAccessTokenURL = "https://github.com/login/oauth/access_token"
+	GitHubUserURL        = "https://api.github.com/user"
+
+	// Copilot token exchange and usage (copilot_internal)
+	CopilotTokenURL = "https://api.github.com/copilot_internal/v2/token"
+	CopilotUserURL  = "https://api.github.com/copilot_internal/user"
+
+	// Default Copilot API endpoint when the token does not carry one
+	CopilotDefaultAPIEndpoint = "https://api.githubcopilot.com"
+
+	// Editor identification headers expected by the Copilot API
+	CopilotEditorVersion       = "vscode/1.99.3"
+	CopilotEditorPluginVersion = "copilot-chat/0.26.7"
+	CopilotUserAgent           = "GitHubCopilotChat/0.26.7"
+	CopilotIntegrationID       = "vscode-chat"
+	CopilotAPIVersion          = "2025-04-01"
+
+	deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+// Device flow polling errors (RFC 8628 section 3.5)
+var (
+	ErrAuthorizationPending = errors.New("authorization pending")
+	ErrSlowDown             = errors.New("slow down")
+	ErrDeviceCodeExpired    = errors.New("device code expired")
+	ErrAccessDenied         = errors.New("access denied by user")
+)
+
+// ErrCopilotForbidden is returned when the GitHub account has no Copilot access
+var ErrCopilotForbidden = errors.New("github account has no copilot access")
+
+// DeviceCodeResponse is the response of POST /login/device/code
+type DeviceCodeResponse struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// accessTokenResponse is the response of POST /login/oauth/access_token
+type accessTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// GitHubUser is the subset of GET /user used to identify the account
+type GitHubUser struct {
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// CopilotToken is a short-lived Copilot API token exchanged from a GitHub token
+type CopilotToken struct {
+	Token       string
+	ExpiresAt   time.Time
+	APIEndpoint string
+}
+
+// copilotTokenResponse is the response of GET /copilot_internal/v2/token
+type copilotTokenResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at"`
+	RefreshIn int64  `json:"refresh_in"`
+	Endpoints struct {
+		API string `json:"api"`
+	} `json:"endpoints"`
+}
+
+// RequestDeviceCode starts the GitHub device flow
+func RequestDeviceCode(ctx context.Context, client *http.Client) (*DeviceCodeResponse, error) {
+	form := url.Values{}
+	form.Set("client_id", GitHubClientID)
+	form.Set("scope", GitHubScope)
+
+	body, err := postForm(ctx, client, GitHubDeviceCodeURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("device code request failed: %w", err)
+	}
+
+	var resp DeviceCodeResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse device code response: %w", err)
+	}
+	if resp.DeviceCode == "" || resp.UserCode == "" {
+		return nil, fmt.Errorf("invalid device code response: %s", string(body))
+	}
+	if resp.Interval <= 0 {
+		resp.Interval = 5
+	}
+	return &resp, nil
+}
+
+// PollAccessToken polls once for the GitHub access token of a device code.
+// Returns ErrAuthorizationPending / ErrSlowDown while the user has not finished authorizing.
+func PollAccessToken(ctx context.Context, client *http.Client, deviceCode string) (string, error) {
+	form := url.Values{}
+	form.Set("client_id", GitHubClientID)
+	form.Set("device_code", deviceCode)
+	form.Set("grant_type", deviceGrantType)
+
+	body, err := postForm(ctx, client, GitHubAccessTokenURL, form)
+	if err != nil {
+		return "", fmt.Errorf("access token request failed: %w", err)
+	}
+
+	var resp accessTokenResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse access token response: %w", err)
+	}
+
+	switch resp.Error {
+	case "":
+		if resp.AccessToken == "" {
+			return "", fmt.Errorf("empty access token in response")
+		}
+		return resp.AccessToken, nil
+	case "authorization_pending":
+		return "", ErrAuthorizationPending
+	case "slow_down":
+		return "", ErrSlowDown
+	case "expired_token":
+		return "", ErrDeviceCodeExpired
+	case "access_denied":
+		return "", ErrAccessDenied
+	default:
+		if resp.ErrorDescription != "" {
+			return "", fmt.Errorf("%s: %s", resp.Error, resp.ErrorDescription)
+		}
+		return "", errors.New(resp.Error)
+	}
+}
+
+// FetchGitHubUser fetches the GitHub account of a GitHub token
+func FetchGitHubUser(ctx context.Context, client *http.Client, githubToken string) (*GitHubUser, error) {
+	body, err := getWithGitHubToken(ctx, client, GitHubUserURL, githubToken)
+	if err != nil {
+		return nil, fmt.Errorf("user request failed: %w", err)
+	}
+
+	var user GitHubUser
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, fmt.Errorf("failed to parse user response: %w", err)
+	}
+	return &user, nil
+}
+
+// ExchangeCopilotToken exchanges a GitHub token for a Copilot API token
+func ExchangeCopilotToken(ctx context.Context, client *http.Client, githubToken string) (*CopilotToken, error) {
+	body, err := getWithGitHubToken(ctx, client, CopilotTokenURL, githubToken)
+	if err != nil {
+		return nil, fmt.Errorf("copilot token exchange failed: %w", err)
+	}
+
+	var resp copilotTokenResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse copilot token response: %w", err)
+	}
+	if resp.Token == "" {
+		return nil, fmt.Errorf("empty copilot token in response")
+	}
+
+	token := &CopilotToken{
+		Token:       resp.Token,
+		APIEndpoint: strings.TrimRight(resp.Endpoints.API, "/"),
+	}
+	// refresh_in 比 expires_at 更保守，优先使用
+	switch {
+	case resp.RefreshIn > 0:
+		token.ExpiresAt = time.Now().Add(time.Duration(resp.RefreshIn) * time.Second)
+	case resp.ExpiresAt > 0:
+		token.ExpiresAt = time.Unix(resp.ExpiresAt, 0)
+	default:
+		token.ExpiresAt = time.Now().Add(25 * time.Minute)
+	}
+	if token.APIEndpoint == "" {
+		token.APIEndpoint = CopilotDefaultAPIEndpoint
+	}
+	return token, nil
+}
+
+// ============================================================================
+// Usage/Quota types and functions
+// ============================================================================
+
+// CopilotQuotaSnapshot is one quota bucket of the usage API
+type CopilotQuotaSnapshot struct {
+	Entitlement      int64   `json:"entitlement"`
+	Remaining        int64   `json:"remaining"`
+	PercentRemaining float64 `json:"percentRemaining"`
+	Unlimited        bool    `json:"unlimited"`
+	OverageCount     int64   `json:"overageCount"`
+	OveragePermitted bool    `json:"overagePermitted"`
+}
+
+// CopilotUsageResponse represents the usage API response
+type CopilotUsageResponse struct {
+	Login               string                `json:"login,omitempty"`
+	Plan                string                `json:"plan,omitempty"`
+	ResetDate           string                `json:"resetDate,omitempty"`
+	Chat                *CopilotQuotaSnapshot `json:"chat,omitempty"`
+	Completions         *CopilotQuotaSnapshot `json:"completions,omitempty"`
+	PremiumInteractions *CopilotQuotaSnapshot `json:"premiumInteractions,omitempty"`
+}
+
+type copilotSnapshotAPI struct {
+	Entitlement      float64 `json:"entitlement"`
+	Remaining        float64 `json:"remaining"`
+	PercentRemaining float64 `json:"percent_remaining"`
+	Unlimited        bool    `json:"unlimited"`
+	OverageCount     float64 `json:"overage_count"`
+	OveragePermitted bool    `json:"overage_permitted"`
+}
+
+type copilotUsageAPIResponse struct {
+	Login          string `json:"login"`
+	CopilotPlan    string `json:"copilot_plan"`
+	QuotaResetDate string `json:"quota_reset_date"`
+	QuotaSnapshots struct {
+		Chat                *copilotSnapshotAPI `json:"chat"`
+		Completions         *copilotSnapshotAPI `json:"completions"`
+		PremiumInteractions *copilotSnapshotAPI `json:"premium_interactions"`
+	} `json:"quota_snapshots"`
+}
+
+func (s *copilotSnapshotAPI) normalize() *CopilotQuotaSnapshot {
+	if s == nil {
+		return nil
+	}
+	return &CopilotQuotaSnapshot{
+		Entitlement:      int64(s.Entitlement),
+		Remaining:        int64(s.Remaining),
+		PercentRemaining: s.PercentRemaining,
+		Unlimited:        s.Unlimited,
+		OverageCount:     int64(s.OverageCount),
+		OveragePermitted: s.OveragePermitted,
+	}
+}
+
+// FetchUsage fetches usage/quota information of a GitHub token
+func FetchUsage(ctx context.Context, client *http.Client, githubToken string) (*CopilotUsageResponse, error) {
+	body, err := getWithGitHubToken(ctx, client, CopilotUserURL, githubToken)
+	if err != nil {
+		return nil, fmt.Errorf("usage request failed: %w", err)
+	}
+
+	var raw copilotUsageAPIResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse usage response: %w", err)
+	}
+
+	return &CopilotUsageResponse{
+		Login:               raw.Login,
+		Plan:                raw.CopilotPlan,
+		ResetDate:           raw.QuotaResetDate,
+		Chat:                raw.QuotaSnapshots.Chat.normalize(),
+		Completions:         raw.QuotaSnapshots.Completions.normalize(),
+		PremiumInteractions: raw.QuotaSnapshots.PremiumInteractions.normalize(),
+	}, nil
+}
+
+// ============================================================================
+// HTTP helpers
+// ============================================================================
+
+func postForm(ctx context.Context, client *http.Client, endpoint string, form url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", CopilotUserAgent)
+	return doRequest(client, req)
+}
+
+func getWithGitHubToken(ctx context.Context, client *http.Client, endpoint, githubToken string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "token "+githubToken)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Editor-Version", CopilotEditorVersion)
+	req.Header.Set("Editor-Plugin-Version", CopilotEditorPluginVersion)
+	req.Header.Set("User-Agent", CopilotUserAgent)
+	req.Header.Set("X-GitHub-Api-Version", CopilotAPIVersion)
+	return doRequest(client, req)
+}
+
+func doRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound {
+		if strings.Contains(req.URL.Path, "/copilot_internal/") {
+			return nil, fmt.Errorf("%w (status %d): %s", ErrCopilotForbidden, resp.StatusCode, string(body))
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
@@ -0,0 +1,264 @@
+package copilot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/event"
+)
+
+// CopilotTokenValidationResult token validation result
+type CopilotTokenValidationResult struct {
+	Valid        bool   `json:"valid"`
+	Error        string `json:"error,omitempty"`
+	Username     string `json:"username,omitempty"`
+	Name         string `json:"name,omitempty"`
+	AvatarURL    string `json:"avatarURL,omitempty"`
+	Plan         string `json:"plan,omitempty"`
+	GitHubToken  string `json:"githubToken,omitempty"`
+	CopilotToken string `json:"copilotToken,omitempty"`
+	ExpiresAt    string `json:"expiresAt,omitempty"` // RFC3339 format
+	APIEndpoint  string `json:"apiEndpoint,omitempty"`
+}
+
+// CopilotQuotaResponse represents the quota data for batch API response
+// This is the format returned by GET /copilot/providers/quotas
+type CopilotQuotaResponse struct {
+	Username            string                `json:"username"`
+	Plan                string                `json:"plan,omitempty"`
+	ResetDate           string                `json:"resetDate,omitempty"`
+	IsForbidden         bool                  `json:"isForbidden"`
+	LastUpdated         int64                 `json:"lastUpdated"` // Unix timestamp
+	Chat                *CopilotQuotaSnapshot `json:"chat,omitempty"`
+	Completions         *CopilotQuotaSnapshot `json:"completions,omitempty"`
+	PremiumInteractions *CopilotQuotaSnapshot `json:"premiumInteractions,omitempty"`
+}
+
+// ValidateGitHubToken validates a GitHub token: it must identify a user and exchange for a Copilot token
+func ValidateGitHubToken(ctx context.Context, client *http.Client, githubToken string) (*CopilotTokenValidationResult, error) {
+	result := &CopilotTokenValidationResult{
+		Valid:       false,
+		GitHubToken: githubToken,
+	}
+
+	// 1. Identify the GitHub account
+	user, err := FetchGitHubUser(ctx, client, githubToken)
+	if err != nil {
+		result.Error = fmt.Sprintf("GitHub user lookup failed: %v", err)
+		return result, nil
+	}
+	result.Username = user.Login
+	result.Name = user.Name
+	result.AvatarURL = user.AvatarURL
+
+	// 2. Exchange for a Copilot token (fails when the account has no Copilot subscription)
+	token, err := ExchangeCopilotToken(ctx, client, githubToken)
+	if err != nil {
+		result.Error = fmt.Sprintf("Copilot token exchange failed: %v", err)
+		return result, nil
+	}
+	result.CopilotToken = token.Token
+	result.ExpiresAt = token.ExpiresAt.Format(time.RFC3339)
+	result.APIEndpoint = token.APIEndpoint
+
+	// 3. Plan type (best effort)
+	if usage, err := FetchUsage(ctx, client, githubToken); err == nil {
+		result.Plan = usage.Plan
+	}
+
+	result.Valid = true
+	return result, nil
+}
+
+// DeviceSession represents a pending GitHub device flow authorization
+type DeviceSession struct {
+	SessionID       string
+	DeviceCode      string
+	UserCode        string
+	VerificationURI string
+	Interval        time.Duration
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+	cancel          context.CancelFunc
+}
+
+// DeviceFlowStartResult is returned to the client to show the user code
+type DeviceFlowStartResult struct {
+	SessionID       string `json:"sessionId"`
+	UserCode        string `json:"userCode"`
+	VerificationURI string `json:"verificationUri"`
+	ExpiresIn       int    `json:"expiresIn"`
+	Interval        int    `json:"interval"`
+}
+
+// DeviceFlowResult represents the device flow authorization result
+type DeviceFlowResult struct {
+	SessionID    string `json:"sessionId"`
+	Success      bool   `json:"success"`
+	GitHubToken  string `json:"githubToken,omitempty"`
+	CopilotToken string `json:"copilotToken,omitempty"`
+	ExpiresAt    string `json:"expiresAt,omitempty"` // RFC3339 format
+	APIEndpoint  string `json:"apiEndpoint,omitempty"`
+	Username     string `json:"username,omitempty"`
+	Name         string `json:"name,omitempty"`
+	AvatarURL    string `json:"avatarURL,omitempty"`
+	Plan         string `json:"plan,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// DeviceFlowManager manages device flow sessions; unlike the Codex OAuth flow there is no
+// callback, so each session polls GitHub in the background and broadcasts the result
+type DeviceFlowManager struct {
+	sessions    sync.Map          // sessionID -> *DeviceSession
+	broadcaster event.Broadcaster // for pushing device flow results
+	httpClient  *http.Client
+}
+
+// NewDeviceFlowManager creates a new device flow manager
+func NewDeviceFlowManager(broadcaster event.Broadcaster) *DeviceFlowManager {
+	// No provider exists during the device flow; a nil network never fails to build
+	httpClient, _ := provider.NewOAuthHTTPClient(nil)
+	return &DeviceFlowManager{
+		broadcaster: broadcaster,
+		httpClient:  httpClient,
+	}
+}
+
+// Start requests a device code and starts polling for the authorization
+func (m *DeviceFlowManager) Start(ctx context.Context) (*DeviceFlowStartResult, error) {
+	code, err := RequestDeviceCode(ctx, m.httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, err := generateSessionID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session id: %w", err)
+	}
+
+	now := time.Now()
+	session := &DeviceSession{
+		SessionID:       sessionID,
+		DeviceCode:      code.DeviceCode,
+		UserCode:        code.UserCode,
+		VerificationURI: code.VerificationURI,
+		Interval:        time.Duration(code.Interval) * time.Second,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(time.Duration(code.ExpiresIn) * time.Second),
+	}
+
+	// 轮询不能绑定在发起请求的 ctx 上，HTTP 请求返回后仍需继续
+	pollCtx, cancel := context.WithDeadline(context.Background(), session.ExpiresAt)
+	session.cancel = cancel
+	m.sessions.Store(sessionID, session)
+	go m.poll(pollCtx, session)
+
+	return &DeviceFlowStartResult{
+		SessionID:       sessionID,
+		UserCode:        code.UserCode,
+		VerificationURI: code.VerificationURI,
+		ExpiresIn:       code.ExpiresIn,
+		Interval:        code.Interval,
+	}, nil
+}
+
+// Cancel stops polling for a session without broadcasting a result
+func (m *DeviceFlowManager) Cancel(sessionID string) bool {
+	val, ok := m.sessions.LoadAndDelete(sessionID)
+	if !ok {
+		return false
+	}
+	val.(*DeviceSession).cancel()
+	return true
+}
+
+// poll polls GitHub until the user authorizes, denies or the device code expires
+func (m *DeviceFlowManager) poll(ctx context.Context, session *DeviceSession) {
+	defer session.cancel()
+	interval := session.Interval
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				m.complete(session.SessionID, &DeviceFlowResult{Error: ErrDeviceCodeExpired.Error()})
+			}
+			return
+		case <-time.After(interval):
+		}
+
+		githubToken, err := PollAccessToken(ctx, m.httpClient, session.DeviceCode)
+		switch {
+		case err == nil:
+			m.completeWithToken(ctx, session.SessionID, githubToken)
+			return
+		case errors.Is(err, ErrAuthorizationPending):
+			continue
+		case errors.Is(err, ErrSlowDown):
+			interval += 5 * time.Second
+			continue
+		case ctx.Err() != nil:
+			// 取消或过期，下一轮由 ctx.Done() 处理
+			continue
+		default:
+			m.complete(session.SessionID, &DeviceFlowResult{Error: err.Error()})
+			return
+		}
+	}
+}
+
+// completeWithToken validates the GitHub token and broadcasts the account info
+func (m *DeviceFlowManager) completeWithToken(ctx context.Context, sessionID, githubToken string) {
+	validation, err := ValidateGitHubToken(ctx, m.httpClient, githubToken)
+	if err != nil || !validation.Valid {
+		msg := "token validation failed"
+		if err != nil {
+			msg = err.Error()
+		} else if validation.Error != "" {
+			msg = validation.Error
+		}
+		m.complete(sessionID, &DeviceFlowResult{Error: msg})
+		return
+	}
+
+	m.complete(sessionID, &DeviceFlowResult{
+		Success:      true,
+		GitHubToken:  validation.GitHubToken,
+		CopilotToken: validation.CopilotToken,
+		ExpiresAt:    validation.ExpiresAt,
+		APIEndpoint:  validation.APIEndpoint,
+		Username:     validation.Username,
+		Name:         validation.Name,
+		AvatarURL:    validation.AvatarURL,
+		Plan:         validation.Plan,
+	})
+}
+
+// complete removes the session and broadcasts the result
+func (m *DeviceFlowManager) complete(sessionID string, result *DeviceFlowResult) {
+	// Session already cancelled by the client
+	if _, ok := m.sessions.LoadAndDelete(sessionID); !ok {
+		return
+	}
+	result.SessionID = sessionID
+
+	// Broadcast result via WebSocket
+	if m.broadcaster != nil {
+		m.broadcaster.BroadcastMessage("copilot_device_result", result)
+	}
+}
+
+func generateSessionID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -24,15 +24,24 @@ func init() {
 }
 
 type CustomAdapter struct {
-	provider *domain.Provider
+	provider   *domain.Provider
+	httpClient *http.Client
 }
 
 func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
 	if p.Config == nil || p.Config.Custom == nil {
 		return nil, fmt.Errorf("provider %s missing custom config", p.Name)
 	}
+	// Long timeout for LLM requests; the transport is shared across adapters with the same network settings
+	opts := provider.DefaultTransportOptions()
+	opts.Timeout = 10 * time.Minute
+	httpClient, err := provider.NewHTTPClient(p.Config.Network, opts)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
 	return &CustomAdapter{
-		provider: p,
+		provider:   p,
+		httpClient: httpClient,
 	}, nil
 }
 
@@ -143,11 +152,7 @@ func (a *CustomAdapter) Execute(c *flow.Ctx, provider *domain.Provider) error {
 		})
 	}
 
-	// Execute request with reasonable timeout
-	client := &http.Client{
-		Timeout: 10 * time.Minute, // Long timeout for LLM requests
-	}
-	resp, err := client.Do(upstreamReq)
+	resp, err := a.httpClient.Do(upstreamReq)
 	if err != nil {
 		proxyErr := domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to connect to upstream")
 		proxyErr.IsNetworkError = true
@@ -258,8 +263,6 @@ func (a *CustomAdapter) handleNonStreamResponse(c *flow.Ctx, resp *http.Response
 
 	// Extract and send token usage metrics
 	if metrics := usage.ExtractFromResponse(string(body)); metrics != nil {
-		// Adjust for client-specific quirks (e.g., Codex input_tokens includes cached tokens)
-		metrics = usage.AdjustForClientType(metrics, clientType)
 		if eventChan != nil {
 			eventChan.SendMetrics(&domain.AdapterMetrics{
 				InputTokens:          metrics.InputTokens,
@@ -352,8 +355,6 @@ func (a *CustomAdapter) handleStreamResponse(c *flow.Ctx, resp *http.Response, c
 
 			// Extract and send token usage
 			if metrics := usage.ExtractFromStreamContent(sseBuffer.String()); metrics != nil {
-				// Adjust for client-specific quirks (e.g., Codex input_tokens includes cached tokens)
-				metrics = usage.AdjustForClientType(metrics, clientType)
 				eventChan.SendMetrics(&domain.AdapterMetrics{
 					InputTokens:          metrics.InputTokens,
 					OutputTokens:         metrics.OutputTokens,
@@ -6,7 +6,6 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"strings"
 	"sync"
@@ -50,11 +49,15 @@ func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
 	if p.Config == nil || p.Config.Kiro == nil {
 		return nil, fmt.Errorf("provider %s missing kiro config", p.Name)
 	}
+	httpClient, err := newKiroHTTPClient(p)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
 	return &KiroAdapter{
 		provider:   p,
 		tokenCache: &TokenCache{},
 		usageCache: &UsageCache{},
-		httpClient: newKiroHTTPClient(),
+		httpClient: httpClient,
 	}, nil
 }
 
@@ -701,33 +704,27 @@ func isRetryableStatusCode(status int) bool {
 }
 
 // newKiroHTTPClient creates an HTTP client for Kiro/CodeWhisperer API
-// 匹配 kiro2api/utils/client.go:26-52
-func newKiroHTTPClient() *http.Client {
-	return &http.Client{
-		Transport: &http.Transport{
-			// 连接建立配置 (匹配 kiro2api)
-			DialContext: (&net.Dialer{
-				Timeout:   15 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-
-			// TLS配置 (匹配 kiro2api)
-			TLSHandshakeTimeout: 15 * time.Second,
-			TLSClientConfig: &tls.Config{
-				MinVersion: tls.VersionTLS12,
-				MaxVersion: tls.VersionTLS13,
-				CipherSuites: []uint16{
-					tls.TLS_AES_256_GCM_SHA384,
-					tls.TLS_CHACHA20_POLY1305_SHA256,
-					tls.TLS_AES_128_GCM_SHA256,
-				},
+// 匹配 kiro2api/utils/client.go:26-52，代理/CA/超时等由供应商网络设置覆盖
+func newKiroHTTPClient(p *domain.Provider) (*http.Client, error) {
+	return provider.NewHTTPClient(p.Config.Network, provider.TransportOptions{
+		// 连接建立配置 (匹配 kiro2api)
+		ConnectTimeout:      15 * time.Second,
+		KeepAlive:           30 * time.Second,
+		TLSHandshakeTimeout: 15 * time.Second,
+
+		// TLS配置 (匹配 kiro2api)
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			MaxVersion: tls.VersionTLS13,
+			CipherSuites: []uint16{
+				tls.TLS_AES_256_GCM_SHA384,
+				tls.TLS_CHACHA20_POLY1305_SHA256,
+				tls.TLS_AES_128_GCM_SHA256,
 			},
-
-			// HTTP配置 (匹配 kiro2api)
-			ForceAttemptHTTP2:  false,
-			DisableCompression: false,
 		},
+
+		// HTTP配置 (匹配 kiro2api)
+		DisableHTTP2: true,
 		// 注意: kiro2api 不设置整体 Timeout
-	}
+	})
 }
@@ -67,14 +67,14 @@ type UsageLimitsUser struct {
 }
 
 // ValidateSocialToken 验证 Social refresh token
-func ValidateSocialToken(ctx context.Context, refreshToken string) (*KiroTokenValidationResult, error) {
+func ValidateSocialToken(ctx context.Context, client *http.Client, refreshToken string) (*KiroTokenValidationResult, error) {
 	result := &KiroTokenValidationResult{
 		Valid:        false,
 		RefreshToken: refreshToken,
 	}
 
 	// 1. 刷新 token 获取 access token
-	refreshResp, err := refreshSocialToken(ctx, refreshToken)
+	refreshResp, err := refreshSocialToken(ctx, client, refreshToken)
 	if err != nil {
 		result.Error = fmt.Sprintf("Token 刷新失败: %v", err)
 		return result, nil
@@ -85,7 +85,7 @@ func ValidateSocialToken(ctx context.Context, refreshToken string) (*KiroTokenVa
 	result.ProfileArn = refreshResp.ProfileArn
 
 	// 2. 获取配额和用户信息
-	usageResp, err := getUsageLimits(ctx, refreshResp.AccessToken, refreshResp.ProfileArn)
+	usageResp, err := getUsageLimits(ctx, client, refreshResp.AccessToken, refreshResp.ProfileArn)
 	if err != nil {
 		// 检测封禁状态
 		if strings.HasPrefix(err.Error(), "BANNED:") {
@@ -120,7 +120,7 @@ func ValidateSocialToken(ctx context.Context, refreshToken string) (*KiroTokenVa
 }
 
 // refreshSocialToken 刷新 Social token
-func refreshSocialToken(ctx context.Context, refreshToken string) (*SocialRefreshResponse, error) {
+func refreshSocialToken(ctx context.Context, client *http.Client, refreshToken string) (*SocialRefreshResponse, error) {
 	reqBody, err := FastMarshal(RefreshRequest{RefreshToken: refreshToken})
 	if err != nil {
 		return nil, fmt.Errorf("序列化请求失败: %w", err)
@@ -132,7 +132,6 @@ func refreshSocialToken(ctx context.Context, refreshToken string) (*SocialRefres
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("请求失败: %w", err)
@@ -157,7 +156,7 @@ func refreshSocialToken(ctx context.Context, refreshToken string) (*SocialRefres
 }
 
 // getUsageLimits 获取配额信息
-func getUsageLimits(ctx context.Context, accessToken, profileArn string) (*UsageLimitsResponse, error) {
+func getUsageLimits(ctx context.Context, client *http.Client, accessToken, profileArn string) (*UsageLimitsResponse, error) {
 	// 构建 URL
 	baseURL := fmt.Sprintf("https://codewhisperer.%s.amazonaws.com/getUsageLimits", DefaultRegion)
 	params := url.Values{}
@@ -173,7 +172,6 @@ func getUsageLimits(ctx context.Context, accessToken, profileArn string) (*Usage
 	}
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("请求失败: %w", err)
@@ -205,15 +203,15 @@ func getUsageLimits(ctx context.Context, accessToken, profileArn string) (*Usage
 }
 
 // FetchQuota 获取 Kiro 配额（基于 refresh token，匹配 kiro2api 计算逻辑）
-func FetchQuota(ctx context.Context, refreshToken string) (*KiroQuotaData, error) {
+func FetchQuota(ctx context.Context, client *http.Client, refreshToken string) (*KiroQuotaData, error) {
 	// 1. 刷新 token 获取 access token
-	refreshResp, err := refreshSocialToken(ctx, refreshToken)
+	refreshResp, err := refreshSocialToken(ctx, client, refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("token 刷新失败: %w", err)
 	}
 
 	// 2. 获取配额信息
-	usageResp, err := getUsageLimits(ctx, refreshResp.AccessToken, refreshResp.ProfileArn)
+	usageResp, err := getUsageLimits(ctx, client, refreshResp.AccessToken, refreshResp.ProfileArn)
 	if err != nil {
 		// 检测封禁状态
 		if strings.HasPrefix(err.Error(), "BANNED:") {
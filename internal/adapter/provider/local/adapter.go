@@ -0,0 +1,440 @@
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/flow"
+	"github.com/awsl-project/maxx/internal/usage"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+func init() {
+	provider.RegisterAdapterFactory("local", NewAdapter)
+}
+
+// LocalAdapter calls a self-hosted model server (Ollama, llama.cpp, vLLM) with OpenAI Chat Completions;
+// Ollama backends use the native /api/chat API, other client formats are converted by the executor.
+type LocalAdapter struct {
+	provider   *domain.Provider
+	httpClient *http.Client
+}
+
+// NewAdapter creates a new local model adapter
+func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
+	if p.Config == nil || p.Config.Local == nil {
+		return nil, fmt.Errorf("provider %s missing local config", p.Name)
+	}
+	config := p.Config.Local
+	if NormalizeBaseURL(config.BaseURL) == "" {
+		return nil, fmt.Errorf("provider %s missing local baseURL", p.Name)
+	}
+
+	opts := provider.DefaultTransportOptions()
+	// 本地推理首 token 可能较慢，放宽超时
+	opts.Timeout = 30 * time.Minute
+	httpClient, err := provider.NewHTTPClient(p.Config.Network, opts)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
+
+	return &LocalAdapter{provider: p, httpClient: httpClient}, nil
+}
+
+// SupportedClientTypes returns the list of client types this adapter natively supports
+func (a *LocalAdapter) SupportedClientTypes() []domain.ClientType {
+	return []domain.ClientType{domain.ClientTypeOpenAI}
+}
+
+// Execute performs the proxy request to the local model server
+func (a *LocalAdapter) Execute(c *flow.Ctx, p *domain.Provider) error {
+	config := a.provider.Config.Local
+	clientType := flow.GetClientType(c)
+	stream := flow.GetIsStream(c)
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+	if clientType != domain.ClientTypeOpenAI {
+		return domain.NewProxyErrorWithMessage(domain.ErrFormatConversion, false,
+			fmt.Sprintf("local provider does not support client type %s", clientType))
+	}
+
+	model := flow.GetMappedModel(c)
+	if model == "" {
+		model = flow.GetRequestModel(c)
+	}
+	if mapped, ok := config.ModelMapping[model]; ok && mapped != "" {
+		model = mapped
+	}
+	if attempt := flow.GetUpstreamAttempt(c); attempt != nil {
+		attempt.MappedModel = model
+	}
+
+	ollama := config.Backend == domain.LocalBackendOllama
+	baseURL := NormalizeBaseURL(config.BaseURL)
+	body := flow.GetRequestBody(c)
+	var upstreamURL string
+	var err error
+	if ollama {
+		upstreamURL = baseURL + "/api/chat"
+		if body, err = convertToOllamaRequest(body, model, stream); err != nil {
+			return domain.NewProxyErrorWithMessage(domain.ErrFormatConversion, false, "failed to convert request for ollama")
+		}
+	} else {
+		upstreamURL = baseURL + "/v1/chat/completions"
+		if body, err = sjson.SetBytes(body, "model", model); err != nil {
+			return domain.NewProxyErrorWithMessage(err, false, "failed to update model in body")
+		}
+		// 流式请求需显式开启 usage 以便计费
+		if stream && !gjson.GetBytes(body, "stream_options.include_usage").Exists() {
+			if body, err = sjson.SetBytes(body, "stream_options.include_usage", true); err != nil {
+				return domain.NewProxyErrorWithMessage(err, false, "failed to set stream options")
+			}
+		}
+	}
+
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(err, true, "failed to create upstream request")
+	}
+	upstreamReq.Header.Set("Content-Type", "application/json")
+	if config.APIKey != "" {
+		upstreamReq.Header.Set("Authorization", "Bearer "+config.APIKey)
+	}
+
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendRequestInfo(&domain.RequestInfo{
+		Method:  upstreamReq.Method,
+		URL:     upstreamURL,
+		Headers: flattenHeaders(upstreamReq.Header),
+		Body:    string(body),
+	})
+
+	resp, err := a.httpClient.Do(upstreamReq)
+	if err != nil {
+		proxyErr := domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to connect to local model server")
+		proxyErr.IsNetworkError = true
+		return proxyErr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    string(respBody),
+		})
+		return newUpstreamError(resp.StatusCode, respBody)
+	}
+
+	switch {
+	case ollama && stream:
+		return a.handleOllamaStream(c, resp)
+	case ollama:
+		return a.handleOllamaResponse(c, resp)
+	case stream:
+		return a.handleStreamResponse(c, resp)
+	default:
+		return a.handleNonStreamResponse(c, resp)
+	}
+}
+
+// handleOllamaResponse converts a non-stream /api/chat response to chat.completion
+func (a *LocalAdapter) handleOllamaResponse(c *flow.Ctx, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to read upstream response")
+	}
+
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    string(body),
+	})
+
+	var ollamaResp ollamaChatResponse
+	if err := json.Unmarshal(body, &ollamaResp); err != nil {
+		return domain.NewProxyErrorWithMessage(domain.ErrFormatConversion, false, "invalid ollama response")
+	}
+	completion := convertOllamaResponse(&ollamaResp)
+	eventChan.SendMetrics(toAdapterMetrics(completion.Usage))
+	eventChan.SendResponseModel(completion.Model)
+
+	out, err := json.Marshal(completion)
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(domain.ErrFormatConversion, false, "failed to encode response")
+	}
+	c.Writer.Header().Set("Content-Type", "application/json")
+	c.Writer.WriteHeader(http.StatusOK)
+	_, _ = c.Writer.Write(out)
+	return nil
+}
+
+// handleOllamaStream converts the /api/chat NDJSON stream to OpenAI SSE chunks
+func (a *LocalAdapter) handleOllamaStream(c *flow.Ctx, resp *http.Response) error {
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    "[streaming]",
+	})
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, false, "streaming not supported")
+	}
+	setStreamHeaders(c.Writer)
+
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	converter := newOllamaStreamConverter()
+	var rawBuffer strings.Builder
+	sendFinalEvents := func() {
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    rawBuffer.String(),
+		})
+		if converter.usage != nil {
+			eventChan.SendMetrics(toAdapterMetrics(converter.usage))
+		}
+		eventChan.SendResponseModel(converter.model)
+	}
+
+	headerWritten := false
+	reader := bufio.NewReader(resp.Body)
+	for {
+		select {
+		case <-ctx.Done():
+			sendFinalEvents()
+			return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+		default:
+		}
+
+		line, readErr := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			rawBuffer.Write(line)
+			out, convErr := converter.convertLine(trimmed)
+			if convErr != nil {
+				sendFinalEvents()
+				if !headerWritten {
+					return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, convErr.Error())
+				}
+				return domain.NewProxyErrorWithMessage(convErr, false, "ollama stream failed")
+			}
+			if len(out) > 0 {
+				if !headerWritten {
+					c.Writer.WriteHeader(http.StatusOK)
+					headerWritten = true
+					eventChan.SendFirstToken(time.Now().UnixMilli())
+				}
+				if _, writeErr := c.Writer.Write(out); writeErr != nil {
+					sendFinalEvents()
+					return domain.NewProxyErrorWithMessage(writeErr, false, "client disconnected")
+				}
+				flusher.Flush()
+			}
+		}
+
+		if readErr != nil {
+			sendFinalEvents()
+			if readErr == io.EOF {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+			}
+			return domain.NewProxyErrorWithMessage(readErr, false, "failed to read upstream stream")
+		}
+	}
+}
+
+func (a *LocalAdapter) handleNonStreamResponse(c *flow.Ctx, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to read upstream response")
+	}
+
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    string(body),
+	})
+	if metrics := usage.ExtractFromResponse(string(body)); metrics != nil {
+		eventChan.SendMetrics(fromUsageMetrics(metrics))
+	}
+	eventChan.SendResponseModel(gjson.GetBytes(body, "model").String())
+
+	c.Writer.Header().Set("Content-Type", "application/json")
+	c.Writer.WriteHeader(http.StatusOK)
+	_, _ = c.Writer.Write(body)
+	return nil
+}
+
+// handleStreamResponse passes the OpenAI-compatible SSE stream through unchanged
+func (a *LocalAdapter) handleStreamResponse(c *flow.Ctx, resp *http.Response) error {
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    "[streaming]",
+	})
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, false, "streaming not supported")
+	}
+	setStreamHeaders(c.Writer)
+
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	var sseBuffer strings.Builder
+	sendFinalEvents := func() {
+		if sseBuffer.Len() == 0 {
+			return
+		}
+		content := sseBuffer.String()
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    content,
+		})
+		if metrics := usage.ExtractFromStreamContent(content); metrics != nil {
+			eventChan.SendMetrics(fromUsageMetrics(metrics))
+		}
+		eventChan.SendResponseModel(extractStreamModel(content))
+	}
+
+	headerWritten := false
+	buf := make([]byte, 32*1024)
+	for {
+		select {
+		case <-ctx.Done():
+			sendFinalEvents()
+			return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+		default:
+		}
+
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if !headerWritten {
+				c.Writer.WriteHeader(http.StatusOK)
+				headerWritten = true
+				eventChan.SendFirstToken(time.Now().UnixMilli())
+			}
+			sseBuffer.Write(buf[:n])
+			if _, writeErr := c.Writer.Write(buf[:n]); writeErr != nil {
+				sendFinalEvents()
+				return domain.NewProxyErrorWithMessage(writeErr, false, "client disconnected")
+			}
+			flusher.Flush()
+		}
+
+		if readErr != nil {
+			sendFinalEvents()
+			if readErr == io.EOF {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+			}
+			return domain.NewProxyErrorWithMessage(readErr, false, "failed to read upstream stream")
+		}
+	}
+}
+
+func setStreamHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+}
+
+// extractStreamModel returns the model of the last chunk that carries one
+func extractStreamModel(content string) string {
+	var model string
+	for _, line := range strings.Split(content, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		if m := gjson.Get(data, "model").String(); m != "" {
+			model = m
+		}
+	}
+	return model
+}
+
+// newUpstreamError builds the ProxyError for an error response; Ollama returns {"error": "..."},
+// OpenAI-compatible servers return {"error": {"message": "..."}}
+func newUpstreamError(status int, body []byte) *domain.ProxyError {
+	message := gjson.GetBytes(body, "error.message").String()
+	if message == "" {
+		message = gjson.GetBytes(body, "error").String()
+	}
+	if message == "" {
+		message = string(body)
+	}
+	proxyErr := domain.NewProxyErrorWithMessage(
+		fmt.Errorf("upstream error: %s", message),
+		isRetryableStatusCode(status),
+		fmt.Sprintf("upstream returned status %d", status),
+	)
+	proxyErr.HTTPStatusCode = status
+	proxyErr.IsServerError = status >= 500 && status < 600
+	return proxyErr
+}
+
+func isRetryableStatusCode(status int) bool {
+	return status == http.StatusTooManyRequests ||
+		status == http.StatusRequestTimeout ||
+		status >= 500
+}
+
+func toAdapterMetrics(u *chatUsage) *domain.AdapterMetrics {
+	return &domain.AdapterMetrics{
+		InputTokens:  u.PromptTokens,
+		OutputTokens: u.CompletionTokens,
+	}
+}
+
+func fromUsageMetrics(m *usage.Metrics) *domain.AdapterMetrics {
+	return &domain.AdapterMetrics{
+		InputTokens:          m.InputTokens,
+		OutputTokens:         m.OutputTokens,
+		CacheReadCount:       m.CacheReadCount,
+		CacheCreationCount:   m.CacheCreationCount,
+		Cache5mCreationCount: m.Cache5mCreationCount,
+		Cache1hCreationCount: m.Cache1hCreationCount,
+	}
+}
+
+func flattenHeaders(h http.Header) map[string]string {
+	result := make(map[string]string)
+	for k, v := range h {
+		if len(v) > 0 {
+			result[k] = v[0]
+		}
+	}
+	return result
+}
@@ -0,0 +1,195 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/tidwall/gjson"
+)
+
+func TestNormalizeBaseURL(t *testing.T) {
+	cases := map[string]string{
+		"http://localhost:11434":    "http://localhost:11434",
+		"http://localhost:11434/":   "http://localhost:11434",
+		"http://gpu-box:8000/v1":    "http://gpu-box:8000",
+		" http://gpu-box:8000/v1/ ": "http://gpu-box:8000",
+	}
+	for in, want := range cases {
+		if got := NormalizeBaseURL(in); got != want {
+			t.Errorf("NormalizeBaseURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestConvertToOllamaRequest(t *testing.T) {
+	body := []byte(`{
+		"model": "claude-haiku",
+		"messages": [
+			{"role": "developer", "content": "be brief"},
+			{"role": "user", "content": [
+				{"type": "text", "text": "what is this?"},
+				{"type": "image_url", "image_url": {"url": "data:image/png;base64,aGVsbG8="}}
+			]},
+			{"role": "assistant", "content": null, "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "lookup", "arguments": "{\"q\":\"x\"}"}}
+			]},
+			{"role": "tool", "tool_call_id": "call_1", "content": "result"}
+		],
+		"tools": [{"type": "function", "function": {"name": "lookup", "parameters": {"type": "object"}}}],
+		"max_tokens": 256,
+		"temperature": 0.2,
+		"stop": "END",
+		"response_format": {"type": "json_object"}
+	}`)
+
+	out, err := convertToOllamaRequest(body, "qwen3:8b", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := gjson.ParseBytes(out)
+	if root.Get("model").String() != "qwen3:8b" || !root.Get("stream").Bool() {
+		t.Errorf("unexpected model/stream: %s", out)
+	}
+	if root.Get("messages.0.role").String() != "system" {
+		t.Errorf("developer role should map to system: %s", out)
+	}
+	if root.Get("messages.1.content").String() != "what is this?" || root.Get("messages.1.images.0").String() != "aGVsbG8=" {
+		t.Errorf("unexpected user message: %s", root.Get("messages.1").Raw)
+	}
+	if root.Get("messages.2.tool_calls.0.function.arguments.q").String() != "x" {
+		t.Errorf("tool call arguments should be an object: %s", root.Get("messages.2").Raw)
+	}
+	if root.Get("messages.3.tool_name").String() != "lookup" {
+		t.Errorf("tool message should carry the function name: %s", root.Get("messages.3").Raw)
+	}
+	if root.Get("options.num_predict").Int() != 256 || root.Get("options.temperature").Float() != 0.2 {
+		t.Errorf("unexpected options: %s", root.Get("options").Raw)
+	}
+	if root.Get("options.stop.0").String() != "END" || root.Get("format").String() != "json" {
+		t.Errorf("unexpected stop/format: %s", out)
+	}
+	if !root.Get("tools").IsArray() {
+		t.Errorf("tools should pass through: %s", out)
+	}
+}
+
+func TestConvertOllamaResponse(t *testing.T) {
+	var resp ollamaChatResponse
+	_ = json.Unmarshal([]byte(`{
+		"model": "qwen3:8b",
+		"message": {"role": "assistant", "content": "", "tool_calls": [{"function": {"name": "lookup", "arguments": {"q": "x"}}}]},
+		"done": true, "done_reason": "stop", "prompt_eval_count": 12, "eval_count": 5
+	}`), &resp)
+
+	completion := convertOllamaResponse(&resp)
+	if completion.Object != "chat.completion" || completion.Model != "qwen3:8b" {
+		t.Errorf("unexpected completion %+v", completion)
+	}
+	choice := completion.Choices[0]
+	if *choice.FinishReason != "tool_calls" || choice.Message.ToolCalls[0].Function.Arguments != `{"q": "x"}` {
+		t.Errorf("unexpected choice %+v", choice.Message)
+	}
+	if choice.Message.ToolCalls[0].Index != nil {
+		t.Error("non-stream tool calls should not carry an index")
+	}
+	if completion.Usage.PromptTokens != 12 || completion.Usage.CompletionTokens != 5 || completion.Usage.TotalTokens != 17 {
+		t.Errorf("unexpected usage %+v", completion.Usage)
+	}
+}
+
+func TestOllamaStreamConverter(t *testing.T) {
+	lines := []string{
+		`{"model":"llama3.2","message":{"role":"assistant","content":"Hel"},"done":false}`,
+		`{"model":"llama3.2","message":{"role":"assistant","content":"lo"},"done":false}`,
+		`{"model":"llama3.2","message":{"role":"assistant","content":""},"done":true,"done_reason":"length","prompt_eval_count":7,"eval_count":2}`,
+	}
+	converter := newOllamaStreamConverter()
+	var out strings.Builder
+	for _, line := range lines {
+		chunk, err := converter.convertLine([]byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.Write(chunk)
+	}
+
+	var content strings.Builder
+	var finish string
+	var events []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		events = append(events, data)
+		if data == "[DONE]" {
+			continue
+		}
+		content.WriteString(gjson.Get(data, "choices.0.delta.content").String())
+		if f := gjson.Get(data, "choices.0.finish_reason").String(); f != "" {
+			finish = f
+			if gjson.Get(data, "usage.prompt_tokens").Int() != 7 || gjson.Get(data, "usage.completion_tokens").Int() != 2 {
+				t.Errorf("finish chunk should carry usage: %s", data)
+			}
+		}
+	}
+	if len(events) != 4 || events[len(events)-1] != "[DONE]" {
+		t.Fatalf("unexpected events %v", events)
+	}
+	if gjson.Get(events[0], "choices.0.delta.role").String() != "assistant" {
+		t.Errorf("first chunk should carry the role: %s", events[0])
+	}
+	if content.String() != "Hello" || finish != "length" || converter.model != "llama3.2" {
+		t.Errorf("content=%q finish=%q model=%q", content.String(), finish, converter.model)
+	}
+
+	if _, err := newOllamaStreamConverter().convertLine([]byte(`{"error":"model not found"}`)); err == nil {
+		t.Error("expected error line to fail")
+	}
+}
+
+func TestDiscoverModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			_, _ = w.Write([]byte(`{"models":[{"name":"qwen3:8b"},{"name":"llama3.2:latest"}]}`))
+		case "/v1/models":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"Qwen/Qwen3-8B"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	models, err := DiscoverModels(context.Background(), server.Client(), &domain.ProviderConfigLocal{
+		BaseURL: server.URL + "/v1",
+		Backend: domain.LocalBackendOllama,
+	})
+	if err != nil || !reflect.DeepEqual(models, []string{"llama3.2:latest", "qwen3:8b"}) {
+		t.Errorf("ollama discovery = %v, %v", models, err)
+	}
+
+	models, err = DiscoverModels(context.Background(), server.Client(), &domain.ProviderConfigLocal{
+		BaseURL: server.URL,
+		APIKey:  "secret",
+	})
+	if err != nil || !reflect.DeepEqual(models, []string{"Qwen/Qwen3-8B"}) {
+		t.Errorf("openai discovery = %v, %v", models, err)
+	}
+
+	// /v1/models 失败时回退到 /api/tags
+	models, err = DiscoverModels(context.Background(), server.Client(), &domain.ProviderConfigLocal{BaseURL: server.URL})
+	if err != nil || len(models) != 2 {
+		t.Errorf("fallback discovery = %v, %v", models, err)
+	}
+}
@@ -0,0 +1,133 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// NormalizeBaseURL trims the trailing slash and an optional /v1 suffix,
+// so both http://host:11434 and http://host:8000/v1 are accepted
+func NormalizeBaseURL(baseURL string) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	return strings.TrimSuffix(baseURL, "/v1")
+}
+
+// DiscoverModels lists the models served by a local backend.
+// Ollama backends try /api/tags first, OpenAI-compatible backends try /v1/models first;
+// the other endpoint is used as a fallback.
+func DiscoverModels(ctx context.Context, httpClient *http.Client, config *domain.ProviderConfigLocal) ([]string, error) {
+	baseURL := NormalizeBaseURL(config.BaseURL)
+	if baseURL == "" {
+		return nil, fmt.Errorf("baseURL is required")
+	}
+
+	discoverers := []func(context.Context, *http.Client, string, string) ([]string, error){fetchOpenAIModels, fetchOllamaTags}
+	if config.Backend == domain.LocalBackendOllama {
+		discoverers[0], discoverers[1] = discoverers[1], discoverers[0]
+	}
+
+	var firstErr error
+	for _, discover := range discoverers {
+		models, err := discover(ctx, httpClient, baseURL, config.APIKey)
+		if err == nil {
+			sort.Strings(models)
+			return models, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// DiscoverWithNetwork runs DiscoverModels with a short-timeout client honouring the provider network settings
+func DiscoverWithNetwork(ctx context.Context, config *domain.ProviderConfigLocal, network *domain.ProviderConfigNetwork) ([]string, error) {
+	opts := provider.DefaultTransportOptions()
+	opts.Timeout = 10 * time.Second
+	httpClient, err := provider.NewHTTPClient(network, opts)
+	if err != nil {
+		return nil, err
+	}
+	return DiscoverModels(ctx, httpClient, config)
+}
+
+// fetchOllamaTags lists models from Ollama's GET /api/tags
+func fetchOllamaTags(ctx context.Context, httpClient *http.Client, baseURL, apiKey string) ([]string, error) {
+	var result struct {
+		Models []struct {
+			Name  string `json:"name"`
+			Model string `json:"model"`
+		} `json:"models"`
+	}
+	if err := getJSON(ctx, httpClient, baseURL+"/api/tags", apiKey, &result); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(result.Models))
+	for _, m := range result.Models {
+		name := m.Name
+		if name == "" {
+			name = m.Model
+		}
+		if name != "" {
+			models = append(models, name)
+		}
+	}
+	return models, nil
+}
+
+// fetchOpenAIModels lists models from the OpenAI-compatible GET /v1/models
+func fetchOpenAIModels(ctx context.Context, httpClient *http.Client, baseURL, apiKey string) ([]string, error) {
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, httpClient, baseURL+"/v1/models", apiKey, &result); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(result.Data))
+	for _, m := range result.Data {
+		if m.ID != "" {
+			models = append(models, m.ID)
+		}
+	}
+	return models, nil
+}
+
+func getJSON(ctx context.Context, httpClient *http.Client, url, apiKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", url, err)
+	}
+	return nil
+}
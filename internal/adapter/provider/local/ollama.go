@@ -0,0 +1,346 @@
+package local
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// ollamaChatRequest is the body of Ollama's native POST /api/chat
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Tools    json.RawMessage `json:"tools,omitempty"`
+	Format   json.RawMessage `json:"format,omitempty"`
+	Options  map[string]any  `json:"options,omitempty"`
+}
+
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	Thinking  string           `json:"thinking,omitempty"`
+	Images    []string         `json:"images,omitempty"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+type ollamaToolCall struct {
+	Function ollamaFunction `json:"function"`
+}
+
+type ollamaFunction struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// ollamaChatResponse is a non-stream response or one NDJSON line of a stream
+type ollamaChatResponse struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason"`
+	PromptEvalCount uint64        `json:"prompt_eval_count"`
+	EvalCount       uint64        `json:"eval_count"`
+	Error           string        `json:"error"`
+}
+
+// OpenAI Chat Completions response shapes produced from Ollama responses
+type chatCompletion struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+	Usage   *chatUsage   `json:"usage,omitempty"`
+}
+
+type chatChoice struct {
+	Index        int          `json:"index"`
+	Message      *chatMessage `json:"message,omitempty"`
+	Delta        *chatMessage `json:"delta,omitempty"`
+	FinishReason *string      `json:"finish_reason"`
+}
+
+type chatMessage struct {
+	Role             string         `json:"role,omitempty"`
+	Content          string         `json:"content"`
+	ReasoningContent string         `json:"reasoning_content,omitempty"`
+	ToolCalls        []chatToolCall `json:"tool_calls,omitempty"`
+}
+
+type chatToolCall struct {
+	Index    *int             `json:"index,omitempty"`
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function chatFunctionCall `json:"function"`
+}
+
+type chatFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type chatUsage struct {
+	PromptTokens     uint64 `json:"prompt_tokens"`
+	CompletionTokens uint64 `json:"completion_tokens"`
+	TotalTokens      uint64 `json:"total_tokens"`
+}
+
+// ollamaOptionKeys maps OpenAI sampling parameters to Ollama options
+var ollamaOptionKeys = map[string]string{
+	"temperature":           "temperature",
+	"top_p":                 "top_p",
+	"seed":                  "seed",
+	"frequency_penalty":     "frequency_penalty",
+	"presence_penalty":      "presence_penalty",
+	"max_tokens":            "num_predict",
+	"max_completion_tokens": "num_predict",
+}
+
+// convertToOllamaRequest converts an OpenAI Chat Completions body to an Ollama /api/chat body
+func convertToOllamaRequest(body []byte, model string, stream bool) ([]byte, error) {
+	root := gjson.ParseBytes(body)
+	req := ollamaChatRequest{Model: model, Stream: stream}
+
+	// tool 消息只带 tool_call_id，Ollama 需要函数名
+	toolNames := make(map[string]string)
+	for _, msg := range root.Get("messages").Array() {
+		m := ollamaMessage{Role: msg.Get("role").String()}
+		if m.Role == "developer" {
+			m.Role = "system"
+		}
+
+		content := msg.Get("content")
+		if content.IsArray() {
+			var texts []string
+			for _, part := range content.Array() {
+				switch part.Get("type").String() {
+				case "text":
+					texts = append(texts, part.Get("text").String())
+				case "image_url":
+					if data, ok := dataURLBase64(part.Get("image_url.url").String()); ok {
+						m.Images = append(m.Images, data)
+					}
+				}
+			}
+			m.Content = strings.Join(texts, "\n")
+		} else {
+			m.Content = content.String()
+		}
+		m.Thinking = msg.Get("reasoning_content").String()
+
+		for _, tc := range msg.Get("tool_calls").Array() {
+			name := tc.Get("function.name").String()
+			toolNames[tc.Get("id").String()] = name
+			// OpenAI 的 arguments 是 JSON 字符串，Ollama 需要对象
+			args := tc.Get("function.arguments")
+			raw := args.Raw
+			if args.Type == gjson.String {
+				raw = args.String()
+			}
+			if strings.TrimSpace(raw) == "" || !json.Valid([]byte(raw)) {
+				raw = "{}"
+			}
+			m.ToolCalls = append(m.ToolCalls, ollamaToolCall{
+				Function: ollamaFunction{Name: name, Arguments: json.RawMessage(raw)},
+			})
+		}
+		if m.Role == "tool" {
+			m.ToolName = toolNames[msg.Get("tool_call_id").String()]
+		}
+		req.Messages = append(req.Messages, m)
+	}
+
+	if tools := root.Get("tools"); tools.IsArray() && len(tools.Array()) > 0 {
+		req.Tools = json.RawMessage(tools.Raw)
+	}
+
+	switch root.Get("response_format.type").String() {
+	case "json_object":
+		req.Format = json.RawMessage(`"json"`)
+	case "json_schema":
+		if schema := root.Get("response_format.json_schema.schema"); schema.Exists() {
+			req.Format = json.RawMessage(schema.Raw)
+		}
+	}
+
+	options := make(map[string]any)
+	for openaiKey, ollamaKey := range ollamaOptionKeys {
+		if v := root.Get(openaiKey); v.Exists() && v.Type == gjson.Number {
+			options[ollamaKey] = v.Value()
+		}
+	}
+	if stop := root.Get("stop"); stop.Exists() {
+		var stops []string
+		if stop.IsArray() {
+			for _, s := range stop.Array() {
+				stops = append(stops, s.String())
+			}
+		} else if stop.String() != "" {
+			stops = append(stops, stop.String())
+		}
+		if len(stops) > 0 {
+			options["stop"] = stops
+		}
+	}
+	if len(options) > 0 {
+		req.Options = options
+	}
+
+	return json.Marshal(req)
+}
+
+// dataURLBase64 extracts the base64 payload of a data: URL; remote image URLs are not supported by Ollama
+func dataURLBase64(url string) (string, bool) {
+	if !strings.HasPrefix(url, "data:") {
+		return "", false
+	}
+	_, data, ok := strings.Cut(url, ";base64,")
+	return data, ok && data != ""
+}
+
+// convertOllamaResponse converts a non-stream Ollama response to an OpenAI chat.completion
+func convertOllamaResponse(resp *ollamaChatResponse) *chatCompletion {
+	message := &chatMessage{
+		Role:             "assistant",
+		Content:          resp.Message.Content,
+		ReasoningContent: resp.Message.Thinking,
+		ToolCalls:        convertToolCalls(resp.Message.ToolCalls, nil),
+	}
+	finish := finishReason(resp.DoneReason, len(message.ToolCalls) > 0)
+	return &chatCompletion{
+		ID:      newCompletionID(),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   resp.Model,
+		Choices: []chatChoice{{Index: 0, Message: message, FinishReason: &finish}},
+		Usage:   newUsage(resp),
+	}
+}
+
+// ollamaStreamConverter converts Ollama NDJSON stream lines to OpenAI chat.completion.chunk SSE events
+type ollamaStreamConverter struct {
+	id           string
+	created      int64
+	model        string
+	started      bool
+	toolIndex    int
+	hasToolCalls bool
+	usage        *chatUsage
+}
+
+func newOllamaStreamConverter() *ollamaStreamConverter {
+	return &ollamaStreamConverter{id: newCompletionID(), created: time.Now().Unix()}
+}
+
+// convertLine converts one NDJSON line; the done line also emits the finish chunk
+// (with usage, for billing and format conversion) and [DONE]
+func (s *ollamaStreamConverter) convertLine(line []byte) ([]byte, error) {
+	var resp ollamaChatResponse
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, fmt.Errorf("invalid ollama stream line: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("ollama error: %s", resp.Error)
+	}
+	if resp.Model != "" {
+		s.model = resp.Model
+	}
+
+	var out []byte
+	delta := &chatMessage{
+		Content:          resp.Message.Content,
+		ReasoningContent: resp.Message.Thinking,
+		ToolCalls:        convertToolCalls(resp.Message.ToolCalls, &s.toolIndex),
+	}
+	if len(delta.ToolCalls) > 0 {
+		s.hasToolCalls = true
+	}
+	if !s.started || delta.Content != "" || delta.ReasoningContent != "" || len(delta.ToolCalls) > 0 {
+		if !s.started {
+			delta.Role = "assistant"
+			s.started = true
+		}
+		out = append(out, s.chunk(delta, nil, nil)...)
+	}
+
+	if resp.Done {
+		finish := finishReason(resp.DoneReason, s.hasToolCalls)
+		s.usage = newUsage(&resp)
+		out = append(out, s.chunk(&chatMessage{}, &finish, s.usage)...)
+		out = append(out, "data: [DONE]\n\n"...)
+	}
+	return out, nil
+}
+
+func (s *ollamaStreamConverter) chunk(delta *chatMessage, finish *string, usage *chatUsage) []byte {
+	data, _ := json.Marshal(chatCompletion{
+		ID:      s.id,
+		Object:  "chat.completion.chunk",
+		Created: s.created,
+		Model:   s.model,
+		Choices: []chatChoice{{Index: 0, Delta: delta, FinishReason: finish}},
+		Usage:   usage,
+	})
+	return []byte("data: " + string(data) + "\n\n")
+}
+
+// convertToolCalls converts Ollama tool calls; index is the running stream index (nil for non-stream)
+func convertToolCalls(calls []ollamaToolCall, index *int) []chatToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	result := make([]chatToolCall, 0, len(calls))
+	for _, call := range calls {
+		args := string(call.Function.Arguments)
+		if args == "" || args == "null" {
+			args = "{}"
+		}
+		tc := chatToolCall{
+			ID:       "call_" + randomHex(12),
+			Type:     "function",
+			Function: chatFunctionCall{Name: call.Function.Name, Arguments: args},
+		}
+		if index != nil {
+			i := *index
+			tc.Index = &i
+			*index++
+		}
+		result = append(result, tc)
+	}
+	return result
+}
+
+func finishReason(doneReason string, hasToolCalls bool) string {
+	if hasToolCalls {
+		return "tool_calls"
+	}
+	if doneReason == "length" {
+		return "length"
+	}
+	return "stop"
+}
+
+func newUsage(resp *ollamaChatResponse) *chatUsage {
+	return &chatUsage{
+		PromptTokens:     resp.PromptEvalCount,
+		CompletionTokens: resp.EvalCount,
+		TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+	}
+}
+
+func newCompletionID() string {
+	return "chatcmpl-" + randomHex(12)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// TransportOptions are the adapter defaults for upstream connections.
+// Provider network settings (domain.ProviderConfigNetwork) override them.
+type TransportOptions struct {
+	ConnectTimeout      time.Duration
+	KeepAlive           time.Duration
+	TLSHandshakeTimeout time.Duration
+	IdleConnTimeout     time.Duration
+	MaxIdleConnsPerHost int
+	DisableHTTP2        bool
+
+	// Base TLS settings (cipher suites, versions), cloned per transport
+	TLSConfig *tls.Config
+
+	// Overall client timeout, 0 means none (streaming responses rely on context cancellation)
+	Timeout time.Duration
+}
+
+// DefaultTransportOptions returns the defaults used for LLM upstream requests
+func DefaultTransportOptions() TransportOptions {
+	return TransportOptions{
+		ConnectTimeout:      20 * time.Second,
+		KeepAlive:           60 * time.Second,
+		TLSHandshakeTimeout: 20 * time.Second,
+		IdleConnTimeout:     90 * time.Second,
+		MaxIdleConnsPerHost: 16,
+		Timeout:             600 * time.Second,
+	}
+}
+
+// transportCache shares transports (and therefore connection pools) between adapters
+// with identical effective settings, so adapter refreshes do not drop warm connections.
+var transportCache sync.Map // key -> *http.Transport
+
+// NewHTTPClient returns an HTTP client for a provider.
+// The underlying transport is shared by every provider with the same network settings.
+func NewHTTPClient(network *domain.ProviderConfigNetwork, opts TransportOptions) (*http.Client, error) {
+	transport, err := SharedTransport(network, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   opts.Timeout,
+	}, nil
+}
+
+// NewOAuthHTTPClient returns a short-timeout client for token refresh, OAuth code exchange and
+// quota queries, sharing the provider transport so they honour the same proxy and CA settings.
+// Pass nil when there is no provider yet (e.g. validating a pasted token).
+func NewOAuthHTTPClient(network *domain.ProviderConfigNetwork) (*http.Client, error) {
+	opts := DefaultTransportOptions()
+	opts.Timeout = 15 * time.Second
+	return NewHTTPClient(network, opts)
+}
+
+// SharedTransport returns a cached transport for the given settings
+func SharedTransport(network *domain.ProviderConfigNetwork, opts TransportOptions) (*http.Transport, error) {
+	key, err := transportKey(network, opts)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := transportCache.Load(key); ok {
+		return t.(*http.Transport), nil
+	}
+
+	t, err := buildTransport(network, opts)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := transportCache.LoadOrStore(key, t)
+	return actual.(*http.Transport), nil
+}
+
+func buildTransport(network *domain.ProviderConfigNetwork, opts TransportOptions) (*http.Transport, error) {
+	if network == nil {
+		network = &domain.ProviderConfigNetwork{}
+	}
+
+	proxy, err := proxyFunc(network.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	connectTimeout := opts.ConnectTimeout
+	tlsHandshakeTimeout := opts.TLSHandshakeTimeout
+	if network.ConnectTimeoutSeconds > 0 {
+		connectTimeout = time.Duration(network.ConnectTimeoutSeconds) * time.Second
+		tlsHandshakeTimeout = connectTimeout
+	}
+	idleTimeout := opts.IdleConnTimeout
+	if network.IdleTimeoutSeconds > 0 {
+		idleTimeout = time.Duration(network.IdleTimeoutSeconds) * time.Second
+	}
+
+	var tlsConfig *tls.Config
+	if opts.TLSConfig != nil {
+		tlsConfig = opts.TLSConfig.Clone()
+	}
+	if network.CACert != "" || network.InsecureSkipVerify {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		if network.CACert != "" {
+			pool, err := certPool(network.CACert)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
+		}
+		if network.InsecureSkipVerify {
+			tlsConfig.InsecureSkipVerify = true
+		}
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: opts.KeepAlive,
+	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     !opts.DisableHTTP2,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:       idleTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ResponseHeaderTimeout: time.Duration(network.ReadTimeoutSeconds) * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}, nil
+}
+
+// proxyFunc resolves the proxy setting. Empty uses the environment.
+func proxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	proxyURL = strings.TrimSpace(proxyURL)
+	switch proxyURL {
+	case "":
+		return http.ProxyFromEnvironment, nil
+	case domain.ProxyDirect:
+		return nil, nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q", proxyURL)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https, socks5 or socks5h)", u.Scheme)
+	}
+	return http.ProxyURL(u), nil
+}
+
+// certPool returns the system roots plus the given PEM bundle
+func certPool(pemData string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(pemData)) {
+		return nil, errors.New("invalid CA certificate: no PEM certificates found")
+	}
+	return pool, nil
+}
+
+func transportKey(network *domain.ProviderConfigNetwork, opts TransportOptions) (string, error) {
+	var tlsKey any
+	if opts.TLSConfig != nil {
+		tlsKey = struct {
+			MinVersion, MaxVersion uint16
+			CipherSuites           []uint16
+		}{opts.TLSConfig.MinVersion, opts.TLSConfig.MaxVersion, opts.TLSConfig.CipherSuites}
+	}
+	opts.TLSConfig = nil
+
+	b, err := json.Marshal(struct {
+		Network *domain.ProviderConfigNetwork
+		Opts    TransportOptions
+		TLS     any
+	}{network, opts, tlsKey})
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,363 @@
+package vertex
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/flow"
+	"github.com/awsl-project/maxx/internal/usage"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+func init() {
+	provider.RegisterAdapterFactory("vertex", NewAdapter)
+}
+
+// VertexAdapter calls Gemini and Anthropic models on Google Vertex AI with service-account auth.
+// Claude models use rawPredict/streamRawPredict (Claude format), all other models use
+// generateContent/streamGenerateContent (Gemini format); the executor converts other client formats.
+type VertexAdapter struct {
+	provider   *domain.Provider
+	project    string
+	region     string
+	tokens     *tokenSource
+	httpClient *http.Client
+}
+
+// NewAdapter creates a new Vertex AI adapter
+func NewAdapter(p *domain.Provider) (provider.ProviderAdapter, error) {
+	if p.Config == nil || p.Config.Vertex == nil {
+		return nil, fmt.Errorf("provider %s missing vertex config", p.Name)
+	}
+	config := p.Config.Vertex
+	key, privateKey, err := parseServiceAccountKey(config.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s: %w", p.Name, err)
+	}
+	project := config.ProjectID
+	if project == "" {
+		project = key.ProjectID
+	}
+	if project == "" {
+		return nil, fmt.Errorf("provider %s missing GCP project ID", p.Name)
+	}
+	region := config.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	opts := provider.DefaultTransportOptions()
+	opts.Timeout = 10 * time.Minute
+	httpClient, err := provider.NewHTTPClient(p.Config.Network, opts)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s network config: %w", p.Name, err)
+	}
+	return &VertexAdapter{
+		provider:   p,
+		project:    project,
+		region:     region,
+		tokens:     newTokenSource(key, privateKey, httpClient),
+		httpClient: httpClient,
+	}, nil
+}
+
+// SupportedClientTypes returns the list of client types this adapter natively supports
+func (a *VertexAdapter) SupportedClientTypes() []domain.ClientType {
+	return []domain.ClientType{domain.ClientTypeClaude, domain.ClientTypeGemini}
+}
+
+// SupportedClientTypesForModel narrows the native format to the publisher serving the model
+func (a *VertexAdapter) SupportedClientTypesForModel(model string) []domain.ClientType {
+	if model == "" {
+		return a.SupportedClientTypes()
+	}
+	if isClaudeModel(resolveModelID(model, a.provider.Config.Vertex)) {
+		return []domain.ClientType{domain.ClientTypeClaude}
+	}
+	return []domain.ClientType{domain.ClientTypeGemini}
+}
+
+// Execute performs the proxy request to Vertex AI
+func (a *VertexAdapter) Execute(c *flow.Ctx, p *domain.Provider) error {
+	config := a.provider.Config.Vertex
+	clientType := flow.GetClientType(c)
+	stream := flow.GetIsStream(c)
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	model := flow.GetMappedModel(c)
+	if model == "" {
+		model = flow.GetRequestModel(c)
+	}
+	modelID := resolveModelID(model, config)
+	if attempt := flow.GetUpstreamAttempt(c); attempt != nil {
+		attempt.MappedModel = modelID
+	}
+
+	var (
+		upstreamURL string
+		body        []byte
+		err         error
+		betas       []string
+	)
+	switch clientType {
+	case domain.ClientTypeClaude:
+		body, err = buildClaudeRequestBody(flow.GetRequestBody(c))
+		if err != nil {
+			return domain.NewProxyErrorWithMessage(err, false, fmt.Sprintf("failed to convert request: %v", err))
+		}
+		action := "rawPredict"
+		if stream {
+			action = "streamRawPredict"
+		}
+		upstreamURL = buildURL(config, a.project, a.region, "anthropic", modelID, action)
+		if strings.HasPrefix(flow.GetRequestURI(c), "/v1/messages/count_tokens") {
+			// count_tokens 使用固定的 count-tokens 模型，目标模型放在 body 中
+			body, _ = sjson.SetBytes(body, "model", modelID)
+			upstreamURL = buildURL(config, a.project, a.region, "anthropic", "count-tokens", "rawPredict")
+		}
+		betas = filterBetas(flow.GetRequestHeaders(c))
+	case domain.ClientTypeGemini:
+		body = normalizeGeminiBody(flow.GetRequestBody(c))
+		upstreamURL = buildURL(config, a.project, a.region, "google", modelID, geminiAction(flow.GetRequestURI(c), stream))
+	default:
+		return domain.NewProxyErrorWithMessage(domain.ErrFormatConversion, false,
+			fmt.Sprintf("vertex does not support client type %s", clientType))
+	}
+
+	token, err := a.tokens.Token(ctx)
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(err, true, fmt.Sprintf("failed to get access token: %v", err))
+	}
+
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, bytes.NewReader(body))
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(err, true, "failed to create upstream request")
+	}
+	upstreamReq.Header.Set("Content-Type", "application/json")
+	upstreamReq.Header.Set("Authorization", "Bearer "+token)
+	if len(betas) > 0 {
+		upstreamReq.Header.Set("anthropic-beta", strings.Join(betas, ","))
+	}
+	if stream {
+		upstreamReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendRequestInfo(&domain.RequestInfo{
+		Method:  upstreamReq.Method,
+		URL:     upstreamURL,
+		Headers: flattenHeaders(upstreamReq.Header),
+		Body:    string(body),
+	})
+
+	resp, err := a.httpClient.Do(upstreamReq)
+	if err != nil {
+		proxyErr := domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to connect to upstream")
+		proxyErr.IsNetworkError = true
+		return proxyErr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    string(respBody),
+		})
+		if resp.StatusCode == http.StatusUnauthorized {
+			a.tokens.Invalidate()
+		}
+		return newUpstreamError(resp.StatusCode, respBody)
+	}
+
+	if stream {
+		return a.handleStreamResponse(c, resp)
+	}
+	return a.handleNonStreamResponse(c, resp)
+}
+
+func (a *VertexAdapter) handleNonStreamResponse(c *flow.Ctx, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "failed to read upstream response")
+	}
+
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    string(body),
+	})
+	if metrics := usage.ExtractFromResponse(string(body)); metrics != nil {
+		eventChan.SendMetrics(toAdapterMetrics(metrics))
+	}
+	eventChan.SendResponseModel(responseModel(string(body)))
+
+	c.Writer.Header().Set("Content-Type", "application/json")
+	c.Writer.WriteHeader(http.StatusOK)
+	_, _ = c.Writer.Write(body)
+	return nil
+}
+
+// handleStreamResponse passes the SSE stream through; both publishers already speak their client's SSE format
+func (a *VertexAdapter) handleStreamResponse(c *flow.Ctx, resp *http.Response) error {
+	eventChan := flow.GetEventChan(c)
+	eventChan.SendResponseInfo(&domain.ResponseInfo{
+		Status:  resp.StatusCode,
+		Headers: flattenHeaders(resp.Header),
+		Body:    "[streaming]",
+	})
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		return domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, false, "streaming not supported")
+	}
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	var sseBuffer strings.Builder
+	sendFinalEvents := func() {
+		if sseBuffer.Len() == 0 {
+			return
+		}
+		content := sseBuffer.String()
+		eventChan.SendResponseInfo(&domain.ResponseInfo{
+			Status:  resp.StatusCode,
+			Headers: flattenHeaders(resp.Header),
+			Body:    content,
+		})
+		if metrics := usage.ExtractFromStreamContent(content); metrics != nil {
+			eventChan.SendMetrics(toAdapterMetrics(metrics))
+		}
+		eventChan.SendResponseModel(responseModel(content))
+	}
+
+	headerWritten := false
+	buf := make([]byte, 32*1024)
+	for {
+		select {
+		case <-ctx.Done():
+			sendFinalEvents()
+			return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+		default:
+		}
+
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if !headerWritten {
+				c.Writer.WriteHeader(http.StatusOK)
+				headerWritten = true
+				eventChan.SendFirstToken(time.Now().UnixMilli())
+			}
+			sseBuffer.Write(buf[:n])
+			if _, writeErr := c.Writer.Write(buf[:n]); writeErr != nil {
+				sendFinalEvents()
+				return domain.NewProxyErrorWithMessage(writeErr, false, "client disconnected")
+			}
+			flusher.Flush()
+		}
+
+		if readErr != nil {
+			sendFinalEvents()
+			if readErr == io.EOF {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return domain.NewProxyErrorWithMessage(ctx.Err(), false, "client disconnected")
+			}
+			return domain.NewProxyErrorWithMessage(readErr, false, "failed to read upstream stream")
+		}
+	}
+}
+
+// normalizeGeminiBody fills in the content roles that the Gemini API defaults but Vertex requires
+func normalizeGeminiBody(body []byte) []byte {
+	contents := gjson.GetBytes(body, "contents")
+	if !contents.IsArray() {
+		return body
+	}
+	for i, content := range contents.Array() {
+		if content.Get("role").String() == "" {
+			if updated, err := sjson.SetBytes(body, fmt.Sprintf("contents.%d.role", i), "user"); err == nil {
+				body = updated
+			}
+		}
+	}
+	return body
+}
+
+// responseModel returns the model reported by either publisher (Claude "model" / Gemini "modelVersion")
+func responseModel(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		data := strings.TrimPrefix(line, "data: ")
+		for _, path := range []string{"message.model", "model", "modelVersion"} {
+			if model := gjson.Get(data, path).String(); model != "" {
+				return model
+			}
+		}
+	}
+	return ""
+}
+
+// newUpstreamError builds the ProxyError for a Vertex error status.
+// Both the Google and Anthropic error bodies use {"error": {"message": ...}}.
+func newUpstreamError(status int, body []byte) *domain.ProxyError {
+	message := gjson.GetBytes(body, "error.message").String()
+	if message == "" {
+		message = string(body)
+	}
+	proxyErr := domain.NewProxyErrorWithMessage(
+		fmt.Errorf("upstream error: %s", message),
+		isRetryableStatusCode(status),
+		fmt.Sprintf("upstream returned status %d", status),
+	)
+	proxyErr.HTTPStatusCode = status
+	proxyErr.IsServerError = status >= 500 && status < 600
+	return proxyErr
+}
+
+func isRetryableStatusCode(status int) bool {
+	return status == http.StatusTooManyRequests ||
+		status == http.StatusRequestTimeout ||
+		status >= 500
+}
+
+func toAdapterMetrics(m *usage.Metrics) *domain.AdapterMetrics {
+	return &domain.AdapterMetrics{
+		InputTokens:          m.InputTokens,
+		OutputTokens:         m.OutputTokens,
+		CacheReadCount:       m.CacheReadCount,
+		CacheCreationCount:   m.CacheCreationCount,
+		Cache5mCreationCount: m.Cache5mCreationCount,
+		Cache1hCreationCount: m.Cache1hCreationCount,
+	}
+}
+
+func flattenHeaders(h http.Header) map[string]string {
+	result := make(map[string]string)
+	for k, v := range h {
+		if len(v) > 0 {
+			result[k] = v[0]
+		}
+	}
+	return result
+}
@@ -0,0 +1,172 @@
+package vertex
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tidwall/gjson"
+)
+
+func TestResolveModelID(t *testing.T) {
+	config := &domain.ProviderConfigVertex{
+		ModelMapping: map[string]string{"claude-sonnet-4-5": "claude-sonnet-4-5@20250929"},
+	}
+	cases := map[string]string{
+		"claude-sonnet-4-5":         "claude-sonnet-4-5@20250929",
+		"claude-opus-4-1-20250805":  "claude-opus-4-1@20250805",
+		"claude-3-5-haiku@20241022": "claude-3-5-haiku@20241022",
+		"gemini-2.5-pro":            "gemini-2.5-pro",
+		"gemini-2.0-flash-001":      "gemini-2.0-flash-001",
+	}
+	for model, want := range cases {
+		if got := resolveModelID(model, config); got != want {
+			t.Errorf("resolveModelID(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	config := &domain.ProviderConfigVertex{}
+	got := buildURL(config, "proj", "us-east5", "anthropic", "claude-opus-4-1@20250805", "streamRawPredict")
+	want := "https://us-east5-aiplatform.googleapis.com/v1/projects/proj/locations/us-east5/publishers/anthropic/models/claude-opus-4-1@20250805:streamRawPredict"
+	if got != want {
+		t.Errorf("buildURL = %q, want %q", got, want)
+	}
+
+	got = buildURL(config, "proj", "global", "google", "gemini-2.5-pro", "streamGenerateContent")
+	want = "https://aiplatform.googleapis.com/v1/projects/proj/locations/global/publishers/google/models/gemini-2.5-pro:streamGenerateContent?alt=sse"
+	if got != want {
+		t.Errorf("buildURL = %q, want %q", got, want)
+	}
+}
+
+func TestGeminiAction(t *testing.T) {
+	cases := []struct {
+		uri    string
+		stream bool
+		want   string
+	}{
+		{"/v1beta/models/gemini-2.5-pro:streamGenerateContent?alt=sse", true, "streamGenerateContent"},
+		{"/v1beta/models/gemini-2.5-pro:countTokens", false, "countTokens"},
+		{"/v1beta/models/gemini-2.5-pro", false, "generateContent"},
+		{"/v1beta/models/gemini-2.5-pro", true, "streamGenerateContent"},
+	}
+	for _, tc := range cases {
+		if got := geminiAction(tc.uri, tc.stream); got != tc.want {
+			t.Errorf("geminiAction(%q) = %q, want %q", tc.uri, got, tc.want)
+		}
+	}
+}
+
+func TestBuildClaudeRequestBody(t *testing.T) {
+	body, err := buildClaudeRequestBody([]byte(`{"model":"claude","stream":true,"max_tokens":10}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gjson.GetBytes(body, "model").Exists() {
+		t.Error("model should be removed")
+	}
+	if v := gjson.GetBytes(body, "anthropic_version").String(); v != vertexAnthropicVersion {
+		t.Errorf("anthropic_version = %q", v)
+	}
+	if !gjson.GetBytes(body, "stream").Bool() {
+		t.Error("stream should be preserved for streamRawPredict")
+	}
+}
+
+func TestNormalizeGeminiBody(t *testing.T) {
+	body := normalizeGeminiBody([]byte(`{"contents":[{"parts":[{"text":"hi"}]},{"role":"model","parts":[]}]}`))
+	if role := gjson.GetBytes(body, "contents.0.role").String(); role != "user" {
+		t.Errorf("missing role should default to user, got %q", role)
+	}
+	if role := gjson.GetBytes(body, "contents.1.role").String(); role != "model" {
+		t.Errorf("existing role should be kept, got %q", role)
+	}
+}
+
+func TestTokenSource(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Errorf("unexpected grant_type %q", r.Form.Get("grant_type"))
+		}
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(r.Form.Get("assertion"), claims, func(*jwt.Token) (any, error) {
+			return &privateKey.PublicKey, nil
+		})
+		if err != nil {
+			t.Errorf("invalid assertion: %v", err)
+		}
+		if claims["iss"] != "sa@proj.iam.gserviceaccount.com" || claims["scope"] != cloudPlatformScope {
+			t.Errorf("unexpected claims %v", claims)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "ya29.test", "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	credentials, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "proj",
+		"private_key":  string(pemKey),
+		"client_email": "sa@proj.iam.gserviceaccount.com",
+		"token_uri":    server.URL,
+	})
+	key, parsedKey, err := parseServiceAccountKey(string(credentials))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.ProjectID != "proj" {
+		t.Errorf("unexpected project %q", key.ProjectID)
+	}
+
+	source := newTokenSource(key, parsedKey, server.Client())
+	for i := 0; i < 2; i++ {
+		token, err := source.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "ya29.test" {
+			t.Errorf("unexpected token %q", token)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("token should be cached, got %d exchanges", calls)
+	}
+
+	source.Invalidate()
+	if _, err := source.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("invalidate should force a new exchange, got %d exchanges", calls)
+	}
+}
+
+func TestParseServiceAccountKeyErrors(t *testing.T) {
+	if _, _, err := parseServiceAccountKey("not json"); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	if _, _, err := parseServiceAccountKey(`{"type":"authorized_user"}`); err == nil {
+		t.Error("expected error for non service account credentials")
+	}
+	if _, _, err := parseServiceAccountKey(`{"client_email":"a","private_key":"bad"}`); err == nil {
+		t.Error("expected error for invalid private key")
+	}
+}
@@ -0,0 +1,163 @@
+package vertex
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultTokenURI    = "https://oauth2.googleapis.com/token"
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+	// 签名 JWT 的有效期（Google 上限为 1 小时）
+	assertionLifetime = time.Hour
+
+	// access token 提前刷新的时间
+	tokenRefreshBuffer = 5 * time.Minute
+)
+
+// serviceAccountKey is the subset of a service-account JSON key used for the JWT bearer flow
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// parseServiceAccountKey validates a service-account JSON key and parses its RSA private key
+func parseServiceAccountKey(data string) (*serviceAccountKey, *rsa.PrivateKey, error) {
+	var key serviceAccountKey
+	if err := json.Unmarshal([]byte(data), &key); err != nil {
+		return nil, nil, fmt.Errorf("invalid service account JSON: %w", err)
+	}
+	if key.Type != "" && key.Type != "service_account" {
+		return nil, nil, fmt.Errorf("credentials type %q is not service_account", key.Type)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, nil, fmt.Errorf("service account JSON missing client_email or private_key")
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key.PrivateKey))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid service account private key: %w", err)
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = defaultTokenURI
+	}
+	return &key, privateKey, nil
+}
+
+// tokenSource exchanges a signed service-account JWT for an OAuth access token
+// (RFC 7523 JWT bearer grant) and caches it until shortly before expiry.
+type tokenSource struct {
+	key        *serviceAccountKey
+	privateKey *rsa.PrivateKey
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func newTokenSource(key *serviceAccountKey, privateKey *rsa.PrivateKey, httpClient *http.Client) *tokenSource {
+	return &tokenSource{key: key, privateKey: privateKey, httpClient: httpClient}
+}
+
+// Token returns a valid access token, exchanging a new assertion when the cached one is about to expire
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Add(tokenRefreshBuffer).Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	token, expiresAt, err := s.exchange(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	s.expiresAt = expiresAt
+	return token, nil
+}
+
+// Invalidate drops the cached token so the next Token call exchanges a new one
+func (s *tokenSource) Invalidate() {
+	s.mu.Lock()
+	s.token = ""
+	s.expiresAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// signAssertion builds the RS256-signed JWT assertion for the token endpoint
+func (s *tokenSource) signAssertion(now time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"iss":   s.key.ClientEmail,
+		"scope": cloudPlatformScope,
+		"aud":   s.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(assertionLifetime).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if s.key.PrivateKeyID != "" {
+		token.Header["kid"] = s.key.PrivateKeyID
+	}
+	return token.SignedString(s.privateKey)
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (s *tokenSource) exchange(ctx context.Context) (string, time.Time, error) {
+	now := time.Now()
+	assertion, err := s.signAssertion(now)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign service account assertion: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.key.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read token response: %w", err)
+	}
+	var result tokenResponse
+	_ = json.Unmarshal(body, &result)
+	if resp.StatusCode != http.StatusOK {
+		if result.Error != "" {
+			return "", time.Time{}, fmt.Errorf("token exchange failed: %s: %s", result.Error, result.ErrorDescription)
+		}
+		return "", time.Time{}, fmt.Errorf("token exchange failed: status %d", resp.StatusCode)
+	}
+	if result.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("token exchange returned empty access token")
+	}
+	return result.AccessToken, now.Add(time.Duration(result.ExpiresIn) * time.Second), nil
+}
@@ -0,0 +1,103 @@
+package vertex
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	// DefaultRegion 默认区域，global 端点可访问绝大多数 Gemini 与 Claude 模型
+	DefaultRegion = "global"
+
+	// vertexAnthropicVersion Anthropic-on-Vertex 固定的 anthropic_version
+	vertexAnthropicVersion = "vertex-2023-10-16"
+)
+
+// claudeDateSuffix 匹配 Claude API 模型名末尾的日期，Vertex 使用 @ 分隔版本
+var claudeDateSuffix = regexp.MustCompile(`-(\d{8})$`)
+
+// isClaudeModel reports whether a model is served by the Anthropic publisher
+func isClaudeModel(model string) bool {
+	return strings.HasPrefix(strings.ToLower(model), "claude")
+}
+
+// resolveModelID maps a request model to a Vertex model ID.
+// Provider-level mapping wins; Claude API names such as "claude-sonnet-4-5-20250929"
+// become "claude-sonnet-4-5@20250929", everything else passes through unchanged.
+func resolveModelID(model string, config *domain.ProviderConfigVertex) string {
+	if mapped, ok := config.ModelMapping[model]; ok && mapped != "" {
+		return mapped
+	}
+	if isClaudeModel(model) && !strings.Contains(model, "@") {
+		return claudeDateSuffix.ReplaceAllString(model, "@$1")
+	}
+	return model
+}
+
+// baseURL returns the API root for a region; "global" uses the regionless host
+func baseURL(config *domain.ProviderConfigVertex, region string) string {
+	if endpoint := strings.TrimSuffix(config.Endpoint, "/"); endpoint != "" {
+		return endpoint
+	}
+	if region == "global" {
+		return "https://aiplatform.googleapis.com"
+	}
+	return fmt.Sprintf("https://%s-aiplatform.googleapis.com", region)
+}
+
+// buildURL returns the publisher model URL for an action (generateContent, streamRawPredict, ...)
+func buildURL(config *domain.ProviderConfigVertex, project, region, publisher, modelID, action string) string {
+	url := fmt.Sprintf("%s/v1/projects/%s/locations/%s/publishers/%s/models/%s:%s",
+		baseURL(config, region), project, region, publisher, modelID, action)
+	if action == "streamGenerateContent" {
+		url += "?alt=sse"
+	}
+	return url
+}
+
+// geminiAction extracts the method from a Gemini request URI
+// (e.g. /v1beta/models/gemini-2.5-pro:streamGenerateContent?alt=sse → streamGenerateContent)
+func geminiAction(requestURI string, stream bool) string {
+	path, _, _ := strings.Cut(requestURI, "?")
+	if idx := strings.LastIndex(path, ":"); idx >= 0 && !strings.Contains(path[idx:], "/") {
+		return path[idx+1:]
+	}
+	if stream {
+		return "streamGenerateContent"
+	}
+	return "generateContent"
+}
+
+// buildClaudeRequestBody converts a Claude Messages request into an Anthropic-on-Vertex body:
+// the model moves to the URL and anthropic_version is required
+func buildClaudeRequestBody(body []byte) ([]byte, error) {
+	if !gjson.ValidBytes(body) {
+		return nil, fmt.Errorf("invalid JSON request body")
+	}
+	body, err := sjson.DeleteBytes(body, "model")
+	if err != nil {
+		return nil, err
+	}
+	return sjson.SetBytes(body, "anthropic_version", vertexAnthropicVersion)
+}
+
+// filterBetas drops anthropic-beta values that only apply to first-party Claude API auth
+func filterBetas(headers http.Header) []string {
+	var betas []string
+	for _, value := range headers.Values("anthropic-beta") {
+		for _, beta := range strings.Split(value, ",") {
+			beta = strings.TrimSpace(beta)
+			if beta == "" || strings.HasPrefix(beta, "oauth-") || strings.HasPrefix(beta, "claude-code-") {
+				continue
+			}
+			betas = append(betas, beta)
+		}
+	}
+	return betas
+}
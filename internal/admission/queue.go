@@ -0,0 +1,317 @@
+package admission
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+var (
+	ErrQueueFull    = errors.New("admission queue is full")
+	ErrQueueTimeout = errors.New("admission queue wait timeout")
+)
+
+const (
+	DefaultMaxLength = 100
+	DefaultMaxWait   = 30 * time.Second
+
+	// 冷却结束后按优先级错开唤醒，让高优先级请求先拿到恢复的路由
+	cooldownWakeStagger = 100 * time.Millisecond
+
+	// 等待时间低于该值视为未排队
+	queuedThreshold = time.Millisecond
+)
+
+var classes = []domain.PriorityClass{domain.PriorityInteractive, domain.PriorityNormal, domain.PriorityBatch}
+
+// Config 准入队列配置，从系统设置读取
+type Config struct {
+	Enabled        bool
+	MaxLength      int
+	MaxWait        time.Duration
+	MaxConcurrency int // 0 表示不限制并发，只在路由全部冷却时排队
+}
+
+// waiter 等待并发槽位的请求
+type waiter struct {
+	rank    int
+	seq     uint64
+	ready   chan struct{}
+	granted bool
+	index   int
+}
+
+type waiterHeap []*waiter
+
+func (h waiterHeap) Len() int { return len(h) }
+func (h waiterHeap) Less(i, j int) bool {
+	if h[i].rank != h[j].rank {
+		return h[i].rank < h[j].rank
+	}
+	return h[i].seq < h[j].seq
+}
+func (h waiterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *waiterHeap) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+func (h *waiterHeap) Pop() any {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*h = old[:n-1]
+	return w
+}
+
+type classCounters struct {
+	waiting   int
+	admitted  uint64
+	queued    uint64
+	rejected  uint64
+	timedOut  uint64
+	totalWait time.Duration
+	maxWait   time.Duration
+}
+
+// Queue 准入队列：路由全部冷却或并发已满时，请求在有限时间内按优先级排队等待，
+// 而不是立即返回 ErrNoRoutes
+type Queue struct {
+	settings repository.SystemSettingRepository
+	projects repository.ProjectRepository
+
+	mu             sync.Mutex
+	maxConcurrency int
+	inFlight       int
+	waiters        waiterHeap
+	seq            uint64
+	counters       map[domain.PriorityClass]*classCounters
+}
+
+// NewQueue creates an admission queue
+func NewQueue(settings repository.SystemSettingRepository, projects repository.ProjectRepository) *Queue {
+	q := &Queue{
+		settings: settings,
+		projects: projects,
+		counters: make(map[domain.PriorityClass]*classCounters),
+	}
+	for _, c := range classes {
+		q.counters[c] = &classCounters{}
+	}
+	return q
+}
+
+// Config reads the queue configuration, invalid values fall back to defaults
+func (q *Queue) Config() Config {
+	cfg := Config{MaxLength: DefaultMaxLength, MaxWait: DefaultMaxWait}
+	if q.settings == nil {
+		return cfg
+	}
+	get := func(key string) string {
+		v, err := q.settings.Get(key)
+		if err != nil {
+			return ""
+		}
+		return v
+	}
+	cfg.Enabled = get(domain.SettingKeyQueueEnabled) == "true"
+	if v, err := strconv.Atoi(get(domain.SettingKeyQueueMaxLength)); err == nil && v >= 0 {
+		cfg.MaxLength = v
+	}
+	if v, err := strconv.Atoi(get(domain.SettingKeyQueueMaxWaitSeconds)); err == nil && v > 0 {
+		cfg.MaxWait = time.Duration(v) * time.Second
+	}
+	if v, err := strconv.Atoi(get(domain.SettingKeyQueueMaxConcurrency)); err == nil && v > 0 {
+		cfg.MaxConcurrency = v
+	}
+	return cfg
+}
+
+// ClassFor resolves the priority class of a request: API token first, then project, then normal
+func (q *Queue) ClassFor(tokenClass domain.PriorityClass, projectID uint64) domain.PriorityClass {
+	if tokenClass != "" {
+		return tokenClass.Normalize()
+	}
+	if projectID > 0 && q.projects != nil {
+		if project, err := q.projects.GetByID(projectID); err == nil && project != nil {
+			return project.Priority.Normalize()
+		}
+	}
+	return domain.PriorityNormal
+}
+
+// WaitCooldown 所有路由都在冷却时等待最早的冷却结束
+// 冷却结束时间超过 deadline 时直接返回 ErrQueueTimeout，不做无意义的等待
+func (q *Queue) WaitCooldown(ctx context.Context, cfg Config, class domain.PriorityClass, until, deadline time.Time) error {
+	class = class.Normalize()
+	wake := until.Add(time.Duration(class.Rank()) * cooldownWakeStagger)
+
+	q.mu.Lock()
+	c := q.counters[class]
+	if wake.After(deadline) {
+		c.timedOut++
+		q.mu.Unlock()
+		return ErrQueueTimeout
+	}
+	if q.waitingLocked() >= cfg.MaxLength {
+		c.rejected++
+		q.mu.Unlock()
+		return ErrQueueFull
+	}
+	c.waiting++
+	q.mu.Unlock()
+
+	defer func() {
+		q.mu.Lock()
+		c.waiting--
+		q.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(time.Until(wake))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Acquire 获取并发槽位，并发已满时按优先级排队，返回的 release 必须在请求结束时调用
+// queuedSince 为请求开始排队的时间，统计的等待时间包括之前等待冷却的时间
+func (q *Queue) Acquire(ctx context.Context, cfg Config, class domain.PriorityClass, queuedSince, deadline time.Time) (release func(), err error) {
+	class = class.Normalize()
+
+	q.mu.Lock()
+	q.maxConcurrency = cfg.MaxConcurrency
+	// 并发上限调大或取消时先放行已在排队的请求
+	q.dispatchLocked()
+	c := q.counters[class]
+	if cfg.MaxConcurrency <= 0 || (q.inFlight < cfg.MaxConcurrency && len(q.waiters) == 0) {
+		q.inFlight++
+		q.recordAdmittedLocked(c, time.Since(queuedSince))
+		q.mu.Unlock()
+		return q.releaseFunc(), nil
+	}
+	if q.waitingLocked() >= cfg.MaxLength {
+		c.rejected++
+		q.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	q.seq++
+	w := &waiter{rank: class.Rank(), seq: q.seq, ready: make(chan struct{})}
+	heap.Push(&q.waiters, w)
+	c.waiting++
+	q.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timer.C:
+		err = ErrQueueTimeout
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	c.waiting--
+	if w.granted {
+		// 超时与放行同时发生时以放行为准
+		q.recordAdmittedLocked(c, time.Since(queuedSince))
+		return q.releaseFunc(), nil
+	}
+	heap.Remove(&q.waiters, w.index)
+	if errors.Is(err, ErrQueueTimeout) {
+		c.timedOut++
+	}
+	return nil, err
+}
+
+// Stats returns a snapshot of the queue
+func (q *Queue) Stats() *domain.AdmissionQueueStats {
+	cfg := q.Config()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := &domain.AdmissionQueueStats{
+		Enabled:        cfg.Enabled,
+		MaxLength:      cfg.MaxLength,
+		MaxConcurrency: cfg.MaxConcurrency,
+		InFlight:       q.inFlight,
+		Waiting:        q.waitingLocked(),
+	}
+	for _, class := range classes {
+		c := q.counters[class]
+		cs := &domain.AdmissionClassStats{
+			Class:     class,
+			Waiting:   c.waiting,
+			Admitted:  c.admitted,
+			Queued:    c.queued,
+			Rejected:  c.rejected,
+			TimedOut:  c.timedOut,
+			MaxWaitMs: c.maxWait.Milliseconds(),
+		}
+		if c.queued > 0 {
+			cs.AvgWaitMs = float64(c.totalWait.Milliseconds()) / float64(c.queued)
+		}
+		stats.Classes = append(stats.Classes, cs)
+	}
+	return stats
+}
+
+func (q *Queue) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			q.inFlight--
+			q.dispatchLocked()
+		})
+	}
+}
+
+// dispatchLocked 有空闲槽位时按优先级放行等待的请求
+func (q *Queue) dispatchLocked() {
+	for len(q.waiters) > 0 && (q.maxConcurrency <= 0 || q.inFlight < q.maxConcurrency) {
+		w := heap.Pop(&q.waiters).(*waiter)
+		w.granted = true
+		q.inFlight++
+		close(w.ready)
+	}
+}
+
+func (q *Queue) waitingLocked() int {
+	n := 0
+	for _, c := range q.counters {
+		n += c.waiting
+	}
+	return n
+}
+
+func (q *Queue) recordAdmittedLocked(c *classCounters, waited time.Duration) {
+	c.admitted++
+	if waited < queuedThreshold {
+		return
+	}
+	c.queued++
+	c.totalWait += waited
+	if waited > c.maxWait {
+		c.maxWait = waited
+	}
+}
@@ -0,0 +1,160 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/cooldown"
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+func TestQueuePriorityOrder(t *testing.T) {
+	q := NewQueue(nil, nil)
+	cfg := Config{Enabled: true, MaxLength: 10, MaxWait: time.Second, MaxConcurrency: 1}
+	ctx := context.Background()
+	now := time.Now()
+	deadline := now.Add(time.Second)
+
+	release, err := q.Acquire(ctx, cfg, domain.PriorityNormal, now, deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan domain.PriorityClass, 2)
+	start := func(class domain.PriorityClass) {
+		go func() {
+			rel, err := q.Acquire(ctx, cfg, class, time.Now(), deadline)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			order <- class
+			rel()
+		}()
+	}
+	// batch 先排队，interactive 后到但应先放行
+	start(domain.PriorityBatch)
+	waitForWaiting(t, q, 1)
+	start(domain.PriorityInteractive)
+	waitForWaiting(t, q, 2)
+
+	release()
+	if got := <-order; got != domain.PriorityInteractive {
+		t.Fatalf("expected interactive first, got %s", got)
+	}
+	if got := <-order; got != domain.PriorityBatch {
+		t.Fatalf("expected batch second, got %s", got)
+	}
+
+	stats := q.Stats()
+	if stats.InFlight != 0 || stats.Waiting != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	for _, c := range stats.Classes {
+		if c.Class == domain.PriorityBatch && (c.Queued != 1 || c.MaxWaitMs < 0) {
+			t.Fatalf("unexpected batch stats %+v", c)
+		}
+	}
+}
+
+func TestQueueLimits(t *testing.T) {
+	q := NewQueue(nil, nil)
+	cfg := Config{Enabled: true, MaxLength: 1, MaxWait: 50 * time.Millisecond, MaxConcurrency: 1}
+	ctx := context.Background()
+	now := time.Now()
+
+	release, err := q.Acquire(ctx, cfg, domain.PriorityNormal, now, now.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := q.Acquire(ctx, cfg, domain.PriorityNormal, time.Now(), time.Now().Add(50*time.Millisecond))
+		done <- err
+	}()
+	waitForWaiting(t, q, 1)
+
+	// 队列已满
+	if _, err := q.Acquire(ctx, cfg, domain.PriorityInteractive, now, now.Add(time.Second)); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	// 等待超时
+	if err := <-done; !errors.Is(err, ErrQueueTimeout) {
+		t.Fatalf("expected ErrQueueTimeout, got %v", err)
+	}
+	if q.Stats().Waiting != 0 {
+		t.Fatal("timed out waiter still counted")
+	}
+}
+
+func TestQueueWaitCooldown(t *testing.T) {
+	q := NewQueue(nil, nil)
+	cfg := Config{Enabled: true, MaxLength: 10, MaxWait: time.Second}
+	ctx := context.Background()
+	now := time.Now()
+
+	if err := q.WaitCooldown(ctx, cfg, domain.PriorityInteractive, now.Add(20*time.Millisecond), now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(now) < 20*time.Millisecond {
+		t.Fatal("returned before cooldown ended")
+	}
+	// 冷却结束晚于最长等待时间，立即失败
+	start := time.Now()
+	if err := q.WaitCooldown(ctx, cfg, domain.PriorityNormal, start.Add(time.Hour), start.Add(time.Second)); !errors.Is(err, ErrQueueTimeout) {
+		t.Fatalf("expected ErrQueueTimeout, got %v", err)
+	}
+	if time.Since(start) > 100*time.Millisecond {
+		t.Fatal("expected immediate failure")
+	}
+}
+
+// 熔断半开且试探名额已满时，交互请求的等待不能退化为无延迟的空转
+func TestQueueWaitCooldownHalfOpen(t *testing.T) {
+	m := cooldown.NewManager()
+	until := time.Now().Add(10 * time.Millisecond)
+	m.RecordFailure(1, "claude", "", cooldown.ReasonServerError, &until)
+	time.Sleep(20 * time.Millisecond)
+	trial, ok := m.AcquireTrial(1, "claude")
+	if !ok || trial == nil {
+		t.Fatal("expected a half-open trial slot")
+	}
+	defer trial.Release()
+
+	q := NewQueue(nil, nil)
+	cfg := Config{Enabled: true, MaxLength: 10, MaxWait: 200 * time.Millisecond}
+	start := time.Now()
+	deadline := start.Add(cfg.MaxWait)
+	waits := 0
+	for m.IsInCooldown(1, "claude", "") {
+		end := m.GetCooldownUntil(1, "claude", "")
+		if err := q.WaitCooldown(context.Background(), cfg, domain.PriorityInteractive, end, deadline); err != nil {
+			if !errors.Is(err, ErrQueueTimeout) {
+				t.Fatal(err)
+			}
+			break
+		}
+		waits++
+	}
+	if waits > 0 {
+		t.Fatalf("expected no busy re-checks before the half-open re-check time, got %d", waits)
+	}
+	if time.Since(start) > 100*time.Millisecond {
+		t.Fatal("expected an immediate timeout when the re-check time is past the deadline")
+	}
+}
+
+func waitForWaiting(t *testing.T, q *Queue, n int) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		if q.Stats().Waiting == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected %d waiting requests", n)
+}
@@ -0,0 +1,110 @@
+package anomaly
+
+import (
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// target 检测对象（API Token 或项目）
+type target struct {
+	dimension string
+	id        uint64
+}
+
+// usage 一段时间内的用量汇总
+type usage struct {
+	requests uint64
+	failed   uint64
+	tokens   uint64
+	cost     uint64
+	hours    int // 有请求的小时数（仅基线使用）
+}
+
+// finding 单个指标的异常
+type finding struct {
+	metric    string
+	current   float64
+	baseline  float64
+	threshold float64
+}
+
+// groupUsage 按 API Token 和项目汇总统计数据
+// countHours 为 true 时按小时桶统计活跃小时数，用于计算基线的平均每小时速率
+func groupUsage(stats []*domain.UsageStats, countHours bool) map[target]*usage {
+	result := make(map[target]*usage)
+	seen := make(map[target]map[time.Time]struct{})
+	add := func(t target, s *domain.UsageStats) {
+		u, ok := result[t]
+		if !ok {
+			u = &usage{}
+			result[t] = u
+		}
+		u.requests += s.TotalRequests
+		u.failed += s.FailedRequests
+		u.tokens += s.InputTokens + s.OutputTokens + s.CacheRead + s.CacheWrite
+		u.cost += s.Cost
+		if countHours && s.TotalRequests > 0 {
+			buckets, ok := seen[t]
+			if !ok {
+				buckets = make(map[time.Time]struct{})
+				seen[t] = buckets
+			}
+			if _, ok := buckets[s.TimeBucket]; !ok {
+				buckets[s.TimeBucket] = struct{}{}
+				u.hours++
+			}
+		}
+	}
+	for _, s := range stats {
+		if s.APITokenID > 0 {
+			add(target{dimension: domain.AnomalyDimensionAPIToken, id: s.APITokenID}, s)
+		}
+		if s.ProjectID > 0 {
+			add(target{dimension: domain.AnomalyDimensionProject, id: s.ProjectID}, s)
+		}
+	}
+	return result
+}
+
+// evaluate 比较检测窗口与基线
+// 速率类指标使用基线活跃小时的平均每小时速率，没有基线（新 Token/项目）时只检测错误率
+func evaluate(current *usage, baseline *usage, window time.Duration, cfg Config) []finding {
+	if current == nil || current.requests == 0 || current.requests < cfg.MinRequests {
+		return nil
+	}
+
+	var findings []finding
+	perHour := float64(time.Hour) / float64(window)
+
+	if cfg.RateFactor > 0 && baseline != nil && baseline.hours > 0 {
+		hours := float64(baseline.hours)
+		for _, m := range []struct {
+			metric            string
+			current, baseline uint64
+		}{
+			{domain.AnomalyMetricRequestRate, current.requests, baseline.requests},
+			{domain.AnomalyMetricTokenRate, current.tokens, baseline.tokens},
+			{domain.AnomalyMetricCostRate, current.cost, baseline.cost},
+		} {
+			cur := float64(m.current) * perHour
+			base := float64(m.baseline) / hours
+			if base > 0 && cur > base*cfg.RateFactor {
+				findings = append(findings, finding{metric: m.metric, current: cur, baseline: base, threshold: base * cfg.RateFactor})
+			}
+		}
+	}
+
+	if cfg.ErrorRate > 0 {
+		cur := float64(current.failed) / float64(current.requests) * 100
+		var base float64
+		if baseline != nil && baseline.requests > 0 {
+			base = float64(baseline.failed) / float64(baseline.requests) * 100
+		}
+		// 长期错误率本就偏高的对象不重复告警
+		if cur >= cfg.ErrorRate && cur > base {
+			findings = append(findings, finding{metric: domain.AnomalyMetricErrorRate, current: cur, baseline: base, threshold: cfg.ErrorRate})
+		}
+	}
+	return findings
+}
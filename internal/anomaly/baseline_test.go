@@ -0,0 +1,87 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+func TestGroupUsage(t *testing.T) {
+	h1 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	h2 := h1.Add(time.Hour)
+	stats := []*domain.UsageStats{
+		{TimeBucket: h1, APITokenID: 1, ProjectID: 7, TotalRequests: 10, FailedRequests: 1, InputTokens: 100, OutputTokens: 50, Cost: 1000},
+		{TimeBucket: h1, APITokenID: 1, ProjectID: 7, Model: "other", TotalRequests: 5, InputTokens: 10, CacheRead: 40, Cost: 500},
+		{TimeBucket: h2, APITokenID: 2, ProjectID: 7, TotalRequests: 20, Cost: 2000},
+		{TimeBucket: h2, TotalRequests: 99}, // 无 Token、无项目
+	}
+
+	got := groupUsage(stats, true)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 targets, got %d", len(got))
+	}
+	token1 := got[target{dimension: domain.AnomalyDimensionAPIToken, id: 1}]
+	if token1.requests != 15 || token1.failed != 1 || token1.tokens != 200 || token1.cost != 1500 || token1.hours != 1 {
+		t.Fatalf("token 1 = %+v", token1)
+	}
+	project := got[target{dimension: domain.AnomalyDimensionProject, id: 7}]
+	if project.requests != 35 || project.hours != 2 {
+		t.Fatalf("project = %+v", project)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	cfg := DefaultConfig()
+	window := 15 * time.Minute
+	// 基线：10 个活跃小时，平均每小时 100 请求、1% 错误率
+	baseline := &usage{requests: 1000, failed: 10, tokens: 100_000, cost: 1_000_000, hours: 10}
+
+	metrics := func(findings []finding) map[string]finding {
+		m := make(map[string]finding)
+		for _, f := range findings {
+			m[f.metric] = f
+		}
+		return m
+	}
+
+	// 15 分钟 50 请求 = 每小时 200，未超过 5 倍
+	if f := evaluate(&usage{requests: 50, tokens: 5000, cost: 50_000}, baseline, window, cfg); len(f) != 0 {
+		t.Fatalf("expected no findings, got %+v", f)
+	}
+
+	// 15 分钟 200 请求 = 每小时 800，成本 = 每小时 8 倍
+	got := metrics(evaluate(&usage{requests: 200, tokens: 20_000, cost: 2_000_000}, baseline, window, cfg))
+	if f, ok := got[domain.AnomalyMetricRequestRate]; !ok || f.current != 800 || f.baseline != 100 || f.threshold != 500 {
+		t.Fatalf("request rate finding = %+v", f)
+	}
+	if _, ok := got[domain.AnomalyMetricTokenRate]; !ok {
+		t.Fatal("expected token rate finding")
+	}
+	if f, ok := got[domain.AnomalyMetricCostRate]; !ok || f.current != 8_000_000 {
+		t.Fatalf("cost rate finding = %+v", f)
+	}
+
+	// 错误率
+	got = metrics(evaluate(&usage{requests: 40, failed: 30}, baseline, window, cfg))
+	if f, ok := got[domain.AnomalyMetricErrorRate]; !ok || f.current != 75 || f.baseline != 1 {
+		t.Fatalf("error rate finding = %+v", f)
+	}
+
+	// 请求数不足时不检测
+	if f := evaluate(&usage{requests: 10, failed: 10}, baseline, window, cfg); len(f) != 0 {
+		t.Fatalf("expected no findings below min requests, got %+v", f)
+	}
+
+	// 没有基线时只检测错误率
+	got = metrics(evaluate(&usage{requests: 1000, failed: 600}, nil, window, cfg))
+	if len(got) != 1 || got[domain.AnomalyMetricErrorRate].metric == "" {
+		t.Fatalf("expected only error rate without baseline, got %+v", got)
+	}
+
+	// 长期错误率偏高的对象不告警
+	noisy := &usage{requests: 1000, failed: 800, hours: 10}
+	if f := evaluate(&usage{requests: 40, failed: 30}, noisy, window, cfg); len(f) != 0 {
+		t.Fatalf("expected no findings for chronically failing target, got %+v", f)
+	}
+}
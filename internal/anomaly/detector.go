@@ -0,0 +1,303 @@
+package anomaly
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/event"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+const (
+	// 同一对象同一指标的告警间隔，避免持续异常时反复告警
+	alertSuppressDuration = time.Hour
+	// 基线按小时数据计算，每小时刷新一次即可
+	baselineRefreshInterval = time.Hour
+	// 保留在内存中的最近告警数
+	maxRecentAlerts = 100
+)
+
+// Config 异常检测配置，从系统设置读取
+type Config struct {
+	Enabled      bool
+	Window       time.Duration
+	BaselineDays int
+	MinRequests  uint64
+	RateFactor   float64 // 速率超过基线的倍数
+	ErrorRate    float64 // 错误率阈值（百分比）
+	WebhookURL   string
+	AutoDisable  bool
+}
+
+// DefaultConfig returns the default detection thresholds (disabled)
+func DefaultConfig() Config {
+	return Config{
+		Window:       15 * time.Minute,
+		BaselineDays: 7,
+		MinRequests:  20,
+		RateFactor:   5,
+		ErrorRate:    50,
+	}
+}
+
+// LoadConfig reads the configuration from system settings, invalid values fall back to defaults
+func LoadConfig(settings repository.SystemSettingRepository) Config {
+	cfg := DefaultConfig()
+	get := func(key string) string {
+		v, err := settings.Get(key)
+		if err != nil {
+			return ""
+		}
+		return v
+	}
+	cfg.Enabled = get(domain.SettingKeyAnomalyEnabled) == "true"
+	cfg.AutoDisable = get(domain.SettingKeyAnomalyAutoDisable) == "true"
+	cfg.WebhookURL = get(domain.SettingKeyAnomalyWebhookURL)
+	if v, err := strconv.Atoi(get(domain.SettingKeyAnomalyWindowMinutes)); err == nil && v > 0 {
+		cfg.Window = time.Duration(v) * time.Minute
+	}
+	if v, err := strconv.Atoi(get(domain.SettingKeyAnomalyBaselineDays)); err == nil && v > 0 {
+		cfg.BaselineDays = v
+	}
+	if v, err := strconv.ParseUint(get(domain.SettingKeyAnomalyMinRequests), 10, 64); err == nil {
+		cfg.MinRequests = v
+	}
+	if v, err := strconv.ParseFloat(get(domain.SettingKeyAnomalyRateFactor), 64); err == nil && v >= 0 {
+		cfg.RateFactor = v
+	}
+	if v, err := strconv.ParseFloat(get(domain.SettingKeyAnomalyErrorRate), 64); err == nil && v >= 0 {
+		cfg.ErrorRate = v
+	}
+	return cfg
+}
+
+// Detector 用量异常检测：按 API Token 和项目比较最近窗口与历史基线，
+// 超出阈值时通过 WebSocket 和 webhook 告警，并可自动禁用 API Token
+type Detector struct {
+	usageStats  repository.UsageStatsRepository
+	apiTokens   repository.APITokenRepository
+	projects    repository.ProjectRepository
+	settings    repository.SystemSettingRepository
+	broadcaster event.Broadcaster
+	httpClient  *http.Client
+
+	mu           sync.Mutex
+	baselines    map[target]*usage
+	baselineAt   time.Time
+	baselineDays int
+	lastAlert    map[alertKey]time.Time
+	recent       []*domain.AnomalyAlert
+}
+
+type alertKey struct {
+	target
+	metric string
+}
+
+// NewDetector creates an anomaly detector
+func NewDetector(
+	usageStats repository.UsageStatsRepository,
+	apiTokens repository.APITokenRepository,
+	projects repository.ProjectRepository,
+	settings repository.SystemSettingRepository,
+	broadcaster event.Broadcaster,
+) *Detector {
+	return &Detector{
+		usageStats:  usageStats,
+		apiTokens:   apiTokens,
+		projects:    projects,
+		settings:    settings,
+		broadcaster: broadcaster,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		lastAlert:   make(map[alertKey]time.Time),
+	}
+}
+
+// Run 执行一次检测，返回本次产生的告警（未启用时直接返回）
+func (d *Detector) Run(now time.Time) ([]*domain.AnomalyAlert, error) {
+	cfg := LoadConfig(d.settings)
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	windowStart := now.Add(-cfg.Window)
+	baselines, err := d.loadBaselines(now, windowStart, cfg.BaselineDays)
+	if err != nil {
+		return nil, fmt.Errorf("load baselines: %w", err)
+	}
+	stats, err := d.usageStats.Query(repository.UsageStatsFilter{
+		Granularity: domain.GranularityMinute,
+		StartTime:   &windowStart,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query window: %w", err)
+	}
+
+	var alerts []*domain.AnomalyAlert
+	for t, current := range groupUsage(stats, false) {
+		for _, f := range evaluate(current, baselines[t], cfg.Window, cfg) {
+			if !d.shouldAlert(alertKey{target: t, metric: f.metric}, now) {
+				continue
+			}
+			alerts = append(alerts, &domain.AnomalyAlert{
+				Time:       now,
+				Dimension:  t.dimension,
+				TargetID:   t.id,
+				TargetName: d.targetName(t),
+				Metric:     f.metric,
+				Current:    f.current,
+				Baseline:   f.baseline,
+				Threshold:  f.threshold,
+			})
+		}
+	}
+
+	disabled := make(map[uint64]bool)
+	for _, alert := range alerts {
+		if cfg.AutoDisable && alert.Dimension == domain.AnomalyDimensionAPIToken {
+			if !disabled[alert.TargetID] {
+				disabled[alert.TargetID] = d.disableToken(alert.TargetID)
+			}
+			alert.TokenDisabled = disabled[alert.TargetID]
+		}
+		d.raise(alert, cfg.WebhookURL)
+	}
+	return alerts, nil
+}
+
+// Recent returns the most recent alerts, newest first
+func (d *Detector) Recent() []*domain.AnomalyAlert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	result := make([]*domain.AnomalyAlert, len(d.recent))
+	for i, alert := range d.recent {
+		result[len(d.recent)-1-i] = alert
+	}
+	return result
+}
+
+// loadBaselines 返回基线（检测窗口之前 baselineDays 天的小时数据），每小时刷新
+func (d *Detector) loadBaselines(now, windowStart time.Time, days int) (map[target]*usage, error) {
+	d.mu.Lock()
+	if d.baselines != nil && d.baselineDays == days && now.Sub(d.baselineAt) < baselineRefreshInterval {
+		baselines := d.baselines
+		d.mu.Unlock()
+		return baselines, nil
+	}
+	d.mu.Unlock()
+
+	start := windowStart.AddDate(0, 0, -days)
+	end := windowStart.Truncate(time.Hour)
+	stats, err := d.usageStats.Query(repository.UsageStatsFilter{
+		Granularity: domain.GranularityHour,
+		StartTime:   &start,
+		EndTime:     &end,
+	})
+	if err != nil {
+		return nil, err
+	}
+	baselines := groupUsage(stats, true)
+
+	d.mu.Lock()
+	d.baselines = baselines
+	d.baselineAt = now
+	d.baselineDays = days
+	d.mu.Unlock()
+	return baselines, nil
+}
+
+func (d *Detector) shouldAlert(key alertKey, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if last, ok := d.lastAlert[key]; ok && now.Sub(last) < alertSuppressDuration {
+		return false
+	}
+	d.lastAlert[key] = now
+	return true
+}
+
+func (d *Detector) targetName(t target) string {
+	switch t.dimension {
+	case domain.AnomalyDimensionAPIToken:
+		if token, err := d.apiTokens.GetByID(t.id); err == nil {
+			return token.Name
+		}
+	case domain.AnomalyDimensionProject:
+		if project, err := d.projects.GetByID(t.id); err == nil {
+			return project.Name
+		}
+	}
+	return fmt.Sprintf("#%d", t.id)
+}
+
+// disableToken 禁用触发告警的 API Token，返回是否由本次禁用
+func (d *Detector) disableToken(id uint64) bool {
+	cached, err := d.apiTokens.GetByID(id)
+	if err != nil || !cached.IsEnabled {
+		return false
+	}
+	// 缓存仓库返回共享指针，修改副本，更新失败时缓存保持不变
+	token := *cached
+	token.IsEnabled = false
+	if err := d.apiTokens.Update(&token); err != nil {
+		log.Printf("[Anomaly] Failed to disable api token %d: %v", id, err)
+		return false
+	}
+	log.Printf("[Anomaly] Disabled api token %d (%s)", id, token.Name)
+	return true
+}
+
+func (d *Detector) raise(alert *domain.AnomalyAlert, webhookURL string) {
+	log.Printf("[Anomaly] %s %s (#%d) %s: current=%.2f baseline=%.2f threshold=%.2f",
+		alert.Dimension, alert.TargetName, alert.TargetID, alert.Metric, alert.Current, alert.Baseline, alert.Threshold)
+
+	d.mu.Lock()
+	d.recent = append(d.recent, alert)
+	if len(d.recent) > maxRecentAlerts {
+		d.recent = d.recent[len(d.recent)-maxRecentAlerts:]
+	}
+	d.mu.Unlock()
+
+	if d.broadcaster != nil {
+		d.broadcaster.BroadcastMessage("anomaly_alert", alert)
+	}
+	if webhookURL != "" {
+		if err := d.sendWebhook(webhookURL, alert); err != nil {
+			log.Printf("[Anomaly] Failed to send webhook: %v", err)
+		}
+	}
+}
+
+// webhookPayload is POSTed to the anomaly webhook for each alert
+type webhookPayload struct {
+	Event string               `json:"event"`
+	Alert *domain.AnomalyAlert `json:"alert"`
+}
+
+func (d *Detector) sendWebhook(url string, alert *domain.AnomalyAlert) error {
+	body, err := json.Marshal(webhookPayload{Event: "anomaly_alert", Alert: alert})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package billing
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+// Engine 根据计费规则计算向客户计费的金额
+// 规则缓存在内存中，规则变更后需调用 Reload；免费额度的已用量按规则和自然月在内存中累计，
+// 首次使用时从 usage_stats 推算，因此重启后不会重复发放免费额度
+type Engine struct {
+	mu        sync.RWMutex
+	byToken   map[uint64]*domain.BillingRule
+	byProject map[uint64]*domain.BillingRule
+	freeUsed  map[freeTierKey]uint64 // 当月已消耗的计费金额（抵扣免费额度前）
+
+	rules      repository.BillingRuleRepository
+	usageStats repository.UsageStatsRepository
+	settings   repository.SystemSettingRepository
+}
+
+type freeTierKey struct {
+	ruleID uint64
+	month  string // 2006-01
+}
+
+// NewEngine creates a billing engine without any rules
+func NewEngine() *Engine {
+	return &Engine{
+		byToken:   make(map[uint64]*domain.BillingRule),
+		byProject: make(map[uint64]*domain.BillingRule),
+		freeUsed:  make(map[freeTierKey]uint64),
+	}
+}
+
+// Default global engine
+var defaultEngine = NewEngine()
+
+// Default returns the default global billing engine
+func Default() *Engine {
+	return defaultEngine
+}
+
+// SetRepositories sets the repositories used to load rules and restore free tier usage
+func (e *Engine) SetRepositories(rules repository.BillingRuleRepository, usageStats repository.UsageStatsRepository, settings repository.SystemSettingRepository) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = rules
+	e.usageStats = usageStats
+	e.settings = settings
+}
+
+// Reload loads enabled rules from the repository
+func (e *Engine) Reload() error {
+	e.mu.RLock()
+	repo := e.rules
+	e.mu.RUnlock()
+	if repo == nil {
+		return nil
+	}
+
+	rules, err := repo.List()
+	if err != nil {
+		return err
+	}
+	e.SetRules(rules)
+	log.Printf("[Billing] Loaded %d billing rules", len(rules))
+	return nil
+}
+
+// SetRules replaces the cached rules, disabled rules are ignored
+func (e *Engine) SetRules(rules []*domain.BillingRule) {
+	byToken := make(map[uint64]*domain.BillingRule)
+	byProject := make(map[uint64]*domain.BillingRule)
+	for _, r := range rules {
+		if !r.IsEnabled || r.DeletedAt != nil {
+			continue
+		}
+		if r.APITokenID > 0 {
+			byToken[r.APITokenID] = r
+		} else if r.ProjectID > 0 {
+			byProject[r.ProjectID] = r
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.byToken = byToken
+	e.byProject = byProject
+}
+
+// Match returns the rule applied to a request, API Token rules take precedence over project rules
+func (e *Engine) Match(projectID, apiTokenID uint64) *domain.BillingRule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.matchLocked(projectID, apiTokenID)
+}
+
+func (e *Engine) matchLocked(projectID, apiTokenID uint64) *domain.BillingRule {
+	if apiTokenID > 0 {
+		if r, ok := e.byToken[apiTokenID]; ok {
+			return r
+		}
+	}
+	if projectID > 0 {
+		if r, ok := e.byProject[projectID]; ok {
+			return r
+		}
+	}
+	return nil
+}
+
+// Bill 计算一次尝试的计费金额并消耗免费额度
+// cost: 已应用倍率的成本；completed: 是否成功（固定费用只对成功请求收取）
+// 只有成功的尝试抵扣并消耗免费额度，失败重试不会把免费额度用光
+func (e *Engine) Bill(projectID, apiTokenID, cost uint64, completed bool, at time.Time) uint64 {
+	rule := e.Match(projectID, apiTokenID)
+	if rule == nil {
+		return cost
+	}
+	billed := rule.Quote(cost, completed)
+	if rule.FreeTierMonthly == 0 || billed == 0 || !completed {
+		return billed
+	}
+
+	monthStart := e.monthStart(at)
+	key := freeTierKey{ruleID: rule.ID, month: monthStart.Format("2006-01")}
+
+	e.mu.RLock()
+	_, loaded := e.freeUsed[key]
+	e.mu.RUnlock()
+	var restored uint64
+	if !loaded {
+		// 在锁外查询统计数据，避免阻塞其他请求
+		restored = e.restoreFreeUsage(rule, monthStart)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	used, ok := e.freeUsed[key]
+	if !ok {
+		used = restored
+	}
+	e.freeUsed[key] = used + billed
+
+	if used >= rule.FreeTierMonthly {
+		return billed
+	}
+	remaining := rule.FreeTierMonthly - used
+	if billed <= remaining {
+		return 0
+	}
+	return billed - remaining
+}
+
+// Reprice 成本重算后调整计费金额：按当前规则把成本差额折算到原计费金额上，
+// 固定费用和已抵扣的免费额度保持不变
+func (e *Engine) Reprice(projectID, apiTokenID, oldCost, oldBilled, newCost uint64) uint64 {
+	rule := e.Match(projectID, apiTokenID)
+	if rule == nil {
+		return newCost
+	}
+	billed := int64(oldBilled) + int64(rule.Quote(newCost, false)) - int64(rule.Quote(oldCost, false))
+	if billed < 0 {
+		return 0
+	}
+	return uint64(billed)
+}
+
+// ResetFreeTier 清空免费额度用量缓存（规则变更后重新从统计数据推算）
+func (e *Engine) ResetFreeTier(ruleID uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for key := range e.freeUsed {
+		if key.ruleID == ruleID {
+			delete(e.freeUsed, key)
+		}
+	}
+}
+
+// restoreFreeUsage 按当月统计数据推算规则已消耗的计费金额
+// 项目规则的统计中可能包含使用自身 Token 规则的请求，失败尝试的成本也计算在内，
+// 这些部分会被高估（对免费额度而言更保守）
+func (e *Engine) restoreFreeUsage(rule *domain.BillingRule, monthStart time.Time) uint64 {
+	e.mu.RLock()
+	repo := e.usageStats
+	e.mu.RUnlock()
+	if repo == nil {
+		return 0
+	}
+
+	filter := repository.UsageStatsFilter{
+		Granularity: domain.GranularityMinute,
+		StartTime:   &monthStart,
+	}
+	if rule.APITokenID > 0 {
+		filter.APITokenID = &rule.APITokenID
+	} else {
+		filter.ProjectID = &rule.ProjectID
+	}
+	summary, err := repo.GetSummary(filter)
+	if err != nil {
+		log.Printf("[Billing] Failed to restore free tier usage for rule %d: %v", rule.ID, err)
+		return 0
+	}
+	return rule.Quote(summary.TotalCost, false) + rule.RequestFee*summary.SuccessfulRequests
+}
+
+// monthStart 返回 at 所在自然月的开始时间（使用配置的时区，与 usage_stats 保持一致）
+func (e *Engine) monthStart(at time.Time) time.Time {
+	at = at.In(e.location())
+	return time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, at.Location())
+}
+
+func (e *Engine) location() *time.Location {
+	e.mu.RLock()
+	settings := e.settings
+	e.mu.RUnlock()
+	name := "Asia/Shanghai"
+	if settings != nil {
+		if v, err := settings.Get(domain.SettingKeyTimezone); err == nil && v != "" {
+			name = v
+		}
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone("UTC+8", 8*60*60)
+	}
+	return loc
+}
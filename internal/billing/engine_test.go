@@ -0,0 +1,95 @@
+package billing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+func TestEngineMatchPrecedence(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]*domain.BillingRule{
+		{ID: 1, ProjectID: 3, MarkupBps: 1000, IsEnabled: true},
+		{ID: 2, APITokenID: 9, MarkupBps: 5000, IsEnabled: true},
+		{ID: 3, ProjectID: 4, MarkupBps: 5000},
+	})
+
+	if r := e.Match(3, 9); r == nil || r.ID != 2 {
+		t.Fatalf("expected token rule, got %+v", r)
+	}
+	if r := e.Match(3, 8); r == nil || r.ID != 1 {
+		t.Fatalf("expected project rule, got %+v", r)
+	}
+	if r := e.Match(4, 0); r != nil {
+		t.Fatalf("disabled rule should not match, got %+v", r)
+	}
+	if billed := e.Bill(0, 0, 1000, true, time.Now()); billed != 1000 {
+		t.Fatalf("expected billed = cost without rule, got %d", billed)
+	}
+}
+
+func TestEngineBill(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]*domain.BillingRule{
+		{ID: 1, ProjectID: 1, MarkupBps: 2000, RequestFee: 100, IsEnabled: true},
+		{ID: 2, ProjectID: 2, MarkupBps: -5000, IsEnabled: true},
+	})
+
+	// +20% 并加收固定费用
+	if billed := e.Bill(1, 0, 1000, true, time.Now()); billed != 1300 {
+		t.Fatalf("billed = %d, want 1300", billed)
+	}
+	// 失败请求不收固定费用
+	if billed := e.Bill(1, 0, 1000, false, time.Now()); billed != 1200 {
+		t.Fatalf("billed = %d, want 1200", billed)
+	}
+	// 折扣
+	if billed := e.Bill(2, 0, 1000, true, time.Now()); billed != 500 {
+		t.Fatalf("billed = %d, want 500", billed)
+	}
+}
+
+func TestEngineFreeTier(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]*domain.BillingRule{
+		{ID: 1, APITokenID: 5, FreeTierMonthly: 2500, IsEnabled: true},
+	})
+	loc := e.location()
+	jan := time.Date(2026, 1, 10, 12, 0, 0, 0, loc)
+
+	for i, want := range []uint64{0, 0, 500, 1000} {
+		// 失败的尝试照常计费，但不消耗免费额度
+		if billed := e.Bill(0, 5, 300, false, jan); billed != 300 {
+			t.Fatalf("failed attempt %d billed = %d, want 300", i, billed)
+		}
+		if billed := e.Bill(0, 5, 1000, true, jan); billed != want {
+			t.Fatalf("request %d billed = %d, want %d", i, billed, want)
+		}
+	}
+
+	// 新的自然月重新计算免费额度
+	feb := time.Date(2026, 2, 1, 0, 0, 0, 0, loc)
+	if billed := e.Bill(0, 5, 1000, true, feb); billed != 0 {
+		t.Fatalf("expected free tier to reset in a new month, got %d", billed)
+	}
+}
+
+func TestEngineReprice(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]*domain.BillingRule{
+		{ID: 1, ProjectID: 1, MarkupBps: 2000, RequestFee: 100, IsEnabled: true},
+	})
+
+	// 原成本 1000 计费 1300，成本改为 2000 后只调整加价部分
+	if billed := e.Reprice(1, 0, 1000, 1300, 2000); billed != 2500 {
+		t.Fatalf("billed = %d, want 2500", billed)
+	}
+	// 免费额度内的请求（计费 0）成本降低时不会变为负数
+	if billed := e.Reprice(1, 0, 1000, 0, 500); billed != 0 {
+		t.Fatalf("billed = %d, want 0", billed)
+	}
+	if billed := e.Reprice(2, 0, 1000, 1300, 2000); billed != 2000 {
+		t.Fatalf("billed = %d, want cost without rule", billed)
+	}
+}
@@ -0,0 +1,106 @@
+package conversation
+
+import (
+	"sort"
+
+	"github.com/tidwall/gjson"
+)
+
+func parseClaudeRequest(root gjson.Result) *Request {
+	req := &Request{System: textOf(root.Get("system"))}
+	for _, m := range root.Get("messages").Array() {
+		req.Messages = append(req.Messages, claudeMessage(m.Get("role").String(), m.Get("content")))
+	}
+	return req
+}
+
+// claudeMessage converts string content or content blocks
+func claudeMessage(role string, content gjson.Result) Message {
+	msg := Message{Role: role}
+	if content.Type == gjson.String {
+		msg.Parts = appendText(msg.Parts, PartText, content.String())
+		return msg
+	}
+	for _, block := range content.Array() {
+		switch block.Get("type").String() {
+		case "text":
+			msg.Parts = appendText(msg.Parts, PartText, block.Get("text").String())
+		case "thinking":
+			msg.Parts = appendText(msg.Parts, PartThinking, block.Get("thinking").String())
+		case "image":
+			msg.Parts = append(msg.Parts, Part{Type: PartImage, Text: block.Get("source.media_type").String()})
+		case "tool_use", "server_tool_use":
+			msg.Parts = append(msg.Parts, Part{
+				Type:       PartToolCall,
+				ToolCallID: block.Get("id").String(),
+				ToolName:   block.Get("name").String(),
+				Input:      rawOrEmpty(block.Get("input")),
+			})
+		case "tool_result":
+			msg.Parts = append(msg.Parts, Part{
+				Type:       PartToolResult,
+				ToolCallID: block.Get("tool_use_id").String(),
+				Text:       textOf(block.Get("content")),
+				IsError:    block.Get("is_error").Bool(),
+			})
+		}
+	}
+	return msg
+}
+
+// claudeStreamMessage rebuilds the assistant message from content_block_* events
+func claudeStreamMessage(events []gjson.Result) Message {
+	type block struct {
+		part  Part
+		input string
+	}
+	blocks := map[int64]*block{}
+	for _, ev := range events {
+		switch ev.Get("type").String() {
+		case "content_block_start":
+			cb := ev.Get("content_block")
+			b := &block{}
+			switch cb.Get("type").String() {
+			case "text":
+				b.part = Part{Type: PartText, Text: cb.Get("text").String()}
+			case "thinking":
+				b.part = Part{Type: PartThinking, Text: cb.Get("thinking").String()}
+			case "tool_use", "server_tool_use":
+				b.part = Part{Type: PartToolCall, ToolCallID: cb.Get("id").String(), ToolName: cb.Get("name").String()}
+			default:
+				continue
+			}
+			blocks[ev.Get("index").Int()] = b
+		case "content_block_delta":
+			b, ok := blocks[ev.Get("index").Int()]
+			if !ok {
+				continue
+			}
+			delta := ev.Get("delta")
+			switch delta.Get("type").String() {
+			case "text_delta":
+				b.part.Text += delta.Get("text").String()
+			case "thinking_delta":
+				b.part.Text += delta.Get("thinking").String()
+			case "input_json_delta":
+				b.input += delta.Get("partial_json").String()
+			}
+		}
+	}
+
+	indexes := make([]int64, 0, len(blocks))
+	for i := range blocks {
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	msg := Message{Role: RoleAssistant}
+	for _, i := range indexes {
+		b := blocks[i]
+		if b.part.Type == PartToolCall {
+			b.part.Input = b.input
+		}
+		msg.Parts = append(msg.Parts, b.part)
+	}
+	return msg
+}
@@ -0,0 +1,123 @@
+package conversation
+
+import (
+	"github.com/tidwall/gjson"
+)
+
+// parseCodexRequest handles the Responses API (instructions + input items)
+func parseCodexRequest(root gjson.Result) *Request {
+	req := &Request{}
+	systems := []string{root.Get("instructions").String()}
+	input := root.Get("input")
+	if input.Type == gjson.String {
+		req.Messages = []Message{{Role: RoleUser, Parts: appendText(nil, PartText, input.String())}}
+		req.System = joinNonEmpty(systems)
+		return req
+	}
+
+	var items []gjson.Result
+	for _, item := range input.Array() {
+		if role := item.Get("role").String(); role == "system" || role == "developer" {
+			systems = append(systems, textOf(item.Get("content")))
+			continue
+		}
+		items = append(items, item)
+	}
+	req.System = joinNonEmpty(systems)
+	req.Messages = codexItemList(items)
+	return req
+}
+
+func codexItems(output gjson.Result) []Message {
+	return codexItemList(output.Array())
+}
+
+// codexItemList converts Responses API items, merging consecutive items of the same role
+func codexItemList(items []gjson.Result) []Message {
+	var messages []Message
+	add := func(role string, parts ...Part) {
+		if n := len(messages); n > 0 && messages[n-1].Role == role {
+			messages[n-1].Parts = append(messages[n-1].Parts, parts...)
+			return
+		}
+		messages = append(messages, Message{Role: role, Parts: parts})
+	}
+
+	for _, item := range items {
+		switch item.Get("type").String() {
+		case "message", "":
+			role := item.Get("role").String()
+			if role == "" {
+				role = RoleUser
+			}
+			var parts []Part
+			content := item.Get("content")
+			if content.Type == gjson.String {
+				parts = appendText(parts, PartText, content.String())
+			}
+			for _, c := range content.Array() {
+				switch c.Get("type").String() {
+				case "input_text", "output_text", "text":
+					parts = appendText(parts, PartText, c.Get("text").String())
+				case "input_image":
+					parts = append(parts, Part{Type: PartImage})
+				}
+			}
+			if len(parts) > 0 {
+				add(role, parts...)
+			}
+		case "reasoning":
+			var text []string
+			for _, s := range item.Get("summary").Array() {
+				text = append(text, s.Get("text").String())
+			}
+			if t := joinNonEmpty(text); t != "" {
+				add(RoleAssistant, Part{Type: PartThinking, Text: t})
+			}
+		case "function_call", "custom_tool_call":
+			input := item.Get("arguments").String()
+			if input == "" {
+				input = item.Get("input").String()
+			}
+			add(RoleAssistant, Part{
+				Type:       PartToolCall,
+				ToolCallID: item.Get("call_id").String(),
+				ToolName:   item.Get("name").String(),
+				Input:      input,
+			})
+		case "function_call_output", "custom_tool_call_output":
+			add(RoleUser, Part{
+				Type:       PartToolResult,
+				ToolCallID: item.Get("call_id").String(),
+				Text:       textOf(item.Get("output")),
+			})
+		}
+	}
+	return messages
+}
+
+// codexStreamMessages prefers the final response.completed payload, then
+// finished output items, and finally the raw text deltas
+func codexStreamMessages(events []gjson.Result) []Message {
+	var done []gjson.Result
+	text := ""
+	for _, ev := range events {
+		switch ev.Get("type").String() {
+		case "response.completed", "response.incomplete":
+			if output := ev.Get("response.output"); len(output.Array()) > 0 {
+				return codexItems(output)
+			}
+		case "response.output_item.done":
+			done = append(done, ev.Get("item"))
+		case "response.output_text.delta":
+			text += ev.Get("delta").String()
+		}
+	}
+	if len(done) > 0 {
+		return codexItemList(done)
+	}
+	if text != "" {
+		return []Message{{Role: RoleAssistant, Parts: []Part{{Type: PartText, Text: text}}}}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Package conversation normalizes client request/response bodies (Claude, OpenAI,
+// Codex, Gemini) into a common message format, so a session can be replayed
+// without knowing which API dialect each request used.
+package conversation
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/tidwall/gjson"
+)
+
+// Roles
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Part types
+const (
+	PartText       = "text"
+	PartThinking   = "thinking"
+	PartImage      = "image"
+	PartToolCall   = "tool_call"
+	PartToolResult = "tool_result"
+)
+
+// ErrInvalidBody is returned when a body cannot be parsed (e.g. truncated)
+var ErrInvalidBody = errors.New("body is not valid JSON or SSE")
+
+// Part is one piece of message content
+type Part struct {
+	Type       string `json:"type"`
+	Text       string `json:"text,omitempty"`
+	ToolCallID string `json:"toolCallId,omitempty"`
+	ToolName   string `json:"toolName,omitempty"`
+	// Input holds tool call arguments as raw JSON
+	Input   string `json:"input,omitempty"`
+	IsError bool   `json:"isError,omitempty"`
+}
+
+// Message is a normalized chat message.
+// Tool results always belong to user messages, whatever the source dialect uses.
+type Message struct {
+	Role  string `json:"role"`
+	Parts []Part `json:"parts"`
+}
+
+// Request is a normalized client request
+type Request struct {
+	System   string    `json:"system,omitempty"`
+	Messages []Message `json:"messages"`
+}
+
+// ParseRequest normalizes a request body sent by the given client type
+func ParseRequest(clientType domain.ClientType, body string) (*Request, error) {
+	if !gjson.Valid(body) {
+		return nil, ErrInvalidBody
+	}
+	root := gjson.Parse(body)
+	switch clientType {
+	case domain.ClientTypeClaude:
+		return parseClaudeRequest(root), nil
+	case domain.ClientTypeOpenAI:
+		return parseOpenAIRequest(root), nil
+	case domain.ClientTypeCodex:
+		return parseCodexRequest(root), nil
+	case domain.ClientTypeGemini:
+		return parseGeminiRequest(root), nil
+	default:
+		return nil, errors.New("unsupported client type: " + string(clientType))
+	}
+}
+
+// ParseResponse normalizes a response body (JSON or SSE) returned to the given client type
+func ParseResponse(clientType domain.ClientType, body string) ([]Message, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, nil
+	}
+	if gjson.Valid(body) {
+		root := gjson.Parse(body)
+		switch clientType {
+		case domain.ClientTypeClaude:
+			return []Message{claudeMessage(RoleAssistant, root.Get("content"))}, nil
+		case domain.ClientTypeOpenAI:
+			return []Message{openAIResponseMessage(root)}, nil
+		case domain.ClientTypeCodex:
+			return codexItems(root.Get("output")), nil
+		case domain.ClientTypeGemini:
+			return []Message{geminiResponseMessage(root)}, nil
+		}
+		return nil, errors.New("unsupported client type: " + string(clientType))
+	}
+
+	events := sseData(body)
+	if len(events) == 0 {
+		return nil, ErrInvalidBody
+	}
+	switch clientType {
+	case domain.ClientTypeClaude:
+		return []Message{claudeStreamMessage(events)}, nil
+	case domain.ClientTypeOpenAI:
+		return []Message{openAIStreamMessage(events)}, nil
+	case domain.ClientTypeCodex:
+		return codexStreamMessages(events), nil
+	case domain.ClientTypeGemini:
+		return []Message{geminiStreamMessage(events)}, nil
+	}
+	return nil, errors.New("unsupported client type: " + string(clientType))
+}
+
+// NewMessages returns the messages added after the last assistant message,
+// i.e. what the user (or tool results) contributed in this turn.
+// Clients resend the whole history on every request, so this is the turn's input.
+func NewMessages(messages []Message) []Message {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == RoleAssistant {
+			return messages[i+1:]
+		}
+	}
+	return messages
+}
+
+// sseData returns the valid JSON data payloads of an SSE body
+func sseData(body string) []gjson.Result {
+	var events []gjson.Result
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "" || data == "[DONE]" || !gjson.Valid(data) {
+			continue
+		}
+		events = append(events, gjson.Parse(data))
+	}
+	return events
+}
+
+// appendText merges consecutive text/thinking chunks into one part
+func appendText(parts []Part, typ, text string) []Part {
+	if text == "" {
+		return parts
+	}
+	if n := len(parts); n > 0 && parts[n-1].Type == typ {
+		parts[n-1].Text += text
+		return parts
+	}
+	return append(parts, Part{Type: typ, Text: text})
+}
+
+// textOf flattens string or content-block arrays into plain text
+func textOf(v gjson.Result) string {
+	if v.Type == gjson.String {
+		return v.String()
+	}
+	if !v.IsArray() {
+		return v.Raw
+	}
+	var texts []string
+	for _, item := range v.Array() {
+		if item.Type == gjson.String {
+			texts = append(texts, item.String())
+		} else if t := item.Get("text"); t.Exists() {
+			texts = append(texts, t.String())
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func rawOrEmpty(v gjson.Result) string {
+	if !v.Exists() {
+		return ""
+	}
+	if v.Type == gjson.String {
+		return v.String()
+	}
+	return v.Raw
+}
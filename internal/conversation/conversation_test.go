@@ -0,0 +1,146 @@
+package conversation
+
+import (
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+func TestParseRequest_Claude(t *testing.T) {
+	body := `{
+		"system": [{"type":"text","text":"You are Claude Code"}],
+		"messages": [
+			{"role":"user","content":"list files"},
+			{"role":"assistant","content":[{"type":"text","text":"Running ls"},{"type":"tool_use","id":"tu_1","name":"Bash","input":{"command":"ls"}}]},
+			{"role":"user","content":[{"type":"tool_result","tool_use_id":"tu_1","content":"a.go\nb.go"}]}
+		]
+	}`
+	req, err := ParseRequest(domain.ClientTypeClaude, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.System != "You are Claude Code" || len(req.Messages) != 3 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	call := req.Messages[1].Parts[1]
+	if call.Type != PartToolCall || call.ToolName != "Bash" || call.Input != `{"command":"ls"}` {
+		t.Fatalf("unexpected tool call %+v", call)
+	}
+
+	input := NewMessages(req.Messages)
+	if len(input) != 1 || input[0].Parts[0].Type != PartToolResult || input[0].Parts[0].Text != "a.go\nb.go" {
+		t.Fatalf("unexpected new messages %+v", input)
+	}
+}
+
+func TestParseResponse_ClaudeStream(t *testing.T) {
+	body := "event: message_start\ndata: {\"type\":\"message_start\"}\n\n" +
+		"data: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"\"}}\n\n" +
+		"data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"Hel\"}}\n\n" +
+		"data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"lo\"}}\n\n" +
+		"data: {\"type\":\"content_block_start\",\"index\":1,\"content_block\":{\"type\":\"tool_use\",\"id\":\"tu_2\",\"name\":\"Read\"}}\n\n" +
+		"data: {\"type\":\"content_block_delta\",\"index\":1,\"delta\":{\"type\":\"input_json_delta\",\"partial_json\":\"{\\\"path\\\":\"}}\n\n" +
+		"data: {\"type\":\"content_block_delta\",\"index\":1,\"delta\":{\"type\":\"input_json_delta\",\"partial_json\":\"\\\"a.go\\\"}\"}}\n\n"
+	out, err := ParseResponse(domain.ClientTypeClaude, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := out[0].Parts
+	if len(parts) != 2 || parts[0].Text != "Hello" || parts[1].ToolName != "Read" || parts[1].Input != `{"path":"a.go"}` {
+		t.Fatalf("unexpected output %+v", parts)
+	}
+}
+
+func TestParseOpenAI(t *testing.T) {
+	body := `{"messages":[
+		{"role":"system","content":"be brief"},
+		{"role":"user","content":[{"type":"text","text":"weather?"}]},
+		{"role":"assistant","content":null,"tool_calls":[{"id":"c1","type":"function","function":{"name":"get_weather","arguments":"{}"}}]},
+		{"role":"tool","tool_call_id":"c1","content":"sunny"}
+	]}`
+	req, err := ParseRequest(domain.ClientTypeOpenAI, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.System != "be brief" || len(req.Messages) != 3 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if last := req.Messages[2]; last.Role != RoleUser || last.Parts[0].Type != PartToolResult {
+		t.Fatalf("tool message should become a user tool_result, got %+v", last)
+	}
+
+	stream := "data: {\"choices\":[{\"delta\":{\"content\":\"It is \"}}]}\n\n" +
+		"data: {\"choices\":[{\"delta\":{\"content\":\"sunny\"}}]}\n\n" +
+		"data: {\"choices\":[{\"delta\":{\"tool_calls\":[{\"index\":0,\"id\":\"c2\",\"function\":{\"name\":\"log\",\"arguments\":\"{\\\"a\\\"\"}}]}}]}\n\n" +
+		"data: {\"choices\":[{\"delta\":{\"tool_calls\":[{\"index\":0,\"function\":{\"arguments\":\":1}\"}}]}}]}\n\n" +
+		"data: [DONE]\n\n"
+	out, err := ParseResponse(domain.ClientTypeOpenAI, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := out[0].Parts
+	if len(parts) != 2 || parts[0].Text != "It is sunny" || parts[1].Input != `{"a":1}` {
+		t.Fatalf("unexpected output %+v", parts)
+	}
+}
+
+func TestParseCodex(t *testing.T) {
+	body := `{"instructions":"You are Codex","input":[
+		{"type":"message","role":"user","content":[{"type":"input_text","text":"fix the bug"}]},
+		{"type":"function_call","call_id":"fc1","name":"shell","arguments":"{\"cmd\":\"go test\"}"},
+		{"type":"function_call_output","call_id":"fc1","output":"ok"}
+	]}`
+	req, err := ParseRequest(domain.ClientTypeCodex, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.System != "You are Codex" || len(req.Messages) != 3 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if input := NewMessages(req.Messages); len(input) != 1 || input[0].Parts[0].Text != "ok" {
+		t.Fatalf("unexpected new messages %+v", input)
+	}
+
+	stream := "event: response.output_text.delta\ndata: {\"type\":\"response.output_text.delta\",\"delta\":\"partial\"}\n\n" +
+		"event: response.completed\ndata: {\"type\":\"response.completed\",\"response\":{\"output\":[{\"type\":\"message\",\"role\":\"assistant\",\"content\":[{\"type\":\"output_text\",\"text\":\"done\"}]}]}}\n\n"
+	out, err := ParseResponse(domain.ClientTypeCodex, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out[0].Parts[0].Text != "done" {
+		t.Fatalf("unexpected output %+v", out)
+	}
+}
+
+func TestParseGemini(t *testing.T) {
+	body := `{"systemInstruction":{"parts":[{"text":"sys"}]},"contents":[
+		{"role":"user","parts":[{"text":"hi"}]},
+		{"role":"model","parts":[{"functionCall":{"name":"lookup","args":{"q":"x"}}}]},
+		{"role":"user","parts":[{"functionResponse":{"name":"lookup","response":{"result":"y"}}}]}
+	]}`
+	req, err := ParseRequest(domain.ClientTypeGemini, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.System != "sys" || req.Messages[1].Role != RoleAssistant || req.Messages[1].Parts[0].Input != `{"q":"x"}` {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	stream := "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"thinking\",\"thought\":true}]}}]}\n\n" +
+		"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"Hel\"}]}}]}\n\n" +
+		"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"lo\"}]}}]}\n\n"
+	out, err := ParseResponse(domain.ClientTypeGemini, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := out[0].Parts
+	if len(parts) != 2 || parts[0].Type != PartThinking || parts[1].Text != "Hello" {
+		t.Fatalf("unexpected output %+v", parts)
+	}
+}
+
+func TestParseRequest_Truncated(t *testing.T) {
+	if _, err := ParseRequest(domain.ClientTypeClaude, `{"messages":[{"role":"user"`); err != ErrInvalidBody {
+		t.Fatalf("expected ErrInvalidBody, got %v", err)
+	}
+}
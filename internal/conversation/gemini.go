@@ -0,0 +1,87 @@
+package conversation
+
+import (
+	"github.com/tidwall/gjson"
+)
+
+func parseGeminiRequest(root gjson.Result) *Request {
+	// CLI 代理格式会把真实请求包在 request 字段中
+	if inner := root.Get("request"); inner.IsObject() {
+		root = inner
+	}
+	req := &Request{}
+	system := root.Get("systemInstruction")
+	if !system.Exists() {
+		system = root.Get("system_instruction")
+	}
+	req.System = textOf(system.Get("parts"))
+	for _, c := range root.Get("contents").Array() {
+		role := RoleUser
+		if c.Get("role").String() == "model" {
+			role = RoleAssistant
+		}
+		req.Messages = append(req.Messages, geminiMessage(role, c.Get("parts")))
+	}
+	return req
+}
+
+func geminiMessage(role string, parts gjson.Result) Message {
+	msg := Message{Role: role}
+	for _, p := range parts.Array() {
+		switch {
+		case p.Get("functionCall").Exists():
+			call := p.Get("functionCall")
+			msg.Parts = append(msg.Parts, Part{
+				Type:       PartToolCall,
+				ToolCallID: call.Get("id").String(),
+				ToolName:   call.Get("name").String(),
+				Input:      rawOrEmpty(call.Get("args")),
+			})
+		case p.Get("functionResponse").Exists():
+			resp := p.Get("functionResponse")
+			msg.Parts = append(msg.Parts, Part{
+				Type:       PartToolResult,
+				ToolCallID: resp.Get("id").String(),
+				ToolName:   resp.Get("name").String(),
+				Text:       rawOrEmpty(resp.Get("response")),
+			})
+		case p.Get("inlineData").Exists():
+			msg.Parts = append(msg.Parts, Part{Type: PartImage, Text: p.Get("inlineData.mimeType").String()})
+		case p.Get("thought").Bool():
+			msg.Parts = appendText(msg.Parts, PartThinking, p.Get("text").String())
+		default:
+			msg.Parts = appendText(msg.Parts, PartText, p.Get("text").String())
+		}
+	}
+	return msg
+}
+
+func geminiCandidateParts(chunk gjson.Result) gjson.Result {
+	if inner := chunk.Get("response"); inner.IsObject() {
+		chunk = inner
+	}
+	return chunk.Get("candidates.0.content.parts")
+}
+
+// geminiResponseMessage handles a single response object or a JSON array of stream chunks
+func geminiResponseMessage(root gjson.Result) Message {
+	if root.IsArray() {
+		return geminiStreamMessage(root.Array())
+	}
+	return geminiMessage(RoleAssistant, geminiCandidateParts(root))
+}
+
+func geminiStreamMessage(chunks []gjson.Result) Message {
+	msg := Message{Role: RoleAssistant}
+	for _, chunk := range chunks {
+		part := geminiMessage(RoleAssistant, geminiCandidateParts(chunk))
+		for _, p := range part.Parts {
+			if p.Type == PartText || p.Type == PartThinking {
+				msg.Parts = appendText(msg.Parts, p.Type, p.Text)
+				continue
+			}
+			msg.Parts = append(msg.Parts, p)
+		}
+	}
+	return msg
+}
@@ -0,0 +1,115 @@
+package conversation
+
+import (
+	"sort"
+
+	"github.com/tidwall/gjson"
+)
+
+func parseOpenAIRequest(root gjson.Result) *Request {
+	req := &Request{}
+	var systems []string
+	for _, m := range root.Get("messages").Array() {
+		role := m.Get("role").String()
+		switch role {
+		case "system", "developer":
+			systems = append(systems, textOf(m.Get("content")))
+		case "tool":
+			req.Messages = append(req.Messages, Message{Role: RoleUser, Parts: []Part{{
+				Type:       PartToolResult,
+				ToolCallID: m.Get("tool_call_id").String(),
+				Text:       textOf(m.Get("content")),
+			}}})
+		default:
+			req.Messages = append(req.Messages, openAIMessage(role, m))
+		}
+	}
+	req.System = joinNonEmpty(systems)
+	return req
+}
+
+func openAIMessage(role string, m gjson.Result) Message {
+	msg := Message{Role: role}
+	if reasoning := m.Get("reasoning_content"); reasoning.Exists() {
+		msg.Parts = appendText(msg.Parts, PartThinking, reasoning.String())
+	}
+	content := m.Get("content")
+	if content.IsArray() {
+		for _, part := range content.Array() {
+			switch part.Get("type").String() {
+			case "text":
+				msg.Parts = appendText(msg.Parts, PartText, part.Get("text").String())
+			case "image_url":
+				msg.Parts = append(msg.Parts, Part{Type: PartImage})
+			}
+		}
+	} else {
+		msg.Parts = appendText(msg.Parts, PartText, content.String())
+	}
+	for _, call := range m.Get("tool_calls").Array() {
+		msg.Parts = append(msg.Parts, Part{
+			Type:       PartToolCall,
+			ToolCallID: call.Get("id").String(),
+			ToolName:   call.Get("function.name").String(),
+			Input:      call.Get("function.arguments").String(),
+		})
+	}
+	return msg
+}
+
+func openAIResponseMessage(root gjson.Result) Message {
+	return openAIMessage(RoleAssistant, root.Get("choices.0.message"))
+}
+
+// openAIStreamMessage accumulates choices[0].delta chunks
+func openAIStreamMessage(events []gjson.Result) Message {
+	msg := Message{Role: RoleAssistant}
+	calls := map[int64]*Part{}
+	for _, ev := range events {
+		delta := ev.Get("choices.0.delta")
+		if !delta.Exists() {
+			continue
+		}
+		msg.Parts = appendText(msg.Parts, PartThinking, delta.Get("reasoning_content").String())
+		msg.Parts = appendText(msg.Parts, PartText, delta.Get("content").String())
+		for _, call := range delta.Get("tool_calls").Array() {
+			idx := call.Get("index").Int()
+			p, ok := calls[idx]
+			if !ok {
+				p = &Part{Type: PartToolCall}
+				calls[idx] = p
+			}
+			if id := call.Get("id").String(); id != "" {
+				p.ToolCallID = id
+			}
+			if name := call.Get("function.name").String(); name != "" {
+				p.ToolName = name
+			}
+			p.Input += call.Get("function.arguments").String()
+		}
+	}
+
+	indexes := make([]int64, 0, len(calls))
+	for i := range calls {
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	for _, i := range indexes {
+		msg.Parts = append(msg.Parts, *calls[i])
+	}
+	return msg
+}
+
+func joinNonEmpty(texts []string) string {
+	out := ""
+	for _, t := range texts {
+		if t == "" {
+			continue
+		}
+		if out != "" {
+			out += "\n\n"
+		}
+		out += t
+	}
+	return out
+}
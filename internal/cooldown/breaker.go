@@ -0,0 +1,167 @@
+package cooldown
+
+import (
+	"log"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// Circuit breaker on top of cooldown entries (per provider + client type):
+//   - closed:    no cooldown entry, all traffic passes
+//   - open:      cooldown entry not yet expired, no traffic passes
+//   - half-open: cooldown entry expired, only a limited number of trial requests pass;
+//     enough successes close the breaker, a failure reopens it with an escalated window
+//
+// Manual freezes, global (all client types) and model-scoped cooldowns skip the half-open state.
+
+const (
+	defaultHalfOpenMaxRequests = 1
+	defaultSuccessThreshold    = 1
+
+	// 半开状态持续无流量超过该时间后直接关闭
+	halfOpenRetention = 24 * time.Hour
+
+	// 半开且试探名额已满时没有确定的结束时间，排队请求按该间隔重新检查
+	halfOpenRecheckInterval = time.Second
+)
+
+// BreakerState represents the circuit breaker state of a provider + client type
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// trialState tracks trial requests of a half-open breaker
+type trialState struct {
+	inFlight  int
+	successes int
+}
+
+// Trial is a half-open trial slot held by a request
+// Release must be called once the request no longer uses the provider
+type Trial struct {
+	m     *Manager
+	key   CooldownKey
+	state *trialState
+}
+
+// Release frees the trial slot, safe to call on a nil Trial
+func (t *Trial) Release() {
+	if t == nil {
+		return
+	}
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	// 熔断已重新打开或关闭时，旧的试探状态已被丢弃
+	if t.m.trials[t.key] == t.state && t.state.inFlight > 0 {
+		t.state.inFlight--
+	}
+}
+
+// AcquireTrial reserves a trial slot when the breaker is half-open
+// Returns ok=false if the breaker is half-open and all trial slots are taken
+// Returns a nil Trial when the breaker is not half-open (closed or a cooldown set after routing)
+func (m *Manager) AcquireTrial(providerID uint64, clientType string) (*Trial, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := CooldownKey{ProviderID: providerID, ClientType: clientType}
+	if !m.isHalfOpenLocked(key, time.Now()) {
+		return nil, true
+	}
+
+	state := m.trials[key]
+	if state == nil {
+		state = &trialState{}
+		m.trials[key] = state
+	}
+	maxRequests, _ := m.breakerLimitsLocked(providerID)
+	if state.inFlight >= maxRequests {
+		return nil, false
+	}
+	state.inFlight++
+	log.Printf("[Cooldown] Provider %d (clientType=%s): Half-open, letting trial request through (%d/%d)",
+		providerID, clientType, state.inFlight, maxRequests)
+	return &Trial{m: m, key: key, state: state}, true
+}
+
+// GetBreakerState returns the circuit breaker state of a provider and client type
+func (m *Manager) GetBreakerState(providerID uint64, clientType string) BreakerState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key := CooldownKey{ProviderID: providerID, ClientType: clientType}
+	now := time.Now()
+	if until, ok := m.cooldowns[key]; ok && now.Before(until) {
+		return BreakerOpen
+	}
+	if m.isHalfOpenLocked(key, now) {
+		return BreakerHalfOpen
+	}
+	return BreakerClosed
+}
+
+// isHalfOpenLocked reports whether the cooldown of key has expired and is waiting for trial requests
+func (m *Manager) isHalfOpenLocked(key CooldownKey, now time.Time) bool {
+	if key.ClientType == "" || key.Model != "" {
+		return false
+	}
+	until, ok := m.cooldowns[key]
+	if !ok || now.Before(until) {
+		return false
+	}
+	return m.reasons[key] != ReasonManual
+}
+
+// halfOpenFullLocked reports whether a half-open key has no free trial slot
+func (m *Manager) halfOpenFullLocked(key CooldownKey, now time.Time) bool {
+	if !m.isHalfOpenLocked(key, now) {
+		return false
+	}
+	state := m.trials[key]
+	if state == nil {
+		return false
+	}
+	maxRequests, _ := m.breakerLimitsLocked(key.ProviderID)
+	return state.inFlight >= maxRequests
+}
+
+// breakerConfigLocked returns the circuit breaker settings of a provider, nil if not configured
+func (m *Manager) breakerConfigLocked(providerID uint64) *domain.ProviderConfigCircuitBreaker {
+	if m.providerRepo == nil {
+		return nil
+	}
+	p, err := m.providerRepo.GetByID(providerID)
+	if err != nil || p == nil || p.Config == nil {
+		return nil
+	}
+	return p.Config.CircuitBreaker
+}
+
+// breakerLimitsLocked returns the half-open trial limit and success threshold of a provider
+func (m *Manager) breakerLimitsLocked(providerID uint64) (maxRequests, successThreshold int) {
+	maxRequests, successThreshold = defaultHalfOpenMaxRequests, defaultSuccessThreshold
+	if cfg := m.breakerConfigLocked(providerID); cfg != nil {
+		if cfg.HalfOpenMaxRequests > 0 {
+			maxRequests = cfg.HalfOpenMaxRequests
+		}
+		if cfg.SuccessThreshold > 0 {
+			successThreshold = cfg.SuccessThreshold
+		}
+	}
+	return maxRequests, successThreshold
+}
+
+// policyLocked returns the cooldown policy of a provider for a reason
+// Priority: 1) provider override, 2) global policy (built-in defaults overlaid with configured ones)
+func (m *Manager) policyLocked(providerID uint64, reason CooldownReason) (CooldownPolicy, bool) {
+	if policy, ok := m.providerPolicies[providerID][reason]; ok {
+		return policy, true
+	}
+	policy, ok := m.policies[reason]
+	return policy, ok
+}
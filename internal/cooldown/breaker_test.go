@@ -0,0 +1,137 @@
+package cooldown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+type stubProviderRepo struct {
+	providers map[uint64]*domain.Provider
+}
+
+func (r *stubProviderRepo) Create(*domain.Provider) error { return nil }
+func (r *stubProviderRepo) Update(*domain.Provider) error { return nil }
+func (r *stubProviderRepo) Delete(uint64) error           { return nil }
+func (r *stubProviderRepo) List() ([]*domain.Provider, error) {
+	return nil, nil
+}
+func (r *stubProviderRepo) GetByID(id uint64) (*domain.Provider, error) {
+	if p, ok := r.providers[id]; ok {
+		return p, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+// expire moves the cooldown of key into the past so the circuit becomes half-open
+func expire(m *Manager, providerID uint64, clientType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cooldowns[CooldownKey{ProviderID: providerID, ClientType: clientType}] = time.Now().Add(-time.Second)
+}
+
+func TestBreakerHalfOpenLimitsTrials(t *testing.T) {
+	m := NewManager()
+	m.RecordFailure(1, "claude", "", ReasonServerError, nil)
+	if m.GetBreakerState(1, "claude") != BreakerOpen || !m.IsInCooldown(1, "claude", "") {
+		t.Fatal("expected open circuit after a failure")
+	}
+
+	expire(m, 1, "claude")
+	if m.GetBreakerState(1, "claude") != BreakerHalfOpen || m.IsInCooldown(1, "claude", "") {
+		t.Fatal("expected half-open circuit with a free trial slot")
+	}
+
+	trial, ok := m.AcquireTrial(1, "claude")
+	if !ok || trial == nil {
+		t.Fatal("first trial should be let through")
+	}
+	if _, ok := m.AcquireTrial(1, "claude"); ok {
+		t.Fatal("second trial should be rejected")
+	}
+	if !m.IsInCooldown(1, "claude", "") {
+		t.Error("router should skip a half-open circuit without free trial slots")
+	}
+
+	if until := m.GetCooldownUntil(1, "claude", ""); !until.After(time.Now()) {
+		t.Errorf("GetCooldownUntil = %v, want a re-check time while trial slots are taken", until)
+	}
+
+	trial.Release()
+	if m.IsInCooldown(1, "claude", "") {
+		t.Error("released trial slot should be available again")
+	}
+	if until := m.GetCooldownUntil(1, "claude", ""); !until.IsZero() {
+		t.Errorf("GetCooldownUntil = %v, want zero with a free trial slot", until)
+	}
+
+	// 关闭状态不占用名额
+	if trial, ok := m.AcquireTrial(1, "openai"); !ok || trial != nil {
+		t.Error("closed circuit should not hand out trial slots")
+	}
+}
+
+func TestBreakerFailedTrialEscalates(t *testing.T) {
+	m := NewManager()
+	first := m.RecordFailure(1, "claude", "", ReasonServerError, nil)
+	expire(m, 1, "claude")
+
+	trial, _ := m.AcquireTrial(1, "claude")
+	second := m.RecordFailure(1, "claude", "", ReasonServerError, nil)
+	trial.Release()
+
+	if m.GetBreakerState(1, "claude") != BreakerOpen {
+		t.Fatal("failed trial should reopen the circuit")
+	}
+	// 默认 server_error 为线性策略，第二次失败冷冻时间加倍
+	if second.Sub(first) < 4*time.Second {
+		t.Errorf("expected escalated cooldown, got %v then %v", first, second)
+	}
+}
+
+func TestBreakerSuccessThreshold(t *testing.T) {
+	m := NewManager()
+	m.SetProviderRepository(&stubProviderRepo{providers: map[uint64]*domain.Provider{
+		1: {ID: 1, Config: &domain.ProviderConfig{CircuitBreaker: &domain.ProviderConfigCircuitBreaker{
+			HalfOpenMaxRequests: 2,
+			SuccessThreshold:    2,
+		}}},
+	}})
+	m.RecordFailure(1, "claude", "", ReasonNetworkError, nil)
+	expire(m, 1, "claude")
+
+	t1, ok1 := m.AcquireTrial(1, "claude")
+	t2, ok2 := m.AcquireTrial(1, "claude")
+	if !ok1 || !ok2 {
+		t.Fatal("two trial slots are configured")
+	}
+
+	m.RecordSuccess(1, "claude", "")
+	t1.Release()
+	if m.GetBreakerState(1, "claude") != BreakerHalfOpen {
+		t.Fatal("one success should not close the circuit")
+	}
+
+	m.RecordSuccess(1, "claude", "")
+	t2.Release()
+	if m.GetBreakerState(1, "claude") != BreakerClosed {
+		t.Fatal("circuit should close after reaching the success threshold")
+	}
+}
+
+func TestBreakerManualFreezeSkipsHalfOpen(t *testing.T) {
+	m := NewManager()
+	m.SetCooldownUntil(1, "claude", time.Now().Add(time.Hour))
+	expire(m, 1, "claude")
+	if m.GetBreakerState(1, "claude") != BreakerClosed {
+		t.Error("an expired manual freeze should close the circuit directly")
+	}
+
+	m.RecordFailure(2, "claude", "", ReasonServerError, nil)
+	expire(m, 2, "claude")
+	m.CleanupExpired()
+	if m.GetBreakerState(2, "claude") != BreakerHalfOpen {
+		t.Error("cleanup must keep half-open circuits")
+	}
+}
@@ -0,0 +1,52 @@
+package cooldown
+
+import (
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// ClassifyProxyError determines the failure reason and explicit cooldown time of a ProxyError
+// Priority: 1) Explicit time from API, 2) Error type (server / network / unknown)
+func ClassifyProxyError(proxyErr *domain.ProxyError) (CooldownReason, *time.Time) {
+	// Priority 1: Check for explicit cooldown time from API
+	if proxyErr.CooldownUntil != nil {
+		// Has explicit time from API (e.g., from CooldownUntil field)
+		reason := ReasonQuotaExhausted // Default, may be overridden below
+		if proxyErr.RateLimitInfo != nil {
+			reason = mapRateLimitTypeToReason(proxyErr.RateLimitInfo.Type)
+		}
+		return reason, proxyErr.CooldownUntil
+	}
+	if proxyErr.RateLimitInfo != nil && !proxyErr.RateLimitInfo.QuotaResetTime.IsZero() {
+		// Has explicit quota reset time from API
+		return mapRateLimitTypeToReason(proxyErr.RateLimitInfo.Type), &proxyErr.RateLimitInfo.QuotaResetTime
+	}
+	if proxyErr.RetryAfter > 0 {
+		// Has Retry-After duration from API
+		untilTime := time.Now().Add(proxyErr.RetryAfter)
+		return ReasonRateLimit, &untilTime
+	}
+	// No explicit time, cooldown duration is calculated by policy
+	if proxyErr.IsServerError {
+		return ReasonServerError, nil
+	}
+	if proxyErr.IsNetworkError {
+		return ReasonNetworkError, nil
+	}
+	return ReasonUnknown, nil
+}
+
+// mapRateLimitTypeToReason maps RateLimitInfo.Type to CooldownReason
+func mapRateLimitTypeToReason(rateLimitType string) CooldownReason {
+	switch rateLimitType {
+	case "quota_exhausted":
+		return ReasonQuotaExhausted
+	case "rate_limit_exceeded":
+		return ReasonRateLimit
+	case "concurrent_limit":
+		return ReasonConcurrentLimit
+	default:
+		return ReasonRateLimit // Default to rate limit
+	}
+}
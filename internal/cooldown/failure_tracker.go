@@ -42,6 +42,7 @@ func (ft *FailureTracker) LoadFromDatabase() error {
 		key := FailureKey{
 			ProviderID: fc.ProviderID,
 			ClientType: fc.ClientType,
+			Model:      fc.Model,
 			Reason:     CooldownReason(fc.Reason),
 		}
 		ft.failureCounts[key] = fc.Count
@@ -53,10 +54,11 @@ func (ft *FailureTracker) LoadFromDatabase() error {
 
 // IncrementFailure increments the failure count and persists to database
 // Returns the new failure count
-func (ft *FailureTracker) IncrementFailure(providerID uint64, clientType string, reason CooldownReason) int {
+func (ft *FailureTracker) IncrementFailure(providerID uint64, clientType string, model string, reason CooldownReason) int {
 	key := FailureKey{
 		ProviderID: providerID,
 		ClientType: clientType,
+		Model:      model,
 		Reason:     reason,
 	}
 
@@ -68,6 +70,7 @@ func (ft *FailureTracker) IncrementFailure(providerID uint64, clientType string,
 		fc := &domain.FailureCount{
 			ProviderID:    providerID,
 			ClientType:    clientType,
+			Model:         model,
 			Reason:        string(reason),
 			Count:         newCount,
 			LastFailureAt: time.Now().UTC(),
@@ -81,16 +84,17 @@ func (ft *FailureTracker) IncrementFailure(providerID uint64, clientType string,
 }
 
 // GetFailureCount returns the current failure count for a given key
-func (ft *FailureTracker) GetFailureCount(providerID uint64, clientType string, reason CooldownReason) int {
+func (ft *FailureTracker) GetFailureCount(providerID uint64, clientType string, model string, reason CooldownReason) int {
 	key := FailureKey{
 		ProviderID: providerID,
 		ClientType: clientType,
+		Model:      model,
 		Reason:     reason,
 	}
 	return ft.failureCounts[key]
 }
 
-// ResetFailures resets all failure counts for a provider+clientType
+// ResetFailures resets all failure counts (of every model) for a provider+clientType
 // If clientType is empty, resets ALL failure counts for the provider
 func (ft *FailureTracker) ResetFailures(providerID uint64, clientType string) {
 	// Clear failure counts for all reasons for this provider+clientType
@@ -120,6 +124,31 @@ func (ft *FailureTracker) ResetFailures(providerID uint64, clientType string) {
 	}
 }
 
+// ResetModelFailures resets the failure counts of exactly one provider+clientType+model
+// Unlike ResetFailures, an empty model only matches failures not tied to a model
+func (ft *FailureTracker) ResetModelFailures(providerID uint64, clientType string, model string) {
+	reset := 0
+	for key := range ft.failureCounts {
+		if key.ProviderID != providerID || key.ClientType != clientType || key.Model != model {
+			continue
+		}
+		delete(ft.failureCounts, key)
+		reset++
+
+		// Delete from database
+		if ft.repository != nil {
+			if err := ft.repository.Delete(providerID, clientType, model, string(key.Reason)); err != nil {
+				log.Printf("[FailureTracker] Failed to delete failure count from database: %v", err)
+			}
+		}
+	}
+
+	if reset > 0 {
+		log.Printf("[FailureTracker] Provider %d (clientType=%s, model=%s): Reset %d failure counts",
+			providerID, clientType, model, reset)
+	}
+}
+
 // CleanupExpired removes failure counts that are too old
 // This prevents indefinite accumulation of failures
 func (ft *FailureTracker) CleanupExpired(olderThanSeconds int64) {
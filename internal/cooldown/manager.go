@@ -15,9 +15,16 @@ type Manager struct {
 	mu             sync.RWMutex
 	cooldowns      map[CooldownKey]time.Time         // cooldown key -> end time
 	reasons        map[CooldownKey]CooldownReason    // cooldown key -> reason
+	explicit       map[CooldownKey]bool              // cooldowns whose end time came from upstream
 	failureTracker *FailureTracker                   // tracks failure counts
 	policies       map[CooldownReason]CooldownPolicy // cooldown calculation strategies
+	trials         map[CooldownKey]*trialState       // half-open trial requests
 	repository     repository.CooldownRepository
+	providerRepo   repository.ProviderRepository // per-provider circuit breaker settings
+
+	// configured cooldown policies, applied on top of DefaultPolicies()
+	policyRepo       repository.CooldownPolicyRepository
+	providerPolicies map[uint64]map[CooldownReason]CooldownPolicy // provider ID -> reason -> policy
 }
 
 // NewManager creates a new cooldown manager
@@ -25,8 +32,12 @@ func NewManager() *Manager {
 	return &Manager{
 		cooldowns:      make(map[CooldownKey]time.Time),
 		reasons:        make(map[CooldownKey]CooldownReason),
+		explicit:       make(map[CooldownKey]bool),
 		failureTracker: NewFailureTracker(),
 		policies:       DefaultPolicies(),
+		trials:         make(map[CooldownKey]*trialState),
+
+		providerPolicies: make(map[uint64]map[CooldownReason]CooldownPolicy),
 	}
 }
 
@@ -52,30 +63,99 @@ func (m *Manager) SetFailureCountRepository(repo repository.FailureCountReposito
 	m.failureTracker.SetRepository(repo)
 }
 
+// SetProviderRepository sets the repository used to look up per-provider circuit breaker settings
+// Should be the cached repository, it is consulted on every failure and half-open check
+func (m *Manager) SetProviderRepository(repo repository.ProviderRepository) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.providerRepo = repo
+}
+
+// SetPolicyRepository sets the repository of configured cooldown policies
+// Call ReloadPolicies afterwards, and again whenever the policies change
+func (m *Manager) SetPolicyRepository(repo repository.CooldownPolicyRepository) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policyRepo = repo
+}
+
+// ReloadPolicies rebuilds the policy tables from the repository without a restart
+// Global policies (ProviderID 0) replace the built-in default of their reason,
+// provider policies override both for that provider only
+func (m *Manager) ReloadPolicies() error {
+	m.mu.RLock()
+	repo := m.policyRepo
+	m.mu.RUnlock()
+	if repo == nil {
+		return nil
+	}
+
+	configured, err := repo.List()
+	if err != nil {
+		return err
+	}
+
+	policies := DefaultPolicies()
+	providerPolicies := make(map[uint64]map[CooldownReason]CooldownPolicy)
+	for _, p := range configured {
+		policy := NewPolicy(p.CooldownPolicyConfig)
+		if policy == nil {
+			log.Printf("[Cooldown] Warning: Ignoring cooldown policy %d with unknown type %q", p.ID, p.Type)
+			continue
+		}
+		reason := CooldownReason(p.Reason)
+		if p.ProviderID == 0 {
+			policies[reason] = policy
+			continue
+		}
+		if providerPolicies[p.ProviderID] == nil {
+			providerPolicies[p.ProviderID] = make(map[CooldownReason]CooldownPolicy)
+		}
+		providerPolicies[p.ProviderID][reason] = policy
+	}
+
+	m.mu.Lock()
+	m.policies = policies
+	m.providerPolicies = providerPolicies
+	m.mu.Unlock()
+
+	log.Printf("[Cooldown] Loaded %d cooldown policies", len(configured))
+	return nil
+}
+
 // LoadFromDatabase loads all active cooldowns and failure counts from database into memory
+// Expired cooldowns of half-open circuits are restored too, so a restart does not close the breaker
 func (m *Manager) LoadFromDatabase() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Load cooldowns
 	if m.repository != nil {
-		cooldowns, err := m.repository.GetAll()
+		now := time.Now()
+		cooldowns, err := m.repository.GetEndedAfter(now.Add(-halfOpenRetention))
 		if err != nil {
 			return err
 		}
 
 		m.cooldowns = make(map[CooldownKey]time.Time)
 		m.reasons = make(map[CooldownKey]CooldownReason)
+		m.trials = make(map[CooldownKey]*trialState)
 		for _, cd := range cooldowns {
 			key := CooldownKey{
 				ProviderID: cd.ProviderID,
 				ClientType: cd.ClientType,
+				Model:      cd.Model,
 			}
 			m.cooldowns[key] = cd.UntilTime
 			m.reasons[key] = CooldownReason(cd.Reason)
+			if now.After(cd.UntilTime) && !m.isHalfOpenLocked(key, now) {
+				delete(m.cooldowns, key)
+				delete(m.reasons, key)
+				delete(m.explicit, key)
+			}
 		}
 
-		log.Printf("[Cooldown] Loaded %d cooldowns from database", len(cooldowns))
+		log.Printf("[Cooldown] Loaded %d cooldowns from database", len(m.cooldowns))
 	}
 
 	// Load failure counts
@@ -89,25 +169,32 @@ func (m *Manager) LoadFromDatabase() error {
 // RecordFailure records a failure and applies cooldown based on the reason and policy
 // If explicitUntil is provided, it will be used directly (e.g., from Retry-After header)
 // Otherwise, the cooldown duration is calculated using the policy for the given reason
+// model is optional - a model name or wildcard family limits the cooldown to those models
 // Returns the calculated cooldown end time
-func (m *Manager) RecordFailure(providerID uint64, clientType string, reason CooldownReason, explicitUntil *time.Time) time.Time {
+func (m *Manager) RecordFailure(providerID uint64, clientType string, model string, reason CooldownReason, explicitUntil *time.Time) time.Time {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	key := CooldownKey{ProviderID: providerID, ClientType: clientType, Model: model}
+	if m.isHalfOpenLocked(key, time.Now()) {
+		log.Printf("[Cooldown] Provider %d (%s): Trial request failed, reopening circuit", providerID, key.scope())
+	}
+
 	// If explicit until time is provided (e.g., from 429 Retry-After), use it directly
 	if explicitUntil != nil {
-		m.setCooldownLocked(providerID, clientType, *explicitUntil, reason)
-		log.Printf("[Cooldown] Provider %d (clientType=%s): Set explicit cooldown until %s (reason=%s)",
-			providerID, clientType, explicitUntil.Format("2006-01-02 15:04:05"), reason)
+		m.setCooldownLocked(key, *explicitUntil, reason)
+		m.explicit[key] = true
+		log.Printf("[Cooldown] Provider %d (%s): Set explicit cooldown until %s (reason=%s)",
+			providerID, key.scope(), explicitUntil.Format("2006-01-02 15:04:05"), reason)
 		return *explicitUntil
 	}
 
 	// Otherwise, calculate cooldown based on policy and failure count
-	// Increment failure count
-	failureCount := m.failureTracker.IncrementFailure(providerID, clientType, reason)
+	// Increment failure count (not reset while half-open, so a failed trial escalates the window)
+	failureCount := m.failureTracker.IncrementFailure(providerID, clientType, model, reason)
 
 	// Get policy for this reason
-	policy, ok := m.policies[reason]
+	policy, ok := m.policyLocked(providerID, reason)
 	if !ok {
 		// Fallback to fixed 5-second cooldown if no policy found
 		policy = &FixedDurationPolicy{Duration: 5 * time.Second}
@@ -118,10 +205,10 @@ func (m *Manager) RecordFailure(providerID uint64, clientType string, reason Coo
 	duration := policy.CalculateCooldown(failureCount)
 	until := time.Now().Add(duration)
 
-	m.setCooldownLocked(providerID, clientType, until, reason)
+	m.setCooldownLocked(key, until, reason)
 
-	log.Printf("[Cooldown] Provider %d (clientType=%s): Set cooldown for %v until %s (reason=%s, failureCount=%d)",
-		providerID, clientType, duration, until.Format("2006-01-02 15:04:05"), reason, failureCount)
+	log.Printf("[Cooldown] Provider %d (%s): Set cooldown for %v until %s (reason=%s, failureCount=%d)",
+		providerID, key.scope(), duration, until.Format("2006-01-02 15:04:05"), reason, failureCount)
 
 	return until
 }
@@ -129,62 +216,110 @@ func (m *Manager) RecordFailure(providerID uint64, clientType string, reason Coo
 // UpdateCooldown updates cooldown time without incrementing failure count
 // This is used for async updates (e.g., when quota reset time is fetched asynchronously)
 // Keeps the existing reason
-func (m *Manager) UpdateCooldown(providerID uint64, clientType string, until time.Time) {
+func (m *Manager) UpdateCooldown(providerID uint64, clientType string, model string, until time.Time) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Get existing reason or use Unknown
-	key := CooldownKey{ProviderID: providerID, ClientType: clientType}
+	key := CooldownKey{ProviderID: providerID, ClientType: clientType, Model: model}
 	reason, ok := m.reasons[key]
 	if !ok {
 		reason = ReasonUnknown
 	}
 
-	m.setCooldownLocked(providerID, clientType, until, reason)
-	log.Printf("[Cooldown] Provider %d (clientType=%s): Updated cooldown to %s (async update, no count increment)",
-		providerID, clientType, until.Format("2006-01-02 15:04:05"))
+	m.setCooldownLocked(key, until, reason)
+	m.explicit[key] = true
+	log.Printf("[Cooldown] Provider %d (%s): Updated cooldown to %s (async update, no count increment)",
+		providerID, key.scope(), until.Format("2006-01-02 15:04:05"))
 }
 
 // RecordSuccess records a successful request and clears cooldown + resets failure counts
 // This ensures the provider is immediately available after a successful request
-func (m *Manager) RecordSuccess(providerID uint64, clientType string) {
+// While half-open, the circuit only closes once the configured number of successes is reached
+// model is the model that was served; cooldowns of the model or its family are cleared as well
+func (m *Manager) RecordSuccess(providerID uint64, clientType string, model string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Clear cooldown from memory
+	if model != "" {
+		m.clearModelCooldownsLocked(providerID, clientType, model)
+	}
+
 	key := CooldownKey{ProviderID: providerID, ClientType: clientType}
+	if m.isHalfOpenLocked(key, time.Now()) {
+		state := m.trials[key]
+		if state == nil {
+			state = &trialState{}
+			m.trials[key] = state
+		}
+		state.successes++
+		if _, threshold := m.breakerLimitsLocked(providerID); state.successes < threshold {
+			log.Printf("[Cooldown] Provider %d (clientType=%s): Trial request succeeded (%d/%d), staying half-open",
+				providerID, clientType, state.successes, threshold)
+			return
+		}
+	}
+
+	// Clear cooldown from memory
 	delete(m.cooldowns, key)
 	delete(m.reasons, key)
+	delete(m.explicit, key)
+	delete(m.trials, key)
 
 	// Delete from database
 	if m.repository != nil {
-		if err := m.repository.Delete(providerID, clientType); err != nil {
+		if err := m.repository.Delete(providerID, clientType, ""); err != nil {
 			log.Printf("[Cooldown] Failed to delete cooldown for provider %d, client %s from database: %v", providerID, clientType, err)
 		}
 	}
 
-	// Reset failure counts
-	m.failureTracker.ResetFailures(providerID, clientType)
+	// Reset failure counts (model-scoped failures are reset together with their cooldowns)
+	m.failureTracker.ResetModelFailures(providerID, clientType, "")
 
 	log.Printf("[Cooldown] Provider %d (clientType=%s): Cleared cooldown after successful request", providerID, clientType)
 }
 
+// clearModelCooldownsLocked clears the model-scoped cooldowns of a provider+clientType that cover model
+func (m *Manager) clearModelCooldownsLocked(providerID uint64, clientType string, model string) {
+	for key := range m.cooldowns {
+		if key.Model == "" || key.ProviderID != providerID || key.ClientType != clientType ||
+			!domain.MatchWildcard(key.Model, model) {
+			continue
+		}
+		delete(m.cooldowns, key)
+		delete(m.reasons, key)
+		delete(m.explicit, key)
+		delete(m.trials, key)
+
+		if m.repository != nil {
+			if err := m.repository.Delete(providerID, clientType, key.Model); err != nil {
+				log.Printf("[Cooldown] Failed to delete cooldown for provider %d (%s) from database: %v", providerID, key.scope(), err)
+			}
+		}
+		m.failureTracker.ResetModelFailures(providerID, clientType, key.Model)
+
+		log.Printf("[Cooldown] Provider %d (%s): Cleared model cooldown after successful request", providerID, key.scope())
+	}
+}
+
 // setCooldownLocked sets cooldown without acquiring lock (internal use only)
-func (m *Manager) setCooldownLocked(providerID uint64, clientType string, until time.Time, reason CooldownReason) {
-	key := CooldownKey{ProviderID: providerID, ClientType: clientType}
+func (m *Manager) setCooldownLocked(key CooldownKey, until time.Time, reason CooldownReason) {
 	m.cooldowns[key] = until
 	m.reasons[key] = reason
+	delete(m.explicit, key)
+	delete(m.trials, key)
 
 	// Persist to database
 	if m.repository != nil {
 		cd := &domain.Cooldown{
-			ProviderID: providerID,
-			ClientType: clientType,
+			ProviderID: key.ProviderID,
+			ClientType: key.ClientType,
+			Model:      key.Model,
 			UntilTime:  until,
 			Reason:     domain.CooldownReason(reason),
 		}
 		if err := m.repository.Upsert(cd); err != nil {
-			log.Printf("[Cooldown] Failed to persist cooldown for provider %d: %v", providerID, err)
+			log.Printf("[Cooldown] Failed to persist cooldown for provider %d: %v", key.ProviderID, err)
 		}
 	}
 }
@@ -196,7 +331,7 @@ func (m *Manager) SetCooldownDuration(providerID uint64, clientType string, dura
 	defer m.mu.Unlock()
 
 	until := time.Now().Add(duration)
-	m.setCooldownLocked(providerID, clientType, until, ReasonUnknown)
+	m.setCooldownLocked(CooldownKey{ProviderID: providerID, ClientType: clientType}, until, ReasonUnknown)
 }
 
 // SetCooldownUntil sets a cooldown for a provider until a specific time
@@ -205,13 +340,13 @@ func (m *Manager) SetCooldownUntil(providerID uint64, clientType string, until t
 	log.Printf("[Cooldown] SetCooldownUntil: providerID=%d, clientType=%q, until=%v", providerID, clientType, until)
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.setCooldownLocked(providerID, clientType, until, ReasonManual)
+	m.setCooldownLocked(CooldownKey{ProviderID: providerID, ClientType: clientType}, until, ReasonManual)
 	log.Printf("[Cooldown] SetCooldownUntil: done, current cooldowns count=%d", len(m.cooldowns))
 }
 
 // ClearCooldown removes the cooldown for a provider
 // If clientType is empty, clears ALL cooldowns for the provider (both global and specific)
-// If clientType is specified, only clears that specific cooldown
+// If clientType is specified, only clears the cooldowns of that client type (of every model)
 func (m *Manager) ClearCooldown(providerID uint64, clientType string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -227,6 +362,8 @@ func (m *Manager) ClearCooldown(providerID uint64, clientType string) {
 		for _, key := range keysToDelete {
 			delete(m.cooldowns, key)
 			delete(m.reasons, key)
+			delete(m.explicit, key)
+			delete(m.trials, key)
 		}
 
 		// Delete from database
@@ -239,15 +376,25 @@ func (m *Manager) ClearCooldown(providerID uint64, clientType string) {
 		// Also reset all failure counts for this provider
 		m.failureTracker.ResetFailures(providerID, "")
 	} else {
-		// Clear specific cooldown
-		key := CooldownKey{ProviderID: providerID, ClientType: clientType}
-		delete(m.cooldowns, key)
-		delete(m.reasons, key)
-
-		// Delete from database
-		if m.repository != nil {
-			if err := m.repository.Delete(providerID, clientType); err != nil {
-				log.Printf("[Cooldown] Failed to delete cooldown for provider %d, client %s from database: %v", providerID, clientType, err)
+		// Clear specific cooldowns, including model-scoped ones
+		models := []string{""}
+		for key := range m.cooldowns {
+			if key.ProviderID == providerID && key.ClientType == clientType && key.Model != "" {
+				models = append(models, key.Model)
+			}
+		}
+		for _, model := range models {
+			key := CooldownKey{ProviderID: providerID, ClientType: clientType, Model: model}
+			delete(m.cooldowns, key)
+			delete(m.reasons, key)
+			delete(m.explicit, key)
+			delete(m.trials, key)
+
+			// Delete from database
+			if m.repository != nil {
+				if err := m.repository.Delete(providerID, clientType, model); err != nil {
+					log.Printf("[Cooldown] Failed to delete cooldown for provider %d, client %s from database: %v", providerID, clientType, err)
+				}
 			}
 		}
 
@@ -259,8 +406,9 @@ func (m *Manager) ClearCooldown(providerID uint64, clientType string) {
 // IsInCooldown checks if a provider is currently in cooldown for a specific client type
 // Checks both:
 // 1. Global cooldown (clientType = "")
-// 2. Client-type-specific cooldown
-func (m *Manager) IsInCooldown(providerID uint64, clientType string) bool {
+// 2. Client-type-specific cooldown, including a half-open circuit whose trial slots are all taken
+// 3. Model-scoped cooldowns covering model (skipped when model is empty)
+func (m *Manager) IsInCooldown(providerID uint64, clientType string, model string) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -278,40 +426,70 @@ func (m *Manager) IsInCooldown(providerID uint64, clientType string) bool {
 		if until, ok := m.cooldowns[specificKey]; ok && now.Before(until) {
 			return true
 		}
+		if m.halfOpenFullLocked(specificKey, now) {
+			return true
+		}
 	}
 
-	return false
+	return !m.modelCooldownUntilLocked(providerID, clientType, model, now).IsZero()
 }
 
-// GetCooldownUntil returns the cooldown end time for a provider and client type
-// Returns the later of global cooldown or client-type-specific cooldown
+// GetCooldownUntil returns the cooldown end time for a provider, client type and model
+// Returns the latest of global, client-type-specific and model-scoped cooldowns
+// A half-open circuit without free trial slots reports the next re-check time, consistent with IsInCooldown
 // Returns zero time if not in cooldown
-func (m *Manager) GetCooldownUntil(providerID uint64, clientType string) time.Time {
+func (m *Manager) GetCooldownUntil(providerID uint64, clientType string, model string) time.Time {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	now := time.Now()
-	var latestCooldown time.Time
-
-	// Check global cooldown
-	globalKey := CooldownKey{ProviderID: providerID, ClientType: ""}
-	if until, ok := m.cooldowns[globalKey]; ok && now.Before(until) {
-		latestCooldown = until
+	until := m.getCooldownUntilLocked(providerID, clientType, model)
+	if until.IsZero() && clientType != "" {
+		now := time.Now()
+		if m.halfOpenFullLocked(CooldownKey{ProviderID: providerID, ClientType: clientType}, now) {
+			until = now.Add(halfOpenRecheckInterval)
+		}
 	}
+	return until
+}
 
-	// Check client-type-specific cooldown
-	if clientType != "" {
-		specificKey := CooldownKey{ProviderID: providerID, ClientType: clientType}
-		if until, ok := m.cooldowns[specificKey]; ok && now.Before(until) {
-			if until.After(latestCooldown) {
-				latestCooldown = until
-			}
+// modelCooldownUntilLocked returns the latest active model-scoped cooldown covering model
+func (m *Manager) modelCooldownUntilLocked(providerID uint64, clientType string, model string, now time.Time) time.Time {
+	var latestCooldown time.Time
+	if model == "" {
+		return latestCooldown
+	}
+	for key, until := range m.cooldowns {
+		if key.Model != "" && key.appliesTo(providerID, clientType, model) &&
+			now.Before(until) && until.After(latestCooldown) {
+			latestCooldown = until
 		}
 	}
-
 	return latestCooldown
 }
 
+// GetCooldownReason returns the reason of the active cooldown stored under exactly this key
+// Unlike IsInCooldown, the global cooldown is not consulted when clientType is specified
+func (m *Manager) GetCooldownReason(providerID uint64, clientType string) (CooldownReason, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key := CooldownKey{ProviderID: providerID, ClientType: clientType}
+	until, ok := m.cooldowns[key]
+	if !ok || !time.Now().Before(until) {
+		return "", false
+	}
+	return m.reasons[key], true
+}
+
+// IsExplicitCooldown reports whether the cooldown stored under exactly this key ends at a time
+// given by upstream (Retry-After, quota reset) rather than one calculated by policy
+func (m *Manager) IsExplicitCooldown(providerID uint64, clientType string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.explicit[CooldownKey{ProviderID: providerID, ClientType: clientType}]
+}
+
 // GetAllCooldowns returns all active cooldowns
 // Returns map of CooldownKey -> end time
 func (m *Manager) GetAllCooldowns() map[CooldownKey]time.Time {
@@ -332,6 +510,7 @@ func (m *Manager) GetAllCooldowns() map[CooldownKey]time.Time {
 
 // CleanupExpired removes expired cooldowns from memory and database
 // Also resets failure counts for expired cooldowns
+// Half-open circuits stay in memory until a trial request settles them or halfOpenRetention passes
 func (m *Manager) CleanupExpired() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -341,20 +520,34 @@ func (m *Manager) CleanupExpired() {
 
 	for key, until := range m.cooldowns {
 		if now.After(until) {
+			if m.isHalfOpenLocked(key, now) && now.Sub(until) < halfOpenRetention {
+				continue
+			}
 			delete(m.cooldowns, key)
 			delete(m.reasons, key)
+			delete(m.explicit, key)
+			delete(m.trials, key)
 			expiredKeys = append(expiredKeys, key)
 		}
 	}
 
 	// Reset failure counts for expired cooldowns
 	for _, key := range expiredKeys {
+		if key.Model != "" {
+			m.failureTracker.ResetModelFailures(key.ProviderID, key.ClientType, key.Model)
+			continue
+		}
 		m.failureTracker.ResetFailures(key.ProviderID, key.ClientType)
 	}
 
-	// Delete expired cooldowns from database
+	// Delete expired cooldowns from database, half-open circuits keep their rows until they settle
 	if m.repository != nil {
-		if err := m.repository.DeleteExpired(); err != nil {
+		for _, key := range expiredKeys {
+			if err := m.repository.Delete(key.ProviderID, key.ClientType, key.Model); err != nil {
+				log.Printf("[Cooldown] Failed to delete expired cooldown for provider %d (%s) from database: %v", key.ProviderID, key.scope(), err)
+			}
+		}
+		if err := m.repository.DeleteExpired(now.Add(-halfOpenRetention)); err != nil {
 			log.Printf("[Cooldown] Failed to delete expired cooldowns from database: %v", err)
 		}
 	}
@@ -368,11 +561,30 @@ func (m *Manager) CleanupExpired() {
 }
 
 // GetCooldownInfo returns cooldown info for a specific provider and client type
-func (m *Manager) GetCooldownInfo(providerID uint64, clientType string, providerName string) *CooldownInfo {
+// For a model-scoped key only that exact entry is reported
+func (m *Manager) GetCooldownInfo(key CooldownKey, providerName string) *CooldownInfo {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	until := m.getCooldownUntilLocked(providerID, clientType)
+	providerID, clientType := key.ProviderID, key.ClientType
+	if key.Model != "" {
+		until, ok := m.cooldowns[key]
+		remaining := time.Until(until)
+		if !ok || remaining < 0 {
+			return nil
+		}
+		return &CooldownInfo{
+			ProviderID:   providerID,
+			ProviderName: providerName,
+			ClientType:   clientType,
+			Model:        key.Model,
+			Until:        until,
+			Remaining:    formatDuration(remaining),
+			Reason:       m.reasons[key],
+		}
+	}
+
+	until := m.getCooldownUntilLocked(providerID, clientType, "")
 	if until.IsZero() {
 		return nil
 	}
@@ -407,7 +619,7 @@ func (m *Manager) GetCooldownInfo(providerID uint64, clientType string, provider
 }
 
 // getCooldownUntilLocked is internal version without lock
-func (m *Manager) getCooldownUntilLocked(providerID uint64, clientType string) time.Time {
+func (m *Manager) getCooldownUntilLocked(providerID uint64, clientType string, model string) time.Time {
 	now := time.Now()
 	var latestCooldown time.Time
 
@@ -427,6 +639,11 @@ func (m *Manager) getCooldownUntilLocked(providerID uint64, clientType string) t
 		}
 	}
 
+	// Check model-scoped cooldowns
+	if until := m.modelCooldownUntilLocked(providerID, clientType, model, now); until.After(latestCooldown) {
+		latestCooldown = until
+	}
+
 	return latestCooldown
 }
 
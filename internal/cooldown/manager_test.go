@@ -0,0 +1,155 @@
+package cooldown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// memCooldownRepo is an in-memory CooldownRepository
+type memCooldownRepo struct {
+	rows map[CooldownKey]*domain.Cooldown
+}
+
+func newMemCooldownRepo() *memCooldownRepo {
+	return &memCooldownRepo{rows: make(map[CooldownKey]*domain.Cooldown)}
+}
+
+func (r *memCooldownRepo) endedAfter(t time.Time) []*domain.Cooldown {
+	var result []*domain.Cooldown
+	for _, cd := range r.rows {
+		if cd.UntilTime.After(t) {
+			result = append(result, cd)
+		}
+	}
+	return result
+}
+
+func (r *memCooldownRepo) GetAll() ([]*domain.Cooldown, error) {
+	return r.endedAfter(time.Now()), nil
+}
+func (r *memCooldownRepo) GetEndedAfter(t time.Time) ([]*domain.Cooldown, error) {
+	return r.endedAfter(t), nil
+}
+func (r *memCooldownRepo) GetByProvider(uint64) ([]*domain.Cooldown, error) { return nil, nil }
+func (r *memCooldownRepo) Upsert(cd *domain.Cooldown) error {
+	r.rows[CooldownKey{ProviderID: cd.ProviderID, ClientType: cd.ClientType, Model: cd.Model}] = cd
+	return nil
+}
+func (r *memCooldownRepo) Delete(providerID uint64, clientType string, model string) error {
+	delete(r.rows, CooldownKey{ProviderID: providerID, ClientType: clientType, Model: model})
+	return nil
+}
+func (r *memCooldownRepo) DeleteAll(uint64) error { return nil }
+func (r *memCooldownRepo) DeleteExpired(before time.Time) error {
+	for key, cd := range r.rows {
+		if !cd.UntilTime.After(before) {
+			delete(r.rows, key)
+		}
+	}
+	return nil
+}
+func (r *memCooldownRepo) Get(providerID uint64, clientType string, model string) (*domain.Cooldown, error) {
+	if cd, ok := r.rows[CooldownKey{ProviderID: providerID, ClientType: clientType, Model: model}]; ok {
+		return cd, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func TestModelScopedCooldown(t *testing.T) {
+	m := NewManager()
+	until := time.Now().Add(time.Hour)
+	m.RecordFailure(1, "claude", "claude-*", ReasonQuotaExhausted, &until)
+
+	if !m.IsInCooldown(1, "claude", "claude-sonnet-4-5") {
+		t.Error("model family should be in cooldown")
+	}
+	if m.IsInCooldown(1, "claude", "gemini-2.5-pro") {
+		t.Error("other models of the provider should stay available")
+	}
+	if m.IsInCooldown(1, "openai", "claude-sonnet-4-5") {
+		t.Error("other client types should stay available")
+	}
+	if m.IsInCooldown(1, "claude", "") {
+		t.Error("model-scoped cooldown should not block requests without a model")
+	}
+	if got := m.GetCooldownUntil(1, "claude", "claude-opus-4-1"); !got.Equal(until) {
+		t.Errorf("GetCooldownUntil = %v, want %v", got, until)
+	}
+
+	// 其它模型的成功请求不影响该模型族的冷冻
+	m.RecordSuccess(1, "claude", "gemini-2.5-pro")
+	if !m.IsInCooldown(1, "claude", "claude-sonnet-4-5") {
+		t.Error("success of another model should not clear the cooldown")
+	}
+
+	m.RecordSuccess(1, "claude", "claude-sonnet-4-5")
+	if m.IsInCooldown(1, "claude", "claude-sonnet-4-5") {
+		t.Error("success of a covered model should clear the cooldown")
+	}
+}
+
+func TestProviderCooldownBlocksAllModels(t *testing.T) {
+	m := NewManager()
+	m.RecordFailure(1, "claude", "", ReasonServerError, nil)
+
+	if !m.IsInCooldown(1, "claude", "claude-sonnet-4-5") || !m.IsInCooldown(1, "claude", "") {
+		t.Error("provider-wide cooldown should block every model")
+	}
+}
+
+func TestClearCooldownIncludesModels(t *testing.T) {
+	m := NewManager()
+	until := time.Now().Add(time.Hour)
+	m.RecordFailure(1, "claude", "gemini-2.5-pro", ReasonRateLimit, &until)
+
+	m.ClearCooldown(1, "claude")
+	if m.IsInCooldown(1, "claude", "gemini-2.5-pro") {
+		t.Error("clearing a client type should clear its model cooldowns")
+	}
+}
+
+func TestHalfOpenSurvivesRestart(t *testing.T) {
+	repo := newMemCooldownRepo()
+	m := NewManager()
+	m.SetRepository(repo)
+	m.RecordFailure(1, "claude", "", ReasonServerError, nil)
+	m.RecordFailure(2, "claude", "gemini-2.5-pro", ReasonRateLimit, nil)
+	for key, cd := range repo.rows {
+		cd.UntilTime = time.Now().Add(-time.Second)
+		m.cooldowns[key] = cd.UntilTime
+	}
+
+	// 半开的熔断在清理后仍保留数据库记录，模型级冷冻没有半开状态，直接清除
+	m.CleanupExpired()
+	if _, ok := repo.rows[CooldownKey{ProviderID: 1, ClientType: "claude"}]; !ok {
+		t.Fatal("half-open circuit should keep its database row")
+	}
+	if _, ok := repo.rows[CooldownKey{ProviderID: 2, ClientType: "claude", Model: "gemini-2.5-pro"}]; ok {
+		t.Error("expired model cooldown should be deleted")
+	}
+
+	restarted := NewManager()
+	restarted.SetRepository(repo)
+	if err := restarted.LoadFromDatabase(); err != nil {
+		t.Fatal(err)
+	}
+	if state := restarted.GetBreakerState(1, "claude"); state != BreakerHalfOpen {
+		t.Fatalf("breaker state after restart = %s, want %s", state, BreakerHalfOpen)
+	}
+	trial, ok := restarted.AcquireTrial(1, "claude")
+	if !ok || trial == nil {
+		t.Fatal("first trial should be let through")
+	}
+	if _, ok := restarted.AcquireTrial(1, "claude"); ok {
+		t.Fatal("half-open circuit should still limit trial requests after restart")
+	}
+	trial.Release()
+
+	// 试探成功后熔断关闭，记录随之删除
+	restarted.RecordSuccess(1, "claude", "")
+	if len(repo.rows) != 0 {
+		t.Errorf("settled circuit should delete its row, got %d rows", len(repo.rows))
+	}
+}
@@ -2,6 +2,8 @@ package cooldown
 
 import (
 	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
 )
 
 // CooldownPolicy defines the interface for cooldown calculation strategies
@@ -105,3 +107,16 @@ func DefaultPolicies() map[CooldownReason]CooldownPolicy {
 		},
 	}
 }
+
+// NewPolicy builds a cooldown policy from its configuration, nil if the type is unknown
+func NewPolicy(cfg domain.CooldownPolicyConfig) CooldownPolicy {
+	switch cfg.Type {
+	case domain.CooldownPolicyFixed:
+		return &FixedDurationPolicy{Duration: time.Duration(cfg.BaseSeconds) * time.Second}
+	case domain.CooldownPolicyLinear:
+		return &LinearIncrementalPolicy{BaseSeconds: cfg.BaseSeconds, MaxSeconds: cfg.MaxSeconds}
+	case domain.CooldownPolicyExponential:
+		return &ExponentialBackoffPolicy{BaseSeconds: cfg.BaseSeconds, MaxSeconds: cfg.MaxSeconds}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package cooldown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+type stubPolicyRepo struct {
+	policies []*domain.CooldownPolicy
+}
+
+func (r *stubPolicyRepo) Create(*domain.CooldownPolicy) error { return nil }
+func (r *stubPolicyRepo) Update(*domain.CooldownPolicy) error { return nil }
+func (r *stubPolicyRepo) Delete(uint64) error                 { return nil }
+func (r *stubPolicyRepo) GetByID(uint64) (*domain.CooldownPolicy, error) {
+	return nil, domain.ErrNotFound
+}
+func (r *stubPolicyRepo) List() ([]*domain.CooldownPolicy, error) {
+	return r.policies, nil
+}
+
+func fixedPolicy(providerID uint64, reason domain.CooldownReason, seconds int) *domain.CooldownPolicy {
+	return &domain.CooldownPolicy{
+		ProviderID: providerID,
+		Reason:     reason,
+		CooldownPolicyConfig: domain.CooldownPolicyConfig{
+			Type:        domain.CooldownPolicyFixed,
+			BaseSeconds: seconds,
+		},
+	}
+}
+
+func cooldownFor(m *Manager, providerID uint64, reason CooldownReason) time.Duration {
+	return time.Until(m.RecordFailure(providerID, "claude", "", reason, nil)).Round(time.Second)
+}
+
+func TestReloadPolicies(t *testing.T) {
+	repo := &stubPolicyRepo{policies: []*domain.CooldownPolicy{
+		fixedPolicy(0, domain.CooldownReasonServerError, 60),
+		fixedPolicy(1, domain.CooldownReasonServerError, 120),
+	}}
+	m := NewManager()
+	m.SetPolicyRepository(repo)
+	if err := m.ReloadPolicies(); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cooldownFor(m, 1, ReasonServerError); d != 120*time.Second {
+		t.Errorf("provider override: got %v, want 2m", d)
+	}
+	if d := cooldownFor(m, 2, ReasonServerError); d != 60*time.Second {
+		t.Errorf("global policy: got %v, want 1m", d)
+	}
+	if d := cooldownFor(m, 2, ReasonRateLimit); d != 5*time.Second {
+		t.Errorf("built-in policy: got %v, want 5s", d)
+	}
+
+	// 删除配置后重新加载即恢复内置策略，无需重启
+	repo.policies = nil
+	if err := m.ReloadPolicies(); err != nil {
+		t.Fatal(err)
+	}
+	if d := cooldownFor(m, 3, ReasonServerError); d != 5*time.Second {
+		t.Errorf("after reload: got %v, want built-in 5s", d)
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	p := NewPolicy(domain.CooldownPolicyConfig{Type: domain.CooldownPolicyExponential, BaseSeconds: 10, MaxSeconds: 30})
+	if got := p.CalculateCooldown(3); got != 30*time.Second {
+		t.Errorf("exponential policy should be capped, got %v", got)
+	}
+	if NewPolicy(domain.CooldownPolicyConfig{Type: "bogus", BaseSeconds: 1}) != nil {
+		t.Error("unknown policy type should yield nil")
+	}
+}
@@ -1,18 +1,46 @@
 package cooldown
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
 
 // CooldownKey uniquely identifies a cooldown entry
 // ClientType is optional - empty string means cooldown applies to all client types
+// Model is optional - empty string means cooldown applies to all models of the provider,
+// otherwise it is a mapped model name or a wildcard model family (e.g. claude-*)
 type CooldownKey struct {
 	ProviderID uint64
 	ClientType string // Empty = all client types
+	Model      string // Empty = all models
+}
+
+// appliesTo reports whether this cooldown entry blocks a request for the given client type and model
+func (k CooldownKey) appliesTo(providerID uint64, clientType string, model string) bool {
+	if k.ProviderID != providerID || (k.ClientType != "" && k.ClientType != clientType) {
+		return false
+	}
+	if k.Model == "" {
+		return true
+	}
+	return model != "" && domain.MatchWildcard(k.Model, model)
+}
+
+// scope describes the client type and model of the key for log messages
+func (k CooldownKey) scope() string {
+	if k.Model == "" {
+		return "clientType=" + k.ClientType
+	}
+	return fmt.Sprintf("clientType=%s, model=%s", k.ClientType, k.Model)
 }
 
-// FailureKey tracks failures by provider, client type, and reason
+// FailureKey tracks failures by provider, client type, model, and reason
 type FailureKey struct {
 	ProviderID uint64
 	ClientType string
+	Model      string // Empty = failures not tied to a model
 	Reason     CooldownReason
 }
 
@@ -21,6 +49,7 @@ type CooldownInfo struct {
 	ProviderID   uint64         `json:"providerID"`
 	ProviderName string         `json:"providerName,omitempty"`
 	ClientType   string         `json:"clientType,omitempty"` // Empty = all types
+	Model        string         `json:"model,omitempty"`      // Empty = all models
 	Until        time.Time      `json:"until"`
 	Remaining    string         `json:"remaining"` // Human readable remaining time
 	Reason       CooldownReason `json:"reason"`    // Cooldown reason
@@ -1,21 +1,33 @@
 package core
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/awsl-project/maxx/internal/adapter/client"
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/azure_openai"
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/bedrock"
 	_ "github.com/awsl-project/maxx/internal/adapter/provider/codex"
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/copilot"
 	_ "github.com/awsl-project/maxx/internal/adapter/provider/custom"
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/local"
+	_ "github.com/awsl-project/maxx/internal/adapter/provider/vertex"
+	"github.com/awsl-project/maxx/internal/admission"
+	"github.com/awsl-project/maxx/internal/billing"
 	"github.com/awsl-project/maxx/internal/converter"
 	"github.com/awsl-project/maxx/internal/cooldown"
+	"github.com/awsl-project/maxx/internal/detailstore"
 	"github.com/awsl-project/maxx/internal/domain"
 	"github.com/awsl-project/maxx/internal/event"
 	"github.com/awsl-project/maxx/internal/executor"
 	"github.com/awsl-project/maxx/internal/handler"
+	"github.com/awsl-project/maxx/internal/health"
 	"github.com/awsl-project/maxx/internal/pricing"
+	"github.com/awsl-project/maxx/internal/report"
 	"github.com/awsl-project/maxx/internal/repository"
 	"github.com/awsl-project/maxx/internal/repository/cached"
 	"github.com/awsl-project/maxx/internal/repository/sqlite"
@@ -47,6 +59,7 @@ type DatabaseRepos struct {
 	SettingRepo               repository.SystemSettingRepository
 	AntigravityQuotaRepo      repository.AntigravityQuotaRepository
 	CodexQuotaRepo            repository.CodexQuotaRepository
+	CopilotQuotaRepo          repository.CopilotQuotaRepository
 	CooldownRepo              repository.CooldownRepository
 	FailureCountRepo          repository.FailureCountRepository
 	CachedProviderRepo        *cached.ProviderRepository
@@ -62,6 +75,14 @@ type DatabaseRepos struct {
 	UsageStatsRepo            repository.UsageStatsRepository
 	ResponseModelRepo         repository.ResponseModelRepository
 	ModelPriceRepo            repository.ModelPriceRepository
+	AuditLogRepo              repository.AuditLogRepository
+	BillingRuleRepo           repository.BillingRuleRepository
+	HealthCheckRepo           repository.ProviderHealthCheckRepository
+	CooldownPolicyRepo        repository.CooldownPolicyRepository
+	ModelFallbackRepo         repository.ModelFallbackRepository
+	CachedModelFallbackRepo   *cached.ModelFallbackRepository
+	DetailStore               detailstore.Store // 外部请求详情存储，未配置时为 nil
+	DataDir                   string
 }
 
 // ServerComponents 包含服务器运行所需的所有组件
@@ -75,10 +96,12 @@ type ServerComponents struct {
 	ProxyHandler        *handler.ProxyHandler
 	ModelsHandler       *handler.ModelsHandler
 	AdminHandler        *handler.AdminHandler
+	ProjectTokenHandler *handler.ProjectTokenHandler
 	AntigravityHandler  *handler.AntigravityHandler
 	KiroHandler         *handler.KiroHandler
 	CodexHandler        *handler.CodexHandler
 	CodexOAuthServer    *CodexOAuthServer
+	CopilotHandler      *handler.CopilotHandler
 	ProjectProxyHandler *handler.ProjectProxyHandler
 	RequestTracker      *RequestTracker
 	PprofManager        *PprofManager
@@ -100,6 +123,7 @@ func InitializeDatabase(config *DatabaseConfig) (*DatabaseRepos, error) {
 	if err != nil {
 		return nil, err
 	}
+	go db.BackfillFullTextIndex()
 
 	providerRepo := sqlite.NewProviderRepository(db)
 	routeRepo := sqlite.NewRouteRepository(db)
@@ -112,6 +136,7 @@ func InitializeDatabase(config *DatabaseConfig) (*DatabaseRepos, error) {
 	settingRepo := sqlite.NewSystemSettingRepository(db)
 	antigravityQuotaRepo := sqlite.NewAntigravityQuotaRepository(db)
 	codexQuotaRepo := sqlite.NewCodexQuotaRepository(db)
+	copilotQuotaRepo := sqlite.NewCopilotQuotaRepository(db)
 	cooldownRepo := sqlite.NewCooldownRepository(db)
 	failureCountRepo := sqlite.NewFailureCountRepository(db)
 	apiTokenRepo := sqlite.NewAPITokenRepository(db)
@@ -119,6 +144,18 @@ func InitializeDatabase(config *DatabaseConfig) (*DatabaseRepos, error) {
 	usageStatsRepo := sqlite.NewUsageStatsRepository(db)
 	responseModelRepo := sqlite.NewResponseModelRepository(db)
 	modelPriceRepo := sqlite.NewModelPriceRepository(db)
+	auditLogRepo := sqlite.NewAuditLogRepository(db)
+	billingRuleRepo := sqlite.NewBillingRuleRepository(db)
+	healthCheckRepo := sqlite.NewProviderHealthCheckRepository(db)
+	cooldownPolicyRepo := sqlite.NewCooldownPolicyRepository(db)
+	modelFallbackRepo := sqlite.NewModelFallbackRepository(db)
+
+	// 配置了外部请求详情存储却无法初始化时直接失败，避免静默丢失请求详情
+	detailStore, err := detailstore.NewFromEnv(config.DataDir)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize detail store: %w", err)
+	}
 
 	log.Printf("[Core] Creating cached repositories")
 
@@ -130,6 +167,7 @@ func InitializeDatabase(config *DatabaseConfig) (*DatabaseRepos, error) {
 	cachedProjectRepo := cached.NewProjectRepository(projectRepo)
 	cachedAPITokenRepo := cached.NewAPITokenRepository(apiTokenRepo)
 	cachedModelMappingRepo := cached.NewModelMappingRepository(modelMappingRepo)
+	cachedModelFallbackRepo := cached.NewModelFallbackRepository(modelFallbackRepo)
 
 	repos := &DatabaseRepos{
 		DB:                        db,
@@ -144,6 +182,7 @@ func InitializeDatabase(config *DatabaseConfig) (*DatabaseRepos, error) {
 		SettingRepo:               settingRepo,
 		AntigravityQuotaRepo:      antigravityQuotaRepo,
 		CodexQuotaRepo:            codexQuotaRepo,
+		CopilotQuotaRepo:          copilotQuotaRepo,
 		CooldownRepo:              cooldownRepo,
 		FailureCountRepo:          failureCountRepo,
 		CachedProviderRepo:        cachedProviderRepo,
@@ -159,6 +198,14 @@ func InitializeDatabase(config *DatabaseConfig) (*DatabaseRepos, error) {
 		UsageStatsRepo:            usageStatsRepo,
 		ResponseModelRepo:         responseModelRepo,
 		ModelPriceRepo:            modelPriceRepo,
+		AuditLogRepo:              auditLogRepo,
+		BillingRuleRepo:           billingRuleRepo,
+		HealthCheckRepo:           healthCheckRepo,
+		CooldownPolicyRepo:        cooldownPolicyRepo,
+		ModelFallbackRepo:         modelFallbackRepo,
+		CachedModelFallbackRepo:   cachedModelFallbackRepo,
+		DetailStore:               detailStore,
+		DataDir:                   config.DataDir,
 	}
 
 	log.Printf("[Core] Database initialized successfully")
@@ -180,6 +227,10 @@ func InitializeServerComponents(
 	if err := cooldown.Default().LoadFromDatabase(); err != nil {
 		log.Printf("[Core] Warning: Failed to load cooldowns from database: %v", err)
 	}
+	cooldown.Default().SetPolicyRepository(repos.CooldownPolicyRepo)
+	if err := cooldown.Default().ReloadPolicies(); err != nil {
+		log.Printf("[Core] Warning: Failed to load cooldown policies: %v", err)
+	}
 
 	log.Printf("[Core] Marking stale requests as failed")
 	if count, err := repos.ProxyRequestRepo.MarkStaleAsFailed(instanceID); err != nil {
@@ -227,11 +278,24 @@ func InitializeServerComponents(
 	if err := repos.CachedModelMappingRepo.Load(); err != nil {
 		log.Printf("[Core] Warning: Failed to load model mappings cache: %v", err)
 	}
+	if err := repos.CachedModelFallbackRepo.Load(); err != nil {
+		log.Printf("[Core] Warning: Failed to load model fallbacks cache: %v", err)
+	}
 
 	// Initialize model prices and load into Calculator
 	if err := initializeModelPrices(repos.ModelPriceRepo); err != nil {
 		log.Printf("[Core] Warning: Failed to initialize model prices: %v", err)
 	}
+	if err := loadExchangeRates(repos.SettingRepo); err != nil {
+		log.Printf("[Core] Warning: Failed to load exchange rates: %v", err)
+	}
+
+	billing.Default().SetRepositories(repos.BillingRuleRepo, repos.UsageStatsRepo, repos.SettingRepo)
+	if err := billing.Default().Reload(); err != nil {
+		log.Printf("[Core] Warning: Failed to load billing rules: %v", err)
+	}
+
+	cooldown.Default().SetProviderRepository(repos.CachedProviderRepo)
 
 	log.Printf("[Core] Creating router")
 	r := router.NewRouter(
@@ -275,6 +339,8 @@ func InitializeServerComponents(
 
 	log.Printf("[Core] Creating project waiter")
 	projectWaiter := waiter.NewProjectWaiter(repos.CachedSessionRepo, repos.SettingRepo, wailsBroadcaster)
+	loopGuard := waiter.NewLoopGuard(repos.SettingRepo)
+	admissionQueue := admission.NewQueue(repos.SettingRepo, repos.CachedProjectRepo)
 
 	log.Printf("[Core] Creating stats aggregator")
 	statsAggregator := stats.NewStatsAggregator(repos.UsageStatsRepo)
@@ -292,14 +358,17 @@ func InitializeServerComponents(
 	log.Printf("[Core] Creating executor")
 	exec := executor.NewExecutor(
 		r,
-		repos.ProxyRequestRepo,
-		repos.AttemptRepo,
+		detailstore.WrapProxyRequestRepository(repos.ProxyRequestRepo, repos.DetailStore),
+		detailstore.WrapProxyUpstreamAttemptRepository(repos.AttemptRepo, repos.DetailStore),
 		repos.CachedRetryConfigRepo,
 		repos.CachedSessionRepo,
 		repos.CachedModelMappingRepo,
+		repos.CachedModelFallbackRepo,
 		repos.SettingRepo,
 		wailsBroadcaster,
 		projectWaiter,
+		loopGuard,
+		admissionQueue,
 		instanceID,
 		statsAggregator,
 	)
@@ -326,11 +395,34 @@ func InitializeServerComponents(
 		repos.UsageStatsRepo,
 		repos.ResponseModelRepo,
 		repos.ModelPriceRepo,
+		repos.AuditLogRepo,
 		addr,
 		r,
 		wailsBroadcaster,
 		pprofMgr, // 直接传入 pprofMgr
 	)
+	adminService.SetDetailStore(repos.DetailStore)
+	adminService.SetBillingRuleRepository(repos.BillingRuleRepo)
+	adminService.SetCooldownPolicyRepository(repos.CooldownPolicyRepo)
+	adminService.SetModelFallbackRepository(repos.CachedModelFallbackRepo)
+	adminService.SetAdmissionQueue(admissionQueue)
+	adminService.SetHealthChecker(health.NewChecker(
+		repos.CachedProviderRepo,
+		repos.CachedRouteRepo,
+		repos.HealthCheckRepo,
+		r,
+		wailsBroadcaster,
+	))
+	if repos.DataDir != "" {
+		adminService.SetReportGenerator(report.NewGenerator(
+			repos.UsageStatsRepo,
+			repos.CachedProviderRepo,
+			repos.ProjectRepo,
+			repos.CachedAPITokenRepo,
+			repos.SettingRepo,
+			repos.DataDir,
+		))
+	}
 
 	log.Printf("[Core] Creating backup service")
 	backupService := service.NewBackupService(
@@ -343,7 +435,10 @@ func InitializeServerComponents(
 		repos.CachedAPITokenRepo,
 		repos.CachedModelMappingRepo,
 		repos.ModelPriceRepo,
+		repos.CooldownPolicyRepo,
+		repos.CachedModelFallbackRepo,
 		r,
+		repos.AuditLogRepo,
 	)
 
 	log.Printf("[Core] Creating handlers")
@@ -355,11 +450,13 @@ func InitializeServerComponents(
 		repos.CachedModelMappingRepo,
 	)
 	adminHandler := handler.NewAdminHandler(adminService, backupService, logPath)
+	projectTokenHandler := handler.NewProjectTokenHandler(adminService, tokenAuthMiddleware)
 	antigravityHandler := handler.NewAntigravityHandler(adminService, repos.AntigravityQuotaRepo, wailsBroadcaster)
 	kiroHandler := handler.NewKiroHandler(adminService)
 	codexHandler := handler.NewCodexHandler(adminService, repos.CodexQuotaRepo, wailsBroadcaster)
 	codexOAuthServer := NewCodexOAuthServer(codexHandler)
 	codexHandler.SetOAuthServer(codexOAuthServer)
+	copilotHandler := handler.NewCopilotHandler(adminService, repos.CopilotQuotaRepo, wailsBroadcaster)
 	projectProxyHandler := handler.NewProjectProxyHandler(proxyHandler, modelsHandler, repos.CachedProjectRepo)
 
 	log.Printf("[Core] Creating request tracker for graceful shutdown")
@@ -376,10 +473,12 @@ func InitializeServerComponents(
 		ProxyHandler:        proxyHandler,
 		ModelsHandler:       modelsHandler,
 		AdminHandler:        adminHandler,
+		ProjectTokenHandler: projectTokenHandler,
 		AntigravityHandler:  antigravityHandler,
 		KiroHandler:         kiroHandler,
 		CodexHandler:        codexHandler,
 		CodexOAuthServer:    codexOAuthServer,
+		CopilotHandler:      copilotHandler,
 		ProjectProxyHandler: projectProxyHandler,
 		RequestTracker:      requestTracker,
 		PprofManager:        pprofMgr,
@@ -389,9 +488,15 @@ func InitializeServerComponents(
 	return components, nil
 }
 
-// CloseDatabase 关闭数据库连接
+// CloseDatabase 关闭数据库连接，并等待排队中的请求详情上传完成
 func CloseDatabase(repos *DatabaseRepos) error {
-	if repos != nil && repos.DB != nil {
+	if repos == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), HTTPShutdownTimeout)
+	defer cancel()
+	_ = detailstore.Flush(ctx, repos.DetailStore)
+	if repos.DB != nil {
 		return repos.DB.Close()
 	}
 	return nil
@@ -425,6 +530,20 @@ func initializeModelPrices(repo repository.ModelPriceRepository) error {
 	return nil
 }
 
+// loadExchangeRates 加载汇率设置到全局 Calculator，用于折算非 USD 价格
+func loadExchangeRates(settingRepo repository.SystemSettingRepository) error {
+	value, err := settingRepo.Get(domain.SettingKeyExchangeRates)
+	if err != nil {
+		return err
+	}
+	rates, err := pricing.ParseExchangeRates(value)
+	if err != nil {
+		return err
+	}
+	pricing.GlobalCalculator().SetExchangeRates(rates)
+	return nil
+}
+
 // seedDefaultModelPrices 从内置价格表导入默认价格
 func seedDefaultModelPrices(repo repository.ModelPriceRepository) error {
 	pt := pricing.DefaultPriceTable()
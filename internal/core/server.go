@@ -73,9 +73,12 @@ func (s *ManagedServer) setupRoutes() *http.ServeMux {
 
 	// API routes under /api prefix (Go 1.22+ enhanced routing)
 	mux.Handle("/api/admin/", http.StripPrefix("/api", components.AdminHandler))
+	mux.Handle("/api/project/tokens", http.StripPrefix("/api", components.ProjectTokenHandler))
+	mux.Handle("/api/project/tokens/", http.StripPrefix("/api", components.ProjectTokenHandler))
 	mux.Handle("/api/antigravity/", http.StripPrefix("/api", components.AntigravityHandler))
 	mux.Handle("/api/kiro/", http.StripPrefix("/api", components.KiroHandler))
 	mux.Handle("/api/codex/", http.StripPrefix("/api", components.CodexHandler))
+	mux.Handle("/api/copilot/", http.StripPrefix("/api", components.CopilotHandler))
 
 	mux.Handle("/v1/messages", components.ProxyHandler)
 	mux.Handle("/v1/messages/", components.ProxyHandler)
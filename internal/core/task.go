@@ -7,7 +7,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/awsl-project/maxx/internal/anomaly"
+	"github.com/awsl-project/maxx/internal/detailstore"
 	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/health"
+	"github.com/awsl-project/maxx/internal/report"
 	"github.com/awsl-project/maxx/internal/repository"
 	"github.com/awsl-project/maxx/internal/repository/sqlite"
 	"github.com/awsl-project/maxx/internal/service"
@@ -15,6 +19,8 @@ import (
 
 const (
 	defaultRequestRetentionHours = 168 // 默认保留 168 小时（7天）
+	defaultAuditLogRetentionDays = 90  // 默认保留 90 天
+	healthCheckRetentionDays     = 7   // 健康探测记录保留 7 天
 
 	// sqlite maintenance throttle
 	sqliteVacuumMinInterval = time.Hour
@@ -36,6 +42,11 @@ type BackgroundTaskDeps struct {
 	ProxyRequest       repository.ProxyRequestRepository
 	AttemptRepo        repository.ProxyUpstreamAttemptRepository
 	Settings           repository.SystemSettingRepository
+	AuditLog           repository.AuditLogRepository
+	DetailStore        detailstore.Store // optional
+	ReportGenerator    *report.Generator // optional
+	AnomalyDetector    *anomaly.Detector // optional
+	HealthChecker      *health.Checker   // optional
 	AntigravityTaskSvc *service.AntigravityTaskService
 	CodexTaskSvc       *service.CodexTaskService
 }
@@ -71,6 +82,43 @@ func StartBackgroundTasks(deps BackgroundTaskDeps) {
 	// 请求详情清理任务（动态间隔）- 根据配置的保留秒数动态调整
 	go deps.runRequestDetailCleanup()
 
+	// 账单报表任务（每小时检查）- 周期结束后生成并投递一次
+	if deps.ReportGenerator != nil {
+		go func() {
+			time.Sleep(time.Minute) // 初始延迟
+			deps.runBillingReports()
+
+			ticker := time.NewTicker(1 * time.Hour)
+			for range ticker.C {
+				deps.runBillingReports()
+			}
+		}()
+	}
+
+	// 用量异常检测任务（每分钟）- 未启用时 Run 直接返回
+	if deps.AnomalyDetector != nil {
+		go func() {
+			time.Sleep(time.Minute) // 初始延迟，等待首次统计聚合完成
+			ticker := time.NewTicker(time.Minute)
+			for range ticker.C {
+				deps.runAnomalyDetection()
+			}
+		}()
+	}
+
+	// Provider 健康探测任务（每 15 秒检查一次到期的探测）- 各 Provider 按自己配置的间隔探测
+	if deps.HealthChecker != nil {
+		go func() {
+			time.Sleep(30 * time.Second) // 初始延迟，等待适配器初始化
+			deps.runHealthChecks()
+
+			ticker := time.NewTicker(15 * time.Second)
+			for range ticker.C {
+				deps.runHealthChecks()
+			}
+		}()
+	}
+
 	// Antigravity 配额刷新任务（动态间隔）
 	if deps.AntigravityTaskSvc != nil {
 		go deps.runAntigravityQuotaRefresh()
@@ -97,9 +145,53 @@ func (d *BackgroundTaskDeps) runCleanupTasks() {
 	// 3. 清理过期请求记录
 	d.cleanupOldRequests()
 
+	// 4. 清理过期审计日志
+	d.cleanupOldAuditLogs()
+
+	// 5. 清理过期健康探测记录
+	d.cleanupOldHealthChecks()
+
 	// 注：请求详情清理由独立的 runRequestDetailCleanup 任务处理（动态间隔）
 }
 
+// runBillingReports 生成上一个已结束周期的账单报表（未启用或已生成时跳过）
+func (d *BackgroundTaskDeps) runBillingReports() {
+	if _, err := d.ReportGenerator.RunDue(time.Now()); err != nil {
+		log.Printf("[Task] Failed to generate billing reports: %v", err)
+	}
+}
+
+// runAnomalyDetection 检测 API Token / 项目的用量异常
+func (d *BackgroundTaskDeps) runAnomalyDetection() {
+	if _, err := d.AnomalyDetector.Run(time.Now()); err != nil {
+		log.Printf("[Task] Failed to run anomaly detection: %v", err)
+	}
+}
+
+// runHealthChecks 探测到期的 Provider，并根据结果施加或解除冷却
+func (d *BackgroundTaskDeps) runHealthChecks() {
+	if _, err := d.HealthChecker.RunDue(context.Background(), time.Now()); err != nil {
+		log.Printf("[Task] Failed to run health checks: %v", err)
+	}
+}
+
+// cleanupOldHealthChecks 清理过期的健康探测记录
+func (d *BackgroundTaskDeps) cleanupOldHealthChecks() {
+	if d.HealthChecker == nil {
+		return
+	}
+
+	before := time.Now().AddDate(0, 0, -healthCheckRetentionDays)
+	deleted, err := d.HealthChecker.Cleanup(before)
+	if err != nil {
+		log.Printf("[Task] Failed to delete old health checks: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("[Task] Deleted %d health checks older than %d days", deleted, healthCheckRetentionDays)
+	}
+}
+
 // cleanupOldRequests 清理过期的请求记录
 func (d *BackgroundTaskDeps) cleanupOldRequests() {
 	retentionHours := defaultRequestRetentionHours
@@ -123,6 +215,7 @@ func (d *BackgroundTaskDeps) cleanupOldRequests() {
 	if deletedRequests > 0 {
 		log.Printf("[Task] Deleted %d requests older than %d hours", deletedRequests, retentionHours)
 	}
+	d.cleanupDetailBlobs(before)
 
 	// Best-effort: SQLite needs VACUUM (and WAL checkpoint) to reclaim disk space after deletes.
 	// Only attempt when using sqlite dialector and when throttling allows.
@@ -130,6 +223,34 @@ func (d *BackgroundTaskDeps) cleanupOldRequests() {
 	d.maybeSQLiteCheckpointAndVacuum(deletedRequests)
 }
 
+// cleanupOldAuditLogs 清理过期的审计日志
+func (d *BackgroundTaskDeps) cleanupOldAuditLogs() {
+	if d.AuditLog == nil {
+		return
+	}
+
+	retentionDays := defaultAuditLogRetentionDays
+	if val, err := d.Settings.Get(domain.SettingKeyAuditLogRetentionDays); err == nil && val != "" {
+		if days, err := strconv.Atoi(val); err == nil {
+			retentionDays = days
+		}
+	}
+
+	if retentionDays <= 0 {
+		return // 0 表示不清理
+	}
+
+	before := time.Now().AddDate(0, 0, -retentionDays)
+	deleted, err := d.AuditLog.DeleteOlderThan(before)
+	if err != nil {
+		log.Printf("[Task] Failed to delete old audit logs: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("[Task] Deleted %d audit logs older than %d days", deleted, retentionDays)
+	}
+}
+
 func (d *BackgroundTaskDeps) maybeSQLiteCheckpointAndVacuum(deletedRequests int64) {
 	if d.DB == nil || d.DB.Dialector() != "sqlite" {
 		return
@@ -203,6 +324,22 @@ func (d *BackgroundTaskDeps) cleanupOldRequestDetails() {
 			log.Printf("[Task] Cleared details for %d attempts older than %d seconds", deleted, seconds)
 		}
 	}
+
+	d.cleanupDetailBlobs(before)
+}
+
+// cleanupDetailBlobs 清理外部详情存储中的过期 body
+func (d *BackgroundTaskDeps) cleanupDetailBlobs(before time.Time) {
+	if d.DetailStore == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	if deleted, err := d.DetailStore.DeleteOlderThan(ctx, before); err != nil {
+		log.Printf("[Task] Failed to delete detail blobs: %v", err)
+	} else if deleted > 0 {
+		log.Printf("[Task] Deleted %d detail blobs older than %s", deleted, before.Format(time.RFC3339))
+	}
 }
 
 // runRequestDetailCleanup 动态间隔清理请求详情
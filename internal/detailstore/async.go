@@ -0,0 +1,154 @@
+package detailstore
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is returned by AsyncStore.Put when the upload queue is full;
+// callers keep the body inline instead of waiting
+var ErrQueueFull = errors.New("detail store upload queue full")
+
+const (
+	defaultUploadWorkers = 4
+	defaultUploadQueue   = 1024
+)
+
+// AsyncStore uploads blobs in the background so that S3 round trips never block the
+// proxy path. Bodies waiting for upload are served from memory; a newer Put of the same
+// key replaces the queued body instead of uploading twice.
+type AsyncStore struct {
+	store Store
+	jobs  chan string
+
+	mu      sync.Mutex
+	pending map[string]*pendingBlob
+	idle    *sync.Cond
+}
+
+type pendingBlob struct {
+	data  []byte
+	dirty bool // replaced while uploading, upload again
+}
+
+// NewAsyncStore starts workers uploading to store with at most queueSize queued keys
+func NewAsyncStore(store Store, workers, queueSize int) *AsyncStore {
+	s := &AsyncStore{
+		store:   store,
+		jobs:    make(chan string, queueSize),
+		pending: make(map[string]*pendingBlob),
+	}
+	s.idle = sync.NewCond(&s.mu)
+	for i := 0; i < workers; i++ {
+		go s.worker()
+	}
+	return s
+}
+
+// Put queues data for upload. It never blocks; ErrQueueFull means nothing was queued.
+func (s *AsyncStore) Put(_ context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.pending[key]; ok {
+		p.data = data
+		p.dirty = true
+		return nil
+	}
+	select {
+	case s.jobs <- key:
+		s.pending[key] = &pendingBlob{data: data}
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Get returns a queued body from memory, otherwise reads the backend
+func (s *AsyncStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	if p, ok := s.pending[key]; ok {
+		data := p.data
+		s.mu.Unlock()
+		return data, nil
+	}
+	s.mu.Unlock()
+	return s.store.Get(ctx, key)
+}
+
+func (s *AsyncStore) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	return s.store.DeleteOlderThan(ctx, before)
+}
+
+// Flush waits until queued uploads finish or ctx is done
+func (s *AsyncStore) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.mu.Lock()
+		for len(s.pending) > 0 {
+			s.idle.Wait()
+		}
+		s.mu.Unlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		n := len(s.pending)
+		s.mu.Unlock()
+		log.Printf("[DetailStore] Shutdown with %d uploads pending", n)
+		return ctx.Err()
+	}
+}
+
+func (s *AsyncStore) worker() {
+	for key := range s.jobs {
+		for {
+			s.mu.Lock()
+			p := s.pending[key]
+			data := p.data
+			p.dirty = false
+			s.mu.Unlock()
+
+			ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+			err := s.store.Put(ctx, key, data)
+			cancel()
+			if err != nil {
+				// 行中已记录引用，上传失败时正文丢失，加载时显示为空
+				log.Printf("[DetailStore] Failed to store %s: %v", key, err)
+			}
+
+			s.mu.Lock()
+			if p.dirty {
+				s.mu.Unlock()
+				continue
+			}
+			delete(s.pending, key)
+			if len(s.pending) == 0 {
+				s.idle.Broadcast()
+			}
+			s.mu.Unlock()
+			break
+		}
+	}
+}
+
+// Backend returns the store behind an AsyncStore, or store itself
+func Backend(store Store) Store {
+	if s, ok := store.(*AsyncStore); ok {
+		return s.store
+	}
+	return store
+}
+
+// Flush waits for queued uploads of an AsyncStore; other stores return immediately
+func Flush(ctx context.Context, store Store) error {
+	if s, ok := store.(*AsyncStore); ok {
+		return s.Flush(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package detailstore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+const storeTimeout = 30 * time.Second
+
+// ProxyRequestRepository offloads large bodies into the store before they reach the database.
+// Bodies are keyed by row ID, so later updates of the same row overwrite the same blob.
+type ProxyRequestRepository struct {
+	repository.ProxyRequestRepository
+	store Store
+}
+
+// WrapProxyRequestRepository returns repo unchanged when store is nil
+func WrapProxyRequestRepository(repo repository.ProxyRequestRepository, store Store) repository.ProxyRequestRepository {
+	if store == nil {
+		return repo
+	}
+	return &ProxyRequestRepository{ProxyRequestRepository: repo, store: store}
+}
+
+func (r *ProxyRequestRepository) Create(p *domain.ProxyRequest) error {
+	if !isLarge(p.RequestInfo) && !isLargeResponse(p.ResponseInfo) {
+		return r.ProxyRequestRepository.Create(p)
+	}
+	// 需要 ID 生成 key：先写入不含 body 的行，再上传并更新
+	reqInfo, respInfo := p.RequestInfo, p.ResponseInfo
+	p.RequestInfo, p.ResponseInfo = withoutBody(reqInfo), withoutResponseBody(respInfo)
+	err := r.ProxyRequestRepository.Create(p)
+	p.RequestInfo, p.ResponseInfo = reqInfo, respInfo
+	if err != nil {
+		return err
+	}
+	return r.Update(p)
+}
+
+func (r *ProxyRequestRepository) Update(p *domain.ProxyRequest) error {
+	if p.ID != 0 {
+		p.RequestInfo = offloadRequest(r.store, RequestKey(p.ID, "request"), p.RequestInfo)
+		p.ResponseInfo = offloadResponse(r.store, RequestKey(p.ID, "response"), p.ResponseInfo)
+	}
+	return r.ProxyRequestRepository.Update(p)
+}
+
+// ProxyUpstreamAttemptRepository is the attempt counterpart of ProxyRequestRepository
+type ProxyUpstreamAttemptRepository struct {
+	repository.ProxyUpstreamAttemptRepository
+	store Store
+}
+
+// WrapProxyUpstreamAttemptRepository returns repo unchanged when store is nil
+func WrapProxyUpstreamAttemptRepository(repo repository.ProxyUpstreamAttemptRepository, store Store) repository.ProxyUpstreamAttemptRepository {
+	if store == nil {
+		return repo
+	}
+	return &ProxyUpstreamAttemptRepository{ProxyUpstreamAttemptRepository: repo, store: store}
+}
+
+func (r *ProxyUpstreamAttemptRepository) Create(a *domain.ProxyUpstreamAttempt) error {
+	if !isLarge(a.RequestInfo) && !isLargeResponse(a.ResponseInfo) {
+		return r.ProxyUpstreamAttemptRepository.Create(a)
+	}
+	reqInfo, respInfo := a.RequestInfo, a.ResponseInfo
+	a.RequestInfo, a.ResponseInfo = withoutBody(reqInfo), withoutResponseBody(respInfo)
+	err := r.ProxyUpstreamAttemptRepository.Create(a)
+	a.RequestInfo, a.ResponseInfo = reqInfo, respInfo
+	if err != nil {
+		return err
+	}
+	return r.Update(a)
+}
+
+func (r *ProxyUpstreamAttemptRepository) Update(a *domain.ProxyUpstreamAttempt) error {
+	if a.ID != 0 {
+		a.RequestInfo = offloadRequest(r.store, AttemptKey(a.ID, "request"), a.RequestInfo)
+		a.ResponseInfo = offloadResponse(r.store, AttemptKey(a.ID, "response"), a.ResponseInfo)
+	}
+	return r.ProxyUpstreamAttemptRepository.Update(a)
+}
+
+// LoadProxyRequest fills offloaded bodies of a request in place
+func LoadProxyRequest(ctx context.Context, store Store, p *domain.ProxyRequest) {
+	if store == nil || p == nil {
+		return
+	}
+	p.RequestInfo = loadRequest(ctx, store, p.RequestInfo)
+	p.ResponseInfo = loadResponse(ctx, store, p.ResponseInfo)
+}
+
+// LoadAttempts fills offloaded bodies of attempts in place
+func LoadAttempts(ctx context.Context, store Store, attempts []*domain.ProxyUpstreamAttempt) {
+	if store == nil {
+		return
+	}
+	for _, a := range attempts {
+		a.RequestInfo = loadRequest(ctx, store, a.RequestInfo)
+		a.ResponseInfo = loadResponse(ctx, store, a.ResponseInfo)
+	}
+}
+
+func isLarge(info *domain.RequestInfo) bool {
+	return info != nil && len(info.Body) >= InlineLimit
+}
+
+func isLargeResponse(info *domain.ResponseInfo) bool {
+	return info != nil && len(info.Body) >= InlineLimit
+}
+
+func withoutBody(info *domain.RequestInfo) *domain.RequestInfo {
+	if info == nil {
+		return nil
+	}
+	copied := *info
+	copied.Body = ""
+	return &copied
+}
+
+func withoutResponseBody(info *domain.ResponseInfo) *domain.ResponseInfo {
+	if info == nil {
+		return nil
+	}
+	copied := *info
+	copied.Body = ""
+	return &copied
+}
+
+// offloadRequest returns a copy referencing the stored body. The original is never
+// modified because request infos are shared between a request and its attempts.
+// On store errors the body stays inline.
+func offloadRequest(store Store, key string, info *domain.RequestInfo) *domain.RequestInfo {
+	if !isLarge(info) {
+		return info
+	}
+	if !put(store, key, info.Body) {
+		return info
+	}
+	copied := *info
+	copied.Body = ""
+	copied.BodyRef = key
+	return &copied
+}
+
+func offloadResponse(store Store, key string, info *domain.ResponseInfo) *domain.ResponseInfo {
+	if !isLargeResponse(info) {
+		return info
+	}
+	if !put(store, key, info.Body) {
+		return info
+	}
+	copied := *info
+	copied.Body = ""
+	copied.BodyRef = key
+	return &copied
+}
+
+func put(store Store, key, body string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	if err := store.Put(ctx, key, []byte(body)); err != nil {
+		log.Printf("[DetailStore] Failed to store %s, keeping body inline: %v", key, err)
+		return false
+	}
+	return true
+}
+
+func loadRequest(ctx context.Context, store Store, info *domain.RequestInfo) *domain.RequestInfo {
+	if info == nil || info.BodyRef == "" || info.Body != "" {
+		return info
+	}
+	body, err := store.Get(ctx, info.BodyRef)
+	if err != nil {
+		log.Printf("[DetailStore] Failed to load %s: %v", info.BodyRef, err)
+		return info
+	}
+	copied := *info
+	copied.Body = string(body)
+	return &copied
+}
+
+func loadResponse(ctx context.Context, store Store, info *domain.ResponseInfo) *domain.ResponseInfo {
+	if info == nil || info.BodyRef == "" || info.Body != "" {
+		return info
+	}
+	body, err := store.Get(ctx, info.BodyRef)
+	if err != nil {
+		log.Printf("[DetailStore] Failed to load %s: %v", info.BodyRef, err)
+		return info
+	}
+	copied := *info
+	copied.Body = string(body)
+	return &copied
+}
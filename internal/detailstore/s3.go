@@ -0,0 +1,186 @@
+package detailstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/sigv4"
+)
+
+// S3Config configures an S3-compatible bucket (MinIO, Garage, R2, AWS S3...)
+type S3Config struct {
+	Endpoint  string // e.g. http://127.0.0.1:9000
+	Bucket    string
+	Region    string // defaults to us-east-1
+	AccessKey string
+	SecretKey string
+	Prefix    string // optional key prefix
+}
+
+// S3Store keeps blobs in an S3-compatible bucket using path-style requests
+type S3Store struct {
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+	signer   sigv4.Signer
+	client   *http.Client
+}
+
+// NewS3Store validates the configuration
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("s3 detail store requires endpoint and bucket")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	prefix := strings.Trim(cfg.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &S3Store{
+		endpoint: endpoint,
+		bucket:   cfg.Bucket,
+		prefix:   prefix,
+		signer: sigv4.Signer{
+			Credentials: sigv4.Credentials{AccessKeyID: cfg.AccessKey, SecretAccessKey: cfg.SecretKey},
+			Region:      region,
+			Service:     "s3",
+		},
+		client: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+func (s *S3Store) objectURL(objectKey string) *url.URL {
+	u := *s.endpoint
+	u.Path = u.Path + "/" + s.bucket + "/" + objectKey
+	return &u
+}
+
+func (s *S3Store) do(ctx context.Context, method string, u *url.URL, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/zstd")
+	}
+	// S3 requires the payload hash as a header as well
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	s.signer.SignWithPayloadHash(req, payloadHash, time.Now())
+	return s.client.Do(req)
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, s.objectURL(s.prefix+key+blobExt), compress(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(s.prefix+key+blobExt), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, s3Error(resp)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(data)
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *S3Store) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	deleted := 0
+	token := ""
+	for {
+		u := s.objectURL("")
+		q := url.Values{}
+		q.Set("list-type", "2")
+		if s.prefix != "" {
+			q.Set("prefix", s.prefix)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		u.RawQuery = q.Encode()
+
+		resp, err := s.do(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return deleted, err
+		}
+		if resp.StatusCode/100 != 2 {
+			err := s3Error(resp)
+			resp.Body.Close()
+			return deleted, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, obj := range result.Contents {
+			if !obj.LastModified.Before(before) || !strings.HasSuffix(obj.Key, blobExt) {
+				continue
+			}
+			resp, err := s.do(ctx, http.MethodDelete, s.objectURL(obj.Key), nil)
+			if err != nil {
+				return deleted, err
+			}
+			resp.Body.Close()
+			if resp.StatusCode/100 == 2 {
+				deleted++
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return deleted, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
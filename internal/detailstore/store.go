@@ -0,0 +1,112 @@
+// Package detailstore keeps request/response bodies of proxy requests outside the
+// database. Rows only hold a reference (RequestInfo.BodyRef / ResponseInfo.BodyRef)
+// and the admin API loads bodies on demand.
+package detailstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ErrNotFound is returned when a blob does not exist (e.g. already cleaned up)
+var ErrNotFound = errors.New("detail blob not found")
+
+// InlineLimit bodies smaller than this stay in the database row
+const InlineLimit = 4 * 1024
+
+// Store persists compressed detail blobs
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// DeleteOlderThan removes blobs last written before the given time
+	DeleteOlderThan(ctx context.Context, before time.Time) (int, error)
+}
+
+// Environment variables
+const (
+	EnvStore       = "MAXX_DETAIL_STORE" // "file" | "s3"，为空时禁用
+	EnvDir         = "MAXX_DETAIL_STORE_DIR"
+	EnvS3Endpoint  = "MAXX_DETAIL_STORE_S3_ENDPOINT"
+	EnvS3Bucket    = "MAXX_DETAIL_STORE_S3_BUCKET"
+	EnvS3Region    = "MAXX_DETAIL_STORE_S3_REGION"
+	EnvS3AccessKey = "MAXX_DETAIL_STORE_S3_ACCESS_KEY"
+	EnvS3SecretKey = "MAXX_DETAIL_STORE_S3_SECRET_KEY"
+	EnvS3Prefix    = "MAXX_DETAIL_STORE_S3_PREFIX"
+)
+
+// NewFromEnv creates the configured store, or returns nil when disabled.
+// The file store defaults to <dataDir>/details. Uploads run in the background (see AsyncStore).
+func NewFromEnv(dataDir string) (Store, error) {
+	store, err := newBackendFromEnv(dataDir)
+	if store == nil || err != nil {
+		return nil, err
+	}
+	return NewAsyncStore(store, defaultUploadWorkers, defaultUploadQueue), nil
+}
+
+func newBackendFromEnv(dataDir string) (Store, error) {
+	switch kind := strings.ToLower(strings.TrimSpace(os.Getenv(EnvStore))); kind {
+	case "":
+		return nil, nil
+	case "file":
+		dir := os.Getenv(EnvDir)
+		if dir == "" {
+			dir = filepath.Join(dataDir, "details")
+		}
+		return NewFileStore(dir)
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  os.Getenv(EnvS3Endpoint),
+			Bucket:    os.Getenv(EnvS3Bucket),
+			Region:    os.Getenv(EnvS3Region),
+			AccessKey: os.Getenv(EnvS3AccessKey),
+			SecretKey: os.Getenv(EnvS3SecretKey),
+			Prefix:    os.Getenv(EnvS3Prefix),
+		})
+	default:
+		return nil, fmt.Errorf("unknown %s %q (use file or s3)", EnvStore, kind)
+	}
+}
+
+// RequestKey returns the blob key of a proxy request body ("request" or "response").
+// Keys are sharded by 10000 IDs to keep directories small.
+func RequestKey(id uint64, part string) string {
+	return fmt.Sprintf("requests/%d/%d.%s", id/10000, id, part)
+}
+
+// AttemptKey returns the blob key of an upstream attempt body
+func AttemptKey(id uint64, part string) string {
+	return fmt.Sprintf("attempts/%d/%d.%s", id/10000, id, part)
+}
+
+var (
+	codecOnce sync.Once
+	encoder   *zstd.Encoder
+	decoder   *zstd.Decoder
+)
+
+func codecs() (*zstd.Encoder, *zstd.Decoder) {
+	codecOnce.Do(func() {
+		encoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+		decoder, _ = zstd.NewReader(nil)
+	})
+	return encoder, decoder
+}
+
+func compress(data []byte) []byte {
+	enc, _ := codecs()
+	return enc.EncodeAll(data, nil)
+}
+
+func decompress(data []byte) ([]byte, error) {
+	_, dec := codecs()
+	return dec.DecodeAll(data, nil)
+}
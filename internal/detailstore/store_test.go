@@ -0,0 +1,154 @@
+package detailstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	key := RequestKey(12345, "request")
+
+	if err := store.Put(ctx, key, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.Get(ctx, key)
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("Get = %q, %v", data, err)
+	}
+
+	if _, err := store.Get(ctx, RequestKey(1, "request")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := store.Put(ctx, "../escape", []byte("x")); err == nil {
+		t.Fatal("expected invalid key error")
+	}
+
+	n, err := store.DeleteOlderThan(ctx, time.Now().Add(time.Minute))
+	if err != nil || n != 1 {
+		t.Fatalf("DeleteOlderThan = %d, %v", n, err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected blob to be deleted, got %v", err)
+	}
+}
+
+type fakeProxyRequestRepo struct {
+	repository.ProxyRequestRepository
+	saved *domain.ProxyRequest
+}
+
+func (r *fakeProxyRequestRepo) Create(p *domain.ProxyRequest) error {
+	p.ID = 7
+	copied := *p
+	r.saved = &copied
+	return nil
+}
+
+func (r *fakeProxyRequestRepo) Update(p *domain.ProxyRequest) error {
+	copied := *p
+	r.saved = &copied
+	return nil
+}
+
+func TestProxyRequestRepository_OffloadsLargeBodies(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner := &fakeProxyRequestRepo{}
+	repo := WrapProxyRequestRepository(inner, store)
+
+	large := strings.Repeat("x", InlineLimit)
+	shared := &domain.RequestInfo{Method: "POST", Body: large}
+	p := &domain.ProxyRequest{
+		RequestInfo:  shared,
+		ResponseInfo: &domain.ResponseInfo{Status: 200, Body: "small"},
+	}
+	if err := repo.Create(p); err != nil {
+		t.Fatal(err)
+	}
+
+	if inner.saved.RequestInfo.Body != "" || inner.saved.RequestInfo.BodyRef != RequestKey(7, "request") {
+		t.Fatalf("request body not offloaded: %+v", inner.saved.RequestInfo)
+	}
+	if inner.saved.ResponseInfo.Body != "small" || inner.saved.ResponseInfo.BodyRef != "" {
+		t.Fatalf("small response body should stay inline: %+v", inner.saved.ResponseInfo)
+	}
+	if shared.Body != large {
+		t.Fatal("shared request info must not be modified")
+	}
+
+	loaded := inner.saved
+	LoadProxyRequest(context.Background(), store, loaded)
+	if loaded.RequestInfo.Body != large {
+		t.Fatalf("body not hydrated, got %d bytes", len(loaded.RequestInfo.Body))
+	}
+}
+
+// blockingStore holds Put until release is closed
+type blockingStore struct {
+	Store
+	release chan struct{}
+}
+
+func (s *blockingStore) Put(ctx context.Context, key string, data []byte) error {
+	<-s.release
+	return s.Store.Put(ctx, key, data)
+}
+
+func TestAsyncStore_QueuesUploads(t *testing.T) {
+	file, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &blockingStore{Store: file, release: make(chan struct{})}
+	store := NewAsyncStore(backend, 1, 1)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, RequestKey(1, "request"), []byte("v1")); err != nil {
+		t.Fatal(err)
+	}
+	// 同一 key 的更新覆盖排队中的正文，不占用队列
+	if err := store.Put(ctx, RequestKey(1, "request"), []byte("v2")); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := store.Get(ctx, RequestKey(1, "request")); err != nil || string(data) != "v2" {
+		t.Fatalf("pending Get = %q, %v", data, err)
+	}
+
+	// 队列已满时立即返回，由调用方保留内联正文
+	deadline := time.Now().Add(time.Second)
+	for {
+		err = store.Put(ctx, RequestKey(2, "request"), []byte("x"))
+		if err == nil {
+			err = store.Put(ctx, RequestKey(3, "request"), []byte("y"))
+		}
+		if errors.Is(err, ErrQueueFull) || time.Now().After(deadline) {
+			break
+		}
+	}
+	if !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	close(backend.release)
+	flushCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := store.Flush(flushCtx); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := file.Get(ctx, RequestKey(1, "request")); err != nil || string(data) != "v2" {
+		t.Fatalf("uploaded = %q, %v", data, err)
+	}
+}
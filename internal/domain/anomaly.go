@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// 异常检测指标
+const (
+	AnomalyMetricRequestRate = "request_rate" // 每小时请求数
+	AnomalyMetricTokenRate   = "token_rate"   // 每小时 Token 数
+	AnomalyMetricCostRate    = "cost_rate"    // 每小时成本（纳美元）
+	AnomalyMetricErrorRate   = "error_rate"   // 错误率（百分比）
+)
+
+// 异常检测维度
+const (
+	AnomalyDimensionAPIToken = "api_token"
+	AnomalyDimensionProject  = "project"
+)
+
+// AnomalyAlert 用量异常告警：某个 API Token 或项目在检测窗口内的指标明显偏离基线
+type AnomalyAlert struct {
+	Time       time.Time `json:"time"`
+	Dimension  string    `json:"dimension"` // api_token / project
+	TargetID   uint64    `json:"targetID"`
+	TargetName string    `json:"targetName"`
+	Metric     string    `json:"metric"`
+
+	// 速率类指标为每小时速率，错误率为百分比
+	Current   float64 `json:"current"`
+	Baseline  float64 `json:"baseline"`
+	Threshold float64 `json:"threshold"`
+
+	// 是否已自动禁用对应的 API Token
+	TokenDisabled bool `json:"tokenDisabled"`
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+)
+
+// APITokenScope API Token 的权限范围
+// 所有列表为空时表示不限制
+type APITokenScope struct {
+	// 允许的客户端类型
+	AllowedClientTypes []ClientType `json:"allowedClientTypes,omitempty"`
+
+	// 允许的模型（支持通配符，如 claude-*）
+	AllowedModels []string `json:"allowedModels,omitempty"`
+
+	// 允许使用的供应商 ID
+	AllowedProviderIDs []uint64 `json:"allowedProviderIDs,omitempty"`
+
+	// IP 白名单，支持单个 IP 或 CIDR
+	AllowedIPs []string `json:"allowedIPs,omitempty"`
+
+	// 项目管理员：可通过自助接口为所属项目创建/吊销 Token
+	ProjectAdmin bool `json:"projectAdmin"`
+}
+
+// AllowsClientType 检查客户端类型是否在允许范围内
+func (s *APITokenScope) AllowsClientType(clientType ClientType) bool {
+	return len(s.AllowedClientTypes) == 0 || slices.Contains(s.AllowedClientTypes, clientType)
+}
+
+// AllowsModel 检查模型是否在允许范围内
+func (s *APITokenScope) AllowsModel(model string) bool {
+	if len(s.AllowedModels) == 0 {
+		return true
+	}
+	for _, pattern := range s.AllowedModels {
+		if MatchWildcard(pattern, model) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsProvider 检查供应商是否在允许范围内
+func (s *APITokenScope) AllowsProvider(providerID uint64) bool {
+	return len(s.AllowedProviderIDs) == 0 || slices.Contains(s.AllowedProviderIDs, providerID)
+}
+
+// AllowsIP 检查客户端 IP 是否在白名单内
+func (s *APITokenScope) AllowsIP(ip string) bool {
+	if len(s.AllowedIPs) == 0 {
+		return true
+	}
+	addr := net.ParseIP(strings.TrimSpace(ip))
+	if addr == nil {
+		return false
+	}
+	for _, entry := range s.AllowedIPs {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 检查 IP 白名单格式
+func (s *APITokenScope) Validate() error {
+	for _, entry := range s.AllowedIPs {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				return fmt.Errorf("%w: invalid CIDR %q", ErrInvalidInput, entry)
+			}
+			continue
+		}
+		if net.ParseIP(entry) == nil {
+			return fmt.Errorf("%w: invalid IP %q", ErrInvalidInput, entry)
+		}
+	}
+	return nil
+}
+
+// Restrict 将 child 限制在 s 的范围内：child 未设置的维度继承 s，
+// 已设置的维度必须是 s 的子集。返回 false 表示 child 超出了 s 的权限。
+// 派生 Token 永远不能成为项目管理员。
+func (s *APITokenScope) Restrict(child APITokenScope) (APITokenScope, bool) {
+	result := APITokenScope{}
+
+	switch {
+	case len(child.AllowedClientTypes) == 0:
+		result.AllowedClientTypes = slices.Clone(s.AllowedClientTypes)
+	default:
+		for _, ct := range child.AllowedClientTypes {
+			if !s.AllowsClientType(ct) {
+				return APITokenScope{}, false
+			}
+		}
+		result.AllowedClientTypes = slices.Clone(child.AllowedClientTypes)
+	}
+
+	switch {
+	case len(child.AllowedModels) == 0:
+		result.AllowedModels = slices.Clone(s.AllowedModels)
+	default:
+		// 通配符模式无法可靠判断包含关系，要求字面量匹配或父级不限制
+		for _, m := range child.AllowedModels {
+			if len(s.AllowedModels) > 0 && !slices.Contains(s.AllowedModels, m) && (strings.Contains(m, "*") || !s.AllowsModel(m)) {
+				return APITokenScope{}, false
+			}
+		}
+		result.AllowedModels = slices.Clone(child.AllowedModels)
+	}
+
+	switch {
+	case len(child.AllowedProviderIDs) == 0:
+		result.AllowedProviderIDs = slices.Clone(s.AllowedProviderIDs)
+	default:
+		for _, id := range child.AllowedProviderIDs {
+			if !s.AllowsProvider(id) {
+				return APITokenScope{}, false
+			}
+		}
+		result.AllowedProviderIDs = slices.Clone(child.AllowedProviderIDs)
+	}
+
+	switch {
+	case len(child.AllowedIPs) == 0:
+		result.AllowedIPs = slices.Clone(s.AllowedIPs)
+	case len(s.AllowedIPs) == 0:
+		result.AllowedIPs = slices.Clone(child.AllowedIPs)
+	default:
+		// 有父级白名单时只允许原样收窄
+		for _, ip := range child.AllowedIPs {
+			if !slices.Contains(s.AllowedIPs, ip) && (strings.Contains(ip, "/") || !s.AllowsIP(ip)) {
+				return APITokenScope{}, false
+			}
+		}
+		result.AllowedIPs = slices.Clone(child.AllowedIPs)
+	}
+
+	return result, true
+}
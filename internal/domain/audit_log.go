@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// AuditAction 审计操作类型
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+	AuditActionImport AuditAction = "import"
+	AuditActionReset  AuditAction = "reset"
+)
+
+// 审计实体类型
+const (
+	AuditEntityProvider        = "provider"
+	AuditEntityRoute           = "route"
+	AuditEntityProject         = "project"
+	AuditEntitySession         = "session"
+	AuditEntityRetryConfig     = "retry_config"
+	AuditEntityRoutingStrategy = "routing_strategy"
+	AuditEntityAPIToken        = "api_token"
+	AuditEntityModelMapping    = "model_mapping"
+	AuditEntityModelPrice      = "model_price"
+	AuditEntitySetting         = "setting"
+	AuditEntityCooldown        = "cooldown"
+	AuditEntityBackup          = "backup"
+	AuditEntityBillingRule     = "billing_rule"
+	AuditEntityCooldownPolicy  = "cooldown_policy"
+	AuditEntityModelFallback   = "model_fallback"
+)
+
+// AuditChange 单个字段的变更（路径使用点号分隔，如 config.custom.baseURL）
+type AuditChange struct {
+	Path   string `json:"path"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// AuditLog 管理配置变更审计日志
+// Before/After 为脱敏后的 JSON 快照，Changes 为两者的字段级差异
+type AuditLog struct {
+	ID        uint64    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// 操作者（JWT subject，未启用认证时为 anonymous）
+	Actor    string `json:"actor"`
+	ClientIP string `json:"clientIP,omitempty"`
+
+	Action     AuditAction `json:"action"`
+	EntityType string      `json:"entityType"`
+	EntityID   string      `json:"entityID,omitempty"`
+	EntityName string      `json:"entityName,omitempty"`
+
+	Before  string        `json:"before,omitempty"`
+	After   string        `json:"after,omitempty"`
+	Changes []AuditChange `json:"changes,omitempty"`
+}
@@ -24,6 +24,8 @@ type BackupData struct {
 	APITokens         []BackupAPIToken        `json:"apiTokens,omitempty"`
 	ModelMappings     []BackupModelMapping    `json:"modelMappings,omitempty"`
 	ModelPrices       []BackupModelPrice      `json:"modelPrices,omitempty"`
+	CooldownPolicies  []BackupCooldownPolicy  `json:"cooldownPolicies,omitempty"`
+	ModelFallbacks    []BackupModelFallback   `json:"modelFallbacks,omitempty"`
 }
 
 // BackupSystemSetting represents a system setting for backup
@@ -87,6 +89,14 @@ type BackupAPIToken struct {
 	IsEnabled   bool       `json:"isEnabled"`
 	DevMode     bool       `json:"devMode"`
 	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+
+	// 权限范围，供应商使用名称引用
+	AllowedClientTypes   []ClientType `json:"allowedClientTypes,omitempty"`
+	AllowedModels        []string     `json:"allowedModels,omitempty"`
+	AllowedProviderNames []string     `json:"allowedProviderNames,omitempty"`
+	ProviderRestricted   bool         `json:"providerRestricted,omitempty"` // 限制了供应商，即使所引用的供应商均已删除
+	AllowedIPs           []string     `json:"allowedIPs,omitempty"`
+	ProjectAdmin         bool         `json:"projectAdmin,omitempty"`
 }
 
 // BackupModelMapping represents a model mapping for backup
@@ -106,6 +116,9 @@ type BackupModelMapping struct {
 // BackupModelPrice represents a model price for backup
 type BackupModelPrice struct {
 	ModelID                string `json:"modelId"`
+	ProviderName           string `json:"providerName,omitempty"` // 为空表示全局价格
+	Currency               string `json:"currency,omitempty"`
+	Source                 string `json:"source,omitempty"`
 	InputPriceMicro        uint64 `json:"inputPriceMicro"`
 	OutputPriceMicro       uint64 `json:"outputPriceMicro"`
 	CacheReadPriceMicro    uint64 `json:"cacheReadPriceMicro"`
@@ -149,3 +162,22 @@ func NewImportResult() *ImportResult {
 		Warnings: []string{},
 	}
 }
+
+// BackupCooldownPolicy represents a cooldown policy for backup
+type BackupCooldownPolicy struct {
+	ProviderName string             `json:"providerName,omitempty"` // 为空表示全局策略
+	Reason       CooldownReason     `json:"reason"`
+	Type         CooldownPolicyType `json:"type"`
+	BaseSeconds  int                `json:"baseSeconds"`
+	MaxSeconds   int                `json:"maxSeconds,omitempty"`
+}
+
+// BackupModelFallback represents a model fallback chain for backup
+type BackupModelFallback struct {
+	ClientType   ClientType `json:"clientType,omitempty"`
+	ProjectSlug  string     `json:"projectSlug,omitempty"` // 为空表示所有项目
+	Pattern      string     `json:"pattern"`
+	Models       []string   `json:"models"`
+	NotifyClient bool       `json:"notifyClient,omitempty"`
+	Priority     int        `json:"priority"`
+}
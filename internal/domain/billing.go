@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// BillingRule 计费规则：在成本（已应用 Provider 倍率）的基础上计算向客户计费的金额
+// 规则匹配优先级：API Token > 项目；未匹配规则时计费金额等于成本
+type BillingRule struct {
+	ID        uint64    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// 软删除时间
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+
+	Name string `json:"name"`
+
+	// 作用对象，二者必须且只能设置一个
+	ProjectID  uint64 `json:"projectID"`
+	APITokenID uint64 `json:"apiTokenID"`
+
+	// 加价比例（万分比，2000 = +20%，-1000 = 9 折）
+	MarkupBps int64 `json:"markupBps"`
+	// 每个成功请求的固定费用 (纳美元)
+	RequestFee uint64 `json:"requestFee"`
+	// 每自然月免费额度 (纳美元)，按计费金额抵扣，0 表示无免费额度
+	FreeTierMonthly uint64 `json:"freeTierMonthly"`
+
+	IsEnabled bool `json:"isEnabled"`
+}
+
+// Quote 计算单次尝试在扣除免费额度前的计费金额
+func (r *BillingRule) Quote(cost uint64, completed bool) uint64 {
+	if r == nil {
+		return cost
+	}
+	billed := int64(cost) + int64(cost)*r.MarkupBps/10000
+	if billed < 0 {
+		billed = 0
+	}
+	if completed {
+		billed += int64(r.RequestFee)
+	}
+	return uint64(billed)
+}
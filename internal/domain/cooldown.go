@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // CooldownReason represents the reason for cooldown
 type CooldownReason string
@@ -20,7 +23,64 @@ type Cooldown struct {
 	CreatedAt  time.Time      `json:"createdAt"`
 	UpdatedAt  time.Time      `json:"updatedAt"`
 	ProviderID uint64         `json:"providerID"`
-	ClientType string         `json:"clientType"` // Empty for global cooldown
-	UntilTime  time.Time      `json:"untilTime"`  // Absolute time when cooldown ends
-	Reason     CooldownReason `json:"reason"`     // Reason for cooldown
+	ClientType string         `json:"clientType"`      // Empty for global cooldown
+	Model      string         `json:"model,omitempty"` // Empty for all models, otherwise a model name or wildcard family (e.g. claude-*)
+	UntilTime  time.Time      `json:"untilTime"`       // Absolute time when cooldown ends
+	Reason     CooldownReason `json:"reason"`          // Reason for cooldown
+}
+
+// CooldownPolicyType 冷却时长计算方式
+type CooldownPolicyType string
+
+const (
+	CooldownPolicyFixed       CooldownPolicyType = "fixed"       // 固定时长 BaseSeconds
+	CooldownPolicyLinear      CooldownPolicyType = "linear"      // BaseSeconds * 失败次数
+	CooldownPolicyExponential CooldownPolicyType = "exponential" // BaseSeconds * 2^(失败次数-1)
+)
+
+// CooldownPolicyConfig 可配置的冷却时长策略
+type CooldownPolicyConfig struct {
+	Type        CooldownPolicyType `json:"type"`
+	BaseSeconds int                `json:"baseSeconds"`
+	MaxSeconds  int                `json:"maxSeconds,omitempty"` // 上限，0 表示不限制（fixed 忽略）
+}
+
+// Validate 检查冷却策略是否合法
+func (p CooldownPolicyConfig) Validate() error {
+	switch p.Type {
+	case CooldownPolicyFixed, CooldownPolicyLinear, CooldownPolicyExponential:
+	default:
+		return fmt.Errorf("%w: unsupported cooldown policy type %q (use fixed, linear or exponential)", ErrInvalidInput, p.Type)
+	}
+	if p.BaseSeconds <= 0 {
+		return fmt.Errorf("%w: cooldown policy baseSeconds must be positive", ErrInvalidInput)
+	}
+	if p.MaxSeconds < 0 {
+		return fmt.Errorf("%w: cooldown policy maxSeconds must not be negative", ErrInvalidInput)
+	}
+	return nil
+}
+
+// CooldownPolicyReasons 可配置冷却策略的原因（手动冻结不走策略）
+var CooldownPolicyReasons = []CooldownReason{
+	CooldownReasonServerError,
+	CooldownReasonNetworkError,
+	CooldownReasonQuotaExhausted,
+	CooldownReasonRateLimitExceeded,
+	CooldownReasonConcurrentLimit,
+	CooldownReasonUnknown,
+}
+
+// CooldownPolicy 持久化的冷却时长策略
+// ProviderID 为 0 时是全局默认，否则只作用于该 Provider
+// 优先级：Provider 覆盖 > 全局默认 > 内置策略
+type CooldownPolicy struct {
+	ID        uint64    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	ProviderID uint64         `json:"providerID"`
+	Reason     CooldownReason `json:"reason"`
+
+	CooldownPolicyConfig
 }
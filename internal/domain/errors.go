@@ -41,6 +41,7 @@ type RateLimitInfo struct {
     QuotaResetTime   time.Time // When quota resets (for quota exhaustion)
     RetryHintMessage string    // Original error message with retry hints
     ClientType       string    // Affected client type (empty = all)
+    Model            string    // Affected model or wildcard model family (empty = all models)
 }
 
 func (e *ProxyError) Error() string {
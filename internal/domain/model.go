@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"crypto/x509"
+	"fmt"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -130,6 +133,119 @@ type ProviderConfigCodex struct {
 	UseCLIProxyAPI bool `json:"useCLIProxyAPI,omitempty"`
 }
 
+type ProviderConfigBedrock struct {
+	// AWS 区域，默认 us-east-1
+	Region string `json:"region,omitempty"`
+
+	// 静态凭证（IAM 用户或临时凭证）
+	AccessKeyID     string `json:"accessKeyID"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	SessionToken    string `json:"sessionToken,omitempty"`
+
+	// 可选: 使用上面的凭证调用 STS AssumeRole，以该角色的临时凭证访问 Bedrock
+	RoleARN    string `json:"roleARN,omitempty"`
+	ExternalID string `json:"externalID,omitempty"`
+
+	// 自定义 Endpoint（如 VPC Endpoint），为空时使用 https://bedrock-runtime.{region}.amazonaws.com
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// 跨区域推理配置前缀 (us / eu / apac / global)，为空时直接使用基础 Model ID
+	InferenceProfilePrefix string `json:"inferenceProfilePrefix,omitempty"`
+
+	// Model 映射: RequestModel → Bedrock Model ID 或 Inference Profile ARN
+	ModelMapping map[string]string `json:"modelMapping,omitempty"`
+}
+
+type ProviderConfigVertex struct {
+	// GCP 项目 ID，为空时使用服务账号 JSON 中的 project_id
+	ProjectID string `json:"projectID,omitempty"`
+
+	// 区域 (如 us-central1、us-east5、global)，默认 global
+	Region string `json:"region,omitempty"`
+
+	// 服务账号 JSON 密钥内容
+	Credentials string `json:"credentials"`
+
+	// 自定义 Endpoint（如 Private Service Connect），为空时按区域生成
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Model 映射: RequestModel → Vertex 模型 ID（如 claude-sonnet-4-5@20250929）
+	ModelMapping map[string]string `json:"modelMapping,omitempty"`
+}
+
+type ProviderConfigAzureOpenAI struct {
+	// 资源 Endpoint，如 https://my-resource.openai.azure.com
+	Endpoint string `json:"endpoint"`
+
+	// API 版本，默认 2025-04-01-preview；设置为 v1 时使用 /openai/v1/ 路径
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// API Key 认证（api-key 请求头）
+	APIKey string `json:"apiKey,omitempty"`
+
+	// Entra ID client credential 认证，APIKey 为空时使用
+	TenantID     string `json:"tenantID,omitempty"`
+	ClientID     string `json:"clientID,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+
+	// 部署映射: RequestModel → Deployment 名称，未命中时直接使用模型名
+	Deployments map[string]string `json:"deployments,omitempty"`
+}
+
+const (
+	// LocalBackendOllama 使用 Ollama 原生 /api/chat 接口（NDJSON 流式）
+	LocalBackendOllama = "ollama"
+	// LocalBackendOpenAI 使用 OpenAI 兼容的 /v1/chat/completions 接口（llama.cpp / vLLM 等）
+	LocalBackendOpenAI = "openai"
+)
+
+type ProviderConfigLocal struct {
+	// 服务地址，如 http://localhost:11434 或 http://gpu-box:8000
+	BaseURL string `json:"baseURL"`
+
+	// 后端类型: ollama 或 openai，默认 openai
+	Backend string `json:"backend,omitempty"`
+
+	// 可选 API Key（vLLM --api-key 等）
+	APIKey string `json:"apiKey,omitempty"`
+
+	// 统一价格 (microUSD/M tokens)，默认 0 即免费
+	InputPriceMicro  uint64 `json:"inputPriceMicro,omitempty"`
+	OutputPriceMicro uint64 `json:"outputPriceMicro,omitempty"`
+
+	// Model 映射: RequestModel → 本地模型名（如 claude-haiku → qwen3:8b）
+	ModelMapping map[string]string `json:"modelMapping,omitempty"`
+}
+
+type ProviderConfigCopilot struct {
+	// GitHub 用户名（用于标识帐号）
+	Username string `json:"username"`
+
+	// 显示名称
+	Name string `json:"name,omitempty"`
+
+	// 头像
+	AvatarURL string `json:"avatarURL,omitempty"`
+
+	// GitHub OAuth access token（device flow 获取，长期有效）
+	GitHubToken string `json:"githubToken"`
+
+	// Copilot API token（由 GitHub token 交换，约 30 分钟过期，持久化以减少交换请求）
+	CopilotToken string `json:"copilotToken,omitempty"`
+
+	// Copilot token 过期时间 (RFC3339 格式)
+	ExpiresAt string `json:"expiresAt,omitempty"`
+
+	// Copilot API 地址（随 token 下发，如 https://api.individual.githubcopilot.com）
+	APIEndpoint string `json:"apiEndpoint,omitempty"`
+
+	// Copilot 订阅计划 (如 "individual", "business")
+	Plan string `json:"plan,omitempty"`
+
+	// Model 映射: RequestModel → MappedModel
+	ModelMapping map[string]string `json:"modelMapping,omitempty"`
+}
+
 // ProviderConfigCLIProxyAPIAntigravity CLIProxyAPI Antigravity 内部配置
 // 用于 useCLIProxyAPI=true 时传递给 CLIProxyAPI adapter
 type ProviderConfigCLIProxyAPIAntigravity struct {
@@ -164,16 +280,122 @@ type ProviderConfigCLIProxyAPICodex struct {
 
 type ProviderConfig struct {
 	// 禁用错误自动冷冻（只影响错误触发的冷冻）
-	DisableErrorCooldown bool                       `json:"disableErrorCooldown,omitempty"`
-	Custom               *ProviderConfigCustom      `json:"custom,omitempty"`
-	Antigravity          *ProviderConfigAntigravity `json:"antigravity,omitempty"`
-	Kiro                 *ProviderConfigKiro        `json:"kiro,omitempty"`
-	Codex                *ProviderConfigCodex       `json:"codex,omitempty"`
+	DisableErrorCooldown bool                          `json:"disableErrorCooldown,omitempty"`
+	Network              *ProviderConfigNetwork        `json:"network,omitempty"`
+	HealthCheck          *ProviderConfigHealthCheck    `json:"healthCheck,omitempty"`
+	CircuitBreaker       *ProviderConfigCircuitBreaker `json:"circuitBreaker,omitempty"`
+	Custom               *ProviderConfigCustom         `json:"custom,omitempty"`
+	Antigravity          *ProviderConfigAntigravity    `json:"antigravity,omitempty"`
+	Kiro                 *ProviderConfigKiro           `json:"kiro,omitempty"`
+	Codex                *ProviderConfigCodex          `json:"codex,omitempty"`
+	Bedrock              *ProviderConfigBedrock        `json:"bedrock,omitempty"`
+	Vertex               *ProviderConfigVertex         `json:"vertex,omitempty"`
+	AzureOpenAI          *ProviderConfigAzureOpenAI    `json:"azureOpenAI,omitempty"`
+	Local                *ProviderConfigLocal          `json:"local,omitempty"`
+	Copilot              *ProviderConfigCopilot        `json:"copilot,omitempty"`
 	// 内部运行时字段，仅用于 NewAdapter 委托，不序列化
 	CLIProxyAPIAntigravity *ProviderConfigCLIProxyAPIAntigravity `json:"-"`
 	CLIProxyAPICodex       *ProviderConfigCLIProxyAPICodex       `json:"-"`
 }
 
+// ProviderConfigHealthCheck 主动健康检查，定期通过适配器发送极小的探测请求
+// 探测不经过代理请求记录，消耗的 token 不计入用量统计、费用和 API Token 额度
+type ProviderConfigHealthCheck struct {
+	Enabled bool `json:"enabled"`
+
+	// 探测间隔（秒），0 使用默认值 300
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+
+	// 单次探测超时（秒），0 使用默认值 30
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+
+	// 需要探测的客户端类型，为空时使用引用该 Provider 的路由的客户端类型
+	ClientTypes []ClientType `json:"clientTypes,omitempty"`
+
+	// 各客户端类型的探测模型，未配置时使用 SupportModels 中第一个精确模型或内置默认值
+	Models map[ClientType]string `json:"models,omitempty"`
+
+	// 探测内容与最大输出 token，为空时使用 "ping" / 1
+	Prompt    string `json:"prompt,omitempty"`
+	MaxTokens int    `json:"maxTokens,omitempty"`
+
+	// 连续失败达到该次数后冷却，0 使用默认值 1
+	// 探测成功时只解除服务端错误 / 网络错误且未带上游指定结束时间的冷却
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+}
+
+// ProviderConfigCircuitBreaker 熔断设置
+// 冷冻（open）结束后进入半开状态，只放行少量试探请求，试探成功后才完全恢复，失败则按策略重新冷冻
+// 冷冻时长策略见 CooldownPolicy
+type ProviderConfigCircuitBreaker struct {
+	// 半开状态下同时放行的试探请求数，0 使用默认值 1
+	HalfOpenMaxRequests int `json:"halfOpenMaxRequests,omitempty"`
+
+	// 半开状态下恢复所需的成功次数，0 使用默认值 1
+	SuccessThreshold int `json:"successThreshold,omitempty"`
+}
+
+// Validate 检查熔断设置是否合法
+func (b *ProviderConfigCircuitBreaker) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if b.HalfOpenMaxRequests < 0 || b.SuccessThreshold < 0 {
+		return fmt.Errorf("%w: circuit breaker limits must not be negative", ErrInvalidInput)
+	}
+	return nil
+}
+
+// ProviderConfigNetwork 出站网络设置，所有适配器通用
+type ProviderConfigNetwork struct {
+	// 出站代理：http://、https://、socks5://、socks5h://
+	// 为空时使用环境变量 (HTTP_PROXY/HTTPS_PROXY/NO_PROXY)，"direct" 表示不使用代理
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// 自定义 CA 证书（PEM），追加到系统根证书
+	CACert string `json:"caCert,omitempty"`
+
+	// 跳过 TLS 证书校验，仅用于测试
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// 连接超时秒数（含 TLS 握手），0 使用适配器默认值
+	ConnectTimeoutSeconds int `json:"connectTimeoutSeconds,omitempty"`
+
+	// 读取超时秒数：发送请求后等待响应头的最长时间，0 表示不限制
+	ReadTimeoutSeconds int `json:"readTimeoutSeconds,omitempty"`
+
+	// 空闲连接保持秒数，0 使用适配器默认值
+	IdleTimeoutSeconds int `json:"idleTimeoutSeconds,omitempty"`
+}
+
+// ProxyDirect 表示不使用任何代理（包括环境变量代理）
+const ProxyDirect = "direct"
+
+// Validate 检查网络设置是否合法
+func (n *ProviderConfigNetwork) Validate() error {
+	if n == nil {
+		return nil
+	}
+	if proxyURL := strings.TrimSpace(n.ProxyURL); proxyURL != "" && proxyURL != ProxyDirect {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("%w: invalid proxy URL %q", ErrInvalidInput, proxyURL)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("%w: unsupported proxy scheme %q (use http, https, socks5 or socks5h)", ErrInvalidInput, u.Scheme)
+		}
+	}
+	if n.CACert != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(n.CACert)) {
+		return fmt.Errorf("%w: invalid CA certificate, no PEM certificates found", ErrInvalidInput)
+	}
+	if n.ConnectTimeoutSeconds < 0 || n.ReadTimeoutSeconds < 0 || n.IdleTimeoutSeconds < 0 {
+		return fmt.Errorf("%w: network timeouts must not be negative", ErrInvalidInput)
+	}
+	return nil
+}
+
 // Provider 供应商
 type Provider struct {
 	ID        uint64    `json:"id"`
@@ -218,6 +440,9 @@ type Project struct {
 
 	// 启用自定义路由的 ClientType 列表，空数组表示所有 ClientType 都使用全局路由
 	EnabledCustomRoutes []ClientType `json:"enabledCustomRoutes"`
+
+	// 排队优先级，API Token 未设置优先级时使用
+	Priority PriorityClass `json:"priority"`
 }
 
 type Session struct {
@@ -236,6 +461,32 @@ type Session struct {
 
 	// RejectedAt 记录会话被拒绝的时间，nil 表示未被拒绝
 	RejectedAt *time.Time `json:"rejectedAt,omitempty"`
+
+	// LoopApprovedAt 记录管理员最近一次允许会话在循环检测触发后继续的时间
+	LoopApprovedAt *time.Time `json:"loopApprovedAt,omitempty"`
+}
+
+// SessionStats 会话维度的聚合统计，由 ProxyRequest 汇总得出
+type SessionStats struct {
+	SessionID  string     `json:"sessionID"`
+	ClientType ClientType `json:"clientType"`
+	ProjectID  uint64     `json:"projectID"`
+
+	// 请求轮数（每个 ProxyRequest 计为一轮）
+	TurnCount    uint64 `json:"turnCount"`
+	FailureCount uint64 `json:"failureCount"`
+
+	InputTokenCount  uint64 `json:"inputTokenCount"`
+	OutputTokenCount uint64 `json:"outputTokenCount"`
+	CacheReadCount   uint64 `json:"cacheReadCount"`
+	CacheWriteCount  uint64 `json:"cacheWriteCount"`
+	Cost             uint64 `json:"cost"`
+
+	// 会话中使用过的 Provider
+	ProviderIDs []uint64 `json:"providerIDs"`
+
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
 }
 
 // 路由
@@ -276,11 +527,13 @@ type RequestInfo struct {
 	Headers map[string]string `json:"headers"`
 	URL     string            `json:"url"`
 	Body    string            `json:"body"`
+	BodyRef string            `json:"bodyRef,omitempty"` // body 存放在外部详情存储时的引用
 }
 type ResponseInfo struct {
 	Status  int               `json:"status"`
 	Headers map[string]string `json:"headers"`
 	Body    string            `json:"body"`
+	BodyRef string            `json:"bodyRef,omitempty"` // body 存放在外部详情存储时的引用
 }
 
 // 追踪
@@ -349,7 +602,12 @@ type ProxyRequest struct {
 	Multiplier   uint64 `json:"multiplier"`   // 倍率（10000=1倍）
 
 	// 成本 (纳美元，1 USD = 1,000,000,000 nanoUSD)
-	Cost uint64 `json:"cost"`
+	// - Cost: 按价格与倍率计算的成本
+	// - UpstreamCost: 上游真实成本（未应用倍率）
+	// - BilledCost: 按计费规则向客户计费的金额
+	Cost         uint64 `json:"cost"`
+	UpstreamCost uint64 `json:"upstreamCost"`
+	BilledCost   uint64 `json:"billedCost"`
 
 	// 使用的 API Token ID，0 表示未使用 Token
 	APITokenID uint64 `json:"apiTokenID"`
@@ -371,9 +629,12 @@ type ProxyUpstreamAttempt struct {
 	// TTFT (Time To First Token) 首字时长，流式接口第一条数据返回的延迟
 	TTFT time.Duration `json:"ttft"`
 
-	// PENDING, IN_PROGRESS, COMPLETED, FAILED
+	// PENDING, IN_PROGRESS, COMPLETED, FAILED, CANCELLED
 	Status string `json:"status"`
 
+	// 失败原因（FAILED 时为 CooldownReason，CANCELLED 时为 cancelled）
+	FailureReason string `json:"failureReason,omitempty"`
+
 	ProxyRequestID uint64 `json:"proxyRequestID"`
 
 	// 是否为 SSE 流式请求
@@ -387,6 +648,10 @@ type ProxyUpstreamAttempt struct {
 	MappedModel   string `json:"mappedModel"`
 	ResponseModel string `json:"responseModel"`
 
+	// 降级步骤：0 表示使用原始请求模型，n 表示使用降级链上的第 n 个模型
+	FallbackStep  int    `json:"fallbackStep,omitempty"`
+	FallbackModel string `json:"fallbackModel,omitempty"` // 降级后用于路由的模型（映射前）
+
 	RequestInfo  *RequestInfo  `json:"requestInfo"`
 	ResponseInfo *ResponseInfo `json:"responseInfo"`
 
@@ -411,7 +676,28 @@ type ProxyUpstreamAttempt struct {
 	ModelPriceID uint64 `json:"modelPriceId"` // 使用的模型价格记录ID
 	Multiplier   uint64 `json:"multiplier"`   // 倍率（10000=1倍）
 
-	Cost uint64 `json:"cost"`
+	Cost         uint64 `json:"cost"`
+	UpstreamCost uint64 `json:"upstreamCost"` // 上游真实成本（未应用倍率）
+	BilledCost   uint64 `json:"billedCost"`   // 按计费规则计费的金额
+}
+
+// 浪费原因中不来自 CooldownReason 的取值
+const (
+	AttemptReasonCancelled = "cancelled" // 客户端断开或请求超时
+)
+
+// WastedSpend 未产生最终结果的 attempt 消耗（失败或取消），按 Provider 和原因聚合
+type WastedSpend struct {
+	ProviderID uint64 `json:"providerID"`
+	Reason     string `json:"reason"`
+
+	Attempts         uint64 `json:"attempts"`
+	InputTokenCount  uint64 `json:"inputTokenCount"`
+	OutputTokenCount uint64 `json:"outputTokenCount"`
+	CacheReadCount   uint64 `json:"cacheReadCount"`
+	CacheWriteCount  uint64 `json:"cacheWriteCount"`
+	Cost             uint64 `json:"cost"`
+	UpstreamCost     uint64 `json:"upstreamCost"`
 }
 
 // AttemptCostData contains minimal data needed for cost recalculation
@@ -428,6 +714,21 @@ type AttemptCostData struct {
 	Cache5mWriteCount uint64
 	Cache1hWriteCount uint64
 	Cost              uint64
+
+	// 计费相关：按 Provider 覆盖价格和倍率重算，并按请求所属项目/Token 的计费规则调整计费金额
+	ProviderID   uint64
+	Multiplier   uint64
+	ProjectID    uint64
+	APITokenID   uint64
+	UpstreamCost uint64
+	BilledCost   uint64
+}
+
+// CostUpdate 重算后需要写回的成本字段 (纳美元)
+type CostUpdate struct {
+	Cost         uint64
+	UpstreamCost uint64
+	BilledCost   uint64
 }
 
 // 重试配置
@@ -514,6 +815,37 @@ const (
 	SettingKeyEnablePprof                   = "enable_pprof"                     // 是否启用 pprof 性能分析，"true" 或 "false"，默认 "false"
 	SettingKeyPprofPort                     = "pprof_port"                       // pprof 服务端口，默认 6060
 	SettingKeyPprofPassword                 = "pprof_password"                   // pprof 访问密码，为空表示不需要密码
+	SettingKeyAuditLogRetentionDays         = "audit_log_retention_days"         // 审计日志保留天数，默认 90 天，0 表示不清理
+	SettingKeyStreamFailoverEnabled         = "stream_failover_enabled"          // 流式响应在输出内容前中断时是否切换到下一路由，"true" 或 "false"，默认 "false"
+	SettingKeyRequestDetailMaxBodyBytes     = "request_detail_max_body_bytes"    // 请求详情单个 body 最大保存字节数，超出时保留首尾，默认 1 MiB，0 表示不限制
+	SettingKeyExchangeRates                 = "exchange_rates"                   // 汇率 JSON，如 {"CNY": 7.2} 表示 1 USD = 7.2 CNY，用于折算非 USD 价格
+	SettingKeyReportSchedule                = "report_schedule"                  // 账单报表周期，"weekly" / "monthly"，为空表示禁用
+	SettingKeyReportFormat                  = "report_format"                    // 账单报表格式，"csv"(默认) 或 "parquet"
+	SettingKeyReportWebhookURL              = "report_webhook_url"               // 报表生成后 POST 摘要的 webhook 地址，为空不发送
+	SettingKeyReportSMTPAddr                = "report_smtp_addr"                 // 发送报表邮件的 SMTP 中继地址（host:port），为空不发送
+	SettingKeyReportSMTPUsername            = "report_smtp_username"             // SMTP 认证用户名（PLAIN），为空不认证
+	SettingKeyReportSMTPPassword            = "report_smtp_password"             // SMTP 认证密码
+	SettingKeyReportEmailFrom               = "report_email_from"                // 报表邮件发件人
+	SettingKeyReportEmailTo                 = "report_email_to"                  // 报表邮件收件人，逗号分隔
+	SettingKeyReportLastPeriod              = "report_last_period"               // 最近一次已生成的报表周期（内部使用，避免重复生成）
+	SettingKeyAnomalyEnabled                = "anomaly_enabled"                  // 是否启用用量异常检测，"true" 或 "false"，默认 "false"
+	SettingKeyAnomalyWindowMinutes          = "anomaly_window_minutes"           // 检测窗口（分钟），默认 15
+	SettingKeyAnomalyBaselineDays           = "anomaly_baseline_days"            // 基线统计天数，默认 7
+	SettingKeyAnomalyMinRequests            = "anomaly_min_requests"             // 窗口内请求数低于该值时不检测，默认 20
+	SettingKeyAnomalyRateFactor             = "anomaly_rate_factor"              // 请求/Token/成本速率超过基线的倍数时告警，默认 5，0 表示不检测
+	SettingKeyAnomalyErrorRate              = "anomaly_error_rate"               // 窗口错误率（百分比）超过该值时告警，默认 50，0 表示不检测
+	SettingKeyAnomalyWebhookURL             = "anomaly_webhook_url"              // 告警 webhook 地址，为空不发送
+	SettingKeyAnomalyAutoDisable            = "anomaly_auto_disable"             // API Token 触发告警时是否自动禁用，"true" 或 "false"，默认 "false"
+	SettingKeyLoopGuardEnabled              = "loop_guard_enabled"               // 是否启用会话循环检测，"true" 或 "false"，默认 "false"
+	SettingKeyLoopGuardWindow               = "loop_guard_window"                // 每个会话记录最近多少个请求指纹，默认 10
+	SettingKeyLoopGuardRepeatThreshold      = "loop_guard_repeat_threshold"      // 窗口内相同指纹出现次数达到该值时触发，默认 5，0 表示不检测
+	SettingKeyLoopGuardMaxTokens            = "loop_guard_max_tokens"            // 单个会话累计 Token 上限，0 表示不限制
+	SettingKeyLoopGuardMaxCost              = "loop_guard_max_cost"              // 单个会话累计成本上限（美元），0 表示不限制
+	SettingKeyLoopGuardAction               = "loop_guard_action"                // 触发后的处理方式，"reject"(默认) 直接拒绝，"approve" 等待管理员确认继续
+	SettingKeyQueueEnabled                  = "queue_enabled"                    // 是否启用准入队列，"true" 或 "false"，默认 "false"
+	SettingKeyQueueMaxLength                = "queue_max_length"                 // 最大排队请求数，默认 100
+	SettingKeyQueueMaxWaitSeconds           = "queue_max_wait_seconds"           // 单个请求最长排队秒数，默认 30
+	SettingKeyQueueMaxConcurrency           = "queue_max_concurrency"            // 同时处理的请求上限，0 表示不限制（只在路由全部冷却时排队）
 )
 
 // ModelPrice 模型价格（每个模型可有多条记录，每条代表一个版本）
@@ -522,7 +854,14 @@ type ModelPrice struct {
 	CreatedAt time.Time `json:"createdAt"`
 	ModelID   string    `json:"modelId"` // 模型名称/前缀，如 "claude-sonnet-4"
 
-	// 基础价格 (microUSD/M tokens)
+	// 价格归属：0 表示全局价格，>0 表示仅对该 Provider 生效的覆盖价格
+	ProviderID uint64 `json:"providerId"`
+	// 价格币种（ISO 4217），为空表示 USD；非 USD 价格计费时按汇率折算为美元
+	Currency string `json:"currency"`
+	// 价格来源，如 default / manual / import:litellm
+	Source string `json:"source"`
+
+	// 基础价格 (micro 货币单位/M tokens，USD 时即 microUSD/M tokens)
 	InputPriceMicro        uint64 `json:"inputPriceMicro"`
 	OutputPriceMicro       uint64 `json:"outputPriceMicro"`
 	CacheReadPriceMicro    uint64 `json:"cacheReadPriceMicro"`
@@ -538,6 +877,16 @@ type ModelPrice struct {
 	OutputPremiumDenom uint64 `json:"outputPremiumDenom"`
 }
 
+// CurrencyUSD 是计费的基准币种，所有成本最终以纳美元记录
+const CurrencyUSD = "USD"
+
+// ModelPrice.Source 取值
+const (
+	PriceSourceDefault = "default" // 内置默认价格
+	PriceSourceManual  = "manual"  // 管理界面手动维护
+	PriceSourceImport  = "import"  // 价格表导入，实际值为 "import:<format>"
+)
+
 // Antigravity 模型配额
 type AntigravityModelQuota struct {
 	Name       string `json:"name"`       // 模型名称
@@ -623,7 +972,79 @@ type CodexQuota struct {
 	CodeReviewWindow *CodexQuotaWindow `json:"codeReviewWindow,omitempty"`
 }
 
+// Copilot 额度快照（chat / completions / premium_interactions）
+type CopilotQuotaSnapshot struct {
+	Entitlement      int64   `json:"entitlement"`
+	Remaining        int64   `json:"remaining"`
+	PercentRemaining float64 `json:"percentRemaining"`
+	Unlimited        bool    `json:"unlimited"`
+	OverageCount     int64   `json:"overageCount"`
+	OveragePermitted bool    `json:"overagePermitted"`
+}
+
+// Copilot 账户配额（基于 GitHub 用户名存储）
+type CopilotQuota struct {
+	ID        uint64    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// 软删除时间
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+
+	// GitHub 用户名作为唯一标识
+	Username string `json:"username"`
+
+	// 订阅计划 (e.g., individual, business, enterprise)
+	Plan string `json:"plan"`
+
+	// 额度重置日期 (YYYY-MM-DD)
+	ResetDate string `json:"resetDate"`
+
+	// 是否无 Copilot 权限 (403/404)
+	IsForbidden bool `json:"isForbidden"`
+
+	// 对话额度
+	Chat *CopilotQuotaSnapshot `json:"chat,omitempty"`
+
+	// 代码补全额度
+	Completions *CopilotQuotaSnapshot `json:"completions,omitempty"`
+
+	// 高级请求额度（Claude / Gemini / o 系列等高级模型消耗）
+	PremiumInteractions *CopilotQuotaSnapshot `json:"premiumInteractions,omitempty"`
+}
+
 // Provider 统计信息
+// ProviderHealthCheck 一次主动健康探测的结果
+type ProviderHealthCheck struct {
+	ID         uint64     `json:"id"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ProviderID uint64     `json:"providerID"`
+	ClientType ClientType `json:"clientType"`
+	Model      string     `json:"model"`
+	Success    bool       `json:"success"`
+	StatusCode int        `json:"statusCode,omitempty"`
+
+	// 探测耗时（纳秒）
+	Latency time.Duration `json:"latency"`
+
+	Error string `json:"error,omitempty"`
+	// 失败原因（与冷冻原因一致）
+	Reason string `json:"reason,omitempty"`
+	// 探测失败后施加的冷冻结束时间，未冷冻时为空
+	CooldownUntil *time.Time `json:"cooldownUntil,omitempty"`
+}
+
+// ProviderHealthStatus Provider 在某个客户端类型下的当前健康状态（内存中，重启后随下一轮探测重建）
+type ProviderHealthStatus struct {
+	ProviderID          uint64               `json:"providerID"`
+	ProviderName        string               `json:"providerName"`
+	ClientType          ClientType           `json:"clientType"`
+	Healthy             bool                 `json:"healthy"`
+	ConsecutiveFailures int                  `json:"consecutiveFailures"`
+	LastCheck           *ProviderHealthCheck `json:"lastCheck"`
+	NextCheckAt         time.Time            `json:"nextCheckAt"`
+}
+
 type ProviderStats struct {
 	ProviderID uint64 `json:"providerID"`
 
@@ -687,7 +1108,9 @@ type UsageStats struct {
 	CacheWrite   uint64 `json:"cacheWrite"`
 
 	// 成本 (纳美元)
-	Cost uint64 `json:"cost"`
+	Cost         uint64 `json:"cost"`
+	UpstreamCost uint64 `json:"upstreamCost"` // 上游真实成本
+	BilledCost   uint64 `json:"billedCost"`   // 计费金额
 }
 
 // UsageStatsSummary 统计数据汇总（用于仪表盘）
@@ -701,6 +1124,9 @@ type UsageStatsSummary struct {
 	TotalCacheRead     uint64  `json:"totalCacheRead"`
 	TotalCacheWrite    uint64  `json:"totalCacheWrite"`
 	TotalCost          uint64  `json:"totalCost"`
+	TotalUpstreamCost  uint64  `json:"totalUpstreamCost"`
+	TotalBilledCost    uint64  `json:"totalBilledCost"`
+	Margin             int64   `json:"margin"` // 毛利 = 计费金额 - 上游成本，可能为负
 }
 
 // APIToken API 访问令牌
@@ -728,6 +1154,9 @@ type APIToken struct {
 	// 开发者模式（开启时该令牌请求详情永久保留）
 	DevMode bool `json:"devMode"`
 
+	// 排队优先级，为空时使用所属项目的优先级
+	Priority PriorityClass `json:"priority"`
+
 	// 过期时间，nil 表示永不过期
 	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 
@@ -737,6 +1166,9 @@ type APIToken struct {
 	// 使用次数
 	UseCount uint64 `json:"useCount"`
 
+	// 权限范围（客户端类型、模型、供应商、IP 白名单）
+	APITokenScope
+
 	// 软删除时间
 	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
@@ -804,6 +1236,39 @@ type ModelMappingQuery struct {
 	APITokenID   uint64
 }
 
+// ModelFallback 模型降级链
+// 当前模型的所有路由都已失败或处于冷冻时，按顺序改用 Models 中的模型重新匹配路由
+type ModelFallback struct {
+	ID        uint64    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// 作用域条件（为空表示所有）
+	ClientType ClientType `json:"clientType,omitempty"` // 客户端类型，空表示所有
+	ProjectID  uint64     `json:"projectID,omitempty"`  // 项目 ID，0 表示所有
+
+	// 降级规则
+	Pattern string   `json:"pattern"` // 源模式，支持通配符 *
+	Models  []string `json:"models"`  // 降级模型，按顺序尝试
+
+	// 是否通过响应头告知客户端实际使用的降级模型
+	NotifyClient bool `json:"notifyClient"`
+
+	// 优先级，数字越小优先级越高
+	Priority int `json:"priority"`
+}
+
+// Matches 检查降级链是否适用于该客户端类型、项目和模型
+func (f *ModelFallback) Matches(clientType ClientType, projectID uint64, model string) bool {
+	if f.ClientType != "" && f.ClientType != clientType {
+		return false
+	}
+	if f.ProjectID != 0 && f.ProjectID != projectID {
+		return false
+	}
+	return MatchWildcard(f.Pattern, model)
+}
+
 // ResponseModel 记录所有出现过的 response model
 // 用于快速查询可选的模型列表，避免每次 DISTINCT 查询
 type ResponseModel struct {
@@ -907,6 +1372,7 @@ type DashboardDaySummary struct {
 	Requests    uint64  `json:"requests"`
 	Tokens      uint64  `json:"tokens"`
 	Cost        uint64  `json:"cost"`
+	Margin      int64   `json:"margin"` // 毛利 = 计费金额 - 上游成本
 	SuccessRate float64 `json:"successRate,omitempty"`
 	RPM         float64 `json:"rpm,omitempty"` // Requests Per Minute (今日平均)
 	TPM         float64 `json:"tpm,omitempty"` // Tokens Per Minute (今日平均)
@@ -917,6 +1383,7 @@ type DashboardAllTimeSummary struct {
 	Requests          uint64     `json:"requests"`
 	Tokens            uint64     `json:"tokens"`
 	Cost              uint64     `json:"cost"`
+	Margin            int64      `json:"margin"` // 毛利 = 计费金额 - 上游成本
 	FirstUseDate      *time.Time `json:"firstUseDate,omitempty"`
 	DaysSinceFirstUse int        `json:"daysSinceFirstUse"`
 }
@@ -0,0 +1,63 @@
+package domain
+
+// PriorityClass 请求排队优先级，按 API Token 或项目分配
+// 准入队列饱和时高优先级请求先获得处理机会
+type PriorityClass string
+
+const (
+	PriorityInteractive PriorityClass = "interactive" // 交互式会话（如 Claude Code）
+	PriorityNormal      PriorityClass = "normal"      // 默认
+	PriorityBatch       PriorityClass = "batch"       // 批处理任务
+)
+
+// Rank returns the scheduling rank of the class, lower is served first
+func (p PriorityClass) Rank() int {
+	switch p {
+	case PriorityInteractive:
+		return 0
+	case PriorityBatch:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Valid reports whether p is empty (inherit/default) or a known class
+func (p PriorityClass) Valid() bool {
+	switch p {
+	case "", PriorityInteractive, PriorityNormal, PriorityBatch:
+		return true
+	}
+	return false
+}
+
+// Normalize maps an empty class to PriorityNormal
+func (p PriorityClass) Normalize() PriorityClass {
+	if p == "" {
+		return PriorityNormal
+	}
+	return p
+}
+
+// AdmissionQueueStats 准入队列快照
+type AdmissionQueueStats struct {
+	Enabled        bool `json:"enabled"`
+	MaxLength      int  `json:"maxLength"`
+	MaxConcurrency int  `json:"maxConcurrency"` // 0 表示不限制
+	InFlight       int  `json:"inFlight"`       // 正在处理的请求数
+	Waiting        int  `json:"waiting"`        // 当前排队的请求数（等待并发槽位 + 等待冷却结束）
+
+	Classes []*AdmissionClassStats `json:"classes"`
+}
+
+// AdmissionClassStats 单个优先级的排队统计（自进程启动以来）
+type AdmissionClassStats struct {
+	Class     PriorityClass `json:"class"`
+	Waiting   int           `json:"waiting"`
+	Admitted  uint64        `json:"admitted"`  // 放行的请求数
+	Queued    uint64        `json:"queued"`    // 其中经过排队的请求数
+	Rejected  uint64        `json:"rejected"`  // 队列已满被拒绝
+	TimedOut  uint64        `json:"timedOut"`  // 超过最长等待时间
+	AvgWaitMs float64       `json:"avgWaitMs"` // 排队请求的平均等待时间
+	MaxWaitMs int64         `json:"maxWaitMs"`
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/awsl-project/maxx/internal/admission"
 	"github.com/awsl-project/maxx/internal/converter"
 	"github.com/awsl-project/maxx/internal/cooldown"
 	"github.com/awsl-project/maxx/internal/domain"
@@ -19,20 +20,23 @@ import (
 
 // Executor handles request execution with retry logic
 type Executor struct {
-	router           *router.Router
-	proxyRequestRepo repository.ProxyRequestRepository
-	attemptRepo      repository.ProxyUpstreamAttemptRepository
-	retryConfigRepo  repository.RetryConfigRepository
-	sessionRepo      repository.SessionRepository
-	modelMappingRepo repository.ModelMappingRepository
-	settingsRepo     repository.SystemSettingRepository
-	broadcaster      event.Broadcaster
-	projectWaiter    *waiter.ProjectWaiter
-	instanceID       string
-	statsAggregator  *stats.StatsAggregator
-	converter        *converter.Registry
-	engine           *flow.Engine
-	middlewares      []flow.HandlerFunc
+	router            *router.Router
+	proxyRequestRepo  repository.ProxyRequestRepository
+	attemptRepo       repository.ProxyUpstreamAttemptRepository
+	retryConfigRepo   repository.RetryConfigRepository
+	sessionRepo       repository.SessionRepository
+	modelMappingRepo  repository.ModelMappingRepository
+	modelFallbackRepo repository.ModelFallbackRepository
+	settingsRepo      repository.SystemSettingRepository
+	broadcaster       event.Broadcaster
+	projectWaiter     *waiter.ProjectWaiter
+	loopGuard         *waiter.LoopGuard
+	admissionQueue    *admission.Queue
+	instanceID        string
+	statsAggregator   *stats.StatsAggregator
+	converter         *converter.Registry
+	engine            *flow.Engine
+	middlewares       []flow.HandlerFunc
 }
 
 // NewExecutor creates a new executor
@@ -43,26 +47,32 @@ func NewExecutor(
 	rcr repository.RetryConfigRepository,
 	sessionRepo repository.SessionRepository,
 	modelMappingRepo repository.ModelMappingRepository,
+	modelFallbackRepo repository.ModelFallbackRepository,
 	settingsRepo repository.SystemSettingRepository,
 	bc event.Broadcaster,
 	projectWaiter *waiter.ProjectWaiter,
+	loopGuard *waiter.LoopGuard,
+	admissionQueue *admission.Queue,
 	instanceID string,
 	statsAggregator *stats.StatsAggregator,
 ) *Executor {
 	return &Executor{
-		router:           r,
-		proxyRequestRepo: prr,
-		attemptRepo:      ar,
-		retryConfigRepo:  rcr,
-		sessionRepo:      sessionRepo,
-		modelMappingRepo: modelMappingRepo,
-		settingsRepo:     settingsRepo,
-		broadcaster:      bc,
-		projectWaiter:    projectWaiter,
-		instanceID:       instanceID,
-		statsAggregator:  statsAggregator,
-		converter:        converter.GetGlobalRegistry(),
-		engine:           flow.NewEngine(),
+		router:            r,
+		proxyRequestRepo:  prr,
+		attemptRepo:       ar,
+		retryConfigRepo:   rcr,
+		sessionRepo:       sessionRepo,
+		modelMappingRepo:  modelMappingRepo,
+		modelFallbackRepo: modelFallbackRepo,
+		settingsRepo:      settingsRepo,
+		broadcaster:       bc,
+		projectWaiter:     projectWaiter,
+		loopGuard:         loopGuard,
+		admissionQueue:    admissionQueue,
+		instanceID:        instanceID,
+		statsAggregator:   statsAggregator,
+		converter:         converter.GetGlobalRegistry(),
+		engine:            flow.NewEngine(),
 	}
 }
 
@@ -184,76 +194,45 @@ func (e *Executor) handleCooldown(proxyErr *domain.ProxyError, provider *domain.
 		}
 	}
 
-	// Determine cooldown reason and explicit time
-	var reason cooldown.CooldownReason
-	var explicitUntil *time.Time
-
-	// Priority 1: Check for explicit cooldown time from API
-	if proxyErr.CooldownUntil != nil {
-		// Has explicit time from API (e.g., from CooldownUntil field)
-		explicitUntil = proxyErr.CooldownUntil
-		reason = cooldown.ReasonQuotaExhausted // Default, may be overridden below
-		if proxyErr.RateLimitInfo != nil {
-			reason = mapRateLimitTypeToReason(proxyErr.RateLimitInfo.Type)
-		}
-	} else if proxyErr.RateLimitInfo != nil && !proxyErr.RateLimitInfo.QuotaResetTime.IsZero() {
-		// Has explicit quota reset time from API
-		explicitUntil = &proxyErr.RateLimitInfo.QuotaResetTime
-		reason = mapRateLimitTypeToReason(proxyErr.RateLimitInfo.Type)
-	} else if proxyErr.RetryAfter > 0 {
-		// Has Retry-After duration from API
-		untilTime := time.Now().Add(proxyErr.RetryAfter)
-		explicitUntil = &untilTime
-		reason = cooldown.ReasonRateLimit
-	} else if proxyErr.IsServerError {
-		// Server error (5xx) - no explicit time, use policy
-		reason = cooldown.ReasonServerError
-		explicitUntil = nil
-	} else if proxyErr.IsNetworkError {
-		// Network error - no explicit time, use policy
-		reason = cooldown.ReasonNetworkError
-		explicitUntil = nil
-	} else {
-		// Unknown error type - use policy
-		reason = cooldown.ReasonUnknown
-		explicitUntil = nil
+	// Adapters that know the quota is per model limit the cooldown to that model (family)
+	model := ""
+	if proxyErr.RateLimitInfo != nil {
+		model = proxyErr.RateLimitInfo.Model
 	}
 
+	// Determine cooldown reason and explicit time
+	reason, explicitUntil := cooldown.ClassifyProxyError(proxyErr)
+
 	// Record failure and apply cooldown
 	// If explicitUntil is not nil, it will be used directly
 	// Otherwise, cooldown duration is calculated based on policy and failure count
-	cooldown.Default().RecordFailure(provider.ID, selectedClientType, reason, explicitUntil)
+	cooldown.Default().RecordFailure(provider.ID, selectedClientType, model, reason, explicitUntil)
 
 	// If there's an async update channel, listen for updates
 	if proxyErr.CooldownUpdateChan != nil {
-		go e.handleAsyncCooldownUpdate(proxyErr.CooldownUpdateChan, provider, selectedClientType)
+		go e.handleAsyncCooldownUpdate(proxyErr.CooldownUpdateChan, provider, selectedClientType, model)
 	}
 }
 
-func shouldSkipErrorCooldown(provider *domain.Provider) bool {
-	return provider != nil && provider.Config != nil && provider.Config.DisableErrorCooldown
+// attemptFailureReason returns the failure reason recorded on a failed attempt
+func attemptFailureReason(err error) string {
+	if proxyErr, ok := err.(*domain.ProxyError); ok {
+		reason, _ := cooldown.ClassifyProxyError(proxyErr)
+		return string(reason)
+	}
+	return string(cooldown.ReasonUnknown)
 }
 
-// mapRateLimitTypeToReason maps RateLimitInfo.Type to CooldownReason
-func mapRateLimitTypeToReason(rateLimitType string) cooldown.CooldownReason {
-	switch rateLimitType {
-	case "quota_exhausted":
-		return cooldown.ReasonQuotaExhausted
-	case "rate_limit_exceeded":
-		return cooldown.ReasonRateLimit
-	case "concurrent_limit":
-		return cooldown.ReasonConcurrentLimit
-	default:
-		return cooldown.ReasonRateLimit // Default to rate limit
-	}
+func shouldSkipErrorCooldown(provider *domain.Provider) bool {
+	return provider != nil && provider.Config != nil && provider.Config.DisableErrorCooldown
 }
 
 // handleAsyncCooldownUpdate listens for async cooldown updates from providers
-func (e *Executor) handleAsyncCooldownUpdate(updateChan chan time.Time, provider *domain.Provider, clientType string) {
+func (e *Executor) handleAsyncCooldownUpdate(updateChan chan time.Time, provider *domain.Provider, clientType string, model string) {
 	select {
 	case newCooldownTime := <-updateChan:
 		if !newCooldownTime.IsZero() {
-			cooldown.Default().UpdateCooldown(provider.ID, clientType, newCooldownTime)
+			cooldown.Default().UpdateCooldown(provider.ID, clientType, model, newCooldownTime)
 		}
 	case <-time.After(15 * time.Second):
 		// Timeout waiting for update
@@ -319,6 +298,7 @@ func (e *Executor) processAdapterEventsRealtime(
 	attempt *domain.ProxyUpstreamAttempt,
 	done chan struct{},
 	clearDetail bool,
+	bodyLimit int,
 ) {
 	defer close(done)
 
@@ -359,11 +339,21 @@ func (e *Executor) processAdapterEventsRealtime(
 			case domain.EventRequestInfo:
 				if !clearDetail && ev.RequestInfo != nil {
 					attempt.RequestInfo = ev.RequestInfo
+					if bodyLimit > 0 && len(attempt.RequestInfo.Body) > bodyLimit {
+						info := *attempt.RequestInfo
+						info.Body = truncateBody(info.Body, bodyLimit)
+						attempt.RequestInfo = &info
+					}
 					dirty = true
 				}
 			case domain.EventResponseInfo:
 				if !clearDetail && ev.ResponseInfo != nil {
 					attempt.ResponseInfo = ev.ResponseInfo
+					if bodyLimit > 0 && len(attempt.ResponseInfo.Body) > bodyLimit {
+						info := *attempt.ResponseInfo
+						info.Body = truncateBody(info.Body, bodyLimit)
+						attempt.ResponseInfo = &info
+					}
 					dirty = true
 				}
 			case domain.EventMetrics:
@@ -412,6 +402,31 @@ func (e *Executor) getRequestDetailRetentionSeconds() int {
 	return seconds
 }
 
+// getDetailMaxBodyBytes 获取请求详情 body 保存上限，0 表示不限制
+func (e *Executor) getDetailMaxBodyBytes() int {
+	if e.settingsRepo == nil {
+		return defaultDetailMaxBodyBytes
+	}
+	val, err := e.settingsRepo.Get(domain.SettingKeyRequestDetailMaxBodyBytes)
+	if err != nil || val == "" {
+		return defaultDetailMaxBodyBytes
+	}
+	limit, err := strconv.Atoi(val)
+	if err != nil || limit < 0 {
+		return defaultDetailMaxBodyBytes
+	}
+	return limit
+}
+
+// isStreamFailoverEnabled 是否启用流式响应中途切换路由（默认关闭）
+func (e *Executor) isStreamFailoverEnabled() bool {
+	if e.settingsRepo == nil {
+		return false
+	}
+	val, err := e.settingsRepo.Get(domain.SettingKeyStreamFailoverEnabled)
+	return err == nil && val == "true"
+}
+
 // shouldClearRequestDetailFor 检查是否应该立即清理请求详情（考虑 Token 开发者模式）
 func (e *Executor) shouldClearRequestDetailFor(state *execState) bool {
 	if state != nil && state.apiTokenDevMode {
@@ -23,10 +23,36 @@ type execState struct {
 	isStream            bool
 	apiTokenID          uint64
 	apiTokenDevMode     bool
+	apiTokenProviders   []uint64
+	apiTokenModels      []string
+	apiTokenPriority    domain.PriorityClass
 	requestBody         []byte
 	originalRequestBody []byte
 	requestHeaders      http.Header
 	requestURI          string
+	detailBodyLimit     int
+
+	// 模型降级链（不含原始请求模型），fallbackStep 为 0 时使用原始请求模型
+	fallbackModels []string
+	fallbackStep   int
+	notifyFallback bool
+}
+
+// routeModel 返回当前用于路由和模型映射的模型：原始请求模型或降级链上的模型
+func (s *execState) routeModel() string {
+	if s.fallbackStep == 0 {
+		return s.requestModel
+	}
+	return s.fallbackModels[s.fallbackStep-1]
+}
+
+// nextFallback 切换到降级链上的下一个模型，没有更多模型时返回 false
+func (s *execState) nextFallback() bool {
+	if s.fallbackStep >= len(s.fallbackModels) {
+		return false
+	}
+	s.fallbackStep++
+	return true
 }
 
 func getExecState(c *flow.Ctx) (*execState, bool) {
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/billing"
 	"github.com/awsl-project/maxx/internal/converter"
 	"github.com/awsl-project/maxx/internal/cooldown"
 	"github.com/awsl-project/maxx/internal/domain"
@@ -27,342 +29,310 @@ func (e *Executor) dispatch(c *flow.Ctx) {
 	ctx := state.ctx
 	clearDetail := e.shouldClearRequestDetailFor(state)
 
-	for _, matchedRoute := range state.routes {
-		if ctx.Err() != nil {
-			state.lastErr = ctx.Err()
-			c.Err = state.lastErr
-			return
-		}
-
-		proxyReq.RouteID = matchedRoute.Route.ID
-		proxyReq.ProviderID = matchedRoute.Provider.ID
-		_ = e.proxyRequestRepo.Update(proxyReq)
-		if e.broadcaster != nil {
-			e.broadcaster.BroadcastProxyRequest(proxyReq)
-		}
-
-		clientType := state.clientType
-		mappedModel := e.mapModel(state.requestModel, matchedRoute.Route, matchedRoute.Provider, clientType, state.projectID, state.apiTokenID)
-
-		originalClientType := clientType
-		currentClientType := clientType
-		needsConversion := false
-		convertedBody := []byte(nil)
-		var convErr error
-		requestBody := state.requestBody
-		requestURI := state.requestURI
-
-		supportedTypes := matchedRoute.ProviderAdapter.SupportedClientTypes()
-		if e.converter.NeedConvert(clientType, supportedTypes) {
-			currentClientType = GetPreferredTargetType(supportedTypes, clientType, matchedRoute.Provider.Type)
-			if currentClientType != clientType {
-				needsConversion = true
-				log.Printf("[Executor] Format conversion needed: %s -> %s for provider %s",
-					clientType, currentClientType, matchedRoute.Provider.Name)
-
-				if currentClientType == domain.ClientTypeCodex {
-					if headers := state.requestHeaders; headers != nil {
-						requestBody = converter.InjectCodexUserAgent(requestBody, headers.Get("User-Agent"))
-					}
-				}
-				convertedBody, convErr = e.converter.TransformRequest(
-					clientType, currentClientType, requestBody, mappedModel, state.isStream)
-				if convErr != nil {
-					log.Printf("[Executor] Request conversion failed: %v, proceeding with original format", convErr)
-					needsConversion = false
-					currentClientType = clientType
-				} else {
-					requestBody = convertedBody
+	// 流式响应中断时以客户端格式的错误事件结束；
+	// 启用流式切换时在输出实际内容前暂存前导事件，失败时可透明切换到下一路由
+	var guard *streamGuard
+	if state.isStream {
+		guard = newStreamGuard(c.Writer, state.clientType, e.isStreamFailoverEnabled())
+	}
 
-					originalURI := requestURI
-					convertedURI := ConvertRequestURI(requestURI, clientType, currentClientType, mappedModel, state.isStream)
-					if convertedURI != originalURI {
-						requestURI = convertedURI
-						log.Printf("[Executor] URI converted: %s -> %s", originalURI, convertedURI)
-					}
-				}
-			}
-		}
+	// 熔断半开时的试探名额只在使用该路由期间占用，切换路由或请求结束时释放
+	var trial *cooldown.Trial
+	defer func() { trial.Release() }()
 
-		retryConfig := e.getRetryConfig(matchedRoute.RetryConfig)
+	// 当前模型的路由全部失败时沿降级链切换模型，重新匹配路由后继续
+	for {
+	routes:
+		for _, matchedRoute := range state.routes {
+			trial.Release()
+			trial = nil
 
-		for attempt := 0; attempt <= retryConfig.MaxRetries; attempt++ {
 			if ctx.Err() != nil {
 				state.lastErr = ctx.Err()
 				c.Err = state.lastErr
 				return
 			}
 
-			attemptStartTime := time.Now()
-			attemptRecord := &domain.ProxyUpstreamAttempt{
-				ProxyRequestID: proxyReq.ID,
-				RouteID:        matchedRoute.Route.ID,
-				ProviderID:     matchedRoute.Provider.ID,
-				IsStream:       state.isStream,
-				Status:         "IN_PROGRESS",
-				StartTime:      attemptStartTime,
-				RequestModel:   state.requestModel,
-				MappedModel:    mappedModel,
-				RequestInfo:    proxyReq.RequestInfo,
+			// 熔断半开时只放行有限的试探请求，名额已满则跳过该路由
+			var ok bool
+			trial, ok = cooldown.Default().AcquireTrial(matchedRoute.Provider.ID, string(state.clientType))
+			if !ok {
+				log.Printf("[Executor] Provider %d is half-open and out of trial slots, skipping route", matchedRoute.Provider.ID)
+				continue
 			}
-			if err := e.attemptRepo.Create(attemptRecord); err != nil {
-				log.Printf("[Executor] Failed to create attempt record: %v", err)
-			}
-			state.currentAttempt = attemptRecord
 
-			proxyReq.ProxyUpstreamAttemptCount++
+			proxyReq.RouteID = matchedRoute.Route.ID
+			proxyReq.ProviderID = matchedRoute.Provider.ID
+			_ = e.proxyRequestRepo.Update(proxyReq)
 			if e.broadcaster != nil {
 				e.broadcaster.BroadcastProxyRequest(proxyReq)
-				e.broadcaster.BroadcastProxyUpstreamAttempt(attemptRecord)
 			}
 
-			eventChan := domain.NewAdapterEventChan()
-			c.Set(flow.KeyClientType, currentClientType)
-			c.Set(flow.KeyOriginalClientType, originalClientType)
-			c.Set(flow.KeyMappedModel, mappedModel)
-			c.Set(flow.KeyRequestBody, requestBody)
-			c.Set(flow.KeyRequestURI, requestURI)
-			c.Set(flow.KeyRequestHeaders, state.requestHeaders)
-			c.Set(flow.KeyProxyRequest, state.proxyReq)
-			c.Set(flow.KeyUpstreamAttempt, attemptRecord)
-			c.Set(flow.KeyEventChan, eventChan)
-			c.Set(flow.KeyBroadcaster, e.broadcaster)
-			eventDone := make(chan struct{})
-			go e.processAdapterEventsRealtime(eventChan, attemptRecord, eventDone, clearDetail)
-
-			var responseWriter http.ResponseWriter
-			var convertingWriter *ConvertingResponseWriter
-			responseCapture := NewResponseCapture(c.Writer)
-			if needsConversion {
-				convertingWriter = NewConvertingResponseWriter(
-					responseCapture, e.converter, originalClientType, currentClientType, state.isStream, state.originalRequestBody)
-				responseWriter = convertingWriter
-			} else {
-				responseWriter = responseCapture
-			}
+			clientType := state.clientType
+			mappedModel := e.mapModel(state.routeModel(), matchedRoute.Route, matchedRoute.Provider, clientType, state.projectID, state.apiTokenID)
 
-			originalWriter := c.Writer
-			c.Writer = responseWriter
-			err := matchedRoute.ProviderAdapter.Execute(c, matchedRoute.Provider)
-			c.Writer = originalWriter
+			originalClientType := clientType
+			currentClientType := clientType
+			needsConversion := false
+			convertedBody := []byte(nil)
+			var convErr error
+			requestBody := state.requestBody
+			requestURI := state.requestURI
 
-			if needsConversion && convertingWriter != nil && !state.isStream {
-				if finalizeErr := convertingWriter.Finalize(); finalizeErr != nil {
-					log.Printf("[Executor] Response conversion finalize failed: %v", finalizeErr)
+			supportedTypes := matchedRoute.ProviderAdapter.SupportedClientTypes()
+			if modelAware, ok := matchedRoute.ProviderAdapter.(provider.ModelAwareAdapter); ok {
+				supportedTypes = modelAware.SupportedClientTypesForModel(mappedModel)
+			}
+			if e.converter.NeedConvert(clientType, supportedTypes) {
+				currentClientType = GetPreferredTargetType(supportedTypes, clientType, matchedRoute.Provider.Type)
+				if currentClientType != clientType {
+					needsConversion = true
+					log.Printf("[Executor] Format conversion needed: %s -> %s for provider %s",
+						clientType, currentClientType, matchedRoute.Provider.Name)
+
+					if currentClientType == domain.ClientTypeCodex {
+						if headers := state.requestHeaders; headers != nil {
+							requestBody = converter.InjectCodexUserAgent(requestBody, headers.Get("User-Agent"))
+						}
+					}
+					convertedBody, convErr = e.converter.TransformRequest(
+						clientType, currentClientType, requestBody, mappedModel, state.isStream)
+					if convErr != nil {
+						log.Printf("[Executor] Request conversion failed: %v, proceeding with original format", convErr)
+						needsConversion = false
+						currentClientType = clientType
+					} else {
+						requestBody = convertedBody
+
+						originalURI := requestURI
+						convertedURI := ConvertRequestURI(requestURI, clientType, currentClientType, mappedModel, state.isStream)
+						if convertedURI != originalURI {
+							requestURI = convertedURI
+							log.Printf("[Executor] URI converted: %s -> %s", originalURI, convertedURI)
+						}
+					}
 				}
 			}
 
-			eventChan.Close()
-			<-eventDone
+			retryConfig := e.getRetryConfig(matchedRoute.RetryConfig)
 
-			if err == nil {
-				attemptRecord.EndTime = time.Now()
-				attemptRecord.Duration = attemptRecord.EndTime.Sub(attemptRecord.StartTime)
-				attemptRecord.Status = "COMPLETED"
-
-				if attemptRecord.InputTokenCount > 0 || attemptRecord.OutputTokenCount > 0 {
-					metrics := &usage.Metrics{
-						InputTokens:          attemptRecord.InputTokenCount,
-						OutputTokens:         attemptRecord.OutputTokenCount,
-						CacheReadCount:       attemptRecord.CacheReadCount,
-						CacheCreationCount:   attemptRecord.CacheWriteCount,
-						Cache5mCreationCount: attemptRecord.Cache5mWriteCount,
-						Cache1hCreationCount: attemptRecord.Cache1hWriteCount,
-					}
-					pricingModel := attemptRecord.ResponseModel
-					if pricingModel == "" {
-						pricingModel = attemptRecord.MappedModel
-					}
-					multiplier := getProviderMultiplier(matchedRoute.Provider, clientType)
-					result := pricing.GlobalCalculator().CalculateWithResult(pricingModel, metrics, multiplier)
-					attemptRecord.Cost = result.Cost
-					attemptRecord.ModelPriceID = result.ModelPriceID
-					attemptRecord.Multiplier = result.Multiplier
+			for attempt := 0; attempt <= retryConfig.MaxRetries; attempt++ {
+				if ctx.Err() != nil {
+					state.lastErr = ctx.Err()
+					c.Err = state.lastErr
+					return
 				}
 
-				if clearDetail {
-					attemptRecord.RequestInfo = nil
-					attemptRecord.ResponseInfo = nil
+				attemptStartTime := time.Now()
+				attemptRecord := &domain.ProxyUpstreamAttempt{
+					ProxyRequestID: proxyReq.ID,
+					RouteID:        matchedRoute.Route.ID,
+					ProviderID:     matchedRoute.Provider.ID,
+					IsStream:       state.isStream,
+					Status:         "IN_PROGRESS",
+					StartTime:      attemptStartTime,
+					RequestModel:   state.requestModel,
+					MappedModel:    mappedModel,
+					FallbackStep:   state.fallbackStep,
+					RequestInfo:    proxyReq.RequestInfo,
+				}
+				if state.fallbackStep > 0 {
+					attemptRecord.FallbackModel = state.routeModel()
+				}
+				if err := e.attemptRepo.Create(attemptRecord); err != nil {
+					log.Printf("[Executor] Failed to create attempt record: %v", err)
 				}
+				state.currentAttempt = attemptRecord
 
-				_ = e.attemptRepo.Update(attemptRecord)
+				proxyReq.ProxyUpstreamAttemptCount++
 				if e.broadcaster != nil {
+					e.broadcaster.BroadcastProxyRequest(proxyReq)
 					e.broadcaster.BroadcastProxyUpstreamAttempt(attemptRecord)
 				}
-				state.currentAttempt = nil
 
-				cooldown.Default().RecordSuccess(matchedRoute.Provider.ID, string(currentClientType))
+				eventChan := domain.NewAdapterEventChan()
+				c.Set(flow.KeyClientType, currentClientType)
+				c.Set(flow.KeyOriginalClientType, originalClientType)
+				c.Set(flow.KeyMappedModel, mappedModel)
+				c.Set(flow.KeyRequestBody, requestBody)
+				c.Set(flow.KeyRequestURI, requestURI)
+				c.Set(flow.KeyRequestHeaders, state.requestHeaders)
+				c.Set(flow.KeyProxyRequest, state.proxyReq)
+				c.Set(flow.KeyUpstreamAttempt, attemptRecord)
+				c.Set(flow.KeyEventChan, eventChan)
+				c.Set(flow.KeyBroadcaster, e.broadcaster)
+				eventDone := make(chan struct{})
+				go e.processAdapterEventsRealtime(eventChan, attemptRecord, eventDone, clearDetail, state.detailBodyLimit)
+
+				var responseWriter http.ResponseWriter
+				var convertingWriter *ConvertingResponseWriter
+				var clientWriter http.ResponseWriter = c.Writer
+				if guard != nil {
+					clientWriter = guard
+				}
+				if state.fallbackStep > 0 && state.notifyFallback {
+					clientWriter.Header().Set(fallbackModelHeader, state.routeModel())
+				}
+				responseCapture := NewResponseCapture(clientWriter, state.detailBodyLimit)
+				if needsConversion {
+					convertingWriter = NewConvertingResponseWriter(
+						responseCapture, e.converter, originalClientType, currentClientType, state.isStream, state.originalRequestBody)
+					responseWriter = convertingWriter
+				} else {
+					responseWriter = responseCapture
+				}
 
-				proxyReq.Status = "COMPLETED"
-				proxyReq.EndTime = time.Now()
-				proxyReq.Duration = proxyReq.EndTime.Sub(proxyReq.StartTime)
-				proxyReq.FinalProxyUpstreamAttemptID = attemptRecord.ID
-				proxyReq.ModelPriceID = attemptRecord.ModelPriceID
-				proxyReq.Multiplier = attemptRecord.Multiplier
-				proxyReq.ResponseModel = mappedModel
+				originalWriter := c.Writer
+				c.Writer = responseWriter
+				err := matchedRoute.ProviderAdapter.Execute(c, matchedRoute.Provider)
+				c.Writer = originalWriter
 
-				if !clearDetail {
-					proxyReq.ResponseInfo = &domain.ResponseInfo{
-						Status:  responseCapture.StatusCode(),
-						Headers: responseCapture.CapturedHeaders(),
-						Body:    responseCapture.Body(),
+				if needsConversion && convertingWriter != nil && !state.isStream {
+					if finalizeErr := convertingWriter.Finalize(); finalizeErr != nil {
+						log.Printf("[Executor] Response conversion finalize failed: %v", finalizeErr)
 					}
 				}
-				proxyReq.StatusCode = responseCapture.StatusCode()
-
-				if metrics := usage.ExtractFromResponse(responseCapture.Body()); metrics != nil {
-					proxyReq.InputTokenCount = metrics.InputTokens
-					proxyReq.OutputTokenCount = metrics.OutputTokens
-					proxyReq.CacheReadCount = metrics.CacheReadCount
-					proxyReq.CacheWriteCount = metrics.CacheCreationCount
-					proxyReq.Cache5mWriteCount = metrics.Cache5mCreationCount
-					proxyReq.Cache1hWriteCount = metrics.Cache1hCreationCount
-				}
-				proxyReq.Cost = attemptRecord.Cost
-				proxyReq.TTFT = attemptRecord.TTFT
 
-				clearProxyRequestDetail(proxyReq, clearDetail)
+				eventChan.Close()
+				<-eventDone
 
-				_ = e.proxyRequestRepo.Update(proxyReq)
-				if e.broadcaster != nil {
-					e.broadcaster.BroadcastProxyRequest(proxyReq)
+				if guard != nil {
+					err = guard.settle(err)
 				}
 
-				state.lastErr = nil
-				state.ctx = ctx
-				return
-			}
+				if err == nil {
+					attemptRecord.EndTime = time.Now()
+					attemptRecord.Duration = attemptRecord.EndTime.Sub(attemptRecord.StartTime)
+					attemptRecord.Status = "COMPLETED"
 
-			attemptRecord.EndTime = time.Now()
-			attemptRecord.Duration = attemptRecord.EndTime.Sub(attemptRecord.StartTime)
-			state.lastErr = err
+					applyAttemptCost(attemptRecord, proxyReq, matchedRoute.Provider, clientType)
 
-			if ctx.Err() != nil {
-				attemptRecord.Status = "CANCELLED"
-			} else {
-				attemptRecord.Status = "FAILED"
-			}
+					if clearDetail {
+						attemptRecord.RequestInfo = nil
+						attemptRecord.ResponseInfo = nil
+					}
 
-			if attemptRecord.InputTokenCount > 0 || attemptRecord.OutputTokenCount > 0 {
-				metrics := &usage.Metrics{
-					InputTokens:          attemptRecord.InputTokenCount,
-					OutputTokens:         attemptRecord.OutputTokenCount,
-					CacheReadCount:       attemptRecord.CacheReadCount,
-					CacheCreationCount:   attemptRecord.CacheWriteCount,
-					Cache5mCreationCount: attemptRecord.Cache5mWriteCount,
-					Cache1hCreationCount: attemptRecord.Cache1hWriteCount,
-				}
-				pricingModel := attemptRecord.ResponseModel
-				if pricingModel == "" {
-					pricingModel = attemptRecord.MappedModel
-				}
-				multiplier := getProviderMultiplier(matchedRoute.Provider, clientType)
-				result := pricing.GlobalCalculator().CalculateWithResult(pricingModel, metrics, multiplier)
-				attemptRecord.Cost = result.Cost
-				attemptRecord.ModelPriceID = result.ModelPriceID
-				attemptRecord.Multiplier = result.Multiplier
-			}
+					_ = e.attemptRepo.Update(attemptRecord)
+					if e.broadcaster != nil {
+						e.broadcaster.BroadcastProxyUpstreamAttempt(attemptRecord)
+					}
+					state.currentAttempt = nil
 
-			if clearDetail {
-				attemptRecord.RequestInfo = nil
-				attemptRecord.ResponseInfo = nil
-			}
+					cooldown.Default().RecordSuccess(matchedRoute.Provider.ID, string(originalClientType), mappedModel)
+					if currentClientType != originalClientType {
+						cooldown.Default().RecordSuccess(matchedRoute.Provider.ID, string(currentClientType), mappedModel)
+					}
 
-			_ = e.attemptRepo.Update(attemptRecord)
-			if e.broadcaster != nil {
-				e.broadcaster.BroadcastProxyUpstreamAttempt(attemptRecord)
-			}
-			state.currentAttempt = nil
-
-			proxyReq.FinalProxyUpstreamAttemptID = attemptRecord.ID
-			proxyReq.ModelPriceID = attemptRecord.ModelPriceID
-			proxyReq.Multiplier = attemptRecord.Multiplier
-
-			if responseCapture.Body() != "" {
-				proxyReq.StatusCode = responseCapture.StatusCode()
-				if !clearDetail {
-					proxyReq.ResponseInfo = &domain.ResponseInfo{
-						Status:  responseCapture.StatusCode(),
-						Headers: responseCapture.CapturedHeaders(),
-						Body:    responseCapture.Body(),
+					proxyReq.Status = "COMPLETED"
+					proxyReq.EndTime = time.Now()
+					proxyReq.Duration = proxyReq.EndTime.Sub(proxyReq.StartTime)
+					proxyReq.FinalProxyUpstreamAttemptID = attemptRecord.ID
+					proxyReq.ModelPriceID = attemptRecord.ModelPriceID
+					proxyReq.Multiplier = attemptRecord.Multiplier
+					proxyReq.ResponseModel = mappedModel
+
+					if !clearDetail {
+						proxyReq.ResponseInfo = &domain.ResponseInfo{
+							Status:  responseCapture.StatusCode(),
+							Headers: responseCapture.CapturedHeaders(),
+							Body:    responseCapture.Body(),
+						}
 					}
-				}
-				if metrics := usage.ExtractFromResponse(responseCapture.Body()); metrics != nil {
-					proxyReq.InputTokenCount = metrics.InputTokens
-					proxyReq.OutputTokenCount = metrics.OutputTokens
-					proxyReq.CacheReadCount = metrics.CacheReadCount
-					proxyReq.CacheWriteCount = metrics.CacheCreationCount
-					proxyReq.Cache5mWriteCount = metrics.Cache5mCreationCount
-					proxyReq.Cache1hWriteCount = metrics.Cache1hCreationCount
-				}
-			}
-			proxyReq.Cost = attemptRecord.Cost
-			proxyReq.TTFT = attemptRecord.TTFT
+					proxyReq.StatusCode = responseCapture.StatusCode()
+
+					if metrics := responseUsage(attemptRecord, responseCapture); metrics != nil {
+						proxyReq.InputTokenCount = metrics.InputTokens
+						proxyReq.OutputTokenCount = metrics.OutputTokens
+						proxyReq.CacheReadCount = metrics.CacheReadCount
+						proxyReq.CacheWriteCount = metrics.CacheCreationCount
+						proxyReq.Cache5mWriteCount = metrics.Cache5mCreationCount
+						proxyReq.Cache1hWriteCount = metrics.Cache1hCreationCount
+					}
+					proxyReq.Cost = attemptRecord.Cost
+					proxyReq.UpstreamCost = attemptRecord.UpstreamCost
+					proxyReq.BilledCost += attemptRecord.BilledCost
+					proxyReq.TTFT = attemptRecord.TTFT
 
-			clearProxyRequestDetail(proxyReq, clearDetail)
+					clearProxyRequestDetail(proxyReq, clearDetail)
 
-			_ = e.proxyRequestRepo.Update(proxyReq)
-			if e.broadcaster != nil {
-				e.broadcaster.BroadcastProxyRequest(proxyReq)
-			}
+					_ = e.proxyRequestRepo.Update(proxyReq)
+					if e.broadcaster != nil {
+						e.broadcaster.BroadcastProxyRequest(proxyReq)
+					}
+
+					state.lastErr = nil
+					state.ctx = ctx
+					return
+				}
 
-			proxyErr, ok := err.(*domain.ProxyError)
-			if ok && ctx.Err() != nil {
-				proxyReq.Status = "CANCELLED"
-				proxyReq.EndTime = time.Now()
-				proxyReq.Duration = proxyReq.EndTime.Sub(proxyReq.StartTime)
-				if ctx.Err() == context.Canceled {
-					proxyReq.Error = "client disconnected"
-				} else if ctx.Err() == context.DeadlineExceeded {
-					proxyReq.Error = "request timeout"
+				attemptRecord.EndTime = time.Now()
+				attemptRecord.Duration = attemptRecord.EndTime.Sub(attemptRecord.StartTime)
+				state.lastErr = err
+
+				if ctx.Err() != nil {
+					attemptRecord.Status = "CANCELLED"
+					attemptRecord.FailureReason = domain.AttemptReasonCancelled
 				} else {
-					proxyReq.Error = ctx.Err().Error()
+					attemptRecord.Status = "FAILED"
+					attemptRecord.FailureReason = attemptFailureReason(err)
 				}
-				clearProxyRequestDetail(proxyReq, clearDetail)
-				_ = e.proxyRequestRepo.Update(proxyReq)
+
+				applyAttemptCost(attemptRecord, proxyReq, matchedRoute.Provider, clientType)
+
+				if clearDetail {
+					attemptRecord.RequestInfo = nil
+					attemptRecord.ResponseInfo = nil
+				}
+
+				_ = e.attemptRepo.Update(attemptRecord)
 				if e.broadcaster != nil {
-					e.broadcaster.BroadcastProxyRequest(proxyReq)
+					e.broadcaster.BroadcastProxyUpstreamAttempt(attemptRecord)
 				}
-				state.lastErr = ctx.Err()
-				c.Err = state.lastErr
-				return
-			}
+				state.currentAttempt = nil
 
-			if ok && ctx.Err() != context.Canceled {
-				log.Printf("[Executor] ProxyError - IsNetworkError: %v, IsServerError: %v, Retryable: %v, Provider: %d",
-					proxyErr.IsNetworkError, proxyErr.IsServerError, proxyErr.Retryable, matchedRoute.Provider.ID)
-				if !shouldSkipErrorCooldown(matchedRoute.Provider) {
-					e.handleCooldown(proxyErr, matchedRoute.Provider, currentClientType, originalClientType)
-					if e.broadcaster != nil {
-						e.broadcaster.BroadcastMessage("cooldown_update", map[string]interface{}{
-							"providerID": matchedRoute.Provider.ID,
-						})
+				proxyReq.FinalProxyUpstreamAttemptID = attemptRecord.ID
+				proxyReq.ModelPriceID = attemptRecord.ModelPriceID
+				proxyReq.Multiplier = attemptRecord.Multiplier
+
+				if responseCapture.Body() != "" {
+					proxyReq.StatusCode = responseCapture.StatusCode()
+					if !clearDetail {
+						proxyReq.ResponseInfo = &domain.ResponseInfo{
+							Status:  responseCapture.StatusCode(),
+							Headers: responseCapture.CapturedHeaders(),
+							Body:    responseCapture.Body(),
+						}
+					}
+					if metrics := responseUsage(attemptRecord, responseCapture); metrics != nil {
+						proxyReq.InputTokenCount = metrics.InputTokens
+						proxyReq.OutputTokenCount = metrics.OutputTokens
+						proxyReq.CacheReadCount = metrics.CacheReadCount
+						proxyReq.CacheWriteCount = metrics.CacheCreationCount
+						proxyReq.Cache5mWriteCount = metrics.Cache5mCreationCount
+						proxyReq.Cache1hWriteCount = metrics.Cache1hCreationCount
 					}
 				}
-			} else if ok && ctx.Err() == context.Canceled {
-				log.Printf("[Executor] Client disconnected, skipping cooldown for Provider: %d", matchedRoute.Provider.ID)
-			} else if !ok {
-				log.Printf("[Executor] Error is not ProxyError, type: %T, error: %v", err, err)
-			}
+				proxyReq.Cost = attemptRecord.Cost
+				proxyReq.UpstreamCost = attemptRecord.UpstreamCost
+				proxyReq.BilledCost += attemptRecord.BilledCost
+				proxyReq.TTFT = attemptRecord.TTFT
 
-			if !ok || !proxyErr.Retryable {
-				break
-			}
+				clearProxyRequestDetail(proxyReq, clearDetail)
 
-			if attempt < retryConfig.MaxRetries {
-				waitTime := e.calculateBackoff(retryConfig, attempt)
-				if proxyErr.RetryAfter > 0 {
-					waitTime = proxyErr.RetryAfter
+				_ = e.proxyRequestRepo.Update(proxyReq)
+				if e.broadcaster != nil {
+					e.broadcaster.BroadcastProxyRequest(proxyReq)
 				}
-				select {
-				case <-ctx.Done():
+
+				proxyErr, ok := err.(*domain.ProxyError)
+				if ok && ctx.Err() != nil {
 					proxyReq.Status = "CANCELLED"
 					proxyReq.EndTime = time.Now()
 					proxyReq.Duration = proxyReq.EndTime.Sub(proxyReq.StartTime)
 					if ctx.Err() == context.Canceled {
-						proxyReq.Error = "client disconnected during retry wait"
+						proxyReq.Error = "client disconnected"
 					} else if ctx.Err() == context.DeadlineExceeded {
-						proxyReq.Error = "request timeout during retry wait"
+						proxyReq.Error = "request timeout"
 					} else {
 						proxyReq.Error = ctx.Err().Error()
 					}
@@ -374,10 +344,69 @@ func (e *Executor) dispatch(c *flow.Ctx) {
 					state.lastErr = ctx.Err()
 					c.Err = state.lastErr
 					return
-				case <-time.After(waitTime):
+				}
+
+				if ok && ctx.Err() != context.Canceled {
+					log.Printf("[Executor] ProxyError - IsNetworkError: %v, IsServerError: %v, Retryable: %v, Provider: %d",
+						proxyErr.IsNetworkError, proxyErr.IsServerError, proxyErr.Retryable, matchedRoute.Provider.ID)
+					if !shouldSkipErrorCooldown(matchedRoute.Provider) {
+						e.handleCooldown(proxyErr, matchedRoute.Provider, currentClientType, originalClientType)
+						if e.broadcaster != nil {
+							e.broadcaster.BroadcastMessage("cooldown_update", map[string]interface{}{
+								"providerID": matchedRoute.Provider.ID,
+							})
+						}
+					}
+				} else if ok && ctx.Err() == context.Canceled {
+					log.Printf("[Executor] Client disconnected, skipping cooldown for Provider: %d", matchedRoute.Provider.ID)
+				} else if !ok {
+					log.Printf("[Executor] Error is not ProxyError, type: %T, error: %v", err, err)
+				}
+
+				// 内容已输出到客户端，无法再切换路由
+				if guard != nil && guard.Committed() {
+					break routes
+				}
+
+				if !ok || !proxyErr.Retryable {
+					break
+				}
+
+				if attempt < retryConfig.MaxRetries {
+					waitTime := e.calculateBackoff(retryConfig, attempt)
+					if proxyErr.RetryAfter > 0 {
+						waitTime = proxyErr.RetryAfter
+					}
+					select {
+					case <-ctx.Done():
+						proxyReq.Status = "CANCELLED"
+						proxyReq.EndTime = time.Now()
+						proxyReq.Duration = proxyReq.EndTime.Sub(proxyReq.StartTime)
+						if ctx.Err() == context.Canceled {
+							proxyReq.Error = "client disconnected during retry wait"
+						} else if ctx.Err() == context.DeadlineExceeded {
+							proxyReq.Error = "request timeout during retry wait"
+						} else {
+							proxyReq.Error = ctx.Err().Error()
+						}
+						clearProxyRequestDetail(proxyReq, clearDetail)
+						_ = e.proxyRequestRepo.Update(proxyReq)
+						if e.broadcaster != nil {
+							e.broadcaster.BroadcastProxyRequest(proxyReq)
+						}
+						state.lastErr = ctx.Err()
+						c.Err = state.lastErr
+						return
+					case <-time.After(waitTime):
+					}
 				}
 			}
 		}
+
+		// 内容已输出到客户端或没有更多降级模型时结束
+		if ctx.Err() != nil || (guard != nil && guard.Committed()) || !e.fallbackRoutes(state) {
+			break
+		}
 	}
 
 	proxyReq.Status = "FAILED"
@@ -395,10 +424,57 @@ func (e *Executor) dispatch(c *flow.Ctx) {
 	if state.lastErr == nil {
 		state.lastErr = domain.NewProxyErrorWithMessage(domain.ErrAllRoutesFailed, false, "all routes exhausted")
 	}
+	if guard != nil && guard.Committed() {
+		// 以客户端格式结束流，而不是直接断开连接
+		guard.terminate(state.lastErr.Error())
+		c.Set(flow.KeyStreamTerminated, true)
+	}
 	state.ctx = ctx
 	c.Err = state.lastErr
 }
 
+// attemptMetrics returns the usage reported by the adapter for an attempt, nil if none
+func attemptMetrics(attempt *domain.ProxyUpstreamAttempt) *usage.Metrics {
+	if attempt.InputTokenCount == 0 && attempt.OutputTokenCount == 0 {
+		return nil
+	}
+	return &usage.Metrics{
+		InputTokens:          attempt.InputTokenCount,
+		OutputTokens:         attempt.OutputTokenCount,
+		CacheReadCount:       attempt.CacheReadCount,
+		CacheCreationCount:   attempt.CacheWriteCount,
+		Cache5mCreationCount: attempt.Cache5mWriteCount,
+		Cache1hCreationCount: attempt.Cache1hWriteCount,
+	}
+}
+
+// responseUsage 优先使用适配器上报的用量；详情 body 被截断时不能整体解析，只从保留的尾部提取
+func responseUsage(attempt *domain.ProxyUpstreamAttempt, capture *ResponseCapture) *usage.Metrics {
+	if metrics := attemptMetrics(attempt); metrics != nil {
+		return metrics
+	}
+	return usage.ExtractFromResponse(capture.usageBody())
+}
+
+// applyAttemptCost 计算 attempt 的成本：上游成本、按倍率调整后的成本，以及按计费规则的计费金额
+// 需要在设置 attempt 最终状态之后调用（固定费用只对成功的 attempt 收取）
+// 每个 attempt 单独计费，请求的计费金额是所有 attempt 之和
+func applyAttemptCost(attempt *domain.ProxyUpstreamAttempt, proxyReq *domain.ProxyRequest, provider *domain.Provider, clientType domain.ClientType) {
+	if metrics := attemptMetrics(attempt); metrics != nil {
+		pricingModel := attempt.ResponseModel
+		if pricingModel == "" {
+			pricingModel = attempt.MappedModel
+		}
+		multiplier := getProviderMultiplier(provider, clientType)
+		result := pricing.GlobalCalculator().CalculateForProvider(provider.ID, pricingModel, metrics, multiplier)
+		attempt.Cost = result.Cost
+		attempt.UpstreamCost = result.UpstreamCost
+		attempt.ModelPriceID = result.ModelPriceID
+		attempt.Multiplier = result.Multiplier
+	}
+	attempt.BilledCost = billing.Default().Bill(proxyReq.ProjectID, proxyReq.APITokenID, attempt.Cost, attempt.Status == "COMPLETED", attempt.EndTime)
+}
+
 func clearProxyRequestDetail(req *domain.ProxyRequest, clearDetail bool) {
 	if !clearDetail || req == nil {
 		return
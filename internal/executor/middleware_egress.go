@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/awsl-project/maxx/internal/domain"
 	"github.com/awsl-project/maxx/internal/flow"
 )
 
@@ -43,8 +44,10 @@ func (e *Executor) egress(c *flow.Ctx) {
 		state.currentAttempt.Duration = state.currentAttempt.EndTime.Sub(state.currentAttempt.StartTime)
 		if state.ctx != nil && state.ctx.Err() != nil {
 			state.currentAttempt.Status = "CANCELLED"
+			state.currentAttempt.FailureReason = domain.AttemptReasonCancelled
 		} else {
 			state.currentAttempt.Status = "FAILED"
+			state.currentAttempt.FailureReason = attemptFailureReason(state.lastErr)
 		}
 		_ = e.attemptRepo.Update(state.currentAttempt)
 		if e.broadcaster != nil {
@@ -52,5 +55,12 @@ func (e *Executor) egress(c *flow.Ctx) {
 		}
 	}
 
+	// 累计会话用量，供循环检测的 Token/成本上限使用
+	if proxyReq != nil && e.loopGuard != nil {
+		e.loopGuard.Record(proxyReq.SessionID,
+			proxyReq.InputTokenCount+proxyReq.OutputTokenCount+proxyReq.CacheReadCount+proxyReq.CacheWriteCount,
+			proxyReq.Cost)
+	}
+
 	_ = state.lastErr
 }
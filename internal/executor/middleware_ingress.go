@@ -9,6 +9,7 @@ import (
 
 	"github.com/awsl-project/maxx/internal/domain"
 	"github.com/awsl-project/maxx/internal/flow"
+	"github.com/awsl-project/maxx/internal/waiter"
 )
 
 func (e *Executor) ingress(c *flow.Ctx) {
@@ -56,6 +57,21 @@ func (e *Executor) ingress(c *flow.Ctx) {
 			state.apiTokenDevMode = devMode
 		}
 	}
+	if v, ok := c.Get(flow.KeyAPITokenProviders); ok {
+		if ids, ok := v.([]uint64); ok {
+			state.apiTokenProviders = ids
+		}
+	}
+	if v, ok := c.Get(flow.KeyAPITokenModels); ok {
+		if models, ok := v.([]string); ok {
+			state.apiTokenModels = models
+		}
+	}
+	if v, ok := c.Get(flow.KeyAPITokenPriority); ok {
+		if p, ok := v.(domain.PriorityClass); ok {
+			state.apiTokenPriority = p
+		}
+	}
 	if v, ok := c.Get(flow.KeyRequestBody); ok {
 		if body, ok := v.([]byte); ok {
 			state.requestBody = body
@@ -95,6 +111,7 @@ func (e *Executor) ingress(c *flow.Ctx) {
 	}
 
 	clearDetail := e.shouldClearRequestDetailFor(state)
+	state.detailBodyLimit = e.getDetailMaxBodyBytes()
 	if !clearDetail {
 		requestURI := state.requestURI
 		requestHeaders := state.requestHeaders
@@ -111,7 +128,7 @@ func (e *Executor) ingress(c *flow.Ctx) {
 				Method:  c.Request.Method,
 				URL:     requestURI,
 				Headers: headers,
-				Body:    string(requestBody),
+				Body:    truncateBody(string(requestBody), state.detailBodyLimit),
 			}
 		}
 	}
@@ -172,5 +189,64 @@ func (e *Executor) ingress(c *flow.Ctx) {
 		state.ctx = ctx
 	}
 
+	if e.loopGuard != nil && !e.checkLoopGuard(c, state) {
+		return
+	}
+
 	c.Next()
 }
+
+// checkLoopGuard 会话循环检测，触发后按配置直接拒绝或等待管理员确认，返回是否继续处理
+func (e *Executor) checkLoopGuard(c *flow.Ctx, state *execState) bool {
+	cfg := e.loopGuard.Config()
+	trip := e.loopGuard.Check(cfg, state.sessionID, state.requestModel, state.requestBody)
+	if trip == nil {
+		return true
+	}
+
+	var err error = waiter.ErrSessionLoopDetected
+	if cfg.Action == waiter.LoopActionApprove && e.projectWaiter != nil {
+		// 审批结果记录在会话上，等待前必须确保会话已持久化，否则审批无法生效，只能等到超时
+		session, _ := e.sessionRepo.GetBySessionID(state.sessionID)
+		if session == nil {
+			session = &domain.Session{SessionID: state.sessionID, ClientType: state.clientType, ProjectID: state.projectID}
+			if createErr := e.sessionRepo.Create(session); createErr != nil {
+				log.Printf("[Executor] Failed to create session %s for loop approval: %v", state.sessionID, createErr)
+				session = nil
+			}
+		}
+		if session != nil {
+			err = e.projectWaiter.WaitForLoopApproval(state.ctx, session, trip)
+			if err == nil {
+				e.loopGuard.Reset(state.sessionID)
+				return true
+			}
+		}
+	}
+
+	proxyReq := state.proxyReq
+	proxyReq.Status = "REJECTED"
+	proxyReq.Error = "loop guard: " + trip.Message() + ": " + err.Error()
+	if errors.Is(err, context.Canceled) {
+		proxyReq.Status = "CANCELLED"
+		proxyReq.Error = "client cancelled: " + err.Error()
+		if e.broadcaster != nil {
+			e.broadcaster.BroadcastMessage("session_pending_cancelled", map[string]interface{}{
+				"sessionID": state.sessionID,
+			})
+		}
+	}
+	proxyReq.EndTime = time.Now()
+	proxyReq.Duration = proxyReq.EndTime.Sub(proxyReq.StartTime)
+	_ = e.proxyRequestRepo.Update(proxyReq)
+
+	if e.broadcaster != nil {
+		e.broadcaster.BroadcastProxyRequest(proxyReq)
+	}
+
+	proxyErr := domain.NewProxyErrorWithMessage(err, false, "loop guard: "+trip.Message())
+	state.lastErr = proxyErr
+	c.Err = proxyErr
+	c.Abort()
+	return false
+}
@@ -1,13 +1,14 @@
 package executor
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/awsl-project/maxx/internal/admission"
 	"github.com/awsl-project/maxx/internal/domain"
 	"github.com/awsl-project/maxx/internal/flow"
-	"github.com/awsl-project/maxx/internal/router"
 )
 
 func (e *Executor) routeMatch(c *flow.Ctx) {
@@ -20,12 +21,39 @@ func (e *Executor) routeMatch(c *flow.Ctx) {
 	}
 
 	proxyReq := state.proxyReq
-	routes, err := e.router.Match(&router.MatchContext{
-		ClientType:   state.clientType,
-		ProjectID:    state.projectID,
-		RequestModel: state.requestModel,
-		APITokenID:   state.apiTokenID,
-	})
+	e.resolveFallback(state)
+	matchCtx := e.newMatchContext(state)
+
+	// 准入队列：路由全部冷却时等待最早的冷却结束，并发已满时按优先级等待空闲槽位
+	queueStart := time.Now()
+	var (
+		queueCfg      admission.Config
+		queueClass    domain.PriorityClass
+		queueDeadline time.Time
+	)
+	if e.admissionQueue != nil {
+		queueCfg = e.admissionQueue.Config()
+	}
+	if queueCfg.Enabled {
+		queueClass = e.admissionQueue.ClassFor(state.apiTokenPriority, state.projectID)
+		queueDeadline = queueStart.Add(queueCfg.MaxWait)
+	}
+
+	routes, err := e.matchRoutes(state, matchCtx)
+	for queueCfg.Enabled && errors.Is(err, domain.ErrNoRoutes) {
+		// 降级链上的模型也都不可用：等待原始模型的路由冷却结束，之后重新走一遍降级链
+		state.fallbackStep = 0
+		matchCtx.RequestModel = state.routeModel()
+		until, ok := e.router.EarliestCooldownEnd(matchCtx)
+		if !ok {
+			break
+		}
+		if waitErr := e.admissionQueue.WaitCooldown(state.ctx, queueCfg, queueClass, until, queueDeadline); waitErr != nil {
+			err = fmt.Errorf("%w (%v)", err, waitErr)
+			break
+		}
+		routes, err = e.matchRoutes(state, matchCtx)
+	}
 	if err != nil {
 		proxyReq.Status = "FAILED"
 		proxyReq.Error = "no routes available"
@@ -62,6 +90,28 @@ func (e *Executor) routeMatch(c *flow.Ctx) {
 		return
 	}
 
+	if queueCfg.Enabled {
+		release, err := e.admissionQueue.Acquire(state.ctx, queueCfg, queueClass, queueStart, queueDeadline)
+		if err != nil {
+			proxyReq.Status = "FAILED"
+			proxyReq.Error = "admission queue: " + err.Error()
+			proxyReq.EndTime = time.Now()
+			proxyReq.Duration = proxyReq.EndTime.Sub(proxyReq.StartTime)
+			if err := e.proxyRequestRepo.Update(proxyReq); err != nil {
+				log.Printf("[Executor] failed to update proxy request: %v", err)
+			}
+			if e.broadcaster != nil {
+				e.broadcaster.BroadcastProxyRequest(proxyReq)
+			}
+			proxyErr := domain.NewProxyErrorWithMessage(err, true, "admission queue: "+err.Error())
+			state.lastErr = proxyErr
+			c.Err = proxyErr
+			c.Abort()
+			return
+		}
+		defer release()
+	}
+
 	proxyReq.Status = "IN_PROGRESS"
 	if err := e.proxyRequestRepo.Update(proxyReq); err != nil {
 		log.Printf("[Executor] failed to update proxy request: %v", err)
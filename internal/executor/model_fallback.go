@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"errors"
+	"log"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/router"
+)
+
+// fallbackModelHeader 告知客户端本次请求实际使用的降级模型
+const fallbackModelHeader = "X-Maxx-Fallback-Model"
+
+// resolveFallback 查找适用于本次请求的降级链（按优先级取第一条匹配的规则）
+// Token 限制了模型范围时，范围外的降级模型会被跳过
+func (e *Executor) resolveFallback(state *execState) {
+	state.fallbackModels, state.fallbackStep, state.notifyFallback = nil, 0, false
+	if e.modelFallbackRepo == nil || state.requestModel == "" {
+		return
+	}
+	fallbacks, err := e.modelFallbackRepo.List()
+	if err != nil {
+		log.Printf("[Executor] Failed to load model fallbacks: %v", err)
+		return
+	}
+	scope := domain.APITokenScope{AllowedModels: state.apiTokenModels}
+	for _, f := range fallbacks {
+		if !f.Matches(state.clientType, state.projectID, state.requestModel) {
+			continue
+		}
+		for _, model := range f.Models {
+			if model == "" || model == state.requestModel {
+				continue
+			}
+			if !scope.AllowsModel(model) {
+				log.Printf("[Executor] Skip fallback model %s: not allowed by API token", model)
+				continue
+			}
+			state.fallbackModels = append(state.fallbackModels, model)
+		}
+		state.notifyFallback = f.NotifyClient
+		return
+	}
+}
+
+// newMatchContext 构造路由匹配条件，模型取当前降级步骤对应的模型
+func (e *Executor) newMatchContext(state *execState) *router.MatchContext {
+	return &router.MatchContext{
+		ClientType:   state.clientType,
+		ProjectID:    state.projectID,
+		RequestModel: state.routeModel(),
+		APITokenID:   state.apiTokenID,
+
+		AllowedProviderIDs: state.apiTokenProviders,
+
+		// 按映射后的模型检查模型级冷却，与实际发往上游的模型保持一致
+		MapModel: func(route *domain.Route, provider *domain.Provider) string {
+			return e.mapModel(state.routeModel(), route, provider, state.clientType, state.projectID, state.apiTokenID)
+		},
+	}
+}
+
+// matchRoutes 匹配当前模型的路由，没有可用路由（全部冷却或不支持该模型）时沿降级链依次尝试后续模型
+func (e *Executor) matchRoutes(state *execState, matchCtx *router.MatchContext) ([]*router.MatchedRoute, error) {
+	matchCtx.RequestModel = state.routeModel()
+	routes, err := e.router.Match(matchCtx)
+	for errors.Is(err, domain.ErrNoRoutes) {
+		model := state.routeModel()
+		if !state.nextFallback() {
+			break
+		}
+		log.Printf("[Executor] No routes available for model %s, falling back to %s (step %d)",
+			model, state.routeModel(), state.fallbackStep)
+		matchCtx.RequestModel = state.routeModel()
+		routes, err = e.router.Match(matchCtx)
+	}
+	return routes, err
+}
+
+// fallbackRoutes 当前模型的路由全部失败后切换到降级链上的下一个模型，并重新匹配路由
+func (e *Executor) fallbackRoutes(state *execState) bool {
+	model := state.routeModel()
+	if !state.nextFallback() {
+		return false
+	}
+	log.Printf("[Executor] All routes failed for model %s, falling back to %s (step %d)",
+		model, state.routeModel(), state.fallbackStep)
+	routes, err := e.matchRoutes(state, e.newMatchContext(state))
+	if err != nil || len(routes) == 0 {
+		return false
+	}
+	state.routes = routes
+	return true
+}
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+type stubModelFallbackRepo struct {
+	fallbacks []*domain.ModelFallback
+}
+
+func (r *stubModelFallbackRepo) Create(*domain.ModelFallback) error { return nil }
+func (r *stubModelFallbackRepo) Update(*domain.ModelFallback) error { return nil }
+func (r *stubModelFallbackRepo) Delete(uint64) error                { return nil }
+func (r *stubModelFallbackRepo) GetByID(uint64) (*domain.ModelFallback, error) {
+	return nil, domain.ErrNotFound
+}
+func (r *stubModelFallbackRepo) List() ([]*domain.ModelFallback, error) { return r.fallbacks, nil }
+
+func TestResolveFallback(t *testing.T) {
+	e := &Executor{modelFallbackRepo: &stubModelFallbackRepo{fallbacks: []*domain.ModelFallback{
+		{ID: 1, ClientType: domain.ClientTypeCodex, Pattern: "*", Models: []string{"gpt-5"}},
+		{ID: 2, ProjectID: 7, Pattern: "claude-opus-*", Models: []string{"claude-opus-4"}},
+		{ID: 3, Pattern: "claude-opus-*", Models: []string{"claude-opus-4", "claude-sonnet-4-5", "gemini-2.5-pro"}, NotifyClient: true},
+	}}}
+
+	state := &execState{clientType: domain.ClientTypeClaude, requestModel: "claude-opus-4"}
+	e.resolveFallback(state)
+
+	// 请求模型本身不会出现在降级链中
+	want := []string{"claude-sonnet-4-5", "gemini-2.5-pro"}
+	if len(state.fallbackModels) != len(want) {
+		t.Fatalf("fallbackModels = %v, want %v", state.fallbackModels, want)
+	}
+	for i, m := range want {
+		if state.fallbackModels[i] != m {
+			t.Fatalf("fallbackModels = %v, want %v", state.fallbackModels, want)
+		}
+	}
+	if !state.notifyFallback {
+		t.Error("notifyFallback should follow the matched chain")
+	}
+
+	if got := state.routeModel(); got != "claude-opus-4" {
+		t.Errorf("routeModel at step 0 = %q, want request model", got)
+	}
+	for _, m := range want {
+		if !state.nextFallback() {
+			t.Fatalf("nextFallback returned false before %s", m)
+		}
+		if got := state.routeModel(); got != m {
+			t.Errorf("routeModel = %q, want %q", got, m)
+		}
+	}
+	if state.nextFallback() {
+		t.Error("nextFallback should stop at the end of the chain")
+	}
+}
+
+func TestResolveFallbackNoMatch(t *testing.T) {
+	e := &Executor{modelFallbackRepo: &stubModelFallbackRepo{fallbacks: []*domain.ModelFallback{
+		{ID: 1, Pattern: "claude-opus-*", Models: []string{"claude-sonnet-4-5"}},
+	}}}
+
+	state := &execState{clientType: domain.ClientTypeClaude, requestModel: "claude-sonnet-4-5"}
+	e.resolveFallback(state)
+	if len(state.fallbackModels) != 0 || state.nextFallback() {
+		t.Errorf("unmatched model should have no fallback chain, got %v", state.fallbackModels)
+	}
+	if got := state.routeModel(); got != "claude-sonnet-4-5" {
+		t.Errorf("routeModel = %q, want request model", got)
+	}
+}
+
+func TestResolveFallbackTokenModelScope(t *testing.T) {
+	e := &Executor{modelFallbackRepo: &stubModelFallbackRepo{fallbacks: []*domain.ModelFallback{
+		{ID: 1, Pattern: "claude-haiku-*", Models: []string{"claude-opus-4", "claude-haiku-4-5"}},
+	}}}
+
+	// Token 只允许 haiku 系列模型，降级链不能越过该限制
+	state := &execState{
+		clientType:     domain.ClientTypeClaude,
+		requestModel:   "claude-haiku-3-5",
+		apiTokenModels: []string{"claude-haiku-*"},
+	}
+	e.resolveFallback(state)
+	if len(state.fallbackModels) != 1 || state.fallbackModels[0] != "claude-haiku-4-5" {
+		t.Fatalf("fallbackModels = %v, want [claude-haiku-4-5]", state.fallbackModels)
+	}
+
+	// 只有范围外的降级模型时没有降级链
+	state = &execState{
+		clientType:     domain.ClientTypeClaude,
+		requestModel:   "claude-haiku-4-5",
+		apiTokenModels: []string{"claude-haiku-4-5"},
+	}
+	e.resolveFallback(state)
+	if len(state.fallbackModels) != 0 || state.nextFallback() {
+		t.Errorf("out-of-scope fallbacks should be dropped, got %v", state.fallbackModels)
+	}
+}
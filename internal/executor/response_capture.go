@@ -2,23 +2,34 @@ package executor
 
 import (
 	"bytes"
+	"fmt"
 	"net/http"
+	"unicode/utf8"
 )
 
+// defaultDetailMaxBodyBytes 请求详情 body 默认上限（首尾各保留一半）
+const defaultDetailMaxBodyBytes = 1 << 20
+
 // ResponseCapture wraps http.ResponseWriter to capture the response
-// This allows us to record the actual response sent to the client
+// This allows us to record the actual response sent to the client.
+// With a limit, only the head and tail of the body are kept in memory.
 type ResponseCapture struct {
 	http.ResponseWriter
 	statusCode int
-	body       bytes.Buffer
+	body       bytes.Buffer // head
+	tail       []byte
+	total      int
+	limit      int
 	headers    http.Header
 }
 
 // NewResponseCapture creates a new ResponseCapture wrapper
-func NewResponseCapture(w http.ResponseWriter) *ResponseCapture {
+// limit <= 0 captures the whole body
+func NewResponseCapture(w http.ResponseWriter, limit int) *ResponseCapture {
 	return &ResponseCapture{
 		ResponseWriter: w,
 		statusCode:     http.StatusOK, // Default status
+		limit:          limit,
 		headers:        make(http.Header),
 	}
 }
@@ -31,10 +42,35 @@ func (rc *ResponseCapture) WriteHeader(code int) {
 
 // Write captures the body and forwards to underlying writer
 func (rc *ResponseCapture) Write(b []byte) (int, error) {
-	rc.body.Write(b)
+	rc.capture(b)
 	return rc.ResponseWriter.Write(b)
 }
 
+func (rc *ResponseCapture) capture(b []byte) {
+	rc.total += len(b)
+	if rc.limit <= 0 {
+		rc.body.Write(b)
+		return
+	}
+
+	headLimit := rc.limit / 2
+	if room := headLimit - rc.body.Len(); room > 0 {
+		n := min(room, len(b))
+		rc.body.Write(b[:n])
+		b = b[n:]
+	}
+	if len(b) == 0 {
+		return
+	}
+
+	// 尾部只保留最近 tailLimit 字节，超过两倍时压缩一次，避免每次写入都拷贝
+	tailLimit := rc.limit - headLimit
+	rc.tail = append(rc.tail, b...)
+	if len(rc.tail) > 2*tailLimit {
+		rc.tail = append([]byte(nil), rc.tail[len(rc.tail)-tailLimit:]...)
+	}
+}
+
 // Header returns the header map (for setting headers)
 func (rc *ResponseCapture) Header() http.Header {
 	return rc.ResponseWriter.Header()
@@ -52,9 +88,64 @@ func (rc *ResponseCapture) StatusCode() int {
 	return rc.statusCode
 }
 
-// Body returns the captured response body
+// Body returns the captured response body, truncated in the middle when over the limit
 func (rc *ResponseCapture) Body() string {
-	return rc.body.String()
+	if len(rc.tail) == 0 {
+		return rc.body.String()
+	}
+	tail := rc.tail
+	if tailLimit := rc.limit - rc.limit/2; len(tail) > tailLimit {
+		tail = tail[len(tail)-tailLimit:]
+	}
+	return joinTruncated(rc.body.Bytes(), tail, rc.total-rc.body.Len()-len(tail))
+}
+
+// usageBody returns the text to extract usage from: the whole body when nothing was dropped,
+// otherwise only the tail, which still holds the final usage events of a stream
+func (rc *ResponseCapture) usageBody() string {
+	if len(rc.tail) == 0 {
+		return rc.body.String()
+	}
+	tail := rc.tail
+	if tailLimit := rc.limit - rc.limit/2; len(tail) > tailLimit {
+		tail = tail[len(tail)-tailLimit:]
+	}
+	if rc.total == rc.body.Len()+len(tail) {
+		return rc.body.String() + string(tail)
+	}
+	return string(tail)
+}
+
+// truncateBody keeps the head and tail of s when it exceeds limit
+func truncateBody(s string, limit int) string {
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+	headLimit := limit / 2
+	tailLimit := limit - headLimit
+	return joinTruncated([]byte(s[:headLimit]), []byte(s[len(s)-tailLimit:]), len(s)-limit)
+}
+
+// joinTruncated joins head and tail with a marker, cutting both at UTF-8 boundaries
+func joinTruncated(head, tail []byte, omitted int) string {
+	if omitted <= 0 {
+		return string(head) + string(tail)
+	}
+	for i := 0; i < utf8.UTFMax && len(head) > 0; i++ {
+		if r, size := utf8.DecodeLastRune(head); r != utf8.RuneError || size != 1 {
+			break
+		}
+		head = head[:len(head)-1]
+		omitted++
+	}
+	for i := 0; i < utf8.UTFMax && len(tail) > 0; i++ {
+		if utf8.RuneStart(tail[0]) {
+			break
+		}
+		tail = tail[1:]
+		omitted++
+	}
+	return fmt.Sprintf("%s\n\n... [truncated %d bytes] ...\n\n%s", head, omitted, tail)
 }
 
 // CapturedHeaders returns the headers that were set
@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+func TestResponseCapture_KeepsHeadAndTail(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rc := NewResponseCapture(rec, 10)
+
+	for i := 0; i < 100; i++ {
+		rc.Write([]byte("0123456789"))
+	}
+
+	if rec.Body.Len() != 1000 {
+		t.Fatalf("client should receive the full body, got %d bytes", rec.Body.Len())
+	}
+	body := rc.Body()
+	if !strings.HasPrefix(body, "01234") || !strings.HasSuffix(body, "56789") {
+		t.Fatalf("unexpected captured body %q", body)
+	}
+	if !strings.Contains(body, "[truncated 990 bytes]") {
+		t.Fatalf("missing truncation marker in %q", body)
+	}
+}
+
+func TestResponseCapture_Unlimited(t *testing.T) {
+	rc := NewResponseCapture(httptest.NewRecorder(), 0)
+	rc.Write([]byte(strings.Repeat("a", 5000)))
+	if len(rc.Body()) != 5000 {
+		t.Fatalf("expected full body, got %d bytes", len(rc.Body()))
+	}
+}
+
+func TestTruncateBody_UTF8Boundary(t *testing.T) {
+	s := strings.Repeat("你好", 100)
+	got := truncateBody(s, 11)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncated body is not valid UTF-8: %q", got)
+	}
+	if truncateBody("short", 11) != "short" {
+		t.Fatal("short bodies must be left untouched")
+	}
+}
+
+func TestResponseUsage_TruncatedStream(t *testing.T) {
+	rc := NewResponseCapture(httptest.NewRecorder(), 256)
+	rc.Write([]byte("event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"usage\":{\"input_tokens\":12,\"output_tokens\":1}}}\n\n"))
+	for i := 0; i < 50; i++ {
+		rc.Write([]byte("event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"" + strings.Repeat("x", 64) + "\"}}\n\n"))
+	}
+	rc.Write([]byte("event: message_delta\ndata: {\"type\":\"message_delta\",\"usage\":{\"input_tokens\":12,\"output_tokens\":345}}\n\n"))
+
+	if !strings.Contains(rc.Body(), "[truncated") {
+		t.Fatal("expected a truncated capture")
+	}
+	metrics := responseUsage(&domain.ProxyUpstreamAttempt{}, rc)
+	if metrics == nil || metrics.OutputTokens != 345 {
+		t.Fatalf("metrics = %+v, want output tokens from the final event", metrics)
+	}
+
+	// 适配器上报的用量优先
+	metrics = responseUsage(&domain.ProxyUpstreamAttempt{InputTokenCount: 7, OutputTokenCount: 8}, rc)
+	if metrics == nil || metrics.InputTokens != 7 || metrics.OutputTokens != 8 {
+		t.Fatalf("metrics = %+v, want adapter metrics", metrics)
+	}
+}
@@ -0,0 +1,378 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/tidwall/gjson"
+)
+
+// streamEventKind classifies an SSE event in the client's dialect
+type streamEventKind int
+
+const (
+	streamEventPreamble streamEventKind = iota // message_start / ping / response.created 等，不含实际内容
+	streamEventContent                         // 已产生内容，之后无法再切换路由
+	streamEventTerminal                        // 正常结束事件
+	streamEventError                           // 上游错误事件
+)
+
+// streamGuard sits between the attempts of a streaming request and the client.
+// With failover enabled it stages status, headers and preamble events until the first
+// content event, so a failed attempt can be discarded and the next route spliced in
+// transparently; without failover it commits as soon as the upstream responds.
+// Once committed it forwards only complete events, so a failure can always be closed
+// with a well-formed error event in the client's dialect.
+type streamGuard struct {
+	w          http.ResponseWriter
+	clientType domain.ClientType
+	failover   bool // 提交前暂存前导事件，失败时可切换路由
+
+	header      http.Header
+	status      int
+	wroteHeader bool
+	passthrough bool // 非 SSE 响应（或错误状态码），直接透传
+	committed   bool
+	terminated  bool // 已观察到结束或错误事件
+
+	staged  bytes.Buffer // 提交前暂存的完整事件
+	pending []byte       // 尚未完整的事件
+}
+
+func newStreamGuard(w http.ResponseWriter, clientType domain.ClientType, failover bool) *streamGuard {
+	return &streamGuard{
+		w:          w,
+		clientType: clientType,
+		failover:   failover,
+		header:     make(http.Header),
+	}
+}
+
+// Header returns the staged headers until the stream is committed
+func (g *streamGuard) Header() http.Header {
+	if g.committed {
+		return g.w.Header()
+	}
+	return g.header
+}
+
+// WriteHeader stages the status code. Non-SSE or error responses are passed through.
+func (g *streamGuard) WriteHeader(code int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+	g.status = code
+	if code != http.StatusOK || !strings.Contains(g.header.Get("Content-Type"), "text/event-stream") {
+		g.passthrough = true
+		g.commit()
+		return
+	}
+	if !g.failover {
+		g.commit()
+	}
+}
+
+func (g *streamGuard) Write(b []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
+	if g.passthrough {
+		return g.w.Write(b)
+	}
+
+	g.pending = append(g.pending, b...)
+	for {
+		end := sseEventEnd(g.pending)
+		if end < 0 {
+			break
+		}
+		event := g.pending[:end]
+		kind := classifyStreamEvent(g.clientType, event)
+		if kind == streamEventTerminal || kind == streamEventError {
+			g.terminated = true
+		}
+
+		if g.committed {
+			if _, err := g.w.Write(event); err != nil {
+				return 0, err
+			}
+		} else {
+			g.staged.Write(event)
+			// 错误事件不提交：由本次尝试返回的错误触发切换路由
+			if kind == streamEventContent || kind == streamEventTerminal {
+				if err := g.commit(); err != nil {
+					return 0, err
+				}
+			}
+		}
+		g.pending = g.pending[end:]
+	}
+	return len(b), nil
+}
+
+// Flush implements http.Flusher; staged data is never flushed
+func (g *streamGuard) Flush() {
+	if !g.committed {
+		return
+	}
+	if f, ok := g.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Committed reports whether any bytes reached the client
+func (g *streamGuard) Committed() bool {
+	return g.committed
+}
+
+func (g *streamGuard) commit() error {
+	if g.committed {
+		return nil
+	}
+	g.committed = true
+	dst := g.w.Header()
+	for k, v := range g.header {
+		dst[k] = v
+	}
+	if g.status == 0 {
+		g.status = http.StatusOK
+	}
+	g.w.WriteHeader(g.status)
+	if g.staged.Len() > 0 {
+		if _, err := g.w.Write(g.staged.Bytes()); err != nil {
+			return err
+		}
+		g.staged.Reset()
+	}
+	g.Flush()
+	return nil
+}
+
+// reset discards everything staged by a failed attempt
+func (g *streamGuard) reset() {
+	g.header = make(http.Header)
+	g.status = 0
+	g.wroteHeader = false
+	g.passthrough = false
+	g.terminated = false
+	g.staged.Reset()
+	g.pending = nil
+}
+
+// settle reconciles the result of an attempt with what the client has seen.
+// A stream that ends without a terminal event is reported as a failure; it stays
+// retryable as long as nothing was committed.
+func (g *streamGuard) settle(err error) error {
+	if err != nil {
+		if !g.committed {
+			g.reset()
+		}
+		return err
+	}
+	if g.passthrough || !g.wroteHeader {
+		return nil
+	}
+	if !g.terminated {
+		proxyErr := domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, !g.committed, "upstream stream ended before completion")
+		proxyErr.IsNetworkError = true
+		if !g.committed {
+			g.reset()
+		}
+		return proxyErr
+	}
+	if len(g.pending) > 0 {
+		g.staged.Write(g.pending)
+		g.pending = nil
+	}
+	if !g.committed {
+		return g.commit()
+	}
+	if g.staged.Len() > 0 {
+		_, werr := g.w.Write(g.staged.Bytes())
+		g.staged.Reset()
+		g.Flush()
+		return werr
+	}
+	return nil
+}
+
+// terminate closes a committed stream with an error event in the client's dialect.
+// Any incomplete event of the failed attempt is dropped.
+func (g *streamGuard) terminate(message string) {
+	if !g.committed || g.passthrough || g.terminated {
+		return
+	}
+	g.pending = nil
+	g.terminated = true
+	_, _ = g.w.Write(streamErrorEvent(g.clientType, message))
+	g.Flush()
+}
+
+// sseEventEnd returns the offset just past the first blank line, or -1
+func sseEventEnd(b []byte) int {
+	for i := 0; i < len(b); i++ {
+		if b[i] != '\n' {
+			continue
+		}
+		j := i + 1
+		if j < len(b) && b[j] == '\r' {
+			j++
+		}
+		if j < len(b) && b[j] == '\n' {
+			return j + 1
+		}
+	}
+	return -1
+}
+
+// parseSSEEvent returns the event name and the joined data lines
+func parseSSEEvent(event []byte) (string, string) {
+	var name string
+	var data []string
+	for _, line := range strings.Split(string(event), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+	return name, strings.Join(data, "\n")
+}
+
+func classifyStreamEvent(clientType domain.ClientType, event []byte) streamEventKind {
+	name, data := parseSSEEvent(event)
+	if data == "" {
+		// 注释或心跳
+		return streamEventPreamble
+	}
+	if data == "[DONE]" {
+		return streamEventTerminal
+	}
+	payload := gjson.Parse(data)
+	if payload.Get("error").IsObject() {
+		return streamEventError
+	}
+
+	eventType := payload.Get("type").String()
+	if eventType == "" {
+		eventType = name
+	}
+
+	switch clientType {
+	case domain.ClientTypeClaude:
+		switch eventType {
+		case "message_start", "ping":
+			return streamEventPreamble
+		case "message_stop":
+			return streamEventTerminal
+		case "error":
+			return streamEventError
+		}
+		return streamEventContent
+	case domain.ClientTypeCodex:
+		switch eventType {
+		case "response.created", "response.in_progress":
+			return streamEventPreamble
+		case "response.completed", "response.incomplete":
+			return streamEventTerminal
+		case "response.failed", "error":
+			return streamEventError
+		}
+		return streamEventContent
+	case domain.ClientTypeOpenAI:
+		if isOpenAIPreambleChunk(payload) {
+			return streamEventPreamble
+		}
+		return streamEventContent
+	case domain.ClientTypeGemini:
+		for _, candidate := range payload.Get("candidates").Array() {
+			if candidate.Get("finishReason").String() != "" {
+				return streamEventTerminal
+			}
+		}
+		return streamEventContent
+	}
+	return streamEventContent
+}
+
+// isOpenAIPreambleChunk reports chunks that only carry the assistant role
+func isOpenAIPreambleChunk(payload gjson.Result) bool {
+	choices := payload.Get("choices").Array()
+	if len(choices) == 0 {
+		return !payload.Get("usage").IsObject()
+	}
+	for _, choice := range choices {
+		if choice.Get("finish_reason").String() != "" {
+			return false
+		}
+		preamble := true
+		choice.Get("delta").ForEach(func(key, value gjson.Result) bool {
+			switch key.String() {
+			case "role":
+				return true
+			case "content":
+				if value.Type == gjson.Null || value.String() == "" {
+					return true
+				}
+			}
+			preamble = false
+			return false
+		})
+		if !preamble {
+			return false
+		}
+	}
+	return true
+}
+
+// streamErrorEvent builds a terminal error event in the client's dialect
+func streamErrorEvent(clientType domain.ClientType, message string) []byte {
+	marshal := func(v any) string {
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+
+	switch clientType {
+	case domain.ClientTypeClaude:
+		return []byte("event: error\ndata: " + marshal(map[string]any{
+			"type": "error",
+			"error": map[string]any{
+				"type":    "api_error",
+				"message": message,
+			},
+		}) + "\n\n")
+	case domain.ClientTypeCodex:
+		return []byte("event: response.failed\ndata: " + marshal(map[string]any{
+			"type": "response.failed",
+			"response": map[string]any{
+				"status": "failed",
+				"error": map[string]any{
+					"code":    "server_error",
+					"message": message,
+				},
+			},
+		}) + "\n\n")
+	case domain.ClientTypeGemini:
+		return []byte("data: " + marshal(map[string]any{
+			"error": map[string]any{
+				"code":    http.StatusBadGateway,
+				"message": message,
+				"status":  "UNAVAILABLE",
+			},
+		}) + "\n\n")
+	default:
+		return []byte("data: " + marshal(map[string]any{
+			"error": map[string]any{
+				"message": message,
+				"type":    "server_error",
+				"code":    "upstream_error",
+			},
+		}) + "\n\ndata: [DONE]\n\n")
+	}
+}
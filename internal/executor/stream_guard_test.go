@@ -0,0 +1,140 @@
+package executor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+const (
+	claudeMessageStart = "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\"}}\n\n"
+	claudePing         = "event: ping\ndata: {\"type\":\"ping\"}\n\n"
+	claudeBlockStart   = "event: content_block_start\ndata: {\"type\":\"content_block_start\",\"index\":0}\n\n"
+	claudeMessageStop  = "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n"
+)
+
+func startSSE(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestStreamGuard_RetriesBeforeContent(t *testing.T) {
+	rec := httptest.NewRecorder()
+	g := newStreamGuard(rec, domain.ClientTypeClaude, true)
+
+	// First attempt dies after the preamble
+	startSSE(g)
+	g.Header().Set("X-Upstream", "first")
+	g.Write([]byte(claudeMessageStart + claudePing))
+	upstreamErr := domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "connection reset")
+	if err := g.settle(upstreamErr); err != upstreamErr {
+		t.Fatalf("settle = %v", err)
+	}
+	if g.Committed() || rec.Body.Len() > 0 {
+		t.Fatalf("preamble leaked to client: %q", rec.Body.String())
+	}
+
+	// Second attempt completes, split mid-event
+	startSSE(g)
+	full := claudeMessageStart + claudeBlockStart + claudeMessageStop
+	g.Write([]byte(full[:10]))
+	g.Write([]byte(full[10:]))
+	if err := g.settle(nil); err != nil {
+		t.Fatalf("settle = %v", err)
+	}
+	if rec.Body.String() != full {
+		t.Fatalf("client body = %q", rec.Body.String())
+	}
+	if rec.Header().Get("X-Upstream") != "" {
+		t.Fatal("headers of the failed attempt leaked to client")
+	}
+}
+
+func TestStreamGuard_TruncatedPreambleIsRetryable(t *testing.T) {
+	g := newStreamGuard(httptest.NewRecorder(), domain.ClientTypeClaude, true)
+	startSSE(g)
+	g.Write([]byte(claudeMessageStart))
+
+	var proxyErr *domain.ProxyError
+	if err := g.settle(nil); !errors.As(err, &proxyErr) || !proxyErr.Retryable {
+		t.Fatalf("settle = %v, want retryable error", err)
+	}
+}
+
+func TestStreamGuard_TerminatesInClientDialect(t *testing.T) {
+	tests := []struct {
+		clientType domain.ClientType
+		content    string
+		want       string
+	}{
+		{domain.ClientTypeClaude, claudeBlockStart, "event: error\ndata: {\"error\":{\"message\":\"upstream stream ended before completion"},
+		{domain.ClientTypeOpenAI, "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n", "data: [DONE]\n\n"},
+		{domain.ClientTypeCodex, "event: response.output_text.delta\ndata: {\"type\":\"response.output_text.delta\",\"delta\":\"hi\"}\n\n", "event: response.failed\n"},
+		{domain.ClientTypeGemini, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"hi\"}]}}]}\n\n", "\"status\":\"UNAVAILABLE\""},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.clientType), func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			g := newStreamGuard(rec, tt.clientType, true)
+			startSSE(g)
+			// A complete content event followed by half an event
+			g.Write([]byte(tt.content + "data: {\"partial"))
+			if !g.Committed() {
+				t.Fatal("content event should commit the stream")
+			}
+
+			err := g.settle(nil)
+			var proxyErr *domain.ProxyError
+			if !errors.As(err, &proxyErr) || proxyErr.Retryable {
+				t.Fatalf("settle = %v, want non-retryable error", err)
+			}
+			g.terminate(err.Error())
+
+			body := rec.Body.String()
+			if !strings.HasPrefix(body, tt.content) || strings.Contains(body, "partial") {
+				t.Fatalf("client body = %q", body)
+			}
+			if !strings.Contains(body[len(tt.content):], tt.want) {
+				t.Fatalf("error event = %q, want %q", body[len(tt.content):], tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamGuard_TerminatesWithoutFailover(t *testing.T) {
+	rec := httptest.NewRecorder()
+	g := newStreamGuard(rec, domain.ClientTypeClaude, false)
+	startSSE(g)
+	if !g.Committed() {
+		t.Fatal("stream should commit as soon as the upstream responds without failover")
+	}
+	g.Write([]byte(claudeMessageStart + "data: {\"partial"))
+	if rec.Body.String() != claudeMessageStart {
+		t.Fatalf("client body = %q", rec.Body.String())
+	}
+
+	err := g.settle(domain.NewProxyErrorWithMessage(domain.ErrUpstreamError, true, "connection reset"))
+	g.terminate(err.Error())
+	body := rec.Body.String()
+	if strings.Contains(body, "partial") || !strings.Contains(body[len(claudeMessageStart):], "event: error\n") {
+		t.Fatalf("client body = %q", body)
+	}
+}
+
+func TestStreamGuard_PassesThroughNonSSE(t *testing.T) {
+	rec := httptest.NewRecorder()
+	g := newStreamGuard(rec, domain.ClientTypeGemini, true)
+	g.Header().Set("Content-Type", "application/json")
+	g.WriteHeader(http.StatusOK)
+	g.Write([]byte("[{\"candidates\":[]}"))
+	if err := g.settle(nil); err != nil {
+		t.Fatalf("settle = %v", err)
+	}
+	if rec.Body.String() != "[{\"candidates\":[]}" {
+		t.Fatalf("client body = %q", rec.Body.String())
+	}
+}
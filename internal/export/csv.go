@@ -0,0 +1,72 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type csvWriter struct {
+	columns []Column
+	w       *csv.Writer
+	record  []string
+}
+
+// NewCSVWriter writes a header row followed by one record per row.
+// Timestamps are written as RFC3339 in UTC.
+func NewCSVWriter(w io.Writer, columns []Column) (Writer, error) {
+	cw := &csvWriter{columns: columns, w: csv.NewWriter(w), record: make([]string, len(columns))}
+	for i, c := range columns {
+		cw.record[i] = c.Name
+	}
+	if err := cw.w.Write(cw.record); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (c *csvWriter) Write(row []any) error {
+	if err := checkRow(c.columns, row); err != nil {
+		return err
+	}
+	for i, v := range row {
+		s, err := formatCSVValue(c.columns[i], v)
+		if err != nil {
+			return err
+		}
+		c.record[i] = s
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatCSVValue(col Column, v any) (string, error) {
+	switch col.Kind {
+	case KindInt64:
+		if n, ok := v.(int64); ok {
+			return strconv.FormatInt(n, 10), nil
+		}
+	case KindFloat64:
+		if f, ok := v.(float64); ok {
+			return strconv.FormatFloat(f, 'f', -1, 64), nil
+		}
+	case KindString:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case KindTimestamp:
+		if t, ok := v.(time.Time); ok {
+			if t.IsZero() {
+				return "", nil
+			}
+			return t.UTC().Format(time.RFC3339), nil
+		}
+	}
+	return "", fmt.Errorf("column %s: unexpected value %T", col.Name, v)
+}
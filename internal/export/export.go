@@ -0,0 +1,85 @@
+// Package export writes tabular usage data as CSV or Parquet row by row, so
+// large exports can be streamed to an HTTP response or file without holding the
+// whole result set in memory.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is an export file format
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatParquet Format = "parquet"
+)
+
+// ParseFormat parses a format name, defaulting to CSV when empty
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatParquet:
+		return FormatParquet, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q (use csv or parquet)", s)
+	}
+}
+
+// ContentType returns the HTTP content type of the format
+func (f Format) ContentType() string {
+	if f == FormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension returns the file extension including the dot
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Kind is the value type of a column
+type Kind int
+
+const (
+	KindInt64     Kind = iota // int64
+	KindFloat64               // float64
+	KindString                // string
+	KindTimestamp             // time.Time，按毫秒精度写出
+)
+
+// Column describes one output column
+type Column struct {
+	Name string
+	Kind Kind
+}
+
+// Writer writes rows whose values match the column kinds in order
+type Writer interface {
+	Write(row []any) error
+	// Close flushes buffered rows and writes any trailer; it does not close the underlying writer
+	Close() error
+}
+
+// NewWriter creates a writer of the given format
+func NewWriter(format Format, w io.Writer, columns []Column) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w, columns)
+	case FormatParquet:
+		return NewParquetWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func checkRow(columns []Column, row []any) error {
+	if len(row) != len(columns) {
+		return fmt.Errorf("row has %d values, expected %d", len(row), len(columns))
+	}
+	return nil
+}
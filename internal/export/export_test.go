@@ -0,0 +1,197 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var testColumns = []Column{
+	{Name: "ts", Kind: KindTimestamp},
+	{Name: "name", Kind: KindString},
+	{Name: "count", Kind: KindInt64},
+	{Name: "cost", Kind: KindFloat64},
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf, testColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2026, 9, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	if err := w.Write([]any{ts, "a,b", int64(3), 0.25}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]any{ts, "x", "bad", 0.0}); err == nil {
+		t.Fatal("expected kind mismatch error")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "ts,name,count,cost\n2026-09-01T00:00:00Z,\"a,b\",3,0.25\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected csv:\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+// thriftReader decodes Thrift compact structs into field id -> value maps
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) varint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.zigzag()
+	case thriftBinary:
+		n := int(r.varint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case thriftList:
+		h := r.data[r.pos]
+		r.pos++
+		n, elem := int(h>>4), h&0x0F
+		if n == 15 {
+			n = int(r.varint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.value(elem)
+		}
+		return list
+	case thriftStruct:
+		return r.structValue()
+	}
+	panic("unsupported thrift type")
+}
+
+func (r *thriftReader) structValue() map[int16]any {
+	fields := map[int16]any{}
+	var last int16
+	for {
+		h := r.data[r.pos]
+		r.pos++
+		if h == 0 {
+			return fields
+		}
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(h & 0x0F)
+		last = id
+	}
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatParquet, &buf, testColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.UnixMilli(1_780_000_000_000)
+	rows := parquetRowGroupRows + 10 // 跨越两个 row group
+	for i := 0; i < rows; i++ {
+		if err := w.Write([]any{ts, strings.Repeat("n", i%3), int64(i), float64(i) / 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	if string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footerStart := len(data) - 8 - footerLen
+	meta := (&thriftReader{data: data[:len(data)-8], pos: footerStart}).structValue()
+
+	if meta[3].(int64) != int64(rows) {
+		t.Fatalf("num_rows = %v, want %d", meta[3], rows)
+	}
+	schema := meta[2].([]any)
+	if len(schema) != len(testColumns)+1 || schema[0].(map[int16]any)[5].(int64) != int64(len(testColumns)) {
+		t.Fatalf("unexpected schema %v", schema)
+	}
+	for i, col := range testColumns {
+		if name := schema[i+1].(map[int16]any)[4]; name != col.Name {
+			t.Fatalf("schema[%d] = %v, want %s", i+1, name, col.Name)
+		}
+	}
+	groups := meta[4].([]any)
+	if len(groups) != 2 || groups[1].(map[int16]any)[3].(int64) != 10 {
+		t.Fatalf("unexpected row groups %v", groups)
+	}
+
+	// 读取第二个 row group 的 count 列并校验值
+	chunk := groups[1].(map[int16]any)[1].([]any)[2].(map[int16]any)
+	colMeta := chunk[3].(map[int16]any)
+	if colMeta[4].(int64) != parquetCodecZSTD {
+		t.Fatalf("codec = %v", colMeta[4])
+	}
+	page := &thriftReader{data: data, pos: int(colMeta[9].(int64))}
+	header := page.structValue()
+	compressed := data[page.pos : page.pos+int(header[3].(int64))]
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dec.Close()
+	plain, err := dec.DecodeAll(compressed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plain) != 10*8 {
+		t.Fatalf("page size = %d", len(plain))
+	}
+	for i := 0; i < 10; i++ {
+		if got := int64(binary.LittleEndian.Uint64(plain[i*8:])); got != int64(parquetRowGroupRows+i) {
+			t.Fatalf("value %d = %d", i, got)
+		}
+	}
+
+	// double 列
+	chunk = groups[0].(map[int16]any)[1].([]any)[3].(map[int16]any)
+	page = &thriftReader{data: data, pos: int(chunk[3].(map[int16]any)[9].(int64))}
+	header = page.structValue()
+	plain, err = dec.DecodeAll(data[page.pos:page.pos+int(header[3].(int64))], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := math.Float64frombits(binary.LittleEndian.Uint64(plain[3*8:])); got != 1.5 {
+		t.Fatalf("cost[3] = %v", got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat(""); err != nil || f != FormatCSV {
+		t.Fatalf("default format = %v, %v", f, err)
+	}
+	if f, err := ParseFormat("Parquet"); err != nil || f != FormatParquet {
+		t.Fatalf("parquet format = %v, %v", f, err)
+	}
+	if _, err := ParseFormat("xlsx"); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,278 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Parquet 常量（parquet.thrift）
+const (
+	parquetMagic = "PAR1"
+
+	parquetTypeInt64     = 2
+	parquetTypeDouble    = 5
+	parquetTypeByteArray = 6
+
+	parquetRequired = 0
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedTimestampMillis = 9
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecZSTD = 6
+
+	parquetPageData = 0
+)
+
+// parquetRowGroupRows 每个 row group 缓冲的行数，决定写出时的内存上限
+const parquetRowGroupRows = 50000
+
+type parquetColumnChunk struct {
+	offset           int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+type parquetRowGroup struct {
+	numRows int64
+	chunks  []parquetColumnChunk
+}
+
+// parquetWriter writes a flat Parquet file with required columns, PLAIN encoding
+// and ZSTD compression. Rows are buffered per row group and flushed as one data
+// page per column.
+type parquetWriter struct {
+	w       io.Writer
+	offset  int64
+	columns []Column
+	pages   []bytes.Buffer
+	rows    int64
+	total   int64
+	groups  []parquetRowGroup
+	enc     *zstd.Encoder
+}
+
+// NewParquetWriter writes the file header immediately; the footer is written on Close
+func NewParquetWriter(w io.Writer, columns []Column) (Writer, error) {
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	pw := &parquetWriter{w: w, columns: columns, pages: make([]bytes.Buffer, len(columns)), enc: enc}
+	if err := pw.write([]byte(parquetMagic)); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (p *parquetWriter) write(b []byte) error {
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	return err
+}
+
+func (p *parquetWriter) Write(row []any) error {
+	if err := checkRow(p.columns, row); err != nil {
+		return err
+	}
+	for i, v := range row {
+		if err := p.appendValue(i, v); err != nil {
+			return err
+		}
+	}
+	p.rows++
+	if p.rows >= parquetRowGroupRows {
+		return p.flushRowGroup()
+	}
+	return nil
+}
+
+func (p *parquetWriter) appendValue(i int, v any) error {
+	col := p.columns[i]
+	page := &p.pages[i]
+	var scratch [8]byte
+	switch col.Kind {
+	case KindInt64:
+		n, ok := v.(int64)
+		if !ok {
+			break
+		}
+		binary.LittleEndian.PutUint64(scratch[:], uint64(n))
+		page.Write(scratch[:])
+		return nil
+	case KindFloat64:
+		f, ok := v.(float64)
+		if !ok {
+			break
+		}
+		binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(f))
+		page.Write(scratch[:])
+		return nil
+	case KindString:
+		s, ok := v.(string)
+		if !ok {
+			break
+		}
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(len(s)))
+		page.Write(scratch[:4])
+		page.WriteString(s)
+		return nil
+	case KindTimestamp:
+		t, ok := v.(time.Time)
+		if !ok {
+			break
+		}
+		var ms int64
+		if !t.IsZero() {
+			ms = t.UnixMilli()
+		}
+		binary.LittleEndian.PutUint64(scratch[:], uint64(ms))
+		page.Write(scratch[:])
+		return nil
+	}
+	return fmt.Errorf("column %s: unexpected value %T", col.Name, v)
+}
+
+func (p *parquetWriter) flushRowGroup() error {
+	if p.rows == 0 {
+		return nil
+	}
+	group := parquetRowGroup{numRows: p.rows, chunks: make([]parquetColumnChunk, len(p.columns))}
+	for i := range p.columns {
+		data := p.pages[i].Bytes()
+		compressed := p.enc.EncodeAll(data, nil)
+
+		var header thriftWriter
+		header.begin()
+		header.i32(1, parquetPageData)
+		header.i32(2, int32(len(data)))
+		header.i32(3, int32(len(compressed)))
+		header.structField(5)
+		header.i32(1, int32(p.rows))
+		header.i32(2, parquetEncodingPlain)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+		header.end()
+		header.end()
+
+		chunk := parquetColumnChunk{
+			offset:           p.offset,
+			uncompressedSize: int64(header.buf.Len() + len(data)),
+			compressedSize:   int64(header.buf.Len() + len(compressed)),
+		}
+		if err := p.write(header.buf.Bytes()); err != nil {
+			return err
+		}
+		if err := p.write(compressed); err != nil {
+			return err
+		}
+		group.chunks[i] = chunk
+		p.pages[i].Reset()
+	}
+	p.groups = append(p.groups, group)
+	p.total += p.rows
+	p.rows = 0
+	return nil
+}
+
+func (p *parquetWriter) Close() error {
+	defer p.enc.Close()
+	if err := p.flushRowGroup(); err != nil {
+		return err
+	}
+
+	footer := p.footer()
+	var trailer [4]byte
+	binary.LittleEndian.PutUint32(trailer[:], uint32(len(footer)))
+	if err := p.write(footer); err != nil {
+		return err
+	}
+	if err := p.write(trailer[:]); err != nil {
+		return err
+	}
+	return p.write([]byte(parquetMagic))
+}
+
+// footer encodes FileMetaData
+func (p *parquetWriter) footer() []byte {
+	var t thriftWriter
+	t.begin()
+	t.i32(1, 1)
+
+	t.list(2, thriftStruct, len(p.columns)+1)
+	t.begin()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(p.columns)))
+	t.end()
+	for _, col := range p.columns {
+		physical, converted := parquetColumnType(col.Kind)
+		t.begin()
+		t.i32(1, physical)
+		t.i32(3, parquetRequired)
+		t.binary(4, col.Name)
+		if converted >= 0 {
+			t.i32(6, converted)
+		}
+		t.end()
+	}
+
+	t.i64(3, p.total)
+
+	t.list(4, thriftStruct, len(p.groups))
+	for _, g := range p.groups {
+		var totalSize int64
+		for _, c := range g.chunks {
+			totalSize += c.uncompressedSize
+		}
+		t.begin()
+		t.list(1, thriftStruct, len(g.chunks))
+		for i, c := range g.chunks {
+			physical, _ := parquetColumnType(p.columns[i].Kind)
+			t.begin()
+			t.i64(2, c.offset)
+			t.structField(3)
+			t.i32(1, physical)
+			t.list(2, thriftI32, 2)
+			t.zigzag(parquetEncodingPlain)
+			t.zigzag(parquetEncodingRLE)
+			t.list(3, thriftBinary, 1)
+			t.rawBinary(p.columns[i].Name)
+			t.i32(4, parquetCodecZSTD)
+			t.i64(5, g.numRows)
+			t.i64(6, c.uncompressedSize)
+			t.i64(7, c.compressedSize)
+			t.i64(9, c.offset)
+			t.end()
+			t.end()
+		}
+		t.i64(2, totalSize)
+		t.i64(3, g.numRows)
+		t.end()
+	}
+
+	t.binary(6, "maxx")
+	t.end()
+	return t.buf.Bytes()
+}
+
+// parquetColumnType returns the physical type and converted type (-1 for none)
+func parquetColumnType(kind Kind) (int32, int32) {
+	switch kind {
+	case KindFloat64:
+		return parquetTypeDouble, -1
+	case KindString:
+		return parquetTypeByteArray, parquetConvertedUTF8
+	case KindTimestamp:
+		return parquetTypeInt64, parquetConvertedTimestampMillis
+	default:
+		return parquetTypeInt64, -1
+	}
+}
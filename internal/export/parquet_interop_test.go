@@ -0,0 +1,76 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// TestParquetWriterInterop reads the hand-written encoding back with parquet-go,
+// so the file layout is checked against an independent implementation
+func TestParquetWriterInterop(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatParquet, &buf, testColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.UnixMilli(1_780_000_000_000)
+	rows := parquetRowGroupRows + 10 // 跨越两个 row group
+	for i := 0; i < rows; i++ {
+		if err := w.Write([]any{ts.Add(time.Duration(i) * time.Millisecond), strings.Repeat("n", i%3), int64(i), float64(i) / 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("parquet-go cannot open file: %v", err)
+	}
+	if f.NumRows() != int64(rows) || len(f.RowGroups()) != 2 {
+		t.Fatalf("num rows = %d, row groups = %d", f.NumRows(), len(f.RowGroups()))
+	}
+	for i, col := range testColumns {
+		field := f.Schema().Fields()[i]
+		if field.Name() != col.Name || !field.Required() {
+			t.Fatalf("field %d = %s (required=%v), want %s", i, field.Name(), field.Required(), col.Name)
+		}
+	}
+
+	type record struct {
+		TS    int64   `parquet:"ts"`
+		Name  string  `parquet:"name"`
+		Count int64   `parquet:"count"`
+		Cost  float64 `parquet:"cost"`
+	}
+	r := parquet.NewGenericReader[record](f)
+	defer r.Close()
+	got := make([]record, 0, rows)
+	batch := make([]record, 1000)
+	for {
+		n, err := r.Read(batch)
+		got = append(got, batch[:n]...)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read rows: %v", err)
+		}
+	}
+	if len(got) != rows {
+		t.Fatalf("read %d rows, want %d", len(got), rows)
+	}
+	for i, rec := range got {
+		want := record{TS: ts.UnixMilli() + int64(i), Name: strings.Repeat("n", i%3), Count: int64(i), Cost: float64(i) / 2}
+		if rec != want {
+			t.Fatalf("row %d = %+v, want %+v", i, rec, want)
+		}
+	}
+}
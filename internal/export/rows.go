@@ -0,0 +1,198 @@
+package export
+
+import (
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+// Names resolves IDs to display names so exported files are readable without joins
+type Names struct {
+	Providers map[uint64]string
+	Projects  map[uint64]string
+	APITokens map[uint64]string
+}
+
+func lookup(m map[uint64]string, id uint64) string {
+	if m == nil {
+		return ""
+	}
+	return m[id]
+}
+
+// costUSD converts nanoUSD to USD
+func costUSD(nano uint64) float64 {
+	return float64(nano) / 1e9
+}
+
+// UsageStatsColumns are the columns of a usage stats export, matching the UsageStatsFilter dimensions
+var UsageStatsColumns = []Column{
+	{Name: "time_bucket", Kind: KindTimestamp},
+	{Name: "granularity", Kind: KindString},
+	{Name: "route_id", Kind: KindInt64},
+	{Name: "provider_id", Kind: KindInt64},
+	{Name: "provider_name", Kind: KindString},
+	{Name: "project_id", Kind: KindInt64},
+	{Name: "project_name", Kind: KindString},
+	{Name: "api_token_id", Kind: KindInt64},
+	{Name: "api_token_name", Kind: KindString},
+	{Name: "client_type", Kind: KindString},
+	{Name: "model", Kind: KindString},
+	{Name: "total_requests", Kind: KindInt64},
+	{Name: "successful_requests", Kind: KindInt64},
+	{Name: "failed_requests", Kind: KindInt64},
+	{Name: "total_duration_ms", Kind: KindInt64},
+	{Name: "total_ttft_ms", Kind: KindInt64},
+	{Name: "input_tokens", Kind: KindInt64},
+	{Name: "output_tokens", Kind: KindInt64},
+	{Name: "cache_read_tokens", Kind: KindInt64},
+	{Name: "cache_write_tokens", Kind: KindInt64},
+	{Name: "cost_nano_usd", Kind: KindInt64},
+	{Name: "cost_usd", Kind: KindFloat64},
+	{Name: "upstream_cost_nano_usd", Kind: KindInt64},
+	{Name: "billed_cost_nano_usd", Kind: KindInt64},
+	{Name: "billed_cost_usd", Kind: KindFloat64},
+}
+
+// UsageStatsRow converts a usage stats record to a row of UsageStatsColumns
+func UsageStatsRow(s *domain.UsageStats, names *Names) []any {
+	if names == nil {
+		names = &Names{}
+	}
+	return []any{
+		s.TimeBucket,
+		string(s.Granularity),
+		int64(s.RouteID),
+		int64(s.ProviderID),
+		lookup(names.Providers, s.ProviderID),
+		int64(s.ProjectID),
+		lookup(names.Projects, s.ProjectID),
+		int64(s.APITokenID),
+		lookup(names.APITokens, s.APITokenID),
+		s.ClientType,
+		s.Model,
+		int64(s.TotalRequests),
+		int64(s.SuccessfulRequests),
+		int64(s.FailedRequests),
+		int64(s.TotalDurationMs),
+		int64(s.TotalTTFTMs),
+		int64(s.InputTokens),
+		int64(s.OutputTokens),
+		int64(s.CacheRead),
+		int64(s.CacheWrite),
+		int64(s.Cost),
+		costUSD(s.Cost),
+		int64(s.UpstreamCost),
+		int64(s.BilledCost),
+		costUSD(s.BilledCost),
+	}
+}
+
+// ProxyRequestColumns are the columns of a raw request export (bodies are never exported)
+var ProxyRequestColumns = []Column{
+	{Name: "id", Kind: KindInt64},
+	{Name: "request_id", Kind: KindString},
+	{Name: "session_id", Kind: KindString},
+	{Name: "start_time", Kind: KindTimestamp},
+	{Name: "end_time", Kind: KindTimestamp},
+	{Name: "duration_ms", Kind: KindInt64},
+	{Name: "ttft_ms", Kind: KindInt64},
+	{Name: "client_type", Kind: KindString},
+	{Name: "request_model", Kind: KindString},
+	{Name: "response_model", Kind: KindString},
+	{Name: "is_stream", Kind: KindInt64},
+	{Name: "status", Kind: KindString},
+	{Name: "status_code", Kind: KindInt64},
+	{Name: "error", Kind: KindString},
+	{Name: "attempt_count", Kind: KindInt64},
+	{Name: "route_id", Kind: KindInt64},
+	{Name: "provider_id", Kind: KindInt64},
+	{Name: "provider_name", Kind: KindString},
+	{Name: "project_id", Kind: KindInt64},
+	{Name: "project_name", Kind: KindString},
+	{Name: "api_token_id", Kind: KindInt64},
+	{Name: "api_token_name", Kind: KindString},
+	{Name: "input_tokens", Kind: KindInt64},
+	{Name: "output_tokens", Kind: KindInt64},
+	{Name: "cache_read_tokens", Kind: KindInt64},
+	{Name: "cache_write_tokens", Kind: KindInt64},
+	{Name: "cost_nano_usd", Kind: KindInt64},
+	{Name: "cost_usd", Kind: KindFloat64},
+	{Name: "upstream_cost_nano_usd", Kind: KindInt64},
+	{Name: "billed_cost_nano_usd", Kind: KindInt64},
+	{Name: "billed_cost_usd", Kind: KindFloat64},
+}
+
+// ProxyRequestRow converts a proxy request to a row of ProxyRequestColumns
+func ProxyRequestRow(r *domain.ProxyRequest, names *Names) []any {
+	if names == nil {
+		names = &Names{}
+	}
+	var stream int64
+	if r.IsStream {
+		stream = 1
+	}
+	return []any{
+		int64(r.ID),
+		r.RequestID,
+		r.SessionID,
+		r.StartTime,
+		r.EndTime,
+		r.Duration.Milliseconds(),
+		r.TTFT.Milliseconds(),
+		string(r.ClientType),
+		r.RequestModel,
+		r.ResponseModel,
+		stream,
+		r.Status,
+		int64(r.StatusCode),
+		r.Error,
+		int64(r.ProxyUpstreamAttemptCount),
+		int64(r.RouteID),
+		int64(r.ProviderID),
+		lookup(names.Providers, r.ProviderID),
+		int64(r.ProjectID),
+		lookup(names.Projects, r.ProjectID),
+		int64(r.APITokenID),
+		lookup(names.APITokens, r.APITokenID),
+		int64(r.InputTokenCount),
+		int64(r.OutputTokenCount),
+		int64(r.CacheReadCount),
+		int64(r.CacheWriteCount),
+		int64(r.Cost),
+		costUSD(r.Cost),
+		int64(r.UpstreamCost),
+		int64(r.BilledCost),
+		costUSD(r.BilledCost),
+	}
+}
+
+// LoadNames builds the name lookup tables from the repositories
+func LoadNames(providers repository.ProviderRepository, projects repository.ProjectRepository, apiTokens repository.APITokenRepository) (*Names, error) {
+	names := &Names{
+		Providers: map[uint64]string{},
+		Projects:  map[uint64]string{},
+		APITokens: map[uint64]string{},
+	}
+	providerList, err := providers.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range providerList {
+		names.Providers[p.ID] = p.Name
+	}
+	projectList, err := projects.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projectList {
+		names.Projects[p.ID] = p.Name
+	}
+	tokenList, err := apiTokens.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tokenList {
+		names.APITokens[t.ID] = t.Name
+	}
+	return names, nil
+}
@@ -19,8 +19,12 @@ const (
 	KeyIsStream            = "is_stream"
 	KeyAPITokenID          = "api_token_id"
 	KeyAPITokenDevMode     = "api_token_dev_mode"
+	KeyAPITokenProviders   = "api_token_providers"
+	KeyAPITokenModels      = "api_token_models"
+	KeyAPITokenPriority    = "api_token_priority"
 	KeyProxyRequest        = "proxy_request"
 	KeyUpstreamAttempt     = "upstream_attempt"
 	KeyEventChan           = "event_chan"
 	KeyBroadcaster         = "broadcaster"
+	KeyStreamTerminated    = "stream_terminated"
 )
@@ -2,15 +2,23 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/awsl-project/maxx/internal/cooldown"
 	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/export"
 	"github.com/awsl-project/maxx/internal/pricing"
+	"github.com/awsl-project/maxx/internal/report"
 	"github.com/awsl-project/maxx/internal/repository"
 	"github.com/awsl-project/maxx/internal/service"
 )
@@ -33,6 +41,24 @@ func NewAdminHandler(svc *service.AdminService, backupSvc *service.BackupService
 	}
 }
 
+// withActor returns a per-request copy of the handler whose services record the request's actor
+func (h *AdminHandler) withActor(r *http.Request) *AdminHandler {
+	actor := AdminActor(r)
+	if actor == "" {
+		actor = service.AuditActorAnonymous
+	}
+	clientIP := trustedClientIP(r)
+
+	cp := *h
+	if h.svc != nil {
+		cp.svc = h.svc.WithActor(actor, clientIP)
+	}
+	if h.backupSvc != nil {
+		cp.backupSvc = h.backupSvc.WithActor(actor, clientIP)
+	}
+	return &cp
+}
+
 // SetRestartFunc sets the restart callback for admin restart endpoint.
 func (h *AdminHandler) SetRestartFunc(fn func() error) {
 	h.restartFn = fn
@@ -49,6 +75,16 @@ func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Bind the authenticated actor so service mutations are attributed in the audit log
+	h = h.withActor(r)
+
+	// Viewers never see provider credentials, API token values or secret settings
+	if AdminRole(r) != AdminRoleAdmin {
+		mw := newMaskingResponseWriter(w)
+		defer mw.flush()
+		w = mw
+	}
+
 	resource := parts[1]
 	var id uint64
 	if len(parts) > 2 && parts[2] != "" {
@@ -102,6 +138,24 @@ func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handlePricing(w, r)
 	case "model-prices":
 		h.handleModelPrices(w, r, id)
+	case "audit-logs":
+		h.handleAuditLogs(w, r)
+	case "reports":
+		h.handleReports(w, r, parts)
+	case "billing-rules":
+		h.handleBillingRules(w, r, id)
+	case "cooldown-policies":
+		h.handleCooldownPolicies(w, r, id)
+	case "model-fallbacks":
+		h.handleModelFallbacks(w, r, id)
+	case "anomaly-alerts":
+		h.handleAnomalyAlerts(w, r)
+	case "queue-stats":
+		h.handleQueueStats(w, r)
+	case "local":
+		http.StripPrefix("/admin", NewLocalHandler()).ServeHTTP(w, r)
+	case "provider-health":
+		h.handleProviderHealth(w, r, id)
 	default:
 		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
 	}
@@ -165,7 +219,11 @@ func (h *AdminHandler) handleProviders(w http.ResponseWriter, r *http.Request, i
 			return
 		}
 		if err := h.svc.CreateProvider(&provider); err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			status := http.StatusInternalServerError
+			if errors.Is(err, domain.ErrInvalidInput) {
+				status = http.StatusBadRequest
+			}
+			writeJSON(w, status, map[string]string{"error": err.Error()})
 			return
 		}
 		writeJSON(w, http.StatusCreated, provider)
@@ -191,7 +249,11 @@ func (h *AdminHandler) handleProviders(w http.ResponseWriter, r *http.Request, i
 		provider.ID = existing.ID
 		provider.CreatedAt = existing.CreatedAt
 		if err := h.svc.UpdateProvider(&provider); err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			status := http.StatusInternalServerError
+			if errors.Is(err, domain.ErrInvalidInput) {
+				status = http.StatusBadRequest
+			}
+			writeJSON(w, status, map[string]string{"error": err.Error()})
 			return
 		}
 		writeJSON(w, http.StatusOK, provider)
@@ -287,11 +349,14 @@ func (h *AdminHandler) handleRoutes(w http.ResponseWriter, r *http.Request, id u
 			return
 		}
 		// Get existing route first for merge update
-		existing, err := h.svc.GetRoute(id)
+		current, err := h.svc.GetRoute(id)
 		if err != nil {
 			writeJSON(w, http.StatusNotFound, map[string]string{"error": "route not found"})
 			return
 		}
+		// Work on a copy so the cached route is not modified before the update is persisted
+		route := *current
+		existing := &route
 		// Decode partial update into a map to detect which fields were sent
 		var updates map[string]interface{}
 		if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
@@ -406,6 +471,10 @@ func (h *AdminHandler) handleProjects(w http.ResponseWriter, r *http.Request, id
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 			return
 		}
+		if !project.Priority.Valid() {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid priority"})
+			return
+		}
 		if err := h.svc.CreateProject(&project); err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
@@ -422,11 +491,17 @@ func (h *AdminHandler) handleProjects(w http.ResponseWriter, r *http.Request, id
 			writeJSON(w, http.StatusNotFound, map[string]string{"error": "project not found"})
 			return
 		}
-		var project domain.Project
+		// Decode onto a copy of the existing project so fields omitted by the client (e.g. priority) are kept
+		project := *existing
+		project.EnabledCustomRoutes = slices.Clone(existing.EnabledCustomRoutes)
 		if err := json.NewDecoder(r.Body).Decode(&project); err != nil {
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 			return
 		}
+		if !project.Priority.Valid() {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid priority"})
+			return
+		}
 		// Validate required fields
 		if project.Name == "" {
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
@@ -480,8 +555,28 @@ func (h *AdminHandler) handleProjectBySlug(w http.ResponseWriter, r *http.Reques
 }
 
 // Session handlers
-// Routes: /admin/sessions, /admin/sessions/{sessionID}/project, /admin/sessions/{sessionID}/reject
+// Routes: /admin/sessions, /admin/sessions/stats, /admin/sessions/{sessionID}/project,
+// /admin/sessions/{sessionID}/reject, /admin/sessions/{sessionID}/approve,
+// /admin/sessions/{sessionID}/stats, /admin/sessions/{sessionID}/timeline
 func (h *AdminHandler) handleSessions(w http.ResponseWriter, r *http.Request, parts []string) {
+	// Aggregates of all sessions: /admin/sessions/stats
+	if len(parts) == 3 && parts[2] == "stats" {
+		h.handleSessionStatsList(w, r)
+		return
+	}
+
+	// Check for sub-resource: /admin/sessions/{sessionID}/stats
+	if len(parts) > 3 && parts[3] == "stats" {
+		h.handleSessionStats(w, r, parts[2])
+		return
+	}
+
+	// Check for sub-resource: /admin/sessions/{sessionID}/timeline
+	if len(parts) > 3 && parts[3] == "timeline" {
+		h.handleSessionTimeline(w, r, parts[2])
+		return
+	}
+
 	// Check for sub-resource: /admin/sessions/{sessionID}/project
 	if len(parts) > 3 && parts[3] == "project" {
 		h.handleSessionProject(w, r, parts[2])
@@ -494,6 +589,12 @@ func (h *AdminHandler) handleSessions(w http.ResponseWriter, r *http.Request, pa
 		return
 	}
 
+	// Check for sub-resource: /admin/sessions/{sessionID}/approve
+	if len(parts) > 3 && parts[3] == "approve" {
+		h.handleSessionApprove(w, r, parts[2])
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		sessions, err := h.svc.GetSessions()
@@ -557,6 +658,133 @@ func (h *AdminHandler) handleSessionReject(w http.ResponseWriter, r *http.Reques
 	writeJSON(w, http.StatusOK, session)
 }
 
+// handleSessionApprove handles POST /admin/sessions/{sessionID}/approve
+// 允许被循环检测拦截的会话继续
+func (h *AdminHandler) handleSessionApprove(w http.ResponseWriter, r *http.Request, sessionID string) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if sessionID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "session ID required"})
+		return
+	}
+
+	session, err := h.svc.ApproveSessionLoop(sessionID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, session)
+}
+
+// handleSessionStatsList handles GET /admin/sessions/stats
+// Query: limit, offset, projectId, start, end (RFC3339, 按最后活跃时间过滤)
+func (h *AdminHandler) handleSessionStatsList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	query := r.URL.Query()
+	limit := 50
+	if l := query.Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	offset := 0
+	if o := query.Get("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed > 0 {
+			offset = parsed
+		}
+	}
+
+	filter := &repository.SessionStatsFilter{}
+	if v := query.Get("projectId"); v != "" {
+		projectID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid projectId"})
+			return
+		}
+		filter.ProjectID = &projectID
+	}
+	if v := query.Get("start"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid start, use RFC3339"})
+			return
+		}
+		filter.StartTime = &t
+	}
+	if v := query.Get("end"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid end, use RFC3339"})
+			return
+		}
+		filter.EndTime = &t
+	}
+
+	stats, err := h.svc.GetSessionStatsList(filter, limit, offset)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// handleSessionStats handles GET /admin/sessions/{sessionID}/stats
+func (h *AdminHandler) handleSessionStats(w http.ResponseWriter, r *http.Request, sessionID string) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	stats, err := h.svc.GetSessionStats(sessionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "session not found"})
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// handleSessionTimeline handles GET /admin/sessions/{sessionID}/timeline
+// Query: after (上一页的 lastId), limit (默认 100，最大 500)
+func (h *AdminHandler) handleSessionTimeline(w http.ResponseWriter, r *http.Request, sessionID string) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	limit := 100
+	var after uint64
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	if a := r.URL.Query().Get("after"); a != "" {
+		after, _ = strconv.ParseUint(a, 10, 64)
+	}
+
+	timeline, err := h.svc.GetSessionTimeline(sessionID, after, limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, timeline)
+}
+
 // RetryConfig handlers
 func (h *AdminHandler) handleRetryConfigs(w http.ResponseWriter, r *http.Request, id uint64) {
 	switch r.Method {
@@ -702,6 +930,12 @@ func (h *AdminHandler) handleProxyRequests(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Check for export endpoint: /admin/requests/export
+	if len(parts) > 2 && parts[2] == "export" {
+		h.handleProxyRequestsExport(w, r)
+		return
+	}
+
 	// Check for active endpoint: /admin/requests/active
 	if len(parts) > 2 && parts[2] == "active" {
 		h.handleActiveProxyRequests(w, r)
@@ -743,26 +977,10 @@ func (h *AdminHandler) handleProxyRequests(w http.ResponseWriter, r *http.Reques
 			}
 
 			// 构建过滤条件
-			var filter *repository.ProxyRequestFilter
-			providerIDStr := r.URL.Query().Get("providerId")
-			statusStr := r.URL.Query().Get("status")
-			apiTokenIDStr := r.URL.Query().Get("apiTokenId")
-
-			if providerIDStr != "" || statusStr != "" || apiTokenIDStr != "" {
-				filter = &repository.ProxyRequestFilter{}
-				if providerIDStr != "" {
-					if providerID, err := strconv.ParseUint(providerIDStr, 10, 64); err == nil {
-						filter.ProviderID = &providerID
-					}
-				}
-				if statusStr != "" {
-					filter.Status = &statusStr
-				}
-				if apiTokenIDStr != "" {
-					if apiTokenID, err := strconv.ParseUint(apiTokenIDStr, 10, 64); err == nil {
-						filter.APITokenID = &apiTokenID
-					}
-				}
+			filter, err := parseProxyRequestFilter(r.URL.Query())
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
 			}
 
 			result, err := h.svc.GetProxyRequestsCursor(limit, before, after, filter)
@@ -785,32 +1003,10 @@ func (h *AdminHandler) handleProxyRequestsCount(w http.ResponseWriter, r *http.R
 	}
 
 	// 解析过滤参数
-	var filter *repository.ProxyRequestFilter
-	providerIDStr := r.URL.Query().Get("providerId")
-	statusStr := r.URL.Query().Get("status")
-	apiTokenIDStr := r.URL.Query().Get("apiTokenId")
-
-	if providerIDStr != "" || statusStr != "" || apiTokenIDStr != "" {
-		filter = &repository.ProxyRequestFilter{}
-		if providerIDStr != "" {
-			providerID, err := strconv.ParseUint(providerIDStr, 10, 64)
-			if err != nil {
-				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid providerId"})
-				return
-			}
-			filter.ProviderID = &providerID
-		}
-		if statusStr != "" {
-			filter.Status = &statusStr
-		}
-		if apiTokenIDStr != "" {
-			apiTokenID, err := strconv.ParseUint(apiTokenIDStr, 10, 64)
-			if err != nil {
-				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid apiTokenId"})
-				return
-			}
-			filter.APITokenID = &apiTokenID
-		}
+	filter, err := parseProxyRequestFilter(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
 	}
 
 	count, err := h.svc.GetProxyRequestsCountWithFilter(filter)
@@ -821,6 +1017,91 @@ func (h *AdminHandler) handleProxyRequestsCount(w http.ResponseWriter, r *http.R
 	writeJSON(w, http.StatusOK, count)
 }
 
+// handleProxyRequestsExport handles GET /admin/requests/export?format=csv|parquet
+// 过滤参数与请求列表一致，不导出请求/响应详情
+func (h *AdminHandler) handleProxyRequestsExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	filter, err := parseProxyRequestFilter(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	h.writeExport(w, r.URL.Query().Get("format"), "requests", export.ProxyRequestColumns, func(ew export.Writer) error {
+		return h.svc.ExportProxyRequests(ew, filter)
+	})
+}
+
+// parseProxyRequestFilter 解析请求列表/计数共用的过滤参数，没有任何条件时返回 nil
+// Query: providerId, status, apiTokenId, start, end (RFC3339), model, projectId, sessionId,
+// clientType, statusCode, minCost (nanoUSD), minDurationMs, minTokens, error, q (全文检索)
+func parseProxyRequestFilter(query url.Values) (*repository.ProxyRequestFilter, error) {
+	filter := &repository.ProxyRequestFilter{}
+	var err error
+
+	parseUint := func(name string) *uint64 {
+		v := query.Get(name)
+		if v == "" || err != nil {
+			return nil
+		}
+		n, parseErr := strconv.ParseUint(v, 10, 64)
+		if parseErr != nil {
+			err = fmt.Errorf("invalid %s", name)
+			return nil
+		}
+		return &n
+	}
+	parseTime := func(name string) *time.Time {
+		v := query.Get(name)
+		if v == "" || err != nil {
+			return nil
+		}
+		t, parseErr := time.Parse(time.RFC3339, v)
+		if parseErr != nil {
+			err = fmt.Errorf("invalid %s, use RFC3339", name)
+			return nil
+		}
+		return &t
+	}
+	parseString := func(name string) *string {
+		if v := strings.TrimSpace(query.Get(name)); v != "" {
+			return &v
+		}
+		return nil
+	}
+
+	filter.ProviderID = parseUint("providerId")
+	filter.Status = parseString("status")
+	filter.APITokenID = parseUint("apiTokenId")
+	filter.StartTime = parseTime("start")
+	filter.EndTime = parseTime("end")
+	filter.Model = parseString("model")
+	filter.ProjectID = parseUint("projectId")
+	filter.SessionID = parseString("sessionId")
+	filter.ClientType = parseString("clientType")
+	filter.MinCost = parseUint("minCost")
+	filter.MinTokens = parseUint("minTokens")
+	filter.ErrorContains = parseString("error")
+	filter.Query = parseString("q")
+	if v := parseUint("statusCode"); v != nil {
+		code := int(*v)
+		filter.StatusCode = &code
+	}
+	if v := parseUint("minDurationMs"); v != nil {
+		ms := int64(*v)
+		filter.MinDurationMs = &ms
+	}
+	if err != nil {
+		return nil, err
+	}
+	if filter.IsEmpty() {
+		return nil, nil
+	}
+	return filter, nil
+}
+
 // ActiveProxyRequests handler - returns all requests with PENDING or IN_PROGRESS status
 func (h *AdminHandler) handleActiveProxyRequests(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -1001,7 +1282,7 @@ func (h *AdminHandler) handleCooldowns(w http.ResponseWriter, r *http.Request, p
 		// Build response using GetCooldownInfo to include reason
 		var result []*cooldown.CooldownInfo
 		for key := range cooldowns {
-			info := cm.GetCooldownInfo(key.ProviderID, key.ClientType, providerNames[key.ProviderID])
+			info := cm.GetCooldownInfo(key, providerNames[key.ProviderID])
 			if info != nil {
 				result = append(result, info)
 			}
@@ -1031,7 +1312,7 @@ func (h *AdminHandler) handleCooldowns(w http.ResponseWriter, r *http.Request, p
 			return
 		}
 		log.Printf("[Cooldown] PUT /cooldowns/%d: setting cooldown until %v", providerID, until)
-		cm.SetCooldownUntil(providerID, body.ClientType, until)
+		h.svc.SetProviderCooldown(providerID, body.ClientType, until)
 		log.Printf("[Cooldown] PUT /cooldowns/%d: cooldown set successfully", providerID)
 		writeJSON(w, http.StatusOK, map[string]string{"message": "cooldown set"})
 
@@ -1041,7 +1322,7 @@ func (h *AdminHandler) handleCooldowns(w http.ResponseWriter, r *http.Request, p
 			return
 		}
 		// Clear all cooldowns for this provider (both global and client-type-specific)
-		cm.ClearCooldown(providerID, "")
+		h.svc.ClearProviderCooldown(providerID)
 		writeJSON(w, http.StatusOK, map[string]string{"message": "cooldown cleared"})
 
 	default:
@@ -1050,6 +1331,18 @@ func (h *AdminHandler) handleCooldowns(w http.ResponseWriter, r *http.Request, p
 }
 
 // API Token handlers
+// apiTokenErrorStatus maps API token service errors to HTTP status codes
+func apiTokenErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func (h *AdminHandler) handleAPITokens(w http.ResponseWriter, r *http.Request, id uint64) {
 	switch r.Method {
 	case http.MethodGet:
@@ -1074,6 +1367,9 @@ func (h *AdminHandler) handleAPITokens(w http.ResponseWriter, r *http.Request, i
 			Description string  `json:"description"`
 			ProjectID   uint64  `json:"projectID"`
 			ExpiresAt   *string `json:"expiresAt"`
+
+			Priority domain.PriorityClass `json:"priority"`
+			domain.APITokenScope
 		}
 		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
@@ -1092,9 +1388,9 @@ func (h *AdminHandler) handleAPITokens(w http.ResponseWriter, r *http.Request, i
 			}
 			expiresAt = &t
 		}
-		result, err := h.svc.CreateAPIToken(body.Name, body.Description, body.ProjectID, expiresAt)
+		result, err := h.svc.CreateAPIToken(body.Name, body.Description, body.ProjectID, expiresAt, body.Priority, body.APITokenScope)
 		if err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			writeJSON(w, apiTokenErrorStatus(err), map[string]string{"error": err.Error()})
 			return
 		}
 		writeJSON(w, http.StatusCreated, result)
@@ -1103,11 +1399,14 @@ func (h *AdminHandler) handleAPITokens(w http.ResponseWriter, r *http.Request, i
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id required"})
 			return
 		}
-		existing, err := h.svc.GetAPIToken(id)
+		current, err := h.svc.GetAPIToken(id)
 		if err != nil {
 			writeJSON(w, http.StatusNotFound, map[string]string{"error": "token not found"})
 			return
 		}
+		// Work on a copy so the cached token is not modified before the update is persisted
+		token := *current
+		existing := &token
 		var body struct {
 			Name        *string `json:"name"`
 			Description *string `json:"description"`
@@ -1115,6 +1414,14 @@ func (h *AdminHandler) handleAPITokens(w http.ResponseWriter, r *http.Request, i
 			IsEnabled   *bool   `json:"isEnabled"`
 			DevMode     *bool   `json:"devMode"`
 			ExpiresAt   *string `json:"expiresAt"`
+
+			Priority *domain.PriorityClass `json:"priority"`
+
+			AllowedClientTypes *[]domain.ClientType `json:"allowedClientTypes"`
+			AllowedModels      *[]string            `json:"allowedModels"`
+			AllowedProviderIDs *[]uint64            `json:"allowedProviderIDs"`
+			AllowedIPs         *[]string            `json:"allowedIPs"`
+			ProjectAdmin       *bool                `json:"projectAdmin"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
@@ -1139,6 +1446,9 @@ func (h *AdminHandler) handleAPITokens(w http.ResponseWriter, r *http.Request, i
 		if body.DevMode != nil {
 			existing.DevMode = *body.DevMode
 		}
+		if body.Priority != nil {
+			existing.Priority = *body.Priority
+		}
 		if body.ExpiresAt != nil {
 			if *body.ExpiresAt == "" {
 				existing.ExpiresAt = nil
@@ -1151,8 +1461,23 @@ func (h *AdminHandler) handleAPITokens(w http.ResponseWriter, r *http.Request, i
 				existing.ExpiresAt = &t
 			}
 		}
+		if body.AllowedClientTypes != nil {
+			existing.AllowedClientTypes = *body.AllowedClientTypes
+		}
+		if body.AllowedModels != nil {
+			existing.AllowedModels = *body.AllowedModels
+		}
+		if body.AllowedProviderIDs != nil {
+			existing.AllowedProviderIDs = *body.AllowedProviderIDs
+		}
+		if body.AllowedIPs != nil {
+			existing.AllowedIPs = *body.AllowedIPs
+		}
+		if body.ProjectAdmin != nil {
+			existing.ProjectAdmin = *body.ProjectAdmin
+		}
 		if err := h.svc.UpdateAPIToken(existing); err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			writeJSON(w, apiTokenErrorStatus(err), map[string]string{"error": err.Error()})
 			return
 		}
 		writeJSON(w, http.StatusOK, existing)
@@ -1226,11 +1551,14 @@ func (h *AdminHandler) handleModelMappings(w http.ResponseWriter, r *http.Reques
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id required"})
 			return
 		}
-		existing, err := h.svc.GetModelMapping(id)
+		current, err := h.svc.GetModelMapping(id)
 		if err != nil {
 			writeJSON(w, http.StatusNotFound, map[string]string{"error": "mapping not found"})
 			return
 		}
+		// Work on a copy so the cached mapping is not modified before the update is persisted
+		mapping := *current
+		existing := &mapping
 		var body struct {
 			ClientType *string `json:"clientType"`
 			Pattern    *string `json:"pattern"`
@@ -1322,14 +1650,92 @@ func (h *AdminHandler) handleUsageStats(w http.ResponseWriter, r *http.Request)
 		h.handleRecalculateCosts(w, r)
 		return
 	}
+	// Check for wasted-spend endpoint: /admin/usage-stats/wasted-spend
+	if strings.HasSuffix(strings.TrimSuffix(path, "/"), "/wasted-spend") {
+		h.handleWastedSpend(w, r)
+		return
+	}
+	// Check for export endpoint: /admin/usage-stats/export
+	if strings.HasSuffix(strings.TrimSuffix(path, "/"), "/export") {
+		h.handleUsageStatsExport(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	stats, err := h.svc.GetUsageStats(parseUsageStatsFilter(r.URL.Query()))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
 
+// handleWastedSpend handles GET /admin/usage-stats/wasted-spend
+// 支持 start / end / providerId / projectId 过滤参数（与 /admin/usage-stats 相同）
+func (h *AdminHandler) handleWastedSpend(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
 		return
 	}
 
-	// Parse query parameters for filtering
+	filter := parseUsageStatsFilter(r.URL.Query())
+	items, err := h.svc.GetWastedSpend(repository.WastedSpendFilter{
+		StartTime:  filter.StartTime,
+		EndTime:    filter.EndTime,
+		ProviderID: filter.ProviderID,
+		ProjectID:  filter.ProjectID,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// handleUsageStatsExport handles GET /admin/usage-stats/export?format=csv|parquet
+// 使用与 /admin/usage-stats 相同的过滤参数，流式写出预聚合数据
+func (h *AdminHandler) handleUsageStatsExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
 	query := r.URL.Query()
+	filter := parseUsageStatsFilter(query)
+	h.writeExport(w, query.Get("format"), "usage-stats-"+string(filter.Granularity), export.UsageStatsColumns, func(ew export.Writer) error {
+		return h.svc.ExportUsageStats(ew, filter)
+	})
+}
+
+// writeExport streams rows produced by fn as a file download. Errors after the
+// first byte has been written can only be logged.
+func (h *AdminHandler) writeExport(w http.ResponseWriter, formatName, baseName string, columns []export.Column, fn func(export.Writer) error) {
+	format, err := export.ParseFormat(formatName)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	filename := fmt.Sprintf("%s-%s%s", baseName, time.Now().Format("20060102-150405"), format.Extension())
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+
+	ew, err := export.NewWriter(format, w, columns)
+	if err == nil {
+		err = fn(ew)
+	}
+	if err == nil {
+		err = ew.Close()
+	}
+	if err != nil {
+		log.Printf("[Admin] Export %s failed: %v", baseName, err)
+	}
+}
+
+// parseUsageStatsFilter 解析统计查询参数，无效值会被忽略
+func parseUsageStatsFilter(query url.Values) repository.UsageStatsFilter {
 	filter := repository.UsageStatsFilter{}
 
 	// Parse granularity (required, default to "hour")
@@ -1388,13 +1794,7 @@ func (h *AdminHandler) handleUsageStats(w http.ResponseWriter, r *http.Request)
 	if model := query.Get("model"); model != "" {
 		filter.Model = &model
 	}
-
-	stats, err := h.svc.GetUsageStats(filter)
-	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-		return
-	}
-	writeJSON(w, http.StatusOK, stats)
+	return filter
 }
 
 // handleRecalculateUsageStats handles POST /admin/usage-stats/recalculate
@@ -1442,6 +1842,68 @@ func (h *AdminHandler) handleResponseModels(w http.ResponseWriter, r *http.Reque
 	writeJSON(w, http.StatusOK, names)
 }
 
+// handleAnomalyAlerts handles GET /admin/anomaly-alerts
+func (h *AdminHandler) handleAnomalyAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.svc.GetAnomalyAlerts())
+}
+
+// handleQueueStats handles GET /admin/queue-stats
+func (h *AdminHandler) handleQueueStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.svc.GetAdmissionQueueStats())
+}
+
+// handleProviderHealth handles provider health probes
+// GET /admin/provider-health - current status of every probed provider / client type
+// GET /admin/provider-health/{providerID}?clientType=&limit= - probe history
+// POST /admin/provider-health/{providerID} - probe now
+func (h *AdminHandler) handleProviderHealth(w http.ResponseWriter, r *http.Request, providerID uint64) {
+	switch r.Method {
+	case http.MethodGet:
+		if providerID == 0 {
+			writeJSON(w, http.StatusOK, h.svc.GetProviderHealth())
+			return
+		}
+		limit := 100
+		if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+			limit = v
+		}
+		clientType := domain.ClientType(r.URL.Query().Get("clientType"))
+		checks, err := h.svc.GetProviderHealthHistory(providerID, clientType, limit)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, checks)
+	case http.MethodPost:
+		if providerID == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id required"})
+			return
+		}
+		checks, err := h.svc.CheckProviderHealth(r.Context(), providerID)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, domain.ErrNotFound) {
+				status = http.StatusNotFound
+			} else if errors.Is(err, domain.ErrInvalidInput) {
+				status = http.StatusBadRequest
+			}
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, checks)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
 // handleDashboard handles GET /admin/dashboard
 // Returns all dashboard data in a single request
 func (h *AdminHandler) handleDashboard(w http.ResponseWriter, r *http.Request) {
@@ -1577,6 +2039,10 @@ func (h *AdminHandler) handleModelPrices(w http.ResponseWriter, r *http.Request,
 		h.handleModelPricesReset(w, r)
 		return
 	}
+	if strings.HasSuffix(path, "/import") && r.Method == http.MethodPost {
+		h.handleModelPricesImport(w, r)
+		return
+	}
 
 	switch r.Method {
 	case http.MethodGet:
@@ -1593,6 +2059,21 @@ func (h *AdminHandler) handleModelPrices(w http.ResponseWriter, r *http.Request,
 				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 				return
 			}
+			// 可选 providerId 过滤：0 为全局价格，>0 为该 Provider 的覆盖价格
+			if v := r.URL.Query().Get("providerId"); v != "" {
+				providerID, err := strconv.ParseUint(v, 10, 64)
+				if err != nil {
+					writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid providerId"})
+					return
+				}
+				filtered := make([]*domain.ModelPrice, 0, len(prices))
+				for _, p := range prices {
+					if p.ProviderID == providerID {
+						filtered = append(filtered, p)
+					}
+				}
+				prices = filtered
+			}
 			writeJSON(w, http.StatusOK, prices)
 		}
 
@@ -1654,11 +2135,132 @@ func (h *AdminHandler) handleModelPricesReset(w http.ResponseWriter, r *http.Req
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return
 	}
-	// Refresh calculator cache
-	pricing.GlobalCalculator().LoadFromDatabase(prices)
+	// Refresh calculator cache (provider overrides are kept by the reset)
+	pricing.GlobalCalculator().LoadFromDatabase(mustGetPrices(h.svc))
 	writeJSON(w, http.StatusOK, prices)
 }
 
+// maxPriceSheetSize 价格表大小上限（LiteLLM 完整价格表约 1MB）
+const maxPriceSheetSize = 32 << 20
+
+// handleModelPricesImport handles POST /admin/model-prices/import
+// Body: {"format": "json|csv|litellm"（为空自动识别）, "providerId": 0, "currency": "CNY", "dryRun": false,
+// "content": "..."} 或使用 "path" 读取服务器本地文件
+func (h *AdminHandler) handleModelPricesImport(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Format     string `json:"format"`
+		ProviderID uint64 `json:"providerId"`
+		Currency   string `json:"currency"`
+		DryRun     bool   `json:"dryRun"`
+		Content    string `json:"content"`
+		Path       string `json:"path"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxPriceSheetSize*2)).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON: " + err.Error()})
+		return
+	}
+
+	data := []byte(body.Content)
+	if body.Path != "" {
+		f, err := os.Open(body.Path)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		data, err = io.ReadAll(io.LimitReader(f, maxPriceSheetSize+1))
+		f.Close()
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+	if len(data) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "content or path required"})
+		return
+	}
+	if len(data) > maxPriceSheetSize {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "price sheet too large"})
+		return
+	}
+
+	result, err := h.svc.ImportModelPrices(data, pricing.SheetOptions{
+		Format:     pricing.SheetFormat(strings.ToLower(body.Format)),
+		ProviderID: body.ProviderID,
+		Currency:   body.Currency,
+	}, body.DryRun)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if !body.DryRun && len(result.Created) > 0 {
+		pricing.GlobalCalculator().LoadFromDatabase(mustGetPrices(h.svc))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleAuditLogs handles GET /admin/audit-logs
+// Query: limit, offset, entityType, entityId, actor, action, start, end (RFC3339)
+func (h *AdminHandler) handleAuditLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	query := r.URL.Query()
+	limit := 50
+	if l := query.Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	offset := 0
+	if o := query.Get("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed > 0 {
+			offset = parsed
+		}
+	}
+
+	filter := &repository.AuditLogFilter{}
+	if v := query.Get("entityType"); v != "" {
+		filter.EntityType = &v
+	}
+	if v := query.Get("entityId"); v != "" {
+		filter.EntityID = &v
+	}
+	if v := query.Get("actor"); v != "" {
+		filter.Actor = &v
+	}
+	if v := query.Get("action"); v != "" {
+		filter.Action = &v
+	}
+	if v := query.Get("start"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid start, use RFC3339"})
+			return
+		}
+		filter.StartTime = &t
+	}
+	if v := query.Get("end"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid end, use RFC3339"})
+			return
+		}
+		filter.EndTime = &t
+	}
+
+	result, err := h.svc.GetAuditLogs(filter, limit, offset)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
 // mustGetPrices is a helper to get prices for refreshing calculator
 func mustGetPrices(svc *service.AdminService) []*domain.ModelPrice {
 	prices, _ := svc.GetModelPrices()
@@ -1672,3 +2274,296 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 		json.NewEncoder(w).Encode(data)
 	}
 }
+
+// handleReports handles POST /admin/reports/run
+// Body: {"schedule": "weekly" | "monthly"}，为空时使用已配置的周期；立即生成上一个完整周期的账单报表并投递
+func (h *AdminHandler) handleReports(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) < 3 || parts[2] != "run" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	var body struct {
+		Schedule string `json:"schedule"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON: " + err.Error()})
+			return
+		}
+	}
+
+	if _, err := report.ParseSchedule(body.Schedule); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	result, err := h.svc.RunBillingReport(body.Schedule)
+	if err != nil {
+		if errors.Is(err, service.ErrReportsDisabled) {
+			writeJSON(w, http.StatusNotImplemented, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Billing rule handlers
+// billingRuleErrorStatus maps billing rule service errors to HTTP status codes
+func billingRuleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrBillingDisabled):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *AdminHandler) handleBillingRules(w http.ResponseWriter, r *http.Request, id uint64) {
+	switch r.Method {
+	case http.MethodGet:
+		if id > 0 {
+			rule, err := h.svc.GetBillingRule(id)
+			if err != nil {
+				writeJSON(w, billingRuleErrorStatus(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, rule)
+		} else {
+			rules, err := h.svc.GetBillingRules()
+			if err != nil {
+				writeJSON(w, billingRuleErrorStatus(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, rules)
+		}
+	case http.MethodPost:
+		var rule domain.BillingRule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := h.svc.CreateBillingRule(&rule); err != nil {
+			writeJSON(w, billingRuleErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, rule)
+	case http.MethodPut:
+		if id == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id required"})
+			return
+		}
+		var rule domain.BillingRule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		rule.ID = id
+		if err := h.svc.UpdateBillingRule(&rule); err != nil {
+			writeJSON(w, billingRuleErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, rule)
+	case http.MethodDelete:
+		if id == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id required"})
+			return
+		}
+		if err := h.svc.DeleteBillingRule(id); err != nil {
+			writeJSON(w, billingRuleErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusNoContent, nil)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// Cooldown policy handlers
+// GET /admin/cooldown-policies?providerId= (0 为全局默认，>0 为该 Provider 的覆盖策略)
+
+func cooldownPolicyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCooldownPoliciesDisabled):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *AdminHandler) handleCooldownPolicies(w http.ResponseWriter, r *http.Request, id uint64) {
+	switch r.Method {
+	case http.MethodGet:
+		if id > 0 {
+			policy, err := h.svc.GetCooldownPolicy(id)
+			if err != nil {
+				writeJSON(w, cooldownPolicyErrorStatus(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, policy)
+			return
+		}
+		policies, err := h.svc.GetCooldownPolicies()
+		if err != nil {
+			writeJSON(w, cooldownPolicyErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		if v := r.URL.Query().Get("providerId"); v != "" {
+			providerID, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid providerId"})
+				return
+			}
+			filtered := make([]*domain.CooldownPolicy, 0, len(policies))
+			for _, p := range policies {
+				if p.ProviderID == providerID {
+					filtered = append(filtered, p)
+				}
+			}
+			policies = filtered
+		}
+		writeJSON(w, http.StatusOK, policies)
+	case http.MethodPost:
+		var policy domain.CooldownPolicy
+		if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := h.svc.CreateCooldownPolicy(&policy); err != nil {
+			writeJSON(w, cooldownPolicyErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, policy)
+	case http.MethodPut:
+		if id == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id required"})
+			return
+		}
+		var policy domain.CooldownPolicy
+		if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		policy.ID = id
+		if err := h.svc.UpdateCooldownPolicy(&policy); err != nil {
+			writeJSON(w, cooldownPolicyErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, policy)
+	case http.MethodDelete:
+		if id == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id required"})
+			return
+		}
+		if err := h.svc.DeleteCooldownPolicy(id); err != nil {
+			writeJSON(w, cooldownPolicyErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusNoContent, nil)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// Model fallback handlers
+// GET /admin/model-fallbacks?clientType= (按 priority 排序，首个匹配的降级链生效)
+
+func modelFallbackErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrModelFallbacksDisabled):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *AdminHandler) handleModelFallbacks(w http.ResponseWriter, r *http.Request, id uint64) {
+	switch r.Method {
+	case http.MethodGet:
+		if id > 0 {
+			fallback, err := h.svc.GetModelFallback(id)
+			if err != nil {
+				writeJSON(w, modelFallbackErrorStatus(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, fallback)
+			return
+		}
+		fallbacks, err := h.svc.GetModelFallbacks()
+		if err != nil {
+			writeJSON(w, modelFallbackErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		if clientType := r.URL.Query().Get("clientType"); clientType != "" {
+			filtered := make([]*domain.ModelFallback, 0, len(fallbacks))
+			for _, f := range fallbacks {
+				if f.ClientType == "" || f.ClientType == domain.ClientType(clientType) {
+					filtered = append(filtered, f)
+				}
+			}
+			fallbacks = filtered
+		}
+		writeJSON(w, http.StatusOK, fallbacks)
+	case http.MethodPost:
+		var fallback domain.ModelFallback
+		if err := json.NewDecoder(r.Body).Decode(&fallback); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := h.svc.CreateModelFallback(&fallback); err != nil {
+			writeJSON(w, modelFallbackErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, fallback)
+	case http.MethodPut:
+		if id == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id required"})
+			return
+		}
+		var fallback domain.ModelFallback
+		if err := json.NewDecoder(r.Body).Decode(&fallback); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		fallback.ID = id
+		if err := h.svc.UpdateModelFallback(&fallback); err != nil {
+			writeJSON(w, modelFallbackErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, fallback)
+	case http.MethodDelete:
+		if id == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id required"})
+			return
+		}
+		if err := h.svc.DeleteModelFallback(id); err != nil {
+			writeJSON(w, modelFallbackErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusNoContent, nil)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
@@ -2,9 +2,11 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/awsl-project/maxx/internal/domain"
@@ -72,6 +74,7 @@ func newAdminHandlerForProviderImportExportTests(providerRepo *adminTestProvider
 		nil,
 		nil,
 		nil,
+		nil,
 		"",
 		nil,
 		nil,
@@ -148,3 +151,50 @@ func TestAdminHandler_ProvidersExport_WithTrailingSlash(t *testing.T) {
 		t.Fatalf("providers = %+v, want one exported-provider", providers)
 	}
 }
+
+func TestAdminHandler_ViewerSeesMaskedSecrets(t *testing.T) {
+	providerRepo := &adminTestProviderRepo{
+		providers: []*domain.Provider{{
+			ID:   1,
+			Name: "custom-provider",
+			Type: "custom",
+			Config: &domain.ProviderConfig{Custom: &domain.ProviderConfigCustom{
+				BaseURL: "https://api.example.com",
+				APIKey:  "sk-secret",
+			}},
+		}},
+	}
+	h := newAdminHandlerForProviderImportExportTests(providerRepo)
+
+	get := func(role string) string {
+		req := httptest.NewRequest(http.MethodGet, "/admin/providers", nil)
+		req = req.WithContext(context.WithValue(req.Context(), adminRoleCtxKey{}, role))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+		return rec.Body.String()
+	}
+
+	viewer := get(AdminRoleViewer)
+	if strings.Contains(viewer, "sk-secret") || !strings.Contains(viewer, "https://api.example.com") {
+		t.Fatalf("viewer response should mask the API key only: %s", viewer)
+	}
+	if admin := get(AdminRoleAdmin); !strings.Contains(admin, "sk-secret") {
+		t.Fatalf("admin response should keep the API key: %s", admin)
+	}
+}
+
+func TestAdminHandler_LocalDiscoverRoute(t *testing.T) {
+	h := newAdminHandlerForProviderImportExportTests(&adminTestProviderRepo{})
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/local/discover", bytes.NewReader([]byte(`{}`)))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d, body = %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseProxyRequestFilter(t *testing.T) {
+	filter, err := parseProxyRequestFilter(url.Values{})
+	if err != nil || filter != nil {
+		t.Fatalf("empty query should yield nil filter, got %+v, %v", filter, err)
+	}
+
+	filter, err = parseProxyRequestFilter(url.Values{
+		"start":         {"2026-01-02T03:04:05Z"},
+		"model":         {"claude-sonnet"},
+		"statusCode":    {"529"},
+		"minDurationMs": {"1500"},
+		"q":             {"  flux capacitor "},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.StartTime == nil || filter.StartTime.Year() != 2026 {
+		t.Fatalf("unexpected start time %v", filter.StartTime)
+	}
+	if *filter.Model != "claude-sonnet" || *filter.StatusCode != 529 || *filter.MinDurationMs != 1500 {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+	if *filter.Query != "flux capacitor" {
+		t.Fatalf("unexpected query %q", *filter.Query)
+	}
+
+	for _, q := range []url.Values{
+		{"providerId": {"abc"}},
+		{"end": {"yesterday"}},
+		{"minTokens": {"-1"}},
+	} {
+		if _, err := parseProxyRequestFilter(q); err == nil {
+			t.Fatalf("expected error for %v", q)
+		}
+	}
+}
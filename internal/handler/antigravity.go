@@ -118,7 +118,11 @@ func (h *AntigravityHandler) ValidateToken(ctx context.Context, refreshToken str
 		return nil, fmt.Errorf("refreshToken is required")
 	}
 
-	result, err := antigravity.ValidateRefreshToken(ctx, refreshToken)
+	client, err := oauthHTTPClient(nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := antigravity.ValidateRefreshToken(ctx, client, refreshToken)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +146,11 @@ func (h *AntigravityHandler) ValidateTokens(ctx context.Context, tokens []string
 		return nil, fmt.Errorf("too many tokens (max 50)")
 	}
 
-	results := antigravity.BatchValidateRefreshTokens(ctx, tokens)
+	client, err := oauthHTTPClient(nil)
+	if err != nil {
+		return nil, err
+	}
+	results := antigravity.BatchValidateRefreshTokens(ctx, client, tokens)
 
 	// 保存每个有效的验证结果到数据库
 	for _, result := range results {
@@ -311,8 +319,13 @@ func (h *AntigravityHandler) GetProviderQuota(ctx context.Context, providerID ui
 		}
 	}
 
+	client, err := oauthHTTPClient(provider.Config.Network)
+	if err != nil {
+		return nil, err
+	}
+
 	// 从 API 获取最新配额
-	quota, err := antigravity.FetchQuotaForProvider(ctx, config.RefreshToken, config.ProjectID)
+	quota, err := antigravity.FetchQuotaForProvider(ctx, client, config.RefreshToken, config.ProjectID)
 	if err != nil {
 		// 如果 API 失败，尝试返回缓存数据
 		if email != "" && h.quotaRepo != nil {
@@ -420,7 +433,11 @@ func (h *AntigravityHandler) GetBatchQuotas(ctx context.Context) (*BatchQuotaRes
 		}
 
 		// 数据库没有缓存，尝试从 API 获取
-		quota, err := antigravity.FetchQuotaForProvider(ctx, config.RefreshToken, config.ProjectID)
+		client, err := oauthHTTPClient(provider.Config.Network)
+		if err != nil {
+			continue
+		}
+		quota, err := antigravity.FetchQuotaForProvider(ctx, client, config.RefreshToken, config.ProjectID)
 		if err != nil {
 			// API 失败，跳过此 provider
 			continue
@@ -521,22 +538,29 @@ func (h *AntigravityHandler) handleOAuthCallback(w http.ResponseWriter, r *http.
 	// 构建回调 URL
 	redirectURI := fmt.Sprintf("%s://%s/antigravity/oauth/callback", getScheme(r), r.Host)
 
+	// 授权时尚未创建 provider，使用默认网络设置
+	client, err := oauthHTTPClient(nil)
+	if err != nil {
+		h.sendOAuthErrorResult(w, state, err.Error())
+		return
+	}
+
 	// 使用 code 交换 tokens
-	accessToken, refreshToken, _, err := antigravity.ExchangeCodeForTokens(r.Context(), code, redirectURI)
+	accessToken, refreshToken, _, err := antigravity.ExchangeCodeForTokens(r.Context(), client, code, redirectURI)
 	if err != nil {
 		h.sendOAuthErrorResult(w, state, fmt.Sprintf("Token exchange failed: %v", err))
 		return
 	}
 
 	// 获取用户信息
-	userInfo, err := antigravity.FetchUserInfo(r.Context(), accessToken)
+	userInfo, err := antigravity.FetchUserInfo(r.Context(), client, accessToken)
 	if err != nil {
 		h.sendOAuthErrorResult(w, state, fmt.Sprintf("Failed to fetch user info: %v", err))
 		return
 	}
 
 	// 获取项目信息和订阅等级
-	projectID, tier, err := antigravity.FetchProjectInfo(r.Context(), accessToken, userInfo.Email)
+	projectID, tier, err := antigravity.FetchProjectInfo(r.Context(), client, accessToken, userInfo.Email)
 	if err != nil {
 		// Project info 获取失败不算致命错误
 		projectID = antigravity.DefaultProjectID
@@ -544,7 +568,7 @@ func (h *AntigravityHandler) handleOAuthCallback(w http.ResponseWriter, r *http.
 	}
 
 	// 获取配额信息
-	quota, err := antigravity.FetchQuota(r.Context(), accessToken, projectID)
+	quota, err := antigravity.FetchQuota(r.Context(), client, accessToken, projectID)
 	if err != nil {
 		// 配额获取失败也不算致命错误
 		quota = &antigravity.QuotaData{
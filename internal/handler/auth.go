@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"context"
+	"crypto/rand"
 	"crypto/subtle"
 	"encoding/json"
+	"log"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -14,51 +18,143 @@ import (
 const (
 	// AdminPasswordEnvKey is the environment variable name for admin password
 	AdminPasswordEnvKey = "MAXX_ADMIN_PASSWORD"
+	// AdminJWTSecretEnvKey is the environment variable name for the JWT signing secret.
+	// Falls back to the admin password; required to keep tokens valid across restarts in OIDC-only setups.
+	AdminJWTSecretEnvKey = "MAXX_ADMIN_JWT_SECRET"
 	// AuthHeader is the header name for JWT authentication
 	AuthHeader = "Authorization"
 	// TokenExpiry is the JWT token expiry duration
 	TokenExpiry = 7 * 24 * time.Hour // 7 days
+	// DefaultAdminSubject is the JWT subject for password logins
+	DefaultAdminSubject = "admin"
 )
 
+// Admin roles carried in the admin JWT
+const (
+	AdminRoleAdmin  = "admin"  // full access
+	AdminRoleViewer = "viewer" // read-only access
+)
+
+// IsValidAdminRole reports whether role is a known admin role
+func IsValidAdminRole(role string) bool {
+	return role == AdminRoleAdmin || role == AdminRoleViewer
+}
+
+func adminRoleRank(role string) int {
+	switch role {
+	case AdminRoleAdmin:
+		return 2
+	case AdminRoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type adminActorCtxKey struct{}
+type adminRoleCtxKey struct{}
+
+// AdminClaims are the claims of the admin JWT
+type AdminClaims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role,omitempty"`
+}
+
 // AuthMiddleware provides JWT authentication for admin API
 type AuthMiddleware struct {
-	password string
+	password  string
+	jwtSecret []byte
+	oidc      *OIDCClient
 }
 
 // NewAuthMiddleware creates a new auth middleware
 func NewAuthMiddleware() *AuthMiddleware {
-	return &AuthMiddleware{
+	m := &AuthMiddleware{
 		password: os.Getenv(AdminPasswordEnvKey),
 	}
+	if cfg := LoadOIDCConfigFromEnv(); cfg != nil {
+		if err := cfg.Validate(); err != nil {
+			log.Fatalf("[Auth] Invalid OIDC configuration: %v", err)
+		}
+		m.oidc = NewOIDCClient(cfg)
+	}
+
+	switch {
+	case os.Getenv(AdminJWTSecretEnvKey) != "":
+		m.jwtSecret = []byte(os.Getenv(AdminJWTSecretEnvKey))
+	case m.password != "":
+		m.jwtSecret = []byte(m.password)
+	case m.oidc != nil:
+		// OIDC only: tokens are invalidated on restart unless a secret is configured
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatalf("Failed to generate admin JWT secret: %v", err)
+		}
+		m.jwtSecret = secret
+		log.Printf("[Auth] %s not set, admin sessions will not survive restarts", AdminJWTSecretEnvKey)
+	}
+	return m
 }
 
 // IsEnabled returns true if authentication is enabled
 func (m *AuthMiddleware) IsEnabled() bool {
+	return m.password != "" || m.oidc != nil
+}
+
+// PasswordEnabled returns true if password login is enabled
+func (m *AuthMiddleware) PasswordEnabled() bool {
 	return m.password != ""
 }
 
-// GenerateToken generates a JWT token
+// OIDC returns the OIDC client, or nil when SSO is not configured
+func (m *AuthMiddleware) OIDC() *OIDCClient {
+	return m.oidc
+}
+
+// GenerateToken generates a JWT token for the password login
 func (m *AuthMiddleware) GenerateToken() (string, error) {
-	claims := jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExpiry)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		Issuer:    "maxx-admin",
+	return m.GenerateTokenFor(DefaultAdminSubject, AdminRoleAdmin)
+}
+
+// GenerateTokenFor generates a JWT token for the given subject and role
+func (m *AuthMiddleware) GenerateTokenFor(subject, role string) (string, error) {
+	claims := AdminClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExpiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "maxx-admin",
+		},
+		Role: role,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.password))
+	return token.SignedString(m.jwtSecret)
 }
 
 // ValidateToken validates a JWT token
 func (m *AuthMiddleware) ValidateToken(tokenString string) bool {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+	_, ok := m.parseToken(tokenString)
+	return ok
+}
+
+// parseToken validates a JWT token and returns its claims
+func (m *AuthMiddleware) parseToken(tokenString string) (*AdminClaims, bool) {
+	if len(m.jwtSecret) == 0 {
+		return nil, false
+	}
+	claims := &AdminClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, jwt.ErrSignatureInvalid
 		}
-		return []byte(m.password), nil
+		return m.jwtSecret, nil
 	})
 
-	return err == nil && token.Valid
+	if err != nil || !token.Valid {
+		return nil, false
+	}
+	return claims, true
 }
 
 // Wrap wraps a handler with JWT authentication
@@ -76,23 +172,156 @@ func (m *AuthMiddleware) Wrap(next http.Handler) http.Handler {
 		}
 
 		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if !m.ValidateToken(token) {
+		claims, ok := m.parseToken(token)
+		if !ok {
 			writeUnauthorized(w)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		// Tokens issued before subjects/roles were added still belong to the admin
+		actor := claims.Subject
+		if actor == "" {
+			actor = DefaultAdminSubject
+		}
+		role := claims.Role
+		if role == "" {
+			role = AdminRoleAdmin
+		}
+
+		// Viewers are read-only and may only read resources without bulk secrets
+		if role != AdminRoleAdmin && !viewerCanRead(r) {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "forbidden"})
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), adminActorCtxKey{}, actor)
+		ctx = context.WithValue(ctx, adminRoleCtxKey{}, role)
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// WrapMutating requires admin authentication for requests that change state; read-only
+// requests pass through. Used for provider OAuth endpoints mounted outside /api/admin.
+func (m *AuthMiddleware) WrapMutating(next http.Handler) http.Handler {
+	wrapped := m.Wrap(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isReadOnlyMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		wrapped.ServeHTTP(w, r)
+	})
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// viewerReadableResources admin resources viewers may read.
+// Secret fields in their responses are masked by AdminHandler; backup export, server logs
+// and the provider export are excluded because they exist to hand out full credentials.
+var viewerReadableResources = map[string]bool{
+	"providers":          true,
+	"routes":             true,
+	"projects":           true,
+	"sessions":           true,
+	"retry-configs":      true,
+	"routing-strategies": true,
+	"requests":           true,
+	"settings":           true,
+	"proxy-status":       true,
+	"provider-stats":     true,
+	"cooldowns":          true,
+	"api-tokens":         true,
+	"model-mappings":     true,
+	"usage-stats":        true,
+	"dashboard":          true,
+	"response-models":    true,
+	"pricing":            true,
+	"model-prices":       true,
+	"audit-logs":         true,
+	"reports":            true,
+	"billing-rules":      true,
+	"cooldown-policies":  true,
+	"model-fallbacks":    true,
+	"anomaly-alerts":     true,
+	"queue-stats":        true,
+	"provider-health":    true,
+}
+
+// viewerCanRead reports whether a viewer may perform the request (path relative to /admin)
+func viewerCanRead(r *http.Request) bool {
+	if !isReadOnlyMethod(r.Method) {
+		return false
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "admin" {
+		return false
+	}
+	if parts[1] == "providers" && len(parts) > 2 && parts[2] == "export" {
+		return false
+	}
+	return viewerReadableResources[parts[1]]
+}
+
 // VerifyPassword checks if the provided password is correct
 func (m *AuthMiddleware) VerifyPassword(password string) bool {
 	if !m.IsEnabled() {
 		return true
 	}
+	if m.password == "" {
+		// SSO only, password login disabled
+		return false
+	}
 	return subtle.ConstantTimeCompare([]byte(m.password), []byte(password)) == 1
 }
 
+// AdminRole returns the admin role for the request.
+// Returns admin when authentication is disabled.
+func AdminRole(r *http.Request) string {
+	if role, ok := r.Context().Value(adminRoleCtxKey{}).(string); ok {
+		return role
+	}
+	return AdminRoleAdmin
+}
+
+// AdminActor returns the authenticated admin identity for the request (used for audit logging).
+// Returns empty string when authentication is disabled.
+func AdminActor(r *http.Request) string {
+	if actor, ok := r.Context().Value(adminActorCtxKey{}).(string); ok {
+		return actor
+	}
+	return ""
+}
+
+// requestClientIP returns the client IP, honoring reverse proxy headers
+func requestClientIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		return strings.TrimSpace(strings.Split(xff, ",")[0])
+	}
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return realIP
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// trustedClientIP returns the client IP used for IP allowlists and audit entries.
+// Forwarding headers are only trusted when the direct peer is a loopback or private address
+// (i.e. a reverse proxy), otherwise clients could spoof their way past the allowlist or forge the audit trail.
+func trustedClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip != nil && (ip.IsLoopback() || ip.IsPrivate()) {
+		return requestClientIP(r)
+	}
+	return host
+}
+
 func writeUnauthorized(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusUnauthorized)
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"encoding/json"
+	"html/template"
+	"log"
 	"net/http"
 	"strings"
 )
@@ -28,6 +30,10 @@ func (h *AuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleVerify(w, r)
 	case "/status":
 		h.handleStatus(w, r)
+	case "/oidc/login":
+		h.handleOIDCLogin(w, r)
+	case "/oidc/callback":
+		h.handleOIDCCallback(w, r)
 	default:
 		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
 	}
@@ -76,6 +82,91 @@ func (h *AuthHandler) handleStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
-		"authEnabled": h.authMiddleware.IsEnabled(),
+		"authEnabled":     h.authMiddleware.IsEnabled(),
+		"passwordEnabled": h.authMiddleware.PasswordEnabled(),
+		"oidcEnabled":     h.authMiddleware.OIDC() != nil,
 	})
 }
+
+// handleOIDCLogin redirects the browser to the OpenID provider
+// GET /admin/auth/oidc/login
+func (h *AuthHandler) handleOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	client := h.authMiddleware.OIDC()
+	if client == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "oidc is not configured"})
+		return
+	}
+
+	authURL, err := client.AuthCodeURL(r.Context(), client.Config().RedirectURL)
+	if err != nil {
+		log.Printf("[Auth] OIDC login failed: %v", err)
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": "failed to start oidc login"})
+		return
+	}
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// handleOIDCCallback completes the OIDC login and hands the admin JWT to the web UI
+// GET /admin/auth/oidc/callback
+func (h *AuthHandler) handleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	client := h.authMiddleware.OIDC()
+	if client == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "oidc is not configured"})
+		return
+	}
+
+	query := r.URL.Query()
+	if errCode := query.Get("error"); errCode != "" {
+		writeOIDCResult(w, http.StatusUnauthorized, "", errCode+": "+query.Get("error_description"))
+		return
+	}
+	state := query.Get("state")
+	code := query.Get("code")
+	if state == "" || code == "" {
+		writeOIDCResult(w, http.StatusBadRequest, "", "missing state or code")
+		return
+	}
+
+	identity, err := client.Exchange(r.Context(), state, code)
+	if err != nil {
+		log.Printf("[Auth] OIDC callback failed: %v", err)
+		writeOIDCResult(w, http.StatusUnauthorized, "", err.Error())
+		return
+	}
+
+	token, err := h.authMiddleware.GenerateTokenFor(identity.Actor(), identity.Role)
+	if err != nil {
+		writeOIDCResult(w, http.StatusInternalServerError, "", "failed to generate token")
+		return
+	}
+	log.Printf("[Auth] OIDC login: %s (role=%s)", identity.Actor(), identity.Role)
+	writeOIDCResult(w, http.StatusOK, token, "")
+}
+
+// oidcResultPage stores the token where the web UI expects it and returns to the app
+var oidcResultPage = template.Must(template.New("oidc").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Maxx</title></head>
+<body>
+{{if .Error}}<p>Login failed: {{.Error}}</p><p><a href="/">Back</a></p>
+{{else}}<script>
+localStorage.setItem('maxx-admin-token', {{.Token}});
+window.location.replace('/');
+</script>{{end}}
+</body></html>`))
+
+func writeOIDCResult(w http.ResponseWriter, status int, token, errMsg string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	oidcResultPage.Execute(w, map[string]string{"Token": token, "Error": errMsg})
+}
@@ -135,7 +135,11 @@ func (h *CodexHandler) ValidateToken(ctx context.Context, refreshToken string) (
 		return nil, fmt.Errorf("refreshToken is required")
 	}
 
-	return codex.ValidateRefreshToken(ctx, refreshToken)
+	client, err := oauthHTTPClient(nil)
+	if err != nil {
+		return nil, err
+	}
+	return codex.ValidateRefreshToken(ctx, client, refreshToken)
 }
 
 // OAuthStartResult OAuth start result
@@ -234,8 +238,15 @@ func (h *CodexHandler) handleOAuthCallback(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// No provider exists yet during authorization, so use the default network settings
+	client, err := oauthHTTPClient(nil)
+	if err != nil {
+		h.sendOAuthErrorResult(w, state, err.Error())
+		return
+	}
+
 	// Exchange code for tokens (using fixed redirect URI)
-	tokenResp, err := codex.ExchangeCodeForTokens(r.Context(), code, codex.OAuthRedirectURI, session.CodeVerifier)
+	tokenResp, err := codex.ExchangeCodeForTokens(r.Context(), client, code, codex.OAuthRedirectURI, session.CodeVerifier)
 	if err != nil {
 		h.sendOAuthErrorResult(w, state, fmt.Sprintf("Token exchange failed: %v", err))
 		return
@@ -311,8 +322,15 @@ func (h *CodexHandler) handleOAuthExchange(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// No provider exists yet during authorization, so use the default network settings
+	client, err := oauthHTTPClient(nil)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
 	// Exchange code for tokens (using fixed redirect URI)
-	tokenResp, err := codex.ExchangeCodeForTokens(r.Context(), req.Code, codex.OAuthRedirectURI, session.CodeVerifier)
+	tokenResp, err := codex.ExchangeCodeForTokens(r.Context(), client, req.Code, codex.OAuthRedirectURI, session.CodeVerifier)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Token exchange failed: %v", err)})
 		return
@@ -408,8 +426,13 @@ func (h *CodexHandler) RefreshProviderInfo(ctx context.Context, providerID int)
 		return nil, fmt.Errorf("provider %s has no refresh token", provider.Name)
 	}
 
+	client, err := oauthHTTPClient(provider.Config.Network)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate and refresh the token
-	result, err := codex.ValidateRefreshToken(ctx, refreshToken)
+	result, err := codex.ValidateRefreshToken(ctx, client, refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to refresh token: %w", err)
 	}
@@ -474,6 +497,11 @@ func (h *CodexHandler) GetProviderUsage(ctx context.Context, providerID int) (*c
 
 	codexConfig := provider.Config.Codex
 
+	client, err := oauthHTTPClient(provider.Config.Network)
+	if err != nil {
+		return nil, err
+	}
+
 	// Ensure we have an access token
 	accessToken := codexConfig.AccessToken
 	if accessToken == "" {
@@ -482,7 +510,7 @@ func (h *CodexHandler) GetProviderUsage(ctx context.Context, providerID int) (*c
 			return nil, fmt.Errorf("provider %s has no refresh token", provider.Name)
 		}
 
-		result, err := codex.ValidateRefreshToken(ctx, codexConfig.RefreshToken)
+		result, err := codex.ValidateRefreshToken(ctx, client, codexConfig.RefreshToken)
 		if err != nil {
 			return nil, fmt.Errorf("failed to refresh token: %w", err)
 		}
@@ -505,7 +533,7 @@ func (h *CodexHandler) GetProviderUsage(ctx context.Context, providerID int) (*c
 			if err == nil && time.Now().After(expiresAt.Add(-60*time.Second)) {
 				// Token expired or about to expire, refresh it
 				if codexConfig.RefreshToken != "" {
-					result, err := codex.ValidateRefreshToken(ctx, codexConfig.RefreshToken)
+					result, err := codex.ValidateRefreshToken(ctx, client, codexConfig.RefreshToken)
 					if err == nil && result.Valid {
 						accessToken = result.AccessToken
 						codexConfig.AccessToken = result.AccessToken
@@ -522,7 +550,7 @@ func (h *CodexHandler) GetProviderUsage(ctx context.Context, providerID int) (*c
 
 	// Fetch usage
 	accountID := codexConfig.AccountID
-	usage, err := codex.FetchUsage(ctx, accessToken, accountID)
+	usage, err := codex.FetchUsage(ctx, client, accessToken, accountID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch usage: %w", err)
 	}
@@ -614,10 +642,15 @@ func (h *CodexHandler) GetBatchQuotas(ctx context.Context) (*CodexBatchQuotaResu
 			continue
 		}
 
+		client, err := oauthHTTPClient(provider.Config.Network)
+		if err != nil {
+			continue
+		}
+
 		// 获取或刷新 access token
 		accessToken := config.AccessToken
 		if accessToken == "" || h.isTokenExpired(config.ExpiresAt) {
-			tokenResp, err := codex.RefreshAccessToken(ctx, config.RefreshToken)
+			tokenResp, err := codex.RefreshAccessToken(ctx, client, config.RefreshToken)
 			if err != nil {
 				// API 失败，跳过此 provider
 				continue
@@ -634,7 +667,7 @@ func (h *CodexHandler) GetBatchQuotas(ctx context.Context) (*CodexBatchQuotaResu
 		}
 
 		// 获取配额
-		usage, err := codex.FetchUsage(ctx, accessToken, config.AccountID)
+		usage, err := codex.FetchUsage(ctx, client, accessToken, config.AccountID)
 		if err != nil {
 			// API 失败，跳过此 provider
 			continue
@@ -0,0 +1,416 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/adapter/provider/copilot"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/event"
+	"github.com/awsl-project/maxx/internal/repository"
+	"github.com/awsl-project/maxx/internal/service"
+)
+
+// CopilotHandler handles GitHub Copilot-specific API requests
+type CopilotHandler struct {
+	svc           *service.AdminService
+	quotaRepo     repository.CopilotQuotaRepository
+	deviceManager *copilot.DeviceFlowManager
+}
+
+// NewCopilotHandler creates a new Copilot handler
+func NewCopilotHandler(svc *service.AdminService, quotaRepo repository.CopilotQuotaRepository, broadcaster event.Broadcaster) *CopilotHandler {
+	return &CopilotHandler{
+		svc:           svc,
+		quotaRepo:     quotaRepo,
+		deviceManager: copilot.NewDeviceFlowManager(broadcaster),
+	}
+}
+
+// ServeHTTP routes Copilot requests
+// Routes:
+//
+//	POST /copilot/validate-token - Validate GitHub token
+//	POST /copilot/device/start - Start GitHub device flow (result pushed via copilot_device_result)
+//	POST /copilot/device/cancel - Cancel a pending device flow
+//	POST /copilot/provider/:id/refresh - Refresh provider info
+//	GET  /copilot/provider/:id/usage - Get provider usage/quota
+//	GET  /copilot/providers/quotas - Batch get all Copilot provider quotas
+func (h *CopilotHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/copilot")
+	path = strings.TrimSuffix(path, "/")
+
+	parts := strings.Split(path, "/")
+
+	// POST /copilot/validate-token
+	if len(parts) >= 2 && parts[1] == "validate-token" && r.Method == http.MethodPost {
+		h.handleValidateToken(w, r)
+		return
+	}
+
+	// POST /copilot/device/start
+	if len(parts) >= 3 && parts[1] == "device" && parts[2] == "start" && r.Method == http.MethodPost {
+		h.handleDeviceStart(w, r)
+		return
+	}
+
+	// POST /copilot/device/cancel
+	if len(parts) >= 3 && parts[1] == "device" && parts[2] == "cancel" && r.Method == http.MethodPost {
+		h.handleDeviceCancel(w, r)
+		return
+	}
+
+	// GET /copilot/providers/quotas - Batch get quotas (before single provider route)
+	if len(parts) >= 3 && parts[1] == "providers" && parts[2] == "quotas" && r.Method == http.MethodGet {
+		h.handleGetBatchQuotas(w, r)
+		return
+	}
+
+	// POST /copilot/provider/:id/refresh
+	if len(parts) >= 4 && parts[1] == "provider" && parts[3] == "refresh" && r.Method == http.MethodPost {
+		h.handleRefreshProviderInfo(w, r, parts[2])
+		return
+	}
+
+	// GET /copilot/provider/:id/usage
+	if len(parts) >= 4 && parts[1] == "provider" && parts[3] == "usage" && r.Method == http.MethodGet {
+		h.handleGetProviderUsage(w, r, parts[2])
+		return
+	}
+
+	writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+}
+
+// ============================================================================
+// Public methods (shared by HTTP handler and Wails)
+// ============================================================================
+
+// ValidateToken validates a GitHub token
+func (h *CopilotHandler) ValidateToken(ctx context.Context, githubToken string) (*copilot.CopilotTokenValidationResult, error) {
+	if githubToken == "" {
+		return nil, fmt.Errorf("githubToken is required")
+	}
+
+	client, err := oauthHTTPClient(nil)
+	if err != nil {
+		return nil, err
+	}
+	return copilot.ValidateGitHubToken(ctx, client, githubToken)
+}
+
+// StartDeviceFlow starts the GitHub device flow
+func (h *CopilotHandler) StartDeviceFlow(ctx context.Context) (*copilot.DeviceFlowStartResult, error) {
+	return h.deviceManager.Start(ctx)
+}
+
+// RefreshProviderInfo refreshes the Copilot provider info by re-validating the GitHub token
+func (h *CopilotHandler) RefreshProviderInfo(ctx context.Context, providerID int) (*copilot.CopilotTokenValidationResult, error) {
+	p, err := h.getCopilotProvider(providerID)
+	if err != nil {
+		return nil, err
+	}
+	config := p.Config.Copilot
+
+	client, err := oauthHTTPClient(p.Config.Network)
+	if err != nil {
+		return nil, err
+	}
+	result, err := copilot.ValidateGitHubToken(ctx, client, config.GitHubToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate token: %w", err)
+	}
+
+	if !result.Valid {
+		return result, nil
+	}
+
+	// Update provider config with new info
+	config.Username = result.Username
+	config.Name = result.Name
+	config.AvatarURL = result.AvatarURL
+	config.Plan = result.Plan
+	config.CopilotToken = result.CopilotToken
+	config.ExpiresAt = result.ExpiresAt
+	config.APIEndpoint = result.APIEndpoint
+
+	// Save the updated provider
+	if err := h.svc.UpdateProvider(p); err != nil {
+		return nil, fmt.Errorf("failed to update provider: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetProviderUsage fetches the usage/quota information for a Copilot provider and caches it
+func (h *CopilotHandler) GetProviderUsage(ctx context.Context, providerID int) (*copilot.CopilotUsageResponse, error) {
+	p, err := h.getCopilotProvider(providerID)
+	if err != nil {
+		return nil, err
+	}
+	config := p.Config.Copilot
+
+	client, err := oauthHTTPClient(p.Config.Network)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := copilot.FetchUsage(ctx, client, config.GitHubToken)
+	if err != nil {
+		if errors.Is(err, copilot.ErrCopilotForbidden) {
+			h.saveQuotaToDB(config.Username, config.Plan, nil, true)
+		}
+		return nil, fmt.Errorf("failed to fetch usage: %w", err)
+	}
+
+	h.saveQuotaToDB(config.Username, usage.Plan, usage, false)
+	return usage, nil
+}
+
+// CopilotBatchQuotaResult 批量配额查询结果
+type CopilotBatchQuotaResult struct {
+	Quotas map[uint64]*copilot.CopilotQuotaResponse `json:"quotas"` // providerId -> quota
+}
+
+// GetBatchQuotas 批量获取所有 Copilot provider 的配额信息（供 HTTP handler 和 Wails 共用）
+// 优先从数据库返回缓存数据，没有缓存时才请求 API
+func (h *CopilotHandler) GetBatchQuotas(ctx context.Context) (*CopilotBatchQuotaResult, error) {
+	providers, err := h.svc.GetProviders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list providers: %w", err)
+	}
+
+	result := &CopilotBatchQuotaResult{
+		Quotas: make(map[uint64]*copilot.CopilotQuotaResponse),
+	}
+
+	for _, p := range providers {
+		if p.Type != "copilot" || p.Config == nil || p.Config.Copilot == nil {
+			continue
+		}
+		username := p.Config.Copilot.Username
+
+		// 优先从数据库获取缓存的配额（无论是否过期）
+		if username != "" && h.quotaRepo != nil {
+			cachedQuota, err := h.quotaRepo.GetByUsername(username)
+			if err == nil && cachedQuota != nil {
+				result.Quotas[p.ID] = h.domainQuotaToResponse(cachedQuota)
+				continue
+			}
+		}
+
+		// 数据库没有缓存，尝试从 API 获取（成功后已写入数据库）
+		usage, err := h.GetProviderUsage(ctx, int(p.ID))
+		if err != nil {
+			// API 失败，跳过此 provider
+			continue
+		}
+		result.Quotas[p.ID] = h.usageToResponse(username, usage)
+	}
+
+	return result, nil
+}
+
+// ============================================================================
+// HTTP handler methods
+// ============================================================================
+
+// handleValidateToken validates a GitHub token
+func (h *CopilotHandler) handleValidateToken(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		GitHubToken string `json:"githubToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	result, err := h.ValidateToken(r.Context(), strings.TrimSpace(req.GitHubToken))
+	if err != nil {
+		if strings.Contains(err.Error(), "required") {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		} else {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleDeviceStart starts the GitHub device flow
+func (h *CopilotHandler) handleDeviceStart(w http.ResponseWriter, r *http.Request) {
+	result, err := h.StartDeviceFlow(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleDeviceCancel cancels a pending device flow
+func (h *CopilotHandler) handleDeviceCancel(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": h.deviceManager.Cancel(req.SessionID)})
+}
+
+// handleRefreshProviderInfo handles POST /copilot/provider/:id/refresh
+func (h *CopilotHandler) handleRefreshProviderInfo(w http.ResponseWriter, r *http.Request, idStr string) {
+	providerID, err := strconv.Atoi(idStr)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid provider ID"})
+		return
+	}
+
+	result, err := h.RefreshProviderInfo(r.Context(), providerID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleGetProviderUsage handles GET /copilot/provider/:id/usage
+func (h *CopilotHandler) handleGetProviderUsage(w http.ResponseWriter, r *http.Request, idStr string) {
+	providerID, err := strconv.Atoi(idStr)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid provider ID"})
+		return
+	}
+
+	usage, err := h.GetProviderUsage(r.Context(), providerID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, usage)
+}
+
+// handleGetBatchQuotas 批量获取所有 Copilot provider 的配额信息
+func (h *CopilotHandler) handleGetBatchQuotas(w http.ResponseWriter, r *http.Request) {
+	result, err := h.GetBatchQuotas(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// getCopilotProvider loads a provider and checks it is a Copilot provider
+func (h *CopilotHandler) getCopilotProvider(providerID int) (*domain.Provider, error) {
+	p, err := h.svc.GetProvider(uint64(providerID))
+	if err != nil {
+		return nil, fmt.Errorf("provider not found: %w", err)
+	}
+	if p.Type != "copilot" || p.Config == nil || p.Config.Copilot == nil {
+		return nil, fmt.Errorf("provider %s is not a copilot provider", p.Name)
+	}
+	if p.Config.Copilot.GitHubToken == "" {
+		return nil, fmt.Errorf("provider %s has no github token", p.Name)
+	}
+	return p, nil
+}
+
+// oauthHTTPClient builds a short-timeout client honouring the provider network settings,
+// shared by the OAuth/quota handlers (nil network before a provider exists)
+func oauthHTTPClient(network *domain.ProviderConfigNetwork) (*http.Client, error) {
+	return provider.NewOAuthHTTPClient(network)
+}
+
+// saveQuotaToDB saves Copilot quota to database
+func (h *CopilotHandler) saveQuotaToDB(username, plan string, usage *copilot.CopilotUsageResponse, isForbidden bool) {
+	if h.quotaRepo == nil || username == "" {
+		return
+	}
+
+	quota := &domain.CopilotQuota{
+		Username:    username,
+		Plan:        plan,
+		IsForbidden: isForbidden,
+	}
+	if usage != nil {
+		quota.ResetDate = usage.ResetDate
+		quota.Chat = h.convertSnapshot(usage.Chat)
+		quota.Completions = h.convertSnapshot(usage.Completions)
+		quota.PremiumInteractions = h.convertSnapshot(usage.PremiumInteractions)
+	}
+
+	h.quotaRepo.Upsert(quota)
+}
+
+// convertSnapshot converts copilot package snapshot to domain snapshot
+func (h *CopilotHandler) convertSnapshot(s *copilot.CopilotQuotaSnapshot) *domain.CopilotQuotaSnapshot {
+	if s == nil {
+		return nil
+	}
+	return &domain.CopilotQuotaSnapshot{
+		Entitlement:      s.Entitlement,
+		Remaining:        s.Remaining,
+		PercentRemaining: s.PercentRemaining,
+		Unlimited:        s.Unlimited,
+		OverageCount:     s.OverageCount,
+		OveragePermitted: s.OveragePermitted,
+	}
+}
+
+// toSnapshot converts domain snapshot to copilot package snapshot
+func (h *CopilotHandler) toSnapshot(s *domain.CopilotQuotaSnapshot) *copilot.CopilotQuotaSnapshot {
+	if s == nil {
+		return nil
+	}
+	return &copilot.CopilotQuotaSnapshot{
+		Entitlement:      s.Entitlement,
+		Remaining:        s.Remaining,
+		PercentRemaining: s.PercentRemaining,
+		Unlimited:        s.Unlimited,
+		OverageCount:     s.OverageCount,
+		OveragePermitted: s.OveragePermitted,
+	}
+}
+
+// usageToResponse converts usage response to quota response
+func (h *CopilotHandler) usageToResponse(username string, usage *copilot.CopilotUsageResponse) *copilot.CopilotQuotaResponse {
+	return &copilot.CopilotQuotaResponse{
+		Username:            username,
+		Plan:                usage.Plan,
+		ResetDate:           usage.ResetDate,
+		LastUpdated:         time.Now().Unix(),
+		Chat:                usage.Chat,
+		Completions:         usage.Completions,
+		PremiumInteractions: usage.PremiumInteractions,
+	}
+}
+
+// domainQuotaToResponse converts domain.CopilotQuota to response format
+func (h *CopilotHandler) domainQuotaToResponse(q *domain.CopilotQuota) *copilot.CopilotQuotaResponse {
+	return &copilot.CopilotQuotaResponse{
+		Username:            q.Username,
+		Plan:                q.Plan,
+		ResetDate:           q.ResetDate,
+		IsForbidden:         q.IsForbidden,
+		LastUpdated:         q.UpdatedAt.Unix(),
+		Chat:                h.toSnapshot(q.Chat),
+		Completions:         h.toSnapshot(q.Completions),
+		PremiumInteractions: h.toSnapshot(q.PremiumInteractions),
+	}
+}
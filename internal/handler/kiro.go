@@ -56,7 +56,11 @@ func (h *KiroHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // ValidateSocialToken 验证 Social refresh token
 func (h *KiroHandler) ValidateSocialToken(ctx context.Context, refreshToken string) (*kiro.KiroTokenValidationResult, error) {
-	return kiro.ValidateSocialToken(ctx, refreshToken)
+	client, err := oauthHTTPClient(nil)
+	if err != nil {
+		return nil, err
+	}
+	return kiro.ValidateSocialToken(ctx, client, refreshToken)
 }
 
 // handleValidateSocialToken 处理验证 Social token 的 HTTP 请求
@@ -98,8 +102,13 @@ func (h *KiroHandler) GetProviderQuota(ctx context.Context, providerID uint64) (
 
 	config := provider.Config.Kiro
 
+	client, err := oauthHTTPClient(provider.Config.Network)
+	if err != nil {
+		return nil, err
+	}
+
 	// 获取配额
-	quota, err := kiro.FetchQuota(ctx, config.RefreshToken)
+	quota, err := kiro.FetchQuota(ctx, client, config.RefreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch quota: %w", err)
 	}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider/local"
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// LocalHandler handles local model provider API requests
+type LocalHandler struct{}
+
+// NewLocalHandler creates a new local model handler
+func NewLocalHandler() *LocalHandler {
+	return &LocalHandler{}
+}
+
+// LocalDiscoverResult 模型发现结果
+type LocalDiscoverResult struct {
+	Models []string `json:"models"`
+}
+
+// ServeHTTP routes local model requests
+// Routes:
+//
+//	POST /local/discover - 从本地服务发现可用模型（/api/tags 或 /v1/models）
+//
+// 会请求调用方提供的地址，只挂载在需要管理员认证的 /admin/local/ 下
+func (h *LocalHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/local")
+	path = strings.TrimSuffix(path, "/")
+
+	if path == "/discover" && r.Method == http.MethodPost {
+		h.handleDiscover(w, r)
+		return
+	}
+
+	writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+}
+
+// DiscoverModels 从本地服务发现可用模型
+func (h *LocalHandler) DiscoverModels(ctx context.Context, config *domain.ProviderConfigLocal, network *domain.ProviderConfigNetwork) (*LocalDiscoverResult, error) {
+	models, err := local.DiscoverWithNetwork(ctx, config, network)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalDiscoverResult{Models: models}, nil
+}
+
+// handleDiscover 处理模型发现的 HTTP 请求
+func (h *LocalHandler) handleDiscover(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		domain.ProviderConfigLocal
+		Network *domain.ProviderConfigNetwork `json:"network,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(req.BaseURL) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "baseURL is required"})
+		return
+	}
+
+	result, err := h.DiscoverModels(r.Context(), &req.ProviderConfigLocal, req.Network)
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,577 @@
+package handler
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// OIDC configuration environment variables
+	OIDCIssuerEnvKey        = "MAXX_OIDC_ISSUER"
+	OIDCClientIDEnvKey      = "MAXX_OIDC_CLIENT_ID"
+	OIDCClientSecretEnvKey  = "MAXX_OIDC_CLIENT_SECRET"  // optional, public clients rely on PKCE only
+	OIDCRedirectURLEnvKey   = "MAXX_OIDC_REDIRECT_URL"   // required, the request host and forwarded headers are client-controlled
+	OIDCScopesEnvKey        = "MAXX_OIDC_SCOPES"         // space or comma separated, default "openid profile email groups"
+	OIDCGroupsClaimEnvKey   = "MAXX_OIDC_GROUPS_CLAIM"   // default "groups"
+	OIDCAllowedGroupsEnvKey = "MAXX_OIDC_ALLOWED_GROUPS" // comma separated, empty allows every authenticated user
+	OIDCRoleMappingEnvKey   = "MAXX_OIDC_ROLE_MAPPING"   // "group=role,group2=role"
+	OIDCDefaultRoleEnvKey   = "MAXX_OIDC_DEFAULT_ROLE"   // role for users matching no mapping, default "viewer"
+
+	// OIDCCallbackPath is the public callback path registered at the IdP
+	OIDCCallbackPath = "/api/admin/auth/oidc/callback"
+
+	oidcSessionTTL     = 10 * time.Minute
+	oidcDiscoveryTTL   = time.Hour
+	oidcJWKSRefreshMin = time.Minute
+)
+
+// OIDCConfig holds OpenID Connect login settings
+type OIDCConfig struct {
+	Issuer        string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	Scopes        []string
+	GroupsClaim   string
+	AllowedGroups []string
+	RoleMapping   map[string]string // group -> role
+	DefaultRole   string
+}
+
+// LoadOIDCConfigFromEnv reads OIDC settings from the environment.
+// Returns nil when issuer or client ID is not configured.
+func LoadOIDCConfigFromEnv() *OIDCConfig {
+	issuer := strings.TrimSpace(os.Getenv(OIDCIssuerEnvKey))
+	clientID := strings.TrimSpace(os.Getenv(OIDCClientIDEnvKey))
+	if issuer == "" || clientID == "" {
+		return nil
+	}
+
+	cfg := &OIDCConfig{
+		Issuer:        strings.TrimSuffix(issuer, "/"),
+		ClientID:      clientID,
+		ClientSecret:  os.Getenv(OIDCClientSecretEnvKey),
+		RedirectURL:   strings.TrimSpace(os.Getenv(OIDCRedirectURLEnvKey)),
+		Scopes:        splitList(os.Getenv(OIDCScopesEnvKey)),
+		GroupsClaim:   strings.TrimSpace(os.Getenv(OIDCGroupsClaimEnvKey)),
+		AllowedGroups: splitList(os.Getenv(OIDCAllowedGroupsEnvKey)),
+		RoleMapping:   make(map[string]string),
+		DefaultRole:   strings.TrimSpace(os.Getenv(OIDCDefaultRoleEnvKey)),
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email", "groups"}
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	for _, pair := range splitList(os.Getenv(OIDCRoleMappingEnvKey)) {
+		group, role, ok := strings.Cut(pair, "=")
+		if !ok {
+			group, role, ok = strings.Cut(pair, ":")
+		}
+		if ok && IsValidAdminRole(strings.TrimSpace(role)) {
+			cfg.RoleMapping[strings.TrimSpace(group)] = strings.TrimSpace(role)
+		}
+	}
+	if !IsValidAdminRole(cfg.DefaultRole) {
+		cfg.DefaultRole = AdminRoleViewer
+	}
+	return cfg
+}
+
+// Validate checks settings that cannot be derived safely at request time
+func (c *OIDCConfig) Validate() error {
+	if c.RedirectURL == "" {
+		return fmt.Errorf("%s is required when OIDC login is enabled", OIDCRedirectURLEnvKey)
+	}
+	u, err := url.Parse(c.RedirectURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an absolute http(s) URL", OIDCRedirectURLEnvKey)
+	}
+	return nil
+}
+
+// splitList splits a comma or whitespace separated list
+func splitList(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t'
+	})
+	result := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// ResolveRole maps IdP groups to an admin role.
+// Returns ok=false when allowed groups are configured and the user is in none of them.
+func (c *OIDCConfig) ResolveRole(groups []string) (string, bool) {
+	groupSet := make(map[string]struct{}, len(groups))
+	for _, g := range groups {
+		groupSet[g] = struct{}{}
+	}
+
+	if len(c.AllowedGroups) > 0 {
+		allowed := false
+		for _, g := range c.AllowedGroups {
+			if _, ok := groupSet[g]; ok {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return "", false
+		}
+	}
+
+	// Without a mapping every allowed user is a full admin
+	if len(c.RoleMapping) == 0 {
+		return AdminRoleAdmin, true
+	}
+
+	role := ""
+	for group, mapped := range c.RoleMapping {
+		if _, ok := groupSet[group]; !ok {
+			continue
+		}
+		if role == "" || adminRoleRank(mapped) > adminRoleRank(role) {
+			role = mapped
+		}
+	}
+	if role == "" {
+		role = c.DefaultRole
+	}
+	return role, true
+}
+
+// OIDCIdentity is the verified user returned by a successful login
+type OIDCIdentity struct {
+	Subject string   `json:"subject"`
+	Email   string   `json:"email,omitempty"`
+	Name    string   `json:"name,omitempty"`
+	Groups  []string `json:"groups,omitempty"`
+	Role    string   `json:"role"`
+}
+
+// Actor returns the identity used for audit logging
+func (i *OIDCIdentity) Actor() string {
+	if i.Email != "" {
+		return i.Email
+	}
+	return i.Subject
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcSession struct {
+	codeVerifier string
+	nonce        string
+	redirectURL  string
+	expiresAt    time.Time
+}
+
+// OIDCClient implements the authorization code + PKCE flow against an OpenID provider
+type OIDCClient struct {
+	cfg        *OIDCConfig
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	discoveredAt  time.Time
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+
+	sessions sync.Map // state -> *oidcSession
+}
+
+// NewOIDCClient creates a new OIDC client
+func NewOIDCClient(cfg *OIDCConfig) *OIDCClient {
+	return &OIDCClient{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		keys:       make(map[string]crypto.PublicKey),
+	}
+}
+
+// Config returns the client configuration
+func (c *OIDCClient) Config() *OIDCConfig {
+	return c.cfg
+}
+
+// AuthCodeURL starts a login: creates state/nonce/PKCE and returns the IdP authorization URL
+func (c *OIDCClient) AuthCodeURL(ctx context.Context, redirectURL string) (string, error) {
+	disc, err := c.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(hash[:])
+
+	c.cleanupSessions()
+	c.sessions.Store(state, &oidcSession{
+		codeVerifier: verifier,
+		nonce:        nonce,
+		redirectURL:  redirectURL,
+		expiresAt:    time.Now().Add(oidcSessionTTL),
+	})
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", c.cfg.ClientID)
+	params.Set("redirect_uri", redirectURL)
+	params.Set("scope", strings.Join(c.cfg.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", challenge)
+	params.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(disc.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return disc.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange completes a login: redeems the code, verifies the ID token and resolves the role
+func (c *OIDCClient) Exchange(ctx context.Context, state, code string) (*OIDCIdentity, error) {
+	val, ok := c.sessions.LoadAndDelete(state)
+	if !ok {
+		return nil, errors.New("invalid or expired state")
+	}
+	session := val.(*oidcSession)
+	if time.Now().After(session.expiresAt) {
+		return nil, errors.New("invalid or expired state")
+	}
+
+	disc, err := c.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", session.redirectURL)
+	form.Set("client_id", c.cfg.ClientID)
+	form.Set("code_verifier", session.codeVerifier)
+	if c.cfg.ClientSecret != "" {
+		form.Set("client_secret", c.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, disc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tokenResp.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	claims, err := c.verifyIDToken(ctx, tokenResp.IDToken, disc.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	if nonce, _ := claims["nonce"].(string); nonce != session.nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+
+	identity := &OIDCIdentity{
+		Groups: claimStrings(claims[c.cfg.GroupsClaim]),
+	}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+	if identity.Email == "" {
+		identity.Email, _ = claims["preferred_username"].(string)
+	}
+	if identity.Subject == "" {
+		return nil, errors.New("id_token has no subject")
+	}
+
+	role, ok := c.cfg.ResolveRole(identity.Groups)
+	if !ok {
+		return nil, fmt.Errorf("user %s is not a member of an allowed group", identity.Actor())
+	}
+	identity.Role = role
+	return identity, nil
+}
+
+// verifyIDToken checks the ID token signature against the provider JWKS and validates iss/aud/exp
+func (c *OIDCClient) verifyIDToken(ctx context.Context, raw, issuer string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.getKey(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(c.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+	return claims, nil
+}
+
+func (c *OIDCClient) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.discovery != nil && time.Since(c.discoveredAt) < oidcDiscoveryTTL {
+		return c.discovery, nil
+	}
+
+	var disc oidcDiscovery
+	if err := c.getJSON(ctx, c.cfg.Issuer+"/.well-known/openid-configuration", &disc); err != nil {
+		if c.discovery != nil {
+			// keep serving the stale document if the IdP is briefly unavailable
+			return c.discovery, nil
+		}
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if disc.AuthorizationEndpoint == "" || disc.TokenEndpoint == "" || disc.JWKSURI == "" {
+		return nil, errors.New("oidc discovery document is incomplete")
+	}
+	// 发现文档必须属于配置的 Issuer，否则 ID Token 的 iss 校验也会以错误的 Issuer 为准
+	if strings.TrimSuffix(disc.Issuer, "/") != c.cfg.Issuer {
+		return nil, fmt.Errorf("oidc discovery issuer %q does not match configured issuer %q", disc.Issuer, c.cfg.Issuer)
+	}
+
+	c.discovery = &disc
+	c.discoveredAt = time.Now()
+	return c.discovery, nil
+}
+
+// getKey returns the signing key for kid, refreshing the JWKS on a miss (rate limited)
+func (c *OIDCClient) getKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	disc, err := c.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key := c.lookupKeyLocked(kid); key != nil {
+		return key, nil
+	}
+	if time.Since(c.keysFetchedAt) < oidcJWKSRefreshMin && len(c.keys) > 0 {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var jwks struct {
+		Keys []oidcJWK `json:"keys"`
+	}
+	if err := c.getJSON(ctx, disc.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if pub, err := k.publicKey(); err == nil {
+			keys[k.Kid] = pub
+		}
+	}
+	c.keys = keys
+	c.keysFetchedAt = time.Now()
+
+	if key := c.lookupKeyLocked(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (c *OIDCClient) lookupKeyLocked(kid string) crypto.PublicKey {
+	if key, ok := c.keys[kid]; ok {
+		return key
+	}
+	// Tokens without kid are accepted when the provider publishes a single key
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key
+		}
+	}
+	return nil
+}
+
+func (c *OIDCClient) getJSON(ctx context.Context, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned status %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
+
+func (c *OIDCClient) cleanupSessions() {
+	now := time.Now()
+	c.sessions.Range(func(key, value any) bool {
+		if s, ok := value.(*oidcSession); ok && now.After(s.expiresAt) {
+			c.sessions.Delete(key)
+		}
+		return true
+	})
+}
+
+// oidcJWK is a single JSON Web Key (RSA or EC)
+type oidcJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k oidcJWK) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// claimStrings normalizes a string or string-array claim
+func claimStrings(v any) []string {
+	switch t := v.(type) {
+	case string:
+		if t == "" {
+			return nil
+		}
+		return []string{t}
+	case []any:
+		result := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "" {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestOIDCConfig_ResolveRole(t *testing.T) {
+	cfg := &OIDCConfig{
+		AllowedGroups: []string{"maxx-admins", "maxx-users"},
+		RoleMapping:   map[string]string{"maxx-admins": AdminRoleAdmin, "maxx-users": AdminRoleViewer},
+		DefaultRole:   AdminRoleViewer,
+	}
+
+	tests := []struct {
+		name     string
+		groups   []string
+		wantRole string
+		wantOK   bool
+	}{
+		{"admin group", []string{"maxx-admins"}, AdminRoleAdmin, true},
+		{"viewer group", []string{"maxx-users"}, AdminRoleViewer, true},
+		{"highest role wins", []string{"maxx-users", "maxx-admins"}, AdminRoleAdmin, true},
+		{"not allowed", []string{"other"}, "", false},
+		{"no groups", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, ok := cfg.ResolveRole(tt.groups)
+			if role != tt.wantRole || ok != tt.wantOK {
+				t.Fatalf("ResolveRole(%v) = (%q, %v), want (%q, %v)", tt.groups, role, ok, tt.wantRole, tt.wantOK)
+			}
+		})
+	}
+
+	// Without mapping every allowed user is admin
+	open := &OIDCConfig{DefaultRole: AdminRoleViewer}
+	if role, ok := open.ResolveRole(nil); !ok || role != AdminRoleAdmin {
+		t.Fatalf("ResolveRole without mapping = (%q, %v), want (admin, true)", role, ok)
+	}
+}
+
+func TestOIDCClient_AuthCodeFlow(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		idp         *httptest.Server
+		nonce       string
+		challenge   string
+		gotVerifier string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		gotVerifier = r.PostForm.Get("code_verifier")
+		idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":    idp.URL,
+			"aud":    "maxx",
+			"sub":    "user-1",
+			"email":  "alice@example.com",
+			"groups": []string{"maxx-users"},
+			"nonce":  nonce,
+			"exp":    time.Now().Add(time.Hour).Unix(),
+		})
+		idToken.Header["kid"] = "k1"
+		signed, _ := idToken.SignedString(key)
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+	})
+	idp = httptest.NewServer(mux)
+	defer idp.Close()
+
+	client := NewOIDCClient(&OIDCConfig{
+		Issuer:      idp.URL,
+		ClientID:    "maxx",
+		Scopes:      []string{"openid"},
+		GroupsClaim: "groups",
+		RoleMapping: map[string]string{"maxx-users": AdminRoleViewer},
+		DefaultRole: AdminRoleViewer,
+	})
+
+	authURL, err := client.AuthCodeURL(context.Background(), "http://maxx.local"+OIDCCallbackPath)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+	nonce = q.Get("nonce")
+	challenge = q.Get("code_challenge")
+	if q.Get("code_challenge_method") != "S256" || challenge == "" {
+		t.Fatalf("missing PKCE params: %s", authURL)
+	}
+
+	if _, err := client.Exchange(context.Background(), "bogus-state", "code"); err == nil {
+		t.Fatal("expected unknown state to fail")
+	}
+
+	identity, err := client.Exchange(context.Background(), q.Get("state"), "code")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Actor() != "alice@example.com" || identity.Role != AdminRoleViewer {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+	if gotVerifier == "" {
+		t.Fatal("code_verifier not sent to token endpoint")
+	}
+
+	// state is single use
+	if _, err := client.Exchange(context.Background(), q.Get("state"), "code"); err == nil {
+		t.Fatal("expected replayed state to fail")
+	}
+}
+
+func TestAuthMiddleware_ViewerIsReadOnly(t *testing.T) {
+	m := &AuthMiddleware{password: "pw", jwtSecret: []byte("secret")}
+	token, err := m.GenerateTokenFor("bob@example.com", AdminRoleViewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotActor, gotRole string
+	h := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotActor = AdminActor(r)
+		gotRole = AdminRole(r)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/providers", nil)
+	req.Header.Set(AuthHeader, "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || gotActor != "bob@example.com" || gotRole != AdminRoleViewer {
+		t.Fatalf("GET: code=%d actor=%q role=%q", rec.Code, gotActor, gotRole)
+	}
+
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodDelete, "/admin/providers/1"},
+		{http.MethodGet, "/admin/backup/export"},
+		{http.MethodGet, "/admin/providers/export"},
+		{http.MethodGet, "/admin/logs"},
+		{http.MethodGet, "/admin/unknown"},
+	} {
+		req = httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set(AuthHeader, "Bearer "+token)
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("%s %s as viewer: code=%d, want 403", tc.method, tc.path, rec.Code)
+		}
+	}
+}
+
+func TestAuthMiddleware_WrapMutating(t *testing.T) {
+	m := &AuthMiddleware{password: "pw", jwtSecret: []byte("secret")}
+	h := m.WrapMutating(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/copilot/providers/quotas", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET without token: code=%d, want 200", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/copilot/provider/1/refresh", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("POST without token: code=%d, want 401", rec.Code)
+	}
+
+	token, err := m.GenerateTokenFor("alice@example.com", AdminRoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/copilot/provider/1/refresh", nil)
+	req.Header.Set(AuthHeader, "Bearer "+token)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST as admin: code=%d, want 200", rec.Code)
+	}
+}
+
+func TestOIDCConfig_Validate(t *testing.T) {
+	for _, redirect := range []string{"", "/api/admin/auth/oidc/callback", "javascript:alert(1)"} {
+		if err := (&OIDCConfig{RedirectURL: redirect}).Validate(); err == nil {
+			t.Errorf("Validate(%q) should fail", redirect)
+		}
+	}
+	if err := (&OIDCConfig{RedirectURL: "https://maxx.example.com" + OIDCCallbackPath}).Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestOIDCClient_RejectsIssuerMismatch(t *testing.T) {
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 "https://evil.example.com",
+			"authorization_endpoint": "https://evil.example.com/authorize",
+			"token_endpoint":         "https://evil.example.com/token",
+			"jwks_uri":               "https://evil.example.com/jwks",
+		})
+	}))
+	defer idp.Close()
+
+	client := NewOIDCClient(&OIDCConfig{Issuer: idp.URL, ClientID: "maxx", Scopes: []string{"openid"}})
+	if _, err := client.AuthCodeURL(context.Background(), "http://maxx.local"+OIDCCallbackPath); err == nil {
+		t.Fatal("expected discovery with a foreign issuer to fail")
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/service"
+)
+
+// ProjectTokenHandler is the self-service token endpoint for project admins.
+// Requests are authenticated with a project admin API token (Authorization: Bearer maxx_...),
+// and can only list, mint and revoke tokens of that token's project.
+type ProjectTokenHandler struct {
+	svc       *service.AdminService
+	tokenAuth *TokenAuthMiddleware
+}
+
+// NewProjectTokenHandler creates a new project token handler
+func NewProjectTokenHandler(svc *service.AdminService, tokenAuth *TokenAuthMiddleware) *ProjectTokenHandler {
+	return &ProjectTokenHandler{
+		svc:       svc,
+		tokenAuth: tokenAuth,
+	}
+}
+
+// ServeHTTP routes project token requests
+// GET    /project/tokens       list tokens of the project
+// POST   /project/tokens       mint a token
+// DELETE /project/tokens/{id}  revoke a token
+func (h *ProjectTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/project/tokens")
+	path = strings.Trim(path, "/")
+
+	admin, err := h.tokenAuth.AuthenticateProjectAdmin(r)
+	if err != nil {
+		log.Printf("[ProjectTokens] Auth failed: %v", err)
+		writeJSON(w, tokenAuthErrorStatus(err), map[string]string{"error": err.Error()})
+		return
+	}
+
+	var id uint64
+	if path != "" {
+		id, err = strconv.ParseUint(path, 10, 64)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+			return
+		}
+	}
+
+	svc := h.svc.WithActor("token:"+admin.TokenPrefix, trustedClientIP(r))
+
+	switch r.Method {
+	case http.MethodGet:
+		if id > 0 {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		tokens, err := svc.ListProjectAPITokens(admin.ProjectID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		// Plaintext tokens are only returned once at creation
+		result := make([]domain.APIToken, len(tokens))
+		for i, t := range tokens {
+			result[i] = *t
+			result[i].Token = ""
+		}
+		writeJSON(w, http.StatusOK, result)
+	case http.MethodPost:
+		if id > 0 {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		var body struct {
+			Name        string  `json:"name"`
+			Description string  `json:"description"`
+			ExpiresAt   *string `json:"expiresAt"`
+			domain.APITokenScope
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if body.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+			return
+		}
+		var expiresAt *time.Time
+		if body.ExpiresAt != nil && *body.ExpiresAt != "" {
+			t, err := time.Parse(time.RFC3339, *body.ExpiresAt)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid expiresAt format, use RFC3339"})
+				return
+			}
+			expiresAt = &t
+		}
+		result, err := svc.CreateProjectAPIToken(admin, body.Name, body.Description, expiresAt, body.APITokenScope)
+		if err != nil {
+			writeJSON(w, apiTokenErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, result)
+	case http.MethodDelete:
+		if id == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "id required"})
+			return
+		}
+		if err := svc.RevokeProjectAPIToken(admin, id); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "token not found"})
+				return
+			}
+			writeJSON(w, apiTokenErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusNoContent, nil)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
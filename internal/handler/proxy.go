@@ -137,7 +137,7 @@ func (h *ProxyHandler) ingress(c *flow.Ctx) {
 		apiToken, err = h.tokenAuth.ValidateRequest(r, clientType)
 		if err != nil {
 			log.Printf("[Proxy] Token auth failed: %v", err)
-			writeError(w, http.StatusUnauthorized, err.Error())
+			writeError(w, tokenAuthErrorStatus(err), err.Error())
 			c.Abort()
 			return
 		}
@@ -145,11 +145,28 @@ func (h *ProxyHandler) ingress(c *flow.Ctx) {
 			apiTokenID = apiToken.ID
 			log.Printf("[Proxy] Token authenticated: id=%d, name=%s, projectID=%d", apiToken.ID, apiToken.Name, apiToken.ProjectID)
 			c.Set(flow.KeyAPITokenDevMode, apiToken.DevMode)
+			if len(apiToken.AllowedProviderIDs) > 0 {
+				c.Set(flow.KeyAPITokenProviders, apiToken.AllowedProviderIDs)
+			}
+			if len(apiToken.AllowedModels) > 0 {
+				c.Set(flow.KeyAPITokenModels, apiToken.AllowedModels)
+			}
+			if apiToken.Priority != "" {
+				c.Set(flow.KeyAPITokenPriority, apiToken.Priority)
+			}
 		}
 	}
 
 	requestModel := h.clientAdapter.ExtractModel(r, body, clientType)
 	log.Printf("[Proxy] Extracted model: %s (path: %s)", requestModel, r.URL.Path)
+	if h.tokenAuth != nil {
+		if err := h.tokenAuth.AuthorizeModel(apiToken, requestModel); err != nil {
+			log.Printf("[Proxy] Token scope rejected model %s: %v", requestModel, err)
+			writeError(w, tokenAuthErrorStatus(err), err.Error())
+			c.Abort()
+			return
+		}
+	}
 	sessionID := h.clientAdapter.ExtractSessionID(r, body, clientType)
 	stream := h.clientAdapter.IsStreamRequest(r, body)
 
@@ -207,6 +224,14 @@ func (h *ProxyHandler) ingress(c *flow.Ctx) {
 	c.Next()
 }
 
+// tokenAuthErrorStatus maps token auth errors to HTTP status codes
+func tokenAuthErrorStatus(err error) int {
+	if IsTokenScopeError(err) {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
+
 func (h *ProxyHandler) dispatch(c *flow.Ctx) {
 	stream := c.IsStream
 	if v, ok := c.Get(flow.KeyProxyStream); ok {
@@ -219,6 +244,14 @@ func (h *ProxyHandler) dispatch(c *flow.Ctx) {
 	if err == nil {
 		return
 	}
+	if v, ok := c.Get(flow.KeyStreamTerminated); ok {
+		if terminated, _ := v.(bool); terminated {
+			// The executor already closed the stream with an error event
+			c.Err = err
+			c.Abort()
+			return
+		}
+	}
 	proxyErr, ok := err.(*domain.ProxyError)
 	if ok {
 		if stream {
@@ -27,8 +27,22 @@ var (
 	ErrInvalidToken  = errors.New("invalid API token")
 	ErrTokenDisabled = errors.New("API token is disabled")
 	ErrTokenExpired  = errors.New("API token has expired")
+
+	// Scope violations (reported as 403)
+	ErrTokenIPNotAllowed         = errors.New("client IP is not allowed for this API token")
+	ErrTokenClientTypeNotAllowed = errors.New("client type is not allowed for this API token")
+	ErrTokenModelNotAllowed      = errors.New("model is not allowed for this API token")
+	ErrTokenNotProjectAdmin      = errors.New("API token is not a project admin token")
 )
 
+// IsTokenScopeError reports whether err is a scope violation rather than an authentication failure
+func IsTokenScopeError(err error) bool {
+	return errors.Is(err, ErrTokenIPNotAllowed) ||
+		errors.Is(err, ErrTokenClientTypeNotAllowed) ||
+		errors.Is(err, ErrTokenModelNotAllowed) ||
+		errors.Is(err, ErrTokenNotProjectAdmin)
+}
+
 // TokenAuthMiddleware handles API token authentication for proxy requests
 type TokenAuthMiddleware struct {
 	tokenRepo   *cached.APITokenRepository
@@ -119,6 +133,64 @@ func (m *TokenAuthMiddleware) ValidateRequest(req *http.Request, clientType doma
 		return nil, ErrMissingToken
 	}
 
+	apiToken, err := m.lookupToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check scopes
+	if !apiToken.AllowsIP(trustedClientIP(req)) {
+		return nil, ErrTokenIPNotAllowed
+	}
+	if !apiToken.AllowsClientType(clientType) {
+		return nil, ErrTokenClientTypeNotAllowed
+	}
+
+	// Update usage (async to not block request)
+	go func() {
+		if err := m.tokenRepo.IncrementUseCount(apiToken.ID); err != nil {
+			log.Printf("[TokenAuth] Failed to increment token use count for ID %d: %v", apiToken.ID, err)
+		}
+	}()
+
+	return apiToken, nil
+}
+
+// AuthorizeModel checks the requested model against the token scope.
+// A nil token (auth disabled) allows every model.
+func (m *TokenAuthMiddleware) AuthorizeModel(apiToken *domain.APIToken, model string) error {
+	if apiToken == nil || model == "" {
+		return nil
+	}
+	if !apiToken.AllowsModel(model) {
+		return ErrTokenModelNotAllowed
+	}
+	return nil
+}
+
+// AuthenticateProjectAdmin validates a project admin token for the self-service token endpoints.
+// Unlike ValidateRequest this is always enforced, regardless of the proxy token auth setting.
+func (m *TokenAuthMiddleware) AuthenticateProjectAdmin(req *http.Request) (*domain.APIToken, error) {
+	token := strings.TrimSpace(m.ExtractToken(req, ""))
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+
+	apiToken, err := m.lookupToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if !apiToken.ProjectAdmin || apiToken.ProjectID == 0 {
+		return nil, ErrTokenNotProjectAdmin
+	}
+	if !apiToken.AllowsIP(trustedClientIP(req)) {
+		return nil, ErrTokenIPNotAllowed
+	}
+	return apiToken, nil
+}
+
+// lookupToken resolves a plaintext token and checks that it is enabled and not expired
+func (m *TokenAuthMiddleware) lookupToken(token string) (*domain.APIToken, error) {
 	// Check if it's a maxx token
 	if !strings.HasPrefix(token, TokenPrefix) {
 		return nil, ErrInvalidToken
@@ -142,14 +214,6 @@ func (m *TokenAuthMiddleware) ValidateRequest(req *http.Request, clientType doma
 	if apiToken.ExpiresAt != nil && time.Now().After(*apiToken.ExpiresAt) {
 		return nil, ErrTokenExpired
 	}
-
-	// Update usage (async to not block request)
-	go func() {
-		if err := m.tokenRepo.IncrementUseCount(apiToken.ID); err != nil {
-			log.Printf("[TokenAuth] Failed to increment token use count for ID %d: %v", apiToken.ID, err)
-		}
-	}()
-
 	return apiToken, nil
 }
 
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository/cached"
+)
+
+type tokenAuthTestRepo struct {
+	tokens []*domain.APIToken
+}
+
+func (r *tokenAuthTestRepo) Create(t *domain.APIToken) error {
+	t.ID = uint64(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, t)
+	return nil
+}
+
+func (r *tokenAuthTestRepo) Update(t *domain.APIToken) error { return nil }
+
+func (r *tokenAuthTestRepo) Delete(id uint64) error { return nil }
+
+func (r *tokenAuthTestRepo) GetByID(id uint64) (*domain.APIToken, error) {
+	for _, t := range r.tokens {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *tokenAuthTestRepo) GetByToken(token string) (*domain.APIToken, error) {
+	for _, t := range r.tokens {
+		if t.Token == token {
+			return t, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *tokenAuthTestRepo) List() ([]*domain.APIToken, error) { return r.tokens, nil }
+
+func (r *tokenAuthTestRepo) IncrementUseCount(id uint64) error { return nil }
+
+type tokenAuthTestSettings map[string]string
+
+func (s tokenAuthTestSettings) Get(key string) (string, error) { return s[key], nil }
+
+func (s tokenAuthTestSettings) Set(key, value string) error {
+	s[key] = value
+	return nil
+}
+
+func (s tokenAuthTestSettings) GetAll() ([]*domain.SystemSetting, error) { return nil, nil }
+
+func (s tokenAuthTestSettings) Delete(key string) error { return nil }
+
+func newTokenAuthForTests(tokens ...*domain.APIToken) *TokenAuthMiddleware {
+	repo := &tokenAuthTestRepo{}
+	for _, t := range tokens {
+		repo.Create(t)
+	}
+	return NewTokenAuthMiddleware(
+		cached.NewAPITokenRepository(repo),
+		tokenAuthTestSettings{SettingKeyProxyTokenAuthEnabled: "true"},
+	)
+}
+
+func TestTokenAuth_EnforcesScopes(t *testing.T) {
+	m := newTokenAuthForTests(&domain.APIToken{
+		Token:     "maxx_scoped",
+		IsEnabled: true,
+		APITokenScope: domain.APITokenScope{
+			AllowedClientTypes: []domain.ClientType{domain.ClientTypeClaude},
+			AllowedModels:      []string{"claude-sonnet-*"},
+			AllowedIPs:         []string{"203.0.113.0/24"},
+		},
+	})
+
+	newReq := func(remoteAddr string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/v1/messages", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("x-api-key", "maxx_scoped")
+		return req
+	}
+
+	token, err := m.ValidateRequest(newReq("203.0.113.7:5000"), domain.ClientTypeClaude)
+	if err != nil {
+		t.Fatalf("ValidateRequest: %v", err)
+	}
+	if err := m.AuthorizeModel(token, "claude-sonnet-4-5"); err != nil {
+		t.Fatalf("AuthorizeModel allowed model: %v", err)
+	}
+	if err := m.AuthorizeModel(token, "claude-opus-4-1"); !errors.Is(err, ErrTokenModelNotAllowed) {
+		t.Fatalf("AuthorizeModel disallowed model err = %v", err)
+	}
+
+	if _, err := m.ValidateRequest(newReq("203.0.113.7:5000"), domain.ClientTypeOpenAI); !errors.Is(err, ErrTokenClientTypeNotAllowed) {
+		t.Fatalf("client type err = %v", err)
+	}
+	if _, err := m.ValidateRequest(newReq("198.51.100.1:5000"), domain.ClientTypeClaude); !errors.Is(err, ErrTokenIPNotAllowed) {
+		t.Fatalf("ip err = %v", err)
+	}
+
+	// Forwarding headers are ignored from public peers
+	spoofed := newReq("198.51.100.1:5000")
+	spoofed.Header.Set("X-Forwarded-For", "203.0.113.7")
+	if _, err := m.ValidateRequest(spoofed, domain.ClientTypeClaude); !errors.Is(err, ErrTokenIPNotAllowed) {
+		t.Fatalf("spoofed ip err = %v", err)
+	}
+	// ...but honored behind a local reverse proxy
+	proxied := newReq("127.0.0.1:5000")
+	proxied.Header.Set("X-Forwarded-For", "203.0.113.7")
+	if _, err := m.ValidateRequest(proxied, domain.ClientTypeClaude); err != nil {
+		t.Fatalf("proxied request: %v", err)
+	}
+
+	if status := tokenAuthErrorStatus(ErrTokenIPNotAllowed); status != http.StatusForbidden {
+		t.Fatalf("scope error status = %d, want 403", status)
+	}
+	if status := tokenAuthErrorStatus(ErrInvalidToken); status != http.StatusUnauthorized {
+		t.Fatalf("auth error status = %d, want 401", status)
+	}
+}
+
+func TestTokenAuth_AuthenticateProjectAdmin(t *testing.T) {
+	m := newTokenAuthForTests(
+		&domain.APIToken{Token: "maxx_admin", IsEnabled: true, ProjectID: 3, APITokenScope: domain.APITokenScope{ProjectAdmin: true}},
+		&domain.APIToken{Token: "maxx_plain", IsEnabled: true, ProjectID: 3},
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/project/tokens", nil)
+	req.Header.Set("Authorization", "Bearer maxx_admin")
+	token, err := m.AuthenticateProjectAdmin(req)
+	if err != nil || token.ProjectID != 3 {
+		t.Fatalf("AuthenticateProjectAdmin = %+v, %v", token, err)
+	}
+
+	req.Header.Set("Authorization", "Bearer maxx_plain")
+	if _, err := m.AuthenticateProjectAdmin(req); !errors.Is(err, ErrTokenNotProjectAdmin) {
+		t.Fatalf("plain token err = %v", err)
+	}
+}
+
+func TestAPITokenScope_Restrict(t *testing.T) {
+	parent := domain.APITokenScope{
+		AllowedClientTypes: []domain.ClientType{domain.ClientTypeClaude, domain.ClientTypeCodex},
+		AllowedModels:      []string{"claude-*"},
+		ProjectAdmin:       true,
+	}
+
+	inherited, ok := parent.Restrict(domain.APITokenScope{})
+	if !ok || len(inherited.AllowedClientTypes) != 2 || inherited.AllowedModels[0] != "claude-*" || inherited.ProjectAdmin {
+		t.Fatalf("inherited scope = %+v, %v", inherited, ok)
+	}
+
+	narrowed, ok := parent.Restrict(domain.APITokenScope{
+		AllowedClientTypes: []domain.ClientType{domain.ClientTypeClaude},
+		AllowedModels:      []string{"claude-haiku-4-5"},
+	})
+	if !ok || len(narrowed.AllowedClientTypes) != 1 || narrowed.AllowedModels[0] != "claude-haiku-4-5" {
+		t.Fatalf("narrowed scope = %+v, %v", narrowed, ok)
+	}
+
+	if _, ok := parent.Restrict(domain.APITokenScope{AllowedClientTypes: []domain.ClientType{domain.ClientTypeGemini}}); ok {
+		t.Fatal("expected wider client type to be rejected")
+	}
+	if _, ok := parent.Restrict(domain.APITokenScope{AllowedModels: []string{"*"}}); ok {
+		t.Fatal("expected wider model pattern to be rejected")
+	}
+}
+
+func TestTrustedClientIP(t *testing.T) {
+	tests := []struct {
+		remoteAddr string
+		xff        string
+		want       string
+	}{
+		// 公网直连时忽略转发头，避免伪造
+		{"203.0.113.7:5000", "10.1.2.3", "203.0.113.7"},
+		// 经本机或内网反向代理时使用转发头
+		{"127.0.0.1:5000", "198.51.100.9, 10.0.0.1", "198.51.100.9"},
+		{"10.0.0.2:5000", "198.51.100.9", "198.51.100.9"},
+		{"10.0.0.2:5000", "", "10.0.0.2"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/providers", nil)
+		req.RemoteAddr = tt.remoteAddr
+		if tt.xff != "" {
+			req.Header.Set("X-Forwarded-For", tt.xff)
+		}
+		if got := trustedClientIP(req); got != tt.want {
+			t.Errorf("trustedClientIP(%s, xff=%q) = %q, want %q", tt.remoteAddr, tt.xff, got, tt.want)
+		}
+	}
+}
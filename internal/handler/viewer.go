@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/service"
+)
+
+// maskingResponseWriter buffers JSON responses and masks secret fields before sending them.
+// Other content types (CSV/Parquet exports, downloads) pass through unchanged.
+type maskingResponseWriter struct {
+	http.ResponseWriter
+	status   int
+	buffered bool
+	decided  bool
+	buf      bytes.Buffer
+}
+
+func newMaskingResponseWriter(w http.ResponseWriter) *maskingResponseWriter {
+	return &maskingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (w *maskingResponseWriter) WriteHeader(status int) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.status = status
+	w.buffered = strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	if !w.buffered {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *maskingResponseWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.buffered {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buf.Write(b)
+}
+
+// flush sends the masked JSON body, called after the handler returns
+func (w *maskingResponseWriter) flush() {
+	if !w.buffered {
+		return
+	}
+	body := service.MaskSecrets(w.buf.Bytes())
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(body)
+}
@@ -0,0 +1,443 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/cooldown"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/event"
+	"github.com/awsl-project/maxx/internal/flow"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+const (
+	defaultInterval         = 5 * time.Minute
+	defaultTimeout          = 30 * time.Second
+	defaultFailureThreshold = 1
+	// 同时进行的探测数上限，避免一次性打出大量请求
+	maxConcurrentProbes = 4
+)
+
+// AdapterSource provides the cached adapter of a provider (implemented by router.Router)
+type AdapterSource interface {
+	GetAdapter(providerID uint64) (provider.ProviderAdapter, bool)
+}
+
+type stateKey struct {
+	providerID uint64
+	clientType domain.ClientType
+}
+
+type probeState struct {
+	lastRun             time.Time
+	interval            time.Duration
+	consecutiveFailures int
+	last                *domain.ProviderHealthCheck
+}
+
+// target is one probe to run: a provider under a client type
+type target struct {
+	provider   *domain.Provider
+	adapter    provider.ProviderAdapter
+	clientType domain.ClientType
+	config     *domain.ProviderConfigHealthCheck
+}
+
+// Checker sends synthetic probe requests through the provider adapters and applies or clears
+// cooldowns from the results, so broken providers are found before real requests hit them.
+// Probes bypass request logging and billing: their (tiny) token usage is not recorded as usage or cost.
+type Checker struct {
+	providerRepo repository.ProviderRepository
+	routeRepo    repository.RouteRepository
+	checkRepo    repository.ProviderHealthCheckRepository
+	adapters     AdapterSource
+	cooldowns    *cooldown.Manager
+	broadcaster  event.Broadcaster
+
+	mu     sync.Mutex
+	states map[stateKey]*probeState
+}
+
+// NewChecker creates a new health checker
+func NewChecker(
+	providerRepo repository.ProviderRepository,
+	routeRepo repository.RouteRepository,
+	checkRepo repository.ProviderHealthCheckRepository,
+	adapters AdapterSource,
+	broadcaster event.Broadcaster,
+) *Checker {
+	return &Checker{
+		providerRepo: providerRepo,
+		routeRepo:    routeRepo,
+		checkRepo:    checkRepo,
+		adapters:     adapters,
+		cooldowns:    cooldown.Default(),
+		broadcaster:  broadcaster,
+		states:       make(map[stateKey]*probeState),
+	}
+}
+
+// RunDue probes every enabled provider / client type whose interval has elapsed
+// Returns the number of probes sent
+func (c *Checker) RunDue(ctx context.Context, now time.Time) (int, error) {
+	providers, err := c.providerRepo.List()
+	if err != nil {
+		return 0, err
+	}
+
+	var due []*target
+	for _, p := range providers {
+		if p.Config == nil || p.Config.HealthCheck == nil || !p.Config.HealthCheck.Enabled {
+			continue
+		}
+		for _, t := range c.targets(p, p.Config.HealthCheck) {
+			if c.markDue(t, now) {
+				due = append(due, t)
+			}
+		}
+	}
+
+	c.runAll(ctx, due)
+	return len(due), nil
+}
+
+// CheckProvider probes a provider immediately, whether or not scheduled checks are enabled
+func (c *Checker) CheckProvider(ctx context.Context, providerID uint64) ([]*domain.ProviderHealthCheck, error) {
+	p, err := c.providerRepo.GetByID(providerID)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &domain.ProviderConfigHealthCheck{}
+	if p.Config != nil && p.Config.HealthCheck != nil {
+		cfg = p.Config.HealthCheck
+	}
+
+	targets := c.targets(p, cfg)
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%w: provider %d has no adapter or client types to probe", domain.ErrInvalidInput, providerID)
+	}
+	now := time.Now()
+	for _, t := range targets {
+		c.markDue(t, now)
+	}
+	return c.runAll(ctx, targets), nil
+}
+
+// Statuses returns the latest health state of every probed provider / client type
+func (c *Checker) Statuses() []*domain.ProviderHealthStatus {
+	names := make(map[uint64]string)
+	if providers, err := c.providerRepo.List(); err == nil {
+		for _, p := range providers {
+			names[p.ID] = p.Name
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make([]*domain.ProviderHealthStatus, 0, len(c.states))
+	for key, state := range c.states {
+		name, ok := names[key.providerID]
+		if !ok {
+			// Provider 已删除
+			delete(c.states, key)
+			continue
+		}
+		if state.last == nil {
+			continue
+		}
+		result = append(result, &domain.ProviderHealthStatus{
+			ProviderID:          key.providerID,
+			ProviderName:        name,
+			ClientType:          key.clientType,
+			Healthy:             state.last.Success,
+			ConsecutiveFailures: state.consecutiveFailures,
+			LastCheck:           state.last,
+			NextCheckAt:         state.lastRun.Add(state.interval),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ProviderID != result[j].ProviderID {
+			return result[i].ProviderID < result[j].ProviderID
+		}
+		return result[i].ClientType < result[j].ClientType
+	})
+	return result
+}
+
+// History returns the recorded probe results of a provider, newest first
+func (c *Checker) History(providerID uint64, clientType domain.ClientType, limit int) ([]*domain.ProviderHealthCheck, error) {
+	return c.checkRepo.ListByProvider(providerID, clientType, limit)
+}
+
+// targets resolves the client types to probe for a provider
+// Priority: 1) configured client types, 2) client types of routes using the provider, 3) native client types
+func (c *Checker) targets(p *domain.Provider, cfg *domain.ProviderConfigHealthCheck) []*target {
+	a, ok := c.adapters.GetAdapter(p.ID)
+	if !ok {
+		return nil
+	}
+
+	clientTypes := cfg.ClientTypes
+	if len(clientTypes) == 0 && c.routeRepo != nil {
+		if routes, err := c.routeRepo.List(); err == nil {
+			seen := make(map[domain.ClientType]bool)
+			for _, route := range routes {
+				if route.ProviderID == p.ID && route.IsEnabled && !seen[route.ClientType] {
+					seen[route.ClientType] = true
+					clientTypes = append(clientTypes, route.ClientType)
+				}
+			}
+		}
+	}
+	if len(clientTypes) == 0 {
+		clientTypes = a.SupportedClientTypes()
+	}
+
+	targets := make([]*target, 0, len(clientTypes))
+	for _, clientType := range clientTypes {
+		targets = append(targets, &target{provider: p, adapter: a, clientType: clientType, config: cfg})
+	}
+	return targets
+}
+
+// markDue records the run time if the target is due, so the next tick does not pick it again
+func (c *Checker) markDue(t *target, now time.Time) bool {
+	interval := defaultInterval
+	if t.config.IntervalSeconds > 0 {
+		interval = time.Duration(t.config.IntervalSeconds) * time.Second
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state := c.stateLocked(t.provider.ID, t.clientType)
+	state.interval = interval
+	if !state.lastRun.IsZero() && now.Sub(state.lastRun) < interval {
+		return false
+	}
+	state.lastRun = now
+	return true
+}
+
+func (c *Checker) stateLocked(providerID uint64, clientType domain.ClientType) *probeState {
+	key := stateKey{providerID: providerID, clientType: clientType}
+	state, ok := c.states[key]
+	if !ok {
+		state = &probeState{}
+		c.states[key] = state
+	}
+	return state
+}
+
+// runAll runs the probes with bounded concurrency and returns results in target order
+func (c *Checker) runAll(ctx context.Context, targets []*target) []*domain.ProviderHealthCheck {
+	results := make([]*domain.ProviderHealthCheck, len(targets))
+	sem := make(chan struct{}, maxConcurrentProbes)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t *target) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = c.run(ctx, t)
+		}(i, t)
+	}
+	wg.Wait()
+	return results
+}
+
+// run sends one probe, then records the result and updates the cooldown state
+func (c *Checker) run(ctx context.Context, t *target) *domain.ProviderHealthCheck {
+	check, err := c.probe(ctx, t)
+
+	c.mu.Lock()
+	state := c.stateLocked(t.provider.ID, t.clientType)
+	if err == nil {
+		state.consecutiveFailures = 0
+	} else {
+		state.consecutiveFailures++
+	}
+	failures := state.consecutiveFailures
+	c.mu.Unlock()
+
+	cooldownChanged := false
+	if err != nil {
+		cooldownChanged = c.handleFailure(t, check, err, failures)
+	} else {
+		check.Success = true
+		cooldownChanged = c.handleSuccess(t)
+	}
+
+	if c.checkRepo != nil {
+		if err := c.checkRepo.Create(check); err != nil {
+			log.Printf("[Health] Failed to save health check for provider %d: %v", t.provider.ID, err)
+		}
+	}
+
+	c.mu.Lock()
+	c.stateLocked(t.provider.ID, t.clientType).last = check
+	c.mu.Unlock()
+
+	if c.broadcaster != nil {
+		c.broadcaster.BroadcastMessage("provider_health_update", check)
+		if cooldownChanged {
+			c.broadcaster.BroadcastMessage("cooldown_update", map[string]interface{}{
+				"providerID": t.provider.ID,
+			})
+		}
+	}
+	return check
+}
+
+// probe executes the probe request through the adapter
+func (c *Checker) probe(ctx context.Context, t *target) (check *domain.ProviderHealthCheck, err error) {
+	timeout := defaultTimeout
+	if t.config.TimeoutSeconds > 0 {
+		timeout = time.Duration(t.config.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	prompt := t.config.Prompt
+	if prompt == "" {
+		prompt = defaultProbePrompt
+	}
+	maxTokens := t.config.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultProbeMaxTokens
+	}
+
+	model := probeModel(t.provider, t.config, t.clientType)
+	wireType := wireClientType(t.adapter, model, t.clientType)
+	uri, body := buildProbeRequest(wireType, model, prompt, maxTokens)
+
+	check = &domain.ProviderHealthCheck{
+		CreatedAt:  time.Now(),
+		ProviderID: t.provider.ID,
+		ClientType: t.clientType,
+		Model:      model,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(body))
+	if err != nil {
+		return check, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	w := newProbeWriter()
+	fc := flow.NewCtx(w, req)
+	fc.Set(flow.KeyClientType, wireType)
+	fc.Set(flow.KeyOriginalClientType, t.clientType)
+	fc.Set(flow.KeyRequestModel, model)
+	fc.Set(flow.KeyMappedModel, model)
+	fc.Set(flow.KeyRequestBody, body)
+	fc.Set(flow.KeyOriginalRequestBody, body)
+	fc.Set(flow.KeyRequestURI, uri)
+	fc.Set(flow.KeyRequestHeaders, req.Header)
+	fc.Set(flow.KeyIsStream, false)
+
+	// 后台任务中适配器 panic 不能影响其他探测
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("adapter panic: %v", r)
+		}
+		check.Latency = time.Since(check.CreatedAt)
+		check.StatusCode = w.status
+	}()
+
+	if err := t.adapter.Execute(fc, t.provider); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return check, domain.NewProxyErrorWithMessage(context.DeadlineExceeded, true,
+				fmt.Sprintf("probe timed out after %s", timeout))
+		}
+		return check, err
+	}
+	if w.status >= http.StatusBadRequest {
+		return check, fmt.Errorf("upstream returned status %d", w.status)
+	}
+	return check, nil
+}
+
+// handleFailure classifies the error and applies a cooldown once the failure threshold is reached
+func (c *Checker) handleFailure(t *target, check *domain.ProviderHealthCheck, err error, failures int) bool {
+	check.Error = err.Error()
+	reason := cooldown.ReasonUnknown
+	var explicitUntil *time.Time
+	clientType := string(t.clientType)
+	model := ""
+
+	var proxyErr *domain.ProxyError
+	if errors.As(err, &proxyErr) {
+		reason, explicitUntil = cooldown.ClassifyProxyError(proxyErr)
+		if errors.Is(proxyErr.Err, context.DeadlineExceeded) {
+			reason = cooldown.ReasonNetworkError
+		}
+		if check.StatusCode == 0 {
+			check.StatusCode = proxyErr.HTTPStatusCode
+		}
+		if proxyErr.CooldownClientType != "" {
+			clientType = proxyErr.CooldownClientType
+		}
+		if proxyErr.RateLimitInfo != nil && proxyErr.RateLimitInfo.ClientType != "" {
+			clientType = proxyErr.RateLimitInfo.ClientType
+		}
+		if proxyErr.RateLimitInfo != nil {
+			model = proxyErr.RateLimitInfo.Model
+		}
+	}
+	check.Reason = string(reason)
+
+	threshold := t.config.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultFailureThreshold
+	}
+	if t.provider.Config != nil && t.provider.Config.DisableErrorCooldown {
+		return false
+	}
+	if failures < threshold {
+		log.Printf("[Health] Provider %d (clientType=%s) probe failed (%d/%d): %v",
+			t.provider.ID, t.clientType, failures, threshold, err)
+		return false
+	}
+
+	until := c.cooldowns.RecordFailure(t.provider.ID, clientType, model, reason, explicitUntil)
+	check.CooldownUntil = &until
+	log.Printf("[Health] Provider %d (clientType=%s) probe failed, cooled down until %s: %v",
+		t.provider.ID, t.clientType, until.Format("2006-01-02 15:04:05"), err)
+	return true
+}
+
+// handleSuccess clears a server / network error cooldown of the probed client type.
+// Manual freezes, rate limits, quota exhaustion and cooldowns with an end time given by
+// upstream are kept: a cheap probe succeeding says nothing about those limits.
+func (c *Checker) handleSuccess(t *target) bool {
+	reason, ok := c.cooldowns.GetCooldownReason(t.provider.ID, string(t.clientType))
+	if !ok || (reason != cooldown.ReasonServerError && reason != cooldown.ReasonNetworkError) {
+		return false
+	}
+	if c.cooldowns.IsExplicitCooldown(t.provider.ID, string(t.clientType)) {
+		return false
+	}
+	c.cooldowns.RecordSuccess(t.provider.ID, string(t.clientType), "")
+	log.Printf("[Health] Provider %d (clientType=%s) probe succeeded, cooldown (%s) cleared",
+		t.provider.ID, t.clientType, reason)
+	return true
+}
+
+// Cleanup deletes probe results recorded before the given time
+func (c *Checker) Cleanup(before time.Time) (int64, error) {
+	if c.checkRepo == nil {
+		return 0, nil
+	}
+	return c.checkRepo.DeleteOlderThan(before)
+}
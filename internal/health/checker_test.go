@@ -0,0 +1,262 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/cooldown"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/flow"
+)
+
+type fakeAdapter struct {
+	mu        sync.Mutex
+	supported []domain.ClientType
+	err       error
+	status    int
+	calls     []flowCall
+}
+
+type flowCall struct {
+	clientType         domain.ClientType
+	originalClientType domain.ClientType
+	uri                string
+	body               []byte
+}
+
+func (a *fakeAdapter) SupportedClientTypes() []domain.ClientType { return a.supported }
+
+func (a *fakeAdapter) Execute(c *flow.Ctx, _ *domain.Provider) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls = append(a.calls, flowCall{
+		clientType:         flow.GetClientType(c),
+		originalClientType: flow.GetOriginalClientType(c),
+		uri:                flow.GetRequestURI(c),
+		body:               flow.GetRequestBody(c),
+	})
+	if a.err != nil {
+		return a.err
+	}
+	status := a.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	c.Writer.WriteHeader(status)
+	_, _ = c.Writer.Write([]byte(`{}`))
+	return nil
+}
+
+type fakeAdapters map[uint64]provider.ProviderAdapter
+
+func (f fakeAdapters) GetAdapter(id uint64) (provider.ProviderAdapter, bool) {
+	a, ok := f[id]
+	return a, ok
+}
+
+type fakeProviderRepo struct {
+	providers []*domain.Provider
+}
+
+func (r *fakeProviderRepo) Create(*domain.Provider) error { return nil }
+func (r *fakeProviderRepo) Update(*domain.Provider) error { return nil }
+func (r *fakeProviderRepo) Delete(uint64) error           { return nil }
+func (r *fakeProviderRepo) List() ([]*domain.Provider, error) {
+	return r.providers, nil
+}
+func (r *fakeProviderRepo) GetByID(id uint64) (*domain.Provider, error) {
+	for _, p := range r.providers {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+type fakeCheckRepo struct {
+	mu     sync.Mutex
+	checks []*domain.ProviderHealthCheck
+}
+
+func (r *fakeCheckRepo) Create(c *domain.ProviderHealthCheck) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.ID = uint64(len(r.checks) + 1)
+	r.checks = append(r.checks, c)
+	return nil
+}
+
+func (r *fakeCheckRepo) ListByProvider(providerID uint64, clientType domain.ClientType, limit int) ([]*domain.ProviderHealthCheck, error) {
+	return r.checks, nil
+}
+
+func (r *fakeCheckRepo) DeleteOlderThan(time.Time) (int64, error) { return 0, nil }
+
+func newTestChecker(p *domain.Provider, a *fakeAdapter) (*Checker, *fakeCheckRepo) {
+	checkRepo := &fakeCheckRepo{}
+	c := NewChecker(&fakeProviderRepo{providers: []*domain.Provider{p}}, nil, checkRepo,
+		fakeAdapters{p.ID: a}, nil)
+	c.cooldowns = cooldown.NewManager()
+	return c, checkRepo
+}
+
+func TestBuildProbeRequest(t *testing.T) {
+	uri, body := buildProbeRequest(domain.ClientTypeGemini, "gemini-2.5-flash", "ping", 1)
+	if uri != "/v1beta/models/gemini-2.5-flash:generateContent" {
+		t.Errorf("unexpected gemini uri %q", uri)
+	}
+	if !strings.Contains(string(body), `"maxOutputTokens":1`) {
+		t.Errorf("unexpected gemini body %s", body)
+	}
+
+	uri, body = buildProbeRequest(domain.ClientTypeClaude, "claude-haiku-4-5", "hello", 5)
+	var req map[string]any
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatal(err)
+	}
+	if uri != "/v1/messages" || req["model"] != "claude-haiku-4-5" || req["max_tokens"] != float64(5) {
+		t.Errorf("unexpected claude request %s %s", uri, body)
+	}
+}
+
+func TestProbeModel(t *testing.T) {
+	p := &domain.Provider{SupportModels: []string{"claude-*", "claude-sonnet-4-5"}}
+	cfg := &domain.ProviderConfigHealthCheck{}
+	if got := probeModel(p, cfg, domain.ClientTypeClaude); got != "claude-sonnet-4-5" {
+		t.Errorf("should skip wildcard models, got %q", got)
+	}
+	cfg.Models = map[domain.ClientType]string{domain.ClientTypeClaude: "claude-haiku-4-5"}
+	if got := probeModel(p, cfg, domain.ClientTypeClaude); got != "claude-haiku-4-5" {
+		t.Errorf("configured model should win, got %q", got)
+	}
+	if got := probeModel(&domain.Provider{}, cfg, domain.ClientTypeOpenAI); got != defaultProbeModels[domain.ClientTypeOpenAI] {
+		t.Errorf("expected default model, got %q", got)
+	}
+}
+
+func TestCheckerAppliesAndClearsCooldown(t *testing.T) {
+	p := &domain.Provider{ID: 1, Name: "p1", Config: &domain.ProviderConfig{
+		HealthCheck: &domain.ProviderConfigHealthCheck{
+			Enabled:          true,
+			IntervalSeconds:  60,
+			ClientTypes:      []domain.ClientType{domain.ClientTypeClaude},
+			FailureThreshold: 2,
+		},
+	}}
+	a := &fakeAdapter{
+		supported: []domain.ClientType{domain.ClientTypeClaude},
+		err:       &domain.ProxyError{Err: domain.ErrUpstreamError, IsServerError: true, HTTPStatusCode: 502},
+	}
+	c, checkRepo := newTestChecker(p, a)
+	now := time.Now()
+
+	// 第一次失败未达到阈值，不冷冻
+	if n, err := c.RunDue(context.Background(), now); err != nil || n != 1 {
+		t.Fatalf("RunDue = %d, %v", n, err)
+	}
+	if c.cooldowns.IsInCooldown(1, "claude", "") {
+		t.Fatal("should not cool down before reaching the threshold")
+	}
+	// 间隔未到，不重复探测
+	if n, _ := c.RunDue(context.Background(), now.Add(30*time.Second)); n != 0 {
+		t.Fatalf("probe should not run before the interval, ran %d", n)
+	}
+
+	now = now.Add(time.Minute)
+	c.RunDue(context.Background(), now)
+	if reason, ok := c.cooldowns.GetCooldownReason(1, "claude"); !ok || reason != cooldown.ReasonServerError {
+		t.Fatalf("expected server error cooldown, got %q %v", reason, ok)
+	}
+	last := checkRepo.checks[len(checkRepo.checks)-1]
+	if last.Success || last.StatusCode != 502 || last.CooldownUntil == nil || last.Reason != string(cooldown.ReasonServerError) {
+		t.Errorf("unexpected failed check %+v", last)
+	}
+
+	statuses := c.Statuses()
+	if len(statuses) != 1 || statuses[0].Healthy || statuses[0].ConsecutiveFailures != 2 {
+		t.Errorf("unexpected statuses %+v", statuses)
+	}
+
+	// 探测成功后解除冷冻
+	a.err = nil
+	c.RunDue(context.Background(), now.Add(time.Minute))
+	if c.cooldowns.IsInCooldown(1, "claude", "") {
+		t.Error("cooldown should be cleared after a successful probe")
+	}
+	if statuses := c.Statuses(); !statuses[0].Healthy || statuses[0].ConsecutiveFailures != 0 {
+		t.Errorf("unexpected statuses after recovery %+v", statuses[0])
+	}
+}
+
+func TestCheckerKeepsManualCooldown(t *testing.T) {
+	p := &domain.Provider{ID: 2, Name: "p2", Config: &domain.ProviderConfig{}}
+	a := &fakeAdapter{supported: []domain.ClientType{domain.ClientTypeOpenAI}}
+	c, _ := newTestChecker(p, a)
+	c.cooldowns.SetCooldownUntil(2, "openai", time.Now().Add(time.Hour))
+
+	checks, err := c.CheckProvider(context.Background(), 2)
+	if err != nil || len(checks) != 1 || !checks[0].Success {
+		t.Fatalf("CheckProvider = %+v, %v", checks, err)
+	}
+	if !c.cooldowns.IsInCooldown(2, "openai", "") {
+		t.Error("manual freeze must not be cleared by probes")
+	}
+}
+
+func TestCheckerKeepsLimitCooldowns(t *testing.T) {
+	p := &domain.Provider{ID: 3, Name: "p3", Config: &domain.ProviderConfig{}}
+	a := &fakeAdapter{supported: []domain.ClientType{domain.ClientTypeOpenAI}}
+	c, _ := newTestChecker(p, a)
+
+	// 探测成功不能说明限流 / 配额已恢复，上游给出的结束时间也要保留
+	until := time.Now().Add(time.Hour)
+	for _, tt := range []struct {
+		reason   cooldown.CooldownReason
+		explicit *time.Time
+	}{
+		{cooldown.ReasonRateLimit, nil},
+		{cooldown.ReasonQuotaExhausted, &until},
+		{cooldown.ReasonServerError, &until},
+	} {
+		c.cooldowns.ClearCooldown(3, "")
+		c.cooldowns.RecordFailure(3, "openai", "", tt.reason, tt.explicit)
+		if _, err := c.CheckProvider(context.Background(), 3); err != nil {
+			t.Fatalf("CheckProvider: %v", err)
+		}
+		if !c.cooldowns.IsInCooldown(3, "openai", "") {
+			t.Errorf("%s cooldown (explicit=%v) must not be cleared by probes", tt.reason, tt.explicit != nil)
+		}
+	}
+}
+
+func TestCheckerProbesInNativeFormat(t *testing.T) {
+	p := &domain.Provider{ID: 3, Name: "p3", Config: &domain.ProviderConfig{
+		DisableErrorCooldown: true,
+		HealthCheck: &domain.ProviderConfigHealthCheck{
+			ClientTypes: []domain.ClientType{domain.ClientTypeClaude},
+		},
+	}}
+	a := &fakeAdapter{supported: []domain.ClientType{domain.ClientTypeOpenAI}, status: http.StatusUnauthorized}
+	c, _ := newTestChecker(p, a)
+
+	checks, err := c.CheckProvider(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := a.calls[0]
+	if call.clientType != domain.ClientTypeOpenAI || call.originalClientType != domain.ClientTypeClaude || call.uri != "/v1/chat/completions" {
+		t.Errorf("unexpected probe call %+v", call)
+	}
+	if checks[0].Success || checks[0].ClientType != domain.ClientTypeClaude || checks[0].StatusCode != http.StatusUnauthorized {
+		t.Errorf("unexpected check %+v", checks[0])
+	}
+	if c.cooldowns.IsInCooldown(3, "claude", "") {
+		t.Error("DisableErrorCooldown should skip probe cooldowns")
+	}
+}
@@ -0,0 +1,130 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/adapter/provider"
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+const (
+	defaultProbePrompt    = "ping"
+	defaultProbeMaxTokens = 1
+)
+
+// 未配置探测模型且 SupportModels 中没有精确模型时使用的默认模型
+var defaultProbeModels = map[domain.ClientType]string{
+	domain.ClientTypeClaude: "claude-haiku-4-5",
+	domain.ClientTypeOpenAI: "gpt-4o-mini",
+	domain.ClientTypeCodex:  "gpt-5",
+	domain.ClientTypeGemini: "gemini-2.5-flash",
+}
+
+// probeModel picks the model used to probe a provider for a client type
+// Priority: 1) configured model, 2) first exact model in SupportModels, 3) built-in default
+func probeModel(p *domain.Provider, cfg *domain.ProviderConfigHealthCheck, clientType domain.ClientType) string {
+	if model := cfg.Models[clientType]; model != "" {
+		return model
+	}
+	for _, model := range p.SupportModels {
+		if model != "" && !strings.Contains(model, "*") {
+			return model
+		}
+	}
+	return defaultProbeModels[clientType]
+}
+
+// wireClientType returns the format the probe is sent in
+// 探测请求直接按适配器原生格式构造，不经过 converter；冷冻仍记在被探测的客户端类型上
+func wireClientType(a provider.ProviderAdapter, model string, clientType domain.ClientType) domain.ClientType {
+	supported := a.SupportedClientTypes()
+	if modelAware, ok := a.(provider.ModelAwareAdapter); ok {
+		supported = modelAware.SupportedClientTypesForModel(model)
+	}
+	if len(supported) == 0 {
+		return clientType
+	}
+	for _, t := range supported {
+		if t == clientType {
+			return clientType
+		}
+	}
+	return supported[0]
+}
+
+// buildProbeRequest builds the smallest valid non-streaming request of a client type
+// Returns the request URI and body
+func buildProbeRequest(clientType domain.ClientType, model, prompt string, maxTokens int) (string, []byte) {
+	var uri string
+	var body map[string]any
+
+	switch clientType {
+	case domain.ClientTypeClaude:
+		uri = "/v1/messages"
+		body = map[string]any{
+			"model":      model,
+			"max_tokens": maxTokens,
+			"messages":   []map[string]any{{"role": "user", "content": prompt}},
+		}
+	case domain.ClientTypeCodex:
+		// Codex 后端不接受 max_output_tokens
+		uri = "/v1/responses"
+		body = map[string]any{
+			"model": model,
+			"input": []map[string]any{{
+				"type":    "message",
+				"role":    "user",
+				"content": []map[string]any{{"type": "input_text", "text": prompt}},
+			}},
+			"stream": false,
+		}
+	case domain.ClientTypeGemini:
+		uri = "/v1beta/models/" + model + ":generateContent"
+		body = map[string]any{
+			"contents":         []map[string]any{{"role": "user", "parts": []map[string]any{{"text": prompt}}}},
+			"generationConfig": map[string]any{"maxOutputTokens": maxTokens},
+		}
+	default:
+		uri = "/v1/chat/completions"
+		body = map[string]any{
+			"model":      model,
+			"max_tokens": maxTokens,
+			"messages":   []map[string]any{{"role": "user", "content": prompt}},
+		}
+	}
+
+	data, _ := json.Marshal(body)
+	return uri, data
+}
+
+// probeWriter discards the response body, only the status code matters
+type probeWriter struct {
+	header http.Header
+	status int
+}
+
+func newProbeWriter() *probeWriter {
+	return &probeWriter{header: make(http.Header)}
+}
+
+func (w *probeWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *probeWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+}
+
+func (w *probeWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return len(b), nil
+}
+
+// Flush implements http.Flusher for adapters that stream regardless of the request
+func (w *probeWriter) Flush() {}
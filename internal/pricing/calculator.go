@@ -2,6 +2,7 @@ package pricing
 
 import (
 	"log"
+	"strings"
 	"sync"
 
 	"github.com/awsl-project/maxx/internal/domain"
@@ -10,7 +11,8 @@ import (
 
 // CostResult 成本计算结果
 type CostResult struct {
-	Cost         uint64 // 成本（纳美元）
+	Cost         uint64 // 成本（纳美元，已应用倍率）
+	UpstreamCost uint64 // 上游真实成本（纳美元，未应用倍率）
 	ModelPriceID uint64 // 使用的价格记录ID（0 表示使用内置价格表）
 	Multiplier   uint64 // 倍率（10000=1倍）
 }
@@ -20,9 +22,16 @@ type Calculator struct {
 	priceTable *PriceTable
 
 	// 数据库价格缓存
-	modelPriceCache map[string]*domain.ModelPrice // key: modelID
-	modelPriceByID  map[uint64]*domain.ModelPrice // key: price ID
-	useDBPrices     bool                          // 是否使用数据库价格
+	modelPriceCache    map[string]*domain.ModelPrice            // key: modelID（全局价格）
+	providerPriceCache map[uint64]map[string]*domain.ModelPrice // key: providerID -> modelID（Provider 覆盖价格）
+	modelPriceByID     map[uint64]*domain.ModelPrice            // key: price ID
+	useDBPrices        bool                                     // 是否使用数据库价格
+
+	// Provider 统一价格（如本地模型），对该 Provider 的所有模型生效
+	providerPricing map[uint64]*ModelPricing
+
+	// 汇率：1 USD = rate 单位外币，key 为大写币种代码
+	exchangeRates map[string]float64
 
 	mu sync.RWMutex
 }
@@ -44,10 +53,13 @@ func GlobalCalculator() *Calculator {
 // NewCalculator 创建新的计算器
 func NewCalculator(pt *PriceTable) *Calculator {
 	return &Calculator{
-		priceTable:      pt,
-		modelPriceCache: make(map[string]*domain.ModelPrice),
-		modelPriceByID:  make(map[uint64]*domain.ModelPrice),
-		useDBPrices:     false,
+		priceTable:         pt,
+		modelPriceCache:    make(map[string]*domain.ModelPrice),
+		providerPriceCache: make(map[uint64]map[string]*domain.ModelPrice),
+		modelPriceByID:     make(map[uint64]*domain.ModelPrice),
+		useDBPrices:        false,
+		providerPricing:    make(map[uint64]*ModelPricing),
+		exchangeRates:      make(map[string]float64),
 	}
 }
 
@@ -57,14 +69,80 @@ func (c *Calculator) LoadFromDatabase(prices []*domain.ModelPrice) {
 	defer c.mu.Unlock()
 
 	c.modelPriceCache = make(map[string]*domain.ModelPrice, len(prices))
+	c.providerPriceCache = make(map[uint64]map[string]*domain.ModelPrice)
 	c.modelPriceByID = make(map[uint64]*domain.ModelPrice, len(prices))
 
+	var overrides int
 	for _, p := range prices {
-		c.modelPriceCache[p.ModelID] = p
 		c.modelPriceByID[p.ID] = p
+		if p.ProviderID == 0 {
+			c.modelPriceCache[p.ModelID] = p
+			continue
+		}
+		cache, ok := c.providerPriceCache[p.ProviderID]
+		if !ok {
+			cache = make(map[string]*domain.ModelPrice)
+			c.providerPriceCache[p.ProviderID] = cache
+		}
+		cache[p.ModelID] = p
+		overrides++
 	}
 	c.useDBPrices = len(prices) > 0
-	log.Printf("[Pricing] Loaded %d model prices from database", len(prices))
+	log.Printf("[Pricing] Loaded %d model prices from database (%d provider overrides)", len(prices), overrides)
+}
+
+// SetExchangeRates 设置汇率（1 USD = rate 单位外币），用于将非 USD 价格折算为纳美元
+func (c *Calculator) SetExchangeRates(rates map[string]float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.exchangeRates = make(map[string]float64, len(rates))
+	for currency, rate := range rates {
+		if rate > 0 {
+			c.exchangeRates[strings.ToUpper(currency)] = rate
+		}
+	}
+}
+
+// SetProviderPricing 设置 Provider 统一价格，优先于全局价格和内置价格表，但低于 Provider 覆盖价格
+// pricing 为 nil 时移除
+func (c *Calculator) SetProviderPricing(providerID uint64, pricing *ModelPricing) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if pricing == nil {
+		delete(c.providerPricing, providerID)
+		return
+	}
+	c.providerPricing[providerID] = pricing
+}
+
+// ProviderPricing 返回 Provider 的统一价格，没有时返回 nil
+// 本地模型按 Provider 配置计费（默认免费），不使用同名官方模型的价格
+func ProviderPricing(p *domain.Provider) *ModelPricing {
+	if p == nil || p.Config == nil || p.Config.Local == nil {
+		return nil
+	}
+	return &ModelPricing{
+		InputPriceMicro:  p.Config.Local.InputPriceMicro,
+		OutputPriceMicro: p.Config.Local.OutputPriceMicro,
+	}
+}
+
+// ExchangeRate 返回币种对 USD 的汇率，USD 固定为 1
+func (c *Calculator) ExchangeRate(currency string) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.exchangeRateLocked(currency)
+}
+
+func (c *Calculator) exchangeRateLocked(currency string) (float64, bool) {
+	currency = strings.ToUpper(currency)
+	if currency == "" || currency == domain.CurrencyUSD {
+		return 1, true
+	}
+	rate, ok := c.exchangeRates[currency]
+	return rate, ok
 }
 
 // GetModelPrice 获取模型价格（支持前缀匹配），返回价格记录
@@ -75,26 +153,18 @@ func (c *Calculator) GetModelPrice(model string) *domain.ModelPrice {
 	if !c.useDBPrices {
 		return nil
 	}
+	return matchModelPrice(c.modelPriceCache, model)
+}
 
-	// 精确匹配
-	if p, ok := c.modelPriceCache[model]; ok {
-		return p
-	}
-
-	// 前缀匹配：找最长匹配
-	var bestMatch *domain.ModelPrice
-	var bestLen int
+// GetProviderModelPrice 获取 Provider 生效的模型价格：优先 Provider 覆盖价格，其次全局价格
+func (c *Calculator) GetProviderModelPrice(providerID uint64, model string) *domain.ModelPrice {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	for key, price := range c.modelPriceCache {
-		if len(key) > 0 && len(model) >= len(key) && model[:len(key)] == key {
-			if len(key) > bestLen {
-				bestMatch = price
-				bestLen = len(key)
-			}
-		}
+	if !c.useDBPrices {
+		return nil
 	}
-
-	return bestMatch
+	return c.getModelPriceLocked(providerID, model)
 }
 
 // GetModelPriceByID 根据ID获取价格记录
@@ -204,6 +274,13 @@ func (c *Calculator) CalculateWithPricing(pricing *ModelPricing, metrics *usage.
 // metrics: token使用指标
 // multiplier: 倍率（10000=1倍），0 表示使用默认值 10000
 func (c *Calculator) CalculateWithResult(model string, metrics *usage.Metrics, multiplier uint64) CostResult {
+	return c.CalculateForProvider(0, model, metrics, multiplier)
+}
+
+// CalculateForProvider 计算成本，优先使用 Provider 覆盖价格，其次 Provider 统一价格、全局价格，最后内置价格表。
+// 非 USD 价格按汇率折算为纳美元；缺少汇率时成本记为 0 并记录警告日志。
+// providerID: 0 表示只使用全局价格
+func (c *Calculator) CalculateForProvider(providerID uint64, model string, metrics *usage.Metrics, multiplier uint64) CostResult {
 	if metrics == nil {
 		return CostResult{Cost: 0, ModelPriceID: 0, Multiplier: 10000}
 	}
@@ -216,45 +293,76 @@ func (c *Calculator) CalculateWithResult(model string, metrics *usage.Metrics, m
 	defer c.mu.RUnlock()
 
 	// 优先使用数据库价格
+	_, hasProviderPricing := c.providerPricing[providerID]
 	if c.useDBPrices {
-		mp := c.getModelPriceLocked(model)
+		var mp *domain.ModelPrice
+		if hasProviderPricing {
+			mp = matchModelPrice(c.providerPriceCache[providerID], model)
+		} else {
+			mp = c.getModelPriceLocked(providerID, model)
+		}
 		if mp != nil {
 			cost := c.calculateWithModelPrice(mp, metrics)
+			rate, ok := c.exchangeRateLocked(mp.Currency)
+			if !ok {
+				log.Printf("[Pricing] No exchange rate for %s (model %s, price %d), cost will be 0", mp.Currency, model, mp.ID)
+				return CostResult{Cost: 0, ModelPriceID: mp.ID, Multiplier: multiplier}
+			}
+			if rate != 1 {
+				cost = uint64(float64(cost) / rate)
+			}
+			upstream := cost
 			// 应用倍率: cost * multiplier / 10000
 			if multiplier != 10000 {
 				cost = cost * multiplier / 10000
 			}
 			return CostResult{
 				Cost:         cost,
+				UpstreamCost: upstream,
 				ModelPriceID: mp.ID,
 				Multiplier:   multiplier,
 			}
 		}
 	}
 
-	// 回退到内置价格表
-	pricing := c.priceTable.Get(model)
+	// Provider 统一价格，否则回退到内置价格表
+	pricing := c.providerPricing[providerID]
+	if !hasProviderPricing {
+		pricing = c.priceTable.Get(model)
+	}
 	if pricing == nil {
 		log.Printf("[Pricing] Unknown model: %s, cost will be 0", model)
 		return CostResult{Cost: 0, ModelPriceID: 0, Multiplier: multiplier}
 	}
 
 	cost := c.CalculateWithPricing(pricing, metrics)
+	upstream := cost
 	// 应用倍率
 	if multiplier != 10000 {
 		cost = cost * multiplier / 10000
 	}
 	return CostResult{
 		Cost:         cost,
+		UpstreamCost: upstream,
 		ModelPriceID: 0, // 使用内置价格表
 		Multiplier:   multiplier,
 	}
 }
 
-// getModelPriceLocked 获取模型价格（需要持有读锁）
-func (c *Calculator) getModelPriceLocked(model string) *domain.ModelPrice {
+// getModelPriceLocked 获取模型价格（需要持有读锁），Provider 覆盖价格优先于全局价格
+func (c *Calculator) getModelPriceLocked(providerID uint64, model string) *domain.ModelPrice {
+	if providerID > 0 {
+		if p := matchModelPrice(c.providerPriceCache[providerID], model); p != nil {
+			return p
+		}
+	}
+	return matchModelPrice(c.modelPriceCache, model)
+}
+
+// matchModelPrice 在价格缓存中查找模型价格：精确匹配优先，其次最长前缀匹配
+func matchModelPrice(cache map[string]*domain.ModelPrice, model string) *domain.ModelPrice {
 	// 精确匹配
-	if p, ok := c.modelPriceCache[model]; ok {
+	if p, ok := cache[model]; ok {
 		return p
 	}
 
@@ -262,7 +370,7 @@ func (c *Calculator) getModelPriceLocked(model string) *domain.ModelPrice {
 	var bestMatch *domain.ModelPrice
 	var bestLen int
 
-	for key, price := range c.modelPriceCache {
+	for key, price := range cache {
 		if len(key) > 0 && len(model) >= len(key) && model[:len(key)] == key {
 			if len(key) > bestLen {
 				bestMatch = price
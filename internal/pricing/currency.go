@@ -0,0 +1,47 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// NormalizeCurrency 规范化币种代码，空值视为 USD
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return domain.CurrencyUSD, nil
+	}
+	if !currencyCodePattern.MatchString(currency) {
+		return "", fmt.Errorf("invalid currency %q, use an ISO 4217 code like CNY", currency)
+	}
+	return currency, nil
+}
+
+// ParseExchangeRates 解析汇率设置，格式为 JSON 对象 {"CNY": 7.2}，表示 1 USD = 7.2 CNY
+func ParseExchangeRates(value string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	if strings.TrimSpace(value) == "" {
+		return rates, nil
+	}
+	var raw map[string]float64
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates, expected JSON like {\"CNY\": 7.2}: %w", err)
+	}
+	for currency, rate := range raw {
+		code, err := NormalizeCurrency(currency)
+		if err != nil {
+			return nil, err
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("exchange rate for %s must be positive", code)
+		}
+		rates[code] = rate
+	}
+	return rates, nil
+}
@@ -0,0 +1,342 @@
+package pricing
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// SheetFormat 价格表格式
+type SheetFormat string
+
+const (
+	// SheetFormatJSON maxx 自身的价格 JSON 数组（与 /admin/model-prices 返回结构一致）
+	SheetFormatJSON SheetFormat = "json"
+	// SheetFormatCSV 表头为 model,input,output[,cache_read,cache_write_5m,cache_write_1h,currency]，价格单位为每百万 token 的货币单位
+	SheetFormatCSV SheetFormat = "csv"
+	// SheetFormatLiteLLM LiteLLM 的 model_prices_and_context_window.json，价格单位为每 token 的美元
+	SheetFormatLiteLLM SheetFormat = "litellm"
+)
+
+// SheetOptions 价格表导入选项
+type SheetOptions struct {
+	Format     SheetFormat // 为空时自动识别
+	ProviderID uint64      // 0 表示导入为全局价格
+	Currency   string      // 行内未指定币种时使用，为空表示 USD
+}
+
+// SheetEntry 价格表中被跳过的条目
+type SheetEntry struct {
+	Model  string `json:"model"`
+	Reason string `json:"reason"`
+}
+
+// Sheet 解析后的价格表
+type Sheet struct {
+	Format  SheetFormat          `json:"format"`
+	Prices  []*domain.ModelPrice `json:"prices"`
+	Skipped []SheetEntry         `json:"skipped"`
+}
+
+// DetectSheetFormat 根据内容识别价格表格式
+func DetectSheetFormat(data []byte) SheetFormat {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return SheetFormatJSON
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return SheetFormatLiteLLM
+	default:
+		return SheetFormatCSV
+	}
+}
+
+// ParseSheet 解析价格表，返回待导入的价格记录（尚未写入数据库）
+func ParseSheet(data []byte, opts SheetOptions) (*Sheet, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	format := opts.Format
+	if format == "" {
+		format = DetectSheetFormat(data)
+	}
+	currency, err := NormalizeCurrency(opts.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &Sheet{Format: format, Prices: []*domain.ModelPrice{}, Skipped: []SheetEntry{}}
+	switch format {
+	case SheetFormatJSON:
+		err = parseJSONSheet(data, currency, sheet)
+	case SheetFormatCSV:
+		err = parseCSVSheet(data, currency, sheet)
+	case SheetFormatLiteLLM:
+		err = parseLiteLLMSheet(data, sheet)
+	default:
+		return nil, fmt.Errorf("unknown price sheet format %q (use json, csv or litellm)", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 同一模型出现多次时保留最后一条
+	seen := make(map[string]int, len(sheet.Prices))
+	prices := sheet.Prices[:0]
+	for _, p := range sheet.Prices {
+		p.ProviderID = opts.ProviderID
+		p.Source = domain.PriceSourceImport + ":" + string(format)
+		if i, ok := seen[p.ModelID]; ok {
+			prices[i] = p
+			continue
+		}
+		seen[p.ModelID] = len(prices)
+		prices = append(prices, p)
+	}
+	sheet.Prices = prices
+	sort.Slice(sheet.Prices, func(i, j int) bool { return sheet.Prices[i].ModelID < sheet.Prices[j].ModelID })
+	sort.Slice(sheet.Skipped, func(i, j int) bool { return sheet.Skipped[i].Model < sheet.Skipped[j].Model })
+	return sheet, nil
+}
+
+func parseJSONSheet(data []byte, currency string, sheet *Sheet) error {
+	var prices []*domain.ModelPrice
+	if err := json.Unmarshal(data, &prices); err != nil {
+		return fmt.Errorf("invalid price JSON: %w", err)
+	}
+	for _, p := range prices {
+		if p == nil {
+			continue
+		}
+		p.ModelID = strings.TrimSpace(p.ModelID)
+		if p.ModelID == "" {
+			sheet.Skipped = append(sheet.Skipped, SheetEntry{Reason: "missing modelId"})
+			continue
+		}
+		c := currency
+		if p.Currency != "" {
+			var err error
+			if c, err = NormalizeCurrency(p.Currency); err != nil {
+				return fmt.Errorf("model %s: %w", p.ModelID, err)
+			}
+		}
+		p.ID = 0
+		p.CreatedAt = time.Time{}
+		p.Currency = c
+		sheet.Prices = append(sheet.Prices, p)
+	}
+	return nil
+}
+
+// csvColumns 支持的 CSV 列名（含常见别名）
+var csvColumns = map[string]string{
+	"model":          "model",
+	"model_id":       "model",
+	"input":          "input",
+	"output":         "output",
+	"cache_read":     "cache_read",
+	"cache_write":    "cache_write_5m",
+	"cache_write_5m": "cache_write_5m",
+	"cache_write_1h": "cache_write_1h",
+	"currency":       "currency",
+}
+
+func parseCSVSheet(data []byte, currency string, sheet *Sheet) error {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("empty price CSV")
+		}
+		return fmt.Errorf("invalid price CSV: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if col, ok := csvColumns[key]; ok {
+			index[col] = i
+		}
+	}
+	for _, required := range []string{"model", "input", "output"} {
+		if _, ok := index[required]; !ok {
+			return fmt.Errorf("price CSV is missing column %q", required)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid price CSV: %w", err)
+		}
+		field := func(col string) string {
+			i, ok := index[col]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		model := field("model")
+		if model == "" {
+			continue
+		}
+		p := &domain.ModelPrice{ModelID: model, Currency: currency}
+		if c := field("currency"); c != "" {
+			if p.Currency, err = NormalizeCurrency(c); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		for col, dst := range map[string]*uint64{
+			"input":          &p.InputPriceMicro,
+			"output":         &p.OutputPriceMicro,
+			"cache_read":     &p.CacheReadPriceMicro,
+			"cache_write_5m": &p.Cache5mWritePriceMicro,
+			"cache_write_1h": &p.Cache1hWritePriceMicro,
+		} {
+			v := field(col)
+			if v == "" {
+				continue
+			}
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || f < 0 || math.IsInf(f, 0) {
+				return fmt.Errorf("line %d: invalid %s price %q", line, col, v)
+			}
+			*dst = uint64(math.Round(f * 1_000_000))
+		}
+		if p.InputPriceMicro == 0 && p.OutputPriceMicro == 0 {
+			sheet.Skipped = append(sheet.Skipped, SheetEntry{Model: model, Reason: "no input/output price"})
+			continue
+		}
+		sheet.Prices = append(sheet.Prices, p)
+	}
+}
+
+// liteLLMEntry LiteLLM 价格条目中用到的字段（价格单位：美元/token）
+type liteLLMEntry struct {
+	Mode                          string   `json:"mode"`
+	InputCostPerToken             *float64 `json:"input_cost_per_token"`
+	OutputCostPerToken            *float64 `json:"output_cost_per_token"`
+	CacheReadInputTokenCost       *float64 `json:"cache_read_input_token_cost"`
+	CacheCreationInputTokenCost   *float64 `json:"cache_creation_input_token_cost"`
+	CacheCreationInputTokenCost1h *float64 `json:"cache_creation_input_token_cost_above_1hr"`
+	InputCostAbove200k            *float64 `json:"input_cost_per_token_above_200k_tokens"`
+	OutputCostAbove200k           *float64 `json:"output_cost_per_token_above_200k_tokens"`
+}
+
+// liteLLMModes 只导入对话类模型，跳过 embedding / image 等
+var liteLLMModes = map[string]bool{"": true, "chat": true, "completion": true, "responses": true}
+
+func parseLiteLLMSheet(data []byte, sheet *Sheet) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid LiteLLM price JSON: %w", err)
+	}
+	for model, msg := range raw {
+		if model == "sample_spec" {
+			continue
+		}
+		// 带 provider 前缀的条目（如 bedrock/...）与上游返回的模型名不匹配
+		if strings.Contains(model, "/") {
+			sheet.Skipped = append(sheet.Skipped, SheetEntry{Model: model, Reason: "provider-prefixed model"})
+			continue
+		}
+		var e liteLLMEntry
+		if err := json.Unmarshal(msg, &e); err != nil {
+			sheet.Skipped = append(sheet.Skipped, SheetEntry{Model: model, Reason: "invalid entry"})
+			continue
+		}
+		if !liteLLMModes[e.Mode] {
+			sheet.Skipped = append(sheet.Skipped, SheetEntry{Model: model, Reason: "mode " + e.Mode})
+			continue
+		}
+		if e.InputCostPerToken == nil || e.OutputCostPerToken == nil {
+			sheet.Skipped = append(sheet.Skipped, SheetEntry{Model: model, Reason: "no input/output price"})
+			continue
+		}
+
+		p := &domain.ModelPrice{
+			ModelID:          model,
+			Currency:         domain.CurrencyUSD,
+			InputPriceMicro:  perTokenToMicro(e.InputCostPerToken),
+			OutputPriceMicro: perTokenToMicro(e.OutputCostPerToken),
+		}
+		p.CacheReadPriceMicro = perTokenToMicro(e.CacheReadInputTokenCost)
+		p.Cache5mWritePriceMicro = perTokenToMicro(e.CacheCreationInputTokenCost)
+		p.Cache1hWritePriceMicro = perTokenToMicro(e.CacheCreationInputTokenCost1h)
+		if e.InputCostAbove200k != nil && p.InputPriceMicro > 0 {
+			p.Has1MContext = true
+			p.Context1MThreshold = 200_000
+			p.InputPremiumNum, p.InputPremiumDenom = premiumFraction(perTokenToMicro(e.InputCostAbove200k), p.InputPriceMicro)
+			if e.OutputCostAbove200k != nil && p.OutputPriceMicro > 0 {
+				p.OutputPremiumNum, p.OutputPremiumDenom = premiumFraction(perTokenToMicro(e.OutputCostAbove200k), p.OutputPriceMicro)
+			} else {
+				p.OutputPremiumNum, p.OutputPremiumDenom = 1, 1
+			}
+		}
+		sheet.Prices = append(sheet.Prices, p)
+	}
+	return nil
+}
+
+// perTokenToMicro 将美元/token 转换为 microUSD/M tokens
+func perTokenToMicro(v *float64) uint64 {
+	if v == nil || *v <= 0 {
+		return 0
+	}
+	return uint64(math.Round(*v * 1e12))
+}
+
+// premiumFraction 将超阈值价格与基础价格之比化简为分数（精度 1/100）
+func premiumFraction(premium, base uint64) (uint64, uint64) {
+	num, denom := uint64(math.Round(float64(premium)*100/float64(base))), uint64(100)
+	if num == 0 {
+		return 1, 1
+	}
+	a, b := num, denom
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return num / a, denom / a
+}
+
+// SamePrice 判断两条价格记录的计费参数是否一致（忽略 ID、版本和来源）
+func SamePrice(a, b *domain.ModelPrice) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	currency := func(c string) string {
+		if c == "" {
+			return domain.CurrencyUSD
+		}
+		return strings.ToUpper(c)
+	}
+	return a.ModelID == b.ModelID &&
+		a.ProviderID == b.ProviderID &&
+		currency(a.Currency) == currency(b.Currency) &&
+		a.InputPriceMicro == b.InputPriceMicro &&
+		a.OutputPriceMicro == b.OutputPriceMicro &&
+		a.CacheReadPriceMicro == b.CacheReadPriceMicro &&
+		a.Cache5mWritePriceMicro == b.Cache5mWritePriceMicro &&
+		a.Cache1hWritePriceMicro == b.Cache1hWritePriceMicro &&
+		a.Has1MContext == b.Has1MContext &&
+		a.Context1MThreshold == b.Context1MThreshold &&
+		a.InputPremiumNum == b.InputPremiumNum &&
+		a.InputPremiumDenom == b.InputPremiumDenom &&
+		a.OutputPremiumNum == b.OutputPremiumNum &&
+		a.OutputPremiumDenom == b.OutputPremiumDenom
+}
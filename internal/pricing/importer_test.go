@@ -0,0 +1,161 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/usage"
+)
+
+func TestParseSheetCSV(t *testing.T) {
+	data := []byte("\xef\xbb\xbfModel,Input,Output,Cache_Read,Currency\n" +
+		"# relay price list\n" +
+		"claude-sonnet-4,21.6,108,2.16,\n" +
+		"gpt-4o,2.5,10,,USD\n" +
+		"free-model,0,0,,\n" +
+		"gpt-4o,3,12,,usd\n")
+	sheet, err := ParseSheet(data, SheetOptions{ProviderID: 7, Currency: "cny"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sheet.Format != SheetFormatCSV || len(sheet.Prices) != 2 || len(sheet.Skipped) != 1 {
+		t.Fatalf("unexpected sheet %+v", sheet)
+	}
+	claude := sheet.Prices[0]
+	if claude.ModelID != "claude-sonnet-4" || claude.Currency != "CNY" || claude.ProviderID != 7 ||
+		claude.InputPriceMicro != 21_600_000 || claude.OutputPriceMicro != 108_000_000 ||
+		claude.CacheReadPriceMicro != 2_160_000 || claude.Source != "import:csv" {
+		t.Fatalf("unexpected claude price %+v", claude)
+	}
+	// 重复模型保留最后一条
+	if gpt := sheet.Prices[1]; gpt.Currency != "USD" || gpt.InputPriceMicro != 3_000_000 {
+		t.Fatalf("unexpected gpt price %+v", gpt)
+	}
+
+	if _, err := ParseSheet([]byte("model,input\nx,1\n"), SheetOptions{}); err == nil {
+		t.Fatal("expected missing column error")
+	}
+	if _, err := ParseSheet([]byte("model,input,output\nx,abc,1\n"), SheetOptions{}); err == nil {
+		t.Fatal("expected invalid price error")
+	}
+}
+
+func TestParseSheetLiteLLM(t *testing.T) {
+	data := []byte(`{
+		"sample_spec": {"input_cost_per_token": 0},
+		"claude-sonnet-4-20250514": {
+			"mode": "chat",
+			"input_cost_per_token": 3e-06,
+			"output_cost_per_token": 1.5e-05,
+			"cache_read_input_token_cost": 3e-07,
+			"cache_creation_input_token_cost": 3.75e-06,
+			"input_cost_per_token_above_200k_tokens": 6e-06,
+			"output_cost_per_token_above_200k_tokens": 2.25e-05
+		},
+		"bedrock/anthropic.claude-v2": {"mode": "chat", "input_cost_per_token": 8e-06, "output_cost_per_token": 2.4e-05},
+		"text-embedding-3-small": {"mode": "embedding", "input_cost_per_token": 2e-08, "output_cost_per_token": 0},
+		"unpriced-model": {"mode": "chat"}
+	}`)
+	sheet, err := ParseSheet(data, SheetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sheet.Format != SheetFormatLiteLLM || len(sheet.Prices) != 1 || len(sheet.Skipped) != 3 {
+		t.Fatalf("unexpected sheet %+v", sheet)
+	}
+	p := sheet.Prices[0]
+	if p.InputPriceMicro != 3_000_000 || p.OutputPriceMicro != 15_000_000 || p.CacheReadPriceMicro != 300_000 ||
+		p.Cache5mWritePriceMicro != 3_750_000 || p.Currency != domain.CurrencyUSD || p.Source != "import:litellm" {
+		t.Fatalf("unexpected price %+v", p)
+	}
+	if !p.Has1MContext || p.Context1MThreshold != 200_000 ||
+		p.InputPremiumNum != 2 || p.InputPremiumDenom != 1 || p.OutputPremiumNum != 3 || p.OutputPremiumDenom != 2 {
+		t.Fatalf("unexpected 1M context pricing %+v", p)
+	}
+}
+
+func TestParseSheetJSON(t *testing.T) {
+	data := []byte(`[{"id": 99, "modelId": "glm-4", "inputPriceMicro": 5000000, "outputPriceMicro": 5000000}, {"modelId": ""}]`)
+	sheet, err := ParseSheet(data, SheetOptions{Currency: "CNY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sheet.Format != SheetFormatJSON || len(sheet.Prices) != 1 || len(sheet.Skipped) != 1 {
+		t.Fatalf("unexpected sheet %+v", sheet)
+	}
+	if p := sheet.Prices[0]; p.ID != 0 || p.Currency != "CNY" || p.Source != "import:json" {
+		t.Fatalf("unexpected price %+v", p)
+	}
+}
+
+func TestCalculateForProvider(t *testing.T) {
+	c := NewCalculator(DefaultPriceTable())
+	c.LoadFromDatabase([]*domain.ModelPrice{
+		{ID: 1, ModelID: "claude-sonnet-4", InputPriceMicro: 3_000_000, OutputPriceMicro: 15_000_000},
+		{ID: 2, ModelID: "claude-sonnet-4", ProviderID: 5, Currency: "CNY", InputPriceMicro: 14_400_000, OutputPriceMicro: 72_000_000},
+	})
+	metrics := &usage.Metrics{InputTokens: 1_000_000}
+
+	global := c.CalculateForProvider(0, "claude-sonnet-4-20250514", metrics, 0)
+	if global.ModelPriceID != 1 || global.Cost != 3_000_000_000 {
+		t.Fatalf("unexpected global cost %+v", global)
+	}
+	// 其他 Provider 使用全局价格
+	if other := c.CalculateForProvider(6, "claude-sonnet-4-20250514", metrics, 0); other.ModelPriceID != 1 {
+		t.Fatalf("unexpected fallback %+v", other)
+	}
+
+	// 缺少汇率时成本记为 0
+	missing := c.CalculateForProvider(5, "claude-sonnet-4-20250514", metrics, 0)
+	if missing.ModelPriceID != 2 || missing.Cost != 0 {
+		t.Fatalf("expected zero cost without exchange rate, got %+v", missing)
+	}
+
+	c.SetExchangeRates(map[string]float64{"cny": 7.2})
+	override := c.CalculateForProvider(5, "claude-sonnet-4-20250514", metrics, 5000)
+	// ¥14.4 / 7.2 = $2，倍率 0.5 后为 $1
+	if override.ModelPriceID != 2 || override.Cost != 1_000_000_000 || override.UpstreamCost != 2_000_000_000 {
+		t.Fatalf("unexpected override cost %+v", override)
+	}
+}
+
+func TestProviderPricing(t *testing.T) {
+	c := NewCalculator(DefaultPriceTable())
+	c.LoadFromDatabase([]*domain.ModelPrice{
+		{ID: 1, ModelID: "gpt-4o", InputPriceMicro: 2_500_000, OutputPriceMicro: 10_000_000},
+		{ID: 2, ModelID: "qwen3", ProviderID: 9, InputPriceMicro: 1_000_000},
+	})
+	metrics := &usage.Metrics{InputTokens: 1_000_000, OutputTokens: 1_000_000}
+
+	// 本地 Provider 免费：不使用全局价格和内置价格表
+	c.SetProviderPricing(9, &ModelPricing{})
+	if free := c.CalculateForProvider(9, "gpt-4o", metrics, 0); free.Cost != 0 || free.ModelPriceID != 0 {
+		t.Fatalf("expected zero cost, got %+v", free)
+	}
+	// Provider 覆盖价格优先于统一价格
+	if override := c.CalculateForProvider(9, "qwen3:8b", metrics, 0); override.ModelPriceID != 2 || override.Cost != 1_000_000_000 {
+		t.Fatalf("unexpected override cost %+v", override)
+	}
+
+	c.SetProviderPricing(9, &ModelPricing{InputPriceMicro: 100_000, OutputPriceMicro: 200_000})
+	if flat := c.CalculateForProvider(9, "llama3.1:8b", metrics, 0); flat.Cost != 300_000_000 {
+		t.Fatalf("unexpected flat cost %+v", flat)
+	}
+
+	c.SetProviderPricing(9, nil)
+	if global := c.CalculateForProvider(9, "gpt-4o", metrics, 0); global.ModelPriceID != 1 {
+		t.Fatalf("expected global price after removal, got %+v", global)
+	}
+}
+
+func TestParseExchangeRates(t *testing.T) {
+	rates, err := ParseExchangeRates(`{"cny": 7.2, "EUR": 0.92}`)
+	if err != nil || rates["CNY"] != 7.2 || rates["EUR"] != 0.92 {
+		t.Fatalf("unexpected rates %v, %v", rates, err)
+	}
+	for _, bad := range []string{`{"CNY": 0}`, `{"RMB1": 7}`, `CNY=7.2`} {
+		if _, err := ParseExchangeRates(bad); err == nil {
+			t.Fatalf("expected error for %s", bad)
+		}
+	}
+}
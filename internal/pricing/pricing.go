@@ -184,6 +184,8 @@ func ConvertToDBPrices(pt *PriceTable) []*domain.ModelPrice {
 	for _, mp := range pt.Models {
 		price := &domain.ModelPrice{
 			ModelID:                mp.ModelID,
+			Currency:               domain.CurrencyUSD,
+			Source:                 domain.PriceSourceDefault,
 			InputPriceMicro:        mp.InputPriceMicro,
 			OutputPriceMicro:       mp.OutputPriceMicro,
 			CacheReadPriceMicro:    mp.CacheReadPriceMicro,
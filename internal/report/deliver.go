@@ -0,0 +1,150 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/export"
+)
+
+// webhookPayload is POSTed to the report webhook after generation
+type webhookPayload struct {
+	Event  string  `json:"event"`
+	Period Period  `json:"period"`
+	Dir    string  `json:"dir"`
+	Files  []*File `json:"files"`
+}
+
+func (g *Generator) sendWebhook(url string, result *Result) error {
+	body, err := json.Marshal(webhookPayload{Event: "billing_report", Period: result.Period, Dir: result.Dir, Files: result.Files})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// sendEmail sends the summary with the report files attached through an SMTP
+// relay. With a username configured it authenticates with PLAIN, which net/smtp
+// only allows over TLS (STARTTLS) or to localhost.
+func sendEmail(cfg *Config, result *Result) error {
+	from := cfg.EmailFrom
+	if from == "" {
+		from = "maxx@localhost"
+	}
+	msg, err := buildEmail(from, cfg.EmailTo, result)
+	if err != nil {
+		return err
+	}
+	auth, err := smtpAuth(cfg)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(cfg.SMTPAddr, auth, from, cfg.EmailTo, msg)
+}
+
+// smtpAuth returns PLAIN auth for the configured credentials, nil without a username
+func smtpAuth(cfg *Config) (smtp.Auth, error) {
+	if cfg.SMTPUsername == "" {
+		return nil, nil
+	}
+	host, _, err := net.SplitHostPort(cfg.SMTPAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %w", cfg.SMTPAddr, err)
+	}
+	return smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, host), nil
+}
+
+func buildEmail(from string, to []string, result *Result) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	p := result.Period
+	subject := fmt.Sprintf("[maxx] %s billing report %s", p.Schedule, p.Key)
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary())
+
+	text, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := text.Write([]byte(summaryText(result))); err != nil {
+		return nil, err
+	}
+
+	for _, f := range result.Files {
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(f.Path)
+		contentType := export.FormatCSV.ContentType()
+		if strings.HasSuffix(name, export.FormatParquet.Extension()) {
+			contentType = export.FormatParquet.ContentType()
+		}
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("%s; name=%q", contentType, name)},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", name)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(data)
+		for len(encoded) > 76 {
+			if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+				return nil, err
+			}
+			encoded = encoded[76:]
+		}
+		if _, err := part.Write([]byte(encoded + "\r\n")); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func summaryText(result *Result) string {
+	var b strings.Builder
+	p := result.Period
+	fmt.Fprintf(&b, "Billing report %s (%s - %s)\r\n\r\n", p.Key, p.Start.Format("2006-01-02"), p.End.Add(-time.Second).Format("2006-01-02"))
+	if len(result.Files) == 0 {
+		b.WriteString("No usage in this period.\r\n")
+		return b.String()
+	}
+	for _, f := range result.Files {
+		fmt.Fprintf(&b, "%-9s #%-4d %-30s requests=%d input=%d output=%d cost=$%.4f billed=$%.4f\r\n",
+			f.Dimension, f.ID, f.Name, f.TotalRequests, f.InputTokens, f.OutputTokens, float64(f.Cost)/1e9, float64(f.BilledCost)/1e9)
+	}
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"fmt"
+	"time"
+)
+
+// Schedule is how often billing reports are generated
+type Schedule string
+
+const (
+	ScheduleWeekly  Schedule = "weekly"
+	ScheduleMonthly Schedule = "monthly"
+)
+
+// ParseSchedule parses a schedule setting; empty means disabled
+func ParseSchedule(s string) (Schedule, error) {
+	switch Schedule(s) {
+	case "", ScheduleWeekly, ScheduleMonthly:
+		return Schedule(s), nil
+	default:
+		return "", fmt.Errorf("unknown report schedule %q (use weekly or monthly)", s)
+	}
+}
+
+// Period is a closed reporting period [Start, End)
+type Period struct {
+	Schedule Schedule  `json:"schedule"`
+	Key      string    `json:"key"` // 2026-09 / 2026-W37
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+// PreviousPeriod returns the last fully elapsed period before now in loc.
+// Weeks start on Monday (ISO 8601).
+func PreviousPeriod(schedule Schedule, now time.Time, loc *time.Location) Period {
+	now = now.In(loc)
+	switch schedule {
+	case ScheduleWeekly:
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		offset := (int(today.Weekday()) + 6) % 7 // 距离本周一的天数
+		end := today.AddDate(0, 0, -offset)
+		start := end.AddDate(0, 0, -7)
+		year, week := start.ISOWeek()
+		return Period{Schedule: schedule, Key: fmt.Sprintf("%d-W%02d", year, week), Start: start, End: end}
+	default:
+		end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+		start := end.AddDate(0, -1, 0)
+		return Period{Schedule: ScheduleMonthly, Key: start.Format("2006-01"), Start: start, End: end}
+	}
+}
+
+// marker is stored in SettingKeyReportLastPeriod once a period has been generated
+func (p Period) marker() string {
+	return string(p.Schedule) + ":" + p.Key
+}
@@ -0,0 +1,314 @@
+// Package report generates scheduled billing reports per project and per API
+// token from the pre-aggregated usage stats. Reports are written to
+// <dataDir>/reports and optionally announced via webhook and sent by email
+// through an SMTP relay.
+package report
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/export"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+// Dimension values of a report file
+const (
+	DimensionProject  = "project"
+	DimensionAPIToken = "api_token"
+)
+
+// Config is the report configuration read from system settings
+type Config struct {
+	Schedule   Schedule
+	Format     export.Format
+	WebhookURL string
+	SMTPAddr   string
+	EmailFrom  string
+	EmailTo    []string
+
+	// SMTP PLAIN 认证，用户名为空时不认证（本地中继）
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// LoadConfig reads the report settings
+func LoadConfig(settings repository.SystemSettingRepository) (*Config, error) {
+	get := func(key string) string {
+		v, _ := settings.Get(key)
+		return strings.TrimSpace(v)
+	}
+	schedule, err := ParseSchedule(get(domain.SettingKeyReportSchedule))
+	if err != nil {
+		return nil, err
+	}
+	format, err := export.ParseFormat(get(domain.SettingKeyReportFormat))
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{
+		Schedule:   schedule,
+		Format:     format,
+		WebhookURL: get(domain.SettingKeyReportWebhookURL),
+		SMTPAddr:   get(domain.SettingKeyReportSMTPAddr),
+		EmailFrom:  get(domain.SettingKeyReportEmailFrom),
+
+		SMTPUsername: get(domain.SettingKeyReportSMTPUsername),
+		SMTPPassword: get(domain.SettingKeyReportSMTPPassword),
+	}
+	for _, to := range strings.Split(get(domain.SettingKeyReportEmailTo), ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			cfg.EmailTo = append(cfg.EmailTo, to)
+		}
+	}
+	return cfg, nil
+}
+
+// File is one generated report file
+type File struct {
+	Dimension     string `json:"dimension"`
+	ID            uint64 `json:"id"`
+	Name          string `json:"name"`
+	Path          string `json:"path"`
+	TotalRequests uint64 `json:"totalRequests"`
+	InputTokens   uint64 `json:"inputTokens"`
+	OutputTokens  uint64 `json:"outputTokens"`
+	Cost          uint64 `json:"cost"`         // 纳美元
+	UpstreamCost  uint64 `json:"upstreamCost"` // 上游成本（纳美元）
+	BilledCost    uint64 `json:"billedCost"`   // 计费金额（纳美元）
+	Margin        int64  `json:"margin"`       // 毛利（纳美元）
+}
+
+// Result describes the reports of one period
+type Result struct {
+	Period Period  `json:"period"`
+	Dir    string  `json:"dir"`
+	Files  []*File `json:"files"`
+}
+
+// Generator generates and delivers billing reports
+type Generator struct {
+	usageStats repository.UsageStatsRepository
+	providers  repository.ProviderRepository
+	projects   repository.ProjectRepository
+	apiTokens  repository.APITokenRepository
+	settings   repository.SystemSettingRepository
+	dir        string
+	httpClient *http.Client
+
+	mu sync.Mutex
+}
+
+// NewGenerator creates a generator writing to <dataDir>/reports
+func NewGenerator(
+	usageStats repository.UsageStatsRepository,
+	providers repository.ProviderRepository,
+	projects repository.ProjectRepository,
+	apiTokens repository.APITokenRepository,
+	settings repository.SystemSettingRepository,
+	dataDir string,
+) *Generator {
+	return &Generator{
+		usageStats: usageStats,
+		providers:  providers,
+		projects:   projects,
+		apiTokens:  apiTokens,
+		settings:   settings,
+		dir:        filepath.Join(dataDir, "reports"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Location returns the configured timezone used for period boundaries
+func (g *Generator) Location() *time.Location {
+	if name, err := g.settings.Get(domain.SettingKeyTimezone); err == nil && name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		return time.FixedZone("UTC+8", 8*60*60)
+	}
+	return loc
+}
+
+// RunDue generates the previous period once it has elapsed. It returns nil when
+// reports are disabled or the period was already generated.
+func (g *Generator) RunDue(now time.Time) (*Result, error) {
+	cfg, err := LoadConfig(g.settings)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Schedule == "" {
+		return nil, nil
+	}
+	// 预留 1 小时让最后一天的统计数据完成 rollup
+	period := PreviousPeriod(cfg.Schedule, now.Add(-time.Hour), g.Location())
+	if last, _ := g.settings.Get(domain.SettingKeyReportLastPeriod); last == period.marker() {
+		return nil, nil
+	}
+	result, err := g.Run(cfg, period)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.settings.Set(domain.SettingKeyReportLastPeriod, period.marker()); err != nil {
+		log.Printf("[Report] Failed to save last period: %v", err)
+	}
+	return result, nil
+}
+
+// Run generates the reports of a period and delivers them. Delivery failures are
+// logged and do not fail the run since the files are already written.
+func (g *Generator) Run(cfg *Config, period Period) (*Result, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	result, err := g.generate(cfg.Format, period)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[Report] Generated %d %s report(s) for %s in %s", len(result.Files), period.Schedule, period.Key, result.Dir)
+
+	if cfg.WebhookURL != "" {
+		if err := g.sendWebhook(cfg.WebhookURL, result); err != nil {
+			log.Printf("[Report] Webhook delivery failed: %v", err)
+		}
+	}
+	if cfg.SMTPAddr != "" && len(cfg.EmailTo) > 0 {
+		if err := sendEmail(cfg, result); err != nil {
+			log.Printf("[Report] Email delivery failed: %v", err)
+		}
+	}
+	return result, nil
+}
+
+func (g *Generator) generate(format export.Format, period Period) (*Result, error) {
+	names, err := export.LoadNames(g.providers, g.projects, g.apiTokens)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(g.dir, string(period.Schedule), period.Key)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	end := period.End.Add(-time.Millisecond)
+	base := repository.UsageStatsFilter{Granularity: domain.GranularityDay, StartTime: &period.Start, EndTime: &end}
+	result := &Result{Period: period, Dir: dir, Files: []*File{}}
+
+	projects, err := g.usageStats.GetSummaryByProject(base)
+	if err != nil {
+		return nil, err
+	}
+	for id, summary := range projects {
+		filter := base
+		filter.ProjectID = &id
+		file, err := g.writeFile(dir, format, DimensionProject, id, names.Projects[id], summary, filter, names)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, file)
+	}
+
+	tokens, err := g.usageStats.GetSummaryByAPIToken(base)
+	if err != nil {
+		return nil, err
+	}
+	for id, summary := range tokens {
+		filter := base
+		filter.APITokenID = &id
+		file, err := g.writeFile(dir, format, DimensionAPIToken, id, names.APITokens[id], summary, filter, names)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, file)
+	}
+
+	sortFiles(result.Files)
+	return result, nil
+}
+
+func (g *Generator) writeFile(dir string, format export.Format, dimension string, id uint64, name string,
+	summary *domain.UsageStatsSummary, filter repository.UsageStatsFilter, names *export.Names) (*File, error) {
+	if name == "" {
+		name = "unassigned"
+		if id > 0 {
+			name = "deleted"
+		}
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d-%s%s", strings.ReplaceAll(dimension, "_", "-"), id, safeFileName(name), format.Extension()))
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	err = func() error {
+		w, err := export.NewWriter(format, f, export.UsageStatsColumns)
+		if err != nil {
+			return err
+		}
+		if err := g.usageStats.Iterate(filter, func(s *domain.UsageStats) error {
+			return w.Write(export.UsageStatsRow(s, names))
+		}); err != nil {
+			return err
+		}
+		return w.Close()
+	}()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return nil, err
+	}
+
+	return &File{
+		Dimension:     dimension,
+		ID:            id,
+		Name:          name,
+		Path:          path,
+		TotalRequests: summary.TotalRequests,
+		InputTokens:   summary.TotalInputTokens,
+		OutputTokens:  summary.TotalOutputTokens,
+		Cost:          summary.TotalCost,
+		UpstreamCost:  summary.TotalUpstreamCost,
+		BilledCost:    summary.TotalBilledCost,
+		Margin:        summary.Margin,
+	}, nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+func safeFileName(name string) string {
+	s := strings.Trim(unsafeFileChars.ReplaceAllString(name, "-"), "-")
+	if len(s) > 40 {
+		s = s[:40]
+	}
+	if s == "" {
+		s = "unnamed"
+	}
+	return s
+}
+
+// sortFiles orders files by dimension (projects first) then ID for stable output
+func sortFiles(files []*File) {
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Dimension != files[j].Dimension {
+			return files[i].Dimension == DimensionProject
+		}
+		return files[i].ID < files[j].ID
+	})
+}
@@ -0,0 +1,221 @@
+package report
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+func TestPreviousPeriod(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	// 2026-10-18 是周日
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, loc)
+
+	monthly := PreviousPeriod(ScheduleMonthly, now, loc)
+	if monthly.Key != "2026-09" || !monthly.Start.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, loc)) || !monthly.End.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, loc)) {
+		t.Fatalf("unexpected monthly period %+v", monthly)
+	}
+
+	weekly := PreviousPeriod(ScheduleWeekly, now, loc)
+	if weekly.Key != "2026-W41" || !weekly.Start.Equal(time.Date(2026, 10, 5, 0, 0, 0, 0, loc)) || !weekly.End.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, loc)) {
+		t.Fatalf("unexpected weekly period %+v", weekly)
+	}
+
+	// 周一当天仍然返回上一个完整周
+	monday := PreviousPeriod(ScheduleWeekly, time.Date(2026, 10, 12, 0, 30, 0, 0, loc), loc)
+	if monday.Key != "2026-W41" {
+		t.Fatalf("unexpected period on monday %+v", monday)
+	}
+}
+
+type fakeSettings struct {
+	repository.SystemSettingRepository
+	values map[string]string
+}
+
+func (f *fakeSettings) Get(key string) (string, error) { return f.values[key], nil }
+func (f *fakeSettings) Set(key, value string) error {
+	f.values[key] = value
+	return nil
+}
+
+type fakeUsageStats struct {
+	repository.UsageStatsRepository
+	rows []*domain.UsageStats
+}
+
+func (f *fakeUsageStats) matching(filter repository.UsageStatsFilter) []*domain.UsageStats {
+	var out []*domain.UsageStats
+	for _, s := range f.rows {
+		if filter.StartTime != nil && s.TimeBucket.Before(*filter.StartTime) ||
+			filter.EndTime != nil && s.TimeBucket.After(*filter.EndTime) ||
+			filter.ProjectID != nil && s.ProjectID != *filter.ProjectID ||
+			filter.APITokenID != nil && s.APITokenID != *filter.APITokenID {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func (f *fakeUsageStats) summarize(filter repository.UsageStatsFilter, key func(*domain.UsageStats) uint64) map[uint64]*domain.UsageStatsSummary {
+	out := map[uint64]*domain.UsageStatsSummary{}
+	for _, s := range f.matching(filter) {
+		sum, ok := out[key(s)]
+		if !ok {
+			sum = &domain.UsageStatsSummary{}
+			out[key(s)] = sum
+		}
+		sum.TotalRequests += s.TotalRequests
+		sum.TotalCost += s.Cost
+	}
+	return out
+}
+
+func (f *fakeUsageStats) GetSummaryByProject(filter repository.UsageStatsFilter) (map[uint64]*domain.UsageStatsSummary, error) {
+	return f.summarize(filter, func(s *domain.UsageStats) uint64 { return s.ProjectID }), nil
+}
+
+func (f *fakeUsageStats) GetSummaryByAPIToken(filter repository.UsageStatsFilter) (map[uint64]*domain.UsageStatsSummary, error) {
+	return f.summarize(filter, func(s *domain.UsageStats) uint64 { return s.APITokenID }), nil
+}
+
+func (f *fakeUsageStats) Iterate(filter repository.UsageStatsFilter, fn func(*domain.UsageStats) error) error {
+	for _, s := range f.matching(filter) {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fakeProviders struct{ repository.ProviderRepository }
+
+func (fakeProviders) List() ([]*domain.Provider, error) {
+	return []*domain.Provider{{ID: 1, Name: "anthropic"}}, nil
+}
+
+type fakeProjects struct{ repository.ProjectRepository }
+
+func (fakeProjects) List() ([]*domain.Project, error) {
+	return []*domain.Project{{ID: 7, Name: "Team A/B"}}, nil
+}
+
+type fakeTokens struct{ repository.APITokenRepository }
+
+func (fakeTokens) List() ([]*domain.APIToken, error) {
+	return []*domain.APIToken{{ID: 3, Name: "ci"}}, nil
+}
+
+func TestGeneratorRunDue(t *testing.T) {
+	var webhookCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webhookCalls.Add(1)
+	}))
+	defer server.Close()
+
+	loc := time.UTC
+	settings := &fakeSettings{values: map[string]string{
+		domain.SettingKeyTimezone:         "UTC",
+		domain.SettingKeyReportSchedule:   "monthly",
+		domain.SettingKeyReportWebhookURL: server.URL,
+	}}
+	usage := &fakeUsageStats{rows: []*domain.UsageStats{
+		{TimeBucket: time.Date(2026, 9, 3, 0, 0, 0, 0, loc), Granularity: domain.GranularityDay, ProviderID: 1, ProjectID: 7, APITokenID: 3, Model: "claude", TotalRequests: 5, Cost: 2_000_000_000},
+		{TimeBucket: time.Date(2026, 9, 4, 0, 0, 0, 0, loc), Granularity: domain.GranularityDay, ProviderID: 1, ProjectID: 0, APITokenID: 3, Model: "claude", TotalRequests: 1, Cost: 1_000_000_000},
+		{TimeBucket: time.Date(2026, 10, 1, 0, 0, 0, 0, loc), Granularity: domain.GranularityDay, ProviderID: 1, ProjectID: 7, APITokenID: 3, Model: "claude", TotalRequests: 9},
+	}}
+	dataDir := t.TempDir()
+	g := NewGenerator(usage, fakeProviders{}, fakeProjects{}, fakeTokens{}, settings, dataDir)
+
+	now := time.Date(2026, 10, 2, 12, 0, 0, 0, loc)
+	result, err := g.RunDue(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil || result.Period.Key != "2026-09" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(result.Files) != 3 {
+		t.Fatalf("expected 3 files (2 projects, 1 token), got %d", len(result.Files))
+	}
+	if f := result.Files[1]; f.Dimension != DimensionProject || f.ID != 7 || f.TotalRequests != 5 ||
+		filepath.Base(f.Path) != "project-7-Team-A-B.csv" {
+		t.Fatalf("unexpected project file %+v", f)
+	}
+	token := result.Files[2]
+	if token.Dimension != DimensionAPIToken || token.TotalRequests != 6 || token.Cost != 3_000_000_000 {
+		t.Fatalf("unexpected token file %+v", token)
+	}
+
+	file, err := os.Open(token.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[1][4] != "anthropic" || records[1][6] != "Team A/B" {
+		t.Fatalf("unexpected csv %v", records)
+	}
+	if webhookCalls.Load() != 1 {
+		t.Fatalf("expected one webhook call, got %d", webhookCalls.Load())
+	}
+
+	// 同一周期不会重复生成
+	again, err := g.RunDue(now.Add(time.Hour))
+	if err != nil || again != nil {
+		t.Fatalf("expected no rerun, got %+v, %v", again, err)
+	}
+}
+
+func TestBuildEmail(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "project-1-a.csv")
+	if err := os.WriteFile(path, []byte("a,b\n1,2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result := &Result{
+		Period: PreviousPeriod(ScheduleMonthly, time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), time.UTC),
+		Files:  []*File{{Dimension: DimensionProject, ID: 1, Name: "a", Path: path, TotalRequests: 2, Cost: 1_500_000_000}},
+	}
+	msg, err := buildEmail("maxx@localhost", []string{"billing@example.com"}, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(msg)
+	for _, want := range []string{
+		"Subject: [maxx] monthly billing report 2026-09",
+		"To: billing@example.com",
+		`filename="project-1-a.csv"`,
+		"YSxiCjEsMgo=", // base64("a,b\n1,2\n")
+		"cost=$1.5000",
+	} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("email missing %q:\n%s", want, s)
+		}
+	}
+}
+
+func TestSMTPAuth(t *testing.T) {
+	if auth, err := smtpAuth(&Config{SMTPAddr: "localhost:25"}); err != nil || auth != nil {
+		t.Fatalf("expected no auth without username, got %v, %v", auth, err)
+	}
+	if auth, err := smtpAuth(&Config{SMTPAddr: "smtp.example.com:587", SMTPUsername: "u", SMTPPassword: "p"}); err != nil || auth == nil {
+		t.Fatalf("expected PLAIN auth, got %v, %v", auth, err)
+	}
+	if _, err := smtpAuth(&Config{SMTPAddr: "smtp.example.com", SMTPUsername: "u"}); err == nil {
+		t.Fatal("expected error for address without port")
+	}
+}
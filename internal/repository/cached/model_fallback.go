@@ -0,0 +1,107 @@
+package cached
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+type ModelFallbackRepository struct {
+	repo  repository.ModelFallbackRepository
+	cache []*domain.ModelFallback
+	mu    sync.RWMutex
+}
+
+func NewModelFallbackRepository(repo repository.ModelFallbackRepository) *ModelFallbackRepository {
+	return &ModelFallbackRepository{
+		repo:  repo,
+		cache: make([]*domain.ModelFallback, 0),
+	}
+}
+
+// Load 从数据库加载所有数据到内存（只在启动时调用一次）
+func (r *ModelFallbackRepository) Load() error {
+	list, err := r.repo.List()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = list
+	r.sortCache()
+	return nil
+}
+
+// sortCache 对缓存进行排序（按 priority、id）
+// 调用前必须持有写锁
+func (r *ModelFallbackRepository) sortCache() {
+	sort.Slice(r.cache, func(i, j int) bool {
+		if r.cache[i].Priority != r.cache[j].Priority {
+			return r.cache[i].Priority < r.cache[j].Priority
+		}
+		return r.cache[i].ID < r.cache[j].ID
+	})
+}
+
+func (r *ModelFallbackRepository) Create(fallback *domain.ModelFallback) error {
+	if err := r.repo.Create(fallback); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = append(r.cache, fallback)
+	r.sortCache()
+	return nil
+}
+
+func (r *ModelFallbackRepository) Update(fallback *domain.ModelFallback) error {
+	if err := r.repo.Update(fallback); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, f := range r.cache {
+		if f.ID == fallback.ID {
+			r.cache[i] = fallback
+			break
+		}
+	}
+	r.sortCache()
+	return nil
+}
+
+func (r *ModelFallbackRepository) Delete(id uint64) error {
+	if err := r.repo.Delete(id); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, f := range r.cache {
+		if f.ID == id {
+			r.cache = append(r.cache[:i], r.cache[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *ModelFallbackRepository) GetByID(id uint64) (*domain.ModelFallback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, f := range r.cache {
+		if f.ID == id {
+			return f, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *ModelFallbackRepository) List() ([]*domain.ModelFallback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.ModelFallback, len(r.cache))
+	copy(result, r.cache)
+	return result, nil
+}
@@ -10,23 +10,26 @@ type CooldownRepository interface {
 	// GetAll returns all active cooldowns
 	GetAll() ([]*domain.Cooldown, error)
 
+	// GetEndedAfter returns cooldowns ending after t, including expired ones kept for half-open circuits
+	GetEndedAfter(t time.Time) ([]*domain.Cooldown, error)
+
 	// GetByProvider returns cooldowns for a specific provider
 	GetByProvider(providerID uint64) ([]*domain.Cooldown, error)
 
 	// Upsert creates or updates a cooldown
 	Upsert(cooldown *domain.Cooldown) error
 
-	// Delete removes a cooldown
-	Delete(providerID uint64, clientType string) error
+	// Delete removes a cooldown (model is empty for cooldowns covering all models)
+	Delete(providerID uint64, clientType string, model string) error
 
 	// DeleteAll removes all cooldowns for a provider
 	DeleteAll(providerID uint64) error
 
-	// DeleteExpired removes all expired cooldowns
-	DeleteExpired() error
+	// DeleteExpired removes cooldowns that ended before t
+	DeleteExpired(before time.Time) error
 
 	// Get retrieves a specific cooldown
-	Get(providerID uint64, clientType string) (*domain.Cooldown, error)
+	Get(providerID uint64, clientType string, model string) (*domain.Cooldown, error)
 }
 
 // CooldownInfo is a helper structure for returning cooldown information
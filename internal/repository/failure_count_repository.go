@@ -6,8 +6,8 @@ import (
 
 // FailureCountRepository manages failure count persistence
 type FailureCountRepository interface {
-	// Get retrieves a failure count by provider, client type, and reason
-	Get(providerID uint64, clientType string, reason string) (*domain.FailureCount, error)
+	// Get retrieves a failure count by provider, client type, model, and reason
+	Get(providerID uint64, clientType string, model string, reason string) (*domain.FailureCount, error)
 
 	// GetAll retrieves all failure counts
 	GetAll() ([]*domain.FailureCount, error)
@@ -16,9 +16,9 @@ type FailureCountRepository interface {
 	Upsert(fc *domain.FailureCount) error
 
 	// Delete deletes a failure count
-	Delete(providerID uint64, clientType string, reason string) error
+	Delete(providerID uint64, clientType string, model string, reason string) error
 
-	// DeleteAll deletes all failure counts for a provider+clientType
+	// DeleteAll deletes all failure counts (of every model) for a provider+clientType
 	DeleteAll(providerID uint64, clientType string) error
 
 	// DeleteExpired deletes failure counts where last failure was too long ago
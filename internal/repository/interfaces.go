@@ -64,6 +64,34 @@ type ProxyRequestFilter struct {
 	ProviderID *uint64 // Provider ID，nil 表示不过滤
 	Status     *string // 状态，nil 表示不过滤
 	APITokenID *uint64 // API Token ID，nil 表示不过滤
+
+	StartTime  *time.Time // 创建时间 >= StartTime
+	EndTime    *time.Time // 创建时间 < EndTime
+	Model      *string    // 匹配请求模型或响应模型
+	ProjectID  *uint64
+	SessionID  *string
+	ClientType *string
+	StatusCode *int
+
+	MinCost       *uint64 // 最低成本 (nanoUSD)
+	MinDurationMs *int64  // 最低耗时 (毫秒)
+	MinTokens     *uint64 // 最低 token 数 (输入 + 输出)
+
+	ErrorContains *string // 错误信息包含的子串
+	Query         *string // 对请求/响应内容的全文检索
+}
+
+// IsEmpty 是否没有任何过滤条件
+func (f *ProxyRequestFilter) IsEmpty() bool {
+	return f == nil || *f == ProxyRequestFilter{}
+}
+
+// SessionStatsFilter 会话统计过滤条件
+type SessionStatsFilter struct {
+	SessionID *string
+	ProjectID *uint64
+	StartTime *time.Time // 最后活跃时间 >= StartTime
+	EndTime   *time.Time // 最后活跃时间 < EndTime
 }
 
 type ProxyRequestRepository interface {
@@ -83,6 +111,12 @@ type ProxyRequestRepository interface {
 	CountWithFilter(filter *ProxyRequestFilter) (int64, error)
 	// UpdateProjectIDBySessionID 批量更新指定 sessionID 的所有请求的 projectID
 	UpdateProjectIDBySessionID(sessionID string, projectID uint64) (int64, error)
+	// ListSessionStats 按会话聚合请求，按最后活跃时间倒序
+	ListSessionStats(filter *SessionStatsFilter, limit, offset int) ([]*domain.SessionStats, error)
+	// ListBySessionID 按时间顺序获取会话内 id > after 的请求（包含 request_info 和 response_info）
+	ListBySessionID(sessionID string, after uint64, limit int) ([]*domain.ProxyRequest, error)
+	// Iterate 按 id 升序分批遍历满足过滤条件的请求（不含请求/响应详情），fn 返回错误时停止
+	Iterate(filter *ProxyRequestFilter, fn func(*domain.ProxyRequest) error) error
 	// MarkStaleAsFailed marks all IN_PROGRESS/PENDING requests from other instances as FAILED
 	// Also marks requests that have been IN_PROGRESS for too long (> 30 minutes) as timed out
 	MarkStaleAsFailed(currentInstanceID string) (int64, error)
@@ -92,12 +126,12 @@ type ProxyRequestRepository interface {
 	DeleteOlderThan(before time.Time) (int64, error)
 	// HasRecentRequests 检查指定时间之后是否有请求记录
 	HasRecentRequests(since time.Time) (bool, error)
-	// UpdateCost updates only the cost field of a request
-	UpdateCost(id uint64, cost uint64) error
+	// UpdateCost updates only the cost fields (cost, upstream_cost, billed_cost) of a request
+	UpdateCost(id uint64, cost domain.CostUpdate) error
 	// AddCost adds a delta to the cost field of a request (can be negative)
 	AddCost(id uint64, delta int64) error
 	// BatchUpdateCosts updates costs for multiple requests in a single transaction
-	BatchUpdateCosts(updates map[uint64]uint64) error
+	BatchUpdateCosts(updates map[uint64]domain.CostUpdate) error
 	// RecalculateCostsFromAttempts recalculates all request costs by summing their attempt costs
 	RecalculateCostsFromAttempts() (int64, error)
 	// RecalculateCostsFromAttemptsWithProgress recalculates all request costs with progress reporting via channel
@@ -117,16 +151,26 @@ type ProxyUpstreamAttemptRepository interface {
 	// StreamForCostCalc iterates through all attempts for cost calculation
 	// Calls the callback with batches of minimal data, returns early if callback returns error
 	StreamForCostCalc(batchSize int, callback func(batch []*domain.AttemptCostData) error) error
-	// UpdateCost updates only the cost field of an attempt
-	UpdateCost(id uint64, cost uint64) error
+	// UpdateCost updates only the cost fields (cost, upstream_cost, billed_cost) of an attempt
+	UpdateCost(id uint64, cost domain.CostUpdate) error
 	// BatchUpdateCosts updates costs for multiple attempts in a single transaction
-	BatchUpdateCosts(updates map[uint64]uint64) error
+	BatchUpdateCosts(updates map[uint64]domain.CostUpdate) error
 	// MarkStaleAttemptsFailed marks stale attempts as failed with proper end_time and duration
 	MarkStaleAttemptsFailed() (int64, error)
 	// FixFailedAttemptsWithoutEndTime fixes FAILED attempts that have no end_time set
 	FixFailedAttemptsWithoutEndTime() (int64, error)
 	// ClearDetailOlderThan 清理指定时间之前 attempt 的详情字段（request_info 和 response_info）
 	ClearDetailOlderThan(before time.Time) (int64, error)
+	// GetWastedSpend 按 Provider 和原因聚合失败或取消的 attempt 消耗，按成本倒序
+	GetWastedSpend(filter WastedSpendFilter) ([]*domain.WastedSpend, error)
+}
+
+// WastedSpendFilter 浪费消耗查询条件，时间按 attempt 结束时间过滤
+type WastedSpendFilter struct {
+	StartTime  *time.Time
+	EndTime    *time.Time
+	ProviderID *uint64
+	ProjectID  *uint64
 }
 
 type SystemSettingRepository interface {
@@ -158,6 +202,17 @@ type CodexQuotaRepository interface {
 	Delete(email string) error
 }
 
+type CopilotQuotaRepository interface {
+	// Upsert 更新或插入配额（基于 GitHub 用户名）
+	Upsert(quota *domain.CopilotQuota) error
+	// GetByUsername 根据 GitHub 用户名获取配额
+	GetByUsername(username string) (*domain.CopilotQuota, error)
+	// List 获取所有配额
+	List() ([]*domain.CopilotQuota, error)
+	// Delete 删除配额
+	Delete(username string) error
+}
+
 type UsageStatsRepository interface {
 	// Upsert 更新或插入统计记录
 	Upsert(stats *domain.UsageStats) error
@@ -165,6 +220,8 @@ type UsageStatsRepository interface {
 	BatchUpsert(stats []*domain.UsageStats) error
 	// Query 查询统计数据（包含当前时间桶的实时数据补全）
 	Query(filter UsageStatsFilter) ([]*domain.UsageStats, error)
+	// Iterate 分批遍历预聚合的统计数据（不补全当前时间桶），fn 返回错误时停止
+	Iterate(filter UsageStatsFilter, fn func(*domain.UsageStats) error) error
 	// QueryDashboardData 查询 Dashboard 所需的所有数据（单次请求，并发执行）
 	QueryDashboardData() (*domain.DashboardData, error)
 	// GetSummary 获取汇总统计数据（总计）
@@ -233,6 +290,15 @@ type ModelMappingRepository interface {
 	SeedDefaults() error // Re-seed default mappings
 }
 
+type ModelFallbackRepository interface {
+	Create(fallback *domain.ModelFallback) error
+	Update(fallback *domain.ModelFallback) error
+	Delete(id uint64) error
+	GetByID(id uint64) (*domain.ModelFallback, error)
+	// List 按优先级返回所有降级链
+	List() ([]*domain.ModelFallback, error)
+}
+
 type ResponseModelRepository interface {
 	// Upsert 更新或插入 response model（基于 name）
 	Upsert(name string) error
@@ -251,20 +317,65 @@ type ModelPriceRepository interface {
 	BatchCreate(prices []*domain.ModelPrice) error
 	// GetByID 获取指定ID的价格记录
 	GetByID(id uint64) (*domain.ModelPrice, error)
-	// GetCurrentByModelID 获取模型的当前价格（最新记录），支持前缀匹配
+	// GetCurrentByModelID 获取模型的当前全局价格（最新记录），支持前缀匹配
 	GetCurrentByModelID(modelID string) (*domain.ModelPrice, error)
-	// ListCurrentPrices 获取所有模型的当前价格（用于初始化 Calculator）
+	// ListCurrentPrices 获取所有模型的当前价格，包含 Provider 覆盖价格（用于初始化 Calculator）
 	ListCurrentPrices() ([]*domain.ModelPrice, error)
-	// ListByModelID 获取模型的价格历史
-	ListByModelID(modelID string) ([]*domain.ModelPrice, error)
+	// ListByModelID 获取模型的价格历史（providerID 为 0 表示全局价格）
+	ListByModelID(providerID uint64, modelID string) ([]*domain.ModelPrice, error)
 	// Count 获取价格记录总数
 	Count() (int64, error)
 	// Delete 删除价格记录（软删除）
 	Delete(id uint64) error
 	// Update 更新价格记录
 	Update(price *domain.ModelPrice) error
-	// SoftDeleteAll 软删除所有价格记录
+	// SoftDeleteAll 软删除所有全局价格记录（Provider 覆盖价格保留）
 	SoftDeleteAll() error
-	// ResetToDefaults 重置为默认价格（软删除现有记录，插入默认价格）
+	// ResetToDefaults 重置为默认价格（软删除现有全局记录，插入默认价格）
 	ResetToDefaults() ([]*domain.ModelPrice, error)
 }
+
+// AuditLogFilter 审计日志查询过滤条件
+type AuditLogFilter struct {
+	EntityType *string    // 实体类型
+	EntityID   *string    // 实体 ID
+	Actor      *string    // 操作者
+	Action     *string    // 操作类型
+	StartTime  *time.Time // 开始时间
+	EndTime    *time.Time // 结束时间
+}
+
+type AuditLogRepository interface {
+	Create(log *domain.AuditLog) error
+	// List 按时间倒序分页查询
+	List(filter *AuditLogFilter, limit, offset int) ([]*domain.AuditLog, error)
+	// Count 统计符合条件的记录数
+	Count(filter *AuditLogFilter) (int64, error)
+	// DeleteOlderThan 删除指定时间之前的记录
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type CooldownPolicyRepository interface {
+	Create(policy *domain.CooldownPolicy) error
+	Update(policy *domain.CooldownPolicy) error
+	Delete(id uint64) error
+	GetByID(id uint64) (*domain.CooldownPolicy, error)
+	List() ([]*domain.CooldownPolicy, error)
+}
+
+type ProviderHealthCheckRepository interface {
+	Create(check *domain.ProviderHealthCheck) error
+	// ListByProvider 按时间倒序查询，clientType 为空时不过滤
+	ListByProvider(providerID uint64, clientType domain.ClientType, limit int) ([]*domain.ProviderHealthCheck, error)
+	// DeleteOlderThan 删除指定时间之前的记录
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type BillingRuleRepository interface {
+	Create(rule *domain.BillingRule) error
+	Update(rule *domain.BillingRule) error
+	Delete(id uint64) error
+	GetByID(id uint64) (*domain.BillingRule, error)
+	// List 返回所有未删除的计费规则
+	List() ([]*domain.BillingRule, error)
+}
@@ -26,6 +26,10 @@ func (r *APITokenRepository) Create(t *domain.APIToken) error {
 		return err
 	}
 	t.ID = model.ID
+	// is_enabled 带 default:1，GORM 创建时会忽略零值，禁用的 Token 需要显式写入
+	if !t.IsEnabled {
+		return r.db.gorm.Model(&APIToken{}).Where("id = ?", t.ID).Update("is_enabled", 0).Error
+	}
 	return nil
 }
 
@@ -40,7 +44,14 @@ func (r *APITokenRepository) Update(t *domain.APIToken) error {
 			"project_id":  t.ProjectID,
 			"is_enabled":  boolToInt(t.IsEnabled),
 			"dev_mode":    boolToInt(t.DevMode),
+			"priority":    string(t.Priority),
 			"expires_at":  toTimestampPtr(t.ExpiresAt),
+
+			"allowed_client_types": LongText(toJSON(t.AllowedClientTypes)),
+			"allowed_models":       LongText(toJSON(t.AllowedModels)),
+			"allowed_provider_ids": LongText(toJSON(t.AllowedProviderIDs)),
+			"allowed_ips":          LongText(toJSON(t.AllowedIPs)),
+			"project_admin":        boolToInt(t.ProjectAdmin),
 		}).Error
 }
 
@@ -117,9 +128,16 @@ func (r *APITokenRepository) toModel(t *domain.APIToken) *APIToken {
 		ProjectID:   t.ProjectID,
 		IsEnabled:   boolToInt(t.IsEnabled),
 		DevMode:     boolToInt(t.DevMode),
+		Priority:    string(t.Priority),
 		ExpiresAt:   toTimestampPtr(t.ExpiresAt),
 		LastUsedAt:  toTimestampPtr(t.LastUsedAt),
 		UseCount:    t.UseCount,
+
+		AllowedClientTypes: LongText(toJSON(t.AllowedClientTypes)),
+		AllowedModels:      LongText(toJSON(t.AllowedModels)),
+		AllowedProviderIDs: LongText(toJSON(t.AllowedProviderIDs)),
+		AllowedIPs:         LongText(toJSON(t.AllowedIPs)),
+		ProjectAdmin:       boolToInt(t.ProjectAdmin),
 	}
 }
 
@@ -136,9 +154,17 @@ func (r *APITokenRepository) toDomain(m *APIToken) *domain.APIToken {
 		ProjectID:   m.ProjectID,
 		IsEnabled:   m.IsEnabled == 1,
 		DevMode:     m.DevMode == 1,
+		Priority:    domain.PriorityClass(m.Priority),
 		ExpiresAt:   fromTimestampPtr(m.ExpiresAt),
 		LastUsedAt:  fromTimestampPtr(m.LastUsedAt),
 		UseCount:    m.UseCount,
+		APITokenScope: domain.APITokenScope{
+			AllowedClientTypes: fromJSON[[]domain.ClientType](string(m.AllowedClientTypes)),
+			AllowedModels:      fromJSON[[]string](string(m.AllowedModels)),
+			AllowedProviderIDs: fromJSON[[]uint64](string(m.AllowedProviderIDs)),
+			AllowedIPs:         fromJSON[[]string](string(m.AllowedIPs)),
+			ProjectAdmin:       m.ProjectAdmin == 1,
+		},
 	}
 }
 
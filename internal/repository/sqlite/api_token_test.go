@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+func TestAPITokenRepository_UpdatePersistsScope(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "maxx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewAPITokenRepository(db)
+
+	token := &domain.APIToken{Token: "maxx_test", Name: "ci", ProjectID: 3, IsEnabled: true}
+	if err := repo.Create(token); err != nil {
+		t.Fatal(err)
+	}
+
+	token.AllowedModels = []string{"claude-*"}
+	token.AllowedProviderIDs = []uint64{1, 2}
+	token.AllowedIPs = []string{"10.0.0.0/8"}
+	token.ProjectAdmin = true
+	if err := repo.Update(token); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.GetByID(token.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.AllowedModels, token.AllowedModels) ||
+		!slices.Equal(got.AllowedProviderIDs, token.AllowedProviderIDs) ||
+		!slices.Equal(got.AllowedIPs, token.AllowedIPs) ||
+		!got.ProjectAdmin {
+		t.Fatalf("scope not persisted: %+v", got.APITokenScope)
+	}
+}
@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository struct {
+	db *DB
+}
+
+func NewAuditLogRepository(db *DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+func (r *AuditLogRepository) Create(l *domain.AuditLog) error {
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = time.Now()
+	}
+
+	model := r.toModel(l)
+	if err := r.db.gorm.Create(model).Error; err != nil {
+		return err
+	}
+	l.ID = model.ID
+	return nil
+}
+
+func (r *AuditLogRepository) List(filter *repository.AuditLogFilter, limit, offset int) ([]*domain.AuditLog, error) {
+	var models []AuditLog
+	query := r.applyFilter(r.db.gorm.Model(&AuditLog{}), filter).Order("id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	logs := make([]*domain.AuditLog, len(models))
+	for i := range models {
+		logs[i] = r.toDomain(&models[i])
+	}
+	return logs, nil
+}
+
+func (r *AuditLogRepository) Count(filter *repository.AuditLogFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.gorm.Model(&AuditLog{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteOlderThan 删除指定时间之前的审计日志
+func (r *AuditLogRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.db.gorm.Where("created_at < ?", toTimestamp(before)).Delete(&AuditLog{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *AuditLogRepository) applyFilter(query *gorm.DB, filter *repository.AuditLogFilter) *gorm.DB {
+	if filter == nil {
+		return query
+	}
+	if filter.EntityType != nil {
+		query = query.Where("entity_type = ?", *filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.Actor != nil {
+		query = query.Where("actor = ?", *filter.Actor)
+	}
+	if filter.Action != nil {
+		query = query.Where("action = ?", *filter.Action)
+	}
+	if filter.StartTime != nil {
+		query = query.Where("created_at >= ?", toTimestamp(*filter.StartTime))
+	}
+	if filter.EndTime != nil {
+		query = query.Where("created_at < ?", toTimestamp(*filter.EndTime))
+	}
+	return query
+}
+
+func (r *AuditLogRepository) toModel(l *domain.AuditLog) *AuditLog {
+	return &AuditLog{
+		ID:         l.ID,
+		CreatedAt:  toTimestamp(l.CreatedAt),
+		Actor:      l.Actor,
+		ClientIP:   l.ClientIP,
+		Action:     string(l.Action),
+		EntityType: l.EntityType,
+		EntityID:   l.EntityID,
+		EntityName: l.EntityName,
+		Before:     LongText(l.Before),
+		After:      LongText(l.After),
+		Changes:    LongText(toJSON(l.Changes)),
+	}
+}
+
+func (r *AuditLogRepository) toDomain(m *AuditLog) *domain.AuditLog {
+	return &domain.AuditLog{
+		ID:         m.ID,
+		CreatedAt:  fromTimestamp(m.CreatedAt),
+		Actor:      m.Actor,
+		ClientIP:   m.ClientIP,
+		Action:     domain.AuditAction(m.Action),
+		EntityType: m.EntityType,
+		EntityID:   m.EntityID,
+		EntityName: m.EntityName,
+		Before:     string(m.Before),
+		After:      string(m.After),
+		Changes:    fromJSON[[]domain.AuditChange](string(m.Changes)),
+	}
+}
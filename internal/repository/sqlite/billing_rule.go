@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"errors"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"gorm.io/gorm"
+)
+
+type BillingRuleRepository struct {
+	db *DB
+}
+
+func NewBillingRuleRepository(db *DB) *BillingRuleRepository {
+	return &BillingRuleRepository{db: db}
+}
+
+func (r *BillingRuleRepository) Create(rule *domain.BillingRule) error {
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	model := r.toModel(rule)
+	if err := r.db.gorm.Create(model).Error; err != nil {
+		return err
+	}
+	rule.ID = model.ID
+	return nil
+}
+
+func (r *BillingRuleRepository) Update(rule *domain.BillingRule) error {
+	rule.UpdatedAt = time.Now()
+	return r.db.gorm.Save(r.toModel(rule)).Error
+}
+
+func (r *BillingRuleRepository) Delete(id uint64) error {
+	now := time.Now().UnixMilli()
+	return r.db.gorm.Model(&BillingRule{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"deleted_at": now,
+			"updated_at": now,
+		}).Error
+}
+
+func (r *BillingRuleRepository) GetByID(id uint64) (*domain.BillingRule, error) {
+	var model BillingRule
+	if err := r.db.gorm.Where("deleted_at = 0").First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&model), nil
+}
+
+func (r *BillingRuleRepository) List() ([]*domain.BillingRule, error) {
+	var models []BillingRule
+	if err := r.db.gorm.Where("deleted_at = 0").Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	rules := make([]*domain.BillingRule, len(models))
+	for i := range models {
+		rules[i] = r.toDomain(&models[i])
+	}
+	return rules, nil
+}
+
+func (r *BillingRuleRepository) toModel(rule *domain.BillingRule) *BillingRule {
+	return &BillingRule{
+		SoftDeleteModel: SoftDeleteModel{
+			BaseModel: BaseModel{
+				ID:        rule.ID,
+				CreatedAt: toTimestamp(rule.CreatedAt),
+				UpdatedAt: toTimestamp(rule.UpdatedAt),
+			},
+			DeletedAt: toTimestampPtr(rule.DeletedAt),
+		},
+		Name:            rule.Name,
+		ProjectID:       rule.ProjectID,
+		APITokenID:      rule.APITokenID,
+		MarkupBps:       rule.MarkupBps,
+		RequestFee:      rule.RequestFee,
+		FreeTierMonthly: rule.FreeTierMonthly,
+		IsEnabled:       boolToInt(rule.IsEnabled),
+	}
+}
+
+func (r *BillingRuleRepository) toDomain(m *BillingRule) *domain.BillingRule {
+	return &domain.BillingRule{
+		ID:              m.ID,
+		CreatedAt:       fromTimestamp(m.CreatedAt),
+		UpdatedAt:       fromTimestamp(m.UpdatedAt),
+		DeletedAt:       fromTimestampPtr(m.DeletedAt),
+		Name:            m.Name,
+		ProjectID:       m.ProjectID,
+		APITokenID:      m.APITokenID,
+		MarkupBps:       m.MarkupBps,
+		RequestFee:      m.RequestFee,
+		FreeTierMonthly: m.FreeTierMonthly,
+		IsEnabled:       m.IsEnabled == 1,
+	}
+}
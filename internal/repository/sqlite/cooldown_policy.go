@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"errors"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"gorm.io/gorm"
+)
+
+type CooldownPolicyRepository struct {
+	db *DB
+}
+
+func NewCooldownPolicyRepository(db *DB) *CooldownPolicyRepository {
+	return &CooldownPolicyRepository{db: db}
+}
+
+func (r *CooldownPolicyRepository) Create(policy *domain.CooldownPolicy) error {
+	now := time.Now()
+	policy.CreatedAt = now
+	policy.UpdatedAt = now
+
+	model := r.toModel(policy)
+	if err := r.db.gorm.Create(model).Error; err != nil {
+		return err
+	}
+	policy.ID = model.ID
+	return nil
+}
+
+func (r *CooldownPolicyRepository) Update(policy *domain.CooldownPolicy) error {
+	policy.UpdatedAt = time.Now()
+	return r.db.gorm.Save(r.toModel(policy)).Error
+}
+
+func (r *CooldownPolicyRepository) Delete(id uint64) error {
+	return r.db.gorm.Delete(&CooldownPolicy{}, id).Error
+}
+
+func (r *CooldownPolicyRepository) GetByID(id uint64) (*domain.CooldownPolicy, error) {
+	var model CooldownPolicy
+	if err := r.db.gorm.First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&model), nil
+}
+
+func (r *CooldownPolicyRepository) List() ([]*domain.CooldownPolicy, error) {
+	var models []CooldownPolicy
+	if err := r.db.gorm.Order("provider_id, reason").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	policies := make([]*domain.CooldownPolicy, len(models))
+	for i := range models {
+		policies[i] = r.toDomain(&models[i])
+	}
+	return policies, nil
+}
+
+func (r *CooldownPolicyRepository) toModel(p *domain.CooldownPolicy) *CooldownPolicy {
+	return &CooldownPolicy{
+		BaseModel: BaseModel{
+			ID:        p.ID,
+			CreatedAt: toTimestamp(p.CreatedAt),
+			UpdatedAt: toTimestamp(p.UpdatedAt),
+		},
+		ProviderID:  p.ProviderID,
+		Reason:      string(p.Reason),
+		Type:        string(p.Type),
+		BaseSeconds: p.BaseSeconds,
+		MaxSeconds:  p.MaxSeconds,
+	}
+}
+
+func (r *CooldownPolicyRepository) toDomain(m *CooldownPolicy) *domain.CooldownPolicy {
+	return &domain.CooldownPolicy{
+		ID:         m.ID,
+		CreatedAt:  fromTimestamp(m.CreatedAt),
+		UpdatedAt:  fromTimestamp(m.UpdatedAt),
+		ProviderID: m.ProviderID,
+		Reason:     domain.CooldownReason(m.Reason),
+		CooldownPolicyConfig: domain.CooldownPolicyConfig{
+			Type:        domain.CooldownPolicyType(m.Type),
+			BaseSeconds: m.BaseSeconds,
+			MaxSeconds:  m.MaxSeconds,
+		},
+	}
+}
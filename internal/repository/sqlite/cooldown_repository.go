@@ -26,6 +26,14 @@ func (r *CooldownRepository) GetAll() ([]*domain.Cooldown, error) {
 	return r.toDomainList(models), nil
 }
 
+func (r *CooldownRepository) GetEndedAfter(t time.Time) ([]*domain.Cooldown, error) {
+	var models []Cooldown
+	if err := r.db.gorm.Where("until_time > ?", toTimestamp(t)).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(models), nil
+}
+
 func (r *CooldownRepository) GetByProvider(providerID uint64) ([]*domain.Cooldown, error) {
 	now := time.Now().UnixMilli()
 	var models []Cooldown
@@ -35,10 +43,10 @@ func (r *CooldownRepository) GetByProvider(providerID uint64) ([]*domain.Cooldow
 	return r.toDomainList(models), nil
 }
 
-func (r *CooldownRepository) Get(providerID uint64, clientType string) (*domain.Cooldown, error) {
+func (r *CooldownRepository) Get(providerID uint64, clientType string, modelName string) (*domain.Cooldown, error) {
 	now := time.Now().UnixMilli()
 	var model Cooldown
-	err := r.db.gorm.Where("provider_id = ? AND client_type = ? AND until_time > ?", providerID, clientType, now).First(&model).Error
+	err := r.db.gorm.Where("provider_id = ? AND client_type = ? AND model = ? AND until_time > ?", providerID, clientType, modelName, now).First(&model).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -57,12 +65,13 @@ func (r *CooldownRepository) Upsert(cooldown *domain.Cooldown) error {
 		},
 		ProviderID: cooldown.ProviderID,
 		ClientType: cooldown.ClientType,
+		Model:      cooldown.Model,
 		UntilTime:  toTimestamp(cooldown.UntilTime),
 		Reason:     string(cooldown.Reason),
 	}
 
 	err := r.db.gorm.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "provider_id"}, {Name: "client_type"}},
+		Columns: []clause.Column{{Name: "provider_id"}, {Name: "client_type"}, {Name: "model"}},
 		DoUpdates: clause.Assignments(map[string]any{
 			"until_time": model.UntilTime,
 			"reason":     model.Reason,
@@ -79,17 +88,16 @@ func (r *CooldownRepository) Upsert(cooldown *domain.Cooldown) error {
 	return nil
 }
 
-func (r *CooldownRepository) Delete(providerID uint64, clientType string) error {
-	return r.db.gorm.Where("provider_id = ? AND client_type = ?", providerID, clientType).Delete(&Cooldown{}).Error
+func (r *CooldownRepository) Delete(providerID uint64, clientType string, model string) error {
+	return r.db.gorm.Where("provider_id = ? AND client_type = ? AND model = ?", providerID, clientType, model).Delete(&Cooldown{}).Error
 }
 
 func (r *CooldownRepository) DeleteAll(providerID uint64) error {
 	return r.db.gorm.Where("provider_id = ?", providerID).Delete(&Cooldown{}).Error
 }
 
-func (r *CooldownRepository) DeleteExpired() error {
-	now := time.Now().UnixMilli()
-	return r.db.gorm.Where("until_time <= ?", now).Delete(&Cooldown{}).Error
+func (r *CooldownRepository) DeleteExpired(before time.Time) error {
+	return r.db.gorm.Where("until_time <= ?", toTimestamp(before)).Delete(&Cooldown{}).Error
 }
 
 func (r *CooldownRepository) toDomain(m *Cooldown) *domain.Cooldown {
@@ -99,6 +107,7 @@ func (r *CooldownRepository) toDomain(m *Cooldown) *domain.Cooldown {
 		UpdatedAt:  fromTimestamp(m.UpdatedAt),
 		ProviderID: m.ProviderID,
 		ClientType: m.ClientType,
+		Model:      m.Model,
 		UntilTime:  fromTimestamp(m.UntilTime),
 		Reason:     domain.CooldownReason(m.Reason),
 	}
@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"gorm.io/gorm"
+)
+
+type CopilotQuotaRepository struct {
+	db *DB
+}
+
+func NewCopilotQuotaRepository(d *DB) *CopilotQuotaRepository {
+	return &CopilotQuotaRepository{db: d}
+}
+
+func (r *CopilotQuotaRepository) Upsert(quota *domain.CopilotQuota) error {
+	now := time.Now()
+
+	// Try to update first
+	result := r.db.gorm.Model(&CopilotQuota{}).
+		Where("username = ? AND deleted_at = 0", quota.Username).
+		Updates(map[string]any{
+			"updated_at":           toTimestamp(now),
+			"plan":                 quota.Plan,
+			"reset_date":           quota.ResetDate,
+			"is_forbidden":         boolToInt(quota.IsForbidden),
+			"chat":                 toJSON(quota.Chat),
+			"completions":          toJSON(quota.Completions),
+			"premium_interactions": toJSON(quota.PremiumInteractions),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// If no rows updated, insert new record
+	if result.RowsAffected == 0 {
+		model := r.toModel(quota)
+		model.CreatedAt = toTimestamp(now)
+		model.UpdatedAt = toTimestamp(now)
+		model.DeletedAt = 0
+
+		if err := r.db.gorm.Create(model).Error; err != nil {
+			return err
+		}
+		quota.ID = model.ID
+		quota.CreatedAt = now
+	}
+	quota.UpdatedAt = now
+
+	return nil
+}
+
+func (r *CopilotQuotaRepository) GetByUsername(username string) (*domain.CopilotQuota, error) {
+	var model CopilotQuota
+	err := r.db.gorm.Where("username = ? AND deleted_at = 0", username).First(&model).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.toDomain(&model), nil
+}
+
+func (r *CopilotQuotaRepository) List() ([]*domain.CopilotQuota, error) {
+	var models []CopilotQuota
+	if err := r.db.gorm.Where("deleted_at = 0").Order("updated_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(models), nil
+}
+
+func (r *CopilotQuotaRepository) Delete(username string) error {
+	now := time.Now().UnixMilli()
+	return r.db.gorm.Model(&CopilotQuota{}).
+		Where("username = ?", username).
+		Updates(map[string]any{
+			"deleted_at": now,
+			"updated_at": now,
+		}).Error
+}
+
+func (r *CopilotQuotaRepository) toModel(q *domain.CopilotQuota) *CopilotQuota {
+	return &CopilotQuota{
+		SoftDeleteModel: SoftDeleteModel{
+			BaseModel: BaseModel{
+				ID:        q.ID,
+				CreatedAt: toTimestamp(q.CreatedAt),
+				UpdatedAt: toTimestamp(q.UpdatedAt),
+			},
+			DeletedAt: toTimestampPtr(q.DeletedAt),
+		},
+		Username:            q.Username,
+		Plan:                q.Plan,
+		ResetDate:           q.ResetDate,
+		IsForbidden:         boolToInt(q.IsForbidden),
+		Chat:                LongText(toJSON(q.Chat)),
+		Completions:         LongText(toJSON(q.Completions)),
+		PremiumInteractions: LongText(toJSON(q.PremiumInteractions)),
+	}
+}
+
+func (r *CopilotQuotaRepository) toDomain(m *CopilotQuota) *domain.CopilotQuota {
+	return &domain.CopilotQuota{
+		ID:                  m.ID,
+		CreatedAt:           fromTimestamp(m.CreatedAt),
+		UpdatedAt:           fromTimestamp(m.UpdatedAt),
+		DeletedAt:           fromTimestampPtr(m.DeletedAt),
+		Username:            m.Username,
+		Plan:                m.Plan,
+		ResetDate:           m.ResetDate,
+		IsForbidden:         m.IsForbidden == 1,
+		Chat:                fromJSON[*domain.CopilotQuotaSnapshot](string(m.Chat)),
+		Completions:         fromJSON[*domain.CopilotQuotaSnapshot](string(m.Completions)),
+		PremiumInteractions: fromJSON[*domain.CopilotQuotaSnapshot](string(m.PremiumInteractions)),
+	}
+}
+
+func (r *CopilotQuotaRepository) toDomainList(models []CopilotQuota) []*domain.CopilotQuota {
+	quotas := make([]*domain.CopilotQuota, len(models))
+	for i, m := range models {
+		quotas[i] = r.toDomain(&m)
+	}
+	return quotas
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/glebarez/sqlite"
@@ -17,6 +18,8 @@ import (
 type DB struct {
 	gorm      *gorm.DB
 	dialector string // "sqlite", "mysql", or "postgres"
+
+	fullTextReady atomic.Bool // 全文索引已建立完成（见 BackfillFullTextIndex）
 }
 
 // GormDB returns the underlying GORM DB instance
@@ -17,9 +17,9 @@ func NewFailureCountRepository(db *DB) repository.FailureCountRepository {
 	return &FailureCountRepository{db: db}
 }
 
-func (r *FailureCountRepository) Get(providerID uint64, clientType string, reason string) (*domain.FailureCount, error) {
+func (r *FailureCountRepository) Get(providerID uint64, clientType string, modelName string, reason string) (*domain.FailureCount, error) {
 	var model FailureCount
-	err := r.db.gorm.Where("provider_id = ? AND client_type = ? AND reason = ?", providerID, clientType, reason).First(&model).Error
+	err := r.db.gorm.Where("provider_id = ? AND client_type = ? AND model = ? AND reason = ?", providerID, clientType, modelName, reason).First(&model).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -46,13 +46,14 @@ func (r *FailureCountRepository) Upsert(fc *domain.FailureCount) error {
 		},
 		ProviderID:    fc.ProviderID,
 		ClientType:    fc.ClientType,
+		Model:         fc.Model,
 		Reason:        fc.Reason,
 		Count:         fc.Count,
 		LastFailureAt: toTimestamp(fc.LastFailureAt),
 	}
 
 	err := r.db.gorm.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "provider_id"}, {Name: "client_type"}, {Name: "reason"}},
+		Columns: []clause.Column{{Name: "provider_id"}, {Name: "client_type"}, {Name: "model"}, {Name: "reason"}},
 		DoUpdates: clause.Assignments(map[string]any{
 			"count":           fc.Count,
 			"last_failure_at": toTimestamp(fc.LastFailureAt),
@@ -72,8 +73,8 @@ func (r *FailureCountRepository) Upsert(fc *domain.FailureCount) error {
 	return nil
 }
 
-func (r *FailureCountRepository) Delete(providerID uint64, clientType string, reason string) error {
-	return r.db.gorm.Where("provider_id = ? AND client_type = ? AND reason = ?", providerID, clientType, reason).Delete(&FailureCount{}).Error
+func (r *FailureCountRepository) Delete(providerID uint64, clientType string, model string, reason string) error {
+	return r.db.gorm.Where("provider_id = ? AND client_type = ? AND model = ? AND reason = ?", providerID, clientType, model, reason).Delete(&FailureCount{}).Error
 }
 
 func (r *FailureCountRepository) DeleteAll(providerID uint64, clientType string) error {
@@ -97,6 +98,7 @@ func (r *FailureCountRepository) toDomain(m *FailureCount) *domain.FailureCount
 		UpdatedAt:     fromTimestamp(m.UpdatedAt),
 		ProviderID:    m.ProviderID,
 		ClientType:    m.ClientType,
+		Model:         m.Model,
 		Reason:        m.Reason,
 		Count:         m.Count,
 		LastFailureAt: fromTimestamp(m.LastFailureAt),
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fullTextBackfillBatch SQLite 每批补建索引的行数，分批提交以免长时间占用写锁
+const fullTextBackfillBatch = 200
+
+// SQLite FTS5 外部内容表的同步触发器
+// 只删除已建立索引的行，避免尚未补建索引的历史行在更新时破坏索引；
+// GORM Save 会写入所有列，更新触发器只在正文或错误信息变化时才重新分词
+var sqliteFullTextTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS proxy_requests_fts_ai AFTER INSERT ON proxy_requests BEGIN
+		INSERT INTO proxy_requests_fts(rowid, request_info, response_info, error)
+		VALUES (new.id, new.request_info, new.response_info, new.error);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS proxy_requests_fts_ad AFTER DELETE ON proxy_requests BEGIN
+		INSERT INTO proxy_requests_fts(proxy_requests_fts, rowid, request_info, response_info, error)
+		SELECT 'delete', old.id, old.request_info, old.response_info, old.error
+		WHERE EXISTS (SELECT 1 FROM proxy_requests_fts_docsize WHERE id = old.id);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS proxy_requests_fts_au AFTER UPDATE OF request_info, response_info, error ON proxy_requests
+	WHEN old.request_info IS NOT new.request_info OR old.response_info IS NOT new.response_info OR old.error IS NOT new.error
+	BEGIN
+		INSERT INTO proxy_requests_fts(proxy_requests_fts, rowid, request_info, response_info, error)
+		SELECT 'delete', old.id, old.request_info, old.response_info, old.error
+		WHERE EXISTS (SELECT 1 FROM proxy_requests_fts_docsize WHERE id = old.id);
+		INSERT INTO proxy_requests_fts(rowid, request_info, response_info, error)
+		VALUES (new.id, new.request_info, new.response_info, new.error);
+	END`,
+}
+
+// BackfillFullTextIndex 为历史请求建立全文索引（见迁移 v3）
+// 大数据库上建索引耗时较长，启动时在后台调用；完成前搜索退化为 LIKE 匹配
+func (d *DB) BackfillFullTextIndex() {
+	start := time.Now()
+	var err error
+	switch d.dialector {
+	case "mysql":
+		err = d.gorm.Exec("CREATE FULLTEXT INDEX " + proxyRequestFulltextIndex + " ON proxy_requests(request_info, response_info, error)").Error
+		if isMySQLDuplicateIndexError(err) {
+			err = nil
+		}
+	case "postgres":
+		// CONCURRENTLY 不阻塞写入
+		err = d.gorm.Exec("CREATE INDEX CONCURRENTLY IF NOT EXISTS " + proxyRequestFulltextIndex + " ON proxy_requests USING GIN (" + postgresRequestTSVector + ")").Error
+	default:
+		err = d.backfillSQLiteFullText()
+	}
+	if err != nil {
+		log.Printf("[DB] Failed to build full-text index, search keeps using LIKE: %v", err)
+		return
+	}
+	d.fullTextReady.Store(true)
+	log.Printf("[DB] Full-text index ready (%s, took %v)", d.dialector, time.Since(start).Round(time.Millisecond))
+}
+
+// backfillSQLiteFullText 分批为尚未建立索引的行补建索引，新行由触发器维护
+func (d *DB) backfillSQLiteFullText() error {
+	var lastID uint64
+	for {
+		var ids []uint64
+		err := d.gorm.Raw(`SELECT id FROM proxy_requests
+			WHERE id > ? AND id NOT IN (SELECT id FROM proxy_requests_fts_docsize)
+			ORDER BY id LIMIT ?`, lastID, fullTextBackfillBatch).Scan(&ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		// 写入时再次检查，与触发器并发插入的行不会重复建立索引
+		err = d.gorm.Exec(`INSERT INTO proxy_requests_fts(rowid, request_info, response_info, error)
+			SELECT id, request_info, response_info, error FROM proxy_requests
+			WHERE id IN ? AND id NOT IN (SELECT id FROM proxy_requests_fts_docsize)`, ids).Error
+		if err != nil {
+			return err
+		}
+		lastID = ids[len(ids)-1]
+	}
+}
+
+// applyLikeSearch 全文索引尚未就绪时的退化搜索，每个词项都需出现在正文或错误信息中
+func applyLikeSearch(query *gorm.DB, terms []string) *gorm.DB {
+	for _, term := range terms {
+		pattern := "%" + escapeLike(strings.ToLower(term)) + "%"
+		query = query.Where("(LOWER(request_info) LIKE ? ESCAPE '!' OR LOWER(response_info) LIKE ? ESCAPE '!' OR LOWER(error) LIKE ? ESCAPE '!')",
+			pattern, pattern, pattern)
+	}
+	return query
+}
@@ -1,12 +1,15 @@
 package sqlite
 
 import (
+	"encoding/json"
 	"errors"
 	"log"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/awsl-project/maxx/internal/domain"
 	mysqlDriver "github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 )
@@ -85,8 +88,243 @@ var migrations = []Migration{
 			}
 		},
 	},
+	{
+		Version:     3,
+		Description: "Add full-text index over proxy request details",
+		Up: func(db *gorm.DB) error {
+			// MySQL / PostgreSQL 的索引以及 SQLite 的历史数据索引在启动后由 BackfillFullTextIndex 后台建立，
+			// 避免大数据库阻塞启动
+			switch db.Dialector.Name() {
+			case "mysql", "postgres":
+				return nil
+			default:
+				// FTS5 外部内容表 + 触发器，与 proxy_requests 保持同步
+				statements := append([]string{
+					`CREATE VIRTUAL TABLE IF NOT EXISTS proxy_requests_fts USING fts5(
+						request_info, response_info, error,
+						content='proxy_requests', content_rowid='id'
+					)`,
+				}, sqliteFullTextTriggers...)
+				for _, sql := range statements {
+					if err := db.Exec(sql).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		},
+		Down: func(db *gorm.DB) error {
+			switch db.Dialector.Name() {
+			case "mysql":
+				sql := "DROP INDEX " + proxyRequestFulltextIndex + " ON proxy_requests"
+				if err := db.Exec(sql).Error; err != nil {
+					log.Printf("[Migration] Warning: rollback v3 failed (dialector=mysql) sql=%q err=%v", sql, err)
+				}
+				return nil
+			case "postgres":
+				return db.Exec("DROP INDEX IF EXISTS " + proxyRequestFulltextIndex).Error
+			default:
+				for _, sql := range []string{
+					"DROP TRIGGER IF EXISTS proxy_requests_fts_ai",
+					"DROP TRIGGER IF EXISTS proxy_requests_fts_ad",
+					"DROP TRIGGER IF EXISTS proxy_requests_fts_au",
+					"DROP TABLE IF EXISTS proxy_requests_fts",
+				} {
+					if err := db.Exec(sql).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		},
+	},
+	{
+		Version:     4,
+		Description: "Backfill upstream_cost and billed_cost from cost",
+		Up: func(db *gorm.DB) error {
+			// 历史数据没有计费规则：计费金额等于成本，上游成本按记录的倍率反推
+			for _, table := range []string{"proxy_upstream_attempts", "proxy_requests"} {
+				sql := "UPDATE " + table + ` SET
+					upstream_cost = CASE WHEN multiplier > 0 THEN cost * 10000 / multiplier ELSE cost END,
+					billed_cost = cost
+					WHERE cost > 0 AND upstream_cost = 0 AND billed_cost = 0`
+				if err := db.Exec(sql).Error; err != nil {
+					return err
+				}
+			}
+			// usage_stats 没有倍率信息，只能视为未加价
+			return db.Exec("UPDATE usage_stats SET upstream_cost = cost, billed_cost = cost WHERE cost > 0 AND upstream_cost = 0 AND billed_cost = 0").Error
+		},
+		Down: func(db *gorm.DB) error {
+			for _, table := range []string{"proxy_upstream_attempts", "proxy_requests", "usage_stats"} {
+				if err := db.Exec("UPDATE " + table + " SET upstream_cost = 0, billed_cost = 0").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     5,
+		Description: "Drop provider-wide unique indexes on cooldowns and failure_counts",
+		Up: func(db *gorm.DB) error {
+			// 冷却改为按模型区分后，唯一索引中新增了 model 列（由 AutoMigrate 以新名字创建），
+			// 旧索引仍约束 provider+clientType 唯一，会导致同一 Provider 下无法存在多个模型的冷却记录
+			for table, index := range legacyCooldownIndexes {
+				switch db.Dialector.Name() {
+				case "mysql":
+					err := db.Exec("DROP INDEX " + index + " ON " + table).Error
+					if err != nil && !isMySQLMissingIndexError(err) {
+						return err
+					}
+				default:
+					if err := db.Exec("DROP INDEX IF EXISTS " + index).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			// 旧索引无法在存在按模型冷却的数据时恢复，回滚时保持不变
+			log.Printf("[Migration] Rollback v5 leaves legacy cooldown indexes dropped")
+			return nil
+		},
+	},
+	{
+		Version:     6,
+		Description: "Move provider circuit breaker policies into cooldown_policies",
+		Up:          migrateProviderCooldownPolicies,
+		Down: func(db *gorm.DB) error {
+			// 迁移出的记录可能已被修改，回滚时不删除，也不写回 Provider 配置
+			log.Printf("[Migration] Rollback v6 keeps migrated cooldown policies")
+			return nil
+		},
+	},
+	{
+		Version:     7,
+		Description: "Only re-index proxy request details when their content changes",
+		Up: func(db *gorm.DB) error {
+			if name := db.Dialector.Name(); name == "mysql" || name == "postgres" {
+				return nil
+			}
+			// 旧版触发器在每次更新状态或用量时都会重新分词全部正文
+			for _, sql := range append([]string{
+				"DROP TRIGGER IF EXISTS proxy_requests_fts_ad",
+				"DROP TRIGGER IF EXISTS proxy_requests_fts_au",
+			}, sqliteFullTextTriggers...) {
+				if err := db.Exec(sql).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			log.Printf("[Migration] Rollback v7 keeps the conditional full-text triggers")
+			return nil
+		},
+	},
+}
+
+// migrateProviderCooldownPolicies 将 Provider 配置 circuitBreaker.policies 中的冷却策略迁移到 cooldown_policies，
+// 并从 Provider 配置中移除 policies；已存在相同 Provider + 原因的记录时保留现有记录，无效的策略跳过
+func migrateProviderCooldownPolicies(db *gorm.DB) error {
+	var providers []Provider
+	if err := db.Where("deleted_at = 0").Find(&providers).Error; err != nil {
+		return err
+	}
+	for _, p := range providers {
+		if p.Config == "" {
+			continue
+		}
+		var config struct {
+			CircuitBreaker *struct {
+				Policies map[domain.CooldownReason]domain.CooldownPolicyConfig `json:"policies"`
+			} `json:"circuitBreaker"`
+		}
+		if err := json.Unmarshal([]byte(p.Config), &config); err != nil {
+			log.Printf("[Migration] Skip provider %d: invalid config: %v", p.ID, err)
+			continue
+		}
+		if config.CircuitBreaker == nil {
+			continue
+		}
+		for reason, policy := range config.CircuitBreaker.Policies {
+			if !slices.Contains(domain.CooldownPolicyReasons, reason) {
+				log.Printf("[Migration] Skip cooldown policy provider=%d: unsupported reason %s", p.ID, reason)
+				continue
+			}
+			if err := policy.Validate(); err != nil {
+				log.Printf("[Migration] Skip cooldown policy provider=%d reason=%s: %v", p.ID, reason, err)
+				continue
+			}
+			var count int64
+			if err := db.Model(&CooldownPolicy{}).
+				Where("provider_id = ? AND reason = ?", p.ID, string(reason)).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+			if err := db.Create(&CooldownPolicy{
+				ProviderID:  p.ID,
+				Reason:      string(reason),
+				Type:        string(policy.Type),
+				BaseSeconds: policy.BaseSeconds,
+				MaxSeconds:  policy.MaxSeconds,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		if config.CircuitBreaker.Policies == nil {
+			continue
+		}
+		cleaned, err := removeCircuitBreakerPolicies(p.Config)
+		if err != nil {
+			return err
+		}
+		if err := db.Model(&Provider{}).Where("id = ?", p.ID).Update("config", cleaned).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeCircuitBreakerPolicies 删除 Provider 配置 JSON 中的 circuitBreaker.policies，其余字段原样保留
+func removeCircuitBreakerPolicies(config LongText) (LongText, error) {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(config), &root); err != nil {
+		return "", err
+	}
+	var breaker map[string]json.RawMessage
+	if err := json.Unmarshal(root["circuitBreaker"], &breaker); err != nil {
+		return "", err
+	}
+	delete(breaker, "policies")
+	b, err := json.Marshal(breaker)
+	if err != nil {
+		return "", err
+	}
+	root["circuitBreaker"] = b
+	out, err := json.Marshal(root)
+	if err != nil {
+		return "", err
+	}
+	return LongText(out), nil
 }
 
+// legacyCooldownIndexes 按模型冷却之前使用的唯一索引（表名 -> 索引名）
+var legacyCooldownIndexes = map[string]string{
+	"cooldowns":      "idx_cooldowns_provider_client",
+	"failure_counts": "idx_failure_counts_provider_client_reason",
+}
+
+const proxyRequestFulltextIndex = "idx_proxy_requests_fulltext"
+
+// postgresRequestTSVector 全文索引表达式，查询时必须使用相同的表达式才能命中索引
+const postgresRequestTSVector = "to_tsvector('simple', coalesce(request_info, '') || ' ' || coalesce(response_info, '') || ' ' || coalesce(error, ''))"
+
 func isMySQLDuplicateIndexError(err error) bool {
 	if err == nil {
 		return false
@@ -100,6 +338,15 @@ func isMySQLDuplicateIndexError(err error) bool {
 	return strings.Contains(lower, "duplicate key name") || strings.Contains(lower, "error 1061")
 }
 
+func isMySQLMissingIndexError(err error) bool {
+	var mysqlErr *mysqlDriver.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1091 // ER_CANT_DROP_FIELD_OR_KEY
+	}
+	lower := strings.ToLower(err.Error())
+	return strings.Contains(lower, "can't drop") || strings.Contains(lower, "error 1091")
+}
+
 // RunMigrations 运行所有待执行的迁移
 func (d *DB) RunMigrations() error {
 	// 确保迁移表存在（由 GORM AutoMigrate 处理）
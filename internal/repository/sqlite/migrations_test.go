@@ -2,8 +2,10 @@ package sqlite
 
 import (
 	"errors"
+	"path/filepath"
 	"testing"
 
+	"github.com/awsl-project/maxx/internal/domain"
 	mysqlDriver "github.com/go-sql-driver/mysql"
 )
 
@@ -21,3 +23,65 @@ func TestIsMySQLDuplicateIndexError(t *testing.T) {
 		t.Fatalf("expected false for unrelated error")
 	}
 }
+
+func TestMigrateProviderCooldownPolicies(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "maxx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	provider := &Provider{Type: "custom", Name: "legacy", Config: `{"circuitBreaker":{"halfOpenMaxRequests":2,"policies":{
+		"server_error":{"type":"fixed","baseSeconds":30},
+		"rate_limit_exceeded":{"type":"exponential","baseSeconds":5,"maxSeconds":600},
+		"network_error":{"type":"bogus","baseSeconds":1}
+	}}}`}
+	if err := db.gorm.Create(provider).Error; err != nil {
+		t.Fatal(err)
+	}
+	repo := NewCooldownPolicyRepository(db)
+	// 已通过新接口保存的策略优先
+	existing := &domain.CooldownPolicy{
+		ProviderID:           provider.ID,
+		Reason:               domain.CooldownReasonServerError,
+		CooldownPolicyConfig: domain.CooldownPolicyConfig{Type: domain.CooldownPolicyLinear, BaseSeconds: 10},
+	}
+	if err := repo.Create(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrateProviderCooldownPolicies(db.gorm); err != nil {
+		t.Fatal(err)
+	}
+
+	policies, err := repo.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byReason := make(map[domain.CooldownReason]*domain.CooldownPolicy)
+	for _, p := range policies {
+		if p.ProviderID != provider.ID {
+			t.Fatalf("unexpected policy for provider %d", p.ProviderID)
+		}
+		byReason[p.Reason] = p
+	}
+	if len(byReason) != 2 {
+		t.Fatalf("expected 2 policies, got %+v", byReason)
+	}
+	if p := byReason[domain.CooldownReasonServerError]; p.Type != domain.CooldownPolicyLinear || p.BaseSeconds != 10 {
+		t.Errorf("existing policy should be kept, got %+v", p.CooldownPolicyConfig)
+	}
+	p := byReason[domain.CooldownReasonRateLimitExceeded]
+	if p == nil || p.Type != domain.CooldownPolicyExponential || p.BaseSeconds != 5 || p.MaxSeconds != 600 {
+		t.Errorf("legacy policy not migrated, got %+v", p)
+	}
+
+	// 迁移后从 Provider 配置中移除 policies，其余熔断设置保留
+	var migrated Provider
+	if err := db.gorm.First(&migrated, provider.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"circuitBreaker":{"halfOpenMaxRequests":2}}`; string(migrated.Config) != want {
+		t.Errorf("config = %s, want %s", migrated.Config, want)
+	}
+}
@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"errors"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"gorm.io/gorm"
+)
+
+type ModelFallbackRepository struct {
+	db *DB
+}
+
+func NewModelFallbackRepository(db *DB) *ModelFallbackRepository {
+	return &ModelFallbackRepository{db: db}
+}
+
+func (r *ModelFallbackRepository) Create(fallback *domain.ModelFallback) error {
+	now := time.Now()
+	fallback.CreatedAt = now
+	fallback.UpdatedAt = now
+
+	model := r.toModel(fallback)
+	if err := r.db.gorm.Create(model).Error; err != nil {
+		return err
+	}
+	fallback.ID = model.ID
+	return nil
+}
+
+func (r *ModelFallbackRepository) Update(fallback *domain.ModelFallback) error {
+	fallback.UpdatedAt = time.Now()
+	return r.db.gorm.Save(r.toModel(fallback)).Error
+}
+
+func (r *ModelFallbackRepository) Delete(id uint64) error {
+	return r.db.gorm.Delete(&ModelFallback{}, id).Error
+}
+
+func (r *ModelFallbackRepository) GetByID(id uint64) (*domain.ModelFallback, error) {
+	var model ModelFallback
+	if err := r.db.gorm.First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&model), nil
+}
+
+func (r *ModelFallbackRepository) List() ([]*domain.ModelFallback, error) {
+	var models []ModelFallback
+	if err := r.db.gorm.Order("priority, id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	fallbacks := make([]*domain.ModelFallback, len(models))
+	for i := range models {
+		fallbacks[i] = r.toDomain(&models[i])
+	}
+	return fallbacks, nil
+}
+
+func (r *ModelFallbackRepository) toModel(f *domain.ModelFallback) *ModelFallback {
+	return &ModelFallback{
+		BaseModel: BaseModel{
+			ID:        f.ID,
+			CreatedAt: toTimestamp(f.CreatedAt),
+			UpdatedAt: toTimestamp(f.UpdatedAt),
+		},
+		ClientType:   string(f.ClientType),
+		ProjectID:    f.ProjectID,
+		Pattern:      f.Pattern,
+		Models:       LongText(toJSON(f.Models)),
+		NotifyClient: boolToInt(f.NotifyClient),
+		Priority:     f.Priority,
+	}
+}
+
+func (r *ModelFallbackRepository) toDomain(m *ModelFallback) *domain.ModelFallback {
+	return &domain.ModelFallback{
+		ID:           m.ID,
+		CreatedAt:    fromTimestamp(m.CreatedAt),
+		UpdatedAt:    fromTimestamp(m.UpdatedAt),
+		ClientType:   domain.ClientType(m.ClientType),
+		ProjectID:    m.ProjectID,
+		Pattern:      m.Pattern,
+		Models:       fromJSON[[]string](string(m.Models)),
+		NotifyClient: m.NotifyClient == 1,
+		Priority:     m.Priority,
+	}
+}
@@ -67,11 +67,11 @@ func (r *ModelPriceRepository) GetByID(id uint64) (*domain.ModelPrice, error) {
 	return r.toDomain(&m), nil
 }
 
-// GetCurrentByModelID 获取模型的当前价格（最新记录），支持前缀匹配
+// GetCurrentByModelID 获取模型的当前全局价格（最新记录），支持前缀匹配
 func (r *ModelPriceRepository) GetCurrentByModelID(modelID string) (*domain.ModelPrice, error) {
 	// 1. 精确匹配
 	var exact ModelPrice
-	err := r.db.gorm.Where("model_id = ? AND deleted_at = 0", modelID).
+	err := r.db.gorm.Where("model_id = ? AND provider_id = 0 AND deleted_at = 0", modelID).
 		Order("created_at DESC").
 		First(&exact).Error
 	if err == nil {
@@ -81,7 +81,7 @@ func (r *ModelPriceRepository) GetCurrentByModelID(modelID string) (*domain.Mode
 	// 2. 前缀匹配：获取所有可能的前缀，找最长匹配
 	var allPrices []ModelPrice
 	if err := r.db.gorm.
-		Where("deleted_at = 0").
+		Where("provider_id = 0 AND deleted_at = 0").
 		Select("DISTINCT model_id").
 		Find(&allPrices).Error; err != nil {
 		return nil, err
@@ -100,7 +100,7 @@ func (r *ModelPriceRepository) GetCurrentByModelID(modelID string) (*domain.Mode
 
 	// 获取最佳匹配的最新价格
 	var m ModelPrice
-	if err := r.db.gorm.Where("model_id = ? AND deleted_at = 0", bestMatch).
+	if err := r.db.gorm.Where("model_id = ? AND provider_id = 0 AND deleted_at = 0", bestMatch).
 		Order("created_at DESC").
 		First(&m).Error; err != nil {
 		return nil, err
@@ -108,13 +108,13 @@ func (r *ModelPriceRepository) GetCurrentByModelID(modelID string) (*domain.Mode
 	return r.toDomain(&m), nil
 }
 
-// ListCurrentPrices 获取所有模型的当前价格（每个 model_id 的最新记录）
+// ListCurrentPrices 获取所有模型的当前价格（每个 provider_id + model_id 的最新记录，包含 Provider 覆盖价格）
 func (r *ModelPriceRepository) ListCurrentPrices() ([]*domain.ModelPrice, error) {
-	// 使用子查询获取每个 model_id 的最新 ID (只查询未删除的记录)
+	// 使用子查询获取每个 provider_id + model_id 的最新 ID (只查询未删除的记录)
 	subQuery := r.db.gorm.Model(&ModelPrice{}).
 		Where("deleted_at = 0").
-		Select("model_id, MAX(id) as max_id").
-		Group("model_id")
+		Select("provider_id, model_id, MAX(id) as max_id").
+		Group("provider_id, model_id")
 
 	var models []ModelPrice
 	if err := r.db.gorm.
@@ -131,10 +131,10 @@ func (r *ModelPriceRepository) ListCurrentPrices() ([]*domain.ModelPrice, error)
 	return result, nil
 }
 
-// ListByModelID 获取模型的价格历史
-func (r *ModelPriceRepository) ListByModelID(modelID string) ([]*domain.ModelPrice, error) {
+// ListByModelID 获取模型的价格历史（providerID 为 0 表示全局价格）
+func (r *ModelPriceRepository) ListByModelID(providerID uint64, modelID string) ([]*domain.ModelPrice, error) {
 	var models []ModelPrice
-	if err := r.db.gorm.Where("model_id = ? AND deleted_at = 0", modelID).
+	if err := r.db.gorm.Where("model_id = ? AND provider_id = ? AND deleted_at = 0", modelID, providerID).
 		Order("created_at DESC").
 		Find(&models).Error; err != nil {
 		return nil, err
@@ -162,15 +162,15 @@ func (r *ModelPriceRepository) Delete(id uint64) error {
 		Update("deleted_at", time.Now().UnixMilli()).Error
 }
 
-// SoftDeleteAll 软删除所有价格记录
+// SoftDeleteAll 软删除所有全局价格记录（Provider 覆盖价格保留）
 func (r *ModelPriceRepository) SoftDeleteAll() error {
-	return r.db.gorm.Model(&ModelPrice{}).Where("deleted_at = 0").
+	return r.db.gorm.Model(&ModelPrice{}).Where("provider_id = 0 AND deleted_at = 0").
 		Update("deleted_at", time.Now().UnixMilli()).Error
 }
 
-// ResetToDefaults 重置为默认价格（软删除现有记录，插入默认价格）
+// ResetToDefaults 重置为默认价格（软删除现有全局记录，插入默认价格）
 func (r *ModelPriceRepository) ResetToDefaults() ([]*domain.ModelPrice, error) {
-	// 1. 软删除所有现有记录
+	// 1. 软删除所有现有全局记录
 	if err := r.SoftDeleteAll(); err != nil {
 		return nil, err
 	}
@@ -183,6 +183,8 @@ func (r *ModelPriceRepository) ResetToDefaults() ([]*domain.ModelPrice, error) {
 	for _, p := range allPrices {
 		domainPrices = append(domainPrices, &domain.ModelPrice{
 			ModelID:                p.ModelID,
+			Currency:               domain.CurrencyUSD,
+			Source:                 domain.PriceSourceDefault,
 			InputPriceMicro:        p.InputPriceMicro,
 			OutputPriceMicro:       p.OutputPriceMicro,
 			CacheReadPriceMicro:    p.CacheReadPriceMicro,
@@ -216,6 +218,9 @@ func (r *ModelPriceRepository) toDomain(m *ModelPrice) *domain.ModelPrice {
 		ID:                     m.ID,
 		CreatedAt:              fromTimestamp(m.CreatedAt),
 		ModelID:                m.ModelID,
+		ProviderID:             m.ProviderID,
+		Currency:               m.Currency,
+		Source:                 m.Source,
 		InputPriceMicro:        m.InputPriceMicro,
 		OutputPriceMicro:       m.OutputPriceMicro,
 		CacheReadPriceMicro:    m.CacheReadPriceMicro,
@@ -239,6 +244,9 @@ func (r *ModelPriceRepository) fromDomain(p *domain.ModelPrice) *ModelPrice {
 		ID:                     p.ID,
 		CreatedAt:              toTimestamp(p.CreatedAt),
 		ModelID:                p.ModelID,
+		ProviderID:             p.ProviderID,
+		Currency:               p.Currency,
+		Source:                 p.Source,
 		InputPriceMicro:        p.InputPriceMicro,
 		OutputPriceMicro:       p.OutputPriceMicro,
 		CacheReadPriceMicro:    p.CacheReadPriceMicro,
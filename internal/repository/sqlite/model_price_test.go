@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+func TestModelPriceRepository_ProviderOverrides(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "maxx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewModelPriceRepository(db)
+
+	prices := []*domain.ModelPrice{
+		{ModelID: "claude-sonnet-4", InputPriceMicro: 3_000_000},
+		{ModelID: "claude-sonnet-4", ProviderID: 5, Currency: "CNY", InputPriceMicro: 14_400_000},
+		{ModelID: "claude-sonnet-4", ProviderID: 5, Currency: "CNY", InputPriceMicro: 15_000_000},
+	}
+	if err := repo.BatchCreate(prices); err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := repo.ListCurrentPrices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(current) != 2 {
+		t.Fatalf("expected global and override price, got %d", len(current))
+	}
+	for _, p := range current {
+		if p.ProviderID == 5 && (p.ID != prices[2].ID || p.Currency != "CNY") {
+			t.Fatalf("unexpected override %+v", p)
+		}
+	}
+
+	global, err := repo.GetCurrentByModelID("claude-sonnet-4-20250514")
+	if err != nil || global == nil || global.ID != prices[0].ID {
+		t.Fatalf("expected global price, got %+v, %v", global, err)
+	}
+	history, err := repo.ListByModelID(5, "claude-sonnet-4")
+	if err != nil || len(history) != 2 {
+		t.Fatalf("unexpected override history %v, %v", history, err)
+	}
+
+	// 重置默认价格不影响 Provider 覆盖价格
+	defaults, err := repo.ResetToDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defaults) == 0 || defaults[0].Source != domain.PriceSourceDefault {
+		t.Fatalf("unexpected defaults %+v", defaults)
+	}
+	current, err = repo.ListCurrentPrices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var overrides int
+	for _, p := range current {
+		if p.ProviderID == 5 {
+			overrides++
+		}
+	}
+	if overrides != 1 || len(current) != len(defaults)+1 {
+		t.Fatalf("expected override to survive reset, got %d overrides in %d prices", overrides, len(current))
+	}
+}
@@ -78,6 +78,7 @@ type Project struct {
 	Name                string `gorm:"size:255"`
 	Slug                string `gorm:"size:128"`
 	EnabledCustomRoutes LongText
+	Priority            string `gorm:"size:32"`
 }
 
 func (Project) TableName() string { return "projects" }
@@ -89,6 +90,8 @@ type Session struct {
 	ClientType string `gorm:"size:64"`
 	ProjectID  uint64
 	RejectedAt int64
+	// 循环检测触发后管理员批准继续的时间
+	LoopApprovedAt int64
 }
 
 func (Session) TableName() string { return "sessions" }
@@ -140,9 +143,17 @@ type APIToken struct {
 	ProjectID   uint64
 	IsEnabled   int `gorm:"default:1"`
 	DevMode     int `gorm:"default:0"`
+	Priority    string `gorm:"size:32"`
 	ExpiresAt   int64
 	LastUsedAt  int64
 	UseCount    uint64
+
+	// 权限范围
+	AllowedClientTypes LongText
+	AllowedModels      LongText
+	AllowedProviderIDs LongText
+	AllowedIPs         LongText
+	ProjectAdmin       int `gorm:"default:0"`
 }
 
 func (APIToken) TableName() string { return "api_tokens" }
@@ -164,6 +175,19 @@ type ModelMapping struct {
 
 func (ModelMapping) TableName() string { return "model_mappings" }
 
+// ModelFallback model - 模型降级链
+type ModelFallback struct {
+	BaseModel
+	ClientType   string `gorm:"size:64"`
+	ProjectID    uint64
+	Pattern      string `gorm:"size:255"`
+	Models       LongText
+	NotifyClient int
+	Priority     int
+}
+
+func (ModelFallback) TableName() string { return "model_fallbacks" }
+
 // AntigravityQuota model
 type AntigravityQuota struct {
 	SoftDeleteModel
@@ -192,6 +216,20 @@ type CodexQuota struct {
 
 func (CodexQuota) TableName() string { return "codex_quotas" }
 
+// CopilotQuota model
+type CopilotQuota struct {
+	SoftDeleteModel
+	Username            string `gorm:"size:255;uniqueIndex"`
+	Plan                string `gorm:"size:64"`
+	ResetDate           string `gorm:"size:32"`
+	IsForbidden         int
+	Chat                LongText // JSON
+	Completions         LongText // JSON
+	PremiumInteractions LongText // JSON
+}
+
+func (CopilotQuota) TableName() string { return "copilot_quotas" }
+
 // ==================== Log/Status/Stats Models (no soft delete) ====================
 
 // ProxyRequest model
@@ -222,6 +260,8 @@ type ProxyRequest struct {
 	ModelPriceID                uint64 // 使用的模型价格记录ID
 	Multiplier                  uint64 // 倍率（10000=1倍）
 	Cost                        uint64
+	UpstreamCost                uint64 // 上游真实成本（未应用倍率）
+	BilledCost                  uint64 // 按计费规则计费的金额
 	RouteID                     uint64
 	ProviderID                  uint64 `gorm:"index"`
 	IsStream                    int
@@ -237,6 +277,7 @@ func (ProxyRequest) TableName() string { return "proxy_requests" }
 type ProxyUpstreamAttempt struct {
 	BaseModel
 	Status            string `gorm:"size:64;index:idx_attempts_status_endtime;index"`
+	FailureReason     string `gorm:"size:64"`
 	ProxyRequestID    uint64 `gorm:"index"`
 	RequestInfo       LongText
 	ResponseInfo      LongText
@@ -251,6 +292,8 @@ type ProxyUpstreamAttempt struct {
 	ModelPriceID      uint64 // 使用的模型价格记录ID
 	Multiplier        uint64 // 倍率（10000=1倍）
 	Cost              uint64
+	UpstreamCost      uint64
+	BilledCost        uint64
 	IsStream          int
 	StartTime         int64
 	EndTime           int64  `gorm:"index:idx_attempts_status_endtime"`
@@ -259,6 +302,8 @@ type ProxyUpstreamAttempt struct {
 	RequestModel      string `gorm:"size:128"`
 	MappedModel       string `gorm:"size:128"`
 	ResponseModel     string `gorm:"size:128"`
+	FallbackStep      int
+	FallbackModel     string `gorm:"size:128"`
 }
 
 func (ProxyUpstreamAttempt) TableName() string { return "proxy_upstream_attempts" }
@@ -276,20 +321,34 @@ func (SystemSetting) TableName() string { return "system_settings" }
 // Cooldown model
 type Cooldown struct {
 	BaseModel
-	ProviderID uint64 `gorm:"uniqueIndex:idx_cooldowns_provider_client"`
-	ClientType string `gorm:"size:255;uniqueIndex:idx_cooldowns_provider_client"`
+	ProviderID uint64 `gorm:"uniqueIndex:idx_cooldowns_provider_client_model"`
+	ClientType string `gorm:"size:255;uniqueIndex:idx_cooldowns_provider_client_model"`
+	Model      string `gorm:"size:128;not null;default:'';uniqueIndex:idx_cooldowns_provider_client_model"`
 	UntilTime  int64  `gorm:"index"`
 	Reason     string `gorm:"size:64;default:'unknown'"`
 }
 
 func (Cooldown) TableName() string { return "cooldowns" }
 
+// CooldownPolicy model - 冷冻时长策略（ProviderID 为 0 时为全局默认）
+type CooldownPolicy struct {
+	BaseModel
+	ProviderID  uint64 `gorm:"uniqueIndex:idx_cooldown_policies_provider_reason"`
+	Reason      string `gorm:"size:64;uniqueIndex:idx_cooldown_policies_provider_reason"`
+	Type        string `gorm:"size:32"`
+	BaseSeconds int
+	MaxSeconds  int
+}
+
+func (CooldownPolicy) TableName() string { return "cooldown_policies" }
+
 // FailureCount model
 type FailureCount struct {
 	BaseModel
-	ProviderID    uint64 `gorm:"uniqueIndex:idx_failure_counts_provider_client_reason"`
-	ClientType    string `gorm:"size:255;uniqueIndex:idx_failure_counts_provider_client_reason"`
-	Reason        string `gorm:"size:255;uniqueIndex:idx_failure_counts_provider_client_reason"`
+	ProviderID    uint64 `gorm:"uniqueIndex:idx_failure_counts_provider_client_model_reason"`
+	ClientType    string `gorm:"size:255;uniqueIndex:idx_failure_counts_provider_client_model_reason"`
+	Model         string `gorm:"size:128;not null;default:'';uniqueIndex:idx_failure_counts_provider_client_model_reason"`
+	Reason        string `gorm:"size:255;uniqueIndex:idx_failure_counts_provider_client_model_reason"`
 	Count         int
 	LastFailureAt int64 `gorm:"index"`
 }
@@ -318,6 +377,8 @@ type UsageStats struct {
 	CacheRead          uint64
 	CacheWrite         uint64
 	Cost               uint64
+	UpstreamCost       uint64
+	BilledCost         uint64
 }
 
 func (UsageStats) TableName() string { return "usage_stats" }
@@ -348,6 +409,9 @@ type ModelPrice struct {
 	CreatedAt              int64
 	DeletedAt              int64  `gorm:"index"` // 软删除时间
 	ModelID                string `gorm:"size:128;index"`
+	ProviderID             uint64 `gorm:"index"`
+	Currency               string `gorm:"size:8"`
+	Source                 string `gorm:"size:64"`
 	InputPriceMicro        uint64
 	OutputPriceMicro       uint64
 	CacheReadPriceMicro    uint64
@@ -363,6 +427,54 @@ type ModelPrice struct {
 
 func (ModelPrice) TableName() string { return "model_prices" }
 
+// AuditLog model - 管理配置变更审计日志
+type AuditLog struct {
+	ID         uint64 `gorm:"primaryKey;autoIncrement"`
+	CreatedAt  int64  `gorm:"index"`
+	Actor      string `gorm:"size:255;index"`
+	ClientIP   string `gorm:"size:64"`
+	Action     string `gorm:"size:32"`
+	EntityType string `gorm:"size:64;index:idx_audit_logs_entity"`
+	EntityID   string `gorm:"size:255;index:idx_audit_logs_entity"`
+	EntityName string `gorm:"size:255"`
+	Before     LongText
+	After      LongText
+	Changes    LongText // JSON
+}
+
+func (AuditLog) TableName() string { return "audit_logs" }
+
+// ProviderHealthCheck model - Provider 主动健康探测结果
+type ProviderHealthCheck struct {
+	ID            uint64 `gorm:"primaryKey;autoIncrement"`
+	CreatedAt     int64  `gorm:"index"`
+	ProviderID    uint64 `gorm:"index:idx_provider_health_checks_provider"`
+	ClientType    string `gorm:"size:64;index:idx_provider_health_checks_provider"`
+	Model         string `gorm:"size:255"`
+	Success       int
+	StatusCode    int
+	LatencyMs     int64
+	Error         LongText
+	Reason        string `gorm:"size:64"`
+	CooldownUntil int64
+}
+
+func (ProviderHealthCheck) TableName() string { return "provider_health_checks" }
+
+// BillingRule model - 计费规则（项目或 API Token 维度的加价、固定费用、免费额度）
+type BillingRule struct {
+	SoftDeleteModel
+	Name            string `gorm:"size:255"`
+	ProjectID       uint64 `gorm:"index"`
+	APITokenID      uint64 `gorm:"index"`
+	MarkupBps       int64
+	RequestFee      uint64
+	FreeTierMonthly uint64
+	IsEnabled       int `gorm:"default:1"`
+}
+
+func (BillingRule) TableName() string { return "billing_rules" }
+
 // ==================== All Models for AutoMigrate ====================
 
 // AllModels returns all GORM models for auto-migration
@@ -376,16 +488,22 @@ func AllModels() []any {
 		&RoutingStrategy{},
 		&APIToken{},
 		&ModelMapping{},
+		&ModelFallback{},
 		&AntigravityQuota{},
 		&CodexQuota{},
+		&CopilotQuota{},
 		&ProxyRequest{},
 		&ProxyUpstreamAttempt{},
 		&SystemSetting{},
 		&Cooldown{},
 		&FailureCount{},
+		&CooldownPolicy{},
 		&UsageStats{},
 		&ResponseModel{},
 		&ModelPrice{},
+		&AuditLog{},
+		&ProviderHealthCheck{},
+		&BillingRule{},
 		&SchemaMigration{},
 	}
 }
@@ -125,6 +125,7 @@ func (r *ProjectRepository) toModel(p *domain.Project) *Project {
 		Name:                p.Name,
 		Slug:                p.Slug,
 		EnabledCustomRoutes: LongText(toJSON(p.EnabledCustomRoutes)),
+		Priority:            string(p.Priority),
 	}
 }
 
@@ -137,6 +138,7 @@ func (r *ProjectRepository) toDomain(m *Project) *domain.Project {
 		Name:                m.Name,
 		Slug:                m.Slug,
 		EnabledCustomRoutes: fromJSON[[]domain.ClientType](string(m.EnabledCustomRoutes)),
+		Priority:            domain.PriorityClass(m.Priority),
 	}
 }
 
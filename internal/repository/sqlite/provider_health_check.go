@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+type ProviderHealthCheckRepository struct {
+	db *DB
+}
+
+func NewProviderHealthCheckRepository(db *DB) *ProviderHealthCheckRepository {
+	return &ProviderHealthCheckRepository{db: db}
+}
+
+func (r *ProviderHealthCheckRepository) Create(c *domain.ProviderHealthCheck) error {
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+
+	model := r.toModel(c)
+	if err := r.db.gorm.Create(model).Error; err != nil {
+		return err
+	}
+	c.ID = model.ID
+	return nil
+}
+
+func (r *ProviderHealthCheckRepository) ListByProvider(providerID uint64, clientType domain.ClientType, limit int) ([]*domain.ProviderHealthCheck, error) {
+	var models []ProviderHealthCheck
+	query := r.db.gorm.Where("provider_id = ?", providerID)
+	if clientType != "" {
+		query = query.Where("client_type = ?", string(clientType))
+	}
+	query = query.Order("id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	checks := make([]*domain.ProviderHealthCheck, len(models))
+	for i := range models {
+		checks[i] = r.toDomain(&models[i])
+	}
+	return checks, nil
+}
+
+// DeleteOlderThan 删除指定时间之前的探测记录
+func (r *ProviderHealthCheckRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.db.gorm.Where("created_at < ?", toTimestamp(before)).Delete(&ProviderHealthCheck{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *ProviderHealthCheckRepository) toModel(c *domain.ProviderHealthCheck) *ProviderHealthCheck {
+	return &ProviderHealthCheck{
+		ID:            c.ID,
+		CreatedAt:     toTimestamp(c.CreatedAt),
+		ProviderID:    c.ProviderID,
+		ClientType:    string(c.ClientType),
+		Model:         c.Model,
+		Success:       boolToInt(c.Success),
+		StatusCode:    c.StatusCode,
+		LatencyMs:     c.Latency.Milliseconds(),
+		Error:         LongText(c.Error),
+		Reason:        c.Reason,
+		CooldownUntil: toTimestampPtr(c.CooldownUntil),
+	}
+}
+
+func (r *ProviderHealthCheckRepository) toDomain(m *ProviderHealthCheck) *domain.ProviderHealthCheck {
+	return &domain.ProviderHealthCheck{
+		ID:            m.ID,
+		CreatedAt:     fromTimestamp(m.CreatedAt),
+		ProviderID:    m.ProviderID,
+		ClientType:    domain.ClientType(m.ClientType),
+		Model:         m.Model,
+		Success:       m.Success == 1,
+		StatusCode:    m.StatusCode,
+		Latency:       time.Duration(m.LatencyMs) * time.Millisecond,
+		Error:         string(m.Error),
+		Reason:        m.Reason,
+		CooldownUntil: fromTimestampPtr(m.CooldownUntil),
+	}
+}
@@ -82,7 +82,7 @@ func (r *ProxyRequestRepository) List(limit, offset int) ([]*domain.ProxyRequest
 func (r *ProxyRequestRepository) ListCursor(limit int, before, after uint64, filter *repository.ProxyRequestFilter) ([]*domain.ProxyRequest, error) {
 	// 使用 Select 排除大字段
 	query := r.db.gorm.Model(&ProxyRequest{}).
-		Select("id, created_at, updated_at, instance_id, request_id, session_id, client_type, request_model, response_model, start_time, end_time, duration_ms, ttft_ms, is_stream, status, status_code, error, proxy_upstream_attempt_count, final_proxy_upstream_attempt_id, route_id, provider_id, project_id, input_token_count, output_token_count, cache_read_count, cache_write_count, cache_5m_write_count, cache_1h_write_count, cost, api_token_id")
+		Select(proxyRequestListColumns)
 
 	if after > 0 {
 		query = query.Where("id > ?", after)
@@ -91,17 +91,7 @@ func (r *ProxyRequestRepository) ListCursor(limit int, before, after uint64, fil
 	}
 
 	// 应用过滤条件
-	if filter != nil {
-		if filter.ProviderID != nil {
-			query = query.Where("provider_id = ?", *filter.ProviderID)
-		}
-		if filter.Status != nil {
-			query = query.Where("status = ?", *filter.Status)
-		}
-		if filter.APITokenID != nil {
-			query = query.Where("api_token_id = ?", *filter.APITokenID)
-		}
-	}
+	query = r.applyFilter(query, filter)
 
 	var models []ProxyRequest
 	// 注意：这里使用基于主键 id 的稳定排序（与 before/after 游标保持一致），避免复杂 ORDER BY
@@ -120,11 +110,42 @@ func (r *ProxyRequestRepository) ListCursor(limit int, before, after uint64, fil
 	return r.toDomainList(models), nil
 }
 
+// iterateBatchSize 导出遍历时每批读取的行数
+const iterateBatchSize = 1000
+
+// proxyRequestListColumns 列表查询使用的列（排除 request_info/response_info 大字段）
+const proxyRequestListColumns = "id, created_at, updated_at, instance_id, request_id, session_id, client_type, request_model, response_model, start_time, end_time, duration_ms, ttft_ms, is_stream, status, status_code, error, proxy_upstream_attempt_count, final_proxy_upstream_attempt_id, route_id, provider_id, project_id, input_token_count, output_token_count, cache_read_count, cache_write_count, cache_5m_write_count, cache_1h_write_count, cost, upstream_cost, billed_cost, api_token_id"
+
+// Iterate 按 id 升序分批遍历满足过滤条件的请求（不含请求/响应详情），用于导出
+func (r *ProxyRequestRepository) Iterate(filter *repository.ProxyRequestFilter, fn func(*domain.ProxyRequest) error) error {
+	var lastID uint64
+	for {
+		query := r.db.gorm.Model(&ProxyRequest{}).
+			Select(proxyRequestListColumns).
+			Where("id > ?", lastID)
+		query = r.applyFilter(query, filter)
+
+		var models []ProxyRequest
+		if err := query.Order("id ASC").Limit(iterateBatchSize).Find(&models).Error; err != nil {
+			return err
+		}
+		for i := range models {
+			if err := fn(r.toDomain(&models[i])); err != nil {
+				return err
+			}
+		}
+		if len(models) < iterateBatchSize {
+			return nil
+		}
+		lastID = models[len(models)-1].ID
+	}
+}
+
 // ListActive 获取所有活跃请求 (PENDING 或 IN_PROGRESS 状态)
 func (r *ProxyRequestRepository) ListActive() ([]*domain.ProxyRequest, error) {
 	var models []ProxyRequest
 	if err := r.db.gorm.Model(&ProxyRequest{}).
-		Select("id, created_at, updated_at, instance_id, request_id, session_id, client_type, request_model, response_model, start_time, end_time, duration_ms, is_stream, status, status_code, error, proxy_upstream_attempt_count, final_proxy_upstream_attempt_id, route_id, provider_id, project_id, input_token_count, output_token_count, cache_read_count, cache_write_count, cache_5m_write_count, cache_1h_write_count, cost, api_token_id").
+		Select("id, created_at, updated_at, instance_id, request_id, session_id, client_type, request_model, response_model, start_time, end_time, duration_ms, is_stream, status, status_code, error, proxy_upstream_attempt_count, final_proxy_upstream_attempt_id, route_id, provider_id, project_id, input_token_count, output_token_count, cache_read_count, cache_write_count, cache_5m_write_count, cache_1h_write_count, cost, upstream_cost, billed_cost, api_token_id").
 		Where("status IN ?", []string{"PENDING", "IN_PROGRESS"}).
 		Order("id DESC").
 		Find(&models).Error; err != nil {
@@ -140,13 +161,24 @@ func (r *ProxyRequestRepository) Count() (int64, error) {
 // CountWithFilter 带过滤条件的计数
 func (r *ProxyRequestRepository) CountWithFilter(filter *repository.ProxyRequestFilter) (int64, error) {
 	// 如果没有过滤条件，使用缓存的总数
-	if filter == nil || (filter.ProviderID == nil && filter.Status == nil && filter.APITokenID == nil) {
+	if filter.IsEmpty() {
 		return atomic.LoadInt64(&r.count), nil
 	}
 
 	// 有过滤条件时需要查询数据库
 	var count int64
-	query := r.db.gorm.Model(&ProxyRequest{})
+	query := r.applyFilter(r.db.gorm.Model(&ProxyRequest{}), filter)
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// applyFilter 将过滤条件转换为 WHERE 子句，ListCursor 与 CountWithFilter 共用
+func (r *ProxyRequestRepository) applyFilter(query *gorm.DB, filter *repository.ProxyRequestFilter) *gorm.DB {
+	if filter == nil {
+		return query
+	}
 	if filter.ProviderID != nil {
 		query = query.Where("provider_id = ?", *filter.ProviderID)
 	}
@@ -156,10 +188,87 @@ func (r *ProxyRequestRepository) CountWithFilter(filter *repository.ProxyRequest
 	if filter.APITokenID != nil {
 		query = query.Where("api_token_id = ?", *filter.APITokenID)
 	}
-	if err := query.Count(&count).Error; err != nil {
-		return 0, err
+	if filter.StartTime != nil {
+		query = query.Where("created_at >= ?", toTimestamp(*filter.StartTime))
+	}
+	if filter.EndTime != nil {
+		query = query.Where("created_at < ?", toTimestamp(*filter.EndTime))
+	}
+	if filter.Model != nil {
+		query = query.Where("(request_model = ? OR response_model = ?)", *filter.Model, *filter.Model)
+	}
+	if filter.ProjectID != nil {
+		query = query.Where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.SessionID != nil {
+		query = query.Where("session_id = ?", *filter.SessionID)
+	}
+	if filter.ClientType != nil {
+		query = query.Where("client_type = ?", *filter.ClientType)
+	}
+	if filter.StatusCode != nil {
+		query = query.Where("status_code = ?", *filter.StatusCode)
+	}
+	if filter.MinCost != nil {
+		query = query.Where("cost >= ?", *filter.MinCost)
+	}
+	if filter.MinDurationMs != nil {
+		query = query.Where("duration_ms >= ?", *filter.MinDurationMs)
+	}
+	if filter.MinTokens != nil {
+		query = query.Where("input_token_count + output_token_count >= ?", *filter.MinTokens)
+	}
+	if filter.ErrorContains != nil {
+		// 使用 '!' 作为转义符：反斜杠在 MySQL 与 PostgreSQL 字面量中的含义不同
+		query = query.Where("LOWER(error) LIKE ? ESCAPE '!'", "%"+escapeLike(strings.ToLower(*filter.ErrorContains))+"%")
+	}
+	if filter.Query != nil {
+		query = r.applyFullTextSearch(query, *filter.Query)
+	}
+	return query
+}
+
+// applyFullTextSearch 按数据库方言使用对应的全文索引（见迁移 v3）
+// 所有词项之间为 AND 关系，词项内的特殊语法字符不生效
+// 只搜索数据库中的内容：已转存到外部详情存储的正文（见 detailstore.InlineLimit）不在搜索范围内
+func (r *ProxyRequestRepository) applyFullTextSearch(query *gorm.DB, text string) *gorm.DB {
+	terms := strings.Fields(text)
+	if len(terms) == 0 {
+		return query
+	}
+	if !r.db.fullTextReady.Load() {
+		return applyLikeSearch(query, terms)
+	}
+	switch r.db.dialector {
+	case "mysql":
+		return query.Where("MATCH(request_info, response_info, error) AGAINST (? IN BOOLEAN MODE)", mysqlBooleanQuery(terms))
+	case "postgres":
+		return query.Where(postgresRequestTSVector+" @@ plainto_tsquery('simple', ?)", strings.Join(terms, " "))
+	default:
+		return query.Where("id IN (SELECT rowid FROM proxy_requests_fts WHERE proxy_requests_fts MATCH ?)", sqliteFTSQuery(terms))
 	}
-	return count, nil
+}
+
+// sqliteFTSQuery 将每个词项作为 FTS5 短语，避免 AND/OR/NEAR、* 等语法被解释
+func sqliteFTSQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// mysqlBooleanQuery 每个词项都是必需的短语：+"term"
+func mysqlBooleanQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `+"` + strings.ReplaceAll(term, `"`, " ") + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
 }
 
 // MarkStaleAsFailed marks all IN_PROGRESS/PENDING requests from other instances as FAILED
@@ -235,6 +344,116 @@ func (r *ProxyRequestRepository) UpdateProjectIDBySessionID(sessionID string, pr
 	return result.RowsAffected, nil
 }
 
+// ListSessionStats 按会话聚合请求，按最后活跃时间倒序
+func (r *ProxyRequestRepository) ListSessionStats(filter *repository.SessionStatsFilter, limit, offset int) ([]*domain.SessionStats, error) {
+	type sessionStatsRow struct {
+		SessionID        string
+		ClientType       string
+		ProjectID        uint64
+		TurnCount        uint64
+		FailureCount     uint64
+		InputTokenCount  uint64
+		OutputTokenCount uint64
+		CacheReadCount   uint64
+		CacheWriteCount  uint64
+		Cost             uint64
+		FirstSeen        int64
+		LastSeen         int64
+	}
+
+	query := r.db.gorm.Model(&ProxyRequest{}).
+		Select(`session_id,
+			MAX(client_type) AS client_type,
+			MAX(project_id) AS project_id,
+			COUNT(*) AS turn_count,
+			SUM(CASE WHEN status = 'FAILED' THEN 1 ELSE 0 END) AS failure_count,
+			SUM(input_token_count) AS input_token_count,
+			SUM(output_token_count) AS output_token_count,
+			SUM(cache_read_count) AS cache_read_count,
+			SUM(cache_write_count) AS cache_write_count,
+			SUM(cost) AS cost,
+			MIN(created_at) AS first_seen,
+			MAX(created_at) AS last_seen`).
+		Where("session_id <> ''").
+		Group("session_id")
+	if filter != nil {
+		if filter.SessionID != nil {
+			query = query.Where("session_id = ?", *filter.SessionID)
+		}
+		if filter.ProjectID != nil {
+			query = query.Where("project_id = ?", *filter.ProjectID)
+		}
+		if filter.StartTime != nil {
+			query = query.Having("MAX(created_at) >= ?", toTimestamp(*filter.StartTime))
+		}
+		if filter.EndTime != nil {
+			query = query.Having("MAX(created_at) < ?", toTimestamp(*filter.EndTime))
+		}
+	}
+
+	var rows []sessionStatsRow
+	if err := query.Order("last_seen DESC").Limit(limit).Offset(offset).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []*domain.SessionStats{}, nil
+	}
+
+	stats := make([]*domain.SessionStats, len(rows))
+	bySession := make(map[string]*domain.SessionStats, len(rows))
+	sessionIDs := make([]string, len(rows))
+	for i, row := range rows {
+		stats[i] = &domain.SessionStats{
+			SessionID:        row.SessionID,
+			ClientType:       domain.ClientType(row.ClientType),
+			ProjectID:        row.ProjectID,
+			TurnCount:        row.TurnCount,
+			FailureCount:     row.FailureCount,
+			InputTokenCount:  row.InputTokenCount,
+			OutputTokenCount: row.OutputTokenCount,
+			CacheReadCount:   row.CacheReadCount,
+			CacheWriteCount:  row.CacheWriteCount,
+			Cost:             row.Cost,
+			ProviderIDs:      []uint64{},
+			FirstSeen:        fromTimestamp(row.FirstSeen),
+			LastSeen:         fromTimestamp(row.LastSeen),
+		}
+		bySession[row.SessionID] = stats[i]
+		sessionIDs[i] = row.SessionID
+	}
+
+	// 各会话使用过的 Provider（GROUP_CONCAT/STRING_AGG 各方言不一致，单独查询）
+	var providerRows []struct {
+		SessionID  string
+		ProviderID uint64
+	}
+	if err := r.db.gorm.Model(&ProxyRequest{}).
+		Distinct("session_id", "provider_id").
+		Where("session_id IN ? AND provider_id > 0", sessionIDs).
+		Order("provider_id").
+		Scan(&providerRows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range providerRows {
+		if s, ok := bySession[row.SessionID]; ok {
+			s.ProviderIDs = append(s.ProviderIDs, row.ProviderID)
+		}
+	}
+	return stats, nil
+}
+
+// ListBySessionID 按时间顺序获取会话内 id > after 的请求（包含 request_info 和 response_info）
+func (r *ProxyRequestRepository) ListBySessionID(sessionID string, after uint64, limit int) ([]*domain.ProxyRequest, error) {
+	var models []ProxyRequest
+	if err := r.db.gorm.Where("session_id = ? AND id > ?", sessionID, after).
+		Order("id ASC").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(models), nil
+}
+
 // DeleteOlderThan 删除指定时间之前的请求记录
 func (r *ProxyRequestRepository) DeleteOlderThan(before time.Time) (int64, error) {
 	beforeTs := toTimestamp(before)
@@ -279,9 +498,13 @@ func (r *ProxyRequestRepository) HasRecentRequests(since time.Time) (bool, error
 	return count > 0, nil
 }
 
-// UpdateCost updates only the cost field of a request
-func (r *ProxyRequestRepository) UpdateCost(id uint64, cost uint64) error {
-	return r.db.gorm.Model(&ProxyRequest{}).Where("id = ?", id).Update("cost", cost).Error
+// UpdateCost updates only the cost fields of a request
+func (r *ProxyRequestRepository) UpdateCost(id uint64, cost domain.CostUpdate) error {
+	return r.db.gorm.Model(&ProxyRequest{}).Where("id = ?", id).Updates(map[string]any{
+		"cost":          cost.Cost,
+		"upstream_cost": cost.UpstreamCost,
+		"billed_cost":   cost.BilledCost,
+	}).Error
 }
 
 // AddCost adds a delta to the cost field of a request (can be negative)
@@ -291,55 +514,8 @@ func (r *ProxyRequestRepository) AddCost(id uint64, delta int64) error {
 }
 
 // BatchUpdateCosts updates costs for multiple requests in a single transaction
-func (r *ProxyRequestRepository) BatchUpdateCosts(updates map[uint64]uint64) error {
-	if len(updates) == 0 {
-		return nil
-	}
-
-	return r.db.gorm.Transaction(func(tx *gorm.DB) error {
-		// Use CASE WHEN for batch update
-		const batchSize = 500
-		ids := make([]uint64, 0, len(updates))
-		for id := range updates {
-			ids = append(ids, id)
-		}
-
-		for i := 0; i < len(ids); i += batchSize {
-			end := i + batchSize
-			if end > len(ids) {
-				end = len(ids)
-			}
-			batchIDs := ids[i:end]
-
-			// Build CASE WHEN statement
-			var cases strings.Builder
-			cases.WriteString("CASE id ")
-			args := make([]interface{}, 0, len(batchIDs)*3+1)
-
-			// First: CASE WHEN pairs (id, cost)
-			for _, id := range batchIDs {
-				cases.WriteString("WHEN ? THEN ? ")
-				args = append(args, id, updates[id])
-			}
-			cases.WriteString("END")
-
-			// Second: timestamp for updated_at
-			args = append(args, time.Now().UnixMilli())
-
-			// Third: WHERE IN ids
-			for _, id := range batchIDs {
-				args = append(args, id)
-			}
-
-			sql := fmt.Sprintf("UPDATE proxy_requests SET cost = %s, updated_at = ? WHERE id IN (?%s)",
-				cases.String(), strings.Repeat(",?", len(batchIDs)-1))
-
-			if err := tx.Exec(sql, args...).Error; err != nil {
-				return err
-			}
-		}
-		return nil
-	})
+func (r *ProxyRequestRepository) BatchUpdateCosts(updates map[uint64]domain.CostUpdate) error {
+	return batchUpdateCosts(r.db.gorm, "proxy_requests", updates)
 }
 
 // RecalculateCostsFromAttempts recalculates all request costs by summing their attempt costs
@@ -409,6 +585,16 @@ func (r *ProxyRequestRepository) RecalculateCostsFromAttemptsWithProgress(progre
 				FROM proxy_upstream_attempts
 				WHERE proxy_request_id = proxy_requests.id
 			),
+			upstream_cost = (
+				SELECT COALESCE(SUM(upstream_cost), 0)
+				FROM proxy_upstream_attempts
+				WHERE proxy_request_id = proxy_requests.id
+			),
+			billed_cost = (
+				SELECT COALESCE(SUM(billed_cost), 0)
+				FROM proxy_upstream_attempts
+				WHERE proxy_request_id = proxy_requests.id
+			),
 			updated_at = ?
 			WHERE id IN (%s)
 		`, strings.Join(placeholders, ","))
@@ -479,6 +665,8 @@ func (r *ProxyRequestRepository) toModel(p *domain.ProxyRequest) *ProxyRequest {
 		ModelPriceID:               p.ModelPriceID,
 		Multiplier:                 p.Multiplier,
 		Cost:                       p.Cost,
+		UpstreamCost:               p.UpstreamCost,
+		BilledCost:                 p.BilledCost,
 		APITokenID:                 p.APITokenID,
 		DevMode:                    boolToInt(p.DevMode),
 	}
@@ -519,6 +707,8 @@ func (r *ProxyRequestRepository) toDomain(m *ProxyRequest) *domain.ProxyRequest
 		ModelPriceID:                m.ModelPriceID,
 		Multiplier:                  m.Multiplier,
 		Cost:                        m.Cost,
+		UpstreamCost:                m.UpstreamCost,
+		BilledCost:                  m.BilledCost,
 		APITokenID:                  m.APITokenID,
 		DevMode:                     m.DevMode == 1,
 	}
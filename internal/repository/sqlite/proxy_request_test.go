@@ -0,0 +1,270 @@
+package sqlite
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+func TestProxyRequestRepository_Filter(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "maxx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewProxyRequestRepository(db)
+
+	requests := []*domain.ProxyRequest{
+		{
+			SessionID:        "s1",
+			ClientType:       domain.ClientTypeClaude,
+			RequestModel:     "claude-sonnet",
+			Status:           "COMPLETED",
+			StatusCode:       200,
+			InputTokenCount:  100,
+			OutputTokenCount: 50,
+			Cost:             1000,
+			RequestInfo:      &domain.RequestInfo{Method: "POST", Body: `{"prompt":"refactor the flux capacitor"}`},
+		},
+		{
+			SessionID:    "s2",
+			ClientType:   domain.ClientTypeOpenAI,
+			RequestModel: "gpt-4o",
+			Status:       "FAILED",
+			StatusCode:   529,
+			Error:        "Upstream Overloaded_Error",
+			RequestInfo:  &domain.RequestInfo{Method: "POST", Body: `{"prompt":"hello world"}`},
+		},
+	}
+	for _, p := range requests {
+		if err := repo.Create(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 更新后索引应同步
+	requests[1].ResponseInfo = &domain.ResponseInfo{Status: 529, Body: "quasar overload"}
+	if err := repo.Update(requests[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	db.BackfillFullTextIndex()
+
+	str := func(s string) *string { return &s }
+	u64 := func(v uint64) *uint64 { return &v }
+	past := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		name   string
+		filter repository.ProxyRequestFilter
+		want   []uint64
+	}{
+		{"full text", repository.ProxyRequestFilter{Query: str("flux capacitor")}, []uint64{requests[0].ID}},
+		{"full text after update", repository.ProxyRequestFilter{Query: str("quasar")}, []uint64{requests[1].ID}},
+		{"full text syntax is literal", repository.ProxyRequestFilter{Query: str("flux OR quasar")}, nil},
+		{"model", repository.ProxyRequestFilter{Model: str("gpt-4o")}, []uint64{requests[1].ID}},
+		{"error substring", repository.ProxyRequestFilter{ErrorContains: str("overloaded_e")}, []uint64{requests[1].ID}},
+		{"like wildcard is literal", repository.ProxyRequestFilter{ErrorContains: str("%")}, nil},
+		{"min tokens", repository.ProxyRequestFilter{MinTokens: u64(150)}, []uint64{requests[0].ID}},
+		{"min cost", repository.ProxyRequestFilter{MinCost: u64(1)}, []uint64{requests[0].ID}},
+		{"since", repository.ProxyRequestFilter{StartTime: &past, SessionID: str("s2")}, []uint64{requests[1].ID}},
+		{"until", repository.ProxyRequestFilter{EndTime: &past}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			items, err := repo.ListCursor(10, 0, 0, &tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []uint64
+			for _, item := range items {
+				got = append(got, item.ID)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+			count, err := repo.CountWithFilter(&tc.filter)
+			if err != nil || count != int64(len(tc.want)) {
+				t.Fatalf("CountWithFilter = %d, %v", count, err)
+			}
+		})
+	}
+}
+
+func TestProxyRequestRepository_FullTextBackfill(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "maxx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewProxyRequestRepository(db)
+
+	var requests []*domain.ProxyRequest
+	for i := 0; i < fullTextBackfillBatch+5; i++ {
+		p := &domain.ProxyRequest{
+			SessionID:   "s",
+			Status:      "PENDING",
+			RequestInfo: &domain.RequestInfo{Method: "POST", Body: fmt.Sprintf(`{"prompt":"history item%d"}`, i)},
+		}
+		if err := repo.Create(p); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, p)
+	}
+	// 模拟升级前的历史数据：索引为空
+	if err := db.gorm.Exec("INSERT INTO proxy_requests_fts(proxy_requests_fts) VALUES ('delete-all')").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	str := func(s string) *string { return &s }
+	count := func(query string) int64 {
+		t.Helper()
+		n, err := repo.CountWithFilter(&repository.ProxyRequestFilter{Query: str(query)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// 索引就绪前退化为 LIKE，历史数据仍可搜到
+	if n := count("item150"); n != 1 {
+		t.Fatalf("LIKE fallback found %d requests, want 1", n)
+	}
+	// 未建立索引的行更新后不应破坏索引
+	requests[3].Status = "COMPLETED"
+	requests[3].ResponseInfo = &domain.ResponseInfo{Status: 200, Body: "nebula"}
+	if err := repo.Update(requests[3]); err != nil {
+		t.Fatal(err)
+	}
+
+	db.BackfillFullTextIndex()
+	if !db.fullTextReady.Load() {
+		t.Fatal("full-text index should be ready after backfill")
+	}
+	if n := count("history"); n != int64(len(requests)) {
+		t.Fatalf("full-text search found %d requests, want %d", n, len(requests))
+	}
+	if n := count("nebula"); n != 1 {
+		t.Fatalf("updated request found %d times, want 1", n)
+	}
+	if n := count("item204"); n != 1 {
+		t.Fatalf("last batch not indexed, found %d", n)
+	}
+	// 再次执行不会重复建立索引
+	db.BackfillFullTextIndex()
+	var indexed int64
+	if err := db.gorm.Raw("SELECT COUNT(*) FROM proxy_requests_fts_docsize").Scan(&indexed).Error; err != nil {
+		t.Fatal(err)
+	}
+	if indexed != int64(len(requests)) {
+		t.Fatalf("indexed rows = %d, want %d", indexed, len(requests))
+	}
+	if err := db.gorm.Exec("INSERT INTO proxy_requests_fts(proxy_requests_fts) VALUES ('integrity-check')").Error; err != nil {
+		t.Fatalf("full-text index corrupted: %v", err)
+	}
+}
+
+func TestProxyRequestRepository_SessionStats(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "maxx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewProxyRequestRepository(db)
+
+	for _, p := range []*domain.ProxyRequest{
+		{SessionID: "a", ClientType: domain.ClientTypeClaude, ProviderID: 2, Status: "COMPLETED", InputTokenCount: 10, Cost: 5},
+		{SessionID: "a", ClientType: domain.ClientTypeClaude, ProviderID: 1, Status: "FAILED", InputTokenCount: 20, Cost: 7},
+		{SessionID: "b", ClientType: domain.ClientTypeCodex, ProviderID: 3, Status: "COMPLETED"},
+		{SessionID: "", Status: "COMPLETED"},
+	} {
+		if err := repo.Create(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := repo.ListSessionStats(nil, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(stats))
+	}
+	var a *domain.SessionStats
+	for _, s := range stats {
+		if s.SessionID == "a" {
+			a = s
+		}
+	}
+	if a == nil || a.TurnCount != 2 || a.FailureCount != 1 || a.InputTokenCount != 30 || a.Cost != 12 {
+		t.Fatalf("unexpected stats %+v", a)
+	}
+	if len(a.ProviderIDs) != 2 || a.ProviderIDs[0] != 1 || a.ProviderIDs[1] != 2 {
+		t.Fatalf("unexpected providers %v", a.ProviderIDs)
+	}
+
+	requests, err := repo.ListBySessionID("a", 0, 10)
+	if err != nil || len(requests) != 2 || requests[0].ID > requests[1].ID {
+		t.Fatalf("ListBySessionID = %v, %v", requests, err)
+	}
+}
+
+func TestProxyRequestRepository_Iterate(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "maxx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewProxyRequestRepository(db)
+
+	// 跨越多个批次
+	total := iterateBatchSize + 5
+	for i := 0; i < total; i++ {
+		model := "claude-sonnet"
+		if i%2 == 1 {
+			model = "gpt-4o"
+		}
+		if err := repo.Create(&domain.ProxyRequest{RequestModel: model, Status: "COMPLETED"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ids []uint64
+	if err := repo.Iterate(nil, func(p *domain.ProxyRequest) error {
+		ids = append(ids, p.ID)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != total {
+		t.Fatalf("iterated %d requests, want %d", len(ids), total)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("ids not ascending at %d: %d <= %d", i, ids[i], ids[i-1])
+		}
+	}
+
+	model := "gpt-4o"
+	count := 0
+	if err := repo.Iterate(&repository.ProxyRequestFilter{Model: &model}, func(p *domain.ProxyRequest) error {
+		if p.RequestModel != model {
+			t.Fatalf("unexpected model %q", p.RequestModel)
+		}
+		count++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != total/2 {
+		t.Fatalf("filtered %d requests, want %d", count, total/2)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
 	"gorm.io/gorm"
 )
 
@@ -79,12 +80,20 @@ func (r *ProxyUpstreamAttemptRepository) StreamForCostCalc(batchSize int, callba
 			Cache5mWriteCount uint64 `gorm:"column:cache_5m_write_count"`
 			Cache1hWriteCount uint64 `gorm:"column:cache_1h_write_count"`
 			Cost              uint64 `gorm:"column:cost"`
+			ProviderID        uint64 `gorm:"column:provider_id"`
+			Multiplier        uint64 `gorm:"column:multiplier"`
+			ProjectID         uint64 `gorm:"column:project_id"`
+			APITokenID        uint64 `gorm:"column:api_token_id"`
+			UpstreamCost      uint64 `gorm:"column:upstream_cost"`
+			BilledCost        uint64 `gorm:"column:billed_cost"`
 		}
 
-		err := r.db.gorm.Table("proxy_upstream_attempts").
-			Select("id, proxy_request_id, response_model, mapped_model, request_model, input_token_count, output_token_count, cache_read_count, cache_write_count, cache_5m_write_count, cache_1h_write_count, cost").
-			Where("id > ?", lastID).
-			Order("id").
+		// 项目和 Token 来自所属请求，用于匹配计费规则
+		err := r.db.gorm.Table("proxy_upstream_attempts AS a").
+			Select("a.id, a.proxy_request_id, a.response_model, a.mapped_model, a.request_model, a.input_token_count, a.output_token_count, a.cache_read_count, a.cache_write_count, a.cache_5m_write_count, a.cache_1h_write_count, a.cost, a.provider_id, a.multiplier, a.upstream_cost, a.billed_cost, COALESCE(r.project_id, 0) AS project_id, COALESCE(r.api_token_id, 0) AS api_token_id").
+			Joins("LEFT JOIN proxy_requests r ON r.id = a.proxy_request_id").
+			Where("a.id > ?", lastID).
+			Order("a.id").
 			Limit(batchSize).
 			Find(&results).Error
 
@@ -112,6 +121,12 @@ func (r *ProxyUpstreamAttemptRepository) StreamForCostCalc(batchSize int, callba
 				Cache5mWriteCount: r.Cache5mWriteCount,
 				Cache1hWriteCount: r.Cache1hWriteCount,
 				Cost:              r.Cost,
+				ProviderID:        r.ProviderID,
+				Multiplier:        r.Multiplier,
+				ProjectID:         r.ProjectID,
+				APITokenID:        r.APITokenID,
+				UpstreamCost:      r.UpstreamCost,
+				BilledCost:        r.BilledCost,
 			}
 		}
 
@@ -129,8 +144,12 @@ func (r *ProxyUpstreamAttemptRepository) StreamForCostCalc(batchSize int, callba
 	return nil
 }
 
-func (r *ProxyUpstreamAttemptRepository) UpdateCost(id uint64, cost uint64) error {
-	return r.db.gorm.Model(&ProxyUpstreamAttempt{}).Where("id = ?", id).Update("cost", cost).Error
+func (r *ProxyUpstreamAttemptRepository) UpdateCost(id uint64, cost domain.CostUpdate) error {
+	return r.db.gorm.Model(&ProxyUpstreamAttempt{}).Where("id = ?", id).Updates(map[string]any{
+		"cost":          cost.Cost,
+		"upstream_cost": cost.UpstreamCost,
+		"billed_cost":   cost.BilledCost,
+	}).Error
 }
 
 // MarkStaleAttemptsFailed marks all IN_PROGRESS/PENDING attempts belonging to stale requests as FAILED
@@ -185,13 +204,17 @@ func (r *ProxyUpstreamAttemptRepository) FixFailedAttemptsWithoutEndTime() (int6
 }
 
 // BatchUpdateCosts updates costs for multiple attempts in a single transaction
-func (r *ProxyUpstreamAttemptRepository) BatchUpdateCosts(updates map[uint64]uint64) error {
+func (r *ProxyUpstreamAttemptRepository) BatchUpdateCosts(updates map[uint64]domain.CostUpdate) error {
+	return batchUpdateCosts(r.db.gorm, "proxy_upstream_attempts", updates)
+}
+
+// batchUpdateCosts 使用 CASE WHEN 批量更新 cost / upstream_cost / billed_cost
+func batchUpdateCosts(db *gorm.DB, table string, updates map[uint64]domain.CostUpdate) error {
 	if len(updates) == 0 {
 		return nil
 	}
 
-	return r.db.gorm.Transaction(func(tx *gorm.DB) error {
-		// Use CASE WHEN for batch update
+	return db.Transaction(func(tx *gorm.DB) error {
 		const batchSize = 500
 		ids := make([]uint64, 0, len(updates))
 		for id := range updates {
@@ -205,28 +228,29 @@ func (r *ProxyUpstreamAttemptRepository) BatchUpdateCosts(updates map[uint64]uin
 			}
 			batchIDs := ids[i:end]
 
-			// Build CASE WHEN statement
-			var cases strings.Builder
-			cases.WriteString("CASE id ")
-			args := make([]interface{}, 0, len(batchIDs)*3+1)
-
-			// First: CASE WHEN pairs (id, cost)
-			for _, id := range batchIDs {
-				cases.WriteString("WHEN ? THEN ? ")
-				args = append(args, id, updates[id])
+			// 每个字段一个 CASE WHEN，参数顺序与 SQL 中出现的顺序一致
+			var cases [3]strings.Builder
+			args := make([]interface{}, 0, len(batchIDs)*7+1)
+			for c, field := range []func(domain.CostUpdate) uint64{
+				func(u domain.CostUpdate) uint64 { return u.Cost },
+				func(u domain.CostUpdate) uint64 { return u.UpstreamCost },
+				func(u domain.CostUpdate) uint64 { return u.BilledCost },
+			} {
+				cases[c].WriteString("CASE id ")
+				for _, id := range batchIDs {
+					cases[c].WriteString("WHEN ? THEN ? ")
+					args = append(args, id, field(updates[id]))
+				}
+				cases[c].WriteString("END")
 			}
-			cases.WriteString("END")
 
-			// Second: timestamp for updated_at
 			args = append(args, time.Now().UnixMilli())
-
-			// Third: WHERE IN ids
 			for _, id := range batchIDs {
 				args = append(args, id)
 			}
 
-			sql := fmt.Sprintf("UPDATE proxy_upstream_attempts SET cost = %s, updated_at = ? WHERE id IN (?%s)",
-				cases.String(), strings.Repeat(",?", len(batchIDs)-1))
+			sql := fmt.Sprintf("UPDATE %s SET cost = %s, upstream_cost = %s, billed_cost = %s, updated_at = ? WHERE id IN (?%s)",
+				table, cases[0].String(), cases[1].String(), cases[2].String(), strings.Repeat(",?", len(batchIDs)-1))
 
 			if err := tx.Exec(sql, args...).Error; err != nil {
 				return err
@@ -257,6 +281,49 @@ func (r *ProxyUpstreamAttemptRepository) ClearDetailOlderThan(before time.Time)
 	return result.RowsAffected, result.Error
 }
 
+// GetWastedSpend 聚合未产生最终结果的 attempt：FAILED 和 CANCELLED
+// 一次调度中 COMPLETED 的 attempt 总是请求的最终结果，不计入浪费；旧数据没有记录失败原因，归为 unknown
+func (r *ProxyUpstreamAttemptRepository) GetWastedSpend(filter repository.WastedSpendFilter) ([]*domain.WastedSpend, error) {
+	query := r.db.gorm.Table("proxy_upstream_attempts AS a").
+		Select(`a.provider_id,
+			CASE
+				WHEN a.status = 'CANCELLED' THEN ?
+				ELSE COALESCE(NULLIF(a.failure_reason, ''), ?)
+			END AS reason,
+			COUNT(*) AS attempts,
+			SUM(a.input_token_count) AS input_token_count,
+			SUM(a.output_token_count) AS output_token_count,
+			SUM(a.cache_read_count) AS cache_read_count,
+			SUM(a.cache_write_count) AS cache_write_count,
+			SUM(a.cost) AS cost,
+			SUM(a.upstream_cost) AS upstream_cost`,
+			domain.AttemptReasonCancelled, string(domain.CooldownReasonUnknown)).
+		Joins("LEFT JOIN proxy_requests AS r ON r.id = a.proxy_request_id").
+		Where("a.status IN ('FAILED', 'CANCELLED')")
+	if filter.StartTime != nil {
+		query = query.Where("a.end_time >= ?", toTimestamp(*filter.StartTime))
+	}
+	if filter.EndTime != nil {
+		query = query.Where("a.end_time < ?", toTimestamp(*filter.EndTime))
+	}
+	if filter.ProviderID != nil {
+		query = query.Where("a.provider_id = ?", *filter.ProviderID)
+	}
+	if filter.ProjectID != nil {
+		query = query.Where("r.project_id = ?", *filter.ProjectID)
+	}
+
+	var rows []domain.WastedSpend
+	if err := query.Group("a.provider_id, reason").Order("cost DESC").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*domain.WastedSpend, len(rows))
+	for i := range rows {
+		result[i] = &rows[i]
+	}
+	return result, nil
+}
+
 func (r *ProxyUpstreamAttemptRepository) toModel(a *domain.ProxyUpstreamAttempt) *ProxyUpstreamAttempt {
 	return &ProxyUpstreamAttempt{
 		BaseModel: BaseModel{
@@ -269,11 +336,14 @@ func (r *ProxyUpstreamAttemptRepository) toModel(a *domain.ProxyUpstreamAttempt)
 		DurationMs:        a.Duration.Milliseconds(),
 		TTFTMs:            a.TTFT.Milliseconds(),
 		Status:            a.Status,
+		FailureReason:     a.FailureReason,
 		ProxyRequestID:    a.ProxyRequestID,
 		IsStream:          boolToInt(a.IsStream),
 		RequestModel:      a.RequestModel,
 		MappedModel:       a.MappedModel,
 		ResponseModel:     a.ResponseModel,
+		FallbackStep:      a.FallbackStep,
+		FallbackModel:     a.FallbackModel,
 		RequestInfo:       LongText(toJSON(a.RequestInfo)),
 		ResponseInfo:      LongText(toJSON(a.ResponseInfo)),
 		RouteID:           a.RouteID,
@@ -287,6 +357,8 @@ func (r *ProxyUpstreamAttemptRepository) toModel(a *domain.ProxyUpstreamAttempt)
 		ModelPriceID:      a.ModelPriceID,
 		Multiplier:        a.Multiplier,
 		Cost:              a.Cost,
+		UpstreamCost:      a.UpstreamCost,
+		BilledCost:        a.BilledCost,
 	}
 }
 
@@ -300,11 +372,14 @@ func (r *ProxyUpstreamAttemptRepository) toDomain(m *ProxyUpstreamAttempt) *doma
 		Duration:          time.Duration(m.DurationMs) * time.Millisecond,
 		TTFT:              time.Duration(m.TTFTMs) * time.Millisecond,
 		Status:            m.Status,
+		FailureReason:     m.FailureReason,
 		ProxyRequestID:    m.ProxyRequestID,
 		IsStream:          m.IsStream == 1,
 		RequestModel:      m.RequestModel,
 		MappedModel:       m.MappedModel,
 		ResponseModel:     m.ResponseModel,
+		FallbackStep:      m.FallbackStep,
+		FallbackModel:     m.FallbackModel,
 		RequestInfo:       fromJSON[*domain.RequestInfo](string(m.RequestInfo)),
 		ResponseInfo:      fromJSON[*domain.ResponseInfo](string(m.ResponseInfo)),
 		RouteID:           m.RouteID,
@@ -318,6 +393,8 @@ func (r *ProxyUpstreamAttemptRepository) toDomain(m *ProxyUpstreamAttempt) *doma
 		ModelPriceID:      m.ModelPriceID,
 		Multiplier:        m.Multiplier,
 		Cost:              m.Cost,
+		UpstreamCost:      m.UpstreamCost,
+		BilledCost:        m.BilledCost,
 	}
 }
 
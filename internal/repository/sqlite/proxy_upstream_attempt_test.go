@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+func TestProxyUpstreamAttemptRepository_GetWastedSpend(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "maxx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	requestRepo := NewProxyRequestRepository(db)
+	attemptRepo := NewProxyUpstreamAttemptRepository(db)
+
+	now := time.Now()
+	newRequest := func(projectID uint64) *domain.ProxyRequest {
+		p := &domain.ProxyRequest{ProjectID: projectID, Status: "IN_PROGRESS"}
+		if err := requestRepo.Create(p); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	// dispatch 按调度器的方式写入：每个 attempt 结束后都更新请求的最终 attempt 和状态
+	type attempt struct {
+		providerID uint64
+		status     string
+		reason     string
+		cost       uint64
+	}
+	dispatch := func(p *domain.ProxyRequest, attempts ...attempt) {
+		for _, at := range attempts {
+			a := &domain.ProxyUpstreamAttempt{
+				ProxyRequestID:   p.ID,
+				ProviderID:       at.providerID,
+				Status:           at.status,
+				FailureReason:    at.reason,
+				EndTime:          now,
+				InputTokenCount:  100,
+				OutputTokenCount: 10,
+				Cost:             at.cost,
+				UpstreamCost:     at.cost / 2,
+			}
+			if err := attemptRepo.Create(a); err != nil {
+				t.Fatal(err)
+			}
+			p.Status = at.status
+			p.FinalProxyUpstreamAttemptID = a.ID
+			if err := requestRepo.Update(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// 请求 1：provider 1 两次 5xx 后由 provider 2 成功
+	dispatch(newRequest(1),
+		attempt{1, "FAILED", "server_error", 1000},
+		attempt{1, "FAILED", "server_error", 500},
+		attempt{2, "COMPLETED", "", 3000})
+
+	// 请求 2：provider 2 流式输出中断，重试时客户端断开
+	dispatch(newRequest(2),
+		attempt{2, "FAILED", "network_error", 700},
+		attempt{2, "CANCELLED", domain.AttemptReasonCancelled, 200})
+
+	// 请求 3：旧数据，未记录失败原因
+	dispatch(newRequest(2), attempt{1, "FAILED", "", 50})
+
+	// 请求 4：一次成功，不产生浪费
+	dispatch(newRequest(2), attempt{2, "COMPLETED", "", 900})
+
+	items, err := attemptRepo.GetWastedSpend(repository.WastedSpendFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]*domain.WastedSpend)
+	for _, item := range items {
+		got[fmt.Sprintf("%s/%d", item.Reason, item.ProviderID)] = item
+	}
+	if len(items) != 4 {
+		t.Fatalf("expected 4 groups, got %d: %+v", len(items), got)
+	}
+	if items[0].Reason != "server_error" || items[0].Cost != 1500 || items[0].Attempts != 2 ||
+		items[0].UpstreamCost != 750 || items[0].InputTokenCount != 200 {
+		t.Fatalf("expected server_error first ordered by cost, got %+v", items[0])
+	}
+	if g := got["network_error/2"]; g == nil || g.Cost != 700 {
+		t.Fatalf("network_error = %+v", g)
+	}
+	if g := got["cancelled/2"]; g == nil || g.Cost != 200 {
+		t.Fatalf("cancelled = %+v", g)
+	}
+	if g := got["unknown/1"]; g == nil || g.Cost != 50 {
+		t.Fatalf("unknown = %+v", g)
+	}
+
+	projectID := uint64(1)
+	items, err = attemptRepo.GetWastedSpend(repository.WastedSpendFilter{ProjectID: &projectID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Cost != 1500 {
+		t.Fatalf("project filter: %+v", items)
+	}
+
+	future := now.Add(time.Hour)
+	items, err = attemptRepo.GetWastedSpend(repository.WastedSpendFilter{StartTime: &future})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 0 {
+		t.Fatalf("time filter: %+v", items)
+	}
+}
@@ -83,6 +83,8 @@ func (r *SessionRepository) toModel(s *domain.Session) *Session {
 		ClientType: string(s.ClientType),
 		ProjectID:  s.ProjectID,
 		RejectedAt: toTimestampPtr(s.RejectedAt),
+
+		LoopApprovedAt: toTimestampPtr(s.LoopApprovedAt),
 	}
 }
 
@@ -96,5 +98,7 @@ func (r *SessionRepository) toDomain(m *Session) *domain.Session {
 		ClientType: domain.ClientType(m.ClientType),
 		ProjectID:  m.ProjectID,
 		RejectedAt: fromTimestampPtr(m.RejectedAt),
+
+		LoopApprovedAt: fromTimestampPtr(m.LoopApprovedAt),
 	}
 }
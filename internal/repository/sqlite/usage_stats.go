@@ -69,6 +69,8 @@ func (r *UsageStatsRepository) Upsert(stats *domain.UsageStats) error {
 			"cache_read":          stats.CacheRead,
 			"cache_write":         stats.CacheWrite,
 			"cost":                stats.Cost,
+			"upstream_cost":       stats.UpstreamCost,
+			"billed_cost":         stats.BilledCost,
 		}),
 	}).Create(model).Error
 }
@@ -87,6 +89,45 @@ func (r *UsageStatsRepository) BatchUpsert(stats []*domain.UsageStats) error {
 
 // queryHistorical 查询预聚合的历史统计数据（内部方法）
 func (r *UsageStatsRepository) queryHistorical(filter repository.UsageStatsFilter) ([]*domain.UsageStats, error) {
+	where, args := usageStatsConditions(filter)
+	var models []UsageStats
+	err := r.db.gorm.Where(where, args...).
+		Order("time_bucket DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(models), nil
+}
+
+// Iterate 按 id 升序分批遍历预聚合数据（不补全当前时间桶），用于导出，避免一次性加载
+func (r *UsageStatsRepository) Iterate(filter repository.UsageStatsFilter, fn func(*domain.UsageStats) error) error {
+	where, args := usageStatsConditions(filter)
+	var lastID uint64
+	for {
+		var models []UsageStats
+		err := r.db.gorm.Where(where, args...).
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(iterateBatchSize).
+			Find(&models).Error
+		if err != nil {
+			return err
+		}
+		for i := range models {
+			if err := fn(r.toDomain(&models[i])); err != nil {
+				return err
+			}
+		}
+		if len(models) < iterateBatchSize {
+			return nil
+		}
+		lastID = models[len(models)-1].ID
+	}
+}
+
+func usageStatsConditions(filter repository.UsageStatsFilter) (string, []interface{}) {
 	var conditions []string
 	var args []interface{}
 
@@ -125,16 +166,7 @@ func (r *UsageStatsRepository) queryHistorical(filter repository.UsageStatsFilte
 		conditions = append(conditions, "model = ?")
 		args = append(args, *filter.Model)
 	}
-
-	var models []UsageStats
-	err := r.db.gorm.Where(strings.Join(conditions, " AND "), args...).
-		Order("time_bucket DESC").
-		Find(&models).Error
-	if err != nil {
-		return nil, err
-	}
-
-	return r.toDomainList(models), nil
+	return strings.Join(conditions, " AND "), args
 }
 
 // Query 查询统计数据并补全当前时间桶的数据
@@ -495,7 +527,9 @@ func (r *UsageStatsRepository) queryRecentMinutesStats(startMinute time.Time, fi
 			COALESCE(a.output_token_count, 0),
 			COALESCE(a.cache_read_count, 0),
 			COALESCE(a.cache_write_count, 0),
-			COALESCE(a.cost, 0)
+			COALESCE(a.cost, 0),
+			COALESCE(a.upstream_cost, 0),
+			COALESCE(a.billed_cost, 0)
 		FROM proxy_upstream_attempts a
 		LEFT JOIN proxy_requests r ON a.proxy_request_id = r.id
 		WHERE ` + strings.Join(conditions, " AND ")
@@ -512,12 +546,12 @@ func (r *UsageStatsRepository) queryRecentMinutesStats(startMinute time.Time, fi
 		var endTime int64
 		var routeID, providerID, projectID, apiTokenID uint64
 		var clientType, model, status string
-		var durationMs, ttftMs, inputTokens, outputTokens, cacheRead, cacheWrite, cost uint64
+		var durationMs, ttftMs, inputTokens, outputTokens, cacheRead, cacheWrite, cost, upstreamCost, billedCost uint64
 
 		err := rows.Scan(
 			&endTime, &routeID, &providerID, &projectID, &apiTokenID, &clientType,
 			&model, &status, &durationMs, &ttftMs,
-			&inputTokens, &outputTokens, &cacheRead, &cacheWrite, &cost,
+			&inputTokens, &outputTokens, &cacheRead, &cacheWrite, &cost, &upstreamCost, &billedCost,
 		)
 		if err != nil {
 			continue
@@ -540,6 +574,8 @@ func (r *UsageStatsRepository) queryRecentMinutesStats(startMinute time.Time, fi
 			CacheRead:    cacheRead,
 			CacheWrite:   cacheWrite,
 			Cost:         cost,
+			UpstreamCost: upstreamCost,
+			BilledCost:   billedCost,
 		})
 	}
 
@@ -564,20 +600,32 @@ func (r *UsageStatsRepository) GetSummary(filter repository.UsageStatsFilter) (*
 	// 聚合所有数据
 	var s domain.UsageStatsSummary
 	for _, stat := range allStats {
-		s.TotalRequests += stat.TotalRequests
-		s.SuccessfulRequests += stat.SuccessfulRequests
-		s.FailedRequests += stat.FailedRequests
-		s.TotalInputTokens += stat.InputTokens
-		s.TotalOutputTokens += stat.OutputTokens
-		s.TotalCacheRead += stat.CacheRead
-		s.TotalCacheWrite += stat.CacheWrite
-		s.TotalCost += stat.Cost
+		accumulateSummary(&s, stat)
 	}
+	finalizeSummary(&s)
+	return &s, nil
+}
+
+// accumulateSummary 将一条统计记录累加到汇总中
+func accumulateSummary(s *domain.UsageStatsSummary, stat *domain.UsageStats) {
+	s.TotalRequests += stat.TotalRequests
+	s.SuccessfulRequests += stat.SuccessfulRequests
+	s.FailedRequests += stat.FailedRequests
+	s.TotalInputTokens += stat.InputTokens
+	s.TotalOutputTokens += stat.OutputTokens
+	s.TotalCacheRead += stat.CacheRead
+	s.TotalCacheWrite += stat.CacheWrite
+	s.TotalCost += stat.Cost
+	s.TotalUpstreamCost += stat.UpstreamCost
+	s.TotalBilledCost += stat.BilledCost
+}
 
+// finalizeSummary 计算成功率和毛利
+func finalizeSummary(s *domain.UsageStatsSummary) {
 	if s.TotalRequests > 0 {
 		s.SuccessRate = float64(s.SuccessfulRequests) / float64(s.TotalRequests) * 100
 	}
-	return &s, nil
+	s.Margin = int64(s.TotalBilledCost) - int64(s.TotalUpstreamCost)
 }
 
 // GetSummaryByProvider 按 Provider 维度获取汇总统计
@@ -624,34 +672,17 @@ func (r *UsageStatsRepository) getSummaryByDimension(filter repository.UsageStat
 			dimID = stat.APITokenID
 		}
 
-		if existing, ok := results[dimID]; ok {
-			existing.TotalRequests += stat.TotalRequests
-			existing.SuccessfulRequests += stat.SuccessfulRequests
-			existing.FailedRequests += stat.FailedRequests
-			existing.TotalInputTokens += stat.InputTokens
-			existing.TotalOutputTokens += stat.OutputTokens
-			existing.TotalCacheRead += stat.CacheRead
-			existing.TotalCacheWrite += stat.CacheWrite
-			existing.TotalCost += stat.Cost
-		} else {
-			results[dimID] = &domain.UsageStatsSummary{
-				TotalRequests:      stat.TotalRequests,
-				SuccessfulRequests: stat.SuccessfulRequests,
-				FailedRequests:     stat.FailedRequests,
-				TotalInputTokens:   stat.InputTokens,
-				TotalOutputTokens:  stat.OutputTokens,
-				TotalCacheRead:     stat.CacheRead,
-				TotalCacheWrite:    stat.CacheWrite,
-				TotalCost:          stat.Cost,
-			}
+		summary, ok := results[dimID]
+		if !ok {
+			summary = &domain.UsageStatsSummary{}
+			results[dimID] = summary
 		}
+		accumulateSummary(summary, stat)
 	}
 
-	// 计算成功率
+	// 计算成功率和毛利
 	for _, s := range results {
-		if s.TotalRequests > 0 {
-			s.SuccessRate = float64(s.SuccessfulRequests) / float64(s.TotalRequests) * 100
-		}
+		finalizeSummary(s)
 	}
 
 	return results, nil
@@ -671,34 +702,17 @@ func (r *UsageStatsRepository) GetSummaryByClientType(filter repository.UsageSta
 	for _, stat := range allStats {
 		clientType := stat.ClientType
 
-		if existing, ok := results[clientType]; ok {
-			existing.TotalRequests += stat.TotalRequests
-			existing.SuccessfulRequests += stat.SuccessfulRequests
-			existing.FailedRequests += stat.FailedRequests
-			existing.TotalInputTokens += stat.InputTokens
-			existing.TotalOutputTokens += stat.OutputTokens
-			existing.TotalCacheRead += stat.CacheRead
-			existing.TotalCacheWrite += stat.CacheWrite
-			existing.TotalCost += stat.Cost
-		} else {
-			results[clientType] = &domain.UsageStatsSummary{
-				TotalRequests:      stat.TotalRequests,
-				SuccessfulRequests: stat.SuccessfulRequests,
-				FailedRequests:     stat.FailedRequests,
-				TotalInputTokens:   stat.InputTokens,
-				TotalOutputTokens:  stat.OutputTokens,
-				TotalCacheRead:     stat.CacheRead,
-				TotalCacheWrite:    stat.CacheWrite,
-				TotalCost:          stat.Cost,
-			}
+		summary, ok := results[clientType]
+		if !ok {
+			summary = &domain.UsageStatsSummary{}
+			results[clientType] = summary
 		}
+		accumulateSummary(summary, stat)
 	}
 
-	// 计算成功率
+	// 计算成功率和毛利
 	for _, s := range results {
-		if s.TotalRequests > 0 {
-			s.SuccessRate = float64(s.SuccessfulRequests) / float64(s.TotalRequests) * 100
-		}
+		finalizeSummary(s)
 	}
 
 	return results, nil
@@ -948,7 +962,9 @@ func (r *UsageStatsRepository) aggregateMinute() (count int, startTime, endTime
 			COALESCE(a.output_token_count, 0),
 			COALESCE(a.cache_read_count, 0),
 			COALESCE(a.cache_write_count, 0),
-			COALESCE(a.cost, 0)
+			COALESCE(a.cost, 0),
+			COALESCE(a.upstream_cost, 0),
+			COALESCE(a.billed_cost, 0)
 		FROM proxy_upstream_attempts a
 		LEFT JOIN proxy_requests r ON a.proxy_request_id = r.id
 		WHERE a.end_time >= ? AND a.end_time < ?
@@ -969,12 +985,12 @@ func (r *UsageStatsRepository) aggregateMinute() (count int, startTime, endTime
 		var endTime int64
 		var routeID, providerID, projectID, apiTokenID uint64
 		var clientType, model, status string
-		var durationMs, ttftMs, inputTokens, outputTokens, cacheRead, cacheWrite, cost uint64
+		var durationMs, ttftMs, inputTokens, outputTokens, cacheRead, cacheWrite, cost, upstreamCost, billedCost uint64
 
 		err := rows.Scan(
 			&endTime, &routeID, &providerID, &projectID, &apiTokenID, &clientType,
 			&model, &status, &durationMs, &ttftMs,
-			&inputTokens, &outputTokens, &cacheRead, &cacheWrite, &cost,
+			&inputTokens, &outputTokens, &cacheRead, &cacheWrite, &cost, &upstreamCost, &billedCost,
 		)
 		if err != nil {
 			continue
@@ -1002,6 +1018,8 @@ func (r *UsageStatsRepository) aggregateMinute() (count int, startTime, endTime
 			CacheRead:    cacheRead,
 			CacheWrite:   cacheWrite,
 			Cost:         cost,
+			UpstreamCost: upstreamCost,
+			BilledCost:   billedCost,
 		})
 	}
 
@@ -1288,7 +1306,9 @@ func (r *UsageStatsRepository) aggregateAllMinutesWithProgress(progressFn func(c
 			COALESCE(a.output_token_count, 0),
 			COALESCE(a.cache_read_count, 0),
 			COALESCE(a.cache_write_count, 0),
-			COALESCE(a.cost, 0)
+			COALESCE(a.cost, 0),
+			COALESCE(a.upstream_cost, 0),
+			COALESCE(a.billed_cost, 0)
 		FROM proxy_upstream_attempts a
 		LEFT JOIN proxy_requests r ON a.proxy_request_id = r.id
 		WHERE a.end_time < ? AND a.status IN ('COMPLETED', 'FAILED', 'CANCELLED')
@@ -1312,12 +1332,12 @@ func (r *UsageStatsRepository) aggregateAllMinutesWithProgress(progressFn func(c
 		var endTime int64
 		var routeID, providerID, projectID, apiTokenID uint64
 		var clientType, model, status string
-		var durationMs, ttftMs, inputTokens, outputTokens, cacheRead, cacheWrite, cost uint64
+		var durationMs, ttftMs, inputTokens, outputTokens, cacheRead, cacheWrite, cost, upstreamCost, billedCost uint64
 
 		err := rows.Scan(
 			&endTime, &routeID, &providerID, &projectID, &apiTokenID, &clientType,
 			&model, &status, &durationMs, &ttftMs,
-			&inputTokens, &outputTokens, &cacheRead, &cacheWrite, &cost,
+			&inputTokens, &outputTokens, &cacheRead, &cacheWrite, &cost, &upstreamCost, &billedCost,
 		)
 		if err != nil {
 			log.Printf("[aggregateAllMinutes] Scan error: %v", err)
@@ -1352,6 +1372,8 @@ func (r *UsageStatsRepository) aggregateAllMinutesWithProgress(progressFn func(c
 			CacheRead:    cacheRead,
 			CacheWrite:   cacheWrite,
 			Cost:         cost,
+			UpstreamCost: upstreamCost,
+			BilledCost:   billedCost,
 		})
 	}
 
@@ -1409,6 +1431,8 @@ func (r *UsageStatsRepository) toModel(s *domain.UsageStats) *UsageStats {
 		CacheRead:          s.CacheRead,
 		CacheWrite:         s.CacheWrite,
 		Cost:               s.Cost,
+		UpstreamCost:       s.UpstreamCost,
+		BilledCost:         s.BilledCost,
 	}
 }
 
@@ -1434,6 +1458,8 @@ func (r *UsageStatsRepository) toDomain(m *UsageStats) *domain.UsageStats {
 		CacheRead:          m.CacheRead,
 		CacheWrite:         m.CacheWrite,
 		Cost:               m.Cost,
+		UpstreamCost:       m.UpstreamCost,
+		BilledCost:         m.BilledCost,
 	}
 }
 
@@ -1478,7 +1504,8 @@ func (r *UsageStatsRepository) QueryDashboardData() (*domain.DashboardData, erro
 		query := `
 			SELECT time_bucket, provider_id, model,
 				SUM(total_requests), SUM(successful_requests),
-				SUM(input_tokens + output_tokens + cache_read + cache_write), SUM(cost)
+				SUM(input_tokens + output_tokens + cache_read + cache_write), SUM(cost),
+				SUM(upstream_cost), SUM(billed_cost)
 			FROM usage_stats
 			WHERE granularity = 'day'
 			AND time_bucket >= ? AND time_bucket < ?
@@ -1508,8 +1535,8 @@ func (r *UsageStatsRepository) QueryDashboardData() (*domain.DashboardData, erro
 			var bucket int64
 			var providerID uint64
 			var model string
-			var requests, successful, tokens, cost uint64
-			if err := rows.Scan(&bucket, &providerID, &model, &requests, &successful, &tokens, &cost); err != nil {
+			var requests, successful, tokens, cost, upstreamCost, billedCost uint64
+			if err := rows.Scan(&bucket, &providerID, &model, &requests, &successful, &tokens, &cost, &upstreamCost, &billedCost); err != nil {
 				continue
 			}
 
@@ -1524,6 +1551,7 @@ func (r *UsageStatsRepository) QueryDashboardData() (*domain.DashboardData, erro
 				yesterdaySummary.Requests += requests
 				yesterdaySummary.Tokens += tokens
 				yesterdaySummary.Cost += cost
+				yesterdaySummary.Margin += int64(billedCost) - int64(upstreamCost)
 			}
 
 			// Provider统计 (30天)
@@ -1614,6 +1642,7 @@ func (r *UsageStatsRepository) QueryDashboardData() (*domain.DashboardData, erro
 				todaySuccessful += s.SuccessfulRequests
 				todaySummary.Tokens += s.InputTokens + s.OutputTokens + s.CacheRead + s.CacheWrite
 				todaySummary.Cost += s.Cost
+				todaySummary.Margin += int64(s.BilledCost) - int64(s.UpstreamCost)
 				todayRequests += s.TotalRequests
 				todayDurationMs += s.TotalDurationMs
 
@@ -1724,6 +1753,7 @@ func (r *UsageStatsRepository) QueryDashboardData() (*domain.DashboardData, erro
 			allTimeSummary.Requests += s.TotalRequests
 			allTimeSummary.Tokens += s.InputTokens + s.OutputTokens + s.CacheRead + s.CacheWrite
 			allTimeSummary.Cost += s.Cost
+			allTimeSummary.Margin += int64(s.BilledCost) - int64(s.UpstreamCost)
 
 			// Top模型（全量）
 			if s.Model != "" {
@@ -2,12 +2,15 @@ package router
 
 import (
 	"math/rand"
+	"slices"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/awsl-project/maxx/internal/adapter/provider"
 	"github.com/awsl-project/maxx/internal/cooldown"
 	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/pricing"
 	"github.com/awsl-project/maxx/internal/repository/cached"
 )
 
@@ -25,6 +28,21 @@ type MatchContext struct {
 	ProjectID    uint64
 	RequestModel string
 	APITokenID   uint64
+
+	// AllowedProviderIDs restricts matching to these providers (API token scope), empty means no restriction
+	AllowedProviderIDs []uint64
+
+	// MapModel returns the model a route would send upstream, used for model-scoped cooldowns
+	// nil means RequestModel is used as is
+	MapModel func(route *domain.Route, provider *domain.Provider) string
+}
+
+// routeModel returns the model checked against model-scoped cooldowns for a route
+func (ctx *MatchContext) routeModel(route *domain.Route, provider *domain.Provider) string {
+	if ctx.MapModel != nil {
+		return ctx.MapModel(route, provider)
+	}
+	return ctx.RequestModel
 }
 
 // Router handles route matching and selection
@@ -69,6 +87,7 @@ func (r *Router) InitAdapters() error {
 	defer r.mu.Unlock()
 
 	for _, p := range providers {
+		pricing.GlobalCalculator().SetProviderPricing(p.ID, pricing.ProviderPricing(p))
 		factory, ok := provider.GetAdapterFactory(p.Type)
 		if !ok {
 			continue // Skip providers without registered adapters
@@ -84,7 +103,9 @@ func (r *Router) InitAdapters() error {
 }
 
 // RefreshAdapter refreshes the adapter for a specific provider
+// Provider pricing follows the provider config, so a type or price change takes effect here
 func (r *Router) RefreshAdapter(p *domain.Provider) error {
+	pricing.GlobalCalculator().SetProviderPricing(p.ID, pricing.ProviderPricing(p))
 	factory, ok := provider.GetAdapterFactory(p.Type)
 	if !ok {
 		return nil
@@ -105,10 +126,44 @@ func (r *Router) RemoveAdapter(providerID uint64) {
 	r.mu.Lock()
 	delete(r.adapters, providerID)
 	r.mu.Unlock()
+	pricing.GlobalCalculator().SetProviderPricing(providerID, nil)
+}
+
+// GetAdapter returns the cached adapter of a provider
+// Health probes reuse it so token caches and refresh callbacks are shared with real traffic
+func (r *Router) GetAdapter(providerID uint64) (provider.ProviderAdapter, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.adapters[providerID]
+	return a, ok
 }
 
 // Match returns matched routes for a client type and project
 func (r *Router) Match(ctx *MatchContext) ([]*MatchedRoute, error) {
+	return r.match(ctx, false)
+}
+
+// EarliestCooldownEnd returns when the first route that is currently skipped only because
+// its provider is in cooldown becomes available again, ok is false if there is no such route
+func (r *Router) EarliestCooldownEnd(ctx *MatchContext) (until time.Time, ok bool) {
+	matched, err := r.match(ctx, true)
+	if err != nil {
+		return time.Time{}, false
+	}
+	for _, m := range matched {
+		end := r.cooldownManager.GetCooldownUntil(m.Provider.ID, string(ctx.ClientType), ctx.routeModel(m.Route, m.Provider))
+		if end.IsZero() {
+			// 已有可用路由（冷却刚好结束），立即重试
+			return time.Now(), true
+		}
+		if !ok || end.Before(until) {
+			until, ok = end, true
+		}
+	}
+	return until, ok
+}
+
+func (r *Router) match(ctx *MatchContext, ignoreCooldown bool) ([]*MatchedRoute, error) {
 	clientType := ctx.ClientType
 	projectID := ctx.ProjectID
 	requestModel := ctx.RequestModel
@@ -194,8 +249,13 @@ func (r *Router) Match(ctx *MatchContext) ([]*MatchedRoute, error) {
 			continue
 		}
 
-		// Skip providers in cooldown
-		if r.cooldownManager.IsInCooldown(route.ProviderID, string(clientType)) {
+		// Skip providers outside the API token scope
+		if len(ctx.AllowedProviderIDs) > 0 && !slices.Contains(ctx.AllowedProviderIDs, route.ProviderID) {
+			continue
+		}
+
+		// Skip providers in cooldown (for the whole provider or the model this route would request)
+		if !ignoreCooldown && r.cooldownManager.IsInCooldown(route.ProviderID, string(clientType), ctx.routeModel(route, prov)) {
 			continue
 		}
 
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -11,9 +12,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/awsl-project/maxx/internal/adapter/provider/local"
+	"github.com/awsl-project/maxx/internal/admission"
+	"github.com/awsl-project/maxx/internal/anomaly"
+	"github.com/awsl-project/maxx/internal/billing"
+	"github.com/awsl-project/maxx/internal/cooldown"
+	"github.com/awsl-project/maxx/internal/detailstore"
 	"github.com/awsl-project/maxx/internal/domain"
 	"github.com/awsl-project/maxx/internal/event"
+	"github.com/awsl-project/maxx/internal/health"
 	"github.com/awsl-project/maxx/internal/pricing"
+	"github.com/awsl-project/maxx/internal/report"
 	"github.com/awsl-project/maxx/internal/repository"
 	"github.com/awsl-project/maxx/internal/usage"
 	"github.com/awsl-project/maxx/internal/version"
@@ -43,10 +52,19 @@ type AdminService struct {
 	usageStatsRepo      repository.UsageStatsRepository
 	responseModelRepo   repository.ResponseModelRepository
 	modelPriceRepo      repository.ModelPriceRepository
+	audit               auditLogger
 	serverAddr          string
 	adapterRefresher    ProviderAdapterRefresher
 	broadcaster         event.Broadcaster
 	pprofReloader       PprofReloader
+	detailStore         detailstore.Store
+	reportGenerator     *report.Generator
+	billingRuleRepo     repository.BillingRuleRepository
+	anomalyDetector     *anomaly.Detector
+	admissionQueue      *admission.Queue
+	healthChecker       *health.Checker
+	cooldownPolicyRepo  repository.CooldownPolicyRepository
+	modelFallbackRepo   repository.ModelFallbackRepository
 }
 
 // PprofReloader is an interface for reloading pprof configuration
@@ -70,6 +88,7 @@ func NewAdminService(
 	usageStatsRepo repository.UsageStatsRepository,
 	responseModelRepo repository.ResponseModelRepository,
 	modelPriceRepo repository.ModelPriceRepository,
+	auditLogRepo repository.AuditLogRepository,
 	serverAddr string,
 	adapterRefresher ProviderAdapterRefresher,
 	broadcaster event.Broadcaster,
@@ -90,6 +109,7 @@ func NewAdminService(
 		usageStatsRepo:      usageStatsRepo,
 		responseModelRepo:   responseModelRepo,
 		modelPriceRepo:      modelPriceRepo,
+		audit:               auditLogger{repo: auditLogRepo},
 		serverAddr:          serverAddr,
 		adapterRefresher:    adapterRefresher,
 		broadcaster:         broadcaster,
@@ -97,6 +117,19 @@ func NewAdminService(
 	}
 }
 
+// SetDetailStore enables on-demand loading of request bodies kept in the external detail store
+func (s *AdminService) SetDetailStore(store detailstore.Store) {
+	s.detailStore = store
+}
+
+// WithActor returns a shallow copy of the service that attributes audit log entries
+// to the given actor. Handlers call this once per request.
+func (s *AdminService) WithActor(actor, clientIP string) *AdminService {
+	cp := *s
+	cp.audit = s.audit.withActor(actor, clientIP)
+	return &cp
+}
+
 // ===== Provider API =====
 
 func (s *AdminService) GetProviders() ([]*domain.Provider, error) {
@@ -108,12 +141,23 @@ func (s *AdminService) GetProvider(id uint64) (*domain.Provider, error) {
 }
 
 func (s *AdminService) CreateProvider(provider *domain.Provider) error {
+	if provider.Config != nil {
+		if err := provider.Config.Network.Validate(); err != nil {
+			return err
+		}
+		if err := provider.Config.CircuitBreaker.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// Auto-set SupportedClientTypes based on provider type
 	s.autoSetSupportedClientTypes(provider)
+	fillLocalSupportModels(provider)
 
 	if err := s.providerRepo.Create(provider); err != nil {
 		return err
 	}
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityProvider, provider.ID, provider.Name, nil, provider)
 	// Refresh adapter cache for the new provider
 	if s.adapterRefresher != nil {
 		s.adapterRefresher.RefreshAdapter(provider)
@@ -122,12 +166,24 @@ func (s *AdminService) CreateProvider(provider *domain.Provider) error {
 }
 
 func (s *AdminService) UpdateProvider(provider *domain.Provider) error {
+	if provider.Config != nil {
+		if err := provider.Config.Network.Validate(); err != nil {
+			return err
+		}
+		if err := provider.Config.CircuitBreaker.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// Auto-set SupportedClientTypes based on provider type
 	s.autoSetSupportedClientTypes(provider)
+	fillLocalSupportModels(provider)
 
+	before, _ := s.providerRepo.GetByID(provider.ID)
 	if err := s.providerRepo.Update(provider); err != nil {
 		return err
 	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityProvider, provider.ID, provider.Name, before, provider)
 	// Refresh adapter cache for the updated provider
 	if s.adapterRefresher != nil {
 		s.adapterRefresher.RefreshAdapter(provider)
@@ -136,18 +192,33 @@ func (s *AdminService) UpdateProvider(provider *domain.Provider) error {
 }
 
 func (s *AdminService) DeleteProvider(id uint64) error {
+	before, _ := s.providerRepo.GetByID(id)
 	// Delete related routes first
 	routes, _ := s.routeRepo.List()
 	for _, route := range routes {
 		if route.ProviderID == id {
-			s.routeRepo.Delete(route.ID)
+			if err := s.routeRepo.Delete(route.ID); err == nil {
+				s.audit.record(domain.AuditActionDelete, domain.AuditEntityRoute, route.ID, "", route, nil)
+			}
 		}
 	}
+	s.deleteProviderCooldownPolicies(id)
 	// Remove adapter from cache
 	if s.adapterRefresher != nil {
 		s.adapterRefresher.RemoveAdapter(id)
 	}
-	return s.providerRepo.Delete(id)
+	if err := s.providerRepo.Delete(id); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityProvider, id, providerName(before), before, nil)
+	return nil
+}
+
+func providerName(p *domain.Provider) string {
+	if p == nil {
+		return ""
+	}
+	return p.Name
 }
 
 // ExportProviders exports all providers for backup/transfer
@@ -223,19 +294,42 @@ func (s *AdminService) GetRoute(id uint64) (*domain.Route, error) {
 }
 
 func (s *AdminService) CreateRoute(route *domain.Route) error {
-	return s.routeRepo.Create(route)
+	if err := s.routeRepo.Create(route); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityRoute, route.ID, "", nil, route)
+	return nil
 }
 
 func (s *AdminService) UpdateRoute(route *domain.Route) error {
-	return s.routeRepo.Update(route)
+	before, _ := s.routeRepo.GetByID(route.ID)
+	if before != nil {
+		// cached repositories hand out shared pointers; snapshot before the update lands
+		snapshot := *before
+		before = &snapshot
+	}
+	if err := s.routeRepo.Update(route); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityRoute, route.ID, "", before, route)
+	return nil
 }
 
 func (s *AdminService) BatchUpdateRoutePositions(updates []domain.RoutePositionUpdate) error {
-	return s.routeRepo.BatchUpdatePositions(updates)
+	if err := s.routeRepo.BatchUpdatePositions(updates); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityRoute, "positions", "", nil, updates)
+	return nil
 }
 
 func (s *AdminService) DeleteRoute(id uint64) error {
-	return s.routeRepo.Delete(id)
+	before, _ := s.routeRepo.GetByID(id)
+	if err := s.routeRepo.Delete(id); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityRoute, id, "", before, nil)
+	return nil
 }
 
 // ===== Project API =====
@@ -253,15 +347,33 @@ func (s *AdminService) GetProjectBySlug(slug string) (*domain.Project, error) {
 }
 
 func (s *AdminService) CreateProject(project *domain.Project) error {
-	return s.projectRepo.Create(project)
+	if err := s.projectRepo.Create(project); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityProject, project.ID, project.Slug, nil, project)
+	return nil
 }
 
 func (s *AdminService) UpdateProject(project *domain.Project) error {
-	return s.projectRepo.Update(project)
+	before, _ := s.projectRepo.GetByID(project.ID)
+	if err := s.projectRepo.Update(project); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityProject, project.ID, project.Slug, before, project)
+	return nil
 }
 
 func (s *AdminService) DeleteProject(id uint64) error {
-	return s.projectRepo.Delete(id)
+	before, _ := s.projectRepo.GetByID(id)
+	if err := s.projectRepo.Delete(id); err != nil {
+		return err
+	}
+	var slug string
+	if before != nil {
+		slug = before.Slug
+	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityProject, id, slug, before, nil)
+	return nil
 }
 
 // ===== Session API =====
@@ -285,10 +397,12 @@ func (s *AdminService) UpdateSessionProject(sessionID string, projectID uint64)
 	}
 
 	// Update session's projectID
+	before := *session
 	session.ProjectID = projectID
 	if err := s.sessionRepo.Update(session); err != nil {
 		return nil, err
 	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntitySession, sessionID, "", &before, session)
 
 	// Batch update all requests with this sessionID
 	updatedCount, err := s.proxyRequestRepo.UpdateProjectIDBySessionID(sessionID, projectID)
@@ -311,11 +425,31 @@ func (s *AdminService) RejectSession(sessionID string) (*domain.Session, error)
 	}
 
 	// Mark as rejected with timestamp
+	before := *session
 	now := time.Now()
 	session.RejectedAt = &now
 	if err := s.sessionRepo.Update(session); err != nil {
 		return nil, err
 	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntitySession, sessionID, "", &before, session)
+
+	return session, nil
+}
+
+// ApproveSessionLoop allows a session held by the loop guard to continue
+func (s *AdminService) ApproveSessionLoop(sessionID string) (*domain.Session, error) {
+	session, err := s.sessionRepo.GetBySessionID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *session
+	now := time.Now()
+	session.LoopApprovedAt = &now
+	if err := s.sessionRepo.Update(session); err != nil {
+		return nil, err
+	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntitySession, sessionID, "", &before, session)
 
 	return session, nil
 }
@@ -331,15 +465,29 @@ func (s *AdminService) GetRetryConfig(id uint64) (*domain.RetryConfig, error) {
 }
 
 func (s *AdminService) CreateRetryConfig(config *domain.RetryConfig) error {
-	return s.retryConfigRepo.Create(config)
+	if err := s.retryConfigRepo.Create(config); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityRetryConfig, config.ID, config.Name, nil, config)
+	return nil
 }
 
 func (s *AdminService) UpdateRetryConfig(config *domain.RetryConfig) error {
-	return s.retryConfigRepo.Update(config)
+	before, _ := s.retryConfigRepo.GetByID(config.ID)
+	if err := s.retryConfigRepo.Update(config); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityRetryConfig, config.ID, config.Name, before, config)
+	return nil
 }
 
 func (s *AdminService) DeleteRetryConfig(id uint64) error {
-	return s.retryConfigRepo.Delete(id)
+	before, _ := s.retryConfigRepo.GetByID(id)
+	if err := s.retryConfigRepo.Delete(id); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityRetryConfig, id, "", before, nil)
+	return nil
 }
 
 // ===== RoutingStrategy API =====
@@ -353,15 +501,27 @@ func (s *AdminService) GetRoutingStrategy(id uint64) (*domain.RoutingStrategy, e
 }
 
 func (s *AdminService) CreateRoutingStrategy(strategy *domain.RoutingStrategy) error {
-	return s.routingStrategyRepo.Create(strategy)
+	if err := s.routingStrategyRepo.Create(strategy); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityRoutingStrategy, strategy.ID, "", nil, strategy)
+	return nil
 }
 
 func (s *AdminService) UpdateRoutingStrategy(strategy *domain.RoutingStrategy) error {
-	return s.routingStrategyRepo.Update(strategy)
+	if err := s.routingStrategyRepo.Update(strategy); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityRoutingStrategy, strategy.ID, "", nil, strategy)
+	return nil
 }
 
 func (s *AdminService) DeleteRoutingStrategy(id uint64) error {
-	return s.routingStrategyRepo.Delete(id)
+	if err := s.routingStrategyRepo.Delete(id); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityRoutingStrategy, id, "", nil, nil)
+	return nil
 }
 
 // ===== ProxyRequest API =====
@@ -411,7 +571,14 @@ func (s *AdminService) GetProxyRequestsCountWithFilter(filter *repository.ProxyR
 }
 
 func (s *AdminService) GetProxyRequest(id uint64) (*domain.ProxyRequest, error) {
-	return s.proxyRequestRepo.GetByID(id)
+	req, err := s.proxyRequestRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	detailstore.LoadProxyRequest(ctx, s.detailStore, req)
+	return req, nil
 }
 
 func (s *AdminService) GetActiveProxyRequests() ([]*domain.ProxyRequest, error) {
@@ -419,7 +586,14 @@ func (s *AdminService) GetActiveProxyRequests() ([]*domain.ProxyRequest, error)
 }
 
 func (s *AdminService) GetProxyUpstreamAttempts(proxyRequestID uint64) ([]*domain.ProxyUpstreamAttempt, error) {
-	return s.attemptRepo.ListByProxyRequestID(proxyRequestID)
+	attempts, err := s.attemptRepo.ListByProxyRequestID(proxyRequestID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	detailstore.LoadAttempts(ctx, s.detailStore, attempts)
+	return attempts, nil
 }
 
 func (s *AdminService) GetProviderStats(clientType string, projectID uint64) (map[uint64]*domain.ProviderStats, error) {
@@ -445,9 +619,24 @@ func (s *AdminService) GetSetting(key string) (string, error) {
 }
 
 func (s *AdminService) UpdateSetting(key, value string) error {
+	var exchangeRates map[string]float64
+	if key == domain.SettingKeyExchangeRates {
+		rates, err := pricing.ParseExchangeRates(value)
+		if err != nil {
+			return err
+		}
+		if err := checkRatesCoverPrices(s.modelPriceRepo, rates); err != nil {
+			return err
+		}
+		exchangeRates = rates
+	}
+
+	oldValue, _ := s.settingRepo.Get(key)
 	if err := s.settingRepo.Set(key, value); err != nil {
 		return err
 	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntitySetting, key, key,
+		map[string]string{"key": key, "value": oldValue}, map[string]string{"key": key, "value": value})
 
 	// 如果更新的是 pprof 相关设置，触发重载
 	switch key {
@@ -457,15 +646,25 @@ func (s *AdminService) UpdateSetting(key, value string) error {
 				return fmt.Errorf("设置已保存，但重载 pprof 失败: %w", err)
 			}
 		}
+	case domain.SettingKeyExchangeRates:
+		pricing.GlobalCalculator().SetExchangeRates(exchangeRates)
 	}
 
 	return nil
 }
 
 func (s *AdminService) DeleteSetting(key string) error {
+	if key == domain.SettingKeyExchangeRates {
+		if err := checkRatesCoverPrices(s.modelPriceRepo, nil); err != nil {
+			return err
+		}
+	}
+	oldValue, _ := s.settingRepo.Get(key)
 	if err := s.settingRepo.Delete(key); err != nil {
 		return err
 	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntitySetting, key, key,
+		map[string]string{"key": key, "value": oldValue}, nil)
 
 	// 如果删除的是 pprof 相关设置，触发重载
 	switch key {
@@ -475,11 +674,42 @@ func (s *AdminService) DeleteSetting(key string) error {
 				return fmt.Errorf("设置已删除，但重载 pprof 失败: %w", err)
 			}
 		}
+	case domain.SettingKeyExchangeRates:
+		pricing.GlobalCalculator().SetExchangeRates(nil)
 	}
 
 	return nil
 }
 
+// ===== Cooldown API =====
+
+// SetProviderCooldown manually puts a provider (optionally a single client type) in cooldown
+func (s *AdminService) SetProviderCooldown(providerID uint64, clientType string, until time.Time) {
+	cm := cooldown.Default()
+	key := cooldown.CooldownKey{ProviderID: providerID, ClientType: clientType}
+	before := cm.GetCooldownInfo(key, "")
+	cm.SetCooldownUntil(providerID, clientType, until)
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityCooldown, providerID, clientType, before,
+		cm.GetCooldownInfo(key, ""))
+}
+
+// ClearProviderCooldown clears all cooldowns (global and client-type-specific) for a provider
+func (s *AdminService) ClearProviderCooldown(providerID uint64) {
+	cm := cooldown.Default()
+	var before []*cooldown.CooldownInfo
+	for key := range cm.GetAllCooldowns() {
+		if key.ProviderID == providerID {
+			if info := cm.GetCooldownInfo(key, ""); info != nil {
+				before = append(before, info)
+			}
+		}
+	}
+	cm.ClearCooldown(providerID, "")
+	if len(before) > 0 {
+		s.audit.record(domain.AuditActionDelete, domain.AuditEntityCooldown, providerID, "", before, nil)
+	}
+}
+
 // ===== Proxy Status API =====
 
 type ProxyStatus struct {
@@ -549,6 +779,22 @@ func (s *AdminService) GetLogs(limit int) (*LogsResult, error) {
 
 // ===== Private helpers =====
 
+// fillLocalSupportModels 本地 Provider 未配置 SupportModels 时通过模型发现填充，
+// 避免路由把本地服务不存在的模型发给它；发现失败不影响保存
+func fillLocalSupportModels(p *domain.Provider) {
+	if p.Type != "local" || p.Config == nil || p.Config.Local == nil || len(p.SupportModels) > 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	models, err := local.DiscoverWithNetwork(ctx, p.Config.Local, p.Config.Network)
+	if err != nil {
+		log.Printf("[Admin] Failed to discover models of local provider %s: %v", p.Name, err)
+		return
+	}
+	p.SupportModels = models
+}
+
 // autoSetSupportedClientTypes sets SupportedClientTypes based on provider type
 func (s *AdminService) autoSetSupportedClientTypes(provider *domain.Provider) {
 	switch provider.Type {
@@ -564,6 +810,33 @@ func (s *AdminService) autoSetSupportedClientTypes(provider *domain.Provider) {
 		provider.SupportedClientTypes = []domain.ClientType{
 			domain.ClientTypeClaude,
 		}
+	case "bedrock":
+		// Bedrock adapter speaks the Claude Messages API only
+		provider.SupportedClientTypes = []domain.ClientType{
+			domain.ClientTypeClaude,
+		}
+	case "azure_openai":
+		// Azure OpenAI serves Chat Completions and the Responses API
+		provider.SupportedClientTypes = []domain.ClientType{
+			domain.ClientTypeOpenAI,
+			domain.ClientTypeCodex,
+		}
+	case "local":
+		// Local models are served through OpenAI Chat Completions (Ollama is converted by the adapter)
+		provider.SupportedClientTypes = []domain.ClientType{
+			domain.ClientTypeOpenAI,
+		}
+	case "copilot":
+		// Copilot serves OpenAI, Claude and Gemini models through Chat Completions
+		provider.SupportedClientTypes = []domain.ClientType{
+			domain.ClientTypeOpenAI,
+		}
+	case "vertex":
+		// Vertex serves Claude models via the Anthropic API and Gemini models natively
+		provider.SupportedClientTypes = []domain.ClientType{
+			domain.ClientTypeClaude,
+			domain.ClientTypeGemini,
+		}
 	case "codex":
 		// Codex natively supports Codex protocol only
 		provider.SupportedClientTypes = []domain.ClientType{
@@ -589,7 +862,17 @@ func (s *AdminService) GetAPIToken(id uint64) (*domain.APIToken, error) {
 }
 
 // CreateAPIToken creates a new API token and returns the plain token (only shown once)
-func (s *AdminService) CreateAPIToken(name, description string, projectID uint64, expiresAt *time.Time) (*domain.APITokenCreateResult, error) {
+func (s *AdminService) CreateAPIToken(name, description string, projectID uint64, expiresAt *time.Time, priority domain.PriorityClass, scope domain.APITokenScope) (*domain.APITokenCreateResult, error) {
+	if err := scope.Validate(); err != nil {
+		return nil, err
+	}
+	if !priority.Valid() {
+		return nil, fmt.Errorf("%w: invalid priority %q", domain.ErrInvalidInput, priority)
+	}
+	if scope.ProjectAdmin && projectID == 0 {
+		return nil, fmt.Errorf("%w: project admin tokens must be bound to a project", domain.ErrInvalidInput)
+	}
+
 	// Generate token
 	plain, prefix, err := generateAPIToken()
 	if err != nil {
@@ -604,11 +887,15 @@ func (s *AdminService) CreateAPIToken(name, description string, projectID uint64
 		ProjectID:   projectID,
 		IsEnabled:   true,
 		ExpiresAt:   expiresAt,
+		Priority:    priority,
+
+		APITokenScope: scope,
 	}
 
 	if err := s.apiTokenRepo.Create(token); err != nil {
 		return nil, err
 	}
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityAPIToken, token.ID, token.Name, nil, token)
 
 	return &domain.APITokenCreateResult{
 		Token:    plain,
@@ -617,11 +904,39 @@ func (s *AdminService) CreateAPIToken(name, description string, projectID uint64
 }
 
 func (s *AdminService) UpdateAPIToken(token *domain.APIToken) error {
-	return s.apiTokenRepo.Update(token)
+	if err := token.APITokenScope.Validate(); err != nil {
+		return err
+	}
+	if !token.Priority.Valid() {
+		return fmt.Errorf("%w: invalid priority %q", domain.ErrInvalidInput, token.Priority)
+	}
+	if token.ProjectAdmin && token.ProjectID == 0 {
+		return fmt.Errorf("%w: project admin tokens must be bound to a project", domain.ErrInvalidInput)
+	}
+	before, _ := s.apiTokenRepo.GetByID(token.ID)
+	if before != nil {
+		// cached repositories hand out shared pointers; snapshot before the update lands
+		snapshot := *before
+		before = &snapshot
+	}
+	if err := s.apiTokenRepo.Update(token); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityAPIToken, token.ID, token.Name, before, token)
+	return nil
 }
 
 func (s *AdminService) DeleteAPIToken(id uint64) error {
-	return s.apiTokenRepo.Delete(id)
+	before, _ := s.apiTokenRepo.GetByID(id)
+	if err := s.apiTokenRepo.Delete(id); err != nil {
+		return err
+	}
+	var name string
+	if before != nil {
+		name = before.Name
+	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityAPIToken, id, name, before, nil)
+	return nil
 }
 
 // generateAPIToken creates a new random token
@@ -662,22 +977,46 @@ func (s *AdminService) GetModelMapping(id uint64) (*domain.ModelMapping, error)
 
 // CreateModelMapping creates a new model mapping
 func (s *AdminService) CreateModelMapping(mapping *domain.ModelMapping) error {
-	return s.modelMappingRepo.Create(mapping)
+	if err := s.modelMappingRepo.Create(mapping); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityModelMapping, mapping.ID, mapping.Pattern, nil, mapping)
+	return nil
 }
 
 // UpdateModelMapping updates an existing model mapping
 func (s *AdminService) UpdateModelMapping(mapping *domain.ModelMapping) error {
-	return s.modelMappingRepo.Update(mapping)
+	before, _ := s.modelMappingRepo.GetByID(mapping.ID)
+	if before != nil {
+		// cached repositories hand out shared pointers; snapshot before the update lands
+		snapshot := *before
+		before = &snapshot
+	}
+	if err := s.modelMappingRepo.Update(mapping); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityModelMapping, mapping.ID, mapping.Pattern, before, mapping)
+	return nil
 }
 
 // DeleteModelMapping deletes a model mapping by ID
 func (s *AdminService) DeleteModelMapping(id uint64) error {
-	return s.modelMappingRepo.Delete(id)
+	before, _ := s.modelMappingRepo.GetByID(id)
+	if err := s.modelMappingRepo.Delete(id); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityModelMapping, id, "", before, nil)
+	return nil
 }
 
 // ClearAllModelMappings deletes all model mappings (both builtin and non-builtin)
 func (s *AdminService) ClearAllModelMappings() error {
-	return s.modelMappingRepo.ClearAll()
+	before, _ := s.modelMappingRepo.List()
+	if err := s.modelMappingRepo.ClearAll(); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityModelMapping, "all", "", before, nil)
+	return nil
 }
 
 // ===== Response Model API =====
@@ -689,7 +1028,13 @@ func (s *AdminService) GetResponseModelNames() ([]string, error) {
 
 // ResetModelMappingsToDefaults re-seeds default builtin mappings
 func (s *AdminService) ResetModelMappingsToDefaults() error {
-	return s.modelMappingRepo.SeedDefaults()
+	before, _ := s.modelMappingRepo.List()
+	if err := s.modelMappingRepo.SeedDefaults(); err != nil {
+		return err
+	}
+	after, _ := s.modelMappingRepo.List()
+	s.audit.record(domain.AuditActionReset, domain.AuditEntityModelMapping, "all", "", before, after)
+	return nil
 }
 
 // GetAvailableClientTypes returns all available client types for model mapping
@@ -709,6 +1054,11 @@ func (s *AdminService) GetUsageStats(filter repository.UsageStatsFilter) ([]*dom
 	return s.usageStatsRepo.Query(filter)
 }
 
+// GetWastedSpend returns the cost of failed and cancelled attempts by provider and reason
+func (s *AdminService) GetWastedSpend(filter repository.WastedSpendFilter) ([]*domain.WastedSpend, error) {
+	return s.attemptRepo.GetWastedSpend(filter)
+}
+
 // GetDashboardData returns all dashboard data in a single query
 func (s *AdminService) GetDashboardData() (*domain.DashboardData, error) {
 	return s.usageStatsRepo.QueryDashboardData()
@@ -810,13 +1160,14 @@ func (s *AdminService) RecalculateCosts() (*RecalculateCostsResult, error) {
 	broadcastProgress("calculating", 0, int(totalCount), fmt.Sprintf("Processing %d attempts...", totalCount))
 
 	calculator := pricing.GlobalCalculator()
+	billingEngine := billing.Default()
 	processedCount := 0
 	const batchSize = 100
 	affectedRequestIDs := make(map[uint64]struct{})
 
 	// 2. Stream through attempts, process and update each batch immediately
 	err = s.attemptRepo.StreamForCostCalc(batchSize, func(batch []*domain.AttemptCostData) error {
-		attemptUpdates := make(map[uint64]uint64, len(batch))
+		attemptUpdates := make(map[uint64]domain.CostUpdate, len(batch))
 
 		for _, attempt := range batch {
 			// Use responseModel if available, otherwise use mappedModel or requestModel
@@ -838,15 +1189,20 @@ func (s *AdminService) RecalculateCosts() (*RecalculateCostsResult, error) {
 				Cache1hCreationCount: attempt.Cache1hWriteCount,
 			}
 
-			// Calculate new cost
-			newCost := calculator.Calculate(model, metrics)
+			// Calculate new cost (provider override price and recorded multiplier)
+			costResult := calculator.CalculateForProvider(attempt.ProviderID, model, metrics, attempt.Multiplier)
+			update := domain.CostUpdate{
+				Cost:         costResult.Cost,
+				UpstreamCost: costResult.UpstreamCost,
+				BilledCost:   billingEngine.Reprice(attempt.ProjectID, attempt.APITokenID, attempt.Cost, attempt.BilledCost, costResult.Cost),
+			}
 
 			// Track affected request IDs
 			affectedRequestIDs[attempt.ProxyRequestID] = struct{}{}
 
 			// Track if attempt needs update
-			if newCost != attempt.Cost {
-				attemptUpdates[attempt.ID] = newCost
+			if update.Cost != attempt.Cost || update.UpstreamCost != attempt.UpstreamCost || update.BilledCost != attempt.BilledCost {
+				attemptUpdates[attempt.ID] = update
 			}
 
 			processedCount++
@@ -931,7 +1287,8 @@ func (s *AdminService) RecalculateRequestCost(requestID uint64) (*RecalculateReq
 	}
 
 	calculator := pricing.GlobalCalculator()
-	var totalCost uint64
+	billingEngine := billing.Default()
+	var total domain.CostUpdate
 
 	// 3. Recalculate cost for each attempt
 	for _, attempt := range attempts {
@@ -955,12 +1312,19 @@ func (s *AdminService) RecalculateRequestCost(requestID uint64) (*RecalculateReq
 		}
 
 		// Calculate new cost
-		newCost := calculator.Calculate(model, metrics)
-		totalCost += newCost
+		costResult := calculator.CalculateForProvider(attempt.ProviderID, model, metrics, attempt.Multiplier)
+		update := domain.CostUpdate{
+			Cost:         costResult.Cost,
+			UpstreamCost: costResult.UpstreamCost,
+			BilledCost:   billingEngine.Reprice(request.ProjectID, request.APITokenID, attempt.Cost, attempt.BilledCost, costResult.Cost),
+		}
+		total.Cost += update.Cost
+		total.UpstreamCost += update.UpstreamCost
+		total.BilledCost += update.BilledCost
 
 		// Update attempt cost if changed
-		if newCost != attempt.Cost {
-			if err := s.attemptRepo.UpdateCost(attempt.ID, newCost); err != nil {
+		if update.Cost != attempt.Cost || update.UpstreamCost != attempt.UpstreamCost || update.BilledCost != attempt.BilledCost {
+			if err := s.attemptRepo.UpdateCost(attempt.ID, update); err != nil {
 				log.Printf("[RecalculateRequestCost] Failed to update attempt %d cost: %v", attempt.ID, err)
 				continue
 			}
@@ -969,8 +1333,8 @@ func (s *AdminService) RecalculateRequestCost(requestID uint64) (*RecalculateReq
 	}
 
 	// 4. Update request cost
-	result.NewCost = totalCost
-	if err := s.proxyRequestRepo.UpdateCost(requestID, totalCost); err != nil {
+	result.NewCost = total.Cost
+	if err := s.proxyRequestRepo.UpdateCost(requestID, total); err != nil {
 		return nil, fmt.Errorf("failed to update request cost: %w", err)
 	}
 
@@ -995,30 +1359,61 @@ func (s *AdminService) GetModelPrice(id uint64) (*domain.ModelPrice, error) {
 
 // CreateModelPrice creates a new model price record
 func (s *AdminService) CreateModelPrice(price *domain.ModelPrice) error {
-	return s.modelPriceRepo.Create(price)
+	if err := s.normalizeModelPrice(price); err != nil {
+		return err
+	}
+	if err := s.modelPriceRepo.Create(price); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityModelPrice, price.ID, price.ModelID, nil, price)
+	return nil
 }
 
 // UpdateModelPrice updates an existing model price (creates a new version)
 // In practice, this creates a new price record for the same model
 func (s *AdminService) UpdateModelPrice(price *domain.ModelPrice) error {
 	// For versioned pricing, we create a new record instead of updating
+	before, _ := s.modelPriceRepo.GetByID(price.ID)
 	// Clear the ID so GORM generates a new one
 	price.ID = 0
 	price.CreatedAt = time.Time{}
-	return s.modelPriceRepo.Create(price)
+	price.Source = ""
+	if err := s.normalizeModelPrice(price); err != nil {
+		return err
+	}
+	if err := s.modelPriceRepo.Create(price); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityModelPrice, price.ID, price.ModelID, before, price)
+	return nil
 }
 
 // DeleteModelPrice deletes a model price record
 func (s *AdminService) DeleteModelPrice(id uint64) error {
-	return s.modelPriceRepo.Delete(id)
+	before, _ := s.modelPriceRepo.GetByID(id)
+	if err := s.modelPriceRepo.Delete(id); err != nil {
+		return err
+	}
+	var modelID string
+	if before != nil {
+		modelID = before.ModelID
+	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityModelPrice, id, modelID, before, nil)
+	return nil
 }
 
-// GetModelPriceHistory returns all price records for a model
-func (s *AdminService) GetModelPriceHistory(modelID string) ([]*domain.ModelPrice, error) {
-	return s.modelPriceRepo.ListByModelID(modelID)
+// GetModelPriceHistory returns all price records for a model (providerID 0 = global prices)
+func (s *AdminService) GetModelPriceHistory(providerID uint64, modelID string) ([]*domain.ModelPrice, error) {
+	return s.modelPriceRepo.ListByModelID(providerID, modelID)
 }
 
 // ResetModelPricesToDefaults resets all model prices to defaults (soft deletes existing)
 func (s *AdminService) ResetModelPricesToDefaults() ([]*domain.ModelPrice, error) {
-	return s.modelPriceRepo.ResetToDefaults()
+	before, _ := s.modelPriceRepo.ListCurrentPrices()
+	prices, err := s.modelPriceRepo.ResetToDefaults()
+	if err != nil {
+		return nil, err
+	}
+	s.audit.record(domain.AuditActionReset, domain.AuditEntityModelPrice, "all", "", before, prices)
+	return prices, nil
 }
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"github.com/awsl-project/maxx/internal/admission"
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// SetAdmissionQueue enables reporting of admission queue statistics
+func (s *AdminService) SetAdmissionQueue(q *admission.Queue) {
+	s.admissionQueue = q
+}
+
+// GetAdmissionQueueStats returns the current admission queue snapshot
+// 统计只保存在内存中，重启后清空
+func (s *AdminService) GetAdmissionQueueStats() *domain.AdmissionQueueStats {
+	if s.admissionQueue == nil {
+		return &domain.AdmissionQueueStats{Classes: []*domain.AdmissionClassStats{}}
+	}
+	return s.admissionQueue.Stats()
+}
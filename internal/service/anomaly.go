@@ -0,0 +1,20 @@
+package service
+
+import (
+	"github.com/awsl-project/maxx/internal/anomaly"
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// SetAnomalyDetector enables listing of recent anomaly alerts
+func (s *AdminService) SetAnomalyDetector(d *anomaly.Detector) {
+	s.anomalyDetector = d
+}
+
+// GetAnomalyAlerts returns the most recent anomaly alerts, newest first
+// 告警只保存在内存中，重启后清空
+func (s *AdminService) GetAnomalyAlerts() []*domain.AnomalyAlert {
+	if s.anomalyDetector == nil {
+		return []*domain.AnomalyAlert{}
+	}
+	return s.anomalyDetector.Recent()
+}
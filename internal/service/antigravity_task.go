@@ -133,8 +133,14 @@ func (s *AntigravityTaskService) refreshAllQuotas(ctx context.Context) bool {
 			continue
 		}
 
+		client, err := oauthHTTPClient(provider.Config.Network)
+		if err != nil {
+			log.Printf("[AntigravityTask] Invalid network config for provider %d: %v", provider.ID, err)
+			continue
+		}
+
 		// Fetch quota from API
-		quota, err := antigravity.FetchQuotaForProvider(ctx, config.RefreshToken, config.ProjectID)
+		quota, err := antigravity.FetchQuotaForProvider(ctx, client, config.RefreshToken, config.ProjectID)
 		if err != nil {
 			log.Printf("[AntigravityTask] Failed to fetch quota for provider %d: %v", provider.ID, err)
 			continue
@@ -5,9 +5,13 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log"
+	"slices"
 	"time"
 
+	"github.com/awsl-project/maxx/internal/cooldown"
 	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/pricing"
 	"github.com/awsl-project/maxx/internal/repository"
 	"github.com/awsl-project/maxx/internal/version"
 )
@@ -23,7 +27,10 @@ type BackupService struct {
 	apiTokenRepo        repository.APITokenRepository
 	modelMappingRepo    repository.ModelMappingRepository
 	modelPriceRepo      repository.ModelPriceRepository
+	cooldownPolicyRepo  repository.CooldownPolicyRepository
+	modelFallbackRepo   repository.ModelFallbackRepository
 	adapterRefresher    ProviderAdapterRefresher
+	audit               auditLogger
 }
 
 // NewBackupService creates a new backup service
@@ -37,7 +44,10 @@ func NewBackupService(
 	apiTokenRepo repository.APITokenRepository,
 	modelMappingRepo repository.ModelMappingRepository,
 	modelPriceRepo repository.ModelPriceRepository,
+	cooldownPolicyRepo repository.CooldownPolicyRepository,
+	modelFallbackRepo repository.ModelFallbackRepository,
 	adapterRefresher ProviderAdapterRefresher,
+	auditLogRepo repository.AuditLogRepository,
 ) *BackupService {
 	return &BackupService{
 		providerRepo:        providerRepo,
@@ -49,10 +59,20 @@ func NewBackupService(
 		apiTokenRepo:        apiTokenRepo,
 		modelMappingRepo:    modelMappingRepo,
 		modelPriceRepo:      modelPriceRepo,
+		cooldownPolicyRepo:  cooldownPolicyRepo,
+		modelFallbackRepo:   modelFallbackRepo,
 		adapterRefresher:    adapterRefresher,
+		audit:               auditLogger{repo: auditLogRepo},
 	}
 }
 
+// WithActor returns a shallow copy of the service that attributes audit log entries to the given actor
+func (s *BackupService) WithActor(actor, clientIP string) *BackupService {
+	cp := *s
+	cp.audit = s.audit.withActor(actor, clientIP)
+	return &cp
+}
+
 // importContext holds ID mappings during import
 type importContext struct {
 	providerNameToID    map[string]uint64
@@ -63,6 +83,8 @@ type importContext struct {
 	routeKeyToID map[string]uint64
 	// modelMappingKey format generated by buildModelMappingKey
 	modelMappingKeys map[string]struct{}
+	// 导入后生效的汇率，用于校验非 USD 价格
+	exchangeRates map[string]float64
 }
 
 func newImportContext() *importContext {
@@ -187,7 +209,7 @@ func (s *BackupService) Export() (*domain.BackupFile, error) {
 	}
 	for _, t := range tokens {
 		apiTokenIDToName[t.ID] = t.Name
-		backup.Data.APITokens = append(backup.Data.APITokens, domain.BackupAPIToken{
+		bt := domain.BackupAPIToken{
 			Name:        t.Name,
 			Token:       t.Token,
 			TokenPrefix: t.TokenPrefix,
@@ -196,7 +218,19 @@ func (s *BackupService) Export() (*domain.BackupFile, error) {
 			IsEnabled:   t.IsEnabled,
 			DevMode:     t.DevMode,
 			ExpiresAt:   t.ExpiresAt,
-		})
+
+			AllowedClientTypes: t.AllowedClientTypes,
+			AllowedModels:      t.AllowedModels,
+			AllowedIPs:         t.AllowedIPs,
+			ProjectAdmin:       t.ProjectAdmin,
+			ProviderRestricted: len(t.AllowedProviderIDs) > 0,
+		}
+		for _, id := range t.AllowedProviderIDs {
+			if name, ok := providerIDToName[id]; ok {
+				bt.AllowedProviderNames = append(bt.AllowedProviderNames, name)
+			}
+		}
+		backup.Data.APITokens = append(backup.Data.APITokens, bt)
 	}
 
 	// 8. Export ModelMappings
@@ -245,8 +279,19 @@ func (s *BackupService) Export() (*domain.BackupFile, error) {
 		return nil, fmt.Errorf("failed to export model prices: %w", err)
 	}
 	for _, mp := range modelPrices {
+		var providerName string
+		if mp.ProviderID > 0 {
+			name, ok := providerIDToName[mp.ProviderID]
+			if !ok {
+				continue // Provider 已删除，覆盖价格不再生效
+			}
+			providerName = name
+		}
 		backup.Data.ModelPrices = append(backup.Data.ModelPrices, domain.BackupModelPrice{
 			ModelID:                mp.ModelID,
+			ProviderName:           providerName,
+			Currency:               mp.Currency,
+			Source:                 mp.Source,
 			InputPriceMicro:        mp.InputPriceMicro,
 			OutputPriceMicro:       mp.OutputPriceMicro,
 			CacheReadPriceMicro:    mp.CacheReadPriceMicro,
@@ -261,6 +306,53 @@ func (s *BackupService) Export() (*domain.BackupFile, error) {
 		})
 	}
 
+	// 10. Export CooldownPolicies
+	cooldownPolicies, err := s.cooldownPolicyRepo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to export cooldown policies: %w", err)
+	}
+	for _, cp := range cooldownPolicies {
+		var providerName string
+		if cp.ProviderID > 0 {
+			name, ok := providerIDToName[cp.ProviderID]
+			if !ok {
+				continue // Provider 已删除，覆盖策略不再生效
+			}
+			providerName = name
+		}
+		backup.Data.CooldownPolicies = append(backup.Data.CooldownPolicies, domain.BackupCooldownPolicy{
+			ProviderName: providerName,
+			Reason:       cp.Reason,
+			Type:         cp.Type,
+			BaseSeconds:  cp.BaseSeconds,
+			MaxSeconds:   cp.MaxSeconds,
+		})
+	}
+
+	// 11. Export ModelFallbacks
+	modelFallbacks, err := s.modelFallbackRepo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to export model fallbacks: %w", err)
+	}
+	for _, f := range modelFallbacks {
+		var projectSlug string
+		if f.ProjectID > 0 {
+			slug, ok := projectIDToSlug[f.ProjectID]
+			if !ok {
+				continue // 项目已删除，降级链不再生效
+			}
+			projectSlug = slug
+		}
+		backup.Data.ModelFallbacks = append(backup.Data.ModelFallbacks, domain.BackupModelFallback{
+			ClientType:   f.ClientType,
+			ProjectSlug:  projectSlug,
+			Pattern:      f.Pattern,
+			Models:       f.Models,
+			NotifyClient: f.NotifyClient,
+			Priority:     f.Priority,
+		})
+	}
+
 	return backup, nil
 }
 
@@ -281,7 +373,7 @@ func (s *BackupService) Import(backup *domain.BackupFile, opts domain.ImportOpti
 
 	// Import in dependency order
 	// 1. SystemSettings (no dependencies)
-	s.importSystemSettings(backup.Data.SystemSettings, opts, result)
+	s.importSystemSettings(backup.Data.SystemSettings, opts, result, ctx)
 
 	// 2. RetryConfigs (no dependencies)
 	s.importRetryConfigs(backup.Data.RetryConfigs, opts, result, ctx)
@@ -304,14 +396,37 @@ func (s *BackupService) Import(backup *domain.BackupFile, opts domain.ImportOpti
 	// 8. ModelMappings (depends on Providers, Projects, Routes, APITokens)
 	s.importModelMappings(backup.Data.ModelMappings, opts, result, ctx)
 
-	// 9. ModelPrices (independent)
-	s.importModelPrices(backup.Data.ModelPrices, opts, result)
+	// 9. ModelPrices (provider overrides depend on Providers)
+	s.importModelPrices(backup.Data.ModelPrices, opts, result, ctx)
+
+	// 10. CooldownPolicies (provider overrides depend on Providers)
+	s.importCooldownPolicies(backup.Data.CooldownPolicies, opts, result, ctx)
+
+	// 11. ModelFallbacks (project scope depends on Projects)
+	s.importModelFallbacks(backup.Data.ModelFallbacks, opts, result, ctx)
+
+	if !opts.DryRun {
+		if err := cooldown.Default().ReloadPolicies(); err != nil {
+			log.Printf("[Cooldown] Failed to reload cooldown policies: %v", err)
+		}
+		s.audit.record(domain.AuditActionImport, domain.AuditEntityBackup, "", backup.AppVersion, nil, map[string]any{
+			"version":    backup.Version,
+			"exportedAt": backup.ExportedAt,
+			"options":    opts,
+			"result":     result,
+			"data":       backup.Data,
+		})
+	}
 
 	return result, nil
 }
 
 // loadExistingMappings loads existing data and populates the import context
 func (s *BackupService) loadExistingMappings(ctx *importContext) error {
+	// Load exchange rates (invalid settings leave non-USD prices without a rate)
+	value, _ := s.settingRepo.Get(domain.SettingKeyExchangeRates)
+	ctx.exchangeRates, _ = pricing.ParseExchangeRates(value)
+
 	// Load providers
 	providers, err := s.providerRepo.List()
 	if err != nil {
@@ -398,30 +513,50 @@ func (s *BackupService) loadExistingMappings(ctx *importContext) error {
 	return nil
 }
 
-func (s *BackupService) importSystemSettings(settings []domain.BackupSystemSetting, opts domain.ImportOptions, result *domain.ImportResult) {
+func (s *BackupService) importSystemSettings(settings []domain.BackupSystemSetting, opts domain.ImportOptions, result *domain.ImportResult, ctx *importContext) {
 	summary := domain.ImportSummary{}
 
 	for _, bs := range settings {
 		existing, _ := s.settingRepo.Get(bs.Key)
-		if existing != "" {
-			switch opts.ConflictStrategy {
-			case "skip", "":
+		if existing != "" && (opts.ConflictStrategy == "skip" || opts.ConflictStrategy == "") {
+			summary.Skipped++
+			continue
+		}
+		if existing != "" && opts.ConflictStrategy == "error" {
+			result.Success = false
+			result.Errors = append(result.Errors, fmt.Sprintf("SystemSetting conflict: key '%s' already exists", bs.Key))
+			return
+		}
+		if existing != "" && opts.ConflictStrategy != "overwrite" {
+			continue
+		}
+
+		var rates map[string]float64
+		if bs.Key == domain.SettingKeyExchangeRates {
+			parsed, err := pricing.ParseExchangeRates(bs.Value)
+			if err == nil {
+				err = checkRatesCoverPrices(s.modelPriceRepo, parsed)
+			}
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("SystemSetting '%s' skipped: %v", bs.Key, err))
 				summary.Skipped++
 				continue
-			case "overwrite":
-				if !opts.DryRun {
-					s.settingRepo.Set(bs.Key, bs.Value)
-				}
-				summary.Updated++
-			case "error":
-				result.Success = false
-				result.Errors = append(result.Errors, fmt.Sprintf("SystemSetting conflict: key '%s' already exists", bs.Key))
-				return
 			}
-		} else {
+			rates = parsed
+		}
+
+		if !opts.DryRun {
+			s.settingRepo.Set(bs.Key, bs.Value)
+		}
+		if rates != nil {
+			ctx.exchangeRates = rates
 			if !opts.DryRun {
-				s.settingRepo.Set(bs.Key, bs.Value)
+				pricing.GlobalCalculator().SetExchangeRates(rates)
 			}
+		}
+		if existing != "" {
+			summary.Updated++
+		} else {
 			summary.Imported++
 		}
 	}
@@ -742,6 +877,25 @@ func (s *BackupService) importAPITokens(tokens []domain.BackupAPIToken, opts dom
 			IsEnabled:   bt.IsEnabled,
 			DevMode:     bt.DevMode,
 			ExpiresAt:   bt.ExpiresAt,
+
+			APITokenScope: domain.APITokenScope{
+				AllowedClientTypes: bt.AllowedClientTypes,
+				AllowedModels:      bt.AllowedModels,
+				AllowedIPs:         bt.AllowedIPs,
+				ProjectAdmin:       bt.ProjectAdmin && projectID != 0,
+			},
+		}
+		for _, name := range bt.AllowedProviderNames {
+			if id, ok := ctx.providerNameToID[name]; ok {
+				t.AllowedProviderIDs = append(t.AllowedProviderIDs, id)
+			} else {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("APIToken '%s': allowed provider '%s' not found", bt.Name, name))
+			}
+		}
+		if (bt.ProviderRestricted || len(bt.AllowedProviderNames) > 0) && len(t.AllowedProviderIDs) == 0 {
+			// An empty list would mean "all providers", never widen a restricted token
+			t.IsEnabled = false
+			result.Warnings = append(result.Warnings, fmt.Sprintf("APIToken '%s' disabled: none of its allowed providers exist", bt.Name))
 		}
 
 		if !opts.DryRun {
@@ -852,7 +1006,7 @@ func (s *BackupService) importModelMappings(mappings []domain.BackupModelMapping
 	result.Summary["modelMappings"] = summary
 }
 
-func (s *BackupService) importModelPrices(prices []domain.BackupModelPrice, opts domain.ImportOptions, result *domain.ImportResult) {
+func (s *BackupService) importModelPrices(prices []domain.BackupModelPrice, opts domain.ImportOptions, result *domain.ImportResult, ctx *importContext) {
 	summary := domain.ImportSummary{}
 
 	existingPrices, err := s.modelPriceRepo.ListCurrentPrices()
@@ -861,13 +1015,36 @@ func (s *BackupService) importModelPrices(prices []domain.BackupModelPrice, opts
 		result.Summary["modelPrices"] = summary
 		return
 	}
-	existingByModelID := make(map[string]*domain.ModelPrice, len(existingPrices))
+	// key: "providerID:modelID"
+	existingByKey := make(map[string]*domain.ModelPrice, len(existingPrices))
 	for _, existing := range existingPrices {
-		existingByModelID[existing.ModelID] = existing
+		existingByKey[fmt.Sprintf("%d:%s", existing.ProviderID, existing.ModelID)] = existing
 	}
 
 	for _, bp := range prices {
-		existing, exists := existingByModelID[bp.ModelID]
+		currency, err := pricing.NormalizeCurrency(bp.Currency)
+		if err == nil && !hasExchangeRate(ctx.exchangeRates, currency) {
+			err = fmt.Errorf("no exchange rate configured for %s", currency)
+		}
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("ModelPrice '%s' skipped: %v", bp.ModelID, err))
+			summary.Skipped++
+			continue
+		}
+		bp.Currency = currency
+
+		var providerID uint64
+		if bp.ProviderName != "" {
+			id, ok := ctx.providerNameToID[bp.ProviderName]
+			if !ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("ModelPrice '%s' skipped: provider '%s' not found", bp.ModelID, bp.ProviderName))
+				summary.Skipped++
+				continue
+			}
+			providerID = id
+		}
+		key := fmt.Sprintf("%d:%s", providerID, bp.ModelID)
+		existing, exists := existingByKey[key]
 		if exists {
 			switch opts.ConflictStrategy {
 			case "skip", "":
@@ -882,6 +1059,9 @@ func (s *BackupService) importModelPrices(prices []domain.BackupModelPrice, opts
 					ID:                     existing.ID,
 					CreatedAt:              existing.CreatedAt,
 					ModelID:                bp.ModelID,
+					ProviderID:             providerID,
+					Currency:               bp.Currency,
+					Source:                 bp.Source,
 					InputPriceMicro:        bp.InputPriceMicro,
 					OutputPriceMicro:       bp.OutputPriceMicro,
 					CacheReadPriceMicro:    bp.CacheReadPriceMicro,
@@ -907,6 +1087,9 @@ func (s *BackupService) importModelPrices(prices []domain.BackupModelPrice, opts
 
 		price := &domain.ModelPrice{
 			ModelID:                bp.ModelID,
+			ProviderID:             providerID,
+			Currency:               bp.Currency,
+			Source:                 bp.Source,
 			InputPriceMicro:        bp.InputPriceMicro,
 			OutputPriceMicro:       bp.OutputPriceMicro,
 			CacheReadPriceMicro:    bp.CacheReadPriceMicro,
@@ -924,7 +1107,7 @@ func (s *BackupService) importModelPrices(prices []domain.BackupModelPrice, opts
 				result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to import ModelPrice '%s': %v", bp.ModelID, err))
 				continue
 			}
-			existingByModelID[bp.ModelID] = price
+			existingByKey[key] = price
 		}
 		summary.Imported++
 	}
@@ -932,6 +1115,167 @@ func (s *BackupService) importModelPrices(prices []domain.BackupModelPrice, opts
 	result.Summary["modelPrices"] = summary
 }
 
+func (s *BackupService) importCooldownPolicies(policies []domain.BackupCooldownPolicy, opts domain.ImportOptions, result *domain.ImportResult, ctx *importContext) {
+	summary := domain.ImportSummary{}
+
+	existingPolicies, err := s.cooldownPolicyRepo.List()
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to load existing cooldown policies: %v", err))
+		result.Summary["cooldownPolicies"] = summary
+		return
+	}
+	// key: "providerID:reason"
+	existingByKey := make(map[string]*domain.CooldownPolicy, len(existingPolicies))
+	for _, existing := range existingPolicies {
+		existingByKey[fmt.Sprintf("%d:%s", existing.ProviderID, existing.Reason)] = existing
+	}
+
+	for _, bp := range policies {
+		config := domain.CooldownPolicyConfig{Type: bp.Type, BaseSeconds: bp.BaseSeconds, MaxSeconds: bp.MaxSeconds}
+		if !slices.Contains(domain.CooldownPolicyReasons, bp.Reason) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("CooldownPolicy '%s' skipped: unsupported reason", bp.Reason))
+			summary.Skipped++
+			continue
+		}
+		if err := config.Validate(); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("CooldownPolicy '%s' skipped: %v", bp.Reason, err))
+			summary.Skipped++
+			continue
+		}
+		var providerID uint64
+		if bp.ProviderName != "" {
+			id, ok := ctx.providerNameToID[bp.ProviderName]
+			if !ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("CooldownPolicy '%s' skipped: provider '%s' not found", bp.Reason, bp.ProviderName))
+				summary.Skipped++
+				continue
+			}
+			providerID = id
+		}
+		key := fmt.Sprintf("%d:%s", providerID, bp.Reason)
+		existing, exists := existingByKey[key]
+		if exists {
+			switch opts.ConflictStrategy {
+			case "skip", "":
+				summary.Skipped++
+				continue
+			case "error":
+				result.Success = false
+				result.Errors = append(result.Errors, fmt.Sprintf("CooldownPolicy conflict: reason '%s' already configured", bp.Reason))
+				return
+			case "overwrite":
+				updated := &domain.CooldownPolicy{
+					ID:                   existing.ID,
+					CreatedAt:            existing.CreatedAt,
+					ProviderID:           providerID,
+					Reason:               bp.Reason,
+					CooldownPolicyConfig: config,
+				}
+				if !opts.DryRun {
+					if err := s.cooldownPolicyRepo.Update(updated); err != nil {
+						result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to update CooldownPolicy '%s': %v", bp.Reason, err))
+						continue
+					}
+				}
+				summary.Updated++
+				continue
+			}
+		}
+
+		policy := &domain.CooldownPolicy{
+			ProviderID:           providerID,
+			Reason:               bp.Reason,
+			CooldownPolicyConfig: config,
+		}
+		if !opts.DryRun {
+			if err := s.cooldownPolicyRepo.Create(policy); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to import CooldownPolicy '%s': %v", bp.Reason, err))
+				continue
+			}
+			existingByKey[key] = policy
+		}
+		summary.Imported++
+	}
+
+	result.Summary["cooldownPolicies"] = summary
+}
+
+func (s *BackupService) importModelFallbacks(fallbacks []domain.BackupModelFallback, opts domain.ImportOptions, result *domain.ImportResult, ctx *importContext) {
+	summary := domain.ImportSummary{}
+
+	existingFallbacks, err := s.modelFallbackRepo.List()
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to load existing model fallbacks: %v", err))
+		result.Summary["modelFallbacks"] = summary
+		return
+	}
+	// key: "clientType:projectID:pattern"
+	existingByKey := make(map[string]*domain.ModelFallback, len(existingFallbacks))
+	for _, existing := range existingFallbacks {
+		existingByKey[fmt.Sprintf("%s:%d:%s", existing.ClientType, existing.ProjectID, existing.Pattern)] = existing
+	}
+
+	for _, bf := range fallbacks {
+		if bf.Pattern == "" || len(bf.Models) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("ModelFallback '%s' skipped: pattern and models are required", bf.Pattern))
+			summary.Skipped++
+			continue
+		}
+		var projectID uint64
+		if bf.ProjectSlug != "" {
+			id, ok := ctx.projectSlugToID[bf.ProjectSlug]
+			if !ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("ModelFallback '%s' skipped: project '%s' not found", bf.Pattern, bf.ProjectSlug))
+				summary.Skipped++
+				continue
+			}
+			projectID = id
+		}
+		key := fmt.Sprintf("%s:%d:%s", bf.ClientType, projectID, bf.Pattern)
+		fallback := &domain.ModelFallback{
+			ClientType:   bf.ClientType,
+			ProjectID:    projectID,
+			Pattern:      bf.Pattern,
+			Models:       bf.Models,
+			NotifyClient: bf.NotifyClient,
+			Priority:     bf.Priority,
+		}
+		if existing, exists := existingByKey[key]; exists {
+			switch opts.ConflictStrategy {
+			case "skip", "":
+				summary.Skipped++
+				continue
+			case "error":
+				result.Success = false
+				result.Errors = append(result.Errors, fmt.Sprintf("ModelFallback conflict: pattern '%s' already configured", bf.Pattern))
+				return
+			case "overwrite":
+				fallback.ID = existing.ID
+				fallback.CreatedAt = existing.CreatedAt
+				if !opts.DryRun {
+					if err := s.modelFallbackRepo.Update(fallback); err != nil {
+						result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to update ModelFallback '%s': %v", bf.Pattern, err))
+						continue
+					}
+				}
+				summary.Updated++
+				continue
+			}
+		}
+
+		if !opts.DryRun {
+			if err := s.modelFallbackRepo.Create(fallback); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to import ModelFallback '%s': %v", bf.Pattern, err))
+				continue
+			}
+			existingByKey[key] = fallback
+		}
+		summary.Imported++
+	}
+
+	result.Summary["modelFallbacks"] = summary
+}
+
 func buildRouteKey(providerName string, clientType domain.ClientType, projectSlug string) string {
 	return fmt.Sprintf("%s:%s:%s", providerName, clientType, projectSlug)
 }
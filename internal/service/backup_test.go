@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/pricing"
 	"github.com/awsl-project/maxx/internal/repository/sqlite"
 )
 
@@ -36,6 +37,9 @@ func newBackupServiceForTest(t *testing.T, db *sqlite.DB) *BackupService {
 		sqlite.NewAPITokenRepository(db),
 		sqlite.NewModelMappingRepository(db),
 		sqlite.NewModelPriceRepository(db),
+		sqlite.NewCooldownPolicyRepository(db),
+		sqlite.NewModelFallbackRepository(db),
+		nil,
 		nil,
 	)
 }
@@ -52,6 +56,8 @@ func seedBackupRoundtripData(t *testing.T, db *sqlite.DB) {
 	apiTokenRepo := sqlite.NewAPITokenRepository(db)
 	modelMappingRepo := sqlite.NewModelMappingRepository(db)
 	modelPriceRepo := sqlite.NewModelPriceRepository(db)
+	cooldownPolicyRepo := sqlite.NewCooldownPolicyRepository(db)
+	modelFallbackRepo := sqlite.NewModelFallbackRepository(db)
 
 	if err := settingRepo.Set("timezone", "UTC"); err != nil {
 		t.Fatalf("seed system setting: %v", err)
@@ -162,6 +168,30 @@ func seedBackupRoundtripData(t *testing.T, db *sqlite.DB) {
 	if err := modelPriceRepo.Create(modelPrice); err != nil {
 		t.Fatalf("seed model price: %v", err)
 	}
+
+	cooldownPolicy := &domain.CooldownPolicy{
+		ProviderID: provider.ID,
+		Reason:     domain.CooldownReasonServerError,
+		CooldownPolicyConfig: domain.CooldownPolicyConfig{
+			Type:        domain.CooldownPolicyExponential,
+			BaseSeconds: 30,
+			MaxSeconds:  600,
+		},
+	}
+	if err := cooldownPolicyRepo.Create(cooldownPolicy); err != nil {
+		t.Fatalf("seed cooldown policy: %v", err)
+	}
+
+	modelFallback := &domain.ModelFallback{
+		ClientType:   domain.ClientTypeClaude,
+		ProjectID:    project.ID,
+		Pattern:      "claude-opus-*",
+		Models:       []string{"claude-opus-4", "claude-sonnet-4-5"},
+		NotifyClient: true,
+	}
+	if err := modelFallbackRepo.Create(modelFallback); err != nil {
+		t.Fatalf("seed model fallback: %v", err)
+	}
 }
 
 func TestBackupService_ExportImportRoundtrip_PreservesCoreConfig(t *testing.T) {
@@ -205,6 +235,22 @@ func TestBackupService_ExportImportRoundtrip_PreservesCoreConfig(t *testing.T) {
 		t.Fatalf("model price not preserved: %+v", mp)
 	}
 
+	if len(roundtrip.Data.CooldownPolicies) != 1 {
+		t.Fatalf("cooldownPolicies count = %d, want 1", len(roundtrip.Data.CooldownPolicies))
+	}
+	cp := roundtrip.Data.CooldownPolicies[0]
+	if cp.ProviderName != roundtrip.Data.Providers[0].Name || cp.Type != domain.CooldownPolicyExponential || cp.MaxSeconds != 600 {
+		t.Fatalf("cooldown policy not preserved: %+v", cp)
+	}
+
+	if len(roundtrip.Data.ModelFallbacks) != 1 {
+		t.Fatalf("modelFallbacks count = %d, want 1", len(roundtrip.Data.ModelFallbacks))
+	}
+	mf := roundtrip.Data.ModelFallbacks[0]
+	if mf.ProjectSlug != roundtrip.Data.Projects[0].Slug || mf.Pattern != "claude-opus-*" || len(mf.Models) != 2 || !mf.NotifyClient {
+		t.Fatalf("model fallback not preserved: %+v", mf)
+	}
+
 	if len(roundtrip.Data.APITokens) != 1 {
 		t.Fatalf("apiTokens count = %d, want 1", len(roundtrip.Data.APITokens))
 	}
@@ -250,6 +296,83 @@ func TestBackupService_Import_ModelMappingsSkipDuplicates(t *testing.T) {
 	}
 }
 
+func TestBackupService_ExportImport_KeepsTokenRestrictedWhenProvidersDeleted(t *testing.T) {
+	sourceDB := newBackupServiceTestDB(t, "source.db")
+	apiTokenRepo := sqlite.NewAPITokenRepository(sourceDB)
+	token := &domain.APIToken{
+		Token:     "maxx_scoped_token",
+		Name:      "token-scoped",
+		IsEnabled: true,
+	}
+	token.AllowedProviderIDs = []uint64{42} // 该供应商已不存在
+	if err := apiTokenRepo.Create(token); err != nil {
+		t.Fatalf("seed api token: %v", err)
+	}
+
+	backup, err := newBackupServiceForTest(t, sourceDB).Export()
+	if err != nil {
+		t.Fatalf("export backup: %v", err)
+	}
+	if len(backup.Data.APITokens) != 1 || !backup.Data.APITokens[0].ProviderRestricted {
+		t.Fatalf("exported token lost its provider restriction: %+v", backup.Data.APITokens)
+	}
+
+	targetDB := newBackupServiceTestDB(t, "target.db")
+	if _, err := newBackupServiceForTest(t, targetDB).Import(backup, domain.ImportOptions{ConflictStrategy: "skip"}); err != nil {
+		t.Fatalf("import backup: %v", err)
+	}
+	imported, err := sqlite.NewAPITokenRepository(targetDB).List()
+	if err != nil {
+		t.Fatalf("list api tokens: %v", err)
+	}
+	if len(imported) != 1 || imported[0].IsEnabled {
+		t.Fatalf("restricted token must be imported disabled, got %+v", imported)
+	}
+}
+
+func TestBackupService_Import_RequiresExchangeRateForPrices(t *testing.T) {
+	t.Cleanup(func() { pricing.GlobalCalculator().SetExchangeRates(nil) })
+	pricing.GlobalCalculator().SetExchangeRates(nil)
+
+	backup := &domain.BackupFile{
+		Version: domain.BackupVersion,
+		Data: domain.BackupData{
+			ModelPrices: []domain.BackupModelPrice{{ModelID: "qwen-max", Currency: "cny", InputPriceMicro: 1000000}},
+		},
+	}
+
+	db := newBackupServiceTestDB(t, "rates.db")
+	svc := newBackupServiceForTest(t, db)
+	result, err := svc.Import(backup, domain.ImportOptions{ConflictStrategy: "skip"})
+	if err != nil {
+		t.Fatalf("import backup: %v", err)
+	}
+	if got := result.Summary["modelPrices"]; got.Imported != 0 || got.Skipped != 1 {
+		t.Fatalf("price without exchange rate should be skipped, got %+v", got)
+	}
+
+	// 备份中携带的汇率先于价格导入，价格随之生效
+	backup.Data.SystemSettings = []domain.BackupSystemSetting{{Key: domain.SettingKeyExchangeRates, Value: `{"CNY": 7.2}`}}
+	result, err = svc.Import(backup, domain.ImportOptions{ConflictStrategy: "skip"})
+	if err != nil {
+		t.Fatalf("import backup: %v", err)
+	}
+	if got := result.Summary["modelPrices"]; got.Imported != 1 {
+		t.Fatalf("price with exchange rate should be imported, got %+v", got)
+	}
+	if rate, ok := pricing.GlobalCalculator().ExchangeRate("CNY"); !ok || rate != 7.2 {
+		t.Fatalf("imported exchange rate not applied, got %v %v", rate, ok)
+	}
+
+	// 仍有 CNY 价格时不允许移除 CNY 汇率
+	if err := checkRatesCoverPrices(sqlite.NewModelPriceRepository(db), map[string]float64{}); err == nil {
+		t.Fatal("removing a rate still used by a price should be rejected")
+	}
+	if err := checkRatesCoverPrices(sqlite.NewModelPriceRepository(db), map[string]float64{"CNY": 7.1}); err != nil {
+		t.Fatalf("rates covering all prices should be accepted: %v", err)
+	}
+}
+
 func TestBuildModelMappingKey_NoSeparatorCollision(t *testing.T) {
 	left := domain.BackupModelMapping{
 		Scope:        domain.ModelMappingScopeGlobal,
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/awsl-project/maxx/internal/billing"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+// ErrBillingDisabled is returned when no billing rule repository is configured
+var ErrBillingDisabled = errors.New("billing rules are not available")
+
+// SetBillingRuleRepository enables billing rule management
+func (s *AdminService) SetBillingRuleRepository(repo repository.BillingRuleRepository) {
+	s.billingRuleRepo = repo
+}
+
+// ===== BillingRule API =====
+
+func (s *AdminService) GetBillingRules() ([]*domain.BillingRule, error) {
+	if s.billingRuleRepo == nil {
+		return nil, ErrBillingDisabled
+	}
+	return s.billingRuleRepo.List()
+}
+
+func (s *AdminService) GetBillingRule(id uint64) (*domain.BillingRule, error) {
+	if s.billingRuleRepo == nil {
+		return nil, ErrBillingDisabled
+	}
+	return s.billingRuleRepo.GetByID(id)
+}
+
+func (s *AdminService) CreateBillingRule(rule *domain.BillingRule) error {
+	if s.billingRuleRepo == nil {
+		return ErrBillingDisabled
+	}
+	rule.ID = 0
+	if err := s.validateBillingRule(rule); err != nil {
+		return err
+	}
+	if err := s.billingRuleRepo.Create(rule); err != nil {
+		return err
+	}
+	s.reloadBillingRules()
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityBillingRule, rule.ID, rule.Name, nil, rule)
+	return nil
+}
+
+func (s *AdminService) UpdateBillingRule(rule *domain.BillingRule) error {
+	if s.billingRuleRepo == nil {
+		return ErrBillingDisabled
+	}
+	before, err := s.billingRuleRepo.GetByID(rule.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validateBillingRule(rule); err != nil {
+		return err
+	}
+	rule.CreatedAt = before.CreatedAt
+	if err := s.billingRuleRepo.Update(rule); err != nil {
+		return err
+	}
+	// 作用对象或额度变化后重新推算免费额度用量
+	billing.Default().ResetFreeTier(rule.ID)
+	s.reloadBillingRules()
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityBillingRule, rule.ID, rule.Name, before, rule)
+	return nil
+}
+
+func (s *AdminService) DeleteBillingRule(id uint64) error {
+	if s.billingRuleRepo == nil {
+		return ErrBillingDisabled
+	}
+	before, err := s.billingRuleRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.billingRuleRepo.Delete(id); err != nil {
+		return err
+	}
+	billing.Default().ResetFreeTier(id)
+	s.reloadBillingRules()
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityBillingRule, id, before.Name, before, nil)
+	return nil
+}
+
+// validateBillingRule 校验作用对象存在，且同一项目/Token 只有一条规则
+func (s *AdminService) validateBillingRule(rule *domain.BillingRule) error {
+	if (rule.ProjectID == 0) == (rule.APITokenID == 0) {
+		return fmt.Errorf("%w: billing rule must target exactly one of projectID or apiTokenID", domain.ErrInvalidInput)
+	}
+	if rule.MarkupBps < -10000 {
+		return fmt.Errorf("%w: markupBps must be >= -10000", domain.ErrInvalidInput)
+	}
+	if rule.ProjectID > 0 {
+		if _, err := s.projectRepo.GetByID(rule.ProjectID); err != nil {
+			return fmt.Errorf("%w: project %d not found", domain.ErrInvalidInput, rule.ProjectID)
+		}
+	}
+	if rule.APITokenID > 0 {
+		if _, err := s.apiTokenRepo.GetByID(rule.APITokenID); err != nil {
+			return fmt.Errorf("%w: api token %d not found", domain.ErrInvalidInput, rule.APITokenID)
+		}
+	}
+
+	rules, err := s.billingRuleRepo.List()
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		if r.ID != rule.ID && r.ProjectID == rule.ProjectID && r.APITokenID == rule.APITokenID {
+			return fmt.Errorf("%w: billing rule %q already targets this %s", domain.ErrAlreadyExists, r.Name, billingRuleTarget(rule))
+		}
+	}
+	return nil
+}
+
+func billingRuleTarget(rule *domain.BillingRule) string {
+	if rule.APITokenID > 0 {
+		return "api token"
+	}
+	return "project"
+}
+
+func (s *AdminService) reloadBillingRules() {
+	if err := billing.Default().Reload(); err != nil {
+		log.Printf("[Billing] Failed to reload billing rules: %v", err)
+	}
+}
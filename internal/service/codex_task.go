@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	"log"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/awsl-project/maxx/internal/adapter/provider"
 	"github.com/awsl-project/maxx/internal/adapter/provider/codex"
 	"github.com/awsl-project/maxx/internal/domain"
 	"github.com/awsl-project/maxx/internal/event"
@@ -135,10 +137,16 @@ func (s *CodexTaskService) refreshAllQuotas(ctx context.Context) bool {
 			continue
 		}
 
+		client, err := oauthHTTPClient(provider.Config.Network)
+		if err != nil {
+			log.Printf("[CodexTask] Invalid network config for provider %d: %v", provider.ID, err)
+			continue
+		}
+
 		// Get or refresh access token
 		accessToken := config.AccessToken
 		if accessToken == "" || s.isTokenExpired(config.ExpiresAt) {
-			tokenResp, err := codex.RefreshAccessToken(ctx, config.RefreshToken)
+			tokenResp, err := codex.RefreshAccessToken(ctx, client, config.RefreshToken)
 			if err != nil {
 				log.Printf("[CodexTask] Failed to refresh token for provider %d: %v", provider.ID, err)
 				continue
@@ -155,7 +163,7 @@ func (s *CodexTaskService) refreshAllQuotas(ctx context.Context) bool {
 		}
 
 		// Fetch quota
-		usage, err := codex.FetchUsage(ctx, accessToken, config.AccountID)
+		usage, err := codex.FetchUsage(ctx, client, accessToken, config.AccountID)
 		if err != nil {
 			log.Printf("[CodexTask] Failed to fetch usage for provider %d: %v", provider.ID, err)
 			// Mark as forbidden if 403 error
@@ -480,3 +488,8 @@ func (s *CodexTaskService) getSortKey(quota *domain.CodexQuota) (*time.Time, *fl
 
 	return resetTime, remainingPercent
 }
+
+// oauthHTTPClient builds the token refresh / quota client for a provider's network settings
+func oauthHTTPClient(network *domain.ProviderConfigNetwork) (*http.Client, error) {
+	return provider.NewOAuthHTTPClient(network)
+}
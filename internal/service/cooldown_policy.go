@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/awsl-project/maxx/internal/cooldown"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+// ErrCooldownPoliciesDisabled is returned when no cooldown policy repository is configured
+var ErrCooldownPoliciesDisabled = errors.New("cooldown policies are not available")
+
+// SetCooldownPolicyRepository enables cooldown policy management
+func (s *AdminService) SetCooldownPolicyRepository(repo repository.CooldownPolicyRepository) {
+	s.cooldownPolicyRepo = repo
+}
+
+// ===== CooldownPolicy API =====
+
+func (s *AdminService) GetCooldownPolicies() ([]*domain.CooldownPolicy, error) {
+	if s.cooldownPolicyRepo == nil {
+		return nil, ErrCooldownPoliciesDisabled
+	}
+	return s.cooldownPolicyRepo.List()
+}
+
+func (s *AdminService) GetCooldownPolicy(id uint64) (*domain.CooldownPolicy, error) {
+	if s.cooldownPolicyRepo == nil {
+		return nil, ErrCooldownPoliciesDisabled
+	}
+	return s.cooldownPolicyRepo.GetByID(id)
+}
+
+func (s *AdminService) CreateCooldownPolicy(policy *domain.CooldownPolicy) error {
+	if s.cooldownPolicyRepo == nil {
+		return ErrCooldownPoliciesDisabled
+	}
+	policy.ID = 0
+	if err := s.validateCooldownPolicy(policy); err != nil {
+		return err
+	}
+	if err := s.cooldownPolicyRepo.Create(policy); err != nil {
+		return err
+	}
+	s.reloadCooldownPolicies()
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityCooldownPolicy, policy.ID, cooldownPolicyName(policy), nil, policy)
+	return nil
+}
+
+func (s *AdminService) UpdateCooldownPolicy(policy *domain.CooldownPolicy) error {
+	if s.cooldownPolicyRepo == nil {
+		return ErrCooldownPoliciesDisabled
+	}
+	before, err := s.cooldownPolicyRepo.GetByID(policy.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validateCooldownPolicy(policy); err != nil {
+		return err
+	}
+	policy.CreatedAt = before.CreatedAt
+	if err := s.cooldownPolicyRepo.Update(policy); err != nil {
+		return err
+	}
+	s.reloadCooldownPolicies()
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityCooldownPolicy, policy.ID, cooldownPolicyName(policy), before, policy)
+	return nil
+}
+
+func (s *AdminService) DeleteCooldownPolicy(id uint64) error {
+	if s.cooldownPolicyRepo == nil {
+		return ErrCooldownPoliciesDisabled
+	}
+	before, err := s.cooldownPolicyRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.cooldownPolicyRepo.Delete(id); err != nil {
+		return err
+	}
+	s.reloadCooldownPolicies()
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityCooldownPolicy, id, cooldownPolicyName(before), before, nil)
+	return nil
+}
+
+// deleteProviderCooldownPolicies 删除 Provider 时一并删除其冷却策略（Provider 软删除，策略不会再被引用）
+func (s *AdminService) deleteProviderCooldownPolicies(providerID uint64) {
+	if s.cooldownPolicyRepo == nil {
+		return
+	}
+	policies, err := s.cooldownPolicyRepo.List()
+	if err != nil {
+		log.Printf("[Cooldown] Failed to list cooldown policies of provider %d: %v", providerID, err)
+		return
+	}
+	deleted := false
+	for _, p := range policies {
+		if p.ProviderID != providerID {
+			continue
+		}
+		if err := s.cooldownPolicyRepo.Delete(p.ID); err != nil {
+			log.Printf("[Cooldown] Failed to delete cooldown policy %d: %v", p.ID, err)
+			continue
+		}
+		deleted = true
+		s.audit.record(domain.AuditActionDelete, domain.AuditEntityCooldownPolicy, p.ID, cooldownPolicyName(p), p, nil)
+	}
+	if deleted {
+		s.reloadCooldownPolicies()
+	}
+}
+
+// validateCooldownPolicy 校验原因与参数合法、Provider 存在，且同一作用范围内每个原因只有一条策略
+func (s *AdminService) validateCooldownPolicy(policy *domain.CooldownPolicy) error {
+	if !slices.Contains(domain.CooldownPolicyReasons, policy.Reason) {
+		return fmt.Errorf("%w: unsupported cooldown reason %q", domain.ErrInvalidInput, policy.Reason)
+	}
+	if err := policy.CooldownPolicyConfig.Validate(); err != nil {
+		return err
+	}
+	if policy.ProviderID > 0 {
+		if _, err := s.providerRepo.GetByID(policy.ProviderID); err != nil {
+			return fmt.Errorf("%w: provider %d not found", domain.ErrInvalidInput, policy.ProviderID)
+		}
+	}
+
+	policies, err := s.cooldownPolicyRepo.List()
+	if err != nil {
+		return err
+	}
+	for _, p := range policies {
+		if p.ID != policy.ID && p.ProviderID == policy.ProviderID && p.Reason == policy.Reason {
+			return fmt.Errorf("%w: %s already has a cooldown policy", domain.ErrAlreadyExists, cooldownPolicyName(policy))
+		}
+	}
+	return nil
+}
+
+func cooldownPolicyName(policy *domain.CooldownPolicy) string {
+	if policy.ProviderID == 0 {
+		return "global/" + string(policy.Reason)
+	}
+	return fmt.Sprintf("provider %d/%s", policy.ProviderID, policy.Reason)
+}
+
+func (s *AdminService) reloadCooldownPolicies() {
+	if err := cooldown.Default().ReloadPolicies(); err != nil {
+		log.Printf("[Cooldown] Failed to reload cooldown policies: %v", err)
+	}
+}
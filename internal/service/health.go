@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/health"
+)
+
+// SetHealthChecker enables provider health probes via the admin API
+func (s *AdminService) SetHealthChecker(c *health.Checker) {
+	s.healthChecker = c
+}
+
+// GetProviderHealth returns the current health state of every probed provider / client type
+// 状态只保存在内存中，重启后随下一轮探测重建
+func (s *AdminService) GetProviderHealth() []*domain.ProviderHealthStatus {
+	if s.healthChecker == nil {
+		return []*domain.ProviderHealthStatus{}
+	}
+	return s.healthChecker.Statuses()
+}
+
+// GetProviderHealthHistory returns the recorded probe results of a provider, newest first
+func (s *AdminService) GetProviderHealthHistory(providerID uint64, clientType domain.ClientType, limit int) ([]*domain.ProviderHealthCheck, error) {
+	if s.healthChecker == nil {
+		return []*domain.ProviderHealthCheck{}, nil
+	}
+	return s.healthChecker.History(providerID, clientType, limit)
+}
+
+// CheckProviderHealth probes a provider immediately
+func (s *AdminService) CheckProviderHealth(ctx context.Context, providerID uint64) ([]*domain.ProviderHealthCheck, error) {
+	if s.healthChecker == nil {
+		return nil, domain.ErrNotFound
+	}
+	return s.healthChecker.CheckProvider(ctx, providerID)
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+// ErrModelFallbacksDisabled is returned when no model fallback repository is configured
+var ErrModelFallbacksDisabled = errors.New("model fallbacks are not available")
+
+// SetModelFallbackRepository enables model fallback chain management
+func (s *AdminService) SetModelFallbackRepository(repo repository.ModelFallbackRepository) {
+	s.modelFallbackRepo = repo
+}
+
+// ===== ModelFallback API =====
+
+func (s *AdminService) GetModelFallbacks() ([]*domain.ModelFallback, error) {
+	if s.modelFallbackRepo == nil {
+		return nil, ErrModelFallbacksDisabled
+	}
+	return s.modelFallbackRepo.List()
+}
+
+func (s *AdminService) GetModelFallback(id uint64) (*domain.ModelFallback, error) {
+	if s.modelFallbackRepo == nil {
+		return nil, ErrModelFallbacksDisabled
+	}
+	return s.modelFallbackRepo.GetByID(id)
+}
+
+func (s *AdminService) CreateModelFallback(fallback *domain.ModelFallback) error {
+	if s.modelFallbackRepo == nil {
+		return ErrModelFallbacksDisabled
+	}
+	fallback.ID = 0
+	if err := s.validateModelFallback(fallback); err != nil {
+		return err
+	}
+	if err := s.modelFallbackRepo.Create(fallback); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionCreate, domain.AuditEntityModelFallback, fallback.ID, fallback.Pattern, nil, fallback)
+	return nil
+}
+
+func (s *AdminService) UpdateModelFallback(fallback *domain.ModelFallback) error {
+	if s.modelFallbackRepo == nil {
+		return ErrModelFallbacksDisabled
+	}
+	before, err := s.modelFallbackRepo.GetByID(fallback.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validateModelFallback(fallback); err != nil {
+		return err
+	}
+	fallback.CreatedAt = before.CreatedAt
+	if err := s.modelFallbackRepo.Update(fallback); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionUpdate, domain.AuditEntityModelFallback, fallback.ID, fallback.Pattern, before, fallback)
+	return nil
+}
+
+func (s *AdminService) DeleteModelFallback(id uint64) error {
+	if s.modelFallbackRepo == nil {
+		return ErrModelFallbacksDisabled
+	}
+	before, err := s.modelFallbackRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.modelFallbackRepo.Delete(id); err != nil {
+		return err
+	}
+	s.audit.record(domain.AuditActionDelete, domain.AuditEntityModelFallback, id, before.Pattern, before, nil)
+	return nil
+}
+
+// validateModelFallback 校验匹配模式与降级模型列表，并去除空白和重复的模型
+func (s *AdminService) validateModelFallback(fallback *domain.ModelFallback) error {
+	fallback.Pattern = strings.TrimSpace(fallback.Pattern)
+	if fallback.Pattern == "" {
+		return fmt.Errorf("%w: pattern is required", domain.ErrInvalidInput)
+	}
+
+	models := make([]string, 0, len(fallback.Models))
+	seen := make(map[string]bool, len(fallback.Models))
+	for _, m := range fallback.Models {
+		m = strings.TrimSpace(m)
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		models = append(models, m)
+	}
+	if len(models) == 0 {
+		return fmt.Errorf("%w: at least one fallback model is required", domain.ErrInvalidInput)
+	}
+	fallback.Models = models
+
+	if fallback.ProjectID > 0 {
+		if _, err := s.projectRepo.GetByID(fallback.ProjectID); err != nil {
+			return fmt.Errorf("%w: project %d not found", domain.ErrInvalidInput, fallback.ProjectID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/pricing"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+// ModelPriceImportResult is the outcome of a price sheet import
+type ModelPriceImportResult struct {
+	Format    pricing.SheetFormat  `json:"format"`
+	DryRun    bool                 `json:"dryRun"`
+	Created   []*domain.ModelPrice `json:"created"`   // 新建的价格版本
+	Unchanged int                  `json:"unchanged"` // 与当前价格一致，未创建新版本
+	Skipped   []pricing.SheetEntry `json:"skipped"`
+}
+
+// ImportModelPrices parses a price sheet and creates a new ModelPrice version for
+// every model whose price differs from the current one. Existing versions are
+// kept so historical requests still reference the price they were billed with.
+func (s *AdminService) ImportModelPrices(data []byte, opts pricing.SheetOptions, dryRun bool) (*ModelPriceImportResult, error) {
+	if opts.ProviderID > 0 {
+		if _, err := s.providerRepo.GetByID(opts.ProviderID); err != nil {
+			return nil, fmt.Errorf("provider %d not found", opts.ProviderID)
+		}
+	}
+	sheet, err := pricing.ParseSheet(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.modelPriceRepo.ListCurrentPrices()
+	if err != nil {
+		return nil, err
+	}
+	currentByModel := make(map[string]*domain.ModelPrice)
+	for _, p := range current {
+		if p.ProviderID == opts.ProviderID {
+			currentByModel[p.ModelID] = p
+		}
+	}
+
+	result := &ModelPriceImportResult{Format: sheet.Format, DryRun: dryRun, Created: []*domain.ModelPrice{}, Skipped: sheet.Skipped}
+	for _, p := range sheet.Prices {
+		if pricing.SamePrice(currentByModel[p.ModelID], p) {
+			result.Unchanged++
+			continue
+		}
+		if err := checkExchangeRate(p.Currency); err != nil {
+			return nil, fmt.Errorf("model %s: %w", p.ModelID, err)
+		}
+		result.Created = append(result.Created, p)
+	}
+	if dryRun || len(result.Created) == 0 {
+		return result, nil
+	}
+
+	if err := s.modelPriceRepo.BatchCreate(result.Created); err != nil {
+		return nil, err
+	}
+	s.audit.record(domain.AuditActionImport, domain.AuditEntityModelPrice, "import", string(sheet.Format), nil, map[string]any{
+		"providerId": opts.ProviderID,
+		"created":    result.Created,
+		"unchanged":  result.Unchanged,
+	})
+	return result, nil
+}
+
+// normalizeModelPrice validates the currency and provider of a manually edited price
+func (s *AdminService) normalizeModelPrice(price *domain.ModelPrice) error {
+	currency, err := pricing.NormalizeCurrency(price.Currency)
+	if err != nil {
+		return err
+	}
+	if err := checkExchangeRate(currency); err != nil {
+		return err
+	}
+	price.Currency = currency
+	if price.ProviderID > 0 {
+		if _, err := s.providerRepo.GetByID(price.ProviderID); err != nil {
+			return fmt.Errorf("provider %d not found", price.ProviderID)
+		}
+	}
+	if price.Source == "" {
+		price.Source = domain.PriceSourceManual
+	}
+	return nil
+}
+
+// checkExchangeRate rejects currencies without a configured rate, whose cost would otherwise be recorded as 0
+func checkExchangeRate(currency string) error {
+	if _, ok := pricing.GlobalCalculator().ExchangeRate(currency); !ok {
+		return fmt.Errorf("no exchange rate configured for %s, set %q first", currency, domain.SettingKeyExchangeRates)
+	}
+	return nil
+}
+
+// hasExchangeRate reports whether prices in the currency can be converted with the given rates
+func hasExchangeRate(rates map[string]float64, currency string) bool {
+	currency = strings.ToUpper(currency)
+	return currency == "" || currency == domain.CurrencyUSD || rates[currency] > 0
+}
+
+// checkRatesCoverPrices rejects exchange rates that drop a currency still used by a current model price
+func checkRatesCoverPrices(repo repository.ModelPriceRepository, rates map[string]float64) error {
+	prices, err := repo.ListCurrentPrices()
+	if err != nil {
+		return err
+	}
+	for _, p := range prices {
+		if !hasExchangeRate(rates, p.Currency) {
+			return fmt.Errorf("%w: exchange rate for %s is still used by the price of %s", domain.ErrInvalidInput, p.Currency, p.ModelID)
+		}
+	}
+	return nil
+}
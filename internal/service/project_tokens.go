@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+)
+
+// ===== Project Self-Service Token API =====
+// 项目管理员 Token 可以在不具备管理后台权限的情况下管理所属项目的 Token
+
+// ListProjectAPITokens returns the tokens bound to a project
+func (s *AdminService) ListProjectAPITokens(projectID uint64) ([]*domain.APIToken, error) {
+	tokens, err := s.apiTokenRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*domain.APIToken, 0)
+	for _, t := range tokens {
+		if t.ProjectID == projectID {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// CreateProjectAPIToken mints a token for the admin token's project.
+// The new token is confined to the admin token's scope and can never be a project admin itself.
+func (s *AdminService) CreateProjectAPIToken(admin *domain.APIToken, name, description string, expiresAt *time.Time, scope domain.APITokenScope) (*domain.APITokenCreateResult, error) {
+	scope.ProjectAdmin = false
+	restricted, ok := admin.Restrict(scope)
+	if !ok {
+		return nil, fmt.Errorf("%w: requested scope exceeds the project admin token scope", domain.ErrInvalidInput)
+	}
+
+	// Derived tokens never outlive the admin token
+	if admin.ExpiresAt != nil && (expiresAt == nil || expiresAt.After(*admin.ExpiresAt)) {
+		expiresAt = admin.ExpiresAt
+	}
+
+	return s.CreateAPIToken(name, description, admin.ProjectID, expiresAt, "", restricted)
+}
+
+// RevokeProjectAPIToken deletes a token of the admin token's project.
+// Project admin tokens are managed by full admins only.
+func (s *AdminService) RevokeProjectAPIToken(admin *domain.APIToken, id uint64) error {
+	token, err := s.apiTokenRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if token.ProjectID != admin.ProjectID {
+		return domain.ErrNotFound
+	}
+	if token.ProjectAdmin {
+		return fmt.Errorf("%w: project admin tokens can only be revoked by an administrator", domain.ErrInvalidInput)
+	}
+	return s.DeleteAPIToken(id)
+}
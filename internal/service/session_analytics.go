@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/conversation"
+	"github.com/awsl-project/maxx/internal/detailstore"
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+const maxSessionTimelineLimit = 500
+
+// SessionTurn is one request of a session with its normalized conversation delta
+type SessionTurn struct {
+	ProxyRequestID   uint64            `json:"proxyRequestID"`
+	StartTime        time.Time         `json:"startTime"`
+	ClientType       domain.ClientType `json:"clientType"`
+	RequestModel     string            `json:"requestModel"`
+	ResponseModel    string            `json:"responseModel"`
+	ProviderID       uint64            `json:"providerID"`
+	Status           string            `json:"status"`
+	StatusCode       int               `json:"statusCode"`
+	Duration         time.Duration     `json:"duration"`
+	InputTokenCount  uint64            `json:"inputTokenCount"`
+	OutputTokenCount uint64            `json:"outputTokenCount"`
+	Cost             uint64            `json:"cost"`
+	Error            string            `json:"error,omitempty"`
+
+	// System is only set on the first turn of a page and when it changes
+	System string `json:"system,omitempty"`
+	// Input holds the messages added since the last assistant message
+	Input  []conversation.Message `json:"input"`
+	Output []conversation.Message `json:"output"`
+	// ParseError explains why the details could not be reconstructed (cleaned up, truncated...)
+	ParseError string `json:"parseError,omitempty"`
+}
+
+// SessionTimeline is a page of turns ordered from oldest to newest
+type SessionTimeline struct {
+	SessionID string         `json:"sessionID"`
+	Turns     []*SessionTurn `json:"turns"`
+	HasMore   bool           `json:"hasMore"`
+	LastID    uint64         `json:"lastId,omitempty"`
+}
+
+// GetSessionStatsList returns per-session aggregates, most recently active first
+func (s *AdminService) GetSessionStatsList(filter *repository.SessionStatsFilter, limit, offset int) ([]*domain.SessionStats, error) {
+	return s.proxyRequestRepo.ListSessionStats(filter, limit, offset)
+}
+
+// GetSessionStats returns the aggregates of a single session
+func (s *AdminService) GetSessionStats(sessionID string) (*domain.SessionStats, error) {
+	stats, err := s.proxyRequestRepo.ListSessionStats(&repository.SessionStatsFilter{SessionID: &sessionID}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return stats[0], nil
+}
+
+// GetSessionTimeline reconstructs the conversation of a session from stored request details
+func (s *AdminService) GetSessionTimeline(sessionID string, after uint64, limit int) (*SessionTimeline, error) {
+	if limit <= 0 || limit > maxSessionTimelineLimit {
+		limit = maxSessionTimelineLimit
+	}
+	// 多取一条用于判断 hasMore
+	requests, err := s.proxyRequestRepo.ListBySessionID(sessionID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &SessionTimeline{SessionID: sessionID, Turns: []*SessionTurn{}}
+	if len(requests) > limit {
+		requests = requests[:limit]
+		timeline.HasMore = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	lastSystem := ""
+	for i, req := range requests {
+		detailstore.LoadProxyRequest(ctx, s.detailStore, req)
+		turn := buildSessionTurn(req)
+		if i > 0 && turn.System == lastSystem {
+			lastSystem, turn.System = turn.System, ""
+		} else {
+			lastSystem = turn.System
+		}
+		timeline.Turns = append(timeline.Turns, turn)
+		timeline.LastID = req.ID
+	}
+	return timeline, nil
+}
+
+func buildSessionTurn(req *domain.ProxyRequest) *SessionTurn {
+	turn := &SessionTurn{
+		ProxyRequestID:   req.ID,
+		StartTime:        req.StartTime,
+		ClientType:       req.ClientType,
+		RequestModel:     req.RequestModel,
+		ResponseModel:    req.ResponseModel,
+		ProviderID:       req.ProviderID,
+		Status:           req.Status,
+		StatusCode:       req.StatusCode,
+		Duration:         req.Duration,
+		InputTokenCount:  req.InputTokenCount,
+		OutputTokenCount: req.OutputTokenCount,
+		Cost:             req.Cost,
+		Error:            req.Error,
+		Input:            []conversation.Message{},
+		Output:           []conversation.Message{},
+	}
+
+	if req.RequestInfo == nil || req.RequestInfo.Body == "" {
+		turn.ParseError = "request detail not available"
+		return turn
+	}
+	parsed, err := conversation.ParseRequest(req.ClientType, req.RequestInfo.Body)
+	if err != nil {
+		turn.ParseError = "request: " + err.Error()
+		return turn
+	}
+	turn.System = parsed.System
+	turn.Input = conversation.NewMessages(parsed.Messages)
+
+	if req.ResponseInfo == nil || req.ResponseInfo.Body == "" {
+		return turn
+	}
+	output, err := conversation.ParseResponse(req.ClientType, req.ResponseInfo.Body)
+	if err != nil {
+		turn.ParseError = "response: " + err.Error()
+		return turn
+	}
+	if output != nil {
+		turn.Output = output
+	}
+	return turn
+}
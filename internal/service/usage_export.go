@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/export"
+	"github.com/awsl-project/maxx/internal/report"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+// ErrReportsDisabled is returned when no report generator is configured
+var ErrReportsDisabled = errors.New("billing reports are not available")
+
+// SetReportGenerator enables manually triggered billing reports
+func (s *AdminService) SetReportGenerator(g *report.Generator) {
+	s.reportGenerator = g
+}
+
+// ExportUsageStats streams the pre-aggregated usage stats matching the filter.
+// The current (still aggregating) time bucket may be incomplete.
+func (s *AdminService) ExportUsageStats(w export.Writer, filter repository.UsageStatsFilter) error {
+	names, err := export.LoadNames(s.providerRepo, s.projectRepo, s.apiTokenRepo)
+	if err != nil {
+		return err
+	}
+	return s.usageStatsRepo.Iterate(filter, func(stats *domain.UsageStats) error {
+		return w.Write(export.UsageStatsRow(stats, names))
+	})
+}
+
+// ExportProxyRequests streams request records (without bodies) matching the filter
+func (s *AdminService) ExportProxyRequests(w export.Writer, filter *repository.ProxyRequestFilter) error {
+	names, err := export.LoadNames(s.providerRepo, s.projectRepo, s.apiTokenRepo)
+	if err != nil {
+		return err
+	}
+	return s.proxyRequestRepo.Iterate(filter, func(req *domain.ProxyRequest) error {
+		return w.Write(export.ProxyRequestRow(req, names))
+	})
+}
+
+// RunBillingReport generates and delivers the reports of the last completed period
+// immediately, using the configured schedule unless one is given
+func (s *AdminService) RunBillingReport(schedule string) (*report.Result, error) {
+	if s.reportGenerator == nil {
+		return nil, ErrReportsDisabled
+	}
+	cfg, err := report.LoadConfig(s.settingRepo)
+	if err != nil {
+		return nil, err
+	}
+	if schedule != "" {
+		if cfg.Schedule, err = report.ParseSchedule(schedule); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Schedule == "" {
+		cfg.Schedule = report.ScheduleMonthly
+	}
+	period := report.PreviousPeriod(cfg.Schedule, time.Now(), s.reportGenerator.Location())
+	return s.reportGenerator.Run(cfg, period)
+}
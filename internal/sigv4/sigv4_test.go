@@ -0,0 +1,62 @@
+package sigv4
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+var testSigner = Signer{
+	Credentials: Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	},
+	Region:  "us-east-1",
+	Service: "service",
+}
+
+// Vectors from the AWS SigV4 test suite
+func TestSign_TestSuite(t *testing.T) {
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			"get-vanilla",
+			"https://example.amazonaws.com/",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			"get-vanilla-query-order-key-case",
+			"https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			testSigner.Sign(req, nil, now)
+			if got := req.Header.Get("Authorization"); got != tt.want {
+				t.Fatalf("Authorization =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalURI_KeepsEscapedPath(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPut, "https://s3.example.com/bucket/requests/0/1.request%3Av1.zst", nil)
+	if got := testSigner.canonicalURI(req.URL); got != "/bucket/requests/0/1.request%3Av1.zst" {
+		t.Fatalf("canonicalURI = %s", got)
+	}
+}
+
+func TestCanonicalURI_EscapePathTwice(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://bedrock-runtime.us-east-1.amazonaws.com/model/anthropic.claude-v2%3A1/invoke", nil)
+	signer := testSigner
+	signer.EscapePathTwice = true
+	if got := signer.canonicalURI(req.URL); got != "/model/anthropic.claude-v2%253A1/invoke" {
+		t.Fatalf("canonicalURI = %s", got)
+	}
+}
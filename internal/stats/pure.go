@@ -28,6 +28,8 @@ type AttemptRecord struct {
 	CacheRead    uint64
 	CacheWrite   uint64
 	Cost         uint64
+	UpstreamCost uint64
+	BilledCost   uint64
 }
 
 // TruncateToGranularity truncates a time to the start of its time bucket
@@ -100,6 +102,8 @@ func AggregateAttempts(records []AttemptRecord, loc *time.Location) []*domain.Us
 			s.CacheRead += r.CacheRead
 			s.CacheWrite += r.CacheWrite
 			s.Cost += r.Cost
+			s.UpstreamCost += r.UpstreamCost
+			s.BilledCost += r.BilledCost
 		} else {
 			statsMap[key] = &domain.UsageStats{
 				Granularity:        domain.GranularityMinute,
@@ -120,6 +124,8 @@ func AggregateAttempts(records []AttemptRecord, loc *time.Location) []*domain.Us
 				CacheRead:          r.CacheRead,
 				CacheWrite:         r.CacheWrite,
 				Cost:               r.Cost,
+				UpstreamCost:       r.UpstreamCost,
+				BilledCost:         r.BilledCost,
 			}
 		}
 	}
@@ -174,6 +180,8 @@ func RollUp(stats []*domain.UsageStats, to domain.Granularity, loc *time.Locatio
 			existing.CacheRead += s.CacheRead
 			existing.CacheWrite += s.CacheWrite
 			existing.Cost += s.Cost
+			existing.UpstreamCost += s.UpstreamCost
+			existing.BilledCost += s.BilledCost
 		} else {
 			statsMap[key] = &domain.UsageStats{
 				Granularity:        to,
@@ -194,6 +202,8 @@ func RollUp(stats []*domain.UsageStats, to domain.Granularity, loc *time.Locatio
 				CacheRead:          s.CacheRead,
 				CacheWrite:         s.CacheWrite,
 				Cost:               s.Cost,
+				UpstreamCost:       s.UpstreamCost,
+				BilledCost:         s.BilledCost,
 			}
 		}
 	}
@@ -244,6 +254,8 @@ func MergeStats(statsList ...[]*domain.UsageStats) []*domain.UsageStats {
 				existing.CacheRead += s.CacheRead
 				existing.CacheWrite += s.CacheWrite
 				existing.Cost += s.Cost
+				existing.UpstreamCost += s.UpstreamCost
+				existing.BilledCost += s.BilledCost
 			} else {
 				// Make a copy to avoid modifying the original
 				copied := *s
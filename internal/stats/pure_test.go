@@ -234,6 +234,8 @@ func TestAggregateAttempts_SameMinute(t *testing.T) {
 			InputTokens:  100,
 			OutputTokens: 50,
 			Cost:         1000,
+			UpstreamCost: 800,
+			BilledCost:   1200,
 		},
 		{
 			EndTime:      baseTime.Add(20 * time.Second),
@@ -243,6 +245,8 @@ func TestAggregateAttempts_SameMinute(t *testing.T) {
 			InputTokens:  200,
 			OutputTokens: 100,
 			Cost:         2000,
+			UpstreamCost: 1600,
+			BilledCost:   2400,
 		},
 		{
 			EndTime:    baseTime.Add(30 * time.Second),
@@ -278,6 +282,9 @@ func TestAggregateAttempts_SameMinute(t *testing.T) {
 	if s.Cost != 3000 {
 		t.Errorf("Cost = %d, want 3000", s.Cost)
 	}
+	if s.UpstreamCost != 2400 || s.BilledCost != 3600 {
+		t.Errorf("UpstreamCost/BilledCost = %d/%d, want 2400/3600", s.UpstreamCost, s.BilledCost)
+	}
 }
 
 func TestAggregateAttempts_DifferentMinutes(t *testing.T) {
@@ -5,8 +5,6 @@ package usage
 import (
 	"encoding/json"
 	"strings"
-
-	"github.com/awsl-project/maxx/internal/domain"
 )
 
 // Metrics represents extracted usage information from an API response.
@@ -124,6 +122,10 @@ func extractFromSSE(body string) *Metrics {
 func extractUsageFromMap(data map[string]interface{}) *Metrics {
 	// Try Claude/Anthropic format: { "usage": { ... } }
 	if usage, ok := data["usage"].(map[string]interface{}); ok {
+		// OpenAI Chat Completions / Response API usage shares the "usage" key
+		if isOpenAIUsage(usage) {
+			return extractOpenAIUsage(usage)
+		}
 		return extractClaudeUsage(usage)
 	}
 
@@ -203,10 +205,22 @@ func extractClaudeUsage(usage map[string]interface{}) *Metrics {
 	return metrics
 }
 
+// isOpenAIUsage reports whether a root-level "usage" object uses OpenAI field names
+func isOpenAIUsage(usage map[string]interface{}) bool {
+	for _, key := range []string{"prompt_tokens", "completion_tokens", "input_tokens_details", "prompt_tokens_details"} {
+		if _, ok := usage[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // extractOpenAIUsage extracts metrics from OpenAI usage format.
 // Supports both standard OpenAI format and Codex/Response API format:
 // - Standard: { "prompt_tokens": 100, "completion_tokens": 50, "total_tokens": 150 }
 // - Response API: { "input_tokens": 100, "output_tokens": 50, "input_tokens_details": {...} }
+// OpenAI counts cached tokens inside prompt/input tokens; they are subtracted so that
+// InputTokens only holds uncached tokens, as for Claude.
 func extractOpenAIUsage(usage map[string]interface{}) *Metrics {
 	metrics := &Metrics{}
 
@@ -230,21 +244,27 @@ func extractOpenAIUsage(usage map[string]interface{}) *Metrics {
 		metrics.OutputTokens = uint64(v)
 	}
 
-	// OpenAI Response API format: prompt_tokens_details.cached_tokens
+	// OpenAI Chat Completions format: prompt_tokens_details.cached_tokens
 	if details, ok := usage["prompt_tokens_details"].(map[string]interface{}); ok {
 		if v, ok := details["cached_tokens"].(float64); ok {
 			metrics.CacheReadCount = uint64(v)
 		}
 	}
 
-	// Alternative: input_tokens_details (Codex format)
+	// Alternative: input_tokens_details (Codex / Response API format)
 	if details, ok := usage["input_tokens_details"].(map[string]interface{}); ok {
 		if v, ok := details["cached_tokens"].(float64); ok {
 			metrics.CacheReadCount = uint64(v)
 		}
 	}
 
-	// Also check top-level cache_read_input_tokens (some relays use this)
+	// Cached tokens are part of the prompt: subtract to avoid billing them twice
+	if metrics.CacheReadCount > 0 && metrics.InputTokens >= metrics.CacheReadCount {
+		metrics.InputTokens -= metrics.CacheReadCount
+	}
+
+	// Also check top-level cache_read_input_tokens (some relays use this, Claude-style
+	// where input tokens already exclude the cache)
 	if v, ok := usage["cache_read_input_tokens"].(float64); ok {
 		metrics.CacheReadCount = uint64(v)
 	}
@@ -294,22 +314,3 @@ func extractGeminiUsage(usage map[string]interface{}) *Metrics {
 func ExtractFromStreamContent(content string) *Metrics {
 	return extractFromSSE(content)
 }
-
-// AdjustForClientType adjusts metrics based on client type specific quirks.
-// For Codex: input_tokens includes cached_tokens, so we subtract to avoid double counting.
-// For other clients: returns metrics unchanged.
-func AdjustForClientType(metrics *Metrics, clientType domain.ClientType) *Metrics {
-	if metrics == nil {
-		return nil
-	}
-
-	// Codex/OpenAI Response API: input_tokens includes cached_tokens
-	// We need to subtract to get actual input tokens (avoiding double billing)
-	if clientType == domain.ClientTypeCodex {
-		if metrics.CacheReadCount > 0 && metrics.InputTokens >= metrics.CacheReadCount {
-			metrics.InputTokens = metrics.InputTokens - metrics.CacheReadCount
-		}
-	}
-
-	return metrics
-}
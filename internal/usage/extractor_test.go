@@ -0,0 +1,68 @@
+package usage
+
+import "testing"
+
+func TestExtractFromResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want Metrics
+	}{
+		{
+			name: "claude message",
+			body: `{"type":"message","usage":{"input_tokens":100,"output_tokens":50,"cache_read_input_tokens":20,"cache_creation_input_tokens":30,"cache_creation_5m_input_tokens":10,"cache_creation_1h_input_tokens":20}}`,
+			want: Metrics{InputTokens: 100, OutputTokens: 50, CacheReadCount: 20, CacheCreationCount: 30, Cache5mCreationCount: 10, Cache1hCreationCount: 20},
+		},
+		{
+			name: "claude stream",
+			body: "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"usage\":{\"input_tokens\":100,\"output_tokens\":1,\"cache_read_input_tokens\":20}}}\n\n" +
+				"event: message_delta\ndata: {\"type\":\"message_delta\",\"usage\":{\"input_tokens\":100,\"output_tokens\":50,\"cache_read_input_tokens\":20}}\n\n",
+			want: Metrics{InputTokens: 100, OutputTokens: 50, CacheReadCount: 20},
+		},
+		{
+			name: "openai chat completions",
+			body: `{"object":"chat.completion","choices":[],"usage":{"prompt_tokens":100,"completion_tokens":50,"total_tokens":150,"prompt_tokens_details":{"cached_tokens":80}}}`,
+			want: Metrics{InputTokens: 20, OutputTokens: 50, CacheReadCount: 80},
+		},
+		{
+			name: "openai chat completions stream",
+			body: "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n" +
+				"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":100,\"completion_tokens\":50,\"prompt_tokens_details\":{\"cached_tokens\":80}}}\n\n" +
+				"data: [DONE]\n\n",
+			want: Metrics{InputTokens: 20, OutputTokens: 50, CacheReadCount: 80},
+		},
+		{
+			name: "openai responses",
+			body: `{"object":"response","usage":{"input_tokens":100,"output_tokens":50,"input_tokens_details":{"cached_tokens":80}}}`,
+			want: Metrics{InputTokens: 20, OutputTokens: 50, CacheReadCount: 80},
+		},
+		{
+			name: "openai responses stream",
+			body: "event: response.created\ndata: {\"type\":\"response.created\",\"response\":{}}\n\n" +
+				"event: response.completed\ndata: {\"type\":\"response.completed\",\"response\":{\"usage\":{\"input_tokens\":100,\"output_tokens\":50,\"input_tokens_details\":{\"cached_tokens\":80}}}}\n\n",
+			want: Metrics{InputTokens: 20, OutputTokens: 50, CacheReadCount: 80},
+		},
+		{
+			name: "gemini",
+			body: `{"candidates":[],"usageMetadata":{"promptTokenCount":100,"candidatesTokenCount":40,"thoughtsTokenCount":10,"cachedContentTokenCount":80}}`,
+			want: Metrics{InputTokens: 20, OutputTokens: 50, CacheReadCount: 80},
+		},
+		{
+			name: "gemini v1internal stream",
+			body: "data: {\"response\":{\"usageMetadata\":{\"promptTokenCount\":100,\"candidatesTokenCount\":50,\"cachedContentTokenCount\":80}}}\n\n",
+			want: Metrics{InputTokens: 20, OutputTokens: 50, CacheReadCount: 80},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractFromResponse(tt.body)
+			if got == nil {
+				t.Fatal("no usage extracted")
+			}
+			if *got != tt.want {
+				t.Fatalf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package waiter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/awsl-project/maxx/internal/domain"
+	"github.com/awsl-project/maxx/internal/repository"
+)
+
+var ErrSessionLoopDetected = errors.New("session loop detected")
+
+// 循环检测触发原因
+const (
+	LoopReasonRepetition  = "repetition"
+	LoopReasonTokenLimit  = "token_limit"
+	LoopReasonCostLimit   = "cost_limit"
+	LoopActionReject      = "reject"
+	LoopActionApprove     = "approve"
+	DefaultLoopWindow     = 10
+	DefaultLoopThreshold  = 5
+	loopSessionIdleTTL    = 6 * time.Hour
+	loopSessionPruneEvery = 10 * time.Minute
+)
+
+// LoopGuardConfig 循环检测配置，从系统设置读取
+type LoopGuardConfig struct {
+	Enabled         bool
+	Window          int
+	RepeatThreshold int
+	MaxTokens       uint64
+	MaxCost         uint64 // 纳美元
+	Action          string
+}
+
+// LoopTrip 描述一次循环检测触发
+type LoopTrip struct {
+	Reason  string
+	Repeats int
+	Tokens  uint64
+	Cost    uint64 // 纳美元
+	Limit   uint64 // 触发的上限（重复次数 / Token / 纳美元）
+}
+
+// Message returns a human readable description that is returned to the client
+func (t *LoopTrip) Message() string {
+	switch t.Reason {
+	case LoopReasonTokenLimit:
+		return fmt.Sprintf("session token ceiling reached (%d / %d tokens)", t.Tokens, t.Limit)
+	case LoopReasonCostLimit:
+		return fmt.Sprintf("session cost ceiling reached ($%.4f / $%.4f)", float64(t.Cost)/1e9, float64(t.Limit)/1e9)
+	default:
+		return fmt.Sprintf("near-identical request repeated %d times in this session", t.Repeats)
+	}
+}
+
+// loopSession 单个会话的检测状态
+type loopSession struct {
+	fingerprints []string
+	tokens       uint64
+	cost         uint64
+	lastSeen     time.Time
+}
+
+// LoopGuard 会话循环检测：记录每个会话最近 N 个请求的指纹和累计用量，
+// 相同请求重复过多或超过会话 Token/成本上限时拦截，防止失控的 Agent 持续消耗
+type LoopGuard struct {
+	settingRepo repository.SystemSettingRepository
+
+	mu        sync.Mutex
+	sessions  map[string]*loopSession
+	lastPrune time.Time
+}
+
+// NewLoopGuard creates a new LoopGuard
+func NewLoopGuard(settingRepo repository.SystemSettingRepository) *LoopGuard {
+	return &LoopGuard{
+		settingRepo: settingRepo,
+		sessions:    make(map[string]*loopSession),
+		lastPrune:   time.Now(),
+	}
+}
+
+// Config reads the loop guard configuration, invalid values fall back to defaults
+func (g *LoopGuard) Config() LoopGuardConfig {
+	cfg := LoopGuardConfig{
+		Window:          DefaultLoopWindow,
+		RepeatThreshold: DefaultLoopThreshold,
+		Action:          LoopActionReject,
+	}
+	if g.settingRepo == nil {
+		return cfg
+	}
+	get := func(key string) string {
+		v, err := g.settingRepo.Get(key)
+		if err != nil {
+			return ""
+		}
+		return v
+	}
+	cfg.Enabled = get(domain.SettingKeyLoopGuardEnabled) == "true"
+	if v, err := strconv.Atoi(get(domain.SettingKeyLoopGuardWindow)); err == nil && v > 0 {
+		cfg.Window = v
+	}
+	if v, err := strconv.Atoi(get(domain.SettingKeyLoopGuardRepeatThreshold)); err == nil && v >= 0 {
+		cfg.RepeatThreshold = v
+	}
+	if v, err := strconv.ParseUint(get(domain.SettingKeyLoopGuardMaxTokens), 10, 64); err == nil {
+		cfg.MaxTokens = v
+	}
+	if v, err := strconv.ParseFloat(get(domain.SettingKeyLoopGuardMaxCost), 64); err == nil && v > 0 {
+		cfg.MaxCost = uint64(v * 1e9)
+	}
+	if get(domain.SettingKeyLoopGuardAction) == LoopActionApprove {
+		cfg.Action = LoopActionApprove
+	}
+	return cfg
+}
+
+// Check records the fingerprint of an incoming request and returns a trip if the
+// session looks like it is looping or has exceeded its token/cost ceiling
+func (g *LoopGuard) Check(cfg LoopGuardConfig, sessionID, model string, body []byte) *LoopTrip {
+	if !cfg.Enabled || sessionID == "" {
+		return nil
+	}
+	fp := requestFingerprint(model, body)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	g.pruneLocked(now)
+
+	s, ok := g.sessions[sessionID]
+	if !ok {
+		s = &loopSession{}
+		g.sessions[sessionID] = s
+	}
+	s.lastSeen = now
+
+	// 用量上限优先于重复检测：上限触发后再次放行前需要管理员确认
+	if cfg.MaxTokens > 0 && s.tokens >= cfg.MaxTokens {
+		return &LoopTrip{Reason: LoopReasonTokenLimit, Tokens: s.tokens, Cost: s.cost, Limit: cfg.MaxTokens}
+	}
+	if cfg.MaxCost > 0 && s.cost >= cfg.MaxCost {
+		return &LoopTrip{Reason: LoopReasonCostLimit, Tokens: s.tokens, Cost: s.cost, Limit: cfg.MaxCost}
+	}
+
+	s.fingerprints = append(s.fingerprints, fp)
+	if len(s.fingerprints) > cfg.Window {
+		s.fingerprints = s.fingerprints[len(s.fingerprints)-cfg.Window:]
+	}
+	if cfg.RepeatThreshold <= 0 {
+		return nil
+	}
+	repeats := 0
+	for _, f := range s.fingerprints {
+		if f == fp {
+			repeats++
+		}
+	}
+	if repeats >= cfg.RepeatThreshold {
+		return &LoopTrip{Reason: LoopReasonRepetition, Repeats: repeats, Tokens: s.tokens, Cost: s.cost, Limit: uint64(cfg.RepeatThreshold)}
+	}
+	return nil
+}
+
+// Record adds the usage of a finished request to the session totals
+func (g *LoopGuard) Record(sessionID string, tokens, cost uint64) {
+	if sessionID == "" || (tokens == 0 && cost == 0) {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s, ok := g.sessions[sessionID]; ok {
+		s.tokens += tokens
+		s.cost += cost
+	}
+}
+
+// Reset clears the history and usage totals of a session, called after an admin approves continuation
+func (g *LoopGuard) Reset(sessionID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.sessions, sessionID)
+}
+
+func (g *LoopGuard) pruneLocked(now time.Time) {
+	if now.Sub(g.lastPrune) < loopSessionPruneEvery {
+		return
+	}
+	g.lastPrune = now
+	for id, s := range g.sessions {
+		if now.Sub(s.lastSeen) > loopSessionIdleTTL {
+			delete(g.sessions, id)
+		}
+	}
+}
+
+// requestFingerprint 计算请求指纹
+// Agent 每轮都会附带完整历史，整体 body 每次都不同，因此只取最后一条消息（通常是相同的工具结果或用户输入）
+// 支持 Claude/OpenAI 的 messages、Gemini 的 contents 以及 Codex 的 input，无法解析时使用整个 body
+// 工具调用 ID 和 cache_control 每轮都会变化，计算前会被去除，只保留文本和工具结果内容
+func requestFingerprint(model string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write(lastMessage(body))
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func lastMessage(body []byte) []byte {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return body
+	}
+	// Gemini CLI / Antigravity 会把请求包在 request 字段中
+	if inner, ok := payload["request"]; ok {
+		var wrapped map[string]json.RawMessage
+		if err := json.Unmarshal(inner, &wrapped); err == nil {
+			payload = wrapped
+		}
+	}
+	for _, key := range []string{"messages", "contents", "input"} {
+		raw, ok := payload[key]
+		if !ok {
+			continue
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			// Codex input 可以是字符串
+			return normalizeMessage(raw)
+		}
+		if len(items) > 0 {
+			return normalizeMessage(items[len(items)-1])
+		}
+	}
+	return body
+}
+
+// fingerprintIgnoredKeys 每轮都会变化、与消息内容无关的字段
+var fingerprintIgnoredKeys = map[string]bool{
+	"id":               true,
+	"tool_use_id":      true, // Claude tool_result
+	"tool_call_id":     true, // OpenAI tool 消息
+	"call_id":          true, // Codex function_call_output
+	"cache_control":    true,
+	"signature":        true,
+	"thoughtSignature": true,
+}
+
+// normalizeMessage 去除消息中的 ID 和缓存标记，输出键有序的紧凑 JSON
+func normalizeMessage(raw []byte) []byte {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return raw
+	}
+	normalized, err := json.Marshal(stripIgnoredKeys(v))
+	if err != nil {
+		return raw
+	}
+	return normalized
+}
+
+func stripIgnoredKeys(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if fingerprintIgnoredKeys[k] {
+				delete(val, k)
+				continue
+			}
+			val[k] = stripIgnoredKeys(item)
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = stripIgnoredKeys(item)
+		}
+	}
+	return v
+}
@@ -0,0 +1,137 @@
+package waiter
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLoopGuardRepetition(t *testing.T) {
+	g := NewLoopGuard(nil)
+	cfg := LoopGuardConfig{Enabled: true, Window: 10, RepeatThreshold: 3}
+
+	// 历史不断增长，但最后一条消息相同
+	body := func(turn int) []byte {
+		return []byte(fmt.Sprintf(`{"model":"m","messages":[{"role":"user","content":"turn %d"},{"role":"user","content":"same tool result"}]}`, turn))
+	}
+
+	if trip := g.Check(cfg, "s1", "m", body(1)); trip != nil {
+		t.Fatalf("unexpected trip on first request: %+v", trip)
+	}
+	if trip := g.Check(cfg, "s1", "m", body(2)); trip != nil {
+		t.Fatalf("unexpected trip on second request: %+v", trip)
+	}
+	trip := g.Check(cfg, "s1", "m", body(3))
+	if trip == nil || trip.Reason != LoopReasonRepetition || trip.Repeats != 3 {
+		t.Fatalf("expected repetition trip, got %+v", trip)
+	}
+
+	// 其他会话不受影响
+	if trip := g.Check(cfg, "s2", "m", body(1)); trip != nil {
+		t.Fatalf("unexpected trip for other session: %+v", trip)
+	}
+
+	// 批准后重置
+	g.Reset("s1")
+	if trip := g.Check(cfg, "s1", "m", body(4)); trip != nil {
+		t.Fatalf("unexpected trip after reset: %+v", trip)
+	}
+
+	// 未启用时不检测
+	if trip := g.Check(LoopGuardConfig{}, "s3", "m", body(1)); trip != nil {
+		t.Fatalf("unexpected trip when disabled: %+v", trip)
+	}
+}
+
+func TestLoopGuardToolLoop(t *testing.T) {
+	g := NewLoopGuard(nil)
+	cfg := LoopGuardConfig{Enabled: true, Window: 10, RepeatThreshold: 3}
+
+	// 每轮工具调用 ID 不同，cache_control 位置也会变化，但工具结果相同
+	body := func(turn int) []byte {
+		cache := ""
+		if turn%2 == 0 {
+			cache = `,"cache_control":{"type":"ephemeral"}`
+		}
+		return []byte(fmt.Sprintf(`{"model":"m","messages":[
+			{"role":"assistant","content":[{"type":"tool_use","id":"toolu_%[1]d","name":"bash","input":{"command":"make test"}}]},
+			{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_%[1]d","content":[{"type":"text","text":"FAIL: build error"%[2]s}]}]}
+		]}`, turn, cache))
+	}
+
+	for turn := 1; turn < 3; turn++ {
+		if trip := g.Check(cfg, "s", "m", body(turn)); trip != nil {
+			t.Fatalf("unexpected trip on turn %d: %+v", turn, trip)
+		}
+	}
+	trip := g.Check(cfg, "s", "m", body(3))
+	if trip == nil || trip.Reason != LoopReasonRepetition {
+		t.Fatalf("expected repetition trip for repeated tool results, got %+v", trip)
+	}
+
+	// Codex 的 function_call_output 同理
+	codex := func(turn int) []byte {
+		return []byte(fmt.Sprintf(`{"input":[{"type":"function_call_output","call_id":"call_%d","output":"exit status 1"}]}`, turn))
+	}
+	for turn := 1; turn < 3; turn++ {
+		if trip := g.Check(cfg, "codex", "m", codex(turn)); trip != nil {
+			t.Fatalf("unexpected trip on turn %d: %+v", turn, trip)
+		}
+	}
+	if trip := g.Check(cfg, "codex", "m", codex(3)); trip == nil {
+		t.Fatal("expected repetition trip for repeated function call outputs")
+	}
+
+	// 工具结果内容不同时不触发
+	other := []byte(`{"messages":[{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_9","content":"ok"}]}]}`)
+	if trip := g.Check(cfg, "s2", "m", other); trip != nil {
+		t.Fatalf("unexpected trip: %+v", trip)
+	}
+}
+
+func TestLoopGuardWindow(t *testing.T) {
+	g := NewLoopGuard(nil)
+	cfg := LoopGuardConfig{Enabled: true, Window: 3, RepeatThreshold: 2}
+
+	same := []byte(`{"input":"hello"}`)
+	if trip := g.Check(cfg, "s", "m", same); trip != nil {
+		t.Fatalf("unexpected trip: %+v", trip)
+	}
+	for i := 0; i < 3; i++ {
+		if trip := g.Check(cfg, "s", "m", []byte(fmt.Sprintf(`{"input":"other %d"}`, i))); trip != nil {
+			t.Fatalf("unexpected trip: %+v", trip)
+		}
+	}
+	// 第一次出现已滑出窗口
+	if trip := g.Check(cfg, "s", "m", same); trip != nil {
+		t.Fatalf("expected earlier fingerprint to leave the window, got %+v", trip)
+	}
+}
+
+func TestLoopGuardCeilings(t *testing.T) {
+	g := NewLoopGuard(nil)
+	cfg := LoopGuardConfig{Enabled: true, Window: 10, MaxTokens: 1000, MaxCost: 5_000_000_000}
+
+	body := func(i int) []byte { return []byte(fmt.Sprintf(`{"contents":[{"parts":[{"text":"%d"}]}]}`, i)) }
+
+	if trip := g.Check(cfg, "s", "m", body(1)); trip != nil {
+		t.Fatalf("unexpected trip: %+v", trip)
+	}
+	g.Record("s", 600, 1_000_000_000)
+	if trip := g.Check(cfg, "s", "m", body(2)); trip != nil {
+		t.Fatalf("unexpected trip below ceiling: %+v", trip)
+	}
+	g.Record("s", 600, 1_000_000_000)
+	trip := g.Check(cfg, "s", "m", body(3))
+	if trip == nil || trip.Reason != LoopReasonTokenLimit || trip.Tokens != 1200 {
+		t.Fatalf("expected token ceiling trip, got %+v", trip)
+	}
+
+	g.Reset("s")
+	cfg.MaxTokens = 0
+	g.Check(cfg, "s", "m", body(4))
+	g.Record("s", 10, 6_000_000_000)
+	trip = g.Check(cfg, "s", "m", body(5))
+	if trip == nil || trip.Reason != LoopReasonCostLimit {
+		t.Fatalf("expected cost ceiling trip, got %+v", trip)
+	}
+}
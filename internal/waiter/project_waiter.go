@@ -17,6 +17,7 @@ var (
 	ErrProjectBindingTimeout  = errors.New("project binding timeout: please select a project in the UI")
 	ErrProjectBindingRequired = errors.New("project binding required")
 	ErrSessionRejected        = errors.New("session rejected by user")
+	ErrLoopApprovalTimeout    = errors.New("loop approval timeout: please approve the session in the UI")
 )
 
 const (
@@ -174,3 +175,50 @@ func (w *ProjectWaiter) WaitForProject(ctx context.Context, session *domain.Sess
 		}
 	}
 }
+
+// WaitForLoopApproval 循环检测触发后等待管理员决定是否允许会话继续
+// 复用项目绑定的待处理会话机制：广播 session_loop_pending，轮询会话的 LoopApprovedAt / RejectedAt
+func (w *ProjectWaiter) WaitForLoopApproval(ctx context.Context, session *domain.Session, trip *LoopTrip) error {
+	start := time.Now()
+	log.Printf("[ProjectWaiter] Session %s: loop guard tripped (%s), waiting for approval", session.SessionID, trip.Message())
+
+	if w.broadcaster != nil {
+		w.broadcaster.BroadcastMessage("session_loop_pending", map[string]interface{}{
+			"sessionID":  session.SessionID,
+			"clientType": session.ClientType,
+			"reason":     trip.Reason,
+			"message":    trip.Message(),
+			"createdAt":  start.Format(time.RFC3339),
+		})
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, w.GetTimeout())
+	defer cancel()
+
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-timeoutCtx.Done():
+			if timeoutCtx.Err() == context.DeadlineExceeded {
+				log.Printf("[ProjectWaiter] Session %s: timeout waiting for loop approval", session.SessionID)
+				return ErrLoopApprovalTimeout
+			}
+			return timeoutCtx.Err()
+		case <-ticker.C:
+			updatedSession, err := w.sessionRepo.GetBySessionID(session.SessionID)
+			if err != nil || updatedSession == nil {
+				continue
+			}
+			if updatedSession.RejectedAt != nil && updatedSession.RejectedAt.After(start) {
+				log.Printf("[ProjectWaiter] Session %s: loop continuation rejected by user", session.SessionID)
+				return ErrSessionRejected
+			}
+			if updatedSession.LoopApprovedAt != nil && updatedSession.LoopApprovedAt.After(start) {
+				log.Printf("[ProjectWaiter] Session %s: loop continuation approved", session.SessionID)
+				return nil
+			}
+		}
+	}
+}
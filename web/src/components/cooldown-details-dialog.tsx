@@ -177,6 +177,14 @@ export function CooldownDetailsDialog({
                     {cooldown.clientType}
                   </span>
                 )}
+                {cooldown.model && (
+                  <span
+                    className="px-1.5 py-0.5 rounded text-[10px] font-mono bg-accent text-muted-foreground"
+                    title={t('cooldown.modelScope', { model: cooldown.model })}
+                  >
+                    {cooldown.model}
+                  </span>
+                )}
               </div>
               <div className="font-semibold text-foreground truncate">
                 Provider #{cooldown.providerID}
@@ -0,0 +1,198 @@
+import { useEffect, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+import {
+  useCooldownPolicies,
+  useCreateCooldownPolicy,
+  useUpdateCooldownPolicy,
+  useDeleteCooldownPolicy,
+} from '@/hooks/queries';
+import type {
+  CooldownPolicy,
+  CooldownPolicyConfig,
+  CooldownPolicyType,
+  CooldownReason,
+} from '@/lib/transport';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select';
+
+// 冷冻原因 -> provider.reasons 下的翻译 key
+const reasons: [CooldownReason, string][] = [
+  ['server_error', 'serverError'],
+  ['network_error', 'networkError'],
+  ['quota_exhausted', 'quotaExhausted'],
+  ['rate_limit_exceeded', 'rateLimitExceeded'],
+  ['concurrent_limit', 'concurrentLimit'],
+  ['unknown', 'unknown'],
+];
+
+const policyTypes: CooldownPolicyType[] = ['fixed', 'linear', 'exponential'];
+
+// Select 不支持空字符串作为值，用 fallback 表示不配置（沿用上一级策略）
+const FALLBACK = 'fallback';
+
+type Drafts = Partial<Record<CooldownReason, CooldownPolicyConfig>>;
+
+const toPositive = (value: string) => {
+  const n = parseInt(value, 10);
+  return Number.isNaN(n) || n <= 0 ? undefined : n;
+};
+
+const sameConfig = (a: CooldownPolicyConfig, b: CooldownPolicyConfig) =>
+  a.type === b.type &&
+  a.baseSeconds === b.baseSeconds &&
+  (a.maxSeconds || 0) === (b.maxSeconds || 0);
+
+/**
+ * 按冷冻原因编辑冷冻时长策略
+ * providerId 为 0 时编辑全局默认，否则编辑该 Provider 的覆盖策略
+ */
+export function CooldownPolicyEditor({ providerId }: { providerId: number }) {
+  const { t } = useTranslation();
+  const { data: allPolicies } = useCooldownPolicies();
+  const createPolicy = useCreateCooldownPolicy();
+  const updatePolicy = useUpdateCooldownPolicy();
+  const deletePolicy = useDeleteCooldownPolicy();
+
+  const existing = new Map<CooldownReason, CooldownPolicy>(
+    (allPolicies ?? []).filter((p) => p.providerID === providerId).map((p) => [p.reason, p]),
+  );
+
+  const [drafts, setDrafts] = useState<Drafts>({});
+  const [saving, setSaving] = useState(false);
+  useEffect(() => {
+    if (allPolicies) {
+      setDrafts(
+        Object.fromEntries(
+          allPolicies
+            .filter((p) => p.providerID === providerId)
+            .map((p) => [
+              p.reason,
+              { type: p.type, baseSeconds: p.baseSeconds, maxSeconds: p.maxSeconds || undefined },
+            ]),
+        ),
+      );
+    }
+  }, [allPolicies, providerId]);
+
+  const setDraft = (reason: CooldownReason, policy: CooldownPolicyConfig | undefined) => {
+    setDrafts((prev) => {
+      const next = { ...prev };
+      if (policy) {
+        next[reason] = policy;
+      } else {
+        delete next[reason];
+      }
+      return next;
+    });
+  };
+
+  // 未填写基础时长的策略无效，视为未配置
+  const validDraft = (reason: CooldownReason) => {
+    const draft = drafts[reason];
+    return draft && draft.baseSeconds > 0 ? draft : undefined;
+  };
+
+  const hasChanges = reasons.some(([reason]) => {
+    const draft = validDraft(reason);
+    const current = existing.get(reason);
+    return draft && current ? !sameConfig(draft, current) : !!draft !== !!current;
+  });
+
+  const handleSave = async () => {
+    setSaving(true);
+    try {
+      for (const [reason] of reasons) {
+        const draft = validDraft(reason);
+        const current = existing.get(reason);
+        if (draft && !current) {
+          await createPolicy.mutateAsync({ providerID: providerId, reason, ...draft });
+        } else if (draft && current && !sameConfig(draft, current)) {
+          await updatePolicy.mutateAsync({
+            id: current.id,
+            data: { providerID: providerId, reason, ...draft },
+          });
+        } else if (!draft && current) {
+          await deletePolicy.mutateAsync(current.id);
+        }
+      }
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const fallbackLabel =
+    providerId === 0 ? t('cooldown.policy.builtin') : t('cooldown.policy.inheritGlobal');
+
+  return (
+    <div className="space-y-2">
+      {reasons.map(([reason, labelKey]) => {
+        const policy = drafts[reason];
+        return (
+          <div key={reason} className="grid grid-cols-4 gap-2 items-center">
+            <span className="text-xs text-foreground">{t(`provider.reasons.${labelKey}`)}</span>
+            <Select
+              value={policy?.type ?? FALLBACK}
+              onValueChange={(value) =>
+                setDraft(
+                  reason,
+                  value === FALLBACK
+                    ? undefined
+                    : { baseSeconds: 0, ...policy, type: value as CooldownPolicyType },
+                )
+              }
+            >
+              <SelectTrigger className="w-full h-8 text-xs">
+                <SelectValue>
+                  {policy ? t(`cooldown.policy.types.${policy.type}`) : fallbackLabel}
+                </SelectValue>
+              </SelectTrigger>
+              <SelectContent>
+                <SelectItem value={FALLBACK}>{fallbackLabel}</SelectItem>
+                {policyTypes.map((type) => (
+                  <SelectItem key={type} value={type}>
+                    {t(`cooldown.policy.types.${type}`)}
+                  </SelectItem>
+                ))}
+              </SelectContent>
+            </Select>
+            <Input
+              type="number"
+              min={1}
+              disabled={!policy}
+              value={policy?.baseSeconds || ''}
+              onChange={(e) =>
+                policy &&
+                setDraft(reason, { ...policy, baseSeconds: toPositive(e.target.value) ?? 0 })
+              }
+              placeholder={t('cooldown.policy.baseSeconds')}
+              className="w-full h-8 text-xs"
+            />
+            <Input
+              type="number"
+              min={1}
+              disabled={!policy || policy.type === 'fixed'}
+              value={policy?.maxSeconds ?? ''}
+              onChange={(e) =>
+                policy && setDraft(reason, { ...policy, maxSeconds: toPositive(e.target.value) })
+              }
+              placeholder={t('cooldown.policy.maxSeconds')}
+              className="w-full h-8 text-xs"
+            />
+          </div>
+        );
+      })}
+      <div className="flex justify-end">
+        <Button size="sm" onClick={handleSave} disabled={!hasChanges || saving}>
+          {saving ? t('common.saving') : t('common.save')}
+        </Button>
+      </div>
+    </div>
+  );
+}
@@ -2,15 +2,19 @@ import { Outlet } from 'react-router-dom';
 import { AppSidebar } from './app-sidebar';
 import { SidebarProvider, SidebarInset } from '@/components/ui/sidebar';
 import { ForceProjectDialog } from '@/components/force-project-dialog';
+import { LoopGuardDialog } from '@/components/loop-guard-dialog';
 import { usePendingSession } from '@/hooks/use-pending-session';
 import { useSettings } from '@/hooks/queries';
 
 export function AppLayout() {
-  const { pendingSession, clearPendingSession } = usePendingSession();
+  const { pendingSession, clearPendingSession, loopPendingSession, clearLoopPendingSession } =
+    usePendingSession();
   const { data: settings } = useSettings();
 
   const forceProjectEnabled = settings?.force_project_binding === 'true';
   const timeoutSeconds = parseInt(settings?.force_project_timeout || '30', 10);
+  const loopApprovalEnabled =
+    settings?.loop_guard_enabled === 'true' && settings?.loop_guard_action === 'approve';
 
   return (
     <>
@@ -31,6 +35,14 @@ export function AppLayout() {
           timeoutSeconds={timeoutSeconds}
         />
       )}
+
+      {loopApprovalEnabled && (
+        <LoopGuardDialog
+          event={loopPendingSession}
+          onClose={clearLoopPendingSession}
+          timeoutSeconds={timeoutSeconds}
+        />
+      )}
     </>
   );
 }
@@ -0,0 +1,147 @@
+/**
+ * Loop Guard Dialog
+ * Shows when the loop guard holds a session and waits for admin approval
+ */
+
+import { useCallback, useEffect, useState } from 'react';
+import { Dialog, DialogContent } from '@/components/ui/dialog';
+import { Repeat, Clock, Check, X } from 'lucide-react';
+import { useApproveSessionLoop, useRejectSession } from '@/hooks/queries';
+import type { SessionLoopPendingEvent } from '@/lib/transport/types';
+import { getClientName, getClientColor } from '@/components/icons/client-icons';
+import { useTranslation } from 'react-i18next';
+import { useCountdown } from '@/hooks/use-countdown';
+import { cn } from '@/lib/utils';
+
+interface LoopGuardDialogProps {
+  event: SessionLoopPendingEvent | null;
+  onClose: () => void;
+  timeoutSeconds: number;
+}
+
+export function LoopGuardDialog({ event, onClose, timeoutSeconds }: LoopGuardDialogProps) {
+  const { t } = useTranslation();
+  const approveSession = useApproveSessionLoop();
+  const rejectSession = useRejectSession();
+  const [eventKey, setEventKey] = useState<string | null>(null);
+
+  const handleTimeout = useCallback(() => {
+    if (event) {
+      onClose();
+    }
+  }, [event, onClose]);
+
+  const { remainingTime, reset: resetCountdown } = useCountdown({
+    initialSeconds: timeoutSeconds,
+    onComplete: handleTimeout,
+    autoStart: !!event,
+  });
+
+  // Reset countdown when a new event arrives
+  useEffect(() => {
+    const key = event ? `${event.sessionID}-${event.createdAt}` : null;
+    if (key && key !== eventKey) {
+      setEventKey(key);
+      resetCountdown(timeoutSeconds);
+    }
+  }, [event, eventKey, timeoutSeconds, resetCountdown]);
+
+  const handleApprove = async () => {
+    if (!event) return;
+    try {
+      await approveSession.mutateAsync(event.sessionID);
+      onClose();
+    } catch (error) {
+      console.error('Failed to approve session:', error);
+    }
+  };
+
+  const handleReject = async () => {
+    if (!event) return;
+    try {
+      await rejectSession.mutateAsync(event.sessionID);
+      onClose();
+    } catch (error) {
+      console.error('Failed to reject session:', error);
+    }
+  };
+
+  if (!event) return null;
+
+  const clientColor = getClientColor(event.clientType);
+  const busy = approveSession.isPending || rejectSession.isPending;
+
+  return (
+    <Dialog open={!!event} onOpenChange={(open) => !open && onClose()}>
+      <DialogContent
+        showCloseButton={false}
+        className="p-0 w-full max-w-[28rem] bg-card border border-red-500/30 shadow-[0_0_30px_-5px_rgba(239,68,68,0.3)]"
+      >
+        <div className="relative bg-gradient-to-b from-red-900/20 to-transparent p-6 pb-4 rounded-t-lg">
+          <div className="flex flex-col items-center text-center space-y-3">
+            <div className="p-3 rounded-2xl bg-red-500/10 border border-red-400/20">
+              <Repeat size={28} className="text-red-400" />
+            </div>
+            <div>
+              <h2 className="text-xl font-bold text-text-primary">{t('sessions.loopDetected')}</h2>
+              <p className="text-xs text-red-500/80 font-medium uppercase tracking-wider mt-1">
+                {t(`sessions.loopReasons.${event.reason}`)}
+              </p>
+            </div>
+          </div>
+        </div>
+
+        <div className="px-6 pb-6 space-y-5">
+          <div className="p-3 rounded-xl bg-muted border border-border space-y-1">
+            <div className="flex items-center gap-2">
+              <span className="text-[10px] font-bold text-text-muted uppercase tracking-wider">
+                {t('sessions.session')}
+              </span>
+              <span
+                className="px-1.5 py-0.5 rounded text-[10px] font-mono font-medium"
+                style={{ backgroundColor: `${clientColor}20`, color: clientColor }}
+              >
+                {getClientName(event.clientType)}
+              </span>
+            </div>
+            <div className="font-mono text-xs text-muted-foreground truncate">
+              {event.sessionID}
+            </div>
+            <div className="text-xs text-foreground">{event.message}</div>
+          </div>
+
+          <div
+            className={cn(
+              'flex items-center justify-center gap-2 font-mono text-2xl font-bold tabular-nums',
+              remainingTime <= 10 ? 'text-red-400' : 'text-amber-400',
+            )}
+          >
+            <Clock size={18} />
+            {remainingTime}s
+          </div>
+
+          <div className="grid grid-cols-2 gap-3">
+            <button
+              onClick={handleReject}
+              disabled={busy}
+              className="flex items-center justify-center gap-2 px-4 py-3 rounded-xl border border-red-500/30 bg-red-500/10 text-red-400 hover:bg-red-500/20 transition-all disabled:opacity-50 disabled:cursor-not-allowed"
+            >
+              <X size={16} />
+              <span className="text-sm font-bold">{t('sessions.reject')}</span>
+            </button>
+            <button
+              onClick={handleApprove}
+              disabled={busy}
+              className="flex items-center justify-center gap-2 px-4 py-3 rounded-xl border border-emerald-500/30 bg-emerald-500/10 text-emerald-400 hover:bg-emerald-500/20 transition-all disabled:opacity-50 disabled:cursor-not-allowed"
+            >
+              <Check size={16} />
+              <span className="text-sm font-bold">{t('sessions.allowContinue')}</span>
+            </button>
+          </div>
+
+          <p className="text-[11px] text-muted-foreground">{t('sessions.loopTimeoutWarning')}</p>
+        </div>
+      </DialogContent>
+    </Dialog>
+  );
+}
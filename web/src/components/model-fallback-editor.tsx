@@ -0,0 +1,228 @@
+import { useEffect, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+import { Plus, Trash2 } from 'lucide-react';
+import {
+  useModelFallbacks,
+  useCreateModelFallback,
+  useUpdateModelFallback,
+  useDeleteModelFallback,
+  useProjects,
+} from '@/hooks/queries';
+import type { ClientType, CreateModelFallbackData, ModelFallback } from '@/lib/transport';
+import { getClientName } from '@/components/icons/client-icons';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { Switch } from '@/components/ui/switch';
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select';
+
+const clientTypes: ClientType[] = ['claude', 'codex', 'gemini', 'openai'];
+
+// Select 不支持空字符串作为值，用 all 表示不限制
+const ALL = 'all';
+
+// 降级模型在输入框中以逗号分隔，保存前再拆分
+interface Draft extends Omit<CreateModelFallbackData, 'models'> {
+  id?: number;
+  models: string;
+}
+
+const toDraft = (f: ModelFallback): Draft => ({
+  id: f.id,
+  clientType: f.clientType || '',
+  projectID: f.projectID,
+  pattern: f.pattern,
+  models: f.models.join(', '),
+  notifyClient: f.notifyClient,
+  priority: f.priority,
+});
+
+const toData = (draft: Draft): CreateModelFallbackData => ({
+  clientType: draft.clientType,
+  projectID: draft.projectID,
+  pattern: draft.pattern.trim(),
+  models: draft.models
+    .split(',')
+    .map((m) => m.trim())
+    .filter(Boolean),
+  notifyClient: draft.notifyClient,
+  priority: draft.priority,
+});
+
+const sameDraft = (a: Draft, b: Draft) =>
+  JSON.stringify(toData(a)) === JSON.stringify(toData(b));
+
+/**
+ * 编辑模型降级链：当前模型的路由全部失败或冷冻时，按顺序降级到后续模型
+ */
+export function ModelFallbackEditor() {
+  const { t } = useTranslation();
+  const { data: fallbacks } = useModelFallbacks();
+  const { data: projects } = useProjects();
+  const createFallback = useCreateModelFallback();
+  const updateFallback = useUpdateModelFallback();
+  const deleteFallback = useDeleteModelFallback();
+
+  const [drafts, setDrafts] = useState<Draft[]>([]);
+  const [saving, setSaving] = useState(false);
+  useEffect(() => {
+    if (fallbacks) {
+      setDrafts(fallbacks.map(toDraft));
+    }
+  }, [fallbacks]);
+
+  const existing = new Map((fallbacks ?? []).map((f) => [f.id, toDraft(f)]));
+
+  const setDraft = (index: number, patch: Partial<Draft>) => {
+    setDrafts((prev) => prev.map((d, i) => (i === index ? { ...d, ...patch } : d)));
+  };
+
+  const addDraft = () => {
+    setDrafts((prev) => [
+      ...prev,
+      { clientType: '', projectID: 0, pattern: '', models: '', notifyClient: false, priority: 0 },
+    ]);
+  };
+
+  // 缺少匹配模式或降级模型的行无效，保存时忽略
+  const isValid = (draft: Draft) => !!draft.pattern.trim() && toData(draft).models.length > 0;
+
+  const removed = [...existing.keys()].filter((id) => !drafts.some((d) => d.id === id));
+  const hasChanges =
+    removed.length > 0 ||
+    drafts.some((d) => {
+      if (!isValid(d)) return false;
+      const current = d.id ? existing.get(d.id) : undefined;
+      return !current || !sameDraft(d, current);
+    });
+
+  const handleSave = async () => {
+    setSaving(true);
+    try {
+      for (const id of removed) {
+        await deleteFallback.mutateAsync(id);
+      }
+      for (const draft of drafts) {
+        if (!isValid(draft)) continue;
+        const current = draft.id ? existing.get(draft.id) : undefined;
+        if (!draft.id) {
+          await createFallback.mutateAsync(toData(draft));
+        } else if (current && !sameDraft(draft, current)) {
+          await updateFallback.mutateAsync({ id: draft.id, data: toData(draft) });
+        }
+      }
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="space-y-2">
+      {drafts.length === 0 && (
+        <p className="text-xs text-muted-foreground">{t('modelFallbacks.empty')}</p>
+      )}
+      {drafts.map((draft, index) => (
+        <div
+          key={draft.id ?? `new-${index}`}
+          className="grid grid-cols-2 md:grid-cols-[8rem_10rem_1fr_2fr_5rem_auto_auto] gap-2 items-center"
+        >
+          <Select
+            value={draft.clientType || ALL}
+            onValueChange={(value) =>
+              setDraft(index, { clientType: value === ALL ? '' : (value as ClientType) })
+            }
+          >
+            <SelectTrigger className="w-full h-8 text-xs">
+              <SelectValue>
+                {draft.clientType
+                  ? getClientName(draft.clientType)
+                  : t('modelFallbacks.allClients')}
+              </SelectValue>
+            </SelectTrigger>
+            <SelectContent>
+              <SelectItem value={ALL}>{t('modelFallbacks.allClients')}</SelectItem>
+              {clientTypes.map((type) => (
+                <SelectItem key={type} value={type}>
+                  {getClientName(type)}
+                </SelectItem>
+              ))}
+            </SelectContent>
+          </Select>
+          <Select
+            value={String(draft.projectID)}
+            onValueChange={(value) => setDraft(index, { projectID: Number(value) })}
+          >
+            <SelectTrigger className="w-full h-8 text-xs">
+              <SelectValue>
+                {draft.projectID
+                  ? (projects?.find((p) => p.id === draft.projectID)?.name ?? `#${draft.projectID}`)
+                  : t('modelFallbacks.allProjects')}
+              </SelectValue>
+            </SelectTrigger>
+            <SelectContent>
+              <SelectItem value="0">{t('modelFallbacks.allProjects')}</SelectItem>
+              {(projects ?? []).map((p) => (
+                <SelectItem key={p.id} value={String(p.id)}>
+                  {p.name}
+                </SelectItem>
+              ))}
+            </SelectContent>
+          </Select>
+          <Input
+            value={draft.pattern}
+            onChange={(e) => setDraft(index, { pattern: e.target.value })}
+            placeholder={t('modelFallbacks.pattern')}
+            className="w-full h-8 text-xs font-mono"
+          />
+          <Input
+            value={draft.models}
+            onChange={(e) => setDraft(index, { models: e.target.value })}
+            placeholder={t('modelFallbacks.models')}
+            className="w-full h-8 text-xs font-mono"
+          />
+          <Input
+            type="number"
+            value={draft.priority}
+            onChange={(e) => setDraft(index, { priority: parseInt(e.target.value, 10) || 0 })}
+            placeholder={t('modelFallbacks.priority')}
+            title={t('modelFallbacks.priority')}
+            className="w-full h-8 text-xs"
+          />
+          <label
+            className="flex items-center gap-2 text-xs text-muted-foreground"
+            title={t('modelFallbacks.notifyClientDesc')}
+          >
+            <Switch
+              checked={draft.notifyClient}
+              onCheckedChange={(checked) => setDraft(index, { notifyClient: checked })}
+            />
+            {t('modelFallbacks.notifyClient')}
+          </label>
+          <Button
+            variant="ghost"
+            size="icon"
+            className="h-8 w-8"
+            onClick={() => setDrafts((prev) => prev.filter((_, i) => i !== index))}
+            title={t('common.delete')}
+          >
+            <Trash2 className="h-4 w-4" />
+          </Button>
+        </div>
+      ))}
+      <div className="flex justify-between">
+        <Button variant="outline" size="sm" onClick={addDraft}>
+          <Plus className="h-4 w-4 mr-1" />
+          {t('common.add')}
+        </Button>
+        <Button size="sm" onClick={handleSave} disabled={!hasChanges || saving}>
+          {saving ? t('common.saving') : t('common.save')}
+        </Button>
+      </div>
+    </div>
+  );
+}
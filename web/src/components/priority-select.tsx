@@ -0,0 +1,48 @@
+import { useTranslation } from 'react-i18next';
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@/components/ui';
+import type { PriorityClass } from '@/lib/transport';
+
+// Select 不支持空字符串作为选项值，用 inherit 代替
+const INHERIT = 'inherit';
+
+interface PrioritySelectProps {
+  value: PriorityClass;
+  onChange: (value: PriorityClass) => void;
+  /** 允许留空以继承项目优先级（API Token 使用） */
+  allowInherit?: boolean;
+  disabled?: boolean;
+}
+
+/**
+ * 准入队列优先级选择
+ */
+export function PrioritySelect({ value, onChange, allowInherit, disabled }: PrioritySelectProps) {
+  const { t } = useTranslation();
+
+  const options = [
+    ...(allowInherit ? [INHERIT] : []),
+    'interactive',
+    'normal',
+    'batch',
+  ] as const;
+  const selected = value || (allowInherit ? INHERIT : 'normal');
+
+  return (
+    <Select
+      value={selected}
+      onValueChange={(v) => v && onChange(v === INHERIT ? '' : (v as PriorityClass))}
+      disabled={disabled}
+    >
+      <SelectTrigger className="w-56">
+        <SelectValue>{t(`priority.${selected}`)}</SelectValue>
+      </SelectTrigger>
+      <SelectContent>
+        {options.map((option) => (
+          <SelectItem key={option} value={option}>
+            {t(`priority.${option}`)}
+          </SelectItem>
+        ))}
+      </SelectContent>
+    </Select>
+  );
+}
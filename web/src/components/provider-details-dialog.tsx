@@ -592,6 +592,11 @@ export function ProviderDetailsDialog({
                             {REASON_INFO[cooldown.reason]?.description ||
                               REASON_INFO.unknown.description}
                           </p>
+                          {cooldown.model && (
+                            <p className="mt-1 text-xs font-mono text-muted-foreground">
+                              {t('cooldown.modelScope', { model: cooldown.model })}
+                            </p>
+                          )}
                         </div>
                       </div>
                     </div>
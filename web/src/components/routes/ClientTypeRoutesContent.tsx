@@ -48,14 +48,38 @@ import { Button } from '../ui';
 import { AntigravityQuotasProvider } from '@/contexts/antigravity-quotas-context';
 import { CooldownsProvider } from '@/contexts/cooldowns-context';
 
-type ProviderTypeKey = 'antigravity' | 'kiro' | 'codex' | 'custom';
-
-const PROVIDER_TYPE_ORDER: ProviderTypeKey[] = ['antigravity', 'kiro', 'codex', 'custom'];
+type ProviderTypeKey =
+  | 'antigravity'
+  | 'kiro'
+  | 'codex'
+  | 'bedrock'
+  | 'vertex'
+  | 'azure_openai'
+  | 'local'
+  | 'copilot'
+  | 'custom';
+
+const PROVIDER_TYPE_ORDER: ProviderTypeKey[] = [
+  'antigravity',
+  'kiro',
+  'codex',
+  'bedrock',
+  'vertex',
+  'azure_openai',
+  'local',
+  'copilot',
+  'custom',
+];
 
 const PROVIDER_TYPE_LABELS: Record<Exclude<ProviderTypeKey, 'custom'>, string> = {
   antigravity: 'Antigravity',
   kiro: 'Kiro',
   codex: 'Codex',
+  bedrock: 'AWS Bedrock',
+  vertex: 'Vertex AI',
+  azure_openai: 'Azure OpenAI',
+  local: 'Local',
+  copilot: 'GitHub Copilot',
 };
 
 interface ClientTypeRoutesContentProps {
@@ -159,6 +183,11 @@ function ClientTypeRoutesContentInner({
       antigravity: [],
       kiro: [],
       codex: [],
+      bedrock: [],
+      vertex: [],
+      azure_openai: [],
+      local: [],
+      copilot: [],
       custom: [],
     };
 
@@ -101,7 +101,9 @@ export function CooldownsProvider({ children }: CooldownsProviderProps) {
 
   const getCooldownForProvider = useCallback(
     (providerId: number, clientType?: string) => {
-      return cooldowns.find((cd: Cooldown) => {
+      // 冷冻整个 Provider 的记录优先于只冷冻部分模型的记录
+      const sorted = [...cooldowns].sort((a, b) => Number(!!a.model) - Number(!!b.model));
+      return sorted.find((cd: Cooldown) => {
         const matchesProvider = cd.providerID === providerId;
         const matchesClientType =
           cd.clientType === '' ||
@@ -47,6 +47,9 @@ export {
   useSessions,
   useUpdateSessionProject,
   useRejectSession,
+  useApproveSessionLoop,
+  useSessionStats,
+  useSessionTimeline,
 } from './use-sessions';
 
 // RetryConfig hooks
@@ -59,6 +62,44 @@ export {
   useDeleteRetryConfig,
 } from './use-retry-configs';
 
+// BillingRule hooks
+export {
+  billingRuleKeys,
+  useBillingRules,
+  useCreateBillingRule,
+  useUpdateBillingRule,
+  useDeleteBillingRule,
+} from './use-billing-rules';
+
+// CooldownPolicy hooks
+export {
+  cooldownPolicyKeys,
+  useCooldownPolicies,
+  useCreateCooldownPolicy,
+  useUpdateCooldownPolicy,
+  useDeleteCooldownPolicy,
+} from './use-cooldown-policies';
+
+// ModelFallback hooks
+export {
+  modelFallbackKeys,
+  useModelFallbacks,
+  useCreateModelFallback,
+  useUpdateModelFallback,
+  useDeleteModelFallback,
+} from './use-model-fallbacks';
+
+// Anomaly Alert hooks
+export { anomalyAlertKeys, useAnomalyAlerts } from './use-anomaly-alerts';
+
+// Provider Health hooks
+export {
+  providerHealthKeys,
+  useProviderHealth,
+  useProviderHealthHistory,
+  useCheckProviderHealth,
+} from './use-provider-health';
+
 // RoutingStrategy hooks
 export {
   routingStrategyKeys,
@@ -113,8 +154,12 @@ export {
   usageStatsKeys,
   useUsageStats,
   useUsageStatsWithPreset,
+  useWastedSpend,
+  useQueueStats,
   useRecalculateUsageStats,
   useRecalculateCosts,
+  useExportUsageStats,
+  useRunBillingReport,
   selectGranularity,
   getTimeRange,
   type TimeRangePreset,
@@ -158,4 +203,5 @@ export {
   useUpdateModelPrice,
   useDeleteModelPrice,
   useResetModelPricesToDefaults,
+  useImportModelPrices,
 } from './use-model-prices';
@@ -0,0 +1,32 @@
+/**
+ * Anomaly Alert React Query Hooks
+ */
+
+import { useEffect } from 'react';
+import { useQuery, useQueryClient } from '@tanstack/react-query';
+import { getTransport, type AnomalyAlert } from '@/lib/transport';
+
+// Query Keys
+export const anomalyAlertKeys = {
+  all: ['anomalyAlerts'] as const,
+};
+
+// 获取最近的用量异常告警，并订阅 WebSocket 实时追加
+export function useAnomalyAlerts() {
+  const queryClient = useQueryClient();
+
+  useEffect(() => {
+    const transport = getTransport();
+    const unsubscribe = transport.subscribe<AnomalyAlert>('anomaly_alert', (alert) => {
+      queryClient.setQueryData<AnomalyAlert[]>(anomalyAlertKeys.all, (old) =>
+        [alert, ...(old ?? [])].slice(0, 100),
+      );
+    });
+    return unsubscribe;
+  }, [queryClient]);
+
+  return useQuery({
+    queryKey: anomalyAlertKeys.all,
+    queryFn: () => getTransport().getAnomalyAlerts(),
+  });
+}
@@ -0,0 +1,58 @@
+/**
+ * BillingRule React Query Hooks
+ */
+
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { getTransport, type CreateBillingRuleData } from '@/lib/transport';
+
+// Query Keys
+export const billingRuleKeys = {
+  all: ['billingRules'] as const,
+  lists: () => [...billingRuleKeys.all, 'list'] as const,
+  list: () => [...billingRuleKeys.lists()] as const,
+};
+
+// 获取所有 BillingRules
+export function useBillingRules() {
+  return useQuery({
+    queryKey: billingRuleKeys.list(),
+    queryFn: () => getTransport().getBillingRules(),
+  });
+}
+
+// 创建 BillingRule
+export function useCreateBillingRule() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (data: CreateBillingRuleData) => getTransport().createBillingRule(data),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: billingRuleKeys.lists() });
+    },
+  });
+}
+
+// 更新 BillingRule
+export function useUpdateBillingRule() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({ id, data }: { id: number; data: CreateBillingRuleData }) =>
+      getTransport().updateBillingRule(id, data),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: billingRuleKeys.lists() });
+    },
+  });
+}
+
+// 删除 BillingRule
+export function useDeleteBillingRule() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (id: number) => getTransport().deleteBillingRule(id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: billingRuleKeys.lists() });
+    },
+  });
+}
@@ -0,0 +1,58 @@
+/**
+ * CooldownPolicy React Query Hooks
+ */
+
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { getTransport, type CreateCooldownPolicyData } from '@/lib/transport';
+
+// Query Keys
+export const cooldownPolicyKeys = {
+  all: ['cooldownPolicies'] as const,
+  lists: () => [...cooldownPolicyKeys.all, 'list'] as const,
+  list: () => [...cooldownPolicyKeys.lists()] as const,
+};
+
+// 获取所有 CooldownPolicies
+export function useCooldownPolicies() {
+  return useQuery({
+    queryKey: cooldownPolicyKeys.list(),
+    queryFn: () => getTransport().getCooldownPolicies(),
+  });
+}
+
+// 创建 CooldownPolicy
+export function useCreateCooldownPolicy() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (data: CreateCooldownPolicyData) => getTransport().createCooldownPolicy(data),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: cooldownPolicyKeys.lists() });
+    },
+  });
+}
+
+// 更新 CooldownPolicy
+export function useUpdateCooldownPolicy() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({ id, data }: { id: number; data: CreateCooldownPolicyData }) =>
+      getTransport().updateCooldownPolicy(id, data),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: cooldownPolicyKeys.lists() });
+    },
+  });
+}
+
+// 删除 CooldownPolicy
+export function useDeleteCooldownPolicy() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (id: number) => getTransport().deleteCooldownPolicy(id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: cooldownPolicyKeys.lists() });
+    },
+  });
+}
@@ -22,6 +22,7 @@ export interface DashboardSummary {
   todayRequests: number;
   todayTokens: number; // input + output + cacheRead + cacheWrite
   todayCost: number; // 微美元
+  todayMargin: number; // 毛利 = 计费金额 - 上游成本
   todaySuccessRate: number; // 0-100
   rpm?: number; // Requests Per Minute (今日平均)
   tpm?: number; // Tokens Per Minute (今日平均)
@@ -87,6 +88,7 @@ export function useDashboardSummary() {
         todayRequests: 0,
         todayTokens: 0,
         todayCost: 0,
+        todayMargin: 0,
         todaySuccessRate: 0,
         rpm: undefined,
         tpm: undefined,
@@ -105,6 +107,7 @@ export function useDashboardSummary() {
       todayRequests: today.requests,
       todayTokens: today.tokens,
       todayCost: today.cost,
+      todayMargin: today.margin || 0,
       todaySuccessRate: today.successRate || 0,
       rpm: today.rpm,
       tpm: today.tpm,
@@ -137,6 +140,7 @@ export function useAllTimeStats() {
         totalRequests: 0,
         totalTokens: 0,
         totalCost: 0,
+        totalMargin: 0,
       };
     }
 
@@ -145,6 +149,7 @@ export function useAllTimeStats() {
       totalRequests: allTime.requests,
       totalTokens: allTime.tokens,
       totalCost: allTime.cost,
+      totalMargin: allTime.margin || 0,
     };
   }, [dashboardData]);
 
@@ -0,0 +1,58 @@
+/**
+ * ModelFallback React Query Hooks
+ */
+
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { getTransport, type CreateModelFallbackData } from '@/lib/transport';
+
+// Query Keys
+export const modelFallbackKeys = {
+  all: ['modelFallbacks'] as const,
+  lists: () => [...modelFallbackKeys.all, 'list'] as const,
+  list: () => [...modelFallbackKeys.lists()] as const,
+};
+
+// 获取所有 ModelFallbacks
+export function useModelFallbacks() {
+  return useQuery({
+    queryKey: modelFallbackKeys.list(),
+    queryFn: () => getTransport().getModelFallbacks(),
+  });
+}
+
+// 创建 ModelFallback
+export function useCreateModelFallback() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (data: CreateModelFallbackData) => getTransport().createModelFallback(data),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: modelFallbackKeys.lists() });
+    },
+  });
+}
+
+// 更新 ModelFallback
+export function useUpdateModelFallback() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({ id, data }: { id: number; data: CreateModelFallbackData }) =>
+      getTransport().updateModelFallback(id, data),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: modelFallbackKeys.lists() });
+    },
+  });
+}
+
+// 删除 ModelFallback
+export function useDeleteModelFallback() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (id: number) => getTransport().deleteModelFallback(id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: modelFallbackKeys.lists() });
+    },
+  });
+}
@@ -3,7 +3,11 @@
  */
 
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
-import { getTransport, type ModelPriceInput } from '@/lib/transport';
+import {
+  getTransport,
+  type ModelPriceImportInput,
+  type ModelPriceInput,
+} from '@/lib/transport';
 import { pricingKeys } from './use-pricing';
 
 // Query Keys
@@ -89,3 +93,17 @@ export function useResetModelPricesToDefaults() {
     },
   });
 }
+
+// 导入价格表（JSON / CSV / LiteLLM）
+export function useImportModelPrices() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (input: ModelPriceImportInput) => getTransport().importModelPrices(input),
+    onSuccess: (result) => {
+      if (result.dryRun) return;
+      queryClient.invalidateQueries({ queryKey: modelPriceKeys.lists() });
+      queryClient.invalidateQueries({ queryKey: pricingKeys.all });
+    },
+  });
+}
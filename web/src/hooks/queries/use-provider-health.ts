@@ -0,0 +1,66 @@
+/**
+ * Provider Health React Query Hooks
+ */
+
+import { useEffect } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { getTransport, type ProviderHealthCheck } from '@/lib/transport';
+
+// Query Keys
+export const providerHealthKeys = {
+  all: ['providerHealth'] as const,
+  statuses: () => [...providerHealthKeys.all, 'statuses'] as const,
+  histories: () => [...providerHealthKeys.all, 'history'] as const,
+  history: (providerId: number) => [...providerHealthKeys.histories(), providerId] as const,
+};
+
+// 订阅 WebSocket 探测结果，刷新健康状态和对应 Provider 的历史
+function useProviderHealthSubscription() {
+  const queryClient = useQueryClient();
+
+  useEffect(() => {
+    const transport = getTransport();
+    const unsubscribe = transport.subscribe<ProviderHealthCheck>(
+      'provider_health_update',
+      (check) => {
+        queryClient.invalidateQueries({ queryKey: providerHealthKeys.statuses() });
+        queryClient.invalidateQueries({ queryKey: providerHealthKeys.history(check.providerID) });
+      },
+    );
+    return unsubscribe;
+  }, [queryClient]);
+}
+
+// 获取所有 Provider / 客户端类型的当前健康状态
+export function useProviderHealth() {
+  useProviderHealthSubscription();
+
+  return useQuery({
+    queryKey: providerHealthKeys.statuses(),
+    queryFn: () => getTransport().getProviderHealth(),
+  });
+}
+
+// 获取单个 Provider 的探测历史（最新在前）
+export function useProviderHealthHistory(providerId: number, limit = 20) {
+  useProviderHealthSubscription();
+
+  return useQuery({
+    queryKey: providerHealthKeys.history(providerId),
+    queryFn: () => getTransport().getProviderHealthHistory(providerId, { limit }),
+    enabled: providerId > 0,
+  });
+}
+
+// 立即探测 Provider
+export function useCheckProviderHealth() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (providerId: number) => getTransport().checkProviderHealth(providerId),
+    onSuccess: (_, providerId) => {
+      queryClient.invalidateQueries({ queryKey: providerHealthKeys.statuses() });
+      queryClient.invalidateQueries({ queryKey: providerHealthKeys.history(providerId) });
+    },
+  });
+}
@@ -10,6 +10,7 @@ import {
   type ProxyUpstreamAttempt,
   type CursorPaginationParams,
   type CursorPaginationResult,
+  type ProxyRequestSearchParams,
 } from '@/lib/transport';
 
 // Query Keys
@@ -17,13 +18,40 @@ export const requestKeys = {
   all: ['requests'] as const,
   lists: () => [...requestKeys.all, 'list'] as const,
   list: (params?: CursorPaginationParams) => [...requestKeys.lists(), params] as const,
-  infinite: (providerId?: number, status?: string, apiTokenId?: number) =>
-    [...requestKeys.all, 'infinite', providerId, status, apiTokenId] as const,
+  infinite: (
+    providerId?: number,
+    status?: string,
+    apiTokenId?: number,
+    search?: ProxyRequestSearchParams,
+  ) => [...requestKeys.all, 'infinite', providerId, status, apiTokenId, search] as const,
   details: () => [...requestKeys.all, 'detail'] as const,
   detail: (id: number) => [...requestKeys.details(), id] as const,
   attempts: (id: number) => [...requestKeys.detail(id), 'attempts'] as const,
 };
 
+const SEARCH_PARAM_KEYS: (keyof ProxyRequestSearchParams)[] = [
+  'start',
+  'end',
+  'model',
+  'projectId',
+  'sessionId',
+  'clientType',
+  'statusCode',
+  'minCost',
+  'minDurationMs',
+  'minTokens',
+  'error',
+  'q',
+];
+
+// 是否包含高级过滤条件：全文检索等条件无法在前端判断，实时推送时只更新已有条目，不插入新条目
+function hasSearchParams(search?: ProxyRequestSearchParams) {
+  if (!search) {
+    return false;
+  }
+  return SEARCH_PARAM_KEYS.some((key) => search[key] !== undefined && search[key] !== '');
+}
+
 // 获取 ProxyRequests (游标分页)
 export function useProxyRequests(params?: CursorPaginationParams) {
   return useQuery({
@@ -33,11 +61,17 @@ export function useProxyRequests(params?: CursorPaginationParams) {
 }
 
 // 获取 ProxyRequests (无限滚动)
-export function useInfiniteProxyRequests(providerId?: number, status?: string, apiTokenId?: number) {
+export function useInfiniteProxyRequests(
+  providerId?: number,
+  status?: string,
+  apiTokenId?: number,
+  search?: ProxyRequestSearchParams,
+) {
   return useInfiniteQuery({
-    queryKey: requestKeys.infinite(providerId, status, apiTokenId),
+    queryKey: requestKeys.infinite(providerId, status, apiTokenId, search),
     queryFn: ({ pageParam }) =>
       getTransport().getProxyRequests({
+        ...search,
         limit: 100,
         before: pageParam,
         providerId,
@@ -50,10 +84,15 @@ export function useInfiniteProxyRequests(providerId?: number, status?: string, a
 }
 
 // 获取 ProxyRequests 总数
-export function useProxyRequestsCount(providerId?: number, status?: string, apiTokenId?: number) {
+export function useProxyRequestsCount(
+  providerId?: number,
+  status?: string,
+  apiTokenId?: number,
+  search?: ProxyRequestSearchParams,
+) {
   return useQuery({
-    queryKey: ['requestsCount', providerId, status, apiTokenId] as const,
-    queryFn: () => getTransport().getProxyRequestsCount(providerId, status, apiTokenId),
+    queryKey: ['requestsCount', providerId, status, apiTokenId, search] as const,
+    queryFn: () => getTransport().getProxyRequestsCount(providerId, status, apiTokenId, search),
   });
 }
 
@@ -187,6 +226,7 @@ export function useProxyRequestUpdates() {
           const filterProviderId = params?.providerId;
           const filterStatus = params?.status;
           const filterAPITokenId = params?.apiTokenId;
+          const searching = hasSearchParams(params);
 
           const matchesFilter = (request: ProxyRequest) => {
             if (filterProviderId !== undefined && request.providerID !== filterProviderId) {
@@ -238,7 +278,7 @@ export function useProxyRequestUpdates() {
               return normalizePage(newItems);
             }
 
-            if (!matchesFilter(updatedRequest)) {
+            if (!matchesFilter(updatedRequest) || searching) {
               return old;
             }
 
@@ -256,6 +296,7 @@ export function useProxyRequestUpdates() {
           const filterProviderId = queryKey[2] as number | undefined;
           const filterStatus = queryKey[3] as string | undefined;
           const filterAPITokenId = queryKey[4] as number | undefined;
+          const searching = hasSearchParams(queryKey[5] as ProxyRequestSearchParams | undefined);
 
           const matchesFilter = (request: ProxyRequest) => {
             if (filterProviderId !== undefined && request.providerID !== filterProviderId) {
@@ -301,7 +342,7 @@ export function useProxyRequestUpdates() {
               return { ...old, pages: updatedPages };
             }
 
-            if (!matchesFilter(updatedRequest)) {
+            if (!matchesFilter(updatedRequest) || searching) {
               return { ...old, pages: updatedPages };
             }
 
@@ -331,6 +372,9 @@ export function useProxyRequestUpdates() {
               const filterProviderId = query.queryKey[1] as number | undefined;
               const filterStatus = query.queryKey[2] as string | undefined;
               const filterAPITokenId = query.queryKey[3] as number | undefined;
+              if (hasSearchParams(query.queryKey[4] as ProxyRequestSearchParams | undefined)) {
+                continue;
+              }
               if (filterProviderId !== undefined && updatedRequest.providerID !== filterProviderId) {
                 continue;
               }
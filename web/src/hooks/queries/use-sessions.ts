@@ -2,7 +2,7 @@
  * Session React Query Hooks
  */
 
-import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { useQuery, useMutation, useQueryClient, useInfiniteQuery } from '@tanstack/react-query';
 import { getTransport } from '@/lib/transport';
 
 // Query Keys
@@ -10,6 +10,8 @@ export const sessionKeys = {
   all: ['sessions'] as const,
   lists: () => [...sessionKeys.all, 'list'] as const,
   list: () => [...sessionKeys.lists()] as const,
+  stats: (sessionID: string) => [...sessionKeys.all, 'stats', sessionID] as const,
+  timeline: (sessionID: string) => [...sessionKeys.all, 'timeline', sessionID] as const,
 };
 
 // 获取所有 Sessions
@@ -36,6 +38,27 @@ export function useUpdateSessionProject() {
   });
 }
 
+// 获取 Session 聚合统计
+export function useSessionStats(sessionID?: string) {
+  return useQuery({
+    queryKey: sessionKeys.stats(sessionID ?? ''),
+    queryFn: () => getTransport().getSessionStats(sessionID!),
+    enabled: !!sessionID,
+    retry: false,
+  });
+}
+
+// 获取 Session 对话时间线（按时间正序分页）
+export function useSessionTimeline(sessionID?: string) {
+  return useInfiniteQuery({
+    queryKey: sessionKeys.timeline(sessionID ?? ''),
+    queryFn: ({ pageParam }) => getTransport().getSessionTimeline(sessionID!, pageParam, 50),
+    getNextPageParam: (lastPage) => (lastPage.hasMore ? lastPage.lastId : undefined),
+    initialPageParam: undefined as number | undefined,
+    enabled: !!sessionID,
+  });
+}
+
 // 拒绝 Session
 export function useRejectSession() {
   const queryClient = useQueryClient();
@@ -47,3 +70,15 @@ export function useRejectSession() {
     },
   });
 }
+
+// 允许被循环检测拦截的 Session 继续
+export function useApproveSessionLoop() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (sessionID: string) => getTransport().approveSessionLoop(sessionID),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: sessionKeys.all });
+    },
+  });
+}
@@ -4,12 +4,20 @@
  */
 
 import { keepPreviousData, useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
-import { getTransport, type UsageStatsFilter, type StatsGranularity } from '@/lib/transport';
+import {
+  getTransport,
+  type UsageStatsFilter,
+  type StatsGranularity,
+  type ExportFormat,
+  type ReportSchedule,
+} from '@/lib/transport';
 
 // Query Keys
 export const usageStatsKeys = {
   all: ['usageStats'] as const,
   list: (filter?: UsageStatsFilter) => [...usageStatsKeys.all, filter] as const,
+  wasted: (filter?: UsageStatsFilter) => [...usageStatsKeys.all, 'wasted', filter] as const,
+  queue: () => [...usageStatsKeys.all, 'queue'] as const,
 };
 
 /**
@@ -121,6 +129,28 @@ export function useUsageStats(filter?: UsageStatsFilter) {
   });
 }
 
+/**
+ * 获取失败、取消或被重试取代的 attempt 消耗（按 Provider 和原因聚合）
+ */
+export function useWastedSpend(filter?: UsageStatsFilter) {
+  return useQuery({
+    queryKey: usageStatsKeys.wasted(filter),
+    queryFn: () => getTransport().getWastedSpend(filter),
+    placeholderData: keepPreviousData,
+  });
+}
+
+/**
+ * 获取准入队列统计（按优先级）
+ */
+export function useQueueStats() {
+  return useQuery({
+    queryKey: usageStatsKeys.queue(),
+    queryFn: () => getTransport().getQueueStats(),
+    refetchInterval: 5000,
+  });
+}
+
 /**
  * 使用预设时间范围获取统计数据
  */
@@ -169,3 +199,31 @@ export function useRecalculateCosts() {
     },
   });
 }
+
+/**
+ * 导出统计数据（CSV / Parquet），成功后触发浏览器下载
+ */
+export function useExportUsageStats() {
+  return useMutation({
+    mutationFn: async ({ filter, format }: { filter?: UsageStatsFilter; format: ExportFormat }) => {
+      const blob = await getTransport().exportUsageStats(filter, format);
+      const url = URL.createObjectURL(blob);
+      const a = document.createElement('a');
+      a.href = url;
+      a.download = `usage-stats-${new Date().toISOString().split('T')[0]}.${format}`;
+      document.body.appendChild(a);
+      a.click();
+      document.body.removeChild(a);
+      URL.revokeObjectURL(url);
+    },
+  });
+}
+
+/**
+ * 立即生成上一个完整周期的账单报表
+ */
+export function useRunBillingReport() {
+  return useMutation({
+    mutationFn: (schedule?: ReportSchedule) => getTransport().runBillingReport(schedule),
+  });
+}
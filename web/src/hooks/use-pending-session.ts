@@ -1,13 +1,20 @@
 import { useState, useEffect, useCallback } from 'react';
 import { getTransport } from '@/lib/transport';
-import type { NewSessionPendingEvent, SessionPendingCancelledEvent } from '@/lib/transport/types';
+import type {
+  NewSessionPendingEvent,
+  SessionLoopPendingEvent,
+  SessionPendingCancelledEvent,
+} from '@/lib/transport/types';
 
 /**
- * Hook to listen for new_session_pending events
- * Used for force project binding feature
+ * Hook to listen for new_session_pending and session_loop_pending events
+ * Used for force project binding and loop guard approval
  */
 export function usePendingSession() {
   const [pendingSession, setPendingSession] = useState<NewSessionPendingEvent | null>(null);
+  const [loopPendingSession, setLoopPendingSession] = useState<SessionLoopPendingEvent | null>(
+    null,
+  );
 
   useEffect(() => {
     const transport = getTransport();
@@ -19,6 +26,13 @@ export function usePendingSession() {
       },
     );
 
+    const unsubscribeLoopPending = transport.subscribe<SessionLoopPendingEvent>(
+      'session_loop_pending',
+      (event) => {
+        setLoopPendingSession(event);
+      },
+    );
+
     const unsubscribeCancelled = transport.subscribe<SessionPendingCancelledEvent>(
       'session_pending_cancelled',
       (event) => {
@@ -29,11 +43,18 @@ export function usePendingSession() {
           }
           return current;
         });
+        setLoopPendingSession((current) => {
+          if (current && current.sessionID === event.sessionID) {
+            return null;
+          }
+          return current;
+        });
       },
     );
 
     return () => {
       unsubscribePending();
+      unsubscribeLoopPending();
       unsubscribeCancelled();
     };
   }, []);
@@ -42,8 +63,14 @@ export function usePendingSession() {
     setPendingSession(null);
   }, []);
 
+  const clearLoopPendingSession = useCallback(() => {
+    setLoopPendingSession(null);
+  }, []);
+
   return {
     pendingSession,
     clearPendingSession,
+    loopPendingSession,
+    clearLoopPendingSession,
   };
 }
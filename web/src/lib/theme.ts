@@ -18,7 +18,12 @@ export type ProviderType =
   | 'custom'
   | 'antigravity'
   | 'kiro'
-  | 'codex';
+  | 'codex'
+  | 'bedrock'
+  | 'vertex'
+  | 'azure_openai'
+  | 'local'
+  | 'copilot';
 
 /**
  * Client 类型定义
@@ -11,10 +11,18 @@ import type {
   Project,
   CreateProjectData,
   Session,
+  SessionStats,
+  SessionTimeline,
   Route,
   CreateRouteData,
   RetryConfig,
   CreateRetryConfigData,
+  BillingRule,
+  CreateBillingRuleData,
+  CooldownPolicy,
+  CreateCooldownPolicyData,
+  ModelFallback,
+  CreateModelFallbackData,
   RoutingStrategy,
   CreateRoutingStrategyData,
   ProxyRequest,
@@ -22,6 +30,7 @@ import type {
   ProxyStatus,
   ProviderStats,
   CursorPaginationParams,
+  ProxyRequestSearchParams,
   CursorPaginationResult,
   WSMessageType,
   WSMessage,
@@ -36,6 +45,12 @@ import type {
   Cooldown,
   KiroTokenValidationResult,
   KiroQuotaData,
+  ProviderConfigLocal,
+  LocalDiscoverResult,
+  CopilotTokenValidationResult,
+  CopilotDeviceFlowStart,
+  CopilotUsageResponse,
+  CopilotQuotaData,
   CodexTokenValidationResult,
   CodexUsageResponse,
   CodexQuotaData,
@@ -47,15 +62,26 @@ import type {
   RoutePositionUpdate,
   UsageStats,
   UsageStatsFilter,
+  WastedSpend,
+  ExportFormat,
+  ReportSchedule,
+  BillingReportResult,
   RecalculateCostsResult,
   RecalculateRequestCostResult,
   DashboardData,
+  AnomalyAlert,
+  ProviderHealthCheck,
+  ProviderHealthStatus,
+  ClientType,
+  AdmissionQueueStats,
   BackupFile,
   BackupImportOptions,
   BackupImportResult,
   PriceTable,
   ModelPrice,
   ModelPriceInput,
+  ModelPriceImportInput,
+  ModelPriceImportResult,
 } from './types';
 
 export class HttpTransport implements Transport {
@@ -217,6 +243,41 @@ export class HttpTransport implements Transport {
     return data;
   }
 
+  async approveSessionLoop(sessionID: string): Promise<Session> {
+    const { data } = await this.client.post<Session>(
+      `/sessions/${encodeURIComponent(sessionID)}/approve`,
+    );
+    return data;
+  }
+
+  async getSessionStatsList(params?: {
+    limit?: number;
+    offset?: number;
+    projectId?: number;
+  }): Promise<SessionStats[]> {
+    const { data } = await this.client.get<SessionStats[]>('/sessions/stats', { params });
+    return data ?? [];
+  }
+
+  async getSessionStats(sessionID: string): Promise<SessionStats> {
+    const { data } = await this.client.get<SessionStats>(
+      `/sessions/${encodeURIComponent(sessionID)}/stats`,
+    );
+    return data;
+  }
+
+  async getSessionTimeline(
+    sessionID: string,
+    after?: number,
+    limit?: number,
+  ): Promise<SessionTimeline> {
+    const { data } = await this.client.get<SessionTimeline>(
+      `/sessions/${encodeURIComponent(sessionID)}/timeline`,
+      { params: { after, limit } },
+    );
+    return data ?? { sessionID, turns: [], hasMore: false };
+  }
+
   // ===== RetryConfig API =====
 
   async getRetryConfigs(): Promise<RetryConfig[]> {
@@ -243,6 +304,72 @@ export class HttpTransport implements Transport {
     await this.client.delete(`/retry-configs/${id}`);
   }
 
+  // ===== BillingRule API =====
+
+  async getBillingRules(): Promise<BillingRule[]> {
+    const { data } = await this.client.get<BillingRule[]>('/billing-rules');
+    return data ?? [];
+  }
+
+  async createBillingRule(payload: CreateBillingRuleData): Promise<BillingRule> {
+    const { data } = await this.client.post<BillingRule>('/billing-rules', payload);
+    return data;
+  }
+
+  async updateBillingRule(id: number, payload: CreateBillingRuleData): Promise<BillingRule> {
+    const { data } = await this.client.put<BillingRule>(`/billing-rules/${id}`, payload);
+    return data;
+  }
+
+  async deleteBillingRule(id: number): Promise<void> {
+    await this.client.delete(`/billing-rules/${id}`);
+  }
+
+  // ===== CooldownPolicy API =====
+
+  async getCooldownPolicies(): Promise<CooldownPolicy[]> {
+    const { data } = await this.client.get<CooldownPolicy[]>('/cooldown-policies');
+    return data ?? [];
+  }
+
+  async createCooldownPolicy(payload: CreateCooldownPolicyData): Promise<CooldownPolicy> {
+    const { data } = await this.client.post<CooldownPolicy>('/cooldown-policies', payload);
+    return data;
+  }
+
+  async updateCooldownPolicy(
+    id: number,
+    payload: CreateCooldownPolicyData,
+  ): Promise<CooldownPolicy> {
+    const { data } = await this.client.put<CooldownPolicy>(`/cooldown-policies/${id}`, payload);
+    return data;
+  }
+
+  async deleteCooldownPolicy(id: number): Promise<void> {
+    await this.client.delete(`/cooldown-policies/${id}`);
+  }
+
+  // ===== ModelFallback API =====
+
+  async getModelFallbacks(): Promise<ModelFallback[]> {
+    const { data } = await this.client.get<ModelFallback[]>('/model-fallbacks');
+    return data ?? [];
+  }
+
+  async createModelFallback(payload: CreateModelFallbackData): Promise<ModelFallback> {
+    const { data } = await this.client.post<ModelFallback>('/model-fallbacks', payload);
+    return data;
+  }
+
+  async updateModelFallback(id: number, payload: CreateModelFallbackData): Promise<ModelFallback> {
+    const { data } = await this.client.put<ModelFallback>(`/model-fallbacks/${id}`, payload);
+    return data;
+  }
+
+  async deleteModelFallback(id: number): Promise<void> {
+    await this.client.delete(`/model-fallbacks/${id}`);
+  }
+
   // ===== RoutingStrategy API =====
 
   async getRoutingStrategies(): Promise<RoutingStrategy[]> {
@@ -283,8 +410,18 @@ export class HttpTransport implements Transport {
     return data ?? { items: [], hasMore: false };
   }
 
-  async getProxyRequestsCount(providerId?: number, status?: string, apiTokenId?: number): Promise<number> {
+  async getProxyRequestsCount(
+    providerId?: number,
+    status?: string,
+    apiTokenId?: number,
+    search?: ProxyRequestSearchParams,
+  ): Promise<number> {
     const params: Record<string, string> = {};
+    for (const [key, value] of Object.entries(search ?? {})) {
+      if (value !== undefined && value !== '') {
+        params[key] = String(value);
+      }
+    }
     if (providerId !== undefined) {
       params.providerId = String(providerId);
     }
@@ -487,6 +624,58 @@ export class HttpTransport implements Transport {
     return data;
   }
 
+  // ===== Local API =====
+
+  async discoverLocalModels(config: ProviderConfigLocal): Promise<LocalDiscoverResult> {
+    const { data } = await this.client.post<LocalDiscoverResult>('/local/discover', config);
+    return data;
+  }
+
+  // ===== Copilot API =====
+
+  async validateCopilotToken(githubToken: string): Promise<CopilotTokenValidationResult> {
+    const { data } = await axios.post<CopilotTokenValidationResult>(
+      '/api/copilot/validate-token',
+      { githubToken },
+      { headers: this.authHeaders() },
+    );
+    return data;
+  }
+
+  async startCopilotDeviceFlow(): Promise<CopilotDeviceFlowStart> {
+    const { data } = await axios.post<CopilotDeviceFlowStart>('/api/copilot/device/start', null, {
+      headers: this.authHeaders(),
+    });
+    return data;
+  }
+
+  async cancelCopilotDeviceFlow(sessionId: string): Promise<void> {
+    await axios.post('/api/copilot/device/cancel', { sessionId }, { headers: this.authHeaders() });
+  }
+
+  async refreshCopilotProviderInfo(providerId: number): Promise<CopilotTokenValidationResult> {
+    const { data } = await axios.post<CopilotTokenValidationResult>(
+      `/api/copilot/provider/${providerId}/refresh`,
+      null,
+      { headers: this.authHeaders() },
+    );
+    return data;
+  }
+
+  async getCopilotProviderUsage(providerId: number): Promise<CopilotUsageResponse> {
+    const { data } = await axios.get<CopilotUsageResponse>(
+      `/api/copilot/provider/${providerId}/usage`,
+    );
+    return data;
+  }
+
+  async getCopilotBatchQuotas(): Promise<Record<number, CopilotQuotaData>> {
+    const { data } = await axios.get<{ quotas: Record<number, CopilotQuotaData> }>(
+      '/api/copilot/providers/quotas',
+    );
+    return data.quotas ?? {};
+  }
+
   // ===== Codex API =====
 
   async validateCodexToken(refreshToken: string): Promise<CodexTokenValidationResult> {
@@ -558,6 +747,31 @@ export class HttpTransport implements Transport {
     await this.client.put(`/cooldowns/${providerId}`, { untilTime, clientType });
   }
 
+  // ===== Provider Health API =====
+
+  async getProviderHealth(): Promise<ProviderHealthStatus[]> {
+    const { data } = await this.client.get<ProviderHealthStatus[]>('/provider-health');
+    return data ?? [];
+  }
+
+  async getProviderHealthHistory(
+    providerId: number,
+    params?: { clientType?: ClientType; limit?: number },
+  ): Promise<ProviderHealthCheck[]> {
+    const { data } = await this.client.get<ProviderHealthCheck[]>(
+      `/provider-health/${providerId}`,
+      { params },
+    );
+    return data ?? [];
+  }
+
+  async checkProviderHealth(providerId: number): Promise<ProviderHealthCheck[]> {
+    const { data } = await this.client.post<ProviderHealthCheck[]>(
+      `/provider-health/${providerId}`,
+    );
+    return data ?? [];
+  }
+
   // ===== Auth API =====
 
   async getAuthStatus(): Promise<AuthStatus> {
@@ -578,6 +792,11 @@ export class HttpTransport implements Transport {
     this.authToken = null;
   }
 
+  // 管理员认证头，用于 /api/admin 之外需要认证的接口
+  private authHeaders(): Record<string, string> {
+    return this.authToken ? { Authorization: `Bearer ${this.authToken}` } : {};
+  }
+
   // ===== API Token API =====
 
   async getAPITokens(): Promise<APIToken[]> {
@@ -606,7 +825,7 @@ export class HttpTransport implements Transport {
 
   // ===== Usage Stats API =====
 
-  async getUsageStats(filter?: UsageStatsFilter): Promise<UsageStats[]> {
+  private usageStatsParams(filter?: UsageStatsFilter): URLSearchParams {
     const params = new URLSearchParams();
     if (filter?.granularity) params.set('granularity', filter.granularity);
     if (filter?.start) params.set('start', filter.start);
@@ -617,13 +836,40 @@ export class HttpTransport implements Transport {
     if (filter?.clientType) params.set('clientType', filter.clientType);
     if (filter?.apiTokenId) params.set('apiTokenId', String(filter.apiTokenId));
     if (filter?.model) params.set('model', filter.model);
+    return params;
+  }
 
-    const query = params.toString();
+  async getUsageStats(filter?: UsageStatsFilter): Promise<UsageStats[]> {
+    const query = this.usageStatsParams(filter).toString();
     const url = query ? `/usage-stats?${query}` : '/usage-stats';
     const { data } = await this.client.get<UsageStats[]>(url);
     return data ?? [];
   }
 
+  async getWastedSpend(filter?: UsageStatsFilter): Promise<WastedSpend[]> {
+    const query = this.usageStatsParams(filter).toString();
+    const url = query ? `/usage-stats/wasted-spend?${query}` : '/usage-stats/wasted-spend';
+    const { data } = await this.client.get<WastedSpend[]>(url);
+    return data ?? [];
+  }
+
+  async exportUsageStats(
+    filter: UsageStatsFilter | undefined,
+    format: ExportFormat,
+  ): Promise<Blob> {
+    const params = this.usageStatsParams(filter);
+    params.set('format', format);
+    const { data } = await this.client.get<Blob>(`/usage-stats/export?${params.toString()}`, {
+      responseType: 'blob',
+    });
+    return data;
+  }
+
+  async runBillingReport(schedule?: ReportSchedule): Promise<BillingReportResult> {
+    const { data } = await this.client.post<BillingReportResult>('/reports/run', { schedule });
+    return data;
+  }
+
   async recalculateUsageStats(): Promise<void> {
     await this.client.post('/usage-stats/recalculate');
   }
@@ -649,6 +895,16 @@ export class HttpTransport implements Transport {
     return data;
   }
 
+  async getAnomalyAlerts(): Promise<AnomalyAlert[]> {
+    const { data } = await this.client.get<AnomalyAlert[]>('/anomaly-alerts');
+    return data ?? [];
+  }
+
+  async getQueueStats(): Promise<AdmissionQueueStats> {
+    const { data } = await this.client.get<AdmissionQueueStats>('/queue-stats');
+    return data;
+  }
+
   // ===== Response Model API =====
 
   async getResponseModels(): Promise<string[]> {
@@ -715,6 +971,11 @@ export class HttpTransport implements Transport {
     return data;
   }
 
+  async importModelPrices(input: ModelPriceImportInput): Promise<ModelPriceImportResult> {
+    const { data } = await this.client.post<ModelPriceImportResult>('/model-prices/import', input);
+    return data;
+  }
+
   // ===== WebSocket 订阅 =====
 
   subscribe<T = unknown>(eventType: WSMessageType, callback: EventCallback<T>): UnsubscribeFn {
@@ -8,10 +8,22 @@ export type {
   ClientType,
   Provider,
   ProviderConfig,
+  ProviderConfigNetwork,
+  ProviderConfigHealthCheck,
+  ProviderConfigCircuitBreaker,
+  CooldownPolicyConfig,
+  CooldownPolicyType,
   ProviderConfigCustom,
   ProviderConfigAntigravity,
+  ProviderConfigBedrock,
+  ProviderConfigVertex,
+  ProviderConfigAzureOpenAI,
+  ProviderConfigLocal,
+  LocalBackend,
+  ProviderConfigCopilot,
   CreateProviderData,
   Project,
+  PriorityClass,
   CreateProjectData,
   Session,
   Route,
@@ -19,6 +31,8 @@ export type {
   RoutePositionUpdate,
   RetryConfig,
   CreateRetryConfigData,
+  BillingRule,
+  CreateBillingRuleData,
   RoutingStrategy,
   RoutingStrategyType,
   RoutingStrategyConfig,
@@ -33,6 +47,12 @@ export type {
   // 分页
   PaginationParams,
   CursorPaginationParams,
+  SessionStats,
+  SessionTurn,
+  SessionTimeline,
+  ConversationMessage,
+  ConversationPart,
+  ProxyRequestSearchParams,
   CursorPaginationResult,
   // WebSocket
   WSMessageType,
@@ -54,6 +74,14 @@ export type {
   // Kiro
   KiroTokenValidationResult,
   KiroQuotaData,
+  LocalDiscoverResult,
+  // Copilot
+  CopilotTokenValidationResult,
+  CopilotDeviceFlowStart,
+  CopilotDeviceFlowResult,
+  CopilotQuotaSnapshot,
+  CopilotUsageResponse,
+  CopilotQuotaData,
   // Codex
   ProviderConfigCodex,
   CodexTokenValidationResult,
@@ -66,13 +94,25 @@ export type {
   ImportResult,
   // Cooldown
   Cooldown,
+  CooldownReason,
+  CooldownPolicy,
+  CreateCooldownPolicyData,
+  // Model Fallback
+  ModelFallback,
+  CreateModelFallbackData,
   // API Token
   APIToken,
   APITokenCreateResult,
+  APITokenScopeData,
   CreateAPITokenData,
   // Usage Stats
   UsageStats,
   UsageStatsFilter,
+  WastedSpend,
+  ExportFormat,
+  ReportSchedule,
+  BillingReportFile,
+  BillingReportResult,
   StatsGranularity,
   RecalculateRequestCostResult,
   RecalculateCostsResult,
@@ -80,6 +120,11 @@ export type {
   RecalculateStatsProgress,
   // Dashboard
   DashboardData,
+  AnomalyAlert,
+  ProviderHealthCheck,
+  ProviderHealthStatus,
+  AdmissionQueueStats,
+  AdmissionClassStats,
   DashboardDaySummary,
   DashboardAllTimeSummary,
   DashboardHeatmapPoint,
@@ -91,6 +136,9 @@ export type {
   PriceTable,
   ModelPrice,
   ModelPriceInput,
+  ModelPriceImportInput,
+  ModelPriceImportResult,
+  PriceSheetFormat,
 } from './types';
 
 export type { Transport, TransportType, TransportConfig } from './interface';
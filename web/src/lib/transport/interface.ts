@@ -9,15 +9,24 @@ import type {
   Project,
   CreateProjectData,
   Session,
+  SessionStats,
+  SessionTimeline,
   Route,
   CreateRouteData,
   RetryConfig,
   CreateRetryConfigData,
+  BillingRule,
+  CreateBillingRuleData,
+  CooldownPolicy,
+  CreateCooldownPolicyData,
+  ModelFallback,
+  CreateModelFallbackData,
   RoutingStrategy,
   CreateRoutingStrategyData,
   ProxyRequest,
   ProxyUpstreamAttempt,
   CursorPaginationParams,
+  ProxyRequestSearchParams,
   CursorPaginationResult,
   ProxyStatus,
   ProviderStats,
@@ -33,6 +42,12 @@ import type {
   Cooldown,
   KiroTokenValidationResult,
   KiroQuotaData,
+  ProviderConfigLocal,
+  LocalDiscoverResult,
+  CopilotTokenValidationResult,
+  CopilotDeviceFlowStart,
+  CopilotUsageResponse,
+  CopilotQuotaData,
   CodexTokenValidationResult,
   CodexUsageResponse,
   CodexQuotaData,
@@ -45,15 +60,26 @@ import type {
   RoutePositionUpdate,
   UsageStats,
   UsageStatsFilter,
+  WastedSpend,
+  ExportFormat,
+  ReportSchedule,
+  BillingReportResult,
   RecalculateCostsResult,
   RecalculateRequestCostResult,
   DashboardData,
+  AnomalyAlert,
+  ProviderHealthCheck,
+  ProviderHealthStatus,
+  ClientType,
+  AdmissionQueueStats,
   BackupFile,
   BackupImportOptions,
   BackupImportResult,
   PriceTable,
   ModelPrice,
   ModelPriceInput,
+  ModelPriceImportInput,
+  ModelPriceImportResult,
 } from './types';
 
 /**
@@ -92,6 +118,14 @@ export interface Transport {
     projectID: number,
   ): Promise<{ session: Session; updatedRequests: number }>;
   rejectSession(sessionID: string): Promise<Session>;
+  approveSessionLoop(sessionID: string): Promise<Session>;
+  getSessionStatsList(params?: {
+    limit?: number;
+    offset?: number;
+    projectId?: number;
+  }): Promise<SessionStats[]>;
+  getSessionStats(sessionID: string): Promise<SessionStats>;
+  getSessionTimeline(sessionID: string, after?: number, limit?: number): Promise<SessionTimeline>;
 
   // ===== RetryConfig API =====
   getRetryConfigs(): Promise<RetryConfig[]>;
@@ -100,6 +134,24 @@ export interface Transport {
   updateRetryConfig(id: number, data: Partial<RetryConfig>): Promise<RetryConfig>;
   deleteRetryConfig(id: number): Promise<void>;
 
+  // ===== BillingRule API =====
+  getBillingRules(): Promise<BillingRule[]>;
+  createBillingRule(data: CreateBillingRuleData): Promise<BillingRule>;
+  updateBillingRule(id: number, data: CreateBillingRuleData): Promise<BillingRule>;
+  deleteBillingRule(id: number): Promise<void>;
+
+  // ===== CooldownPolicy API =====
+  getCooldownPolicies(): Promise<CooldownPolicy[]>;
+  createCooldownPolicy(data: CreateCooldownPolicyData): Promise<CooldownPolicy>;
+  updateCooldownPolicy(id: number, data: CreateCooldownPolicyData): Promise<CooldownPolicy>;
+  deleteCooldownPolicy(id: number): Promise<void>;
+
+  // ===== ModelFallback API =====
+  getModelFallbacks(): Promise<ModelFallback[]>;
+  createModelFallback(data: CreateModelFallbackData): Promise<ModelFallback>;
+  updateModelFallback(id: number, data: CreateModelFallbackData): Promise<ModelFallback>;
+  deleteModelFallback(id: number): Promise<void>;
+
   // ===== RoutingStrategy API =====
   getRoutingStrategies(): Promise<RoutingStrategy[]>;
   getRoutingStrategy(id: number): Promise<RoutingStrategy>;
@@ -109,7 +161,12 @@ export interface Transport {
 
   // ===== ProxyRequest API (只读) =====
   getProxyRequests(params?: CursorPaginationParams): Promise<CursorPaginationResult<ProxyRequest>>;
-  getProxyRequestsCount(providerId?: number, status?: string, apiTokenId?: number): Promise<number>;
+  getProxyRequestsCount(
+    providerId?: number,
+    status?: string,
+    apiTokenId?: number,
+    search?: ProxyRequestSearchParams,
+  ): Promise<number>;
   getActiveProxyRequests(): Promise<ProxyRequest[]>;
   getProxyRequest(id: number): Promise<ProxyRequest>;
   getProxyUpstreamAttempts(proxyRequestId: number): Promise<ProxyUpstreamAttempt[]>;
@@ -157,6 +214,17 @@ export interface Transport {
   validateKiroSocialToken(refreshToken: string): Promise<KiroTokenValidationResult>;
   getKiroProviderQuota(providerId: number): Promise<KiroQuotaData>;
 
+  // ===== Local API =====
+  discoverLocalModels(config: ProviderConfigLocal): Promise<LocalDiscoverResult>;
+
+  // ===== Copilot API =====
+  validateCopilotToken(githubToken: string): Promise<CopilotTokenValidationResult>;
+  startCopilotDeviceFlow(): Promise<CopilotDeviceFlowStart>;
+  cancelCopilotDeviceFlow(sessionId: string): Promise<void>;
+  refreshCopilotProviderInfo(providerId: number): Promise<CopilotTokenValidationResult>;
+  getCopilotProviderUsage(providerId: number): Promise<CopilotUsageResponse>;
+  getCopilotBatchQuotas(): Promise<Record<number, CopilotQuotaData>>;
+
   // ===== Codex API =====
   validateCodexToken(refreshToken: string): Promise<CodexTokenValidationResult>;
   startCodexOAuth(): Promise<{ authURL: string; state: string }>;
@@ -172,6 +240,14 @@ export interface Transport {
   clearCooldown(providerId: number): Promise<void>;
   setCooldown(providerId: number, untilTime: string, clientType?: string): Promise<void>;
 
+  // ===== Provider Health API =====
+  getProviderHealth(): Promise<ProviderHealthStatus[]>;
+  getProviderHealthHistory(
+    providerId: number,
+    params?: { clientType?: ClientType; limit?: number },
+  ): Promise<ProviderHealthCheck[]>;
+  checkProviderHealth(providerId: number): Promise<ProviderHealthCheck[]>;
+
   // ===== Auth API =====
   getAuthStatus(): Promise<AuthStatus>;
   verifyPassword(password: string): Promise<AuthVerifyResult>;
@@ -187,12 +263,17 @@ export interface Transport {
 
   // ===== Usage Stats API =====
   getUsageStats(filter?: UsageStatsFilter): Promise<UsageStats[]>;
+  getWastedSpend(filter?: UsageStatsFilter): Promise<WastedSpend[]>;
+  exportUsageStats(filter: UsageStatsFilter | undefined, format: ExportFormat): Promise<Blob>;
+  runBillingReport(schedule?: ReportSchedule): Promise<BillingReportResult>;
   recalculateUsageStats(): Promise<void>;
   recalculateCosts(): Promise<RecalculateCostsResult>;
   recalculateRequestCost(requestId: number): Promise<RecalculateRequestCostResult>;
 
   // ===== Dashboard API =====
   getDashboardData(): Promise<DashboardData>;
+  getAnomalyAlerts(): Promise<AnomalyAlert[]>;
+  getQueueStats(): Promise<AdmissionQueueStats>;
 
   // ===== Response Model API =====
   getResponseModels(): Promise<string[]>;
@@ -211,6 +292,7 @@ export interface Transport {
   updateModelPrice(id: number, data: ModelPriceInput): Promise<ModelPrice>;
   deleteModelPrice(id: number): Promise<void>;
   resetModelPricesToDefaults(): Promise<ModelPrice[]>;
+  importModelPrices(input: ModelPriceImportInput): Promise<ModelPriceImportResult>;
 
   // ===== 实时订阅 =====
   subscribe<T = unknown>(eventType: WSMessageType, callback: EventCallback<T>): UnsubscribeFn;
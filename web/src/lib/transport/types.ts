@@ -57,12 +57,110 @@ export interface ProviderConfigCodex {
   useCLIProxyAPI?: boolean;
 }
 
+export interface ProviderConfigBedrock {
+  region?: string; // 默认 us-east-1
+  accessKeyID: string;
+  secretAccessKey: string;
+  sessionToken?: string;
+  roleARN?: string; // 设置后通过 STS AssumeRole 获取临时凭证
+  externalID?: string;
+  endpoint?: string; // 自定义 Endpoint，如 VPC Endpoint
+  inferenceProfilePrefix?: string; // 跨区域推理配置前缀: us / eu / apac / global
+  modelMapping?: Record<string, string>;
+}
+
+export interface ProviderConfigVertex {
+  projectID?: string; // 为空时使用服务账号 JSON 中的 project_id
+  region?: string; // 默认 global
+  credentials: string; // 服务账号 JSON 密钥
+  endpoint?: string;
+  modelMapping?: Record<string, string>;
+}
+
+export interface ProviderConfigAzureOpenAI {
+  endpoint: string; // https://{resource}.openai.azure.com
+  apiVersion?: string; // 默认 2025-04-01-preview，设为 v1 使用 /openai/v1/ 路径
+  apiKey?: string; // 为空时使用 Entra ID 客户端凭证
+  tenantID?: string;
+  clientID?: string;
+  clientSecret?: string;
+  deployments?: Record<string, string>; // 模型名 -> 部署名，支持 * 通配
+}
+
+export type LocalBackend = 'ollama' | 'openai';
+
+export interface ProviderConfigLocal {
+  baseURL: string; // 如 http://localhost:11434
+  backend?: LocalBackend; // ollama 使用原生 /api/chat，openai 使用 /v1/chat/completions（默认）
+  apiKey?: string;
+  inputPriceMicro?: number; // microUSD/M tokens，默认 0 即免费
+  outputPriceMicro?: number;
+  modelMapping?: Record<string, string>;
+}
+
+export interface ProviderConfigCopilot {
+  username: string; // GitHub 用户名
+  name?: string;
+  avatarURL?: string;
+  githubToken: string; // device flow 获取的 GitHub token
+  copilotToken?: string; // 由 GitHub token 交换，自动刷新
+  expiresAt?: string; // RFC3339 format
+  apiEndpoint?: string;
+  plan?: string; // individual, business, enterprise 等
+  modelMapping?: Record<string, string>;
+}
+
+// 上游网络设置，超时单位为秒
+export interface ProviderConfigNetwork {
+  proxyURL?: string; // http(s)://, socks5://, socks5h:// 或 "direct"；为空时使用环境变量
+  caCert?: string; // PEM
+  insecureSkipVerify?: boolean;
+  connectTimeoutSeconds?: number;
+  readTimeoutSeconds?: number;
+  idleTimeoutSeconds?: number;
+}
+
+// 主动健康检查：定期通过适配器发送极小的探测请求，失败时冷冻，成功时解除冷冻
+export interface ProviderConfigHealthCheck {
+  enabled: boolean;
+  intervalSeconds?: number; // 默认 300
+  timeoutSeconds?: number; // 默认 30
+  clientTypes?: ClientType[]; // 为空时使用引用该 Provider 的路由的客户端类型
+  models?: Partial<Record<ClientType, string>>;
+  prompt?: string; // 默认 "ping"
+  maxTokens?: number; // 默认 1
+  failureThreshold?: number; // 连续失败多少次后冷冻，默认 1
+}
+
+export type CooldownPolicyType = 'fixed' | 'linear' | 'exponential';
+
+// 冷冻时长策略：fixed 固定 baseSeconds，linear 为 base * n，exponential 为 base * 2^(n-1)
+export interface CooldownPolicyConfig {
+  type: CooldownPolicyType;
+  baseSeconds: number;
+  maxSeconds?: number; // 0 表示不限制
+}
+
+// 熔断：冷冻结束后进入半开状态，只放行少量试探请求，成功后恢复，失败则重新冷冻
+export interface ProviderConfigCircuitBreaker {
+  halfOpenMaxRequests?: number; // 默认 1
+  successThreshold?: number; // 默认 1
+}
+
 export interface ProviderConfig {
   disableErrorCooldown?: boolean;
+  network?: ProviderConfigNetwork;
+  healthCheck?: ProviderConfigHealthCheck;
+  circuitBreaker?: ProviderConfigCircuitBreaker;
   custom?: ProviderConfigCustom;
   antigravity?: ProviderConfigAntigravity;
   kiro?: ProviderConfigKiro;
   codex?: ProviderConfigCodex;
+  bedrock?: ProviderConfigBedrock;
+  vertex?: ProviderConfigVertex;
+  azureOpenAI?: ProviderConfigAzureOpenAI;
+  local?: ProviderConfigLocal;
+  copilot?: ProviderConfigCopilot;
 }
 
 export interface Provider {
@@ -88,6 +186,9 @@ export type CreateProviderData = Omit<
 
 // ===== Project =====
 
+/** 准入队列优先级，空值表示 normal */
+export type PriorityClass = '' | 'interactive' | 'normal' | 'batch';
+
 export interface Project {
   id: number;
   createdAt: string;
@@ -95,6 +196,7 @@ export interface Project {
   name: string;
   slug: string;
   enabledCustomRoutes: ClientType[];
+  priority?: PriorityClass;
 }
 
 export type CreateProjectData = Omit<Project, 'id' | 'createdAt' | 'updatedAt' | 'slug'> & {
@@ -112,6 +214,70 @@ export interface Session {
   projectID: number;
 }
 
+/** 会话聚合统计 */
+export interface SessionStats {
+  sessionID: string;
+  clientType: ClientType;
+  projectID: number;
+  turnCount: number;
+  failureCount: number;
+  inputTokenCount: number;
+  outputTokenCount: number;
+  cacheReadCount: number;
+  cacheWriteCount: number;
+  cost: number; // nanoUSD
+  providerIDs: number[];
+  firstSeen: string;
+  lastSeen: string;
+}
+
+export type ConversationPartType = 'text' | 'thinking' | 'image' | 'tool_call' | 'tool_result';
+
+/** 归一化后的消息内容，与客户端类型无关 */
+export interface ConversationPart {
+  type: ConversationPartType;
+  text?: string;
+  toolCallId?: string;
+  toolName?: string;
+  input?: string;
+  isError?: boolean;
+}
+
+export interface ConversationMessage {
+  role: 'system' | 'user' | 'assistant';
+  parts: ConversationPart[];
+}
+
+/** 会话中的一轮请求 */
+export interface SessionTurn {
+  proxyRequestID: number;
+  startTime: string;
+  clientType: ClientType;
+  requestModel: string;
+  responseModel: string;
+  providerID: number;
+  status: ProxyRequestStatus;
+  statusCode: number;
+  duration: number; // nanoseconds
+  inputTokenCount: number;
+  outputTokenCount: number;
+  cost: number;
+  error?: string;
+  /** 仅在本页第一轮或发生变化时返回 */
+  system?: string;
+  /** 上一条 assistant 消息之后新增的消息 */
+  input: ConversationMessage[];
+  output: ConversationMessage[];
+  parseError?: string;
+}
+
+export interface SessionTimeline {
+  sessionID: string;
+  turns: SessionTurn[];
+  hasMore: boolean;
+  lastId?: number;
+}
+
 // ===== Route =====
 
 export interface Route {
@@ -151,6 +317,24 @@ export interface RetryConfig {
 
 export type CreateRetryConfigData = Omit<RetryConfig, 'id' | 'createdAt' | 'updatedAt'>;
 
+// ===== BillingRule =====
+
+/** 计费规则：项目或 API Token 维度的加价、固定费用和免费额度 */
+export interface BillingRule {
+  id: number;
+  createdAt: string;
+  updatedAt: string;
+  name: string;
+  projectID: number; // 与 apiTokenID 二选一
+  apiTokenID: number;
+  markupBps: number; // 加价比例（万分比，2000 = +20%）
+  requestFee: number; // 每个成功请求的固定费用（纳美元）
+  freeTierMonthly: number; // 每月免费额度（纳美元）
+  isEnabled: boolean;
+}
+
+export type CreateBillingRuleData = Omit<BillingRule, 'id' | 'createdAt' | 'updatedAt'>;
+
 // ===== RoutingStrategy =====
 
 export type RoutingStrategyType = 'priority' | 'weighted_random';
@@ -177,12 +361,14 @@ export interface RequestInfo {
   headers: Record<string, string>;
   url: string;
   body: string;
+  bodyRef?: string;
 }
 
 export interface ResponseInfo {
   status: number;
   headers: Record<string, string>;
   body: string;
+  bodyRef?: string;
 }
 
 export type ProxyRequestStatus =
@@ -229,6 +415,8 @@ export interface ProxyRequest {
   modelPriceId: number; // 使用的模型价格记录ID
   multiplier: number; // 倍率（10000=1倍）
   cost: number;
+  upstreamCost: number; // 上游真实成本（未应用倍率）
+  billedCost: number; // 按计费规则计费的金额
   // API Token ID
   apiTokenID: number;
 }
@@ -257,6 +445,8 @@ export interface ProxyUpstreamAttempt {
   requestModel: string; // 客户端请求的原始模型
   mappedModel: string; // 映射后实际发送的模型
   responseModel: string; // 上游响应中返回的模型名称
+  fallbackStep?: number; // 降级链步骤，0 表示原始模型
+  fallbackModel?: string; // 降级后用于路由的模型
   requestInfo: RequestInfo | null;
   responseInfo: ResponseInfo | null;
   routeID: number;
@@ -271,6 +461,9 @@ export interface ProxyUpstreamAttempt {
   modelPriceId: number; // 使用的模型价格记录ID
   multiplier: number; // 倍率（10000=1倍）
   cost: number;
+  upstreamCost: number;
+  billedCost: number;
+  failureReason?: string; // FAILED 时为冷却原因，CANCELLED 时为 cancelled
 }
 
 // ===== 分页 =====
@@ -281,7 +474,30 @@ export interface PaginationParams {
 }
 
 /** 基于游标的分页参数 (用于大数据量场景) */
-export interface CursorPaginationParams {
+/** 请求历史的高级过滤条件 */
+export interface ProxyRequestSearchParams {
+  /** 起始时间 (RFC3339) */
+  start?: string;
+  /** 结束时间 (RFC3339) */
+  end?: string;
+  /** 匹配请求模型或响应模型 */
+  model?: string;
+  projectId?: number;
+  sessionId?: string;
+  clientType?: ClientType;
+  statusCode?: number;
+  /** 最低成本 (nanoUSD) */
+  minCost?: number;
+  minDurationMs?: number;
+  /** 最低 token 数 (输入 + 输出) */
+  minTokens?: number;
+  /** 错误信息包含的子串 */
+  error?: string;
+  /** 对请求/响应内容的全文检索 */
+  q?: string;
+}
+
+export interface CursorPaginationParams extends ProxyRequestSearchParams {
   limit?: number;
   /** 获取 id 小于此值的记录 (向后翻页) */
   before?: number;
@@ -314,11 +530,15 @@ export type WSMessageType =
   | 'log_message'
   | 'antigravity_oauth_result'
   | 'codex_oauth_result'
+  | 'copilot_device_result'
   | 'new_session_pending'
   | 'session_pending_cancelled'
+  | 'session_loop_pending'
   | 'cooldown_update'
   | 'recalculate_costs_progress'
   | 'recalculate_stats_progress'
+  | 'anomaly_alert'
+  | 'provider_health_update'
   | '_ws_reconnected'; // 内部事件：WebSocket 重连成功
 
 export interface WSMessage<T = unknown> {
@@ -333,6 +553,15 @@ export interface NewSessionPendingEvent {
   createdAt: string;
 }
 
+// Session loop pending event (loop guard tripped, waiting for approval)
+export interface SessionLoopPendingEvent {
+  sessionID: string;
+  clientType: ClientType;
+  reason: 'repetition' | 'token_limit' | 'cost_limit';
+  message: string;
+  createdAt: string;
+}
+
 // Session pending cancelled event (client disconnected)
 export interface SessionPendingCancelledEvent {
   sessionID: string;
@@ -484,6 +713,80 @@ export interface KiroQuotaData {
   last_updated: number;
 }
 
+// ===== Local 类型 =====
+
+export interface LocalDiscoverResult {
+  models: string[];
+}
+
+// ===== Copilot 类型 =====
+
+export interface CopilotTokenValidationResult {
+  valid: boolean;
+  error?: string;
+  username?: string;
+  name?: string;
+  avatarURL?: string;
+  plan?: string;
+  githubToken?: string;
+  copilotToken?: string;
+  expiresAt?: string; // RFC3339 format
+  apiEndpoint?: string;
+}
+
+export interface CopilotDeviceFlowStart {
+  sessionId: string;
+  userCode: string;
+  verificationUri: string;
+  expiresIn: number; // 秒
+  interval: number; // 秒
+}
+
+// 通过 WebSocket copilot_device_result 推送
+export interface CopilotDeviceFlowResult {
+  sessionId: string;
+  success: boolean;
+  githubToken?: string;
+  copilotToken?: string;
+  expiresAt?: string; // RFC3339 format
+  apiEndpoint?: string;
+  username?: string;
+  name?: string;
+  avatarURL?: string;
+  plan?: string;
+  error?: string;
+}
+
+export interface CopilotQuotaSnapshot {
+  entitlement: number;
+  remaining: number;
+  percentRemaining: number;
+  unlimited: boolean;
+  overageCount: number;
+  overagePermitted: boolean;
+}
+
+export interface CopilotUsageResponse {
+  login?: string;
+  plan?: string;
+  resetDate?: string; // YYYY-MM-DD
+  chat?: CopilotQuotaSnapshot;
+  completions?: CopilotQuotaSnapshot;
+  premiumInteractions?: CopilotQuotaSnapshot;
+}
+
+// Copilot quota data (for batch API response)
+export interface CopilotQuotaData {
+  username: string;
+  plan?: string;
+  resetDate?: string;
+  isForbidden: boolean;
+  lastUpdated: number; // Unix timestamp
+  chat?: CopilotQuotaSnapshot;
+  completions?: CopilotQuotaSnapshot;
+  premiumInteractions?: CopilotQuotaSnapshot;
+}
+
 // ===== Codex 类型 =====
 
 export interface CodexTokenValidationResult {
@@ -590,14 +893,71 @@ export interface Cooldown {
   updatedAt: string;
   providerID: number;
   clientType: string; // 'all' for global cooldown, or specific client type
+  model?: string; // 只冷冻该模型（或通配模型族，如 claude-*），为空表示冷冻所有模型
   until: string; // ISO 8601 timestamp (Go time.Time)
   reason: CooldownReason;
 }
 
+/** 冷冻时长策略：providerID 为 0 是全局默认，否则只覆盖该 Provider（优先级：Provider > 全局 > 内置） */
+export interface CooldownPolicy extends CooldownPolicyConfig {
+  id: number;
+  createdAt: string;
+  updatedAt: string;
+  providerID: number;
+  reason: CooldownReason;
+}
+
+export type CreateCooldownPolicyData = Omit<CooldownPolicy, 'id' | 'createdAt' | 'updatedAt'>;
+
+// ===== 模型降级链 =====
+
+/** 当前模型的路由全部失败或冷冻时，按 models 顺序降级（按 priority 取第一条匹配的规则） */
+export interface ModelFallback {
+  id: number;
+  createdAt: string;
+  updatedAt: string;
+  clientType?: ClientType | ''; // 为空表示所有客户端
+  projectID: number; // 0 表示所有项目
+  pattern: string; // 请求模型匹配模式，支持通配符，如 claude-opus-*
+  models: string[];
+  notifyClient: boolean; // 通过 X-Maxx-Fallback-Model 响应头告知客户端
+  priority: number;
+}
+
+export type CreateModelFallbackData = Omit<ModelFallback, 'id' | 'createdAt' | 'updatedAt'>;
+
+// ===== Provider 健康探测 =====
+
+export interface ProviderHealthCheck {
+  id: number;
+  createdAt: string;
+  providerID: number;
+  clientType: ClientType;
+  model: string;
+  success: boolean;
+  statusCode?: number;
+  latency: number; // 纳秒
+  error?: string;
+  reason?: CooldownReason;
+  cooldownUntil?: string; // 探测失败后施加的冷冻结束时间
+}
+
+export interface ProviderHealthStatus {
+  providerID: number;
+  providerName: string;
+  clientType: ClientType;
+  healthy: boolean;
+  consecutiveFailures: number;
+  lastCheck: ProviderHealthCheck;
+  nextCheckAt: string;
+}
+
 // ===== Auth 相关 =====
 
 export interface AuthStatus {
   authEnabled: boolean;
+  passwordEnabled?: boolean;
+  oidcEnabled?: boolean;
 }
 
 export interface AuthVerifyResult {
@@ -619,9 +979,15 @@ export interface APIToken {
   projectID: number;
   isEnabled: boolean;
   devMode: boolean;
+  priority?: PriorityClass; // 为空时继承项目优先级
   expiresAt?: string;
   lastUsedAt?: string;
   useCount: number;
+  allowedClientTypes?: ClientType[];
+  allowedModels?: string[]; // 支持通配符
+  allowedProviderIDs?: number[];
+  allowedIPs?: string[]; // IP 或 CIDR
+  projectAdmin: boolean; // 可通过 /api/project/tokens 管理所属项目的 Token
 }
 
 export interface APITokenCreateResult {
@@ -629,11 +995,20 @@ export interface APITokenCreateResult {
   apiToken: APIToken;
 }
 
-export interface CreateAPITokenData {
+export interface APITokenScopeData {
+  allowedClientTypes?: ClientType[];
+  allowedModels?: string[];
+  allowedProviderIDs?: number[];
+  allowedIPs?: string[];
+  projectAdmin?: boolean;
+}
+
+export interface CreateAPITokenData extends APITokenScopeData {
   name: string;
   description?: string;
   projectID?: number;
   expiresAt?: string;
+  priority?: PriorityClass;
 }
 
 // ===== Usage Stats =====
@@ -662,6 +1037,8 @@ export interface UsageStats {
   cacheRead: number;
   cacheWrite: number;
   cost: number;
+  upstreamCost: number;
+  billedCost: number;
 }
 
 /** 统计数据汇总 */
@@ -675,6 +1052,9 @@ export interface UsageStatsSummary {
   totalCacheRead: number;
   totalCacheWrite: number;
   totalCost: number; // 微美元
+  totalUpstreamCost: number;
+  totalBilledCost: number;
+  margin: number; // 毛利 = 计费金额 - 上游成本，可能为负
 }
 
 export interface UsageStatsFilter {
@@ -689,6 +1069,52 @@ export interface UsageStatsFilter {
   model?: string; // 模型名称
 }
 
+/** WastedSpend - 失败、取消或被重试取代的 attempt 消耗，按 Provider 和原因聚合 */
+export interface WastedSpend {
+  providerID: number;
+  reason: string; // 冷却原因，或 cancelled
+  attempts: number;
+  inputTokenCount: number;
+  outputTokenCount: number;
+  cacheReadCount: number;
+  cacheWriteCount: number;
+  cost: number; // 纳美元
+  upstreamCost: number;
+}
+
+/** ExportFormat - 导出文件格式 */
+export type ExportFormat = 'csv' | 'parquet';
+
+/** ReportSchedule - 账单报表周期 */
+export type ReportSchedule = 'weekly' | 'monthly';
+
+/** BillingReportFile - 单个项目 / API Token 的报表文件 */
+export interface BillingReportFile {
+  dimension: 'project' | 'api_token';
+  id: number;
+  name: string;
+  path: string;
+  totalRequests: number;
+  inputTokens: number;
+  outputTokens: number;
+  cost: number; // 纳美元
+  upstreamCost: number;
+  billedCost: number;
+  margin: number;
+}
+
+/** BillingReportResult - 一个周期的报表生成结果 */
+export interface BillingReportResult {
+  period: {
+    schedule: ReportSchedule;
+    key: string;
+    start: string;
+    end: string;
+  };
+  dir: string;
+  files: BillingReportFile[];
+}
+
 /** RecalculateCostsResult - 全量成本重算结果 */
 export interface RecalculateCostsResult {
   totalAttempts: number;
@@ -809,6 +1235,12 @@ export interface BackupAPIToken {
   isEnabled: boolean;
   devMode?: boolean;
   expiresAt?: string;
+  allowedClientTypes?: ClientType[];
+  allowedModels?: string[];
+  allowedProviderNames?: string[];
+  providerRestricted?: boolean; // 限制了供应商，即使所引用的供应商均已删除
+  allowedIPs?: string[];
+  projectAdmin?: boolean;
 }
 
 export interface BackupModelMapping {
@@ -867,6 +1299,7 @@ export interface DashboardDaySummary {
   requests: number;
   tokens: number;
   cost: number;
+  margin: number; // 毛利 = 计费金额 - 上游成本
   successRate?: number;
   rpm?: number; // Requests Per Minute (今日平均)
   tpm?: number; // Tokens Per Minute (今日平均)
@@ -877,10 +1310,46 @@ export interface DashboardAllTimeSummary {
   requests: number;
   tokens: number;
   cost: number;
+  margin: number;
   firstUseDate?: string;
   daysSinceFirstUse: number;
 }
 
+/** AnomalyAlert - API Token / 项目用量异常告警 */
+export interface AnomalyAlert {
+  time: string;
+  dimension: 'api_token' | 'project';
+  targetID: number;
+  targetName: string;
+  metric: 'request_rate' | 'token_rate' | 'cost_rate' | 'error_rate';
+  current: number; // 速率类为每小时速率，错误率为百分比
+  baseline: number;
+  threshold: number;
+  tokenDisabled: boolean; // 是否已自动禁用该 API Token
+}
+
+/** AdmissionClassStats - 单个优先级的准入队列统计 */
+export interface AdmissionClassStats {
+  class: Exclude<PriorityClass, ''>;
+  waiting: number;
+  admitted: number;
+  queued: number; // 实际排队后放行的请求数
+  rejected: number; // 队列已满被拒绝
+  timedOut: number;
+  avgWaitMs: number;
+  maxWaitMs: number;
+}
+
+/** AdmissionQueueStats - 准入队列快照（仅内存，重启后清空） */
+export interface AdmissionQueueStats {
+  enabled: boolean;
+  maxLength: number;
+  maxConcurrency: number;
+  inFlight: number;
+  waiting: number;
+  classes: AdmissionClassStats[];
+}
+
 /** Dashboard 热力图数据点 */
 export interface DashboardHeatmapPoint {
   date: string;
@@ -951,6 +1420,12 @@ export interface ModelPrice {
   id: number;
   createdAt: string;
   modelId: string;
+  /** 0 = 全局价格，>0 = 该 Provider 的覆盖价格 */
+  providerId: number;
+  /** ISO 4217 币种，价格为该币种的 micro 单位/M tokens */
+  currency: string;
+  /** default / manual / import:<format> */
+  source: string;
   inputPriceMicro: number;
   outputPriceMicro: number;
   cacheReadPriceMicro: number;
@@ -967,6 +1442,8 @@ export interface ModelPrice {
 /** 创建/更新模型价格的请求 */
 export interface ModelPriceInput {
   modelId: string;
+  providerId?: number;
+  currency?: string;
   inputPriceMicro: number;
   outputPriceMicro: number;
   cacheReadPriceMicro?: number;
@@ -979,3 +1456,27 @@ export interface ModelPriceInput {
   outputPremiumNum?: number;
   outputPremiumDenom?: number;
 }
+
+/** 价格表格式，为空时自动识别 */
+export type PriceSheetFormat = '' | 'json' | 'csv' | 'litellm';
+
+/** 导入价格表的请求 */
+export interface ModelPriceImportInput {
+  format?: PriceSheetFormat;
+  providerId?: number;
+  currency?: string;
+  dryRun?: boolean;
+  /** 价格表内容 */
+  content?: string;
+  /** 服务器本地文件路径 */
+  path?: string;
+}
+
+/** 导入价格表的结果 */
+export interface ModelPriceImportResult {
+  format: PriceSheetFormat;
+  dryRun: boolean;
+  created: ModelPrice[];
+  unchanged: number;
+  skipped: { model: string; reason: string }[];
+}
@@ -44,7 +44,15 @@ import {
   Shield,
 } from 'lucide-react';
 import { PageHeader } from '@/components/layout';
-import type { APIToken } from '@/lib/transport';
+import { PrioritySelect } from '@/components/priority-select';
+import type { APIToken, PriorityClass } from '@/lib/transport';
+import {
+  TokenScopeFields,
+  emptyTokenScopeForm,
+  scopeFormToData,
+  tokenToScopeForm,
+  type TokenScopeForm,
+} from './token-scope-fields';
 
 export function APITokensPage() {
   const { t, i18n } = useTranslation();
@@ -81,7 +89,9 @@ export function APITokensPage() {
   const [projectID, setProjectID] = useState<string>('0');
   const [expiresAt, setExpiresAt] = useState('');
   const [devMode, setDevMode] = useState(false);
+  const [priority, setPriority] = useState<PriorityClass>('');
   const [showProjectPicker, setShowProjectPicker] = useState(false);
+  const [scope, setScope] = useState<TokenScopeForm>(emptyTokenScopeForm);
 
   const resetForm = () => {
     setName('');
@@ -89,7 +99,9 @@ export function APITokensPage() {
     setProjectID('0');
     setExpiresAt('');
     setDevMode(false);
+    setPriority('');
     setShowProjectPicker(false);
+    setScope(emptyTokenScopeForm);
   };
 
   const closeEditDialog = () => {
@@ -105,6 +117,8 @@ export function APITokensPage() {
         description,
         projectID: parseInt(projectID) || 0,
         expiresAt: expiresAt ? new Date(expiresAt).toISOString() : undefined,
+        priority,
+        ...scopeFormToData(scope),
       },
       {
         onSuccess: (result) => {
@@ -130,6 +144,8 @@ export function APITokensPage() {
           projectID: parseInt(projectID) || 0,
           expiresAt: expiresAt ? new Date(expiresAt).toISOString() : undefined,
           devMode,
+          priority,
+          ...scopeFormToData(scope),
         },
       },
       {
@@ -159,6 +175,8 @@ export function APITokensPage() {
     setProjectID(token.projectID.toString());
     setExpiresAt(token.expiresAt ? token.expiresAt.split('T')[0] : '');
     setDevMode(!!token.devMode);
+    setPriority(token.priority ?? '');
+    setScope(tokenToScopeForm(token));
   };
 
   const handleCopyToken = async () => {
@@ -393,7 +411,7 @@ export function APITokensPage() {
           if (!open) resetForm();
         }}
       >
-        <DialogContent>
+        <DialogContent className="max-h-[90vh] overflow-y-auto">
           <DialogHeader>
             <DialogTitle>{t('apiTokens.createDialog.title')}</DialogTitle>
             <DialogDescription>{t('apiTokens.createDialog.description')}</DialogDescription>
@@ -465,6 +483,24 @@ export function APITokensPage() {
               />
               <p className="text-xs text-text-muted">{t('apiTokens.createDialog.expiresAtHint')}</p>
             </div>
+            <div className="space-y-2">
+              <label className="text-xs font-medium text-text-secondary uppercase tracking-wider">
+                {t('apiTokens.priority')}
+              </label>
+              <PrioritySelect
+                value={priority}
+                onChange={setPriority}
+                allowInherit
+                disabled={createToken.isPending}
+              />
+              <p className="text-xs text-text-muted">{t('apiTokens.priorityHint')}</p>
+            </div>
+            <TokenScopeFields
+              value={scope}
+              onChange={setScope}
+              hasProject={projectID !== '0'}
+              disabled={createToken.isPending}
+            />
             <DialogFooter>
               <Button type="button" variant="outline" onClick={() => setShowForm(false)}>
                 {t('common.cancel')}
@@ -487,7 +523,7 @@ export function APITokensPage() {
           }
         }}
       >
-        <DialogContent>
+        <DialogContent className="max-h-[90vh] overflow-y-auto">
           <DialogHeader>
             <DialogTitle>{t('apiTokens.editDialog.title')}</DialogTitle>
             <DialogDescription>{t('apiTokens.editDialog.description')}</DialogDescription>
@@ -568,6 +604,24 @@ export function APITokensPage() {
                 </span>
               </div>
             </div>
+            <div className="space-y-2">
+              <label className="text-xs font-medium text-text-secondary uppercase tracking-wider">
+                {t('apiTokens.priority')}
+              </label>
+              <PrioritySelect
+                value={priority}
+                onChange={setPriority}
+                allowInherit
+                disabled={updateToken.isPending}
+              />
+              <p className="text-xs text-text-muted">{t('apiTokens.priorityHint')}</p>
+            </div>
+            <TokenScopeFields
+              value={scope}
+              onChange={setScope}
+              hasProject={projectID !== '0'}
+              disabled={updateToken.isPending}
+            />
             <DialogFooter>
               <Button
                 type="button"
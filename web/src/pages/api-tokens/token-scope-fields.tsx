@@ -0,0 +1,104 @@
+import { useId } from 'react';
+import { useTranslation } from 'react-i18next';
+import { Input, Switch } from '@/components/ui';
+import type { APIToken, APITokenScopeData, ClientType } from '@/lib/transport';
+
+export interface TokenScopeForm {
+  clientTypes: string;
+  models: string;
+  providerIDs: string;
+  ips: string;
+  projectAdmin: boolean;
+}
+
+export const emptyTokenScopeForm: TokenScopeForm = {
+  clientTypes: '',
+  models: '',
+  providerIDs: '',
+  ips: '',
+  projectAdmin: false,
+};
+
+function splitList(value: string): string[] {
+  return value
+    .split(/[,\s]+/)
+    .map((s) => s.trim())
+    .filter(Boolean);
+}
+
+export function tokenToScopeForm(token: APIToken): TokenScopeForm {
+  return {
+    clientTypes: (token.allowedClientTypes ?? []).join(', '),
+    models: (token.allowedModels ?? []).join(', '),
+    providerIDs: (token.allowedProviderIDs ?? []).join(', '),
+    ips: (token.allowedIPs ?? []).join(', '),
+    projectAdmin: !!token.projectAdmin,
+  };
+}
+
+// 空列表表示不限制
+export function scopeFormToData(form: TokenScopeForm): APITokenScopeData {
+  return {
+    allowedClientTypes: splitList(form.clientTypes) as ClientType[],
+    allowedModels: splitList(form.models),
+    allowedProviderIDs: splitList(form.providerIDs)
+      .map((id) => parseInt(id))
+      .filter((id) => !isNaN(id) && id > 0),
+    allowedIPs: splitList(form.ips),
+    projectAdmin: form.projectAdmin,
+  };
+}
+
+interface TokenScopeFieldsProps {
+  value: TokenScopeForm;
+  onChange: (value: TokenScopeForm) => void;
+  hasProject: boolean;
+  disabled?: boolean;
+}
+
+export function TokenScopeFields({ value, onChange, hasProject, disabled }: TokenScopeFieldsProps) {
+  const { t } = useTranslation();
+  const projectAdminSwitchId = useId();
+
+  const field = (key: 'clientTypes' | 'models' | 'providerIDs' | 'ips', placeholder: string) => (
+    <div className="space-y-2">
+      <label className="text-xs font-medium text-text-secondary uppercase tracking-wider">
+        {t(`apiTokens.scope.${key}`)}
+      </label>
+      <Input
+        value={value[key]}
+        onChange={(e) => onChange({ ...value, [key]: e.target.value })}
+        placeholder={placeholder}
+        disabled={disabled}
+      />
+    </div>
+  );
+
+  return (
+    <div className="space-y-4 rounded-md border border-border p-3">
+      <div>
+        <p className="text-sm font-medium">{t('apiTokens.scope.title')}</p>
+        <p className="text-xs text-text-muted">{t('apiTokens.scope.hint')}</p>
+      </div>
+      {field('clientTypes', 'claude, codex')}
+      {field('models', 'claude-sonnet-*, gpt-5*')}
+      {field('providerIDs', '1, 2')}
+      {field('ips', '10.0.0.0/8, 203.0.113.7')}
+      <div className="flex items-center justify-between">
+        <label
+          htmlFor={projectAdminSwitchId}
+          className="text-xs font-medium text-text-secondary uppercase tracking-wider"
+        >
+          {t('apiTokens.scope.projectAdmin')}
+        </label>
+        <Switch
+          id={projectAdminSwitchId}
+          checked={value.projectAdmin}
+          onCheckedChange={(checked) => onChange({ ...value, projectAdmin: checked })}
+          disabled={disabled || !hasProject}
+        />
+      </div>
+      <p className="text-xs text-text-muted">{t('apiTokens.scope.projectAdminHint')}</p>
+    </div>
+  );
+}
@@ -1,4 +1,4 @@
-import { useState, type FormEvent } from 'react';
+import { useEffect, useState, type FormEvent } from 'react';
 import { useTranslation } from 'react-i18next';
 import { Button } from '@/components/ui/button';
 import { Input } from '@/components/ui/input';
@@ -14,6 +14,18 @@ export function LoginPage({ onSuccess }: LoginPageProps) {
   const [password, setPassword] = useState('');
   const [error, setError] = useState('');
   const [isLoading, setIsLoading] = useState(false);
+  const [passwordEnabled, setPasswordEnabled] = useState(true);
+  const [oidcEnabled, setOidcEnabled] = useState(false);
+
+  useEffect(() => {
+    transport
+      .getAuthStatus()
+      .then((status) => {
+        setPasswordEnabled(status.passwordEnabled ?? true);
+        setOidcEnabled(status.oidcEnabled ?? false);
+      })
+      .catch(() => {});
+  }, [transport]);
 
   const handleSubmit = async (e: FormEvent) => {
     e.preventDefault();
@@ -42,23 +54,36 @@ export function LoginPage({ onSuccess }: LoginPageProps) {
           <p className="text-muted-foreground text-sm">{t('login.description')}</p>
         </div>
 
-        <form onSubmit={handleSubmit} className="space-y-4">
-          <div className="space-y-2">
-            <Input
-              type="password"
-              placeholder={t('login.passwordPlaceholder')}
-              value={password}
-              onChange={(e) => setPassword(e.target.value)}
-              autoFocus
-              disabled={isLoading}
-            />
-            {error && <p className="text-destructive text-sm">{error}</p>}
-          </div>
+        {passwordEnabled && (
+          <form onSubmit={handleSubmit} className="space-y-4">
+            <div className="space-y-2">
+              <Input
+                type="password"
+                placeholder={t('login.passwordPlaceholder')}
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                autoFocus
+                disabled={isLoading}
+              />
+              {error && <p className="text-destructive text-sm">{error}</p>}
+            </div>
+
+            <Button type="submit" className="w-full" disabled={isLoading || !password}>
+              {isLoading ? t('login.verifying') : t('login.submit')}
+            </Button>
+          </form>
+        )}
 
-          <Button type="submit" className="w-full" disabled={isLoading || !password}>
-            {isLoading ? t('login.verifying') : t('login.submit')}
+        {oidcEnabled && (
+          <Button
+            type="button"
+            variant={passwordEnabled ? 'outline' : 'default'}
+            className="w-full"
+            onClick={() => window.location.assign('/api/admin/auth/oidc/login')}
+          >
+            {t('login.sso')}
           </Button>
-        </form>
+        )}
       </div>
     </div>
   );
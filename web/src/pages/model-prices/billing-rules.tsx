@@ -0,0 +1,323 @@
+import { useState } from 'react';
+import { useTranslation } from 'react-i18next';
+import {
+  Button,
+  Card,
+  CardContent,
+  Input,
+  Dialog,
+  DialogContent,
+  DialogHeader,
+  DialogTitle,
+  DialogFooter,
+  Label,
+  Switch,
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui';
+import {
+  useBillingRules,
+  useCreateBillingRule,
+  useUpdateBillingRule,
+  useDeleteBillingRule,
+  useProjects,
+  useAPITokens,
+} from '@/hooks/queries';
+import type { BillingRule, CreateBillingRuleData } from '@/lib/transport/types';
+import { Plus, Trash2, Pencil } from 'lucide-react';
+import { cn } from '@/lib/utils';
+
+// 纳美元 -> 美元显示
+function formatNanoUSD(nano: number): string {
+  return `$${(nano / 1_000_000_000).toFixed(4)}`;
+}
+
+function formatMarkup(bps: number): string {
+  const pct = bps / 100;
+  return `${pct > 0 ? '+' : ''}${pct}%`;
+}
+
+interface FormData {
+  name: string;
+  targetType: 'project' | 'token';
+  targetId: string;
+  markupPct: string;
+  requestFee: string; // 美元
+  freeTierMonthly: string; // 美元
+  isEnabled: boolean;
+}
+
+const emptyForm: FormData = {
+  name: '',
+  targetType: 'project',
+  targetId: '',
+  markupPct: '0',
+  requestFee: '0',
+  freeTierMonthly: '0',
+  isEnabled: true,
+};
+
+function toForm(rule: BillingRule): FormData {
+  return {
+    name: rule.name,
+    targetType: rule.apiTokenID > 0 ? 'token' : 'project',
+    targetId: String(rule.apiTokenID > 0 ? rule.apiTokenID : rule.projectID),
+    markupPct: String(rule.markupBps / 100),
+    requestFee: String(rule.requestFee / 1_000_000_000),
+    freeTierMonthly: String(rule.freeTierMonthly / 1_000_000_000),
+    isEnabled: rule.isEnabled,
+  };
+}
+
+function toData(form: FormData): CreateBillingRuleData {
+  const targetId = Number(form.targetId) || 0;
+  const usdToNano = (v: string) => Math.max(0, Math.round((parseFloat(v) || 0) * 1_000_000_000));
+  return {
+    name: form.name.trim(),
+    projectID: form.targetType === 'project' ? targetId : 0,
+    apiTokenID: form.targetType === 'token' ? targetId : 0,
+    markupBps: Math.round((parseFloat(form.markupPct) || 0) * 100),
+    requestFee: usdToNano(form.requestFee),
+    freeTierMonthly: usdToNano(form.freeTierMonthly),
+    isEnabled: form.isEnabled,
+  };
+}
+
+export function BillingRulesCard() {
+  const { t } = useTranslation();
+  const { data: rules } = useBillingRules();
+  const { data: projects } = useProjects();
+  const { data: tokens } = useAPITokens();
+  const createRule = useCreateBillingRule();
+  const updateRule = useUpdateBillingRule();
+  const deleteRule = useDeleteBillingRule();
+
+  const [editing, setEditing] = useState<BillingRule | null>(null);
+  const [open, setOpen] = useState(false);
+  const [form, setForm] = useState<FormData>(emptyForm);
+  const [error, setError] = useState('');
+
+  const isPending = createRule.isPending || updateRule.isPending || deleteRule.isPending;
+
+  const targetName = (rule: Pick<BillingRule, 'projectID' | 'apiTokenID'>) => {
+    if (rule.apiTokenID > 0) {
+      const token = tokens?.find((tk) => tk.id === rule.apiTokenID);
+      return `${t('billingRules.token')}: ${token?.name ?? `#${rule.apiTokenID}`}`;
+    }
+    const project = projects?.find((p) => p.id === rule.projectID);
+    return `${t('billingRules.project')}: ${project?.name ?? `#${rule.projectID}`}`;
+  };
+
+  const targets =
+    form.targetType === 'token'
+      ? (tokens || []).map((tk) => ({ id: tk.id, name: tk.name }))
+      : (projects || []).map((p) => ({ id: p.id, name: p.name }));
+
+  const handleOpen = (rule: BillingRule | null) => {
+    setEditing(rule);
+    setForm(rule ? toForm(rule) : emptyForm);
+    setError('');
+    setOpen(true);
+  };
+
+  const handleSave = () => {
+    const data = toData(form);
+    const onSuccess = () => setOpen(false);
+    const onError = (err: Error) => setError(err.message);
+    if (editing) {
+      updateRule.mutate({ id: editing.id, data }, { onSuccess, onError });
+    } else {
+      createRule.mutate(data, { onSuccess, onError });
+    }
+  };
+
+  return (
+    <Card className="border-border bg-card">
+      <CardContent className="p-6">
+        <div className="flex items-center justify-between mb-4">
+          <div>
+            <h3 className="text-sm font-medium">{t('billingRules.title')}</h3>
+            <p className="text-xs text-muted-foreground">{t('billingRules.description')}</p>
+          </div>
+          <Button variant="outline" size="sm" onClick={() => handleOpen(null)}>
+            <Plus className="h-4 w-4 mr-1" />
+            {t('common.add')}
+          </Button>
+        </div>
+
+        {!rules || rules.length === 0 ? (
+          <p className="text-center text-sm text-muted-foreground py-4">
+            {t('billingRules.noData')}
+          </p>
+        ) : (
+          <div className="space-y-1">
+            {rules.map((rule) => (
+              <div
+                key={rule.id}
+                className={cn(
+                  'flex items-center gap-3 py-2 hover:bg-accent/50 rounded px-2 -mx-2',
+                  !rule.isEnabled && 'opacity-50',
+                )}
+              >
+                <div className="flex-1 min-w-0 text-sm truncate">{rule.name}</div>
+                <div className="w-48 text-xs text-muted-foreground truncate">
+                  {targetName(rule)}
+                </div>
+                <div className="w-20 text-right text-sm font-mono">
+                  {formatMarkup(rule.markupBps)}
+                </div>
+                <div className="w-24 text-right text-sm font-mono">
+                  {formatNanoUSD(rule.requestFee)}
+                </div>
+                <div className="w-24 text-right text-sm font-mono text-muted-foreground">
+                  {formatNanoUSD(rule.freeTierMonthly)}
+                </div>
+                <div className="w-20 shrink-0 flex items-center gap-1 justify-end">
+                  <Button
+                    variant="ghost"
+                    size="sm"
+                    onClick={() => handleOpen(rule)}
+                    disabled={isPending}
+                  >
+                    <Pencil className="h-4 w-4" />
+                  </Button>
+                  <Button
+                    variant="ghost"
+                    size="sm"
+                    onClick={() => deleteRule.mutate(rule.id)}
+                    disabled={isPending}
+                  >
+                    <Trash2 className="h-4 w-4 text-destructive" />
+                  </Button>
+                </div>
+              </div>
+            ))}
+          </div>
+        )}
+      </CardContent>
+
+      <Dialog open={open} onOpenChange={setOpen}>
+        <DialogContent className="max-w-lg">
+          <DialogHeader>
+            <DialogTitle>
+              {editing ? t('billingRules.editTitle') : t('billingRules.createTitle')}
+            </DialogTitle>
+          </DialogHeader>
+
+          <div className="space-y-4 py-4">
+            <div className="space-y-2">
+              <Label>{t('billingRules.name')}</Label>
+              <Input
+                value={form.name}
+                onChange={(e) => setForm({ ...form, name: e.target.value })}
+              />
+            </div>
+
+            <div className="grid grid-cols-2 gap-4">
+              <div className="space-y-2">
+                <Label>{t('billingRules.target')}</Label>
+                <Select
+                  value={form.targetType}
+                  onValueChange={(v) =>
+                    v && setForm({ ...form, targetType: v as FormData['targetType'], targetId: '' })
+                  }
+                >
+                  <SelectTrigger className="w-full">
+                    <SelectValue>
+                      {form.targetType === 'token'
+                        ? t('billingRules.token')
+                        : t('billingRules.project')}
+                    </SelectValue>
+                  </SelectTrigger>
+                  <SelectContent>
+                    <SelectItem value="project">{t('billingRules.project')}</SelectItem>
+                    <SelectItem value="token">{t('billingRules.token')}</SelectItem>
+                  </SelectContent>
+                </Select>
+              </div>
+              <div className="space-y-2">
+                <Label>&nbsp;</Label>
+                <Select
+                  value={form.targetId}
+                  onValueChange={(v) => v && setForm({ ...form, targetId: v })}
+                >
+                  <SelectTrigger className="w-full">
+                    <SelectValue>
+                      {targets.find((x) => String(x.id) === form.targetId)?.name ?? '-'}
+                    </SelectValue>
+                  </SelectTrigger>
+                  <SelectContent>
+                    {targets.map((x) => (
+                      <SelectItem key={x.id} value={String(x.id)}>
+                        {x.name}
+                      </SelectItem>
+                    ))}
+                  </SelectContent>
+                </Select>
+              </div>
+            </div>
+
+            <div className="grid grid-cols-3 gap-4">
+              <div className="space-y-2">
+                <Label>{t('billingRules.markup')} (%)</Label>
+                <Input
+                  type="number"
+                  step="1"
+                  value={form.markupPct}
+                  onChange={(e) => setForm({ ...form, markupPct: e.target.value })}
+                  className="font-mono"
+                />
+              </div>
+              <div className="space-y-2">
+                <Label>{t('billingRules.requestFee')} ($)</Label>
+                <Input
+                  type="number"
+                  step="0.0001"
+                  value={form.requestFee}
+                  onChange={(e) => setForm({ ...form, requestFee: e.target.value })}
+                  className="font-mono"
+                />
+              </div>
+              <div className="space-y-2">
+                <Label>{t('billingRules.freeTier')} ($)</Label>
+                <Input
+                  type="number"
+                  step="0.01"
+                  value={form.freeTierMonthly}
+                  onChange={(e) => setForm({ ...form, freeTierMonthly: e.target.value })}
+                  className="font-mono"
+                />
+              </div>
+            </div>
+            <p className="text-xs text-muted-foreground">{t('billingRules.hint')}</p>
+
+            <div className="flex items-center gap-3">
+              <Switch
+                checked={form.isEnabled}
+                onCheckedChange={(checked) => setForm({ ...form, isEnabled: checked })}
+              />
+              <Label>{t('common.enabled')}</Label>
+            </div>
+
+            {error && <p className="text-xs text-destructive">{error}</p>}
+          </div>
+
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setOpen(false)}>
+              {t('common.cancel')}
+            </Button>
+            <Button
+              onClick={handleSave}
+              disabled={!form.name.trim() || !form.targetId || isPending}
+            >
+              {t('common.save')}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </Card>
+  );
+}
@@ -0,0 +1,217 @@
+import { useRef, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+import {
+  Button,
+  Input,
+  Dialog,
+  DialogContent,
+  DialogHeader,
+  DialogTitle,
+  DialogFooter,
+  Label,
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui';
+import { Textarea } from '@/components/ui/textarea';
+import { useImportModelPrices } from '@/hooks/queries';
+import type { PriceSheetFormat, Provider } from '@/lib/transport/types';
+import { Upload } from 'lucide-react';
+
+const FORMATS: PriceSheetFormat[] = ['', 'csv', 'json', 'litellm'];
+
+const CSV_PLACEHOLDER = 'model,input,output,cache_read,currency\nclaude-sonnet-4,21.6,108,2.16,CNY';
+
+const toUnits = (micro: number) => (micro / 1_000_000).toFixed(2);
+
+interface ImportPricesDialogProps {
+  open: boolean;
+  onOpenChange: (open: boolean) => void;
+  providers: Provider[];
+}
+
+export function ImportPricesDialog({ open, onOpenChange, providers }: ImportPricesDialogProps) {
+  const { t } = useTranslation();
+  const importPrices = useImportModelPrices();
+  const fileInputRef = useRef<HTMLInputElement>(null);
+
+  const [format, setFormat] = useState<PriceSheetFormat>('');
+  const [providerId, setProviderId] = useState('0');
+  const [currency, setCurrency] = useState('');
+  const [content, setContent] = useState('');
+  const [path, setPath] = useState('');
+
+  const handleFile = async (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0];
+    if (!file) return;
+    setContent(await file.text());
+    setPath('');
+    e.target.value = '';
+  };
+
+  const submit = (dryRun: boolean) => {
+    importPrices.mutate({
+      format,
+      providerId: Number(providerId),
+      currency: currency.trim(),
+      dryRun,
+      content: path.trim() ? undefined : content,
+      path: path.trim() || undefined,
+    });
+  };
+
+  const handleOpenChange = (next: boolean) => {
+    if (!next) importPrices.reset();
+    onOpenChange(next);
+  };
+
+  const providerName = (id: string) =>
+    id === '0'
+      ? t('modelPrices.globalPrice')
+      : (providers.find((p) => String(p.id) === id)?.name ?? `#${id}`);
+
+  const result = importPrices.data;
+  const hasSource = content.trim() !== '' || path.trim() !== '';
+
+  return (
+    <Dialog open={open} onOpenChange={handleOpenChange}>
+      <DialogContent className="max-w-2xl">
+        <DialogHeader>
+          <DialogTitle>{t('modelPrices.importTitle')}</DialogTitle>
+        </DialogHeader>
+
+        <div className="space-y-4 py-4">
+          <p className="text-xs text-muted-foreground">{t('modelPrices.importDesc')}</p>
+
+          <div className="grid grid-cols-3 gap-4">
+            <div className="space-y-2">
+              <Label>{t('modelPrices.importFormat')}</Label>
+              <Select
+                value={format || 'auto'}
+                onValueChange={(v) => v && setFormat(v === 'auto' ? '' : (v as PriceSheetFormat))}
+              >
+                <SelectTrigger className="w-full">
+                  <SelectValue>
+                    {format ? t(`modelPrices.format_${format}`) : t('modelPrices.format_auto')}
+                  </SelectValue>
+                </SelectTrigger>
+                <SelectContent>
+                  {FORMATS.map((f) => (
+                    <SelectItem key={f || 'auto'} value={f || 'auto'}>
+                      {t(`modelPrices.format_${f || 'auto'}`)}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="space-y-2">
+              <Label>{t('modelPrices.provider')}</Label>
+              <Select value={providerId} onValueChange={(v) => v && setProviderId(v)}>
+                <SelectTrigger className="w-full">
+                  <SelectValue>{providerName(providerId)}</SelectValue>
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="0">{t('modelPrices.globalPrice')}</SelectItem>
+                  {providers.map((p) => (
+                    <SelectItem key={p.id} value={String(p.id)}>
+                      {p.name}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="space-y-2">
+              <Label>{t('modelPrices.currency')}</Label>
+              <Input
+                value={currency}
+                onChange={(e) => setCurrency(e.target.value.toUpperCase())}
+                placeholder="USD"
+                maxLength={3}
+                className="font-mono"
+              />
+            </div>
+          </div>
+
+          <div className="space-y-2">
+            <div className="flex items-center justify-between">
+              <Label>{t('modelPrices.importContent')}</Label>
+              <input
+                ref={fileInputRef}
+                type="file"
+                accept=".csv,.json,text/csv,application/json"
+                className="hidden"
+                onChange={handleFile}
+              />
+              <Button variant="outline" size="sm" onClick={() => fileInputRef.current?.click()}>
+                <Upload className="h-4 w-4 mr-1" />
+                {t('modelPrices.importChooseFile')}
+              </Button>
+            </div>
+            <Textarea
+              value={content}
+              onChange={(e) => setContent(e.target.value)}
+              placeholder={CSV_PLACEHOLDER}
+              className="font-mono text-xs h-40"
+              disabled={path.trim() !== ''}
+            />
+          </div>
+
+          <div className="space-y-2">
+            <Label>{t('modelPrices.importPath')}</Label>
+            <Input
+              value={path}
+              onChange={(e) => setPath(e.target.value)}
+              placeholder="/data/model_prices_and_context_window.json"
+              className="font-mono"
+            />
+          </div>
+
+          {result && (
+            <div className="rounded-md border border-border p-3 text-xs space-y-2">
+              <p className={result.dryRun ? 'text-muted-foreground' : 'text-emerald-600'}>
+                {t(result.dryRun ? 'modelPrices.importPreview' : 'modelPrices.importDone', {
+                  created: result.created.length,
+                  unchanged: result.unchanged,
+                  skipped: result.skipped.length,
+                })}
+              </p>
+              {result.created.length > 0 && (
+                <div className="max-h-32 overflow-y-auto font-mono text-muted-foreground">
+                  {result.created.map((p) => (
+                    <div key={p.modelId} className="flex justify-between gap-4">
+                      <span>{p.modelId}</span>
+                      <span>
+                        {p.currency} {toUnits(p.inputPriceMicro)} / {toUnits(p.outputPriceMicro)}
+                      </span>
+                    </div>
+                  ))}
+                </div>
+              )}
+            </div>
+          )}
+          {importPrices.error && (
+            <p className="text-xs text-destructive">{(importPrices.error as Error).message}</p>
+          )}
+        </div>
+
+        <DialogFooter>
+          <Button variant="outline" onClick={() => handleOpenChange(false)}>
+            {t('common.cancel')}
+          </Button>
+          <Button
+            variant="outline"
+            onClick={() => submit(true)}
+            disabled={!hasSource || importPrices.isPending}
+          >
+            {t('modelPrices.importPreviewButton')}
+          </Button>
+          <Button onClick={() => submit(false)} disabled={!hasSource || importPrices.isPending}>
+            {t('modelPrices.import')}
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  );
+}
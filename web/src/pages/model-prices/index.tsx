@@ -1,4 +1,4 @@
-import { useState } from 'react';
+import { useEffect, useState } from 'react';
 import { useTranslation } from 'react-i18next';
 import {
   Button,
@@ -20,6 +20,11 @@ import {
   AlertDialogFooter,
   AlertDialogHeader,
   AlertDialogTitle,
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
 } from '@/components/ui';
 import { PageHeader } from '@/components/layout/page-header';
 import {
@@ -28,13 +33,48 @@ import {
   useUpdateModelPrice,
   useDeleteModelPrice,
   useResetModelPricesToDefaults,
+  useProviders,
+  useSettings,
+  useUpdateSetting,
 } from '@/hooks/queries';
 import type { ModelPrice, ModelPriceInput } from '@/lib/transport/types';
-import { DollarSign, Plus, Trash2, Pencil, RotateCcw } from 'lucide-react';
+import { DollarSign, Plus, Trash2, Pencil, RotateCcw, FileUp } from 'lucide-react';
+import { cn } from '@/lib/utils';
+import { ImportPricesDialog } from './import-dialog';
+import { BillingRulesCard } from './billing-rules';
+
+const CURRENCY_SYMBOLS: Record<string, string> = { USD: '$', CNY: '¥', EUR: '€' };
+
+// Helper to format micro price to display format (e.g., $3.00 / M tokens, ¥21.60 / M tokens)
+function formatMicroPrice(micro: number, currency = 'USD'): string {
+  const code = currency || 'USD';
+  const symbol = CURRENCY_SYMBOLS[code];
+  const value = (micro / 1_000_000).toFixed(2);
+  return symbol ? `${symbol}${value}` : `${value} ${code}`;
+}
+
+// 汇率设置为 JSON（{"CNY":7.2}），编辑时使用 "CNY=7.2, EUR=0.92" 格式
+function ratesToText(value: string | undefined): string {
+  if (!value) return '';
+  try {
+    return Object.entries(JSON.parse(value) as Record<string, number>)
+      .map(([code, rate]) => `${code}=${rate}`)
+      .join(', ');
+  } catch {
+    return value;
+  }
+}
 
-// Helper to format micro USD price to display format (e.g., $3.00 / M tokens)
-function formatMicroPrice(microUsd: number): string {
-  return `$${(microUsd / 1_000_000).toFixed(2)}`;
+function textToRates(text: string): string | null {
+  const rates: Record<string, number> = {};
+  for (const part of text.split(/[,\n]/)) {
+    if (!part.trim()) continue;
+    const [code, rate] = part.split('=').map((s) => s.trim());
+    const value = parseFloat(rate);
+    if (!/^[A-Za-z]{3}$/.test(code ?? '') || !(value > 0)) return null;
+    rates[code.toUpperCase()] = value;
+  }
+  return Object.keys(rates).length > 0 ? JSON.stringify(rates) : '';
 }
 
 // Helper to parse display price to micro USD
@@ -46,6 +86,8 @@ function parsePriceToMicro(priceStr: string): number {
 
 interface PriceFormData {
   modelId: string;
+  providerId: string;
+  currency: string;
   inputPrice: string;
   outputPrice: string;
   cacheReadPrice: string;
@@ -61,6 +103,8 @@ interface PriceFormData {
 
 const defaultFormData: PriceFormData = {
   modelId: '',
+  providerId: '0',
+  currency: 'USD',
   inputPrice: '3.00',
   outputPrice: '15.00',
   cacheReadPrice: '0.30',
@@ -77,6 +121,8 @@ const defaultFormData: PriceFormData = {
 function priceToFormData(price: ModelPrice): PriceFormData {
   return {
     modelId: price.modelId,
+    providerId: String(price.providerId ?? 0),
+    currency: price.currency || 'USD',
     inputPrice: (price.inputPriceMicro / 1_000_000).toFixed(2),
     outputPrice: (price.outputPriceMicro / 1_000_000).toFixed(2),
     cacheReadPrice: (price.cacheReadPriceMicro / 1_000_000).toFixed(2),
@@ -94,6 +140,8 @@ function priceToFormData(price: ModelPrice): PriceFormData {
 function formDataToInput(form: PriceFormData): ModelPriceInput {
   return {
     modelId: form.modelId,
+    providerId: Number(form.providerId) || 0,
+    currency: form.currency.trim().toUpperCase() || 'USD',
     inputPriceMicro: parsePriceToMicro(form.inputPrice),
     outputPriceMicro: parsePriceToMicro(form.outputPrice),
     cacheReadPriceMicro: parsePriceToMicro(form.cacheReadPrice),
@@ -115,12 +163,14 @@ export function ModelPricesPage() {
   const updatePrice = useUpdateModelPrice();
   const deletePrice = useDeleteModelPrice();
   const resetPrices = useResetModelPricesToDefaults();
+  const { data: providers } = useProviders();
 
   const [isDialogOpen, setIsDialogOpen] = useState(false);
   const [editingPrice, setEditingPrice] = useState<ModelPrice | null>(null);
   const [formData, setFormData] = useState<PriceFormData>(defaultFormData);
   const [deleteConfirmId, setDeleteConfirmId] = useState<number | null>(null);
   const [resetConfirmOpen, setResetConfirmOpen] = useState(false);
+  const [importOpen, setImportOpen] = useState(false);
 
   const handleOpenCreate = () => {
     setEditingPrice(null);
@@ -167,7 +217,15 @@ export function ModelPricesPage() {
 
   if (isLoading) return null;
 
-  const sortedPrices = [...(prices || [])].sort((a, b) => a.modelId.localeCompare(b.modelId));
+  const providerName = (id: number) =>
+    id === 0
+      ? t('modelPrices.globalPrice')
+      : (providers?.find((p) => p.id === id)?.name ?? `#${id}`);
+
+  // 全局价格在前，Provider 覆盖价格按 Provider 分组
+  const sortedPrices = [...(prices || [])].sort(
+    (a, b) => (a.providerId ?? 0) - (b.providerId ?? 0) || a.modelId.localeCompare(b.modelId),
+  );
 
   return (
     <div className="flex flex-col h-full bg-background">
@@ -178,6 +236,15 @@ export function ModelPricesPage() {
         description={t('modelPrices.description', { count: prices?.length || 0 })}
         actions={
           <div className="flex items-center gap-2">
+            <Button
+              variant="outline"
+              size="sm"
+              onClick={() => setImportOpen(true)}
+              disabled={isPending}
+            >
+              <FileUp className="h-4 w-4 mr-1" />
+              {t('modelPrices.import')}
+            </Button>
             <Button
               variant="outline"
               size="sm"
@@ -195,7 +262,10 @@ export function ModelPricesPage() {
         }
       />
 
-      <div className="flex-1 overflow-y-auto p-6">
+      <div className="flex-1 overflow-y-auto p-6 space-y-6">
+        <ExchangeRatesCard />
+        <BillingRulesCard />
+
         <Card className="border-border bg-card">
           <CardContent className="p-6">
             <p className="text-xs text-muted-foreground mb-4">{t('modelPrices.pageDesc')}</p>
@@ -203,6 +273,7 @@ export function ModelPricesPage() {
             {/* Header row */}
             <div className="flex items-center gap-3 text-xs text-muted-foreground font-medium border-b pb-2 mb-2">
               <div className="flex-1 min-w-0">{t('modelPrices.modelId')}</div>
+              <div className="w-32">{t('modelPrices.provider')}</div>
               <div className="w-24 text-right">{t('modelPrices.inputPrice')}</div>
               <div className="w-24 text-right">{t('modelPrices.outputPrice')}</div>
               <div className="w-24 text-right">{t('modelPrices.cacheRead')}</div>
@@ -221,15 +292,28 @@ export function ModelPricesPage() {
                     key={price.id}
                     className="flex items-center gap-3 py-2 hover:bg-accent/50 rounded px-2 -mx-2"
                   >
-                    <div className="flex-1 min-w-0 font-mono text-sm truncate">{price.modelId}</div>
+                    <div className="flex-1 min-w-0 font-mono text-sm truncate">
+                      {price.modelId}
+                      {price.source?.startsWith('import') && (
+                        <span className="ml-2 text-xs text-muted-foreground">{price.source}</span>
+                      )}
+                    </div>
+                    <div
+                      className={cn(
+                        'w-32 text-xs truncate',
+                        !price.providerId && 'text-muted-foreground',
+                      )}
+                    >
+                      {providerName(price.providerId ?? 0)}
+                    </div>
                     <div className="w-24 text-right text-sm font-mono">
-                      {formatMicroPrice(price.inputPriceMicro)}
+                      {formatMicroPrice(price.inputPriceMicro, price.currency)}
                     </div>
                     <div className="w-24 text-right text-sm font-mono">
-                      {formatMicroPrice(price.outputPriceMicro)}
+                      {formatMicroPrice(price.outputPriceMicro, price.currency)}
                     </div>
                     <div className="w-24 text-right text-sm font-mono text-muted-foreground">
-                      {formatMicroPrice(price.cacheReadPriceMicro)}
+                      {formatMicroPrice(price.cacheReadPriceMicro, price.currency)}
                     </div>
                     <div className="w-16 text-center">
                       {price.has1mContext ? (
@@ -287,10 +371,47 @@ export function ModelPricesPage() {
               <p className="text-xs text-muted-foreground">{t('modelPrices.modelIdHint')}</p>
             </div>
 
+            {/* Provider override & currency */}
+            <div className="grid grid-cols-2 gap-4">
+              <div className="space-y-2">
+                <Label>{t('modelPrices.provider')}</Label>
+                <Select
+                  value={formData.providerId}
+                  onValueChange={(v) => v && setFormData({ ...formData, providerId: v })}
+                  disabled={!!editingPrice}
+                >
+                  <SelectTrigger className="w-full">
+                    <SelectValue>{providerName(Number(formData.providerId))}</SelectValue>
+                  </SelectTrigger>
+                  <SelectContent>
+                    <SelectItem value="0">{t('modelPrices.globalPrice')}</SelectItem>
+                    {(providers || []).map((p) => (
+                      <SelectItem key={p.id} value={String(p.id)}>
+                        {p.name}
+                      </SelectItem>
+                    ))}
+                  </SelectContent>
+                </Select>
+              </div>
+              <div className="space-y-2">
+                <Label>{t('modelPrices.currency')}</Label>
+                <Input
+                  value={formData.currency}
+                  onChange={(e) =>
+                    setFormData({ ...formData, currency: e.target.value.toUpperCase() })
+                  }
+                  placeholder="USD"
+                  maxLength={3}
+                  className="font-mono"
+                />
+              </div>
+            </div>
+            <p className="text-xs text-muted-foreground">{t('modelPrices.providerHint')}</p>
+
             {/* Prices Grid */}
             <div className="grid grid-cols-2 gap-4">
               <div className="space-y-2">
-                <Label>{t('modelPrices.inputPrice')} ($/M)</Label>
+                <Label>{t('modelPrices.inputPrice')} (/M)</Label>
                 <Input
                   type="number"
                   step="0.01"
@@ -300,7 +421,7 @@ export function ModelPricesPage() {
                 />
               </div>
               <div className="space-y-2">
-                <Label>{t('modelPrices.outputPrice')} ($/M)</Label>
+                <Label>{t('modelPrices.outputPrice')} (/M)</Label>
                 <Input
                   type="number"
                   step="0.01"
@@ -314,7 +435,7 @@ export function ModelPricesPage() {
             {/* Cache Prices */}
             <div className="grid grid-cols-3 gap-4">
               <div className="space-y-2">
-                <Label>{t('modelPrices.cacheRead')} ($/M)</Label>
+                <Label>{t('modelPrices.cacheRead')} (/M)</Label>
                 <Input
                   type="number"
                   step="0.01"
@@ -324,7 +445,7 @@ export function ModelPricesPage() {
                 />
               </div>
               <div className="space-y-2">
-                <Label>{t('modelPrices.cache5mWrite')} ($/M)</Label>
+                <Label>{t('modelPrices.cache5mWrite')} (/M)</Label>
                 <Input
                   type="number"
                   step="0.01"
@@ -334,7 +455,7 @@ export function ModelPricesPage() {
                 />
               </div>
               <div className="space-y-2">
-                <Label>{t('modelPrices.cache1hWrite')} ($/M)</Label>
+                <Label>{t('modelPrices.cache1hWrite')} (/M)</Label>
                 <Input
                   type="number"
                   step="0.01"
@@ -428,6 +549,12 @@ export function ModelPricesPage() {
         </DialogContent>
       </Dialog>
 
+      <ImportPricesDialog
+        open={importOpen}
+        onOpenChange={setImportOpen}
+        providers={providers || []}
+      />
+
       {/* Delete Confirmation Dialog */}
       <AlertDialog
         open={deleteConfirmId !== null}
@@ -466,4 +593,47 @@ export function ModelPricesPage() {
   );
 }
 
+function ExchangeRatesCard() {
+  const { t } = useTranslation();
+  const { data: settings } = useSettings();
+  const updateSetting = useUpdateSetting();
+
+  const saved = ratesToText(settings?.exchange_rates);
+  const [draft, setDraft] = useState(saved);
+  useEffect(() => setDraft(saved), [saved]);
+
+  const parsed = textToRates(draft);
+
+  return (
+    <Card className="border-border bg-card">
+      <CardContent className="p-6 space-y-2">
+        <div className="flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-3">
+          <Label className="shrink-0 sm:w-32">{t('modelPrices.exchangeRates')}</Label>
+          <Input
+            value={draft}
+            onChange={(e) => setDraft(e.target.value)}
+            placeholder="CNY=7.2, EUR=0.92"
+            className="flex-1 font-mono"
+            disabled={updateSetting.isPending}
+          />
+          <Button
+            size="sm"
+            onClick={() =>
+              parsed !== null && updateSetting.mutate({ key: 'exchange_rates', value: parsed })
+            }
+            disabled={parsed === null || draft === saved || updateSetting.isPending}
+          >
+            {updateSetting.isPending ? t('common.saving') : t('common.save')}
+          </Button>
+        </div>
+        <p className={`text-xs ${parsed === null ? 'text-destructive' : 'text-muted-foreground'}`}>
+          {t(
+            parsed === null ? 'modelPrices.exchangeRatesInvalid' : 'modelPrices.exchangeRatesDesc',
+          )}
+        </p>
+      </CardContent>
+    </Card>
+  );
+}
+
 export default ModelPricesPage;
@@ -18,6 +18,7 @@ import {
   Hash,
   Cpu,
   AlertTriangle,
+  ShieldAlert,
 } from 'lucide-react';
 import { Card, CardContent, CardHeader, CardTitle, ActivityHeatmap } from '@/components/ui';
 import {
@@ -39,7 +40,9 @@ import {
   useProxyRequests,
   useProxyRequestUpdates,
   useSessions,
+  useAnomalyAlerts,
 } from '@/hooks/queries';
+import type { AnomalyAlert } from '@/lib/transport';
 import { useCooldowns } from '@/hooks/use-cooldowns';
 import { CooldownTimer } from '@/components/cooldown-timer';
 import { useStreamingRequests } from '@/hooks/use-streaming';
@@ -73,6 +76,11 @@ function formatCost(nanoUsd: number): string {
   return '$' + usd.toFixed(6).replace(/\.?0+$/, '');
 }
 
+// 毛利可能为负
+function formatMargin(nanoUsd: number): string {
+  return nanoUsd < 0 ? '-' + formatCost(-nanoUsd) : formatCost(nanoUsd);
+}
+
 // 格式化相对时间
 function formatRelativeTime(dateStr: string): string {
   const date = new Date(dateStr);
@@ -117,6 +125,72 @@ function TrendIndicator({ value, suffix = '%' }: { value: number; suffix?: strin
   );
 }
 
+// 异常告警的指标值：成本为纳美元/小时，错误率为百分比
+function formatAnomalyValue(alert: AnomalyAlert, value: number): string {
+  switch (alert.metric) {
+    case 'cost_rate':
+      return `${formatCost(value)}/h`;
+    case 'error_rate':
+      return `${value.toFixed(1)}%`;
+    default:
+      return `${formatNumber(Math.round(value))}/h`;
+  }
+}
+
+// 用量异常告警卡片，没有告警时不显示
+function AnomalyAlertsCard() {
+  const { t } = useTranslation();
+  const { data: alerts } = useAnomalyAlerts();
+
+  if (!alerts || alerts.length === 0) {
+    return null;
+  }
+
+  return (
+    <Card className="border-red-500/30 bg-card/50 backdrop-blur-sm">
+      <CardHeader className="pb-2">
+        <CardTitle className="text-base font-semibold flex items-center gap-2">
+          <ShieldAlert className="h-4 w-4 text-red-500" />
+          {t('dashboard.anomalyAlerts')}
+        </CardTitle>
+      </CardHeader>
+      <CardContent>
+        <div className="space-y-1">
+          {alerts.slice(0, 5).map((alert) => (
+            <div
+              key={`${alert.time}-${alert.dimension}-${alert.targetID}-${alert.metric}`}
+              className="flex items-center gap-3 py-1.5 text-sm"
+            >
+              <span className="text-xs text-muted-foreground w-16 shrink-0">
+                {formatRelativeTime(alert.time)}
+              </span>
+              <span className="flex-1 min-w-0 truncate">
+                <span className="text-muted-foreground">
+                  {t(`dashboard.anomalyDimensions.${alert.dimension}`)}
+                </span>{' '}
+                <span className="font-medium">{alert.targetName}</span>
+              </span>
+              <span className="text-xs w-28">{t(`dashboard.anomalyMetrics.${alert.metric}`)}</span>
+              <span className="font-mono text-xs text-right w-40">
+                {formatAnomalyValue(alert, alert.current)}
+                <span className="text-muted-foreground">
+                  {' / '}
+                  {formatAnomalyValue(alert, alert.baseline)}
+                </span>
+              </span>
+              {alert.tokenDisabled && (
+                <span className="text-xs text-red-600 dark:text-red-400 shrink-0">
+                  {t('dashboard.anomalyTokenDisabled')}
+                </span>
+              )}
+            </div>
+          ))}
+        </div>
+      </CardContent>
+    </Card>
+  );
+}
+
 // 统计卡片组件
 function StatCard({
   title,
@@ -315,6 +389,11 @@ export function OverviewPage() {
             <StatCard
               title={t('dashboard.todayCost')}
               value={formatCost(summary?.todayCost || 0)}
+              subtitle={
+                summary?.todayMargin
+                  ? `${t('dashboard.margin')} ${formatMargin(summary.todayMargin)}`
+                  : undefined
+              }
               trend={summary?.costChange}
               icon={Coins}
               iconClassName="text-amber-600 dark:text-amber-400"
@@ -340,6 +419,8 @@ export function OverviewPage() {
             />
           </div>
 
+          <AnomalyAlertsCard />
+
           {/* 第二行：趋势图 + 使用统计 */}
           <div className="grid gap-4 grid-cols-1 lg:grid-cols-3">
             {/* 24小时趋势 */}
@@ -451,6 +532,17 @@ export function OverviewPage() {
                     {formatCost(allTimeStats?.totalCost || 0)}
                   </span>
                 </div>
+                {allTimeStats?.totalMargin !== 0 && (
+                  <div className="flex items-center justify-between">
+                    <span className="text-sm text-muted-foreground flex items-center gap-2">
+                      <Coins className="h-4 w-4" />
+                      {t('dashboard.margin')}
+                    </span>
+                    <span className="text-sm font-medium font-mono">
+                      {formatMargin(allTimeStats?.totalMargin || 0)}
+                    </span>
+                  </div>
+                )}
               </CardContent>
             </Card>
           </div>
@@ -2,7 +2,8 @@ import { useState } from 'react';
 import { Card, CardContent, CardHeader, CardTitle, Input, Button } from '@/components/ui';
 import { useUpdateProject, projectKeys } from '@/hooks/queries';
 import { useQueryClient } from '@tanstack/react-query';
-import type { Project } from '@/lib/transport';
+import { PrioritySelect } from '@/components/priority-select';
+import type { PriorityClass, Project } from '@/lib/transport';
 import { Loader2, Save, Copy, Check } from 'lucide-react';
 import { useTranslation } from 'react-i18next';
 
@@ -16,15 +17,17 @@ export function OverviewTab({ project }: OverviewTabProps) {
   const updateProject = useUpdateProject();
   const [name, setName] = useState(project.name);
   const [slug, setSlug] = useState(project.slug);
+  const [priority, setPriority] = useState<PriorityClass>(project.priority ?? '');
   const [copied, setCopied] = useState<string | null>(null);
 
-  const hasChanges = name !== project.name || slug !== project.slug;
+  const hasChanges =
+    name !== project.name || slug !== project.slug || priority !== (project.priority ?? '');
 
   const handleSave = () => {
     updateProject.mutate(
       {
         id: project.id,
-        data: { name, slug, enabledCustomRoutes: project.enabledCustomRoutes },
+        data: { name, slug, priority, enabledCustomRoutes: project.enabledCustomRoutes },
       },
       {
         onSuccess: () => {
@@ -81,6 +84,17 @@ export function OverviewTab({ project }: OverviewTabProps) {
               />
               <p className="text-xs text-text-muted">{t('projects.slugDesc')}</p>
             </div>
+            <div className="space-y-2">
+              <label className="text-sm font-medium text-text-primary">
+                {t('projects.priority')}
+              </label>
+              <PrioritySelect
+                value={priority}
+                onChange={setPriority}
+                disabled={updateProject.isPending}
+              />
+              <p className="text-xs text-text-muted">{t('projects.priorityDesc')}</p>
+            </div>
           </div>
 
           <div className="grid grid-cols-1 sm:grid-cols-2 gap-4 text-sm">
@@ -0,0 +1,305 @@
+import { useState } from 'react';
+import { ChevronLeft, Check, Key, Globe, Shield, Trash2, Tag, Layers } from 'lucide-react';
+import { useTranslation } from 'react-i18next';
+import { useCreateProvider, useUpdateProvider } from '@/hooks/queries';
+import type { CreateProviderData, Provider, ProviderConfigAzureOpenAI } from '@/lib/transport';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { Switch } from '@/components/ui';
+import { PageHeader } from '@/components/layout/page-header';
+import { useProviderNavigation } from '../hooks/use-provider-navigation';
+import { ModelMappingEditor } from './model-mapping-editor';
+
+const emptyConfig: ProviderConfigAzureOpenAI = {
+  endpoint: '',
+};
+
+interface AzureOpenAIConfigStepProps {
+  // 编辑已有 Provider 时传入，否则为创建流程
+  provider?: Provider;
+  onClose?: () => void;
+  onDelete?: () => void;
+}
+
+/**
+ * Azure OpenAI Provider 配置（创建与编辑共用）
+ */
+export function AzureOpenAIConfigStep({ provider, onClose, onDelete }: AzureOpenAIConfigStepProps) {
+  const { t } = useTranslation();
+  const { goToSelectType, goToProviders } = useProviderNavigation();
+  const createProvider = useCreateProvider();
+  const updateProvider = useUpdateProvider();
+
+  const [name, setName] = useState(provider?.name ?? '');
+  const [config, setConfig] = useState<ProviderConfigAzureOpenAI>({
+    ...emptyConfig,
+    ...provider?.config?.azureOpenAI,
+  });
+  const [disableErrorCooldown, setDisableErrorCooldown] = useState(
+    !!provider?.config?.disableErrorCooldown,
+  );
+  const [saving, setSaving] = useState(false);
+  const [saveStatus, setSaveStatus] = useState<'idle' | 'success' | 'error'>('idle');
+
+  const isEdit = !!provider;
+  // API Key 为空时需要完整的 Entra ID 客户端凭证
+  const hasAPIKey = !!config.apiKey?.trim();
+  const hasEntra = !!(
+    config.tenantID?.trim() &&
+    config.clientID?.trim() &&
+    config.clientSecret?.trim()
+  );
+  const isValid = name.trim() !== '' && config.endpoint.trim() !== '' && (hasAPIKey || hasEntra);
+
+  const update = (updates: Partial<ProviderConfigAzureOpenAI>) =>
+    setConfig((prev) => ({ ...prev, ...updates }));
+
+  const handleBack = () => (isEdit ? onClose?.() : goToSelectType());
+  const handleDone = () => (isEdit ? onClose?.() : goToProviders());
+
+  const handleSave = async () => {
+    if (!isValid) return;
+    setSaving(true);
+    setSaveStatus('idle');
+
+    const azureOpenAI: ProviderConfigAzureOpenAI = {
+      endpoint: config.endpoint.trim(),
+      apiVersion: config.apiVersion?.trim() || undefined,
+      apiKey: config.apiKey?.trim() || undefined,
+      tenantID: hasAPIKey ? undefined : config.tenantID?.trim(),
+      clientID: hasAPIKey ? undefined : config.clientID?.trim(),
+      clientSecret: hasAPIKey ? undefined : config.clientSecret?.trim(),
+      deployments:
+        config.deployments && Object.keys(config.deployments).length > 0
+          ? config.deployments
+          : undefined,
+    };
+
+    try {
+      if (provider) {
+        await updateProvider.mutateAsync({
+          id: provider.id,
+          data: {
+            name: name.trim(),
+            config: { ...provider.config, disableErrorCooldown, azureOpenAI },
+          },
+        });
+      } else {
+        const data: CreateProviderData = {
+          type: 'azure_openai',
+          name: name.trim(),
+          config: { disableErrorCooldown, azureOpenAI },
+        };
+        await createProvider.mutateAsync(data);
+      }
+      setSaveStatus('success');
+      setTimeout(handleDone, 500);
+    } catch (error) {
+      console.error('Failed to save provider:', error);
+      setSaveStatus('error');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="flex flex-col h-full">
+      <PageHeader
+        icon={<ChevronLeft className="cursor-pointer" onClick={handleBack} />}
+        title={isEdit ? t('provider.edit') : t('addProvider.azure_openai.name')}
+        description={t('addProvider.azure_openai.description')}
+      >
+        {isEdit && onDelete && (
+          <Button onClick={onDelete} variant={'destructive'}>
+            <Trash2 size={14} />
+            {t('provider.delete')}
+          </Button>
+        )}
+        <Button onClick={handleBack} variant={'secondary'}>
+          {t('common.cancel')}
+        </Button>
+        <Button onClick={handleSave} disabled={saving || !isValid} variant={'default'}>
+          {saving ? (
+            t('common.saving')
+          ) : saveStatus === 'success' ? (
+            <>
+              <Check size={14} /> {t('common.saved')}
+            </>
+          ) : isEdit ? (
+            t('provider.saveChanges')
+          ) : (
+            t('provider.create')
+          )}
+        </Button>
+      </PageHeader>
+
+      <div className="flex-1 overflow-y-auto p-6">
+        <div className="mx-auto max-w-7xl space-y-8">
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('provider.basicInfo')}
+            </h3>
+            <div className="grid gap-6">
+              <div>
+                <label className="text-sm font-medium text-text-primary block mb-2">
+                  {t('provider.displayName')}
+                </label>
+                <Input
+                  type="text"
+                  value={name}
+                  onChange={(e) => setName(e.target.value)}
+                  placeholder={t('provider.namePlaceholder')}
+                  className="w-full"
+                />
+              </div>
+              <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+                <div>
+                  <label className="text-sm font-medium text-foreground block mb-2">
+                    <div className="flex items-center gap-2">
+                      <Globe size={14} />
+                      <span>{t('addProvider.azure_openai.endpoint')}</span>
+                    </div>
+                  </label>
+                  <Input
+                    type="text"
+                    value={config.endpoint}
+                    onChange={(e) => update({ endpoint: e.target.value })}
+                    placeholder="https://my-resource.openai.azure.com"
+                    className="w-full font-mono"
+                  />
+                </div>
+                <div>
+                  <label className="text-sm font-medium text-foreground block mb-2">
+                    <div className="flex items-center gap-2">
+                      <Tag size={14} />
+                      <span>{t('addProvider.azure_openai.apiVersion')}</span>
+                    </div>
+                  </label>
+                  <Input
+                    type="text"
+                    value={config.apiVersion ?? ''}
+                    onChange={(e) => update({ apiVersion: e.target.value })}
+                    placeholder="2025-04-01-preview"
+                    className="w-full font-mono"
+                  />
+                  <p className="text-xs text-text-secondary mt-1">
+                    {t('addProvider.azure_openai.apiVersionHint')}
+                  </p>
+                </div>
+              </div>
+            </div>
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('addProvider.azure_openai.auth')}
+            </h3>
+            <div>
+              <label className="text-sm font-medium text-foreground block mb-2">
+                <div className="flex items-center gap-2">
+                  <Key size={14} />
+                  <span>{t('addProvider.azure_openai.apiKey')}</span>
+                </div>
+              </label>
+              <Input
+                type="password"
+                value={config.apiKey ?? ''}
+                onChange={(e) => update({ apiKey: e.target.value })}
+                placeholder={t('addProvider.optional')}
+                className="w-full font-mono"
+              />
+              <p className="text-xs text-text-secondary mt-1">
+                {t('addProvider.azure_openai.entraHint')}
+              </p>
+            </div>
+            {!hasAPIKey && (
+              <div className="space-y-3">
+                <div className="flex items-center gap-2 text-sm font-medium text-foreground">
+                  <Shield size={14} />
+                  <span>{t('addProvider.azure_openai.entra')}</span>
+                </div>
+                <div className="grid grid-cols-1 md:grid-cols-3 gap-6">
+                  <div>
+                    <label className="text-sm font-medium text-foreground block mb-2">
+                      {t('addProvider.azure_openai.tenantID')}
+                    </label>
+                    <Input
+                      type="text"
+                      value={config.tenantID ?? ''}
+                      onChange={(e) => update({ tenantID: e.target.value })}
+                      placeholder="00000000-0000-0000-0000-000000000000"
+                      className="w-full font-mono"
+                    />
+                  </div>
+                  <div>
+                    <label className="text-sm font-medium text-foreground block mb-2">
+                      {t('addProvider.azure_openai.clientID')}
+                    </label>
+                    <Input
+                      type="text"
+                      value={config.clientID ?? ''}
+                      onChange={(e) => update({ clientID: e.target.value })}
+                      placeholder="00000000-0000-0000-0000-000000000000"
+                      className="w-full font-mono"
+                    />
+                  </div>
+                  <div>
+                    <label className="text-sm font-medium text-foreground block mb-2">
+                      {t('addProvider.azure_openai.clientSecret')}
+                    </label>
+                    <Input
+                      type="password"
+                      value={config.clientSecret ?? ''}
+                      onChange={(e) => update({ clientSecret: e.target.value })}
+                      className="w-full font-mono"
+                    />
+                  </div>
+                </div>
+              </div>
+            )}
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              <div className="flex items-center gap-2">
+                <Layers size={18} />
+                <span>{t('addProvider.azure_openai.deployments')}</span>
+              </div>
+            </h3>
+            <p className="text-xs text-text-secondary">
+              {t('addProvider.azure_openai.deploymentsHint')}
+            </p>
+            <ModelMappingEditor
+              value={config.deployments ?? {}}
+              onChange={(deployments) => update({ deployments })}
+            />
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('provider.errorCooldownTitle')}
+            </h3>
+            <div className="flex items-center justify-between p-4 bg-card border border-border rounded-xl">
+              <div className="pr-4">
+                <div className="text-sm font-medium text-foreground">
+                  {t('provider.disableErrorCooldown')}
+                </div>
+                <p className="text-xs text-muted-foreground mt-1">
+                  {t('provider.disableErrorCooldownDesc')}
+                </p>
+              </div>
+              <Switch checked={disableErrorCooldown} onCheckedChange={setDisableErrorCooldown} />
+            </div>
+          </div>
+
+          {saveStatus === 'error' && (
+            <div className="p-4 bg-error/10 border border-error/30 rounded-lg text-sm text-error flex items-center gap-2">
+              <div className="w-1.5 h-1.5 rounded-full bg-error" />
+              {isEdit ? t('provider.updateError') : t('provider.createError')}
+            </div>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+}
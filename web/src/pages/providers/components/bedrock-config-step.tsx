@@ -0,0 +1,314 @@
+import { useState } from 'react';
+import { ChevronLeft, Check, Key, Globe, Shield, Trash2, MapPin } from 'lucide-react';
+import { useTranslation } from 'react-i18next';
+import { useCreateProvider, useUpdateProvider } from '@/hooks/queries';
+import type { CreateProviderData, Provider, ProviderConfigBedrock } from '@/lib/transport';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { Switch } from '@/components/ui';
+import { PageHeader } from '@/components/layout/page-header';
+import { useProviderNavigation } from '../hooks/use-provider-navigation';
+
+const emptyConfig: ProviderConfigBedrock = {
+  region: 'us-east-1',
+  accessKeyID: '',
+  secretAccessKey: '',
+};
+
+interface BedrockConfigStepProps {
+  // 编辑已有 Provider 时传入，否则为创建流程
+  provider?: Provider;
+  onClose?: () => void;
+  onDelete?: () => void;
+}
+
+/**
+ * AWS Bedrock Provider 配置（创建与编辑共用）
+ */
+export function BedrockConfigStep({ provider, onClose, onDelete }: BedrockConfigStepProps) {
+  const { t } = useTranslation();
+  const { goToSelectType, goToProviders } = useProviderNavigation();
+  const createProvider = useCreateProvider();
+  const updateProvider = useUpdateProvider();
+
+  const [name, setName] = useState(provider?.name ?? '');
+  const [config, setConfig] = useState<ProviderConfigBedrock>({
+    ...emptyConfig,
+    ...provider?.config?.bedrock,
+  });
+  const [disableErrorCooldown, setDisableErrorCooldown] = useState(
+    !!provider?.config?.disableErrorCooldown,
+  );
+  const [saving, setSaving] = useState(false);
+  const [saveStatus, setSaveStatus] = useState<'idle' | 'success' | 'error'>('idle');
+
+  const isEdit = !!provider;
+  const isValid =
+    name.trim() !== '' && config.accessKeyID.trim() !== '' && config.secretAccessKey.trim() !== '';
+
+  const update = (updates: Partial<ProviderConfigBedrock>) =>
+    setConfig((prev) => ({ ...prev, ...updates }));
+
+  const handleBack = () => (isEdit ? onClose?.() : goToSelectType());
+  const handleDone = () => (isEdit ? onClose?.() : goToProviders());
+
+  const handleSave = async () => {
+    if (!isValid) return;
+    setSaving(true);
+    setSaveStatus('idle');
+
+    const bedrock: ProviderConfigBedrock = {
+      ...config,
+      region: config.region?.trim() || undefined,
+      accessKeyID: config.accessKeyID.trim(),
+      secretAccessKey: config.secretAccessKey.trim(),
+      sessionToken: config.sessionToken?.trim() || undefined,
+      roleARN: config.roleARN?.trim() || undefined,
+      externalID: config.externalID?.trim() || undefined,
+      endpoint: config.endpoint?.trim() || undefined,
+      inferenceProfilePrefix: config.inferenceProfilePrefix?.trim() || undefined,
+    };
+
+    try {
+      if (provider) {
+        await updateProvider.mutateAsync({
+          id: provider.id,
+          data: {
+            name: name.trim(),
+            config: { ...provider.config, disableErrorCooldown, bedrock },
+          },
+        });
+      } else {
+        const data: CreateProviderData = {
+          type: 'bedrock',
+          name: name.trim(),
+          config: { disableErrorCooldown, bedrock },
+        };
+        await createProvider.mutateAsync(data);
+      }
+      setSaveStatus('success');
+      setTimeout(handleDone, 500);
+    } catch (error) {
+      console.error('Failed to save provider:', error);
+      setSaveStatus('error');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="flex flex-col h-full">
+      <PageHeader
+        icon={<ChevronLeft className="cursor-pointer" onClick={handleBack} />}
+        title={isEdit ? t('provider.edit') : t('addProvider.bedrock.name')}
+        description={t('addProvider.bedrock.description')}
+      >
+        {isEdit && onDelete && (
+          <Button onClick={onDelete} variant={'destructive'}>
+            <Trash2 size={14} />
+            {t('provider.delete')}
+          </Button>
+        )}
+        <Button onClick={handleBack} variant={'secondary'}>
+          {t('common.cancel')}
+        </Button>
+        <Button onClick={handleSave} disabled={saving || !isValid} variant={'default'}>
+          {saving ? (
+            t('common.saving')
+          ) : saveStatus === 'success' ? (
+            <>
+              <Check size={14} /> {t('common.saved')}
+            </>
+          ) : isEdit ? (
+            t('provider.saveChanges')
+          ) : (
+            t('provider.create')
+          )}
+        </Button>
+      </PageHeader>
+
+      <div className="flex-1 overflow-y-auto p-6">
+        <div className="mx-auto max-w-7xl space-y-8">
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('provider.basicInfo')}
+            </h3>
+            <div className="grid gap-6">
+              <div>
+                <label className="text-sm font-medium text-text-primary block mb-2">
+                  {t('provider.displayName')}
+                </label>
+                <Input
+                  type="text"
+                  value={name}
+                  onChange={(e) => setName(e.target.value)}
+                  placeholder={t('provider.namePlaceholder')}
+                  className="w-full"
+                />
+              </div>
+              <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+                <div>
+                  <label className="text-sm font-medium text-foreground block mb-2">
+                    <div className="flex items-center gap-2">
+                      <MapPin size={14} />
+                      <span>{t('addProvider.bedrock.region')}</span>
+                    </div>
+                  </label>
+                  <Input
+                    type="text"
+                    value={config.region ?? ''}
+                    onChange={(e) => update({ region: e.target.value })}
+                    placeholder="us-east-1"
+                    className="w-full font-mono"
+                  />
+                </div>
+                <div>
+                  <label className="text-sm font-medium text-foreground block mb-2">
+                    <div className="flex items-center gap-2">
+                      <Globe size={14} />
+                      <span>{t('addProvider.bedrock.endpoint')}</span>
+                    </div>
+                  </label>
+                  <Input
+                    type="text"
+                    value={config.endpoint ?? ''}
+                    onChange={(e) => update({ endpoint: e.target.value })}
+                    placeholder="https://bedrock-runtime.us-east-1.amazonaws.com"
+                    className="w-full font-mono"
+                  />
+                  <p className="text-xs text-text-secondary mt-1">
+                    {t('addProvider.bedrock.endpointHint')}
+                  </p>
+                </div>
+              </div>
+            </div>
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('addProvider.bedrock.credentials')}
+            </h3>
+            <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+              <div>
+                <label className="text-sm font-medium text-foreground block mb-2">
+                  <div className="flex items-center gap-2">
+                    <Key size={14} />
+                    <span>{t('addProvider.bedrock.accessKeyID')}</span>
+                  </div>
+                </label>
+                <Input
+                  type="text"
+                  value={config.accessKeyID}
+                  onChange={(e) => update({ accessKeyID: e.target.value })}
+                  placeholder="AKIA..."
+                  className="w-full font-mono"
+                />
+              </div>
+              <div>
+                <label className="text-sm font-medium text-foreground block mb-2">
+                  <div className="flex items-center gap-2">
+                    <Key size={14} />
+                    <span>{t('addProvider.bedrock.secretAccessKey')}</span>
+                  </div>
+                </label>
+                <Input
+                  type="password"
+                  value={config.secretAccessKey}
+                  onChange={(e) => update({ secretAccessKey: e.target.value })}
+                  className="w-full font-mono"
+                />
+              </div>
+              <div className="md:col-span-2">
+                <label className="text-sm font-medium text-foreground block mb-2">
+                  {t('addProvider.bedrock.sessionToken')}
+                </label>
+                <Input
+                  type="password"
+                  value={config.sessionToken ?? ''}
+                  onChange={(e) => update({ sessionToken: e.target.value })}
+                  placeholder={t('addProvider.optional')}
+                  className="w-full font-mono"
+                />
+              </div>
+              <div>
+                <label className="text-sm font-medium text-foreground block mb-2">
+                  <div className="flex items-center gap-2">
+                    <Shield size={14} />
+                    <span>{t('addProvider.bedrock.roleARN')}</span>
+                  </div>
+                </label>
+                <Input
+                  type="text"
+                  value={config.roleARN ?? ''}
+                  onChange={(e) => update({ roleARN: e.target.value })}
+                  placeholder="arn:aws:iam::123456789012:role/bedrock-access"
+                  className="w-full font-mono"
+                />
+                <p className="text-xs text-text-secondary mt-1">
+                  {t('addProvider.bedrock.roleARNHint')}
+                </p>
+              </div>
+              <div>
+                <label className="text-sm font-medium text-foreground block mb-2">
+                  {t('addProvider.bedrock.externalID')}
+                </label>
+                <Input
+                  type="text"
+                  value={config.externalID ?? ''}
+                  onChange={(e) => update({ externalID: e.target.value })}
+                  placeholder={t('addProvider.optional')}
+                  className="w-full font-mono"
+                />
+              </div>
+            </div>
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('addProvider.bedrock.models')}
+            </h3>
+            <div>
+              <label className="text-sm font-medium text-foreground block mb-2">
+                {t('addProvider.bedrock.inferenceProfilePrefix')}
+              </label>
+              <Input
+                type="text"
+                value={config.inferenceProfilePrefix ?? ''}
+                onChange={(e) => update({ inferenceProfilePrefix: e.target.value })}
+                placeholder="us / eu / apac / global"
+                className="w-full md:w-80 font-mono"
+              />
+              <p className="text-xs text-text-secondary mt-1">
+                {t('addProvider.bedrock.modelsHint')}
+              </p>
+            </div>
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('provider.errorCooldownTitle')}
+            </h3>
+            <div className="flex items-center justify-between p-4 bg-card border border-border rounded-xl">
+              <div className="pr-4">
+                <div className="text-sm font-medium text-foreground">
+                  {t('provider.disableErrorCooldown')}
+                </div>
+                <p className="text-xs text-muted-foreground mt-1">
+                  {t('provider.disableErrorCooldownDesc')}
+                </p>
+              </div>
+              <Switch checked={disableErrorCooldown} onCheckedChange={setDisableErrorCooldown} />
+            </div>
+          </div>
+
+          {saveStatus === 'error' && (
+            <div className="p-4 bg-error/10 border border-error/30 rounded-lg text-sm text-error flex items-center gap-2">
+              <div className="w-1.5 h-1.5 rounded-full bg-error" />
+              {isEdit ? t('provider.updateError') : t('provider.createError')}
+            </div>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,515 @@
+import { useEffect, useRef, useState } from 'react';
+import {
+  ChevronLeft,
+  Check,
+  Copy,
+  ExternalLink,
+  Github,
+  Key,
+  Loader2,
+  LogIn,
+  RefreshCw,
+  Trash2,
+} from 'lucide-react';
+import { useTranslation } from 'react-i18next';
+import { useCreateProvider, useUpdateProvider } from '@/hooks/queries';
+import {
+  getTransport,
+  type CopilotDeviceFlowResult,
+  type CopilotDeviceFlowStart,
+  type CopilotQuotaSnapshot,
+  type CopilotUsageResponse,
+  type CreateProviderData,
+  type Provider,
+  type ProviderConfigCopilot,
+} from '@/lib/transport';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { Badge } from '@/components/ui/badge';
+import { Switch } from '@/components/ui';
+import { PageHeader } from '@/components/layout/page-header';
+import { useProviderNavigation } from '../hooks/use-provider-navigation';
+import { ModelMappingEditor } from './model-mapping-editor';
+
+const emptyConfig: ProviderConfigCopilot = {
+  username: '',
+  githubToken: '',
+};
+
+type AccountInfo = Pick<
+  CopilotDeviceFlowResult,
+  | 'githubToken'
+  | 'copilotToken'
+  | 'expiresAt'
+  | 'apiEndpoint'
+  | 'username'
+  | 'name'
+  | 'avatarURL'
+  | 'plan'
+>;
+
+interface CopilotConfigStepProps {
+  // 编辑已有 Provider 时传入，否则为创建流程
+  provider?: Provider;
+  onClose?: () => void;
+  onDelete?: () => void;
+}
+
+/**
+ * GitHub Copilot Provider 配置（device flow 登录，创建与编辑共用）
+ */
+export function CopilotConfigStep({ provider, onClose, onDelete }: CopilotConfigStepProps) {
+  const { t } = useTranslation();
+  const { goToSelectType, goToProviders } = useProviderNavigation();
+  const createProvider = useCreateProvider();
+  const updateProvider = useUpdateProvider();
+
+  const [name, setName] = useState(provider?.name ?? '');
+  const [config, setConfig] = useState<ProviderConfigCopilot>({
+    ...emptyConfig,
+    ...provider?.config?.copilot,
+  });
+  const [device, setDevice] = useState<CopilotDeviceFlowStart | null>(null);
+  const [starting, setStarting] = useState(false);
+  const [copied, setCopied] = useState(false);
+  const [manualToken, setManualToken] = useState('');
+  const [validating, setValidating] = useState(false);
+  const [loginError, setLoginError] = useState('');
+  const [usage, setUsage] = useState<CopilotUsageResponse | null>(null);
+  const [loadingUsage, setLoadingUsage] = useState(false);
+  const [disableErrorCooldown, setDisableErrorCooldown] = useState(
+    !!provider?.config?.disableErrorCooldown,
+  );
+  const [saving, setSaving] = useState(false);
+  const [saveStatus, setSaveStatus] = useState<'idle' | 'success' | 'error'>('idle');
+  // 卸载时取消仍在轮询的 device flow
+  const sessionRef = useRef<string | null>(null);
+
+  const isEdit = !!provider;
+  const isSignedIn = config.githubToken !== '';
+  const isValid = name.trim() !== '' && isSignedIn;
+
+  const handleBack = () => (isEdit ? onClose?.() : goToSelectType());
+  const handleDone = () => (isEdit ? onClose?.() : goToProviders());
+
+  const applyAccount = (account: AccountInfo) => {
+    setConfig((prev) => ({
+      ...prev,
+      githubToken: account.githubToken ?? '',
+      copilotToken: account.copilotToken,
+      expiresAt: account.expiresAt,
+      apiEndpoint: account.apiEndpoint,
+      username: account.username ?? '',
+      name: account.name,
+      avatarURL: account.avatarURL,
+      plan: account.plan,
+    }));
+    setName((prev) => prev || `Copilot - ${account.username}`);
+  };
+
+  // Subscribe to device flow results via WebSocket
+  useEffect(() => {
+    const transport = getTransport();
+    const unsubscribe = transport.subscribe<CopilotDeviceFlowResult>(
+      'copilot_device_result',
+      (result) => {
+        if (result.sessionId !== device?.sessionId) return;
+        sessionRef.current = null;
+        setDevice(null);
+        if (result.success && result.githubToken) {
+          applyAccount(result);
+        } else {
+          setLoginError(result.error || t('addProvider.copilot.errors.loginFailed'));
+        }
+      },
+    );
+    return () => unsubscribe();
+  }, [device?.sessionId, t]);
+
+  useEffect(() => {
+    return () => {
+      if (sessionRef.current) {
+        getTransport().cancelCopilotDeviceFlow(sessionRef.current).catch(() => {});
+      }
+    };
+  }, []);
+
+  const handleStartLogin = async () => {
+    setStarting(true);
+    setLoginError('');
+    setCopied(false);
+    try {
+      const result = await getTransport().startCopilotDeviceFlow();
+      sessionRef.current = result.sessionId;
+      setDevice(result);
+      window.open(result.verificationUri, '_blank', 'noopener,noreferrer');
+    } catch (error) {
+      console.error('Failed to start device flow:', error);
+      setLoginError(t('addProvider.copilot.errors.startFailed'));
+    } finally {
+      setStarting(false);
+    }
+  };
+
+  const handleCancelLogin = () => {
+    if (device) {
+      getTransport().cancelCopilotDeviceFlow(device.sessionId).catch(() => {});
+    }
+    sessionRef.current = null;
+    setDevice(null);
+  };
+
+  const handleCopyCode = async () => {
+    if (!device) return;
+    await navigator.clipboard.writeText(device.userCode);
+    setCopied(true);
+  };
+
+  const handleValidateToken = async () => {
+    setValidating(true);
+    setLoginError('');
+    try {
+      const result = await getTransport().validateCopilotToken(manualToken.trim());
+      if (result.valid) {
+        applyAccount(result);
+        setManualToken('');
+      } else {
+        setLoginError(result.error || t('addProvider.copilot.errors.invalidToken'));
+      }
+    } catch (error) {
+      console.error('Failed to validate token:', error);
+      setLoginError(t('addProvider.copilot.errors.invalidToken'));
+    } finally {
+      setValidating(false);
+    }
+  };
+
+  const handleLoadUsage = async () => {
+    if (!provider) return;
+    setLoadingUsage(true);
+    try {
+      setUsage(await getTransport().getCopilotProviderUsage(provider.id));
+    } catch (error) {
+      console.error('Failed to load usage:', error);
+    } finally {
+      setLoadingUsage(false);
+    }
+  };
+
+  const handleSave = async () => {
+    if (!isValid) return;
+    setSaving(true);
+    setSaveStatus('idle');
+
+    const copilot: ProviderConfigCopilot = {
+      ...config,
+      modelMapping:
+        config.modelMapping && Object.keys(config.modelMapping).length > 0
+          ? config.modelMapping
+          : undefined,
+    };
+
+    try {
+      if (provider) {
+        await updateProvider.mutateAsync({
+          id: provider.id,
+          data: {
+            name: name.trim(),
+            config: { ...provider.config, disableErrorCooldown, copilot },
+          },
+        });
+      } else {
+        const data: CreateProviderData = {
+          type: 'copilot',
+          name: name.trim(),
+          config: { disableErrorCooldown, copilot },
+        };
+        await createProvider.mutateAsync(data);
+      }
+      setSaveStatus('success');
+      setTimeout(handleDone, 500);
+    } catch (error) {
+      console.error('Failed to save provider:', error);
+      setSaveStatus('error');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const renderQuota = (label: string, snapshot?: CopilotQuotaSnapshot) => {
+    if (!snapshot) return null;
+    const percentage = snapshot.unlimited ? 100 : Math.max(0, snapshot.percentRemaining);
+    return (
+      <div className="space-y-1.5">
+        <div className="flex items-center justify-between text-xs">
+          <span className="font-medium text-foreground">{label}</span>
+          <span className="font-mono text-muted-foreground">
+            {snapshot.unlimited
+              ? t('addProvider.copilot.unlimited')
+              : `${snapshot.remaining} / ${snapshot.entitlement}`}
+          </span>
+        </div>
+        <div className="h-1.5 w-full bg-muted rounded-full overflow-hidden">
+          <div
+            className={`h-full rounded-full ${
+              percentage >= 50 ? 'bg-success' : percentage >= 20 ? 'bg-warning' : 'bg-error'
+            }`}
+            style={{ width: `${percentage}%` }}
+          />
+        </div>
+      </div>
+    );
+  };
+
+  return (
+    <div className="flex flex-col h-full">
+      <PageHeader
+        icon={<ChevronLeft className="cursor-pointer" onClick={handleBack} />}
+        title={isEdit ? t('provider.edit') : t('addProvider.copilot.name')}
+        description={t('addProvider.copilot.description')}
+      >
+        {isEdit && onDelete && (
+          <Button onClick={onDelete} variant={'destructive'}>
+            <Trash2 size={14} />
+            {t('provider.delete')}
+          </Button>
+        )}
+        <Button onClick={handleBack} variant={'secondary'}>
+          {t('common.cancel')}
+        </Button>
+        <Button onClick={handleSave} disabled={saving || !isValid} variant={'default'}>
+          {saving ? (
+            t('common.saving')
+          ) : saveStatus === 'success' ? (
+            <>
+              <Check size={14} /> {t('common.saved')}
+            </>
+          ) : isEdit ? (
+            t('provider.saveChanges')
+          ) : (
+            t('provider.create')
+          )}
+        </Button>
+      </PageHeader>
+
+      <div className="flex-1 overflow-y-auto p-6">
+        <div className="mx-auto max-w-7xl space-y-8">
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('addProvider.copilot.account')}
+            </h3>
+
+            {isSignedIn && (
+              <div className="flex items-center justify-between gap-4 p-4 bg-card border border-border rounded-xl">
+                <div className="flex items-center gap-3 min-w-0">
+                  {config.avatarURL ? (
+                    <img src={config.avatarURL} alt="" className="size-10 rounded-full shrink-0" />
+                  ) : (
+                    <div className="size-10 rounded-full bg-provider-copilot/15 flex items-center justify-center shrink-0">
+                      <Github size={18} className="text-provider-copilot" />
+                    </div>
+                  )}
+                  <div className="min-w-0">
+                    <div className="text-sm font-medium text-foreground truncate">
+                      {config.name || config.username}
+                    </div>
+                    <div className="text-xs text-muted-foreground font-mono truncate">
+                      @{config.username}
+                    </div>
+                  </div>
+                  {config.plan && (
+                    <Badge variant="outline" className="capitalize">
+                      {config.plan}
+                    </Badge>
+                  )}
+                </div>
+                <Button
+                  variant="secondary"
+                  size="sm"
+                  onClick={handleStartLogin}
+                  disabled={starting || !!device}
+                >
+                  <LogIn size={14} />
+                  {t('addProvider.copilot.relogin')}
+                </Button>
+              </div>
+            )}
+
+            {device ? (
+              <div className="p-6 bg-card border border-border rounded-xl space-y-4 text-center">
+                <p className="text-sm text-muted-foreground">
+                  {t('addProvider.copilot.enterCode')}
+                </p>
+                <div className="flex items-center justify-center gap-2">
+                  <span className="text-3xl font-mono font-bold tracking-widest text-foreground">
+                    {device.userCode}
+                  </span>
+                  <Button variant="ghost" size="icon" onClick={handleCopyCode}>
+                    {copied ? <Check size={16} /> : <Copy size={16} />}
+                  </Button>
+                </div>
+                <a
+                  href={device.verificationUri}
+                  target="_blank"
+                  rel="noopener noreferrer"
+                  className="inline-flex items-center gap-1 text-sm text-primary hover:underline"
+                >
+                  {device.verificationUri}
+                  <ExternalLink size={12} />
+                </a>
+                <div className="flex items-center justify-center gap-2 text-xs text-muted-foreground">
+                  <Loader2 size={12} className="animate-spin" />
+                  {t('addProvider.copilot.waiting')}
+                </div>
+                <Button variant="secondary" size="sm" onClick={handleCancelLogin}>
+                  {t('common.cancel')}
+                </Button>
+              </div>
+            ) : (
+              !isSignedIn && (
+                <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+                  <div className="p-4 bg-card border border-border rounded-xl space-y-3">
+                    <p className="text-sm text-muted-foreground">
+                      {t('addProvider.copilot.loginHint')}
+                    </p>
+                    <Button onClick={handleStartLogin} disabled={starting}>
+                      {starting ? (
+                        <Loader2 size={14} className="animate-spin" />
+                      ) : (
+                        <Github size={14} />
+                      )}
+                      {t('addProvider.copilot.login')}
+                    </Button>
+                  </div>
+                  <div className="p-4 bg-card border border-border rounded-xl space-y-3">
+                    <label className="text-sm font-medium text-foreground block">
+                      <div className="flex items-center gap-2">
+                        <Key size={14} />
+                        <span>{t('addProvider.copilot.githubToken')}</span>
+                      </div>
+                    </label>
+                    <div className="flex gap-2">
+                      <Input
+                        type="password"
+                        value={manualToken}
+                        onChange={(e) => setManualToken(e.target.value)}
+                        placeholder="ghu_..."
+                        className="w-full font-mono"
+                      />
+                      <Button
+                        variant="secondary"
+                        onClick={handleValidateToken}
+                        disabled={validating || manualToken.trim() === ''}
+                      >
+                        {validating && <Loader2 size={14} className="animate-spin" />}
+                        {t('addProvider.copilot.validate')}
+                      </Button>
+                    </div>
+                  </div>
+                </div>
+              )
+            )}
+            {loginError && <p className="text-xs text-error">{loginError}</p>}
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('provider.basicInfo')}
+            </h3>
+            <div>
+              <label className="text-sm font-medium text-text-primary block mb-2">
+                {t('provider.displayName')}
+              </label>
+              <Input
+                type="text"
+                value={name}
+                onChange={(e) => setName(e.target.value)}
+                placeholder={t('provider.namePlaceholder')}
+                className="w-full"
+              />
+            </div>
+          </div>
+
+          {isEdit && (
+            <div className="space-y-6">
+              <div className="flex items-center justify-between border-b border-border pb-2">
+                <h3 className="text-lg font-semibold text-text-primary">
+                  {t('addProvider.copilot.usage')}
+                </h3>
+                <Button
+                  variant="secondary"
+                  size="sm"
+                  onClick={handleLoadUsage}
+                  disabled={loadingUsage}
+                >
+                  {loadingUsage ? (
+                    <Loader2 size={14} className="animate-spin" />
+                  ) : (
+                    <RefreshCw size={14} />
+                  )}
+                  {t('common.refresh')}
+                </Button>
+              </div>
+              {usage ? (
+                <div className="grid grid-cols-1 md:grid-cols-3 gap-6">
+                  {renderQuota(
+                    t('addProvider.copilot.premiumInteractions'),
+                    usage.premiumInteractions,
+                  )}
+                  {renderQuota(t('addProvider.copilot.chat'), usage.chat)}
+                  {renderQuota(t('addProvider.copilot.completions'), usage.completions)}
+                </div>
+              ) : (
+                <p className="text-xs text-muted-foreground">
+                  {t('addProvider.copilot.usageEmpty')}
+                </p>
+              )}
+              {usage?.resetDate && (
+                <p className="text-xs text-text-secondary">
+                  {t('addProvider.copilot.resetDate', { date: usage.resetDate })}
+                </p>
+              )}
+            </div>
+          )}
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('addProvider.copilot.modelMapping')}
+            </h3>
+            <p className="text-xs text-text-secondary">
+              {t('addProvider.copilot.modelMappingHint')}
+            </p>
+            <ModelMappingEditor
+              value={config.modelMapping ?? {}}
+              onChange={(modelMapping) => setConfig((prev) => ({ ...prev, modelMapping }))}
+            />
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('provider.errorCooldownTitle')}
+            </h3>
+            <div className="flex items-center justify-between p-4 bg-card border border-border rounded-xl">
+              <div className="pr-4">
+                <div className="text-sm font-medium text-foreground">
+                  {t('provider.disableErrorCooldown')}
+                </div>
+                <p className="text-xs text-muted-foreground mt-1">
+                  {t('provider.disableErrorCooldownDesc')}
+                </p>
+              </div>
+              <Switch checked={disableErrorCooldown} onCheckedChange={setDisableErrorCooldown} />
+            </div>
+          </div>
+
+          {saveStatus === 'error' && (
+            <div className="p-4 bg-error/10 border border-error/30 rounded-lg text-sm text-error flex items-center gap-2">
+              <div className="w-1.5 h-1.5 rounded-full bg-error" />
+              {isEdit ? t('provider.updateError') : t('provider.createError')}
+            </div>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+}
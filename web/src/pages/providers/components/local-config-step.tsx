@@ -0,0 +1,397 @@
+import { useState } from 'react';
+import {
+  ChevronLeft,
+  Check,
+  Key,
+  Globe,
+  Trash2,
+  Cpu,
+  RefreshCw,
+  Loader2,
+  X,
+  DollarSign,
+} from 'lucide-react';
+import { useTranslation } from 'react-i18next';
+import { useCreateProvider, useUpdateProvider } from '@/hooks/queries';
+import {
+  getTransport,
+  type CreateProviderData,
+  type LocalBackend,
+  type Provider,
+  type ProviderConfigLocal,
+} from '@/lib/transport';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { Badge } from '@/components/ui/badge';
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select';
+import { Switch } from '@/components/ui';
+import { PageHeader } from '@/components/layout/page-header';
+import { useProviderNavigation } from '../hooks/use-provider-navigation';
+import { ModelMappingEditor } from './model-mapping-editor';
+
+const emptyConfig: ProviderConfigLocal = {
+  baseURL: '',
+  backend: 'ollama',
+};
+
+// 价格以 USD/M tokens 输入，存储为 microUSD/M tokens
+const toPriceMicro = (value: string) => {
+  const price = parseFloat(value);
+  return Number.isFinite(price) && price > 0 ? Math.round(price * 1_000_000) : undefined;
+};
+const fromPriceMicro = (micro?: number) => (micro ? String(micro / 1_000_000) : '');
+
+interface LocalConfigStepProps {
+  // 编辑已有 Provider 时传入，否则为创建流程
+  provider?: Provider;
+  onClose?: () => void;
+  onDelete?: () => void;
+}
+
+/**
+ * 本地模型 Provider 配置（Ollama / llama.cpp / vLLM，创建与编辑共用）
+ */
+export function LocalConfigStep({ provider, onClose, onDelete }: LocalConfigStepProps) {
+  const { t } = useTranslation();
+  const { goToSelectType, goToProviders } = useProviderNavigation();
+  const createProvider = useCreateProvider();
+  const updateProvider = useUpdateProvider();
+
+  const [name, setName] = useState(provider?.name ?? '');
+  const [config, setConfig] = useState<ProviderConfigLocal>({
+    ...emptyConfig,
+    ...provider?.config?.local,
+  });
+  const [inputPrice, setInputPrice] = useState(fromPriceMicro(config.inputPriceMicro));
+  const [outputPrice, setOutputPrice] = useState(fromPriceMicro(config.outputPriceMicro));
+  // 已发现的模型（不含映射来源模型）
+  const mappingKeys = Object.keys(config.modelMapping ?? {});
+  const [models, setModels] = useState<string[]>(
+    (provider?.supportModels ?? []).filter((m) => !mappingKeys.includes(m)),
+  );
+  const [discovering, setDiscovering] = useState(false);
+  const [discoverError, setDiscoverError] = useState('');
+  const [disableErrorCooldown, setDisableErrorCooldown] = useState(
+    !!provider?.config?.disableErrorCooldown,
+  );
+  const [saving, setSaving] = useState(false);
+  const [saveStatus, setSaveStatus] = useState<'idle' | 'success' | 'error'>('idle');
+
+  const isEdit = !!provider;
+  const isValid = name.trim() !== '' && config.baseURL.trim() !== '';
+
+  const update = (updates: Partial<ProviderConfigLocal>) =>
+    setConfig((prev) => ({ ...prev, ...updates }));
+
+  const handleBack = () => (isEdit ? onClose?.() : goToSelectType());
+  const handleDone = () => (isEdit ? onClose?.() : goToProviders());
+
+  const discover = async (): Promise<string[] | null> => {
+    setDiscovering(true);
+    setDiscoverError('');
+    try {
+      const result = await getTransport().discoverLocalModels({
+        baseURL: config.baseURL.trim(),
+        backend: config.backend,
+        apiKey: config.apiKey?.trim() || undefined,
+      });
+      setModels(result.models);
+      return result.models;
+    } catch (error) {
+      console.error('Failed to discover models:', error);
+      setDiscoverError(t('addProvider.local.discoverError'));
+      return null;
+    } finally {
+      setDiscovering(false);
+    }
+  };
+
+  const handleSave = async () => {
+    if (!isValid) return;
+    setSaving(true);
+    setSaveStatus('idle');
+
+    // 首次创建时自动发现模型，失败不阻止保存
+    let discovered = models;
+    if (!isEdit && discovered.length === 0) {
+      discovered = (await discover()) ?? [];
+    }
+    // 路由按请求模型匹配 supportModels，映射来源模型也需要加入
+    const modelMapping =
+      config.modelMapping && Object.keys(config.modelMapping).length > 0
+        ? config.modelMapping
+        : undefined;
+    const supportModels = Array.from(new Set([...discovered, ...Object.keys(modelMapping ?? {})]));
+
+    const local: ProviderConfigLocal = {
+      baseURL: config.baseURL.trim(),
+      backend: config.backend,
+      apiKey: config.apiKey?.trim() || undefined,
+      inputPriceMicro: toPriceMicro(inputPrice),
+      outputPriceMicro: toPriceMicro(outputPrice),
+      modelMapping,
+    };
+
+    try {
+      if (provider) {
+        await updateProvider.mutateAsync({
+          id: provider.id,
+          data: {
+            name: name.trim(),
+            config: { ...provider.config, disableErrorCooldown, local },
+            supportModels,
+          },
+        });
+      } else {
+        const data: CreateProviderData = {
+          type: 'local',
+          name: name.trim(),
+          config: { disableErrorCooldown, local },
+          supportModels,
+        };
+        await createProvider.mutateAsync(data);
+      }
+      setSaveStatus('success');
+      setTimeout(handleDone, 500);
+    } catch (error) {
+      console.error('Failed to save provider:', error);
+      setSaveStatus('error');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="flex flex-col h-full">
+      <PageHeader
+        icon={<ChevronLeft className="cursor-pointer" onClick={handleBack} />}
+        title={isEdit ? t('provider.edit') : t('addProvider.local.name')}
+        description={t('addProvider.local.description')}
+      >
+        {isEdit && onDelete && (
+          <Button onClick={onDelete} variant={'destructive'}>
+            <Trash2 size={14} />
+            {t('provider.delete')}
+          </Button>
+        )}
+        <Button onClick={handleBack} variant={'secondary'}>
+          {t('common.cancel')}
+        </Button>
+        <Button onClick={handleSave} disabled={saving || !isValid} variant={'default'}>
+          {saving ? (
+            t('common.saving')
+          ) : saveStatus === 'success' ? (
+            <>
+              <Check size={14} /> {t('common.saved')}
+            </>
+          ) : isEdit ? (
+            t('provider.saveChanges')
+          ) : (
+            t('provider.create')
+          )}
+        </Button>
+      </PageHeader>
+
+      <div className="flex-1 overflow-y-auto p-6">
+        <div className="mx-auto max-w-7xl space-y-8">
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('provider.basicInfo')}
+            </h3>
+            <div className="grid gap-6">
+              <div>
+                <label className="text-sm font-medium text-text-primary block mb-2">
+                  {t('provider.displayName')}
+                </label>
+                <Input
+                  type="text"
+                  value={name}
+                  onChange={(e) => setName(e.target.value)}
+                  placeholder={t('provider.namePlaceholder')}
+                  className="w-full"
+                />
+              </div>
+              <div className="grid grid-cols-1 md:grid-cols-3 gap-6">
+                <div>
+                  <label className="text-sm font-medium text-foreground block mb-2">
+                    <div className="flex items-center gap-2">
+                      <Globe size={14} />
+                      <span>{t('addProvider.local.baseURL')}</span>
+                    </div>
+                  </label>
+                  <Input
+                    type="text"
+                    value={config.baseURL}
+                    onChange={(e) => update({ baseURL: e.target.value })}
+                    placeholder="http://localhost:11434"
+                    className="w-full font-mono"
+                  />
+                </div>
+                <div>
+                  <label className="text-sm font-medium text-foreground block mb-2">
+                    <div className="flex items-center gap-2">
+                      <Cpu size={14} />
+                      <span>{t('addProvider.local.backend')}</span>
+                    </div>
+                  </label>
+                  <Select
+                    value={config.backend ?? 'openai'}
+                    onValueChange={(value) => update({ backend: value as LocalBackend })}
+                  >
+                    <SelectTrigger className="w-full">
+                      <SelectValue />
+                    </SelectTrigger>
+                    <SelectContent>
+                      <SelectItem value="ollama">{t('addProvider.local.backendOllama')}</SelectItem>
+                      <SelectItem value="openai">{t('addProvider.local.backendOpenAI')}</SelectItem>
+                    </SelectContent>
+                  </Select>
+                </div>
+                <div>
+                  <label className="text-sm font-medium text-foreground block mb-2">
+                    <div className="flex items-center gap-2">
+                      <Key size={14} />
+                      <span>{t('addProvider.local.apiKey')}</span>
+                    </div>
+                  </label>
+                  <Input
+                    type="password"
+                    value={config.apiKey ?? ''}
+                    onChange={(e) => update({ apiKey: e.target.value })}
+                    placeholder={t('addProvider.optional')}
+                    className="w-full font-mono"
+                  />
+                </div>
+              </div>
+            </div>
+          </div>
+
+          <div className="space-y-6">
+            <div className="flex items-center justify-between border-b border-border pb-2">
+              <h3 className="text-lg font-semibold text-text-primary">
+                {t('addProvider.local.models')}
+              </h3>
+              <Button
+                variant="secondary"
+                size="sm"
+                onClick={discover}
+                disabled={discovering || config.baseURL.trim() === ''}
+              >
+                {discovering ? (
+                  <Loader2 size={14} className="animate-spin" />
+                ) : (
+                  <RefreshCw size={14} />
+                )}
+                {t('addProvider.local.discover')}
+              </Button>
+            </div>
+            {models.length > 0 ? (
+              <div className="flex flex-wrap gap-2">
+                {models.map((model) => (
+                  <Badge key={model} variant="outline" className="font-mono gap-1">
+                    {model}
+                    <X
+                      size={12}
+                      className="cursor-pointer text-muted-foreground hover:text-error"
+                      onClick={() => setModels((prev) => prev.filter((m) => m !== model))}
+                    />
+                  </Badge>
+                ))}
+              </div>
+            ) : (
+              <p className="text-xs text-muted-foreground">{t('addProvider.local.modelsEmpty')}</p>
+            )}
+            {discoverError && <p className="text-xs text-error">{discoverError}</p>}
+            <p className="text-xs text-text-secondary">{t('addProvider.local.modelsHint')}</p>
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('addProvider.local.modelMapping')}
+            </h3>
+            <p className="text-xs text-text-secondary">
+              {t('addProvider.local.modelMappingHint')}
+            </p>
+            <ModelMappingEditor
+              value={config.modelMapping ?? {}}
+              onChange={(modelMapping) => update({ modelMapping })}
+            />
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('addProvider.local.pricing')}
+            </h3>
+            <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+              <div>
+                <label className="text-sm font-medium text-foreground block mb-2">
+                  <div className="flex items-center gap-2">
+                    <DollarSign size={14} />
+                    <span>{t('addProvider.local.inputPrice')}</span>
+                  </div>
+                </label>
+                <Input
+                  type="number"
+                  min={0}
+                  step="0.01"
+                  value={inputPrice}
+                  onChange={(e) => setInputPrice(e.target.value)}
+                  placeholder="0"
+                  className="w-full font-mono"
+                />
+              </div>
+              <div>
+                <label className="text-sm font-medium text-foreground block mb-2">
+                  <div className="flex items-center gap-2">
+                    <DollarSign size={14} />
+                    <span>{t('addProvider.local.outputPrice')}</span>
+                  </div>
+                </label>
+                <Input
+                  type="number"
+                  min={0}
+                  step="0.01"
+                  value={outputPrice}
+                  onChange={(e) => setOutputPrice(e.target.value)}
+                  placeholder="0"
+                  className="w-full font-mono"
+                />
+              </div>
+            </div>
+            <p className="text-xs text-text-secondary">{t('addProvider.local.pricingHint')}</p>
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('provider.errorCooldownTitle')}
+            </h3>
+            <div className="flex items-center justify-between p-4 bg-card border border-border rounded-xl">
+              <div className="pr-4">
+                <div className="text-sm font-medium text-foreground">
+                  {t('provider.disableErrorCooldown')}
+                </div>
+                <p className="text-xs text-muted-foreground mt-1">
+                  {t('provider.disableErrorCooldownDesc')}
+                </p>
+              </div>
+              <Switch checked={disableErrorCooldown} onCheckedChange={setDisableErrorCooldown} />
+            </div>
+          </div>
+
+          {saveStatus === 'error' && (
+            <div className="p-4 bg-error/10 border border-error/30 rounded-lg text-sm text-error flex items-center gap-2">
+              <div className="w-1.5 h-1.5 rounded-full bg-error" />
+              {isEdit ? t('provider.updateError') : t('provider.createError')}
+            </div>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,103 @@
+import { useState } from 'react';
+import { ShieldAlert } from 'lucide-react';
+import { useTranslation } from 'react-i18next';
+import { useUpdateProvider } from '@/hooks/queries';
+import type { Provider, ProviderConfigCircuitBreaker } from '@/lib/transport';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { CooldownPolicyEditor } from '@/components/cooldown-policy-editor';
+
+const toPositive = (value: string) => {
+  const n = parseInt(value, 10);
+  return Number.isNaN(n) || n <= 0 ? undefined : n;
+};
+
+/**
+ * Provider 熔断设置：半开试探请求数、恢复所需成功次数、按原因覆盖冷冻策略
+ * 编辑时使用，独立保存
+ */
+export function ProviderCircuitBreakerSection({ provider }: { provider: Provider }) {
+  const { t } = useTranslation();
+  const updateProvider = useUpdateProvider();
+
+  const [config, setConfig] = useState<ProviderConfigCircuitBreaker>(
+    provider.config?.circuitBreaker ?? {},
+  );
+  const [saved, setSaved] = useState(false);
+
+  const update = (patch: Partial<ProviderConfigCircuitBreaker>) => {
+    setConfig((prev) => ({ ...prev, ...patch }));
+    setSaved(false);
+  };
+
+  const handleSave = async () => {
+    await updateProvider.mutateAsync({
+      id: provider.id,
+      data: { config: { ...provider.config, circuitBreaker: config } },
+    });
+    setSaved(true);
+  };
+
+  return (
+    <div>
+      <div className="flex items-center gap-2 mb-4 border-b border-border pb-2">
+        <ShieldAlert size={18} className="text-warning" />
+        <h4 className="text-lg font-semibold text-foreground">
+          {t('provider.circuitBreaker.title')}
+        </h4>
+      </div>
+
+      <div className="bg-card border border-border rounded-xl p-4 space-y-4">
+        <p className="text-xs text-muted-foreground">{t('provider.circuitBreaker.desc')}</p>
+
+        <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
+          <div>
+            <label className="text-xs font-medium text-foreground block mb-1">
+              {t('provider.circuitBreaker.halfOpenMaxRequests')}
+            </label>
+            <Input
+              type="number"
+              min={1}
+              value={config.halfOpenMaxRequests ?? ''}
+              onChange={(e) => update({ halfOpenMaxRequests: toPositive(e.target.value) })}
+              placeholder="1"
+              className="w-full h-8 text-sm"
+            />
+          </div>
+          <div>
+            <label className="text-xs font-medium text-foreground block mb-1">
+              {t('provider.circuitBreaker.successThreshold')}
+            </label>
+            <Input
+              type="number"
+              min={1}
+              value={config.successThreshold ?? ''}
+              onChange={(e) => update({ successThreshold: toPositive(e.target.value) })}
+              placeholder="1"
+              className="w-full h-8 text-sm"
+            />
+          </div>
+        </div>
+
+        <div className="flex justify-end">
+          <Button size="sm" onClick={handleSave} disabled={updateProvider.isPending}>
+            {updateProvider.isPending
+              ? t('common.saving')
+              : saved
+                ? t('common.saved')
+                : t('common.save')}
+          </Button>
+        </div>
+        <div className="pt-4 border-t border-border space-y-2">
+          <div className="text-sm font-medium text-foreground">
+            {t('provider.circuitBreaker.policies')}
+          </div>
+          <p className="text-xs text-muted-foreground">
+            {t('provider.circuitBreaker.policiesDesc')}
+          </p>
+          <CooldownPolicyEditor providerId={provider.id} />
+        </div>
+      </div>
+    </div>
+  );
+}
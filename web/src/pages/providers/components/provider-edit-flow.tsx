@@ -35,12 +35,20 @@ import type {
   CreateProviderData,
   ModelMapping,
   ModelMappingInput,
+  ProviderConfigNetwork,
 } from '@/lib/transport';
 import { defaultClients, type ClientConfig } from '../types';
 import { ClientsConfigSection } from './clients-config-section';
 import { AntigravityProviderView } from './antigravity-provider-view';
 import { KiroProviderView } from './kiro-provider-view';
 import { CodexProviderView } from './codex-provider-view';
+import { BedrockConfigStep } from './bedrock-config-step';
+import { CopilotConfigStep } from './copilot-config-step';
+import { LocalConfigStep } from './local-config-step';
+import { AzureOpenAIConfigStep } from './azure-openai-config-step';
+import { VertexConfigStep } from './vertex-config-step';
+import { ProviderHealthSection } from './provider-health-section';
+import { ProviderCircuitBreakerSection } from './provider-circuit-breaker-section';
 import { Button } from '@/components/ui/button';
 import { Input } from '@/components/ui/input';
 import { Switch } from '@/components/ui';
@@ -277,6 +285,31 @@ type EditFormData = {
   cloakStrictMode?: boolean;
   cloakSensitiveWords?: string;
   disableErrorCooldown?: boolean;
+  networkProxyURL: string;
+  networkCACert: string;
+  networkInsecureSkipVerify: boolean;
+  networkConnectTimeout: string;
+  networkReadTimeout: string;
+  networkIdleTimeout: string;
+};
+
+const formatSeconds = (seconds?: number) => (seconds ? String(seconds) : '');
+
+const parseSeconds = (value: string) => {
+  const seconds = parseInt(value, 10);
+  return Number.isFinite(seconds) && seconds > 0 ? seconds : undefined;
+};
+
+const buildNetworkConfig = (data: EditFormData): ProviderConfigNetwork | undefined => {
+  const network: ProviderConfigNetwork = {
+    proxyURL: data.networkProxyURL.trim() || undefined,
+    caCert: data.networkCACert.trim() || undefined,
+    insecureSkipVerify: data.networkInsecureSkipVerify || undefined,
+    connectTimeoutSeconds: parseSeconds(data.networkConnectTimeout),
+    readTimeoutSeconds: parseSeconds(data.networkReadTimeout),
+    idleTimeoutSeconds: parseSeconds(data.networkIdleTimeout),
+  };
+  return Object.values(network).some((v) => v !== undefined) ? network : undefined;
 };
 
 export function ProviderEditFlow({ provider, onClose }: ProviderEditFlowProps) {
@@ -313,6 +346,12 @@ export function ProviderEditFlow({ provider, onClose }: ProviderEditFlowProps) {
     cloakStrictMode: provider.config?.custom?.cloak?.strictMode || false,
     cloakSensitiveWords: (provider.config?.custom?.cloak?.sensitiveWords || []).join('\n'),
     disableErrorCooldown: provider.config?.disableErrorCooldown ?? false,
+    networkProxyURL: provider.config?.network?.proxyURL || '',
+    networkCACert: provider.config?.network?.caCert || '',
+    networkInsecureSkipVerify: provider.config?.network?.insecureSkipVerify ?? false,
+    networkConnectTimeout: formatSeconds(provider.config?.network?.connectTimeoutSeconds),
+    networkReadTimeout: formatSeconds(provider.config?.network?.readTimeoutSeconds),
+    networkIdleTimeout: formatSeconds(provider.config?.network?.idleTimeoutSeconds),
   });
 
   const updateClient = (clientId: ClientType, updates: Partial<ClientConfig>) => {
@@ -361,6 +400,9 @@ export function ProviderEditFlow({ provider, onClose }: ProviderEditFlowProps) {
         type: provider.type || 'custom', // Preserve the provider type
         config: {
           disableErrorCooldown: !!formData.disableErrorCooldown,
+          network: buildNetworkConfig(formData),
+          healthCheck: provider.config?.healthCheck,
+          circuitBreaker: provider.config?.circuitBreaker,
           custom: {
             baseURL: formData.baseURL,
             apiKey: formData.apiKey || provider.config?.custom?.apiKey || '',
@@ -422,6 +464,9 @@ export function ProviderEditFlow({ provider, onClose }: ProviderEditFlowProps) {
         logo: provider.logo,
         config: {
           disableErrorCooldown: !!formData.disableErrorCooldown,
+          network: buildNetworkConfig(formData),
+          healthCheck: provider.config?.healthCheck,
+          circuitBreaker: provider.config?.circuitBreaker,
           custom: {
             baseURL: formData.baseURL,
             apiKey: formData.apiKey || provider.config?.custom?.apiKey || '',
@@ -550,6 +595,106 @@ export function ProviderEditFlow({ provider, onClose }: ProviderEditFlowProps) {
     );
   }
 
+  // Bedrock provider
+  if (provider.type === 'bedrock') {
+    return (
+      <>
+        <BedrockConfigStep
+          provider={provider}
+          onDelete={() => setShowDeleteConfirm(true)}
+          onClose={onClose}
+        />
+        <DeleteConfirmModal
+          providerName={provider.name}
+          deleting={deleting}
+          open={showDeleteConfirm}
+          onConfirm={handleDelete}
+          onCancel={() => setShowDeleteConfirm(false)}
+        />
+      </>
+    );
+  }
+
+  // Vertex AI provider
+  if (provider.type === 'vertex') {
+    return (
+      <>
+        <VertexConfigStep
+          provider={provider}
+          onDelete={() => setShowDeleteConfirm(true)}
+          onClose={onClose}
+        />
+        <DeleteConfirmModal
+          providerName={provider.name}
+          deleting={deleting}
+          open={showDeleteConfirm}
+          onConfirm={handleDelete}
+          onCancel={() => setShowDeleteConfirm(false)}
+        />
+      </>
+    );
+  }
+
+  // Azure OpenAI provider
+  if (provider.type === 'azure_openai') {
+    return (
+      <>
+        <AzureOpenAIConfigStep
+          provider={provider}
+          onDelete={() => setShowDeleteConfirm(true)}
+          onClose={onClose}
+        />
+        <DeleteConfirmModal
+          providerName={provider.name}
+          deleting={deleting}
+          open={showDeleteConfirm}
+          onConfirm={handleDelete}
+          onCancel={() => setShowDeleteConfirm(false)}
+        />
+      </>
+    );
+  }
+
+  // Local provider
+  if (provider.type === 'local') {
+    return (
+      <>
+        <LocalConfigStep
+          provider={provider}
+          onDelete={() => setShowDeleteConfirm(true)}
+          onClose={onClose}
+        />
+        <DeleteConfirmModal
+          providerName={provider.name}
+          deleting={deleting}
+          open={showDeleteConfirm}
+          onConfirm={handleDelete}
+          onCancel={() => setShowDeleteConfirm(false)}
+        />
+      </>
+    );
+  }
+
+  // GitHub Copilot provider
+  if (provider.type === 'copilot') {
+    return (
+      <>
+        <CopilotConfigStep
+          provider={provider}
+          onDelete={() => setShowDeleteConfirm(true)}
+          onClose={onClose}
+        />
+        <DeleteConfirmModal
+          providerName={provider.name}
+          deleting={deleting}
+          open={showDeleteConfirm}
+          onConfirm={handleDelete}
+          onCancel={() => setShowDeleteConfirm(false)}
+        />
+      </>
+    );
+  }
+
   // Custom provider edit form
   return (
     <div className="flex flex-col h-full">
@@ -696,6 +841,88 @@ export function ProviderEditFlow({ provider, onClose }: ProviderEditFlowProps) {
             </div>
           </div>
 
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-foreground border-b border-border pb-2">
+              {t('provider.network.title')}
+            </h3>
+            <div className="grid gap-6">
+              <div>
+                <label className="text-sm font-medium text-foreground block mb-2">
+                  {t('provider.network.proxyURL')}
+                </label>
+                <Input
+                  type="text"
+                  value={formData.networkProxyURL}
+                  onChange={(e) =>
+                    setFormData((prev) => ({ ...prev, networkProxyURL: e.target.value }))
+                  }
+                  placeholder="socks5://127.0.0.1:1080"
+                  className="w-full"
+                />
+                <p className="text-xs text-muted-foreground mt-1">
+                  {t('provider.network.proxyURLDesc')}
+                </p>
+              </div>
+
+              <div>
+                <label className="text-sm font-medium text-foreground block mb-2">
+                  {t('provider.network.caCert')}
+                </label>
+                <textarea
+                  value={formData.networkCACert}
+                  onChange={(e) =>
+                    setFormData((prev) => ({ ...prev, networkCACert: e.target.value }))
+                  }
+                  placeholder="-----BEGIN CERTIFICATE-----"
+                  className="w-full h-28 px-4 py-3 rounded-xl border border-border bg-card text-foreground placeholder:text-muted-foreground font-mono text-xs resize-none focus:outline-none focus:ring-2 focus:ring-accent/50 transition-all"
+                />
+              </div>
+
+              <div className="grid grid-cols-1 md:grid-cols-3 gap-6">
+                {(
+                  [
+                    ['networkConnectTimeout', 'provider.network.connectTimeout'],
+                    ['networkReadTimeout', 'provider.network.readTimeout'],
+                    ['networkIdleTimeout', 'provider.network.idleTimeout'],
+                  ] as const
+                ).map(([field, label]) => (
+                  <div key={field}>
+                    <label className="text-sm font-medium text-foreground block mb-2">
+                      {t(label)}
+                    </label>
+                    <Input
+                      type="number"
+                      min={0}
+                      value={formData[field]}
+                      onChange={(e) =>
+                        setFormData((prev) => ({ ...prev, [field]: e.target.value }))
+                      }
+                      placeholder={t('provider.network.timeoutPlaceholder')}
+                      className="w-full"
+                    />
+                  </div>
+                ))}
+              </div>
+
+              <div className="flex items-center justify-between p-4 bg-card border border-border rounded-xl">
+                <div className="pr-4">
+                  <div className="text-sm font-medium text-foreground">
+                    {t('provider.network.insecureSkipVerify')}
+                  </div>
+                  <p className="text-xs text-muted-foreground mt-1">
+                    {t('provider.network.insecureSkipVerifyDesc')}
+                  </p>
+                </div>
+                <Switch
+                  checked={formData.networkInsecureSkipVerify}
+                  onCheckedChange={(checked) =>
+                    setFormData((prev) => ({ ...prev, networkInsecureSkipVerify: checked }))
+                  }
+                />
+              </div>
+            </div>
+          </div>
+
           {/* Provider Supported Models Filter */}
           <ProviderSupportModels
             supportModels={formData.supportModels}
@@ -705,6 +932,12 @@ export function ProviderEditFlow({ provider, onClose }: ProviderEditFlowProps) {
           {/* Provider Model Mappings */}
           <ProviderModelMappings provider={provider} />
 
+          {/* Provider Health Checks */}
+          <ProviderHealthSection provider={provider} />
+
+          {/* Provider Circuit Breaker */}
+          <ProviderCircuitBreakerSection provider={provider} />
+
           {saveStatus === 'error' && (
             <div className="p-4 bg-error/10 border border-error/30 rounded-lg text-sm text-error flex items-center gap-2">
               <div className="w-1.5 h-1.5 rounded-full bg-error" />
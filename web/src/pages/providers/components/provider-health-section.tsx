@@ -0,0 +1,158 @@
+import { useState } from 'react';
+import { Activity, Loader2, Play } from 'lucide-react';
+import { useTranslation } from 'react-i18next';
+import {
+  useCheckProviderHealth,
+  useProviderHealthHistory,
+  useUpdateProvider,
+} from '@/hooks/queries';
+import type { Provider, ProviderConfigHealthCheck } from '@/lib/transport';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { Badge } from '@/components/ui/badge';
+import { Switch } from '@/components/ui';
+
+const numberFields = [
+  ['intervalSeconds', 'provider.health.interval', '300'],
+  ['timeoutSeconds', 'provider.health.timeout', '30'],
+  ['failureThreshold', 'provider.health.failureThreshold', '1'],
+] as const;
+
+type NumberField = (typeof numberFields)[number][0];
+
+/**
+ * Provider 主动健康检查配置与探测历史（编辑时使用，独立保存）
+ */
+export function ProviderHealthSection({ provider }: { provider: Provider }) {
+  const { t } = useTranslation();
+  const updateProvider = useUpdateProvider();
+  const checkHealth = useCheckProviderHealth();
+  const { data: history } = useProviderHealthHistory(provider.id);
+
+  const [config, setConfig] = useState<ProviderConfigHealthCheck>(
+    provider.config?.healthCheck ?? { enabled: false },
+  );
+  const [saved, setSaved] = useState(false);
+
+  const setNumber = (field: NumberField, value: string) => {
+    const n = parseInt(value, 10);
+    setConfig((prev) => ({ ...prev, [field]: Number.isNaN(n) || n <= 0 ? undefined : n }));
+    setSaved(false);
+  };
+
+  const handleSave = async () => {
+    await updateProvider.mutateAsync({
+      id: provider.id,
+      data: { config: { ...provider.config, healthCheck: config } },
+    });
+    setSaved(true);
+  };
+
+  return (
+    <div>
+      <div className="flex items-center justify-between gap-2 mb-4 border-b border-border pb-2">
+        <div className="flex items-center gap-2">
+          <Activity size={18} className="text-success" />
+          <h4 className="text-lg font-semibold text-foreground">{t('provider.health.title')}</h4>
+        </div>
+        <Button
+          variant="outline"
+          size="sm"
+          onClick={() => checkHealth.mutate(provider.id)}
+          disabled={checkHealth.isPending}
+        >
+          {checkHealth.isPending ? (
+            <Loader2 className="h-4 w-4 mr-1 animate-spin" />
+          ) : (
+            <Play className="h-4 w-4 mr-1" />
+          )}
+          {t('provider.health.probeNow')}
+        </Button>
+      </div>
+
+      <div className="bg-card border border-border rounded-xl p-4 space-y-4">
+        <p className="text-xs text-muted-foreground">{t('provider.health.desc')}</p>
+
+        <div className="flex items-center justify-between">
+          <div className="text-sm font-medium text-foreground">{t('provider.health.enabled')}</div>
+          <Switch
+            checked={config.enabled}
+            onCheckedChange={(enabled) => {
+              setConfig((prev) => ({ ...prev, enabled }));
+              setSaved(false);
+            }}
+          />
+        </div>
+
+        <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
+          {numberFields.map(([field, label, placeholder]) => (
+            <div key={field}>
+              <label className="text-xs font-medium text-foreground block mb-1">{t(label)}</label>
+              <Input
+                type="number"
+                min={1}
+                value={config[field] ?? ''}
+                onChange={(e) => setNumber(field, e.target.value)}
+                placeholder={placeholder}
+                className="w-full h-8 text-sm"
+              />
+            </div>
+          ))}
+        </div>
+
+        <div className="flex justify-end">
+          <Button size="sm" onClick={handleSave} disabled={updateProvider.isPending}>
+            {updateProvider.isPending
+              ? t('common.saving')
+              : saved
+                ? t('common.saved')
+                : t('common.save')}
+          </Button>
+        </div>
+
+        <div className="pt-4 border-t border-border">
+          <div className="text-sm font-medium text-foreground mb-2">
+            {t('provider.health.history')}
+          </div>
+          {history && history.length > 0 ? (
+            <div className="space-y-1.5">
+              {history.map((check) => (
+                <div key={check.id} className="flex items-center gap-3 text-xs">
+                  <span
+                    className={`w-2 h-2 rounded-full shrink-0 ${
+                      check.success ? 'bg-success' : 'bg-error'
+                    }`}
+                  />
+                  <span className="text-muted-foreground w-40 shrink-0">
+                    {new Date(check.createdAt).toLocaleString()}
+                  </span>
+                  <Badge variant="outline" className="shrink-0">
+                    {check.clientType}
+                  </Badge>
+                  <span className="font-mono text-muted-foreground truncate w-40 shrink-0">
+                    {check.model}
+                  </span>
+                  <span className="font-mono w-16 shrink-0 text-right">
+                    {Math.round(check.latency / 1e6)}ms
+                  </span>
+                  <span className="truncate text-muted-foreground" title={check.error}>
+                    {check.success
+                      ? check.statusCode
+                      : check.cooldownUntil
+                        ? t('provider.health.cooledDown', {
+                            time: new Date(check.cooldownUntil).toLocaleTimeString(),
+                            error: check.error,
+                          })
+                        : check.error}
+                  </span>
+                </div>
+              ))}
+            </div>
+          ) : (
+            <p className="text-xs text-muted-foreground">{t('provider.health.noHistory')}</p>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+}
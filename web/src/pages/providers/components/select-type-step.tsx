@@ -8,6 +8,10 @@ import {
   Cloud,
   Code2,
   ChevronLeft,
+  Triangle,
+  Hexagon,
+  Cpu,
+  Bot,
 } from 'lucide-react';
 import { quickTemplates, PROVIDER_TYPE_CONFIGS } from '../types';
 import { Button } from '@/components/ui';
@@ -18,11 +22,32 @@ import { useProviderNavigation } from '../hooks/use-provider-navigation';
 
 export function SelectTypeStep() {
   const { formData, updateFormData } = useProviderForm();
-  const { goToCustomConfig, goToAntigravity, goToKiro, goToCodex, goToProviders } =
-    useProviderNavigation();
+  const {
+    goToCustomConfig,
+    goToAntigravity,
+    goToKiro,
+    goToCodex,
+    goToBedrock,
+    goToVertex,
+    goToAzureOpenAI,
+    goToLocal,
+    goToCopilot,
+    goToProviders,
+  } = useProviderNavigation();
   const { t } = useTranslation();
 
-  const handleSelectType = (type: 'custom' | 'antigravity' | 'kiro' | 'codex') => {
+  const handleSelectType = (
+    type:
+      | 'custom'
+      | 'antigravity'
+      | 'kiro'
+      | 'codex'
+      | 'bedrock'
+      | 'vertex'
+      | 'azure_openai'
+      | 'local'
+      | 'copilot',
+  ) => {
     updateFormData({ type });
     if (type === 'antigravity') {
       goToAntigravity();
@@ -30,6 +55,16 @@ export function SelectTypeStep() {
       goToKiro();
     } else if (type === 'codex') {
       goToCodex();
+    } else if (type === 'bedrock') {
+      goToBedrock();
+    } else if (type === 'vertex') {
+      goToVertex();
+    } else if (type === 'azure_openai') {
+      goToAzureOpenAI();
+    } else if (type === 'local') {
+      goToLocal();
+    } else if (type === 'copilot') {
+      goToCopilot();
     }
   };
 
@@ -167,6 +202,151 @@ export function SelectTypeStep() {
                 </Button>
               )}
 
+              <Button
+                onClick={() => handleSelectType('bedrock')}
+                variant="ghost"
+                className={`group p-0 rounded-xl border text-left h-auto w-full overflow-hidden transition-all duration-200 focus-visible:outline-none focus-visible:ring-2 focus-visible:ring-primary focus-visible:ring-offset-2 ${
+                  formData.type === 'bedrock'
+                    ? 'border-provider-bedrock bg-provider-bedrock/10 shadow-sm'
+                    : 'border-border bg-card hover:bg-muted hover:border-accent/30 hover:shadow-sm'
+                }`}
+              >
+                <div className="p-4 sm:p-5 flex items-center gap-3 sm:gap-4 min-w-0 w-full">
+                  <div className="size-10 sm:size-11 md:size-12 rounded-lg bg-provider-bedrock/15 flex items-center justify-center shrink-0 transition-transform duration-200 group-hover:scale-105">
+                    <Cloud className="size-5 md:size-6 text-provider-bedrock" />
+                  </div>
+
+                  <div className="flex-1 min-w-0 space-y-1">
+                    <h3 className="text-sm sm:text-base font-semibold text-foreground leading-tight truncate">
+                      {t('addProvider.bedrock.name')}
+                    </h3>
+                    <p className="text-xs sm:text-sm text-muted-foreground leading-relaxed line-clamp-2">
+                      {t('addProvider.bedrock.description')}
+                    </p>
+                  </div>
+
+                  {formData.type === 'bedrock' && (
+                    <CheckCircle2 className="size-5 text-provider-bedrock shrink-0 self-center animate-in zoom-in-50 duration-200" />
+                  )}
+                </div>
+              </Button>
+
+              <Button
+                onClick={() => handleSelectType('vertex')}
+                variant="ghost"
+                className={`group p-0 rounded-xl border text-left h-auto w-full overflow-hidden transition-all duration-200 focus-visible:outline-none focus-visible:ring-2 focus-visible:ring-primary focus-visible:ring-offset-2 ${
+                  formData.type === 'vertex'
+                    ? 'border-provider-vertex bg-provider-vertex/10 shadow-sm'
+                    : 'border-border bg-card hover:bg-muted hover:border-accent/30 hover:shadow-sm'
+                }`}
+              >
+                <div className="p-4 sm:p-5 flex items-center gap-3 sm:gap-4 min-w-0 w-full">
+                  <div className="size-10 sm:size-11 md:size-12 rounded-lg bg-provider-vertex/15 flex items-center justify-center shrink-0 transition-transform duration-200 group-hover:scale-105">
+                    <Triangle className="size-5 md:size-6 text-provider-vertex" />
+                  </div>
+
+                  <div className="flex-1 min-w-0 space-y-1">
+                    <h3 className="text-sm sm:text-base font-semibold text-foreground leading-tight truncate">
+                      {t('addProvider.vertex.name')}
+                    </h3>
+                    <p className="text-xs sm:text-sm text-muted-foreground leading-relaxed line-clamp-2">
+                      {t('addProvider.vertex.description')}
+                    </p>
+                  </div>
+
+                  {formData.type === 'vertex' && (
+                    <CheckCircle2 className="size-5 text-provider-vertex shrink-0 self-center animate-in zoom-in-50 duration-200" />
+                  )}
+                </div>
+              </Button>
+
+              <Button
+                onClick={() => handleSelectType('azure_openai')}
+                variant="ghost"
+                className={`group p-0 rounded-xl border text-left h-auto w-full overflow-hidden transition-all duration-200 focus-visible:outline-none focus-visible:ring-2 focus-visible:ring-primary focus-visible:ring-offset-2 ${
+                  formData.type === 'azure_openai'
+                    ? 'border-provider-azure_openai bg-provider-azure_openai/10 shadow-sm'
+                    : 'border-border bg-card hover:bg-muted hover:border-accent/30 hover:shadow-sm'
+                }`}
+              >
+                <div className="p-4 sm:p-5 flex items-center gap-3 sm:gap-4 min-w-0 w-full">
+                  <div className="size-10 sm:size-11 md:size-12 rounded-lg bg-provider-azure_openai/15 flex items-center justify-center shrink-0 transition-transform duration-200 group-hover:scale-105">
+                    <Hexagon className="size-5 md:size-6 text-provider-azure_openai" />
+                  </div>
+
+                  <div className="flex-1 min-w-0 space-y-1">
+                    <h3 className="text-sm sm:text-base font-semibold text-foreground leading-tight truncate">
+                      {t('addProvider.azure_openai.name')}
+                    </h3>
+                    <p className="text-xs sm:text-sm text-muted-foreground leading-relaxed line-clamp-2">
+                      {t('addProvider.azure_openai.description')}
+                    </p>
+                  </div>
+
+                  {formData.type === 'azure_openai' && (
+                    <CheckCircle2 className="size-5 text-provider-azure_openai shrink-0 self-center animate-in zoom-in-50 duration-200" />
+                  )}
+                </div>
+              </Button>
+
+              <Button
+                onClick={() => handleSelectType('local')}
+                variant="ghost"
+                className={`group p-0 rounded-xl border text-left h-auto w-full overflow-hidden transition-all duration-200 focus-visible:outline-none focus-visible:ring-2 focus-visible:ring-primary focus-visible:ring-offset-2 ${
+                  formData.type === 'local'
+                    ? 'border-provider-local bg-provider-local/10 shadow-sm'
+                    : 'border-border bg-card hover:bg-muted hover:border-accent/30 hover:shadow-sm'
+                }`}
+              >
+                <div className="p-4 sm:p-5 flex items-center gap-3 sm:gap-4 min-w-0 w-full">
+                  <div className="size-10 sm:size-11 md:size-12 rounded-lg bg-provider-local/15 flex items-center justify-center shrink-0 transition-transform duration-200 group-hover:scale-105">
+                    <Cpu className="size-5 md:size-6 text-provider-local" />
+                  </div>
+
+                  <div className="flex-1 min-w-0 space-y-1">
+                    <h3 className="text-sm sm:text-base font-semibold text-foreground leading-tight truncate">
+                      {t('addProvider.local.name')}
+                    </h3>
+                    <p className="text-xs sm:text-sm text-muted-foreground leading-relaxed line-clamp-2">
+                      {t('addProvider.local.description')}
+                    </p>
+                  </div>
+
+                  {formData.type === 'local' && (
+                    <CheckCircle2 className="size-5 text-provider-local shrink-0 self-center animate-in zoom-in-50 duration-200" />
+                  )}
+                </div>
+              </Button>
+
+              <Button
+                onClick={() => handleSelectType('copilot')}
+                variant="ghost"
+                className={`group p-0 rounded-xl border text-left h-auto w-full overflow-hidden transition-all duration-200 focus-visible:outline-none focus-visible:ring-2 focus-visible:ring-primary focus-visible:ring-offset-2 ${
+                  formData.type === 'copilot'
+                    ? 'border-provider-copilot bg-provider-copilot/10 shadow-sm'
+                    : 'border-border bg-card hover:bg-muted hover:border-accent/30 hover:shadow-sm'
+                }`}
+              >
+                <div className="p-4 sm:p-5 flex items-center gap-3 sm:gap-4 min-w-0 w-full">
+                  <div className="size-10 sm:size-11 md:size-12 rounded-lg bg-provider-copilot/15 flex items-center justify-center shrink-0 transition-transform duration-200 group-hover:scale-105">
+                    <Bot className="size-5 md:size-6 text-provider-copilot" />
+                  </div>
+
+                  <div className="flex-1 min-w-0 space-y-1">
+                    <h3 className="text-sm sm:text-base font-semibold text-foreground leading-tight truncate">
+                      {t('addProvider.copilot.name')}
+                    </h3>
+                    <p className="text-xs sm:text-sm text-muted-foreground leading-relaxed line-clamp-2">
+                      {t('addProvider.copilot.description')}
+                    </p>
+                  </div>
+
+                  {formData.type === 'copilot' && (
+                    <CheckCircle2 className="size-5 text-provider-copilot shrink-0 self-center animate-in zoom-in-50 duration-200" />
+                  )}
+                </div>
+              </Button>
+
               <Button
                 onClick={() => handleSelectType('custom')}
                 variant="ghost"
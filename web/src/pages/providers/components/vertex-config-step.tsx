@@ -0,0 +1,283 @@
+import { useMemo, useState } from 'react';
+import { ChevronLeft, Check, Key, Globe, Trash2, MapPin, FolderKanban } from 'lucide-react';
+import { useTranslation } from 'react-i18next';
+import { useCreateProvider, useUpdateProvider } from '@/hooks/queries';
+import type { CreateProviderData, Provider, ProviderConfigVertex } from '@/lib/transport';
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { Textarea } from '@/components/ui/textarea';
+import { Switch } from '@/components/ui';
+import { PageHeader } from '@/components/layout/page-header';
+import { useProviderNavigation } from '../hooks/use-provider-navigation';
+
+const emptyConfig: ProviderConfigVertex = {
+  region: 'global',
+  credentials: '',
+};
+
+// 解析服务账号 JSON，仅用于表单校验与展示
+function parseServiceAccount(credentials: string): { projectID?: string; email?: string } | null {
+  if (!credentials.trim()) return null;
+  try {
+    const key = JSON.parse(credentials);
+    if (!key.client_email || !key.private_key) return null;
+    return { projectID: key.project_id, email: key.client_email };
+  } catch {
+    return null;
+  }
+}
+
+interface VertexConfigStepProps {
+  // 编辑已有 Provider 时传入，否则为创建流程
+  provider?: Provider;
+  onClose?: () => void;
+  onDelete?: () => void;
+}
+
+/**
+ * Google Vertex AI Provider 配置（创建与编辑共用）
+ */
+export function VertexConfigStep({ provider, onClose, onDelete }: VertexConfigStepProps) {
+  const { t } = useTranslation();
+  const { goToSelectType, goToProviders } = useProviderNavigation();
+  const createProvider = useCreateProvider();
+  const updateProvider = useUpdateProvider();
+
+  const [name, setName] = useState(provider?.name ?? '');
+  const [config, setConfig] = useState<ProviderConfigVertex>({
+    ...emptyConfig,
+    ...provider?.config?.vertex,
+  });
+  const [disableErrorCooldown, setDisableErrorCooldown] = useState(
+    !!provider?.config?.disableErrorCooldown,
+  );
+  const [saving, setSaving] = useState(false);
+  const [saveStatus, setSaveStatus] = useState<'idle' | 'success' | 'error'>('idle');
+
+  const serviceAccount = useMemo(
+    () => parseServiceAccount(config.credentials),
+    [config.credentials],
+  );
+  const projectID = config.projectID?.trim() || serviceAccount?.projectID || '';
+
+  const isEdit = !!provider;
+  const isValid = name.trim() !== '' && serviceAccount !== null && projectID !== '';
+
+  const update = (updates: Partial<ProviderConfigVertex>) =>
+    setConfig((prev) => ({ ...prev, ...updates }));
+
+  const handleBack = () => (isEdit ? onClose?.() : goToSelectType());
+  const handleDone = () => (isEdit ? onClose?.() : goToProviders());
+
+  const handleSave = async () => {
+    if (!isValid) return;
+    setSaving(true);
+    setSaveStatus('idle');
+
+    const vertex: ProviderConfigVertex = {
+      ...config,
+      projectID: config.projectID?.trim() || undefined,
+      region: config.region?.trim() || undefined,
+      credentials: config.credentials.trim(),
+      endpoint: config.endpoint?.trim() || undefined,
+    };
+
+    try {
+      if (provider) {
+        await updateProvider.mutateAsync({
+          id: provider.id,
+          data: {
+            name: name.trim(),
+            config: { ...provider.config, disableErrorCooldown, vertex },
+          },
+        });
+      } else {
+        const data: CreateProviderData = {
+          type: 'vertex',
+          name: name.trim(),
+          config: { disableErrorCooldown, vertex },
+        };
+        await createProvider.mutateAsync(data);
+      }
+      setSaveStatus('success');
+      setTimeout(handleDone, 500);
+    } catch (error) {
+      console.error('Failed to save provider:', error);
+      setSaveStatus('error');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="flex flex-col h-full">
+      <PageHeader
+        icon={<ChevronLeft className="cursor-pointer" onClick={handleBack} />}
+        title={isEdit ? t('provider.edit') : t('addProvider.vertex.name')}
+        description={t('addProvider.vertex.description')}
+      >
+        {isEdit && onDelete && (
+          <Button onClick={onDelete} variant={'destructive'}>
+            <Trash2 size={14} />
+            {t('provider.delete')}
+          </Button>
+        )}
+        <Button onClick={handleBack} variant={'secondary'}>
+          {t('common.cancel')}
+        </Button>
+        <Button onClick={handleSave} disabled={saving || !isValid} variant={'default'}>
+          {saving ? (
+            t('common.saving')
+          ) : saveStatus === 'success' ? (
+            <>
+              <Check size={14} /> {t('common.saved')}
+            </>
+          ) : isEdit ? (
+            t('provider.saveChanges')
+          ) : (
+            t('provider.create')
+          )}
+        </Button>
+      </PageHeader>
+
+      <div className="flex-1 overflow-y-auto p-6">
+        <div className="mx-auto max-w-7xl space-y-8">
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('provider.basicInfo')}
+            </h3>
+            <div className="grid gap-6">
+              <div>
+                <label className="text-sm font-medium text-text-primary block mb-2">
+                  {t('provider.displayName')}
+                </label>
+                <Input
+                  type="text"
+                  value={name}
+                  onChange={(e) => setName(e.target.value)}
+                  placeholder={t('provider.namePlaceholder')}
+                  className="w-full"
+                />
+              </div>
+              <div className="grid grid-cols-1 md:grid-cols-3 gap-6">
+                <div>
+                  <label className="text-sm font-medium text-foreground block mb-2">
+                    <div className="flex items-center gap-2">
+                      <FolderKanban size={14} />
+                      <span>{t('addProvider.vertex.projectID')}</span>
+                    </div>
+                  </label>
+                  <Input
+                    type="text"
+                    value={config.projectID ?? ''}
+                    onChange={(e) => update({ projectID: e.target.value })}
+                    placeholder={serviceAccount?.projectID || 'my-gcp-project'}
+                    className="w-full font-mono"
+                  />
+                  <p className="text-xs text-text-secondary mt-1">
+                    {t('addProvider.vertex.projectIDHint')}
+                  </p>
+                </div>
+                <div>
+                  <label className="text-sm font-medium text-foreground block mb-2">
+                    <div className="flex items-center gap-2">
+                      <MapPin size={14} />
+                      <span>{t('addProvider.vertex.region')}</span>
+                    </div>
+                  </label>
+                  <Input
+                    type="text"
+                    value={config.region ?? ''}
+                    onChange={(e) => update({ region: e.target.value })}
+                    placeholder="global"
+                    className="w-full font-mono"
+                  />
+                  <p className="text-xs text-text-secondary mt-1">
+                    {t('addProvider.vertex.regionHint')}
+                  </p>
+                </div>
+                <div>
+                  <label className="text-sm font-medium text-foreground block mb-2">
+                    <div className="flex items-center gap-2">
+                      <Globe size={14} />
+                      <span>{t('addProvider.vertex.endpoint')}</span>
+                    </div>
+                  </label>
+                  <Input
+                    type="text"
+                    value={config.endpoint ?? ''}
+                    onChange={(e) => update({ endpoint: e.target.value })}
+                    placeholder={t('addProvider.optional')}
+                    className="w-full font-mono"
+                  />
+                  <p className="text-xs text-text-secondary mt-1">
+                    {t('addProvider.vertex.endpointHint')}
+                  </p>
+                </div>
+              </div>
+            </div>
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('addProvider.vertex.credentials')}
+            </h3>
+            <div>
+              <label className="text-sm font-medium text-foreground block mb-2">
+                <div className="flex items-center gap-2">
+                  <Key size={14} />
+                  <span>{t('addProvider.vertex.serviceAccountKey')}</span>
+                </div>
+              </label>
+              <Textarea
+                value={config.credentials}
+                onChange={(e) => update({ credentials: e.target.value })}
+                placeholder='{"type": "service_account", "project_id": "...", ...}'
+                className="w-full font-mono text-xs min-h-40 max-h-80"
+              />
+              {config.credentials.trim() !== '' &&
+                (serviceAccount ? (
+                  <p className="text-xs text-success mt-1">
+                    {t('addProvider.vertex.serviceAccountDetected', {
+                      email: serviceAccount.email,
+                    })}
+                  </p>
+                ) : (
+                  <p className="text-xs text-error mt-1">
+                    {t('addProvider.vertex.serviceAccountInvalid')}
+                  </p>
+                ))}
+              <p className="text-xs text-text-secondary mt-1">
+                {t('addProvider.vertex.modelsHint')}
+              </p>
+            </div>
+          </div>
+
+          <div className="space-y-6">
+            <h3 className="text-lg font-semibold text-text-primary border-b border-border pb-2">
+              {t('provider.errorCooldownTitle')}
+            </h3>
+            <div className="flex items-center justify-between p-4 bg-card border border-border rounded-xl">
+              <div className="pr-4">
+                <div className="text-sm font-medium text-foreground">
+                  {t('provider.disableErrorCooldown')}
+                </div>
+                <p className="text-xs text-muted-foreground mt-1">
+                  {t('provider.disableErrorCooldownDesc')}
+                </p>
+              </div>
+              <Switch checked={disableErrorCooldown} onCheckedChange={setDisableErrorCooldown} />
+            </div>
+          </div>
+
+          {saveStatus === 'error' && (
+            <div className="p-4 bg-error/10 border border-error/30 rounded-lg text-sm text-error flex items-center gap-2">
+              <div className="w-1.5 h-1.5 rounded-full bg-error" />
+              {isEdit ? t('provider.updateError') : t('provider.createError')}
+            </div>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+}
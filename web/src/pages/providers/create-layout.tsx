@@ -5,6 +5,11 @@ import { AntigravityTokenImport } from './components/antigravity-token-import';
 import { KiroTokenImport } from './components/kiro-token-import';
 import { CodexTokenImport } from './components/codex-token-import';
 import { CustomConfigStep } from './components/custom-config-step';
+import { BedrockConfigStep } from './components/bedrock-config-step';
+import { CopilotConfigStep } from './components/copilot-config-step';
+import { LocalConfigStep } from './components/local-config-step';
+import { AzureOpenAIConfigStep } from './components/azure-openai-config-step';
+import { VertexConfigStep } from './components/vertex-config-step';
 
 export function ProviderCreateLayout() {
   return (
@@ -15,6 +20,11 @@ export function ProviderCreateLayout() {
         <Route path="antigravity" element={<AntigravityTokenImport />} />
         <Route path="kiro" element={<KiroTokenImport />} />
         <Route path="codex" element={<CodexTokenImport />} />
+        <Route path="bedrock" element={<BedrockConfigStep />} />
+        <Route path="vertex" element={<VertexConfigStep />} />
+        <Route path="local" element={<LocalConfigStep />} />
+        <Route path="copilot" element={<CopilotConfigStep />} />
+        <Route path="azure_openai" element={<AzureOpenAIConfigStep />} />
       </Routes>
     </ProviderFormProvider>
   );
@@ -9,6 +9,11 @@ export function useProviderNavigation() {
     goToAntigravity: () => navigate('/providers/create/antigravity'),
     goToKiro: () => navigate('/providers/create/kiro'),
     goToCodex: () => navigate('/providers/create/codex'),
+    goToBedrock: () => navigate('/providers/create/bedrock'),
+    goToVertex: () => navigate('/providers/create/vertex'),
+    goToLocal: () => navigate('/providers/create/local'),
+    goToCopilot: () => navigate('/providers/create/copilot'),
+    goToAzureOpenAI: () => navigate('/providers/create/azure_openai'),
     goToProviders: () => navigate('/providers'),
     goBack: () => navigate(-1),
   };
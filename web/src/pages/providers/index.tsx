@@ -64,6 +64,11 @@ export function ProvidersPage() {
       antigravity: [],
       kiro: [],
       codex: [],
+      bedrock: [],
+      vertex: [],
+      azure_openai: [],
+      local: [],
+      copilot: [],
       custom: [],
     };
 
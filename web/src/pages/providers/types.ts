@@ -1,7 +1,19 @@
 import type { ClientType, Provider } from '@/lib/transport';
 import { getProviderColorVar } from '@/lib/theme';
 import type { LucideIcon } from 'lucide-react';
-import { Wand2, Zap, Server, Mail, Globe, Code2 } from 'lucide-react';
+import {
+  Wand2,
+  Zap,
+  Server,
+  Mail,
+  Globe,
+  Code2,
+  Cloud,
+  Triangle,
+  Hexagon,
+  Cpu,
+  Bot,
+} from 'lucide-react';
 import duckcodingLogo from '@/assets/icons/duckcoding.gif';
 import freeDuckLogo from '@/assets/icons/free-duck.gif';
 import nvidiaLogo from '@/assets/icons/nvidia.svg';
@@ -12,7 +24,16 @@ import zhipuLogo from '@/assets/icons/zhipu.svg';
 // ===== Provider Type Configuration =====
 // 通用的 Provider 类型配置，添加新类型只需在这里配置
 
-export type ProviderTypeKey = 'custom' | 'antigravity' | 'kiro' | 'codex';
+export type ProviderTypeKey =
+  | 'custom'
+  | 'antigravity'
+  | 'kiro'
+  | 'codex'
+  | 'bedrock'
+  | 'vertex'
+  | 'azure_openai'
+  | 'local'
+  | 'copilot';
 
 export interface ProviderTypeConfig {
   key: ProviderTypeKey;
@@ -54,6 +75,46 @@ export const PROVIDER_TYPE_CONFIGS: Record<ProviderTypeKey, ProviderTypeConfig>
     isAccountBased: true,
     getDisplayInfo: (p) => p.config?.codex?.email || 'Codex Account',
   },
+  bedrock: {
+    key: 'bedrock',
+    label: 'AWS Bedrock',
+    icon: Cloud,
+    color: getProviderColorVar('bedrock'),
+    isAccountBased: false,
+    getDisplayInfo: (p) => p.config?.bedrock?.region || 'us-east-1',
+  },
+  vertex: {
+    key: 'vertex',
+    label: 'Vertex AI',
+    icon: Triangle,
+    color: getProviderColorVar('vertex'),
+    isAccountBased: false,
+    getDisplayInfo: (p) => p.config?.vertex?.projectID || p.config?.vertex?.region || 'global',
+  },
+  azure_openai: {
+    key: 'azure_openai',
+    label: 'Azure OpenAI',
+    icon: Hexagon,
+    color: getProviderColorVar('azure_openai'),
+    isAccountBased: false,
+    getDisplayInfo: (p) => p.config?.azureOpenAI?.endpoint || 'Not configured',
+  },
+  local: {
+    key: 'local',
+    label: 'Local',
+    icon: Cpu,
+    color: getProviderColorVar('local'),
+    isAccountBased: false,
+    getDisplayInfo: (p) => p.config?.local?.baseURL || 'Not configured',
+  },
+  copilot: {
+    key: 'copilot',
+    label: 'GitHub Copilot',
+    icon: Bot,
+    color: getProviderColorVar('copilot'),
+    isAccountBased: true,
+    getDisplayInfo: (p) => p.config?.copilot?.username || 'GitHub Account',
+  },
   custom: {
     key: 'custom',
     label: 'Custom',
@@ -213,7 +274,16 @@ export const defaultClients: ClientConfig[] = [
 
 // Form data types
 export type ProviderFormData = {
-  type: 'custom' | 'antigravity' | 'kiro' | 'codex';
+  type:
+    | 'custom'
+    | 'antigravity'
+    | 'kiro'
+    | 'codex'
+    | 'bedrock'
+    | 'vertex'
+    | 'azure_openai'
+    | 'local'
+    | 'copilot';
   name: string;
   selectedTemplate: string | null;
   baseURL: string;
@@ -233,4 +303,9 @@ export type CreateStep =
   | 'custom-config'
   | 'antigravity-import'
   | 'kiro-import'
-  | 'codex-import';
+  | 'codex-import'
+  | 'bedrock-config'
+  | 'vertex-config'
+  | 'azure_openai-config'
+  | 'local-config'
+  | 'copilot-config';
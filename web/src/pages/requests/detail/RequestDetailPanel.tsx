@@ -585,6 +585,19 @@ export function RequestDetailPanel({
                     {selectedAttempt.requestModel || '-'}
                   </dd>
                 </div>
+                {!!selectedAttempt.fallbackStep && (
+                  <div className="grid grid-cols-1 sm:grid-cols-3 gap-4 items-center">
+                    <dt className="text-xs font-medium text-muted-foreground uppercase tracking-wider">
+                      {t('requests.fallbackModel')}
+                    </dt>
+                    <dd className="sm:col-span-2 font-mono text-xs text-foreground bg-muted px-2 py-1 rounded">
+                      {selectedAttempt.fallbackModel || '-'}
+                      <span className="ml-2 text-amber-500 text-[10px]">
+                        {t('requests.fallbackStep', { step: selectedAttempt.fallbackStep })}
+                      </span>
+                    </dd>
+                  </div>
+                )}
                 <div className="grid grid-cols-1 sm:grid-cols-3 gap-4 items-center">
                   <dt className="text-xs font-medium text-muted-foreground uppercase tracking-wider">
                     {t('requests.mappedModel')}
@@ -11,7 +11,7 @@ import {
   useAPITokens,
   useSettings,
 } from '@/hooks/queries';
-import { Activity, RefreshCw, Loader2, CheckCircle, AlertTriangle, Ban } from 'lucide-react';
+import { Activity, RefreshCw, Loader2, CheckCircle, AlertTriangle, Ban, Search } from 'lucide-react';
 import type { APIToken, ProxyRequest, ProxyRequestStatus, Provider } from '@/lib/transport';
 import { ClientIcon } from '@/components/icons/client-icons';
 import {
@@ -22,6 +22,7 @@ import {
   TableHeader,
   TableRow,
   Badge,
+  Input,
   Select,
   SelectContent,
   SelectItem,
@@ -34,14 +35,38 @@ import { cn } from '@/lib/utils';
 import { PageHeader } from '@/components/layout/page-header';
 import { useIsMobile } from '@/hooks/use-mobile';
 
-type ProviderTypeKey = 'antigravity' | 'kiro' | 'codex' | 'custom';
-
-const PROVIDER_TYPE_ORDER: ProviderTypeKey[] = ['antigravity', 'kiro', 'codex', 'custom'];
+type ProviderTypeKey =
+  | 'antigravity'
+  | 'kiro'
+  | 'codex'
+  | 'bedrock'
+  | 'vertex'
+  | 'azure_openai'
+  | 'local'
+  | 'copilot'
+  | 'custom';
+
+const PROVIDER_TYPE_ORDER: ProviderTypeKey[] = [
+  'antigravity',
+  'kiro',
+  'codex',
+  'bedrock',
+  'vertex',
+  'azure_openai',
+  'local',
+  'copilot',
+  'custom',
+];
 
 const PROVIDER_TYPE_LABELS: Record<ProviderTypeKey, string> = {
   antigravity: 'Antigravity',
   kiro: 'Kiro',
   codex: 'Codex',
+  bedrock: 'AWS Bedrock',
+  vertex: 'Vertex AI',
+  azure_openai: 'Azure OpenAI',
+  local: 'Local',
+  copilot: 'GitHub Copilot',
   custom: 'Custom',
 };
 
@@ -101,6 +126,16 @@ export function RequestsPage() {
   );
   // Status 过滤器
   const [selectedStatus, setSelectedStatus] = useState<string | undefined>(undefined);
+  // 全文检索（输入防抖）
+  const [searchInput, setSearchInput] = useState('');
+  const [searchQuery, setSearchQuery] = useState('');
+
+  useEffect(() => {
+    const timer = window.setTimeout(() => setSearchQuery(searchInput.trim()), 300);
+    return () => window.clearTimeout(timer);
+  }, [searchInput]);
+
+  const search = useMemo(() => (searchQuery ? { q: searchQuery } : undefined), [searchQuery]);
 
   const scrollContainerRef = useRef<HTMLDivElement | null>(null);
   const loadMoreRef = useRef<HTMLDivElement | null>(null);
@@ -110,12 +145,13 @@ export function RequestsPage() {
 
   // 使用 Infinite Query
   const { data, fetchNextPage, hasNextPage, isFetchingNextPage, isLoading, refetch } =
-    useInfiniteProxyRequests(activeProviderId, selectedStatus, activeTokenId);
+    useInfiniteProxyRequests(activeProviderId, selectedStatus, activeTokenId, search);
 
   const { data: totalCount, refetch: refetchCount } = useProxyRequestsCount(
     activeProviderId,
     selectedStatus,
     activeTokenId,
+    search,
   );
   const { data: providers = [], isSuccess: providersIsSuccess } = useProviders();
   const { data: projects = [] } = useProjects();
@@ -281,6 +317,18 @@ export function RequestsPage() {
         title={t('requests.title')}
         description={t('requests.description', { count: total })}
       >
+        <div className="relative">
+          <Search
+            size={14}
+            className="absolute left-3 top-1/2 -translate-y-1/2 text-muted-foreground"
+          />
+          <Input
+            placeholder={t('requests.searchPlaceholder')}
+            value={searchInput}
+            onChange={(e) => setSearchInput(e.target.value)}
+            className="pl-9 w-32 md:w-48"
+          />
+        </div>
         {/* Filter Mode + Dynamic Target Filter */}
         <FilterModeSelect mode={filterMode} onSelect={handleFilterModeChange} />
         {filterMode === 'provider' ? (
@@ -1012,6 +1060,11 @@ function ProviderFilter({
       antigravity: [],
       kiro: [],
       codex: [],
+      bedrock: [],
+      vertex: [],
+      azure_openai: [],
+      local: [],
+      copilot: [],
       custom: [],
     };
 
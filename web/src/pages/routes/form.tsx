@@ -5,14 +5,38 @@ import { useCreateRoute, useUpdateRoute, useProviders, useProjects } from '@/hoo
 import type { ClientType, Route, Provider } from '@/lib/transport';
 import { ModelMappingEditor } from '@/pages/providers/components/model-mapping-editor';
 
-type ProviderTypeKey = 'antigravity' | 'kiro' | 'codex' | 'custom';
-
-const PROVIDER_TYPE_ORDER: ProviderTypeKey[] = ['antigravity', 'kiro', 'codex', 'custom'];
+type ProviderTypeKey =
+  | 'antigravity'
+  | 'kiro'
+  | 'codex'
+  | 'bedrock'
+  | 'vertex'
+  | 'azure_openai'
+  | 'local'
+  | 'copilot'
+  | 'custom';
+
+const PROVIDER_TYPE_ORDER: ProviderTypeKey[] = [
+  'antigravity',
+  'kiro',
+  'codex',
+  'bedrock',
+  'vertex',
+  'azure_openai',
+  'local',
+  'copilot',
+  'custom',
+];
 
 const PROVIDER_TYPE_LABELS: Record<ProviderTypeKey, string> = {
   antigravity: 'Antigravity',
   kiro: 'Kiro',
   codex: 'Codex',
+  bedrock: 'AWS Bedrock',
+  vertex: 'Vertex AI',
+  azure_openai: 'Azure OpenAI',
+  local: 'Local',
+  copilot: 'GitHub Copilot',
   custom: 'Custom',
 };
 
@@ -44,6 +68,11 @@ export function RouteForm({ route, onClose, isGlobal, projectId }: RouteFormProp
       antigravity: [],
       kiro: [],
       codex: [],
+      bedrock: [],
+      vertex: [],
+      azure_openai: [],
+      local: [],
+      copilot: [],
       custom: [],
     };
 
@@ -1,5 +1,6 @@
 import { useState, useEffect } from 'react';
 import { useTranslation } from 'react-i18next';
+import { Link } from 'react-router-dom';
 import {
   Badge,
   Button,
@@ -13,7 +14,13 @@ import {
   TableRow,
 } from '@/components/ui';
 import { Dialog, DialogContent } from '@/components/ui/dialog';
-import { useSessions, useProjects, useUpdateSessionProject } from '@/hooks/queries';
+import {
+  useSessions,
+  useProjects,
+  useUpdateSessionProject,
+  useSessionStats,
+  useSessionTimeline,
+} from '@/hooks/queries';
 import { PageHeader } from '@/components/layout/page-header';
 import {
   LayoutDashboard,
@@ -24,9 +31,10 @@ import {
   Check,
   AlertCircle,
   FolderOpen,
+  MessageSquare,
 } from 'lucide-react';
-import type { Session } from '@/lib/transport';
-import { cn } from '@/lib/utils';
+import type { ConversationMessage, Session, SessionTurn } from '@/lib/transport';
+import { cn, formatDuration } from '@/lib/utils';
 import { ClientIcon } from '@/components/icons/client-icons';
 
 export function SessionsPage() {
@@ -171,7 +179,7 @@ function SessionDetailModal({ session, projects, onClose }: SessionDetailModalPr
     <Dialog open={!!session} onOpenChange={(open) => !open && onClose()}>
       <DialogContent
         showCloseButton={false}
-        className="overflow-hidden p-0 w-full max-w-lg bg-card"
+        className="overflow-hidden p-0 w-full max-w-2xl bg-card"
       >
         {/* Header */}
         <div className="flex items-center justify-between px-6 py-4 border-b border-border">
@@ -197,7 +205,7 @@ function SessionDetailModal({ session, projects, onClose }: SessionDetailModalPr
         </div>
 
         {/* Content */}
-        <div className="px-6 py-4 space-y-4">
+        <div className="px-6 py-4 space-y-4 max-h-[70vh] overflow-y-auto">
           {/* Session ID */}
           <div>
             <label className="text-xs font-medium text-text-secondary uppercase tracking-wider block mb-1.5">
@@ -218,6 +226,12 @@ function SessionDetailModal({ session, projects, onClose }: SessionDetailModalPr
             </div>
           </div>
 
+          {/* Stats */}
+          <SessionStatsGrid sessionID={session.sessionID} />
+
+          {/* Timeline */}
+          <SessionTimelineList sessionID={session.sessionID} />
+
           {/* Project Binding */}
           <div>
             <label className="text-xs font-medium text-text-secondary uppercase tracking-wider flex items-center gap-2 mb-2">
@@ -300,3 +314,135 @@ function SessionDetailModal({ session, projects, onClose }: SessionDetailModalPr
     </Dialog>
   );
 }
+
+function SessionStatsGrid({ sessionID }: { sessionID: string }) {
+  const { t } = useTranslation();
+  const { data: stats } = useSessionStats(sessionID);
+
+  if (!stats) return null;
+
+  const items = [
+    { label: t('sessions.turns'), value: stats.turnCount.toLocaleString() },
+    { label: t('sessions.failures'), value: stats.failureCount.toLocaleString() },
+    {
+      label: t('sessions.tokens'),
+      value: (stats.inputTokenCount + stats.outputTokenCount).toLocaleString(),
+    },
+    { label: t('sessions.cost'), value: `$${(stats.cost / 1_000_000_000).toFixed(4)}` },
+    { label: t('sessions.providersUsed'), value: stats.providerIDs.length.toLocaleString() },
+    { label: t('sessions.lastSeen'), value: new Date(stats.lastSeen).toLocaleString() },
+  ];
+
+  return (
+    <div className="grid grid-cols-3 gap-2">
+      {items.map((item) => (
+        <div key={item.label} className="bg-muted rounded-md px-3 py-2">
+          <div className="text-[10px] text-text-muted uppercase tracking-wider">{item.label}</div>
+          <div className="text-sm font-medium text-foreground font-mono truncate">{item.value}</div>
+        </div>
+      ))}
+    </div>
+  );
+}
+
+// 将消息压缩为一行摘要：文本优先，其次工具调用/结果
+function summarizeMessages(messages: ConversationMessage[]): string {
+  const pieces: string[] = [];
+  for (const message of messages) {
+    for (const part of message.parts) {
+      if (part.type === 'text' && part.text) {
+        pieces.push(part.text);
+      } else if (part.type === 'tool_call') {
+        pieces.push(`[${part.toolName ?? 'tool'}]`);
+      } else if (part.type === 'tool_result') {
+        pieces.push(part.isError ? '[tool error]' : '[tool result]');
+      } else if (part.type === 'image') {
+        pieces.push('[image]');
+      }
+    }
+  }
+  return pieces.join(' ').replace(/\s+/g, ' ').slice(0, 200);
+}
+
+function SessionTimelineList({ sessionID }: { sessionID: string }) {
+  const { t } = useTranslation();
+  const { data, isLoading, hasNextPage, fetchNextPage, isFetchingNextPage } =
+    useSessionTimeline(sessionID);
+  const turns = data?.pages.flatMap((page) => page.turns) ?? [];
+
+  return (
+    <div>
+      <label className="text-xs font-medium text-text-secondary uppercase tracking-wider flex items-center gap-2 mb-2">
+        <MessageSquare size={12} /> {t('sessions.timeline')}
+      </label>
+      {isLoading ? (
+        <div className="flex justify-center py-4">
+          <Loader2 className="h-4 w-4 animate-spin text-accent" />
+        </div>
+      ) : turns.length === 0 ? (
+        <p className="text-xs text-text-muted">{t('sessions.noTurns')}</p>
+      ) : (
+        <div className="space-y-2">
+          {turns.map((turn) => (
+            <SessionTurnItem key={turn.proxyRequestID} turn={turn} />
+          ))}
+          {hasNextPage && (
+            <Button
+              variant="outline"
+              size="sm"
+              className="w-full"
+              onClick={() => fetchNextPage()}
+              disabled={isFetchingNextPage}
+            >
+              {isFetchingNextPage ? (
+                <Loader2 size={14} className="animate-spin" />
+              ) : (
+                t('sessions.loadMore')
+              )}
+            </Button>
+          )}
+        </div>
+      )}
+    </div>
+  );
+}
+
+function SessionTurnItem({ turn }: { turn: SessionTurn }) {
+  const { t } = useTranslation();
+  const input = summarizeMessages(turn.input);
+  const output = summarizeMessages(turn.output);
+
+  return (
+    <Link
+      to={`/requests/${turn.proxyRequestID}`}
+      className="block rounded-md border border-border px-3 py-2 hover:bg-accent transition-colors"
+    >
+      <div className="flex items-center gap-2 text-[10px] text-text-muted font-mono">
+        <span>{new Date(turn.startTime).toLocaleTimeString()}</span>
+        <span className="truncate">{turn.responseModel || turn.requestModel}</span>
+        <span className="ml-auto">{formatDuration(turn.duration)}</span>
+        <Badge variant={turn.status === 'FAILED' ? 'danger' : 'default'} className="text-[10px]">
+          {turn.status}
+        </Badge>
+      </div>
+      {turn.parseError ? (
+        <p className="text-xs text-text-muted italic mt-1">{t('sessions.detailUnavailable')}</p>
+      ) : (
+        <>
+          {input && (
+            <p className="text-xs text-foreground mt-1 line-clamp-2">
+              <span className="text-text-muted">{t('sessions.turnInput')}: </span>
+              {input}
+            </p>
+          )}
+          {output && (
+            <p className="text-xs text-text-secondary mt-1 line-clamp-2">
+              <span className="text-text-muted">{t('sessions.turnOutput')}: </span>
+              {output}
+            </p>
+          )}
+        </>
+      )}
+    </Link>
+  );
+}
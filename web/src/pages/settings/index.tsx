@@ -14,6 +14,12 @@ import {
   Activity,
   Eye,
   EyeOff,
+  FileSpreadsheet,
+  ShieldAlert,
+  Repeat,
+  Hourglass,
+  Snowflake,
+  GitBranch,
 } from 'lucide-react';
 import { useTranslation } from 'react-i18next';
 import { useTheme } from '@/components/theme-provider';
@@ -36,7 +42,14 @@ import {
   TabsContent,
 } from '@/components/ui';
 import { PageHeader } from '@/components/layout/page-header';
-import { useSettings, useUpdateSetting, useDeleteSetting } from '@/hooks/queries';
+import { CooldownPolicyEditor } from '@/components/cooldown-policy-editor';
+import { ModelFallbackEditor } from '@/components/model-fallback-editor';
+import {
+  useSettings,
+  useUpdateSetting,
+  useDeleteSetting,
+  useRunBillingReport,
+} from '@/hooks/queries';
 import { useTransport } from '@/lib/transport/context';
 import type { BackupFile, BackupImportResult } from '@/lib/transport/types';
 import { getDefaultThemes, getLuxuryThemes } from '@/lib/theme';
@@ -59,7 +72,14 @@ export function SettingsPage() {
           <GeneralSection />
           <TimezoneSection />
           <DataRetentionSection />
+          <BillingReportSection />
+          <AnomalySection />
           <ForceProjectSection />
+          <LoopGuardSection />
+          <QueueSection />
+          <StreamFailoverSection />
+          <CooldownPolicySection />
+          <ModelFallbackSection />
           <AntigravitySection />
           <PprofSection />
           <BackupSection />
@@ -278,29 +298,38 @@ function DataRetentionSection() {
 
   const requestRetentionHours = settings?.request_retention_hours ?? '168';
   const requestDetailRetentionSeconds = settings?.request_detail_retention_seconds ?? '-1';
+  // 存储单位为字节，界面以 KB 展示
+  const maxBodyKB = String(
+    Math.floor(parseInt(settings?.request_detail_max_body_bytes ?? '1048576', 10) / 1024) || 0,
+  );
 
   const [requestDraft, setRequestDraft] = useState('');
   const [detailDraft, setDetailDraft] = useState('');
+  const [maxBodyDraft, setMaxBodyDraft] = useState('');
   const [initialized, setInitialized] = useState(false);
 
   useEffect(() => {
     if (!isLoading && !initialized) {
       setRequestDraft(requestRetentionHours);
       setDetailDraft(requestDetailRetentionSeconds);
+      setMaxBodyDraft(maxBodyKB);
       setInitialized(true);
     }
-  }, [isLoading, initialized, requestRetentionHours, requestDetailRetentionSeconds]);
+  }, [isLoading, initialized, requestRetentionHours, requestDetailRetentionSeconds, maxBodyKB]);
 
   useEffect(() => {
     if (initialized) {
       setRequestDraft(requestRetentionHours);
       setDetailDraft(requestDetailRetentionSeconds);
+      setMaxBodyDraft(maxBodyKB);
     }
-  }, [requestRetentionHours, requestDetailRetentionSeconds, initialized]);
+  }, [requestRetentionHours, requestDetailRetentionSeconds, maxBodyKB, initialized]);
 
   const hasChanges =
     initialized &&
-    (requestDraft !== requestRetentionHours || detailDraft !== requestDetailRetentionSeconds);
+    (requestDraft !== requestRetentionHours ||
+      detailDraft !== requestDetailRetentionSeconds ||
+      maxBodyDraft !== maxBodyKB);
 
   const handleSave = async () => {
     const requestNum = parseInt(requestDraft, 10);
@@ -319,6 +348,14 @@ function DataRetentionSection() {
         value: detailDraft,
       });
     }
+
+    const maxBodyNum = parseInt(maxBodyDraft, 10);
+    if (!isNaN(maxBodyNum) && maxBodyNum >= 0 && maxBodyDraft !== maxBodyKB) {
+      await updateSetting.mutateAsync({
+        key: 'request_detail_max_body_bytes',
+        value: String(maxBodyNum * 1024),
+      });
+    }
   };
 
   if (isLoading || !initialized) return null;
@@ -370,6 +407,551 @@ function DataRetentionSection() {
           <span className="text-xs text-muted-foreground">{t('common.seconds')}</span>
         </div>
         <p className="text-xs text-muted-foreground">{t('settings.requestDetailRetentionDesc')}</p>
+
+        <div className="flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-3 pt-4 border-t border-border">
+          <div className="text-sm font-medium text-muted-foreground shrink-0">
+            {t('settings.requestDetailMaxBody')}
+          </div>
+          <Input
+            type="number"
+            value={maxBodyDraft}
+            onChange={(e) => setMaxBodyDraft(e.target.value)}
+            className="w-24"
+            min={0}
+            disabled={updateSetting.isPending}
+          />
+          <span className="text-xs text-muted-foreground">KB</span>
+        </div>
+        <p className="text-xs text-muted-foreground">{t('settings.requestDetailMaxBodyDesc')}</p>
+      </CardContent>
+    </Card>
+  );
+}
+
+const REPORT_DELIVERY_KEYS = [
+  'report_webhook_url',
+  'report_smtp_addr',
+  'report_smtp_username',
+  'report_smtp_password',
+  'report_email_from',
+  'report_email_to',
+] as const;
+
+function BillingReportSection() {
+  const { data: settings, isLoading } = useSettings();
+  const updateSetting = useUpdateSetting();
+  const runReport = useRunBillingReport();
+  const { t } = useTranslation();
+
+  const schedule = settings?.report_schedule || 'disabled';
+  const format = settings?.report_format || 'csv';
+
+  const [drafts, setDrafts] = useState<Record<string, string>>({});
+  useEffect(() => {
+    if (settings) {
+      setDrafts(Object.fromEntries(REPORT_DELIVERY_KEYS.map((key) => [key, settings[key] ?? ''])));
+    }
+  }, [settings]);
+
+  const hasChanges = REPORT_DELIVERY_KEYS.some(
+    (key) => (drafts[key] ?? '') !== (settings?.[key] ?? ''),
+  );
+
+  const handleSave = async () => {
+    for (const key of REPORT_DELIVERY_KEYS) {
+      const value = (drafts[key] ?? '').trim();
+      if (value !== (settings?.[key] ?? '')) {
+        await updateSetting.mutateAsync({ key, value });
+      }
+    }
+  };
+
+  if (isLoading) return null;
+
+  const fieldLabels: Record<(typeof REPORT_DELIVERY_KEYS)[number], string> = {
+    report_webhook_url: t('settings.reportWebhookUrl'),
+    report_smtp_addr: t('settings.reportSmtpAddr'),
+    report_smtp_username: t('settings.reportSmtpUsername'),
+    report_smtp_password: t('settings.reportSmtpPassword'),
+    report_email_from: t('settings.reportEmailFrom'),
+    report_email_to: t('settings.reportEmailTo'),
+  };
+  const placeholders: Record<(typeof REPORT_DELIVERY_KEYS)[number], string> = {
+    report_webhook_url: 'https://example.com/hooks/billing',
+    report_smtp_addr: 'localhost:25',
+    report_smtp_username: 'maxx@example.com',
+    report_smtp_password: '',
+    report_email_from: 'maxx@localhost',
+    report_email_to: 'billing@example.com, finance@example.com',
+  };
+
+  return (
+    <Card className="border-border bg-card">
+      <CardHeader className="border-b border-border">
+        <div className="flex items-center justify-between">
+          <div>
+            <CardTitle className="text-base font-medium flex items-center gap-2">
+              <FileSpreadsheet className="h-4 w-4 text-muted-foreground" />
+              {t('settings.billingReports')}
+            </CardTitle>
+            <p className="text-xs text-muted-foreground mt-1">{t('settings.billingReportsDesc')}</p>
+          </div>
+          <div className="flex gap-2">
+            <Button
+              variant="outline"
+              size="sm"
+              onClick={() => runReport.mutate(schedule === 'disabled' ? undefined : schedule)}
+              disabled={runReport.isPending}
+            >
+              {runReport.isPending ? t('settings.reportRunning') : t('settings.reportRunNow')}
+            </Button>
+            <Button onClick={handleSave} disabled={!hasChanges || updateSetting.isPending} size="sm">
+              {updateSetting.isPending ? t('common.saving') : t('common.save')}
+            </Button>
+          </div>
+        </div>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        <div className="flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-3">
+          <div className="text-sm font-medium text-muted-foreground shrink-0 sm:w-40">
+            {t('settings.reportSchedule')}
+          </div>
+          <Select
+            value={schedule}
+            onValueChange={(v) =>
+              v &&
+              updateSetting.mutate({ key: 'report_schedule', value: v === 'disabled' ? '' : v })
+            }
+            disabled={updateSetting.isPending}
+          >
+            <SelectTrigger className="w-40">
+              <SelectValue>{t(`settings.reportSchedule_${schedule}`)}</SelectValue>
+            </SelectTrigger>
+            <SelectContent>
+              {['disabled', 'weekly', 'monthly'].map((value) => (
+                <SelectItem key={value} value={value}>
+                  {t(`settings.reportSchedule_${value}`)}
+                </SelectItem>
+              ))}
+            </SelectContent>
+          </Select>
+          <Select
+            value={format}
+            onValueChange={(v) => v && updateSetting.mutate({ key: 'report_format', value: v })}
+            disabled={updateSetting.isPending}
+          >
+            <SelectTrigger className="w-32">
+              <SelectValue>{format.toUpperCase()}</SelectValue>
+            </SelectTrigger>
+            <SelectContent>
+              {['csv', 'parquet'].map((value) => (
+                <SelectItem key={value} value={value}>
+                  {value.toUpperCase()}
+                </SelectItem>
+              ))}
+            </SelectContent>
+          </Select>
+        </div>
+
+        {REPORT_DELIVERY_KEYS.map((key) => (
+          <div
+            key={key}
+            className="flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-3 pt-4 border-t border-border"
+          >
+            <div className="text-sm font-medium text-muted-foreground shrink-0 sm:w-40">
+              {fieldLabels[key]}
+            </div>
+            <Input
+              type={key === 'report_smtp_password' ? 'password' : 'text'}
+              value={drafts[key] ?? ''}
+              onChange={(e) => setDrafts((prev) => ({ ...prev, [key]: e.target.value }))}
+              placeholder={placeholders[key]}
+              className="flex-1"
+              disabled={updateSetting.isPending}
+            />
+          </div>
+        ))}
+        <p className="text-xs text-muted-foreground">{t('settings.reportDeliveryDesc')}</p>
+
+        {runReport.data && (
+          <p className="text-xs text-emerald-600 dark:text-emerald-400">
+            {t('settings.reportGenerated', {
+              period: runReport.data.period.key,
+              count: runReport.data.files.length,
+              dir: runReport.data.dir,
+            })}
+          </p>
+        )}
+        {runReport.error && (
+          <p className="text-xs text-destructive">{(runReport.error as Error).message}</p>
+        )}
+      </CardContent>
+    </Card>
+  );
+}
+
+const ANOMALY_KEYS = [
+  'anomaly_window_minutes',
+  'anomaly_baseline_days',
+  'anomaly_min_requests',
+  'anomaly_rate_factor',
+  'anomaly_error_rate',
+  'anomaly_webhook_url',
+] as const;
+
+// 未设置时后端使用的默认值
+const ANOMALY_DEFAULTS: Record<(typeof ANOMALY_KEYS)[number], string> = {
+  anomaly_window_minutes: '15',
+  anomaly_baseline_days: '7',
+  anomaly_min_requests: '20',
+  anomaly_rate_factor: '5',
+  anomaly_error_rate: '50',
+  anomaly_webhook_url: '',
+};
+
+function AnomalySection() {
+  const { data: settings, isLoading } = useSettings();
+  const updateSetting = useUpdateSetting();
+  const { t } = useTranslation();
+
+  const enabled = settings?.anomaly_enabled === 'true';
+  const autoDisable = settings?.anomaly_auto_disable === 'true';
+
+  const [drafts, setDrafts] = useState<Record<string, string>>({});
+  useEffect(() => {
+    if (settings) {
+      setDrafts(
+        Object.fromEntries(
+          ANOMALY_KEYS.map((key) => [key, settings[key] || ANOMALY_DEFAULTS[key]]),
+        ),
+      );
+    }
+  }, [settings]);
+
+  const current = (key: (typeof ANOMALY_KEYS)[number]) =>
+    settings?.[key] || ANOMALY_DEFAULTS[key];
+  const hasChanges = ANOMALY_KEYS.some((key) => (drafts[key] ?? '').trim() !== current(key));
+
+  const handleSave = async () => {
+    for (const key of ANOMALY_KEYS) {
+      const value = (drafts[key] ?? '').trim();
+      if (value !== current(key)) {
+        await updateSetting.mutateAsync({ key, value });
+      }
+    }
+  };
+
+  if (isLoading) return null;
+
+  const fieldLabels: Record<(typeof ANOMALY_KEYS)[number], string> = {
+    anomaly_window_minutes: t('settings.anomalyWindowMinutes'),
+    anomaly_baseline_days: t('settings.anomalyBaselineDays'),
+    anomaly_min_requests: t('settings.anomalyMinRequests'),
+    anomaly_rate_factor: t('settings.anomalyRateFactor'),
+    anomaly_error_rate: t('settings.anomalyErrorRate'),
+    anomaly_webhook_url: t('settings.anomalyWebhookUrl'),
+  };
+
+  return (
+    <Card className="border-border bg-card">
+      <CardHeader className="border-b border-border">
+        <div className="flex items-center justify-between">
+          <div>
+            <CardTitle className="text-base font-medium flex items-center gap-2">
+              <ShieldAlert className="h-4 w-4 text-muted-foreground" />
+              {t('settings.anomalyDetection')}
+            </CardTitle>
+            <p className="text-xs text-muted-foreground mt-1">{t('settings.anomalyDetectionDesc')}</p>
+          </div>
+          <Button onClick={handleSave} disabled={!hasChanges || updateSetting.isPending} size="sm">
+            {updateSetting.isPending ? t('common.saving') : t('common.save')}
+          </Button>
+        </div>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        <div className="flex items-center justify-between">
+          <div className="text-sm font-medium text-foreground">
+            {t('settings.enableAnomalyDetection')}
+          </div>
+          <Switch
+            checked={enabled}
+            onCheckedChange={(checked) =>
+              updateSetting.mutate({ key: 'anomaly_enabled', value: checked ? 'true' : 'false' })
+            }
+            disabled={updateSetting.isPending}
+          />
+        </div>
+        <div className="flex items-center justify-between pt-4 border-t border-border">
+          <div>
+            <div className="text-sm font-medium text-foreground">
+              {t('settings.anomalyAutoDisable')}
+            </div>
+            <p className="text-xs text-muted-foreground mt-1">
+              {t('settings.anomalyAutoDisableDesc')}
+            </p>
+          </div>
+          <Switch
+            checked={autoDisable}
+            onCheckedChange={(checked) =>
+              updateSetting.mutate({
+                key: 'anomaly_auto_disable',
+                value: checked ? 'true' : 'false',
+              })
+            }
+            disabled={updateSetting.isPending}
+          />
+        </div>
+
+        {ANOMALY_KEYS.map((key) => (
+          <div
+            key={key}
+            className="flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-3 pt-4 border-t border-border"
+          >
+            <div className="text-sm font-medium text-muted-foreground shrink-0 sm:w-40">
+              {fieldLabels[key]}
+            </div>
+            <Input
+              type={key === 'anomaly_webhook_url' ? 'text' : 'number'}
+              value={drafts[key] ?? ''}
+              onChange={(e) => setDrafts((prev) => ({ ...prev, [key]: e.target.value }))}
+              placeholder={
+                key === 'anomaly_webhook_url' ? 'https://example.com/hooks/anomaly' : undefined
+              }
+              className={key === 'anomaly_webhook_url' ? 'flex-1' : 'w-32'}
+              min={0}
+              disabled={updateSetting.isPending}
+            />
+          </div>
+        ))}
+        <p className="text-xs text-muted-foreground">{t('settings.anomalyThresholdsDesc')}</p>
+      </CardContent>
+    </Card>
+  );
+}
+
+const LOOP_GUARD_KEYS = [
+  'loop_guard_window',
+  'loop_guard_repeat_threshold',
+  'loop_guard_max_tokens',
+  'loop_guard_max_cost',
+] as const;
+
+// 未设置时后端使用的默认值
+const LOOP_GUARD_DEFAULTS: Record<(typeof LOOP_GUARD_KEYS)[number], string> = {
+  loop_guard_window: '10',
+  loop_guard_repeat_threshold: '5',
+  loop_guard_max_tokens: '0',
+  loop_guard_max_cost: '0',
+};
+
+function LoopGuardSection() {
+  const { data: settings, isLoading } = useSettings();
+  const updateSetting = useUpdateSetting();
+  const { t } = useTranslation();
+
+  const enabled = settings?.loop_guard_enabled === 'true';
+  const action = settings?.loop_guard_action === 'approve' ? 'approve' : 'reject';
+
+  const [drafts, setDrafts] = useState<Record<string, string>>({});
+  useEffect(() => {
+    if (settings) {
+      setDrafts(
+        Object.fromEntries(
+          LOOP_GUARD_KEYS.map((key) => [key, settings[key] || LOOP_GUARD_DEFAULTS[key]]),
+        ),
+      );
+    }
+  }, [settings]);
+
+  const current = (key: (typeof LOOP_GUARD_KEYS)[number]) =>
+    settings?.[key] || LOOP_GUARD_DEFAULTS[key];
+  const hasChanges = LOOP_GUARD_KEYS.some((key) => (drafts[key] ?? '').trim() !== current(key));
+
+  const handleSave = async () => {
+    for (const key of LOOP_GUARD_KEYS) {
+      const value = (drafts[key] ?? '').trim();
+      if (value !== current(key)) {
+        await updateSetting.mutateAsync({ key, value });
+      }
+    }
+  };
+
+  if (isLoading) return null;
+
+  const fieldLabels: Record<(typeof LOOP_GUARD_KEYS)[number], string> = {
+    loop_guard_window: t('settings.loopGuardWindow'),
+    loop_guard_repeat_threshold: t('settings.loopGuardRepeatThreshold'),
+    loop_guard_max_tokens: t('settings.loopGuardMaxTokens'),
+    loop_guard_max_cost: t('settings.loopGuardMaxCost'),
+  };
+
+  return (
+    <Card className="border-border bg-card">
+      <CardHeader className="border-b border-border">
+        <div className="flex items-center justify-between">
+          <div>
+            <CardTitle className="text-base font-medium flex items-center gap-2">
+              <Repeat className="h-4 w-4 text-muted-foreground" />
+              {t('settings.loopGuard')}
+            </CardTitle>
+            <p className="text-xs text-muted-foreground mt-1">{t('settings.loopGuardDesc')}</p>
+          </div>
+          <Button onClick={handleSave} disabled={!hasChanges || updateSetting.isPending} size="sm">
+            {updateSetting.isPending ? t('common.saving') : t('common.save')}
+          </Button>
+        </div>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        <div className="flex items-center justify-between">
+          <div className="text-sm font-medium text-foreground">{t('settings.enableLoopGuard')}</div>
+          <Switch
+            checked={enabled}
+            onCheckedChange={(checked) =>
+              updateSetting.mutate({ key: 'loop_guard_enabled', value: checked ? 'true' : 'false' })
+            }
+            disabled={updateSetting.isPending}
+          />
+        </div>
+        <div className="flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-3 pt-4 border-t border-border">
+          <div className="text-sm font-medium text-muted-foreground shrink-0 sm:w-40">
+            {t('settings.loopGuardAction')}
+          </div>
+          <Select
+            value={action}
+            onValueChange={(v) => v && updateSetting.mutate({ key: 'loop_guard_action', value: v })}
+            disabled={updateSetting.isPending}
+          >
+            <SelectTrigger className="w-56">
+              <SelectValue>{t(`settings.loopGuardAction_${action}`)}</SelectValue>
+            </SelectTrigger>
+            <SelectContent>
+              {['reject', 'approve'].map((value) => (
+                <SelectItem key={value} value={value}>
+                  {t(`settings.loopGuardAction_${value}`)}
+                </SelectItem>
+              ))}
+            </SelectContent>
+          </Select>
+        </div>
+
+        {LOOP_GUARD_KEYS.map((key) => (
+          <div
+            key={key}
+            className="flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-3 pt-4 border-t border-border"
+          >
+            <div className="text-sm font-medium text-muted-foreground shrink-0 sm:w-40">
+              {fieldLabels[key]}
+            </div>
+            <Input
+              type="number"
+              value={drafts[key] ?? ''}
+              onChange={(e) => setDrafts((prev) => ({ ...prev, [key]: e.target.value }))}
+              className="w-32"
+              min={0}
+              step={key === 'loop_guard_max_cost' ? '0.01' : '1'}
+              disabled={updateSetting.isPending}
+            />
+          </div>
+        ))}
+        <p className="text-xs text-muted-foreground">{t('settings.loopGuardThresholdsDesc')}</p>
+      </CardContent>
+    </Card>
+  );
+}
+
+const QUEUE_KEYS = ['queue_max_length', 'queue_max_wait_seconds', 'queue_max_concurrency'] as const;
+
+// 未设置时后端使用的默认值
+const QUEUE_DEFAULTS: Record<(typeof QUEUE_KEYS)[number], string> = {
+  queue_max_length: '100',
+  queue_max_wait_seconds: '30',
+  queue_max_concurrency: '0',
+};
+
+function QueueSection() {
+  const { data: settings, isLoading } = useSettings();
+  const updateSetting = useUpdateSetting();
+  const { t } = useTranslation();
+
+  const enabled = settings?.queue_enabled === 'true';
+
+  const [drafts, setDrafts] = useState<Record<string, string>>({});
+  useEffect(() => {
+    if (settings) {
+      setDrafts(
+        Object.fromEntries(QUEUE_KEYS.map((key) => [key, settings[key] || QUEUE_DEFAULTS[key]])),
+      );
+    }
+  }, [settings]);
+
+  const current = (key: (typeof QUEUE_KEYS)[number]) => settings?.[key] || QUEUE_DEFAULTS[key];
+  const hasChanges = QUEUE_KEYS.some((key) => (drafts[key] ?? '').trim() !== current(key));
+
+  const handleSave = async () => {
+    for (const key of QUEUE_KEYS) {
+      const value = (drafts[key] ?? '').trim();
+      if (value !== current(key)) {
+        await updateSetting.mutateAsync({ key, value });
+      }
+    }
+  };
+
+  if (isLoading) return null;
+
+  const fieldLabels: Record<(typeof QUEUE_KEYS)[number], string> = {
+    queue_max_length: t('settings.queueMaxLength'),
+    queue_max_wait_seconds: t('settings.queueMaxWait'),
+    queue_max_concurrency: t('settings.queueMaxConcurrency'),
+  };
+
+  return (
+    <Card className="border-border bg-card">
+      <CardHeader className="border-b border-border">
+        <div className="flex items-center justify-between">
+          <div>
+            <CardTitle className="text-base font-medium flex items-center gap-2">
+              <Hourglass className="h-4 w-4 text-muted-foreground" />
+              {t('settings.queue')}
+            </CardTitle>
+            <p className="text-xs text-muted-foreground mt-1">{t('settings.queueDesc')}</p>
+          </div>
+          <Button onClick={handleSave} disabled={!hasChanges || updateSetting.isPending} size="sm">
+            {updateSetting.isPending ? t('common.saving') : t('common.save')}
+          </Button>
+        </div>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        <div className="flex items-center justify-between">
+          <div className="text-sm font-medium text-foreground">{t('settings.enableQueue')}</div>
+          <Switch
+            checked={enabled}
+            onCheckedChange={(checked) =>
+              updateSetting.mutate({ key: 'queue_enabled', value: checked ? 'true' : 'false' })
+            }
+            disabled={updateSetting.isPending}
+          />
+        </div>
+
+        {QUEUE_KEYS.map((key) => (
+          <div
+            key={key}
+            className="flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-3 pt-4 border-t border-border"
+          >
+            <div className="text-sm font-medium text-muted-foreground shrink-0 sm:w-40">
+              {fieldLabels[key]}
+            </div>
+            <Input
+              type="number"
+              value={drafts[key] ?? ''}
+              onChange={(e) => setDrafts((prev) => ({ ...prev, [key]: e.target.value }))}
+              className="w-32"
+              min={0}
+              step="1"
+              disabled={updateSetting.isPending}
+            />
+          </div>
+        ))}
+        <p className="text-xs text-muted-foreground">{t('settings.queueMaxConcurrencyDesc')}</p>
       </CardContent>
     </Card>
   );
@@ -449,6 +1031,89 @@ function ForceProjectSection() {
   );
 }
 
+function StreamFailoverSection() {
+  const { data: settings, isLoading } = useSettings();
+  const updateSetting = useUpdateSetting();
+  const { t } = useTranslation();
+
+  const enabled = settings?.stream_failover_enabled === 'true';
+
+  const handleToggle = async (checked: boolean) => {
+    await updateSetting.mutateAsync({
+      key: 'stream_failover_enabled',
+      value: checked ? 'true' : 'false',
+    });
+  };
+
+  if (isLoading) return null;
+
+  return (
+    <Card className="border-border bg-card">
+      <CardHeader className="border-b border-border">
+        <CardTitle className="text-base font-medium flex items-center gap-2">
+          <Activity className="h-4 w-4 text-muted-foreground" />
+          {t('settings.streamFailover')}
+        </CardTitle>
+      </CardHeader>
+      <CardContent>
+        <div className="flex items-center justify-between">
+          <div>
+            <div className="text-sm font-medium text-foreground">
+              {t('settings.enableStreamFailover')}
+            </div>
+            <p className="text-xs text-muted-foreground mt-1">{t('settings.streamFailoverDesc')}</p>
+          </div>
+          <Switch
+            checked={enabled}
+            onCheckedChange={handleToggle}
+            disabled={updateSetting.isPending}
+          />
+        </div>
+      </CardContent>
+    </Card>
+  );
+}
+
+// 全局冷冻时长策略，Provider 可在熔断设置中单独覆盖
+function CooldownPolicySection() {
+  const { t } = useTranslation();
+
+  return (
+    <Card className="border-border bg-card">
+      <CardHeader className="border-b border-border">
+        <CardTitle className="text-base font-medium flex items-center gap-2">
+          <Snowflake className="h-4 w-4 text-muted-foreground" />
+          {t('settings.cooldownPolicies')}
+        </CardTitle>
+        <p className="text-xs text-muted-foreground mt-1">{t('settings.cooldownPoliciesDesc')}</p>
+      </CardHeader>
+      <CardContent>
+        <CooldownPolicyEditor providerId={0} />
+      </CardContent>
+    </Card>
+  );
+}
+
+// 模型降级链：当前模型在所有路由上都不可用时按顺序降级
+function ModelFallbackSection() {
+  const { t } = useTranslation();
+
+  return (
+    <Card className="border-border bg-card">
+      <CardHeader className="border-b border-border">
+        <CardTitle className="text-base font-medium flex items-center gap-2">
+          <GitBranch className="h-4 w-4 text-muted-foreground" />
+          {t('settings.modelFallbacks')}
+        </CardTitle>
+        <p className="text-xs text-muted-foreground mt-1">{t('settings.modelFallbacksDesc')}</p>
+      </CardHeader>
+      <CardContent>
+        <ModelFallbackEditor />
+      </CardContent>
+    </Card>
+  );
+}
+
 function AntigravitySection() {
   const { data: settings, isLoading } = useSettings();
   const updateSetting = useUpdateSetting();
@@ -10,6 +10,7 @@ import {
   Coins,
   CheckCircle,
   X,
+  Download,
 } from 'lucide-react';
 import { PageHeader } from '@/components/layout/page-header';
 import {
@@ -31,6 +32,7 @@ import {
   useAPITokens,
   useRecalculateUsageStats,
   useRecalculateCosts,
+  useExportUsageStats,
   useResponseModels,
 } from '@/hooks/queries';
 import type {
@@ -41,6 +43,8 @@ import type {
   RecalculateStatsProgress,
 } from '@/lib/transport';
 import { getTransport } from '@/lib/transport';
+import { WastedSpendCard } from './wasted-spend';
+import { QueueStatsCard } from './queue-stats';
 import {
   ComposedChart,
   Bar,
@@ -485,6 +489,7 @@ export function StatsPage() {
   );
   const recalculateStatsMutation = useRecalculateUsageStats();
   const recalculateCostsMutation = useRecalculateCosts();
+  const exportMutation = useExportUsageStats();
 
   // 计算汇总数据和 RPM/TPM
   const summary = useMemo(() => {
@@ -498,6 +503,7 @@ export function StatsPage() {
         totalCacheWrite: 0,
         cacheHitRate: 0,
         totalCost: 0,
+        totalMargin: 0,
         avgRpm: 0,
         avgTpm: 0,
         avgTtft: 0,
@@ -515,6 +521,7 @@ export function StatsPage() {
         totalCacheRead: acc.totalCacheRead + s.cacheRead,
         totalCacheWrite: acc.totalCacheWrite + s.cacheWrite,
         totalCost: acc.totalCost + s.cost,
+        totalMargin: acc.totalMargin + (s.billedCost || 0) - (s.upstreamCost || 0),
         totalDurationMs: acc.totalDurationMs + s.totalDurationMs,
         totalTtftMs: acc.totalTtftMs + (s.totalTtftMs || 0),
       }),
@@ -527,6 +534,7 @@ export function StatsPage() {
         totalCacheRead: 0,
         totalCacheWrite: 0,
         totalCost: 0,
+        totalMargin: 0,
         totalDurationMs: 0,
         totalTtftMs: 0,
       },
@@ -568,6 +576,18 @@ export function StatsPage() {
         description={t('stats.description')}
         actions={
           <div className="flex gap-2">
+            {(['csv', 'parquet'] as const).map((format) => (
+              <Button
+                key={format}
+                variant="outline"
+                size="sm"
+                onClick={() => exportMutation.mutate({ filter, format })}
+                disabled={exportMutation.isPending}
+              >
+                <Download className="h-4 w-4 mr-2" />
+                {t(format === 'csv' ? 'stats.exportCsv' : 'stats.exportParquet')}
+              </Button>
+            ))}
             <Button
               variant="outline"
               size="sm"
@@ -686,7 +706,18 @@ export function StatsPage() {
                     {} as Record<string, typeof providers>,
                   );
                   // 类型排序优先级
-                  const typeOrder = ['antigravity', 'kiro', 'codex', 'custom', 'other'];
+                  const typeOrder = [
+                    'antigravity',
+                    'kiro',
+                    'codex',
+                    'bedrock',
+                    'vertex',
+                    'azure_openai',
+                    'local',
+                    'copilot',
+                    'custom',
+                    'other',
+                  ];
                   const sortedTypes = Object.keys(grouped).sort((a, b) => {
                     const aIndex = typeOrder.indexOf(a);
                     const bIndex = typeOrder.indexOf(b);
@@ -862,6 +893,11 @@ export function StatsPage() {
               <StatCard
                 title={t('stats.totalCost')}
                 value={`$${(summary.totalCost / 1_000_000_000).toFixed(4)}`}
+                subtitle={
+                  summary.totalMargin !== 0
+                    ? `${t('stats.margin')} $${(summary.totalMargin / 1_000_000_000).toFixed(4)}`
+                    : undefined
+                }
                 icon={Coins}
                 iconClassName="text-amber-600 dark:text-amber-400"
               />
@@ -1025,6 +1061,9 @@ export function StatsPage() {
                 </CardContent>
               </Card>
             )}
+
+            <WastedSpendCard filter={filter} providers={providers} />
+            <QueueStatsCard />
           </div>
         </div>
       </div>
@@ -0,0 +1,67 @@
+import { useTranslation } from 'react-i18next';
+import { Hourglass } from 'lucide-react';
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui';
+import { useQueueStats } from '@/hooks/queries';
+
+function formatWait(ms: number): string {
+  if (ms >= 1000) return `${(ms / 1000).toFixed(1)}s`;
+  return `${Math.round(ms)}ms`;
+}
+
+/**
+ * 准入队列：各优先级的排队数量、等待时间、拒绝与超时次数
+ */
+export function QueueStatsCard() {
+  const { t } = useTranslation();
+  const { data: stats } = useQueueStats();
+
+  if (!stats?.enabled) {
+    return null;
+  }
+
+  return (
+    <Card className="border-border/50 bg-card/50 backdrop-blur-sm">
+      <CardHeader className="flex flex-row items-center justify-between pb-2">
+        <CardTitle className="text-base font-semibold flex items-center gap-2">
+          <Hourglass className="h-4 w-4 text-sky-500" />
+          {t('stats.queue')}
+        </CardTitle>
+        <span className="text-sm font-mono text-muted-foreground">
+          {t('stats.queueSummary', {
+            inFlight: stats.inFlight,
+            waiting: stats.waiting,
+            maxLength: stats.maxLength,
+          })}
+        </span>
+      </CardHeader>
+      <CardContent className="pt-2">
+        <p className="text-xs text-muted-foreground mb-3">{t('stats.queueDesc')}</p>
+        <div className="flex items-center gap-3 text-xs text-muted-foreground font-medium border-b pb-2 mb-2">
+          <div className="flex-1 min-w-0">{t('stats.queuePriority')}</div>
+          <div className="w-20 text-right">{t('stats.queueWaiting')}</div>
+          <div className="w-20 text-right">{t('stats.queueQueued')}</div>
+          <div className="w-24 text-right">{t('stats.queueAvgWait')}</div>
+          <div className="w-24 text-right">{t('stats.queueMaxWait')}</div>
+          <div className="w-20 text-right">{t('stats.queueRejected')}</div>
+          <div className="w-20 text-right">{t('stats.queueTimedOut')}</div>
+        </div>
+        <div className="space-y-1">
+          {stats.classes.map((item) => (
+            <div
+              key={item.class}
+              className="flex items-center gap-3 py-1.5 text-sm hover:bg-accent/50 rounded px-2 -mx-2"
+            >
+              <div className="flex-1 min-w-0 truncate">{t(`priority.${item.class}`)}</div>
+              <div className="w-20 text-right font-mono">{item.waiting.toLocaleString()}</div>
+              <div className="w-20 text-right font-mono">{item.queued.toLocaleString()}</div>
+              <div className="w-24 text-right font-mono">{formatWait(item.avgWaitMs)}</div>
+              <div className="w-24 text-right font-mono">{formatWait(item.maxWaitMs)}</div>
+              <div className="w-20 text-right font-mono">{item.rejected.toLocaleString()}</div>
+              <div className="w-20 text-right font-mono">{item.timedOut.toLocaleString()}</div>
+            </div>
+          ))}
+        </div>
+      </CardContent>
+    </Card>
+  );
+}
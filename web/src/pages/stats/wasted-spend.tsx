@@ -0,0 +1,87 @@
+import { useMemo } from 'react';
+import { useTranslation } from 'react-i18next';
+import { AlertTriangle } from 'lucide-react';
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui';
+import { useWastedSpend } from '@/hooks/queries';
+import type { Provider, UsageStatsFilter } from '@/lib/transport';
+
+// 纳美元 -> 美元显示
+function formatUSD(nano: number): string {
+  return `$${(nano / 1_000_000_000).toFixed(4)}`;
+}
+
+function formatTokens(num: number): string {
+  if (num >= 1_000_000) return (num / 1_000_000).toFixed(1) + 'M';
+  if (num >= 1000) return (num / 1000).toFixed(1) + 'K';
+  return num.toLocaleString();
+}
+
+/**
+ * 浪费消耗：失败、取消或被重试取代的 attempt 产生的成本和 Token，按 Provider 和原因拆分
+ */
+export function WastedSpendCard({
+  filter,
+  providers,
+}: {
+  filter: UsageStatsFilter;
+  providers?: Provider[];
+}) {
+  const { t } = useTranslation();
+  const { data: items } = useWastedSpend(filter);
+
+  const { rows, total } = useMemo(() => {
+    const list = items ?? [];
+    return { rows: list, total: list.reduce((sum, item) => sum + item.cost, 0) };
+  }, [items]);
+
+  if (rows.length === 0) {
+    return null;
+  }
+
+  const providerName = (id: number) => providers?.find((p) => p.id === id)?.name ?? `#${id}`;
+
+  return (
+    <Card className="border-border/50 bg-card/50 backdrop-blur-sm">
+      <CardHeader className="flex flex-row items-center justify-between pb-2">
+        <CardTitle className="text-base font-semibold flex items-center gap-2">
+          <AlertTriangle className="h-4 w-4 text-amber-500" />
+          {t('stats.wastedSpend')}
+        </CardTitle>
+        <span className="text-sm font-mono text-muted-foreground">{formatUSD(total)}</span>
+      </CardHeader>
+      <CardContent className="pt-2">
+        <p className="text-xs text-muted-foreground mb-3">{t('stats.wastedSpendDesc')}</p>
+        <div className="flex items-center gap-3 text-xs text-muted-foreground font-medium border-b pb-2 mb-2">
+          <div className="flex-1 min-w-0">{t('stats.provider')}</div>
+          <div className="w-40">{t('stats.wastedReason')}</div>
+          <div className="w-20 text-right">{t('stats.wastedAttempts')}</div>
+          <div className="w-24 text-right">{t('stats.tokens')}</div>
+          <div className="w-24 text-right">{t('stats.totalCost')}</div>
+        </div>
+        <div className="space-y-1">
+          {rows.map((item) => (
+            <div
+              key={`${item.providerID}-${item.reason}`}
+              className="flex items-center gap-3 py-1.5 text-sm hover:bg-accent/50 rounded px-2 -mx-2"
+            >
+              <div className="flex-1 min-w-0 truncate">{providerName(item.providerID)}</div>
+              <div className="w-40 text-xs truncate">
+                {t(`stats.wastedReasons.${item.reason}`, { defaultValue: item.reason })}
+              </div>
+              <div className="w-20 text-right font-mono">{item.attempts.toLocaleString()}</div>
+              <div className="w-24 text-right font-mono">
+                {formatTokens(
+                  item.inputTokenCount +
+                    item.outputTokenCount +
+                    item.cacheReadCount +
+                    item.cacheWriteCount,
+                )}
+              </div>
+              <div className="w-24 text-right font-mono">{formatUSD(item.cost)}</div>
+            </div>
+          ))}
+        </div>
+      </CardContent>
+    </Card>
+  );
+}